@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -11,13 +13,49 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/williamkoller/system-education/config"
+	auth_usecase "github.com/williamkoller/system-education/internal/auth/application/usecase"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_event "github.com/williamkoller/system-education/internal/auth/domain/event"
+	infra_cryptography "github.com/williamkoller/system-education/internal/auth/infra/cryptography"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	infra_oidc "github.com/williamkoller/system-education/internal/auth/infra/oidc"
+	infra_revocation "github.com/williamkoller/system-education/internal/auth/infra/revocation"
+	infra_throttle "github.com/williamkoller/system-education/internal/auth/infra/throttle"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_oidc "github.com/williamkoller/system-education/internal/auth/port/oidc"
+	port_auth_throttle "github.com/williamkoller/system-education/internal/auth/port/throttle"
 	auth_router "github.com/williamkoller/system-education/internal/auth/presentation/router"
+	permission_service "github.com/williamkoller/system-education/internal/permission/application/service"
+	permission_event "github.com/williamkoller/system-education/internal/permission/domain/event"
+	permission_repository "github.com/williamkoller/system-education/internal/permission/infra/db/repository"
+	infra_policy "github.com/williamkoller/system-education/internal/permission/infra/policy"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	permission_middleware "github.com/williamkoller/system-education/internal/permission/presentation/middleware"
 	permission_router "github.com/williamkoller/system-education/internal/permission/presentation/router"
+	role_service "github.com/williamkoller/system-education/internal/role/application/service"
+	role_event "github.com/williamkoller/system-education/internal/role/domain/event"
+	role_repository "github.com/williamkoller/system-education/internal/role/infra/db/repository"
+	role_router "github.com/williamkoller/system-education/internal/role/presentation/router"
+	user_service "github.com/williamkoller/system-education/internal/user/application/service"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	user_event "github.com/williamkoller/system-education/internal/user/domain/event"
+	user_cryptography "github.com/williamkoller/system-education/internal/user/infra/cryptography"
+	user_repository "github.com/williamkoller/system-education/internal/user/infra/db/repository"
+	infra_email "github.com/williamkoller/system-education/internal/user/infra/email"
+	infra_password "github.com/williamkoller/system-education/internal/user/infra/password"
+	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
+	port_user_password "github.com/williamkoller/system-education/internal/user/port/password"
+	port_user_service "github.com/williamkoller/system-education/internal/user/port/service"
 	user_router "github.com/williamkoller/system-education/internal/user/presentation/router"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	"github.com/williamkoller/system-education/shared/infra/broker"
+	"github.com/williamkoller/system-education/shared/infra/email"
+	"github.com/williamkoller/system-education/shared/infra/metrics"
+	"github.com/williamkoller/system-education/shared/infra/outbox"
 	"github.com/williamkoller/system-education/shared/middleware"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -30,12 +68,160 @@ func main() {
 	database := config.NewDatabaseConnection()
 	config.RunMigrations(database, "")
 
-	g := gin.Default()
-	g.Use(gin.Recovery())
-	g.Use(middleware.GlobalErrorHandler())
-	user_router.UserRouter(g, database, cfg.Resend.ApiKey, cfg.Resend.FromAddress, cfg.Secret, cfg.ExpiresIn)
-	auth_router.AuthRouter(g, database, cfg.Secret, cfg.ExpiresIn)
-	permission_router.PermissionRouter(g, database, cfg.Secret, cfg.ExpiresIn)
+	jwt, err := newTokenManager(cfg)
+	if err != nil {
+		log.Fatalf("Error loading signing keys: %v", err)
+	}
+
+	revocations := infra_revocation.NewCachedRevocationStore(auth_repository.NewRevocationGormRepository(database), 30*time.Second)
+	permissions := permission_service.NewCachedPermissionService(
+		permission_repository.NewPermissionGormRepository(database),
+		permission_repository.NewPermissionVersionGormRepository(database),
+		30*time.Second,
+		role_service.NewRolePermissionSource(role_repository.NewRoleGormRepository(database)),
+	)
+
+	policies := permission_service.NewCachedPolicyEngine(newPolicyRepository(cfg, database), 30*time.Second)
+
+	middlewareOptions := []permission_middleware.Option{
+		permission_middleware.WithPermissionService(permissions),
+		permission_middleware.WithPolicyEngine(policies),
+	}
+	if cfg.PermissionFlatMatching {
+		middlewareOptions = append(middlewareOptions, permission_middleware.WithFlatMatching())
+	}
+	accessControl := permission_middleware.NewPermissionMiddleware(middlewareOptions...)
+	eventMetrics := shared_event.NewMetricsRecorder()
+	dispatcher := shared_event.NewDispatcher(
+		shared_event.WithMetrics(eventMetrics),
+		shared_event.WithHandlerTimeout(cfg.Events.HandlerTimeout),
+		shared_event.WithDefaultRetryPolicy(shared_event.RetryPolicy{
+			MaxAttempts: cfg.Events.RetryAttempts,
+			Backoff:     cfg.Events.RetryBackoff,
+			MaxBackoff:  time.Minute,
+		}),
+	)
+	// Every event is registered up front so the relay can decode anything in
+	// the outbox and wildcard subscriptions see the whole catalog.
+	for _, catalog := range [][]func() shared_event.Event{user_event.Catalog(), auth_event.Catalog(), permission_event.Catalog(), role_event.Catalog()} {
+		if err := dispatcher.RegisterEvents(catalog...); err != nil {
+			log.Fatalf("Error registering events: %v", err)
+		}
+	}
+
+	hasher, err := newPasswordHasher(cfg)
+	if err != nil {
+		log.Fatalf("Error configuring password hashing: %v", err)
+	}
+
+	passwords, err := newPasswordPolicy(cfg, database, hasher)
+	if err != nil {
+		log.Fatalf("Error loading password policy: %v", err)
+	}
+
+	apiKeys := auth_usecase.NewAPIKeyUsecase(
+		auth_repository.NewAPIKeyGormRepository(database),
+		user_repository.NewUserGormRepository(database),
+		permissions,
+		infra_cryptography.NewSecureTokenGenerator(32),
+	)
+
+	g, err := middleware.NewEngine(cfg.App.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Error configuring trusted proxies: %v", err)
+	}
+	user_router.UserRouter(g, user_router.Dependencies{
+		DB:            database,
+		TokenManager:  jwt,
+		Revocations:   revocations,
+		APIKeys:       apiKeys,
+		Permissions:   permissions,
+		AccessControl: accessControl,
+		Events:        dispatcher,
+
+		PasswordHasher: hasher,
+		PasswordPolicy: passwords,
+
+		ResendAPIKey: cfg.Resend.ApiKey,
+		FromAddress:  cfg.Resend.FromAddress,
+		SetupToken:   cfg.SetupToken,
+	})
+	auth_router.AuthRouter(g, auth_router.Dependencies{
+		DB:             database,
+		TokenManager:   jwt,
+		Revocations:    revocations,
+		Permissions:    permissions,
+		PermissionMode: auth_entity.PermissionMode(cfg.PermissionMode),
+		AccessControl:  accessControl,
+		APIKeys:        apiKeys,
+		Events:         dispatcher,
+
+		AccessExpiresIn:  cfg.ExpiresIn,
+		RefreshExpiresIn: cfg.RefreshExpiresIn,
+
+		MFAIssuer:             cfg.MFA.Issuer,
+		MFARequiredModules:    cfg.MFA.RequiredModules,
+		MFAChallengeExpiresIn: cfg.MFA.ChallengeExpiresIn,
+
+		LoginAttempts: newLoginAttemptStore(cfg, database),
+		Lockout:       newLockoutPolicy(cfg),
+
+		PasswordHasher:           hasher,
+		PasswordPolicy:           passwords,
+		Notifier:                 infra_email.NewResendEmailNotifier(email.NewResendClient(cfg.Resend.ApiKey, cfg.Resend.FromAddress)),
+		PublicURL:                cfg.Account.PublicURL,
+		ResetExpiresIn:           cfg.Account.ResetExpiresIn,
+		VerificationExpiresIn:    cfg.Account.VerificationExpiresIn,
+		RequireEmailVerification: cfg.Account.RequireEmailVerification,
+
+		IdentityProviders:  newIdentityProviders(cfg),
+		PermissionGranter:  permission_service.NewDefaultGranter(permission_repository.NewPermissionGormRepository(database), permissions, cfg.OIDC.DefaultModules, cfg.OIDC.DefaultActions),
+		OIDCStateExpiresIn: cfg.OIDC.StateExpiresIn,
+
+		OAuthCodeExpiresIn: cfg.OAuth.CodeExpiresIn,
+	})
+	permission_router.PermissionRouter(g, permission_router.Dependencies{
+		DB:            database,
+		TokenManager:  jwt,
+		Revocations:   revocations,
+		APIKeys:       apiKeys,
+		Permissions:   permissions,
+		Policies:      policies,
+		AccessControl: accessControl,
+		Events:        dispatcher,
+	})
+	role_router.RoleRouter(g, role_router.Dependencies{
+		DB:            database,
+		TokenManager:  jwt,
+		Revocations:   revocations,
+		APIKeys:       apiKeys,
+		Permissions:   permissions,
+		AccessControl: accessControl,
+		Events:        dispatcher,
+	})
+
+	eventBroker, err := newBroker(cfg)
+	if err != nil {
+		log.Fatalf("Error connecting to the event broker: %v", err)
+	}
+	defer eventBroker.Close()
+	if err := dispatcher.Handle(">", shared_event.Publish(eventBroker, cfg.Broker.Source)); err != nil {
+		log.Fatalf("Error forwarding events to the broker: %v", err)
+	}
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	if err := shared_event.NewConsumer(eventBroker, dispatcher, cfg.Broker.Source).Start(relayCtx, cfg.Broker.Subscribe...); err != nil {
+		log.Fatalf("Error consuming events from the broker: %v", err)
+	}
+	relay := shared_event.NewRelay(outbox.NewOutboxGormStore(database, outbox.WithLease(cfg.Outbox.Lease)), dispatcher, shared_event.RelayConfig{
+		PollInterval: cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+		MaxAttempts:  cfg.Outbox.MaxAttempts,
+		BaseBackoff:  cfg.Outbox.BaseBackoff,
+		MaxBackoff:   cfg.Outbox.MaxBackoff,
+	})
+	go relay.Run(relayCtx)
 
 	address := ":" + strconv.Itoa(cfg.App.Port)
 	srv := &http.Server{
@@ -44,6 +230,22 @@ func main() {
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
+	var metricsSrv *http.Server
+	if cfg.Events.MetricsAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.NewEventMetricsHandler(eventMetrics))
+		metricsSrv = &http.Server{
+			Addr:              cfg.Events.MetricsAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("metrics listen: %s\n", err)
+			}
+		}()
+	}
+
 	log.Println("Server running at http://localhost:8080")
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -58,6 +260,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutdown Server ...")
+	stopRelay()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -65,6 +268,121 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Println("Server Shutdown: ", err)
 	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(ctx); err != nil {
+			log.Println("Metrics Server Shutdown: ", err)
+		}
+	}
 
 	log.Println("Server exiting")
 }
+
+func newTokenManager(cfg *config.Config) (port_auth_cryptography.TokenManager, error) {
+	if cfg.JWT.Algorithm == "HS256" {
+		return infra_cryptography.NewJWTTokenManager(cfg.Secret, cfg.ExpiresIn), nil
+	}
+
+	signingKey, err := infra_cryptography.LoadSigningKey(cfg.JWT.KeyID, cfg.JWT.PrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	if signingKey.Method.Alg() != cfg.JWT.Algorithm {
+		return nil, fmt.Errorf("signing key is %s but JWT_ALGORITHM is %s", signingKey.Method.Alg(), cfg.JWT.Algorithm)
+	}
+
+	verificationKeys := make([]*infra_cryptography.VerificationKey, 0, len(cfg.JWT.VerificationKeys))
+	for kid, path := range cfg.JWT.VerificationKeys {
+		key, err := infra_cryptography.LoadVerificationKey(kid, path)
+		if err != nil {
+			return nil, err
+		}
+		verificationKeys = append(verificationKeys, key)
+	}
+
+	return infra_cryptography.NewAsymmetricTokenManager(signingKey, verificationKeys, cfg.ExpiresIn)
+}
+
+func newPolicyRepository(cfg *config.Config, database *gorm.DB) port_permission_repository.PolicyRepository {
+	if cfg.PolicySource == "file" {
+		return infra_policy.NewFilePolicyRepository(cfg.PolicyFile)
+	}
+	return permission_repository.NewPolicyGormRepository(database)
+}
+
+func newLoginAttemptStore(cfg *config.Config, database *gorm.DB) port_auth_throttle.LoginAttemptStore {
+	if cfg.Lockout.Store == "memory" {
+		return infra_throttle.NewMemoryLoginAttemptStore()
+	}
+	return auth_repository.NewLoginAttemptGormRepository(database)
+}
+
+func newBroker(cfg *config.Config) (shared_event.Broker, error) {
+	switch cfg.Broker.Driver {
+	case "nats":
+		return broker.DialNATS(cfg.Broker.NATSURL,
+			broker.WithClientName(cfg.Broker.Source),
+			broker.WithQueueGroup(cfg.Broker.NATSQueueGroup),
+			broker.WithStream(cfg.Broker.NATSStream, cfg.Broker.NATSSubjectPrefix),
+		)
+	case "kafka":
+		opts := []broker.KafkaOption{broker.WithClientID(cfg.Broker.Source)}
+		if cfg.Broker.KafkaTLS {
+			opts = append(opts, broker.WithTLS(&tls.Config{MinVersion: tls.VersionTLS12}))
+		}
+		return broker.NewKafkaBroker(cfg.Broker.KafkaBrokers, cfg.Broker.KafkaGroup, opts...)
+	default:
+		return broker.NewMemoryBroker(), nil
+	}
+}
+
+func newLockoutPolicy(cfg *config.Config) auth_entity.LockoutPolicy {
+	policy := auth_entity.DefaultAccountLockoutPolicy()
+	policy.LockoutThreshold = cfg.Lockout.Threshold
+	policy.LockoutDuration = cfg.Lockout.Duration
+	return policy
+}
+
+func newIdentityProviders(cfg *config.Config) []port_auth_oidc.IdentityProvider {
+	providers := make([]port_auth_oidc.IdentityProvider, 0, len(cfg.OIDC.Providers))
+	for _, provider := range cfg.OIDC.Providers {
+		providers = append(providers, infra_oidc.NewProvider(infra_oidc.ProviderConfig{
+			Name:           provider.Name,
+			Issuer:         provider.Issuer,
+			ClientID:       provider.ClientID,
+			ClientSecret:   provider.ClientSecret,
+			RedirectURL:    provider.RedirectURL,
+			AllowedDomains: provider.AllowedDomains,
+			TrustEmail:     provider.TrustEmail,
+		}, nil))
+	}
+	return providers
+}
+
+func newPasswordHasher(cfg *config.Config) (*user_cryptography.PasswordHasher, error) {
+	return user_cryptography.NewPasswordHasher(cfg.Password.HashAlgorithm, cfg.Password.BcryptCost, user_cryptography.Argon2Params{
+		Memory:      uint32(cfg.Password.Argon2Memory),
+		Iterations:  uint32(cfg.Password.Argon2Iterations),
+		Parallelism: uint8(cfg.Password.Argon2Parallelism),
+	})
+}
+
+func newPasswordPolicy(cfg *config.Config, database *gorm.DB, hasher port_cryptography.Bcrypt) (port_user_service.PasswordPolicy, error) {
+	policy := user_entity.DefaultPasswordPolicy()
+	policy.MinLength = cfg.Password.MinLength
+	policy.RequireUpper = cfg.Password.RequireUpper
+	policy.RequireLower = cfg.Password.RequireLower
+	policy.RequireDigit = cfg.Password.RequireDigit
+	policy.RequireSymbol = cfg.Password.RequireSymbol
+	policy.HistorySize = cfg.Password.HistorySize
+
+	var breaches port_user_password.BreachChecker
+	if cfg.Password.BreachFile != "" {
+		checker, err := infra_password.NewFileBreachChecker(cfg.Password.BreachFile)
+		if err != nil {
+			return nil, err
+		}
+		breaches = checker
+	}
+
+	return user_service.NewPasswordPolicyService(policy, hasher, user_repository.NewPasswordHistoryGormRepository(database), breaches), nil
+}
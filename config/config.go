@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,18 +20,182 @@ type AppConfiguration struct {
 	Port    int
 	AppName string
 	Env     string
+	// TrustedProxies lists the addresses or CIDRs of reverse proxies whose
+	// X-Forwarded-For header is believed. Empty, the default, attributes
+	// every request to its TCP peer.
+	TrustedProxies []string
 }
 
 type Config struct {
-	Database  DatabaseConfiguration
-	App       AppConfiguration
-	Resend    ResendConfiguration
-	Secret    string
-	ExpiresIn time.Duration
+	Database         DatabaseConfiguration
+	App              AppConfiguration
+	Resend           ResendConfiguration
+	JWT              JWTConfiguration
+	MFA              MFAConfiguration
+	Lockout          LockoutConfiguration
+	Account          AccountConfiguration
+	Password         PasswordConfiguration
+	OIDC             OIDCConfiguration
+	OAuth            OAuthConfiguration
+	Outbox           OutboxConfiguration
+	Events           EventsConfiguration
+	Broker           BrokerConfiguration
+	Secret           string
+	ExpiresIn        time.Duration
+	RefreshExpiresIn time.Duration
+	// PermissionMode is "embedded" (modules and actions copied into the
+	// token) or "reference" (only a permission version, resolved per request).
+	PermissionMode string
+	// PermissionFlatMatching restores the legacy check that matches modules
+	// and actions independently instead of as module/action pairs.
+	PermissionFlatMatching bool
+	// PolicySource is where route policies are loaded from: "database" (the
+	// policies table) or "file" (the JSON document at PolicyFile).
+	PolicySource string
+	PolicyFile   string
+	// SetupToken enables POST /setup, which creates the first administrator
+	// while no users exist. Leave it empty once the system is bootstrapped.
+	SetupToken string
+}
+
+// JWTConfiguration selects how access tokens are signed. HS256 uses Secret;
+// RS256 and EdDSA sign with the PEM key at PrivateKeyPath and also accept
+// tokens from the retired keys listed in VerificationKeys (kid -> PEM path).
+type JWTConfiguration struct {
+	Algorithm        string
+	KeyID            string
+	PrivateKeyPath   string
+	VerificationKeys map[string]string
+}
+
+// MFAConfiguration controls the TOTP second factor. Users holding any of
+// RequiredModules must complete MFA on every login, enrolling on the spot if
+// they have not yet; an empty list leaves MFA opt-in for everyone.
+type MFAConfiguration struct {
+	Issuer             string
+	RequiredModules    []string
+	ChallengeExpiresIn time.Duration
+}
+
+// LockoutConfiguration controls login brute-force protection. Store is
+// "database" (shared by every instance) or "memory" (per instance). After
+// Threshold consecutive failures an account is locked for Duration; zero
+// disables the lockout and keeps only the exponential backoff.
+type LockoutConfiguration struct {
+	Store     string
+	Threshold int
+	Duration  time.Duration
+}
+
+// AccountConfiguration controls password resets and email verification.
+// Links mailed to users point at PublicURL. With RequireEmailVerification
+// users cannot log in until they follow their verification link.
+type AccountConfiguration struct {
+	PublicURL                string
+	ResetExpiresIn           time.Duration
+	VerificationExpiresIn    time.Duration
+	RequireEmailVerification bool
+}
+
+// PasswordConfiguration sets the password policy and hashing. BreachFile
+// points at a local list of SHA-1 hashes of breached passwords, sorted by
+// hash; leave it empty to skip that check. HashAlgorithm is "argon2id" or "bcrypt"; stored
+// hashes made with the other algorithm or weaker parameters are upgraded on
+// the user's next login.
+type PasswordConfiguration struct {
+	MinLength         int
+	RequireUpper      bool
+	RequireLower      bool
+	RequireDigit      bool
+	RequireSymbol     bool
+	HistorySize       int
+	BreachFile        string
+	HashAlgorithm     string
+	BcryptCost        int
+	Argon2Memory      int
+	Argon2Iterations  int
+	Argon2Parallelism int
+}
+
+// OIDCConfiguration lists the OpenID Connect providers users can sign in
+// with, named in OIDC_PROVIDERS and configured through OIDC_<NAME>_*
+// variables. Users signing in for the first time are created with the
+// DefaultModules and DefaultActions permission.
+type OIDCConfiguration struct {
+	Providers      []OIDCProviderConfiguration
+	DefaultModules []string
+	DefaultActions []string
+	StateExpiresIn time.Duration
+}
+
+// OIDCProviderConfiguration describes one provider. Issuer must match the
+// iss claim exactly; for Microsoft that is the tenant-specific
+// https://login.microsoftonline.com/<tenant-id>/v2.0. TrustEmail accepts
+// emails without an email_verified claim, which Microsoft does not send.
+type OIDCProviderConfiguration struct {
+	Name           string
+	Issuer         string
+	ClientID       string
+	ClientSecret   string
+	RedirectURL    string
+	AllowedDomains []string
+	TrustEmail     bool
+}
+
+// OAuthConfiguration tunes the OAuth2 authorization server. Its access tokens
+// last as long as first-party ones (JWT_EXPIRES_IN).
+type OAuthConfiguration struct {
+	CodeExpiresIn time.Duration
+}
+
+// OutboxConfiguration tunes the relay that delivers stored domain events.
+// Failed deliveries are retried after BaseBackoff, doubling up to MaxBackoff,
+// and given up on after MaxAttempts. A fetched batch stays claimed by its
+// relay for Lease.
+type OutboxConfiguration struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Lease        time.Duration
+}
+
+// EventsConfiguration applies to every event handler. RetryAttempts of 1
+// disables retries; individual events can be given their own policy in code.
+// Handler metrics are served in the Prometheus text format on
+// MetricsAddress, kept apart from the public API; empty turns them off.
+type EventsConfiguration struct {
+	HandlerTimeout time.Duration
+	RetryAttempts  int
+	RetryBackoff   time.Duration
+	MetricsAddress string
+}
+
+// BrokerConfiguration shares domain events with other services. Driver
+// "memory" keeps them in the process, which is what local development and
+// tests use; "nats" and "kafka" publish every event as a CloudEvents
+// envelope from Source. Events matching the Subscribe patterns are received
+// from the broker and fed to the local handlers.
+type BrokerConfiguration struct {
+	Driver    string
+	Source    string
+	Subscribe []string
+	NATSURL   string
+	// NATSQueueGroup lets replicas share the subscribed events instead of
+	// each handling all of them.
+	NATSQueueGroup string
+	// NATSStream is the JetStream stream that keeps every subject under
+	// NATSSubjectPrefix.
+	NATSStream        string
+	NATSSubjectPrefix string
+	KafkaBrokers      []string
+	KafkaGroup        string
+	KafkaTLS          bool
 }
 
 type ResendConfiguration struct {
-	ApiKey string
+	ApiKey      string
 	FromAddress string
 }
 
@@ -47,14 +212,104 @@ func LoadConfig() (*Config, error) {
 
 	resend := loadResend()
 	secret := loadSecret()
-	expiresIn := loadTimeDuration()
+
+	jwtCfg, err := loadJWTConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresIn, err := loadTimeDuration("JWT_EXPIRES_IN", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshExpiresIn, err := loadTimeDuration("JWT_REFRESH_EXPIRES_IN", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	permissionMode := getEnv("JWT_PERMISSION_MODE", "embedded")
+	if permissionMode != "embedded" && permissionMode != "reference" {
+		return nil, fmt.Errorf("JWT_PERMISSION_MODE inválido: %s", permissionMode)
+	}
+
+	flatMatching, err := strconv.ParseBool(getEnv("PERMISSION_FLAT_MATCHING", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("PERMISSION_FLAT_MATCHING inválido: %v", err)
+	}
+
+	mfaCfg, err := loadMFAConfiguration(appCfg.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	lockoutCfg, err := loadLockoutConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	accountCfg, err := loadAccountConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	passwordCfg, err := loadPasswordConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	oidcCfg, err := loadOIDCConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	oauthCodeExpiresIn, err := loadTimeDuration("OAUTH_CODE_EXPIRES_IN", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxCfg, err := loadOutboxConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	eventsCfg, err := loadEventsConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	brokerCfg, err := loadBrokerConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	policySource := getEnv("PERMISSION_POLICY_SOURCE", "database")
+	if policySource != "database" && policySource != "file" {
+		return nil, fmt.Errorf("PERMISSION_POLICY_SOURCE inválido: %s", policySource)
+	}
 
 	return &Config{
-		Database:  *dbCfg,
-		App:       *appCfg,
-		Resend:    resend,
-		Secret:    secret,
-		ExpiresIn: expiresIn,
+		Database:               *dbCfg,
+		App:                    *appCfg,
+		Resend:                 resend,
+		JWT:                    *jwtCfg,
+		MFA:                    *mfaCfg,
+		Lockout:                *lockoutCfg,
+		Account:                *accountCfg,
+		Password:               *passwordCfg,
+		OIDC:                   *oidcCfg,
+		OAuth:                  OAuthConfiguration{CodeExpiresIn: oauthCodeExpiresIn},
+		Outbox:                 *outboxCfg,
+		Events:                 *eventsCfg,
+		Broker:                 *brokerCfg,
+		Secret:                 secret,
+		ExpiresIn:              expiresIn,
+		RefreshExpiresIn:       refreshExpiresIn,
+		PermissionMode:         permissionMode,
+		PermissionFlatMatching: flatMatching,
+		PolicySource:           policySource,
+		PolicyFile:             getEnv("PERMISSION_POLICY_FILE", "config/policies.json"),
+		SetupToken:             getEnv("SETUP_TOKEN", ""),
 	}, nil
 }
 
@@ -88,9 +343,10 @@ func loadAppConfiguration() (*AppConfiguration, error) {
 	}
 
 	cfg := &AppConfiguration{
-		Port:    port,
-		AppName: getEnv("APP_NAME", "myapp"),
-		Env:     getEnv("APP_ENV", "development"),
+		Port:           port,
+		AppName:        getEnv("APP_NAME", "myapp"),
+		Env:            getEnv("APP_ENV", "development"),
+		TrustedProxies: splitList(getEnv("TRUSTED_PROXIES", "")),
 	}
 
 	return cfg, nil
@@ -105,15 +361,382 @@ func getEnv(key, fallback string) string {
 
 func loadResend() ResendConfiguration {
 	return ResendConfiguration{
-		ApiKey:       getEnv("RESEND_API_KEY", ""),
-		FromAddress:  getEnv("RESEND_FROM_ADDRESS", ""),
+		ApiKey:      getEnv("RESEND_API_KEY", ""),
+		FromAddress: getEnv("RESEND_FROM_ADDRESS", ""),
+	}
+}
+
+func loadJWTConfiguration() (*JWTConfiguration, error) {
+	cfg := &JWTConfiguration{
+		Algorithm:        getEnv("JWT_ALGORITHM", "HS256"),
+		KeyID:            getEnv("JWT_KEY_ID", ""),
+		PrivateKeyPath:   getEnv("JWT_PRIVATE_KEY_PATH", ""),
+		VerificationKeys: map[string]string{},
+	}
+
+	switch cfg.Algorithm {
+	case "HS256":
+	case "RS256", "EdDSA":
+		if cfg.KeyID == "" || cfg.PrivateKeyPath == "" {
+			return nil, fmt.Errorf("JWT_KEY_ID e JWT_PRIVATE_KEY_PATH são obrigatórios para %s", cfg.Algorithm)
+		}
+	default:
+		return nil, fmt.Errorf("JWT_ALGORITHM inválido: %s", cfg.Algorithm)
+	}
+
+	for _, entry := range strings.Split(getEnv("JWT_VERIFICATION_KEYS", ""), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kid, path, ok := strings.Cut(entry, "=")
+		if !ok || kid == "" || path == "" {
+			return nil, fmt.Errorf("JWT_VERIFICATION_KEYS inválida: %q", entry)
+		}
+		cfg.VerificationKeys[kid] = path
+	}
+
+	return cfg, nil
+}
+
+func loadMFAConfiguration(appName string) (*MFAConfiguration, error) {
+	challengeExpiresIn, err := loadTimeDuration("MFA_CHALLENGE_EXPIRES_IN", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unlike the other settings an explicitly empty value is meaningful
+	// here: it turns enforcement off.
+	modules, ok := os.LookupEnv("MFA_REQUIRED_MODULES")
+	if !ok {
+		modules = "permissions"
+	}
+
+	cfg := &MFAConfiguration{
+		Issuer:             getEnv("MFA_ISSUER", appName),
+		RequiredModules:    []string{},
+		ChallengeExpiresIn: challengeExpiresIn,
 	}
+	for _, module := range strings.Split(modules, ",") {
+		if module = strings.TrimSpace(module); module != "" {
+			cfg.RequiredModules = append(cfg.RequiredModules, module)
+		}
+	}
+
+	return cfg, nil
+}
+
+func loadLockoutConfiguration() (*LockoutConfiguration, error) {
+	store := getEnv("LOGIN_ATTEMPT_STORE", "database")
+	if store != "database" && store != "memory" {
+		return nil, fmt.Errorf("LOGIN_ATTEMPT_STORE inválido: %s", store)
+	}
+
+	threshold, err := strconv.Atoi(getEnv("LOGIN_LOCKOUT_THRESHOLD", "10"))
+	if err != nil || threshold < 0 {
+		return nil, fmt.Errorf("LOGIN_LOCKOUT_THRESHOLD inválido: %s", getEnv("LOGIN_LOCKOUT_THRESHOLD", "10"))
+	}
+
+	duration, err := loadTimeDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LockoutConfiguration{
+		Store:     store,
+		Threshold: threshold,
+		Duration:  duration,
+	}, nil
+}
+
+func loadAccountConfiguration() (*AccountConfiguration, error) {
+	resetExpiresIn, err := loadTimeDuration("PASSWORD_RESET_EXPIRES_IN", 30*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	verificationExpiresIn, err := loadTimeDuration("EMAIL_VERIFICATION_EXPIRES_IN", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	requireVerification, err := strconv.ParseBool(getEnv("AUTH_REQUIRE_EMAIL_VERIFICATION", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("AUTH_REQUIRE_EMAIL_VERIFICATION inválido: %v", err)
+	}
+
+	return &AccountConfiguration{
+		PublicURL:                getEnv("APP_PUBLIC_URL", "http://localhost:3000"),
+		ResetExpiresIn:           resetExpiresIn,
+		VerificationExpiresIn:    verificationExpiresIn,
+		RequireEmailVerification: requireVerification,
+	}, nil
+}
+
+func loadPasswordConfiguration() (*PasswordConfiguration, error) {
+	minLength, err := loadNonNegativeInt("PASSWORD_MIN_LENGTH", 8)
+	if err != nil {
+		return nil, err
+	}
+
+	historySize, err := loadNonNegativeInt("PASSWORD_HISTORY_SIZE", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	requireUpper, err := loadBool("PASSWORD_REQUIRE_UPPER", true)
+	if err != nil {
+		return nil, err
+	}
+
+	requireLower, err := loadBool("PASSWORD_REQUIRE_LOWER", true)
+	if err != nil {
+		return nil, err
+	}
+
+	requireDigit, err := loadBool("PASSWORD_REQUIRE_DIGIT", true)
+	if err != nil {
+		return nil, err
+	}
+
+	requireSymbol, err := loadBool("PASSWORD_REQUIRE_SYMBOL", false)
+	if err != nil {
+		return nil, err
+	}
+
+	hashAlgorithm := getEnv("PASSWORD_HASH_ALGORITHM", "argon2id")
+	if hashAlgorithm != "argon2id" && hashAlgorithm != "bcrypt" {
+		return nil, fmt.Errorf("PASSWORD_HASH_ALGORITHM inválido: %s", hashAlgorithm)
+	}
+
+	bcryptCost, err := loadNonNegativeInt("PASSWORD_BCRYPT_COST", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	argon2Memory, err := loadNonNegativeInt("PASSWORD_ARGON2_MEMORY_KIB", 64*1024)
+	if err != nil {
+		return nil, err
+	}
+
+	argon2Iterations, err := loadNonNegativeInt("PASSWORD_ARGON2_ITERATIONS", 3)
+	if err != nil {
+		return nil, err
+	}
+
+	argon2Parallelism, err := loadNonNegativeInt("PASSWORD_ARGON2_PARALLELISM", 2)
+	if err != nil || argon2Parallelism > 255 {
+		return nil, fmt.Errorf("PASSWORD_ARGON2_PARALLELISM inválido: %s", getEnv("PASSWORD_ARGON2_PARALLELISM", "2"))
+	}
+
+	return &PasswordConfiguration{
+		MinLength:         minLength,
+		RequireUpper:      requireUpper,
+		RequireLower:      requireLower,
+		RequireDigit:      requireDigit,
+		RequireSymbol:     requireSymbol,
+		HistorySize:       historySize,
+		BreachFile:        getEnv("PASSWORD_BREACH_FILE", ""),
+		HashAlgorithm:     hashAlgorithm,
+		BcryptCost:        bcryptCost,
+		Argon2Memory:      argon2Memory,
+		Argon2Iterations:  argon2Iterations,
+		Argon2Parallelism: argon2Parallelism,
+	}, nil
+}
+
+func loadOIDCConfiguration() (*OIDCConfiguration, error) {
+	stateExpiresIn, err := loadTimeDuration("OIDC_STATE_EXPIRES_IN", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &OIDCConfiguration{
+		Providers:      []OIDCProviderConfiguration{},
+		DefaultModules: splitList(getEnv("OIDC_DEFAULT_MODULES", "users")),
+		DefaultActions: splitList(getEnv("OIDC_DEFAULT_ACTIONS", "read")),
+		StateExpiresIn: stateExpiresIn,
+	}
+
+	for _, name := range splitList(getEnv("OIDC_PROVIDERS", "")) {
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+
+		defaultIssuer := ""
+		if name == "google" {
+			defaultIssuer = "https://accounts.google.com"
+		}
+
+		trustEmail, err := loadBool(prefix+"TRUST_EMAIL", false)
+		if err != nil {
+			return nil, err
+		}
+
+		provider := OIDCProviderConfiguration{
+			Name:           name,
+			Issuer:         getEnv(prefix+"ISSUER", defaultIssuer),
+			ClientID:       getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret:   getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:    getEnv(prefix+"REDIRECT_URL", ""),
+			AllowedDomains: splitList(getEnv(prefix+"ALLOWED_DOMAINS", "")),
+			TrustEmail:     trustEmail,
+		}
+		if provider.Issuer == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			return nil, fmt.Errorf("%sISSUER, %sCLIENT_ID e %sREDIRECT_URL são obrigatórios", prefix, prefix, prefix)
+		}
+
+		cfg.Providers = append(cfg.Providers, provider)
+	}
+
+	return cfg, nil
 }
 
 func loadSecret() string {
 	return getEnv("JWT_SECRET", "")
 }
 
-func loadTimeDuration() time.Duration {
-	return 3*time.Hour + 15*time.Minute + 30*time.Second
+func loadOutboxConfiguration() (*OutboxConfiguration, error) {
+	pollInterval, err := loadTimeDuration("OUTBOX_POLL_INTERVAL", time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize, err := loadNonNegativeInt("OUTBOX_BATCH_SIZE", 100)
+	if err != nil || batchSize == 0 {
+		return nil, fmt.Errorf("OUTBOX_BATCH_SIZE inválido: %s", getEnv("OUTBOX_BATCH_SIZE", "100"))
+	}
+
+	maxAttempts, err := loadNonNegativeInt("OUTBOX_MAX_ATTEMPTS", 10)
+	if err != nil || maxAttempts == 0 {
+		return nil, fmt.Errorf("OUTBOX_MAX_ATTEMPTS inválido: %s", getEnv("OUTBOX_MAX_ATTEMPTS", "10"))
+	}
+
+	baseBackoff, err := loadTimeDuration("OUTBOX_BASE_BACKOFF", time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBackoff, err := loadTimeDuration("OUTBOX_MAX_BACKOFF", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	lease, err := loadTimeDuration("OUTBOX_LEASE", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxConfiguration{
+		PollInterval: pollInterval,
+		BatchSize:    batchSize,
+		MaxAttempts:  maxAttempts,
+		BaseBackoff:  baseBackoff,
+		MaxBackoff:   maxBackoff,
+		Lease:        lease,
+	}, nil
+}
+
+func loadEventsConfiguration() (*EventsConfiguration, error) {
+	handlerTimeout, err := loadTimeDuration("EVENT_HANDLER_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	retryAttempts, err := loadNonNegativeInt("EVENT_RETRY_ATTEMPTS", 1)
+	if err != nil || retryAttempts == 0 {
+		return nil, fmt.Errorf("EVENT_RETRY_ATTEMPTS inválido: %s", getEnv("EVENT_RETRY_ATTEMPTS", "1"))
+	}
+
+	retryBackoff, err := loadTimeDuration("EVENT_RETRY_BACKOFF", time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventsConfiguration{
+		HandlerTimeout: handlerTimeout,
+		RetryAttempts:  retryAttempts,
+		RetryBackoff:   retryBackoff,
+		MetricsAddress: getEnv("EVENT_METRICS_ADDRESS", ":9090"),
+	}, nil
+}
+
+func loadBrokerConfiguration() (*BrokerConfiguration, error) {
+	driver := getEnv("BROKER_DRIVER", "memory")
+	if driver != "memory" && driver != "nats" && driver != "kafka" {
+		return nil, fmt.Errorf("BROKER_DRIVER inválido: %s", driver)
+	}
+
+	kafkaTLS, err := loadBool("KAFKA_TLS", false)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &BrokerConfiguration{
+		Driver:            driver,
+		Source:            getEnv("BROKER_SOURCE", "system-education"),
+		Subscribe:         splitList(getEnv("BROKER_SUBSCRIBE", "")),
+		NATSURL:           getEnv("NATS_URL", "nats://localhost:4222"),
+		NATSQueueGroup:    getEnv("NATS_QUEUE_GROUP", ""),
+		NATSStream:        getEnv("NATS_STREAM", "EVENTS"),
+		NATSSubjectPrefix: getEnv("NATS_SUBJECT_PREFIX", "events"),
+		KafkaBrokers:      splitList(getEnv("KAFKA_BROKERS", "")),
+		KafkaGroup:        getEnv("KAFKA_CONSUMER_GROUP", "system-education"),
+		KafkaTLS:          kafkaTLS,
+	}
+	if driver == "kafka" && len(cfg.KafkaBrokers) == 0 {
+		return nil, fmt.Errorf("KAFKA_BROKERS é obrigatório quando BROKER_DRIVER é %s", driver)
+	}
+
+	return cfg, nil
+}
+
+func loadTimeDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s inválida: %v", key, err)
+	}
+
+	return duration, nil
+}
+
+func loadBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s inválido: %v", key, err)
+	}
+
+	return parsed, nil
+}
+
+func loadNonNegativeInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("%s inválido: %s", key, value)
+	}
+
+	return parsed, nil
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
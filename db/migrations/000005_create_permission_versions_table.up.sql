@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS permission_versions (
+    user_id UUID PRIMARY KEY,
+    version BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
\ No newline at end of file
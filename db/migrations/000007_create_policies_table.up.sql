@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS policies (
+    name TEXT PRIMARY KEY,
+    description TEXT,
+    rule JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+    id UUID PRIMARY KEY,
+    provider TEXT NOT NULL,
+    state_hash TEXT NOT NULL UNIQUE,
+    nonce TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
+
+CREATE INDEX idx_oidc_login_states_expires_at ON oidc_login_states(expires_at);
+
+CREATE TABLE IF NOT EXISTS external_identities (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_external_identities_user_id ON external_identities(user_id);
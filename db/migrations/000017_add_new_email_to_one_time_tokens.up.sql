@@ -0,0 +1 @@
+ALTER TABLE one_time_tokens ADD COLUMN IF NOT EXISTS new_email TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+DELETE FROM policies WHERE name IN ('users.read', 'users.update', 'users.delete', 'permissions.update', 'permissions.delete');
@@ -0,0 +1,9 @@
+-- The route policies the routers attach, matching config/policies.json, so
+-- the database source works out of the box. Existing rows are left alone.
+INSERT INTO policies (name, description, rule) VALUES
+    ('users.read', 'GET /users/:id', '{"allOf":[{"grant":{"modules":["users"],"actions":["read"]}},{"anyOf":[{"grant":{"modules":["users"],"actions":["admin"]}},{"attribute":{"key":"claims.userID","operator":"eq","ref":"params.id"}}]}]}'),
+    ('users.update', 'PUT /users/:id', '{"allOf":[{"grant":{"modules":["users"],"actions":["update"]}},{"anyOf":[{"grant":{"modules":["users"],"actions":["admin"]}},{"attribute":{"key":"claims.userID","operator":"eq","ref":"params.id"}}]}]}'),
+    ('users.delete', 'DELETE /users/:id', '{"allOf":[{"grant":{"modules":["users"],"actions":["delete"]}},{"anyOf":[{"grant":{"modules":["users"],"actions":["admin"]}},{"attribute":{"key":"claims.userID","operator":"eq","ref":"params.id"}}]}]}'),
+    ('permissions.update', 'PUT /permissions/:id', '{"grant":{"modules":["permissions"],"actions":["update"]}}'),
+    ('permissions.delete', 'DELETE /permissions/:id', '{"grant":{"modules":["permissions"],"actions":["delete"]}}')
+ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,2 @@
+ALTER TABLE outbox DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE outbox DROP COLUMN IF EXISTS locked_by;
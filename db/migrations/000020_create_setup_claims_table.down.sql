@@ -0,0 +1 @@
+DROP TABLE IF EXISTS setup_claims;
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS setup_claims (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now()
+);
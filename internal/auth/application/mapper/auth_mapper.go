@@ -0,0 +1,268 @@
+package auth_mapper
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	permission_mapper "github.com/williamkoller/system-education/internal/permission/application/mapper"
+	user_mapper "github.com/williamkoller/system-education/internal/user/application/mapper"
+)
+
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+func ToTokenResponse(p *auth_entity.TokenPair) *TokenResponse {
+	return &TokenResponse{
+		Token:        p.AccessToken,
+		RefreshToken: p.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(p.ExpiresIn.Seconds()),
+	}
+}
+
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type MFAChallengeResponse struct {
+	MFARequired    bool                   `json:"mfa_required"`
+	ChallengeToken string                 `json:"challenge_token"`
+	ExpiresIn      int64                  `json:"expires_in"`
+	Enrollment     *MFAEnrollmentResponse `json:"enrollment,omitempty"`
+}
+
+type MFAVerificationResponse struct {
+	*TokenResponse
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+func ToMFAEnrollmentResponse(e *auth_entity.MFAEnrollment) *MFAEnrollmentResponse {
+	if e == nil {
+		return nil
+	}
+	return &MFAEnrollmentResponse{
+		Secret:     e.Secret,
+		OtpauthURI: e.URI,
+	}
+}
+
+func ToMFAChallengeResponse(t *auth_entity.MFAChallengeTicket) *MFAChallengeResponse {
+	return &MFAChallengeResponse{
+		MFARequired:    true,
+		ChallengeToken: t.Token,
+		ExpiresIn:      int64(t.ExpiresIn.Seconds()),
+		Enrollment:     ToMFAEnrollmentResponse(t.Enrollment),
+	}
+}
+
+func ToMFAVerificationResponse(v *auth_entity.MFAVerification) *MFAVerificationResponse {
+	return &MFAVerificationResponse{
+		TokenResponse: ToTokenResponse(v.Tokens),
+		RecoveryCodes: v.RecoveryCodes,
+	}
+}
+
+type OAuthClientResponse struct {
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	GrantTypes   []string `json:"grant_types"`
+	Scopes       []string `json:"scopes"`
+	Confidential bool     `json:"confidential"`
+}
+
+func ToOAuthClientResponse(r *auth_entity.OAuthClientRegistration) *OAuthClientResponse {
+	return &OAuthClientResponse{
+		ClientID:     r.Client.ClientID,
+		ClientSecret: r.ClientSecret,
+		Name:         r.Client.Name,
+		RedirectURIs: r.Client.RedirectURIs,
+		GrantTypes:   r.Client.GrantTypes,
+		Scopes:       r.Client.Scopes,
+		Confidential: r.Client.Confidential,
+	}
+}
+
+type OAuthAuthorizationResponse struct {
+	ConsentRequired bool     `json:"consent_required"`
+	ClientID        string   `json:"client_id,omitempty"`
+	ClientName      string   `json:"client_name,omitempty"`
+	Scopes          []string `json:"scopes,omitempty"`
+	RedirectTo      string   `json:"redirect_to,omitempty"`
+}
+
+func ToOAuthAuthorizationResponse(a *auth_entity.OAuthAuthorization) *OAuthAuthorizationResponse {
+	response := &OAuthAuthorizationResponse{
+		ConsentRequired: a.ConsentRequired,
+		Scopes:          a.Scopes,
+		RedirectTo:      a.RedirectTo,
+	}
+	if a.Client != nil {
+		response.ClientID = a.Client.ClientID
+		response.ClientName = a.Client.Name
+	}
+	return response
+}
+
+// OAuthTokenResponse follows RFC 6749 5.1, unlike TokenResponse which keeps
+// the first-party field names.
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+func ToOAuthTokenResponse(t *auth_entity.OAuthToken) *OAuthTokenResponse {
+	return &OAuthTokenResponse{
+		AccessToken: t.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(t.ExpiresIn.Seconds()),
+		Scope:       auth_entity.ScopeString(t.Scopes),
+	}
+}
+
+type OAuthIntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	TokenID   string `json:"jti,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+func ToOAuthIntrospectionResponse(i *auth_entity.OAuthIntrospection) *OAuthIntrospectionResponse {
+	if !i.Active {
+		return &OAuthIntrospectionResponse{Active: false}
+	}
+	response := &OAuthIntrospectionResponse{
+		Active:    true,
+		Scope:     i.Scope,
+		ClientID:  i.ClientID,
+		Subject:   i.Subject,
+		TokenID:   i.TokenID,
+		TokenType: "Bearer",
+	}
+	if !i.IssuedAt.IsZero() {
+		response.IssuedAt = i.IssuedAt.Unix()
+	}
+	if !i.ExpiresAt.IsZero() {
+		response.ExpiresAt = i.ExpiresAt.Unix()
+	}
+	return response
+}
+
+type OAuthConsentResponse struct {
+	ClientID  string    `json:"client_id"`
+	Scopes    []string  `json:"scopes"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ToOAuthConsentResponses(consents []*auth_entity.OAuthConsent) []*OAuthConsentResponse {
+	responses := make([]*OAuthConsentResponse, 0, len(consents))
+	for _, consent := range consents {
+		responses = append(responses, &OAuthConsentResponse{
+			ClientID:  consent.ClientID,
+			Scopes:    consent.Scopes,
+			UpdatedAt: consent.UpdatedAt,
+		})
+	}
+	return responses
+}
+
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse is the only response that carries the key itself.
+type CreatedAPIKeyResponse struct {
+	*APIKeyResponse
+	Key string `json:"key"`
+}
+
+func ToAPIKeyResponse(k *auth_entity.APIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+func ToAPIKeyResponses(keys []*auth_entity.APIKey) []*APIKeyResponse {
+	responses := make([]*APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, ToAPIKeyResponse(key))
+	}
+	return responses
+}
+
+func ToCreatedAPIKeyResponse(issued *auth_entity.APIKeyIssued) *CreatedAPIKeyResponse {
+	return &CreatedAPIKeyResponse{
+		APIKeyResponse: ToAPIKeyResponse(issued.APIKey),
+		Key:            issued.Key,
+	}
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+// ToSessionResponses flags the session the request was made from.
+func ToSessionResponses(sessions []*auth_entity.Session, currentID string) []*SessionResponse {
+	responses := make([]*SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		responses = append(responses, &SessionResponse{
+			ID:         s.ID,
+			Device:     s.Device,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			Current:    currentID != "" && s.ID == currentID,
+		})
+	}
+	return responses
+}
+
+type ProfileResponse struct {
+	User           *user_mapper.UserResponse               `json:"user"`
+	Permissions    []*permission_mapper.PermissionResponse `json:"permissions"`
+	ActiveSessions int                                     `json:"active_sessions"`
+	MFAEnabled     bool                                    `json:"mfa_enabled"`
+}
+
+func ToProfileResponse(p *port_auth_usecase.Profile) *ProfileResponse {
+	return &ProfileResponse{
+		User:           user_mapper.ToUser(p.User),
+		Permissions:    permission_mapper.ToPermissions(p.Permissions),
+		ActiveSessions: p.ActiveSessions,
+		MFAEnabled:     p.MFAEnabled,
+	}
+}
@@ -0,0 +1,103 @@
+package auth_mapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+)
+
+func TestToTokenResponse(t *testing.T) {
+	pair := &auth_entity.TokenPair{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		ExpiresIn:    15 * time.Minute,
+	}
+
+	resp := ToTokenResponse(pair)
+
+	assert.Equal(t, "access", resp.Token)
+	assert.Equal(t, "refresh", resp.RefreshToken)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, int64(900), resp.ExpiresIn)
+}
+
+func TestToMFAChallengeResponse(t *testing.T) {
+	ticket := &auth_entity.MFAChallengeTicket{
+		Token:     "challenge",
+		ExpiresIn: 5 * time.Minute,
+		Enrollment: &auth_entity.MFAEnrollment{
+			Secret: "SECRET",
+			URI:    "otpauth://totp/app:john?secret=SECRET",
+		},
+	}
+
+	resp := ToMFAChallengeResponse(ticket)
+
+	assert.True(t, resp.MFARequired)
+	assert.Equal(t, "challenge", resp.ChallengeToken)
+	assert.Equal(t, int64(300), resp.ExpiresIn)
+	assert.Equal(t, "SECRET", resp.Enrollment.Secret)
+	assert.Equal(t, "otpauth://totp/app:john?secret=SECRET", resp.Enrollment.OtpauthURI)
+}
+
+func TestToMFAChallengeResponse_WithoutEnrollment(t *testing.T) {
+	resp := ToMFAChallengeResponse(&auth_entity.MFAChallengeTicket{Token: "challenge"})
+
+	assert.Nil(t, resp.Enrollment)
+}
+
+func TestToMFAVerificationResponse(t *testing.T) {
+	verification := &auth_entity.MFAVerification{
+		Tokens:        &auth_entity.TokenPair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: time.Minute},
+		RecoveryCodes: []string{"aaaa-bbbb"},
+	}
+
+	resp := ToMFAVerificationResponse(verification)
+
+	assert.Equal(t, "access", resp.Token)
+	assert.Equal(t, "refresh", resp.RefreshToken)
+	assert.Equal(t, []string{"aaaa-bbbb"}, resp.RecoveryCodes)
+}
+
+func TestToOAuthTokenResponse(t *testing.T) {
+	resp := ToOAuthTokenResponse(&auth_entity.OAuthToken{
+		AccessToken: "access",
+		ExpiresIn:   time.Hour,
+		Scopes:      []string{"roles:read", "users:read"},
+	})
+
+	assert.Equal(t, "access", resp.AccessToken)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, int64(3600), resp.ExpiresIn)
+	assert.Equal(t, "roles:read users:read", resp.Scope)
+}
+
+func TestToOAuthIntrospectionResponse_InactiveHidesDetails(t *testing.T) {
+	resp := ToOAuthIntrospectionResponse(&auth_entity.OAuthIntrospection{
+		Active:   false,
+		ClientID: "client-1",
+	})
+
+	assert.Equal(t, &OAuthIntrospectionResponse{Active: false}, resp)
+}
+
+func TestToProfileResponse(t *testing.T) {
+	resp := ToProfileResponse(&port_auth_usecase.Profile{
+		User:           &user_entity.User{ID: "user-1", Email: "john@example.com", Password: "hash"},
+		Permissions:    []*permission_entity.Permission{{ID: "perm-1", Modules: []string{"users"}, Actions: []string{"read"}}},
+		ActiveSessions: 2,
+		MFAEnabled:     true,
+	})
+
+	assert.Equal(t, "user-1", resp.User.ID)
+	assert.Equal(t, "john@example.com", resp.User.Email)
+	assert.Len(t, resp.Permissions, 1)
+	assert.Equal(t, []string{"users"}, resp.Permissions[0].Modules)
+	assert.Equal(t, 2, resp.ActiveSessions)
+	assert.True(t, resp.MFAEnabled)
+}
@@ -0,0 +1,133 @@
+package auth_service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_event "github.com/williamkoller/system-education/internal/auth/domain/event"
+	port_auth_event "github.com/williamkoller/system-education/internal/auth/port/event"
+	port_auth_service "github.com/williamkoller/system-education/internal/auth/port/service"
+	port_auth_throttle "github.com/williamkoller/system-education/internal/auth/port/throttle"
+)
+
+// LoginThrottle tracks failed logins per account and per client address.
+// Accounts are keyed by the email that was typed, whether or not it belongs
+// to a user, so throttling behaves the same for unknown addresses and does
+// not reveal which accounts exist.
+type LoginThrottle struct {
+	store   port_auth_throttle.LoginAttemptStore
+	account auth_entity.LockoutPolicy
+	ip      auth_entity.LockoutPolicy
+	events  port_auth_event.Dispatcher
+	now     func() time.Time
+}
+
+var _ port_auth_service.LoginThrottle = &LoginThrottle{}
+
+func NewLoginThrottle(store port_auth_throttle.LoginAttemptStore, account, ip auth_entity.LockoutPolicy, events port_auth_event.Dispatcher) *LoginThrottle {
+	return &LoginThrottle{
+		store:   store,
+		account: account,
+		ip:      ip,
+		events:  events,
+		now:     time.Now,
+	}
+}
+
+func (t *LoginThrottle) Check(email, clientIP string) error {
+	now := t.now()
+
+	attempts, err := t.store.Get(accountKey(email))
+	if err != nil {
+		return fmt.Errorf("failed to load login attempts: %w", err)
+	}
+	wait := t.account.RetryAfter(attempts, now)
+	locked := wait > 0 && t.account.IsLocked(attempts)
+
+	if clientIP != "" {
+		attempts, err := t.store.Get(ipKey(clientIP))
+		if err != nil {
+			return fmt.Errorf("failed to load login attempts: %w", err)
+		}
+		if ipWait := t.ip.RetryAfter(attempts, now); ipWait > wait {
+			wait = ipWait
+		}
+	}
+
+	if wait > 0 {
+		return &auth_entity.LoginThrottledError{RetryAfter: wait, Locked: locked}
+	}
+	return nil
+}
+
+func (t *LoginThrottle) RecordFailure(email, clientIP string) error {
+	now := t.now()
+
+	attempts, err := t.store.RecordFailure(accountKey(email), now, t.account.Window)
+	if err != nil {
+		return fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	// Check rejects attempts while the lockout lasts, so every failure that
+	// reaches the threshold starts a new lockout.
+	if t.account.IsLocked(attempts) {
+		t.dispatch(auth_event.NewAccountLockedEvent(normalizeEmail(email), attempts.Failures, t.account.LockedUntil(attempts)))
+	}
+
+	if clientIP != "" {
+		if _, err := t.store.RecordFailure(ipKey(clientIP), now, t.ip.Window); err != nil {
+			return fmt.Errorf("failed to record login attempt: %w", err)
+		}
+	}
+	return nil
+}
+
+// RecordSuccess clears the account's failures. The address keeps its count:
+// one valid login must not reset the budget of an address trying many
+// accounts.
+func (t *LoginThrottle) RecordSuccess(email, clientIP string) error {
+	key := accountKey(email)
+
+	attempts, err := t.store.Get(key)
+	if err != nil {
+		return fmt.Errorf("failed to load login attempts: %w", err)
+	}
+	if attempts.Failures == 0 {
+		return nil
+	}
+
+	if err := t.store.Reset(key); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	if t.account.IsLocked(attempts) {
+		t.dispatch(auth_event.NewAccountUnlockedEvent(normalizeEmail(email), auth_event.UnlockReasonExpired))
+	}
+	return nil
+}
+
+func (t *LoginThrottle) Unlock(email string) error {
+	if err := t.store.Reset(accountKey(email)); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	t.dispatch(auth_event.NewAccountUnlockedEvent(normalizeEmail(email), auth_event.UnlockReasonAdmin))
+	return nil
+}
+
+func (t *LoginThrottle) dispatch(event interface{}) {
+	if t.events != nil {
+		t.events.Dispatch(event)
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func accountKey(email string) string {
+	return "account:" + normalizeEmail(email)
+}
+
+func ipKey(clientIP string) string {
+	return "ip:" + clientIP
+}
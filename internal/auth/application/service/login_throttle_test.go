@@ -0,0 +1,193 @@
+package auth_service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_event "github.com/williamkoller/system-education/internal/auth/domain/event"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type fakeAttemptStore struct {
+	attempts map[string]auth_entity.LoginAttempts
+	err      error
+}
+
+func newFakeAttemptStore() *fakeAttemptStore {
+	return &fakeAttemptStore{attempts: map[string]auth_entity.LoginAttempts{}}
+}
+
+func (s *fakeAttemptStore) Get(key string) (*auth_entity.LoginAttempts, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	attempts := s.attempts[key]
+	attempts.Key = key
+	return &attempts, nil
+}
+
+func (s *fakeAttemptStore) RecordFailure(key string, at time.Time, window time.Duration) (*auth_entity.LoginAttempts, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	attempts := s.attempts[key]
+	if window > 0 && at.Sub(attempts.LastFailureAt) >= window {
+		attempts = auth_entity.LoginAttempts{}
+	}
+	attempts.Key = key
+	attempts.Failures++
+	attempts.LastFailureAt = at
+	s.attempts[key] = attempts
+	return &attempts, nil
+}
+
+func (s *fakeAttemptStore) Reset(key string) error {
+	delete(s.attempts, key)
+	return s.err
+}
+
+type MockDispatcher struct {
+	mock.Mock
+}
+
+func (m *MockDispatcher) Dispatch(event interface{}) {
+	m.Called(event)
+}
+
+func (m *MockDispatcher) Register(eventName string, handler shared_event.Handler) {
+	m.Called(eventName, handler)
+}
+
+func newTestLoginThrottle() (*LoginThrottle, *fakeAttemptStore, *MockDispatcher, *time.Time) {
+	store := newFakeAttemptStore()
+	events := new(MockDispatcher)
+	account := auth_entity.LockoutPolicy{
+		FreeAttempts:     2,
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		LockoutThreshold: 4,
+		LockoutDuration:  15 * time.Minute,
+		Window:           time.Hour,
+	}
+	ip := auth_entity.LockoutPolicy{FreeAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Minute, Window: time.Hour}
+
+	throttle := NewLoginThrottle(store, account, ip, events)
+	now := testNow
+	throttle.now = func() time.Time { return now }
+	return throttle, store, events, &now
+}
+
+func TestLoginThrottle_AllowsFreeAttempts(t *testing.T) {
+	throttle, _, _, _ := newTestLoginThrottle()
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, throttle.Check("john@example.com", "10.0.0.1"))
+		require.NoError(t, throttle.RecordFailure("john@example.com", "10.0.0.1"))
+	}
+
+	assert.NoError(t, throttle.Check("john@example.com", "10.0.0.1"))
+}
+
+func TestLoginThrottle_BacksOffAfterFreeAttempts(t *testing.T) {
+	throttle, _, _, now := newTestLoginThrottle()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, throttle.RecordFailure("John@Example.com", "10.0.0.1"))
+	}
+
+	var throttled *auth_entity.LoginThrottledError
+	err := throttle.Check("john@example.com", "10.0.0.2")
+	require.ErrorAs(t, err, &throttled)
+	assert.ErrorIs(t, err, auth_entity.ErrTooManyLoginAttempts)
+	assert.Equal(t, time.Second, throttled.RetryAfter)
+	assert.False(t, throttled.Locked)
+
+	*now = now.Add(time.Second)
+	assert.NoError(t, throttle.Check("john@example.com", "10.0.0.2"))
+}
+
+func TestLoginThrottle_LocksAccountAndDispatchesEvent(t *testing.T) {
+	throttle, _, events, now := newTestLoginThrottle()
+	events.On("Dispatch", mock.MatchedBy(func(e *auth_event.AccountLockedEvent) bool {
+		return e.Email == "john@example.com" && e.Failures == 4 && e.LockedUntil.Equal(testNow.Add(15*time.Minute))
+	})).Once()
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, throttle.RecordFailure("john@example.com", ""))
+	}
+
+	var throttled *auth_entity.LoginThrottledError
+	require.ErrorAs(t, throttle.Check("john@example.com", ""), &throttled)
+	assert.True(t, throttled.Locked)
+	assert.Equal(t, 15*time.Minute, throttled.RetryAfter)
+
+	*now = now.Add(15 * time.Minute)
+	assert.NoError(t, throttle.Check("john@example.com", ""))
+	events.AssertExpectations(t)
+}
+
+func TestLoginThrottle_ThrottlesByAddressAcrossAccounts(t *testing.T) {
+	throttle, _, _, _ := newTestLoginThrottle()
+
+	for i := 0; i < 6; i++ {
+		require.NoError(t, throttle.RecordFailure("user"+string(rune('a'+i))+"@example.com", "10.0.0.1"))
+	}
+
+	assert.ErrorIs(t, throttle.Check("new@example.com", "10.0.0.1"), auth_entity.ErrTooManyLoginAttempts)
+	assert.NoError(t, throttle.Check("new@example.com", "10.0.0.2"))
+}
+
+func TestLoginThrottle_RecordSuccessResetsAccountOnly(t *testing.T) {
+	throttle, store, _, _ := newTestLoginThrottle()
+
+	require.NoError(t, throttle.RecordFailure("john@example.com", "10.0.0.1"))
+	require.NoError(t, throttle.RecordSuccess("john@example.com", "10.0.0.1"))
+
+	assert.NotContains(t, store.attempts, "account:john@example.com")
+	assert.Equal(t, 1, store.attempts["ip:10.0.0.1"].Failures)
+}
+
+func TestLoginThrottle_RecordSuccessAfterLockoutDispatchesUnlock(t *testing.T) {
+	throttle, _, events, now := newTestLoginThrottle()
+	events.On("Dispatch", mock.AnythingOfType("*auth_event.AccountLockedEvent"))
+	events.On("Dispatch", mock.MatchedBy(func(e *auth_event.AccountUnlockedEvent) bool {
+		return e.Email == "john@example.com" && e.Reason == auth_event.UnlockReasonExpired
+	})).Once()
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, throttle.RecordFailure("john@example.com", ""))
+	}
+	*now = now.Add(15 * time.Minute)
+
+	require.NoError(t, throttle.RecordSuccess("john@example.com", ""))
+	events.AssertExpectations(t)
+}
+
+func TestLoginThrottle_Unlock(t *testing.T) {
+	throttle, _, events, _ := newTestLoginThrottle()
+	events.On("Dispatch", mock.AnythingOfType("*auth_event.AccountLockedEvent"))
+	events.On("Dispatch", mock.MatchedBy(func(e *auth_event.AccountUnlockedEvent) bool {
+		return e.Reason == auth_event.UnlockReasonAdmin
+	})).Once()
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, throttle.RecordFailure("john@example.com", ""))
+	}
+
+	require.NoError(t, throttle.Unlock("john@example.com"))
+	assert.NoError(t, throttle.Check("john@example.com", ""))
+	events.AssertExpectations(t)
+}
+
+func TestLoginThrottle_StoreErrorFailsClosed(t *testing.T) {
+	throttle, store, _, _ := newTestLoginThrottle()
+	store.err = errors.New("db down")
+
+	assert.ErrorContains(t, throttle.Check("john@example.com", "10.0.0.1"), "failed to load login attempts")
+	assert.ErrorContains(t, throttle.RecordFailure("john@example.com", "10.0.0.1"), "failed to record login attempt")
+}
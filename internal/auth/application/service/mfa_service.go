@@ -0,0 +1,311 @@
+package auth_service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_service "github.com/williamkoller/system-education/internal/auth/port/service"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+)
+
+const RecoveryCodeCount = 10
+
+// MFAService owns TOTP enrollment and the second step of a login. Users who
+// confirmed a TOTP secret always get a challenge; users holding one of the
+// required modules get one even when they have not enrolled, together with
+// a fresh secret the first verified code will confirm.
+type MFAService struct {
+	repo               port_auth_repository.MFARepository
+	challenges         port_auth_repository.MFAChallengeRepository
+	otp                port_auth_cryptography.OTPProvider
+	recoveryCodes      port_auth_cryptography.RecoveryCodeGenerator
+	tokenGenerator     port_auth_cryptography.SecureTokenGenerator
+	permissions        port_permission_service.PermissionService
+	requiredModules    []string
+	challengeExpiresIn time.Duration
+	now                func() time.Time
+}
+
+var _ port_auth_service.MFAService = &MFAService{}
+
+func NewMFAService(
+	repo port_auth_repository.MFARepository,
+	challenges port_auth_repository.MFAChallengeRepository,
+	otp port_auth_cryptography.OTPProvider,
+	recoveryCodes port_auth_cryptography.RecoveryCodeGenerator,
+	tokenGenerator port_auth_cryptography.SecureTokenGenerator,
+	permissions port_permission_service.PermissionService,
+	requiredModules []string,
+	challengeExpiresIn time.Duration,
+) *MFAService {
+	return &MFAService{
+		repo:               repo,
+		challenges:         challenges,
+		otp:                otp,
+		recoveryCodes:      recoveryCodes,
+		tokenGenerator:     tokenGenerator,
+		permissions:        permissions,
+		requiredModules:    requiredModules,
+		challengeExpiresIn: challengeExpiresIn,
+		now:                time.Now,
+	}
+}
+
+func (s *MFAService) Challenge(userID, account string) (*auth_entity.MFAChallengeTicket, error) {
+	credential, err := s.findCredential(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var enrollment *auth_entity.MFAEnrollment
+	if !credential.IsConfirmed() {
+		required, err := s.required(userID)
+		if err != nil {
+			return nil, err
+		}
+		if !required {
+			return nil, nil
+		}
+
+		// Reuse a pending secret so a user who already scanned it is not
+		// asked to scan a new one on every attempt.
+		if credential == nil {
+			if credential, err = s.newCredential(userID); err != nil {
+				return nil, err
+			}
+		}
+		enrollment = &auth_entity.MFAEnrollment{
+			Secret: credential.Secret,
+			URI:    s.otp.URI(credential.Secret, account),
+		}
+	}
+
+	token, hash, err := s.tokenGenerator.Generate()
+	if err != nil {
+		return nil, errors.New("error in generate mfa challenge")
+	}
+
+	challenge := auth_entity.NewMFAChallenge(uuid.New().String(), userID, hash, s.now().Add(s.challengeExpiresIn))
+	if _, err := s.challenges.Save(challenge); err != nil {
+		return nil, fmt.Errorf("failed to save mfa challenge: %w", err)
+	}
+
+	return &auth_entity.MFAChallengeTicket{
+		Token:      token,
+		ExpiresIn:  s.challengeExpiresIn,
+		Enrollment: enrollment,
+	}, nil
+}
+
+func (s *MFAService) Verify(challengeToken, code, recoveryCode string) (string, []string, error) {
+	if challengeToken == "" {
+		return "", nil, auth_entity.ErrInvalidMFAChallenge
+	}
+
+	challenge, err := s.challenges.FindByTokenHash(s.tokenGenerator.Hash(challengeToken))
+	if err != nil {
+		return "", nil, auth_entity.ErrInvalidMFAChallenge
+	}
+
+	now := s.now()
+	if challenge.IsUsed() || challenge.IsExpired(now) {
+		return "", nil, auth_entity.ErrInvalidMFAChallenge
+	}
+
+	allowed, err := s.challenges.ConsumeAttempt(challenge.ID, auth_entity.MaxMFAChallengeAttempts)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to record mfa attempt: %w", err)
+	}
+	if !allowed {
+		return "", nil, auth_entity.ErrInvalidMFAChallenge
+	}
+
+	credential, err := s.findCredential(challenge.UserID)
+	if err != nil {
+		return "", nil, err
+	}
+	if credential == nil {
+		return "", nil, auth_entity.ErrInvalidMFAChallenge
+	}
+
+	var codes []string
+	switch {
+	case recoveryCode != "":
+		if !credential.IsConfirmed() {
+			return "", nil, auth_entity.ErrInvalidMFACode
+		}
+		if err := s.useRecoveryCode(challenge.UserID, recoveryCode, now); err != nil {
+			return "", nil, err
+		}
+	case code != "":
+		step, ok := s.otp.Validate(credential.Secret, code, now)
+		if !ok {
+			return "", nil, auth_entity.ErrInvalidMFACode
+		}
+		if credential.IsConfirmed() {
+			if err := s.advanceStep(challenge.UserID, step); err != nil {
+				return "", nil, err
+			}
+		} else if codes, err = s.confirm(challenge.UserID, step, now); err != nil {
+			return "", nil, err
+		}
+	default:
+		return "", nil, auth_entity.ErrInvalidMFACode
+	}
+
+	marked, err := s.challenges.MarkUsed(challenge.ID, now)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to consume mfa challenge: %w", err)
+	}
+	if !marked {
+		return "", nil, auth_entity.ErrInvalidMFAChallenge
+	}
+
+	return challenge.UserID, codes, nil
+}
+
+// Enroll starts (or restarts) enrollment with a new secret. It refuses to
+// replace a confirmed credential.
+func (s *MFAService) Enroll(userID, account string) (*auth_entity.MFAEnrollment, error) {
+	credential, err := s.findCredential(userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential.IsConfirmed() {
+		return nil, auth_entity.ErrMFAAlreadyEnabled
+	}
+
+	credential, err = s.newCredential(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth_entity.MFAEnrollment{
+		Secret: credential.Secret,
+		URI:    s.otp.URI(credential.Secret, account),
+	}, nil
+}
+
+// Confirm activates a pending credential and returns the recovery codes,
+// which are never shown again.
+func (s *MFAService) Confirm(userID, code string) ([]string, error) {
+	credential, err := s.findCredential(userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil {
+		return nil, auth_entity.ErrMFANotEnrolled
+	}
+	if credential.IsConfirmed() {
+		return nil, auth_entity.ErrMFAAlreadyEnabled
+	}
+
+	now := s.now()
+	step, ok := s.otp.Validate(credential.Secret, code, now)
+	if !ok {
+		return nil, auth_entity.ErrInvalidMFACode
+	}
+
+	return s.confirm(userID, step, now)
+}
+
+// findCredential returns nil without an error when the user never enrolled.
+func (s *MFAService) findCredential(userID string) (*auth_entity.MFACredential, error) {
+	credential, err := s.repo.FindByUserID(userID)
+	if errors.Is(err, auth_entity.ErrMFANotEnrolled) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mfa credential: %w", err)
+	}
+	return credential, nil
+}
+
+func (s *MFAService) newCredential(userID string) (*auth_entity.MFACredential, error) {
+	secret, err := s.otp.GenerateSecret()
+	if err != nil {
+		return nil, errors.New("error in generate mfa secret")
+	}
+
+	credential, err := s.repo.Save(auth_entity.NewMFACredential(userID, secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save mfa credential: %w", err)
+	}
+	return credential, nil
+}
+
+// required reports whether the user holds a non-denied grant on one of the
+// modules that mandate MFA. A failed lookup fails the login instead of
+// letting the user through without a second factor.
+func (s *MFAService) required(userID string) (bool, error) {
+	if len(s.requiredModules) == 0 {
+		return false, nil
+	}
+
+	effective, err := s.permissions.Resolve(userID, 0)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+
+	for _, grant := range effective.Grants() {
+		if grant.Level == permission_entity.LevelDenied {
+			continue
+		}
+		for _, module := range s.requiredModules {
+			if grant.Module == module {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *MFAService) confirm(userID string, step int64, now time.Time) ([]string, error) {
+	codes, err := s.recoveryCodes.Generate(RecoveryCodeCount)
+	if err != nil {
+		return nil, errors.New("error in generate recovery codes")
+	}
+
+	stored := make([]*auth_entity.RecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		hash := s.tokenGenerator.Hash(auth_entity.NormalizeRecoveryCode(code))
+		stored = append(stored, auth_entity.NewRecoveryCode(uuid.New().String(), userID, hash))
+	}
+
+	if err := s.repo.Confirm(userID, step, now, stored); err != nil {
+		if errors.Is(err, auth_entity.ErrMFANotEnrolled) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to confirm mfa credential: %w", err)
+	}
+	return codes, nil
+}
+
+func (s *MFAService) advanceStep(userID string, step int64) error {
+	advanced, err := s.repo.AdvanceStep(userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to record mfa step: %w", err)
+	}
+	if !advanced {
+		return auth_entity.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *MFAService) useRecoveryCode(userID, code string, now time.Time) error {
+	hash := s.tokenGenerator.Hash(auth_entity.NormalizeRecoveryCode(code))
+	used, err := s.repo.UseRecoveryCode(userID, hash, now)
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if !used {
+		return auth_entity.ErrInvalidMFACode
+	}
+	return nil
+}
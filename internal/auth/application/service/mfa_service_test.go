@@ -0,0 +1,642 @@
+package auth_service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+type MockMFARepository struct {
+	mock.Mock
+}
+
+func (m *MockMFARepository) FindByUserID(userID string) (*auth_entity.MFACredential, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.MFACredential), args.Error(1)
+}
+
+func (m *MockMFARepository) Save(c *auth_entity.MFACredential) (*auth_entity.MFACredential, error) {
+	args := m.Called(c)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.MFACredential), args.Error(1)
+}
+
+func (m *MockMFARepository) Confirm(userID string, step int64, confirmedAt time.Time, codes []*auth_entity.RecoveryCode) error {
+	args := m.Called(userID, step, confirmedAt, codes)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) AdvanceStep(userID string, step int64) (bool, error) {
+	args := m.Called(userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) UseRecoveryCode(userID, codeHash string, usedAt time.Time) (bool, error) {
+	args := m.Called(userID, codeHash, usedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockMFAChallengeRepository struct {
+	mock.Mock
+}
+
+func (m *MockMFAChallengeRepository) Save(c *auth_entity.MFAChallenge) (*auth_entity.MFAChallenge, error) {
+	args := m.Called(c)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.MFAChallenge), args.Error(1)
+}
+
+func (m *MockMFAChallengeRepository) FindByTokenHash(hash string) (*auth_entity.MFAChallenge, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.MFAChallenge), args.Error(1)
+}
+
+func (m *MockMFAChallengeRepository) ConsumeAttempt(id string, maxAttempts int) (bool, error) {
+	args := m.Called(id, maxAttempts)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFAChallengeRepository) MarkUsed(id string, usedAt time.Time) (bool, error) {
+	args := m.Called(id, usedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockOTPProvider struct {
+	mock.Mock
+}
+
+func (m *MockOTPProvider) GenerateSecret() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockOTPProvider) URI(secret, account string) string {
+	args := m.Called(secret, account)
+	return args.String(0)
+}
+
+func (m *MockOTPProvider) Validate(secret, code string, at time.Time) (int64, bool) {
+	args := m.Called(secret, code, at)
+	return args.Get(0).(int64), args.Bool(1)
+}
+
+type MockRecoveryCodeGenerator struct {
+	mock.Mock
+}
+
+func (m *MockRecoveryCodeGenerator) Generate(count int) ([]string, error) {
+	args := m.Called(count)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type MockSecureTokenGenerator struct {
+	mock.Mock
+}
+
+func (m *MockSecureTokenGenerator) Generate() (string, string, error) {
+	args := m.Called()
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockSecureTokenGenerator) Hash(token string) string {
+	return "hash:" + token
+}
+
+type MockPermissionService struct {
+	mock.Mock
+}
+
+func (m *MockPermissionService) Resolve(userID string, minVersion int64) (*permission_entity.EffectivePermissions, error) {
+	args := m.Called(userID, minVersion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Refresh(userID string) (*permission_entity.EffectivePermissions, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Invalidate(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func confirmedCredential() *auth_entity.MFACredential {
+	confirmedAt := testNow.Add(-time.Hour)
+	return &auth_entity.MFACredential{UserID: "user-1", Secret: "SECRET", ConfirmedAt: &confirmedAt, LastUsedStep: 10}
+}
+
+func effective(modules ...string) *permission_entity.EffectivePermissions {
+	return permission_entity.NewEffectivePermissions("user-1", 1, []*permission_entity.Permission{
+		{Modules: modules, Actions: []string{"read"}, Level: string(permission_entity.LevelAllowed)},
+	})
+}
+
+func TestMFAService_Challenge_NotNeeded(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockRepo.On("FindByUserID", "user-1").Return(nil, auth_entity.ErrMFANotEnrolled)
+	mockPermissions.On("Resolve", "user-1", int64(0)).Return(effective("users"), nil)
+
+	ticket, err := service.Challenge("user-1", "john@example.com")
+
+	assert.NoError(t, err)
+	assert.Nil(t, ticket)
+	mockChallenges.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestMFAService_Challenge_Enrolled(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockRepo.On("FindByUserID", "user-1").Return(confirmedCredential(), nil)
+	mockTokens.On("Generate").Return("challenge", "challenge-hash", nil)
+	mockChallenges.On("Save", mock.MatchedBy(func(c *auth_entity.MFAChallenge) bool {
+		return c.UserID == "user-1" && c.TokenHash == "challenge-hash" && c.ExpiresAt.Equal(testNow.Add(5*time.Minute))
+	})).Return(&auth_entity.MFAChallenge{}, nil)
+
+	ticket, err := service.Challenge("user-1", "john@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "challenge", ticket.Token)
+	assert.Equal(t, 5*time.Minute, ticket.ExpiresIn)
+	assert.Nil(t, ticket.Enrollment)
+	mockPermissions.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything)
+	mockChallenges.AssertExpectations(t)
+}
+
+func TestMFAService_Challenge_EnforcedEnrollment(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockRepo.On("FindByUserID", "user-1").Return(nil, auth_entity.ErrMFANotEnrolled)
+	mockPermissions.On("Resolve", "user-1", int64(0)).Return(effective("permissions"), nil)
+	mockOTP.On("GenerateSecret").Return("NEWSECRET", nil)
+	mockRepo.On("Save", mock.MatchedBy(func(c *auth_entity.MFACredential) bool {
+		return c.UserID == "user-1" && c.Secret == "NEWSECRET" && !c.IsConfirmed()
+	})).Return(auth_entity.NewMFACredential("user-1", "NEWSECRET"), nil)
+	mockOTP.On("URI", "NEWSECRET", "john@example.com").Return("otpauth://totp/x")
+	mockTokens.On("Generate").Return("challenge", "challenge-hash", nil)
+	mockChallenges.On("Save", mock.Anything).Return(&auth_entity.MFAChallenge{}, nil)
+
+	ticket, err := service.Challenge("user-1", "john@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "challenge", ticket.Token)
+	assert.Equal(t, "NEWSECRET", ticket.Enrollment.Secret)
+	assert.Equal(t, "otpauth://totp/x", ticket.Enrollment.URI)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMFAService_Challenge_EnforcedReusesPendingSecret(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockRepo.On("FindByUserID", "user-1").Return(auth_entity.NewMFACredential("user-1", "PENDING"), nil)
+	mockPermissions.On("Resolve", "user-1", int64(0)).Return(effective("permissions"), nil)
+	mockOTP.On("URI", "PENDING", "john@example.com").Return("otpauth://totp/x")
+	mockTokens.On("Generate").Return("challenge", "challenge-hash", nil)
+	mockChallenges.On("Save", mock.Anything).Return(&auth_entity.MFAChallenge{}, nil)
+
+	ticket, err := service.Challenge("user-1", "john@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PENDING", ticket.Enrollment.Secret)
+	mockOTP.AssertNotCalled(t, "GenerateSecret")
+}
+
+func TestMFAService_Challenge_DeniedGrantDoesNotEnforce(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockRepo.On("FindByUserID", "user-1").Return(nil, auth_entity.ErrMFANotEnrolled)
+	mockPermissions.On("Resolve", "user-1", int64(0)).Return(permission_entity.NewEffectivePermissions("user-1", 1, []*permission_entity.Permission{
+		{Modules: []string{"permissions"}, Actions: []string{"read"}, Level: string(permission_entity.LevelDenied)},
+	}), nil)
+
+	ticket, err := service.Challenge("user-1", "john@example.com")
+
+	assert.NoError(t, err)
+	assert.Nil(t, ticket)
+}
+
+func TestMFAService_Challenge_PermissionErrorFailsClosed(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockRepo.On("FindByUserID", "user-1").Return(nil, auth_entity.ErrMFANotEnrolled)
+	mockPermissions.On("Resolve", "user-1", int64(0)).Return(nil, errors.New("db down"))
+
+	ticket, err := service.Challenge("user-1", "john@example.com")
+
+	assert.Error(t, err)
+	assert.Nil(t, ticket)
+}
+
+func activeChallenge() *auth_entity.MFAChallenge {
+	return auth_entity.NewMFAChallenge("ch-1", "user-1", "hash:challenge", testNow.Add(time.Minute))
+}
+
+func TestMFAService_Verify_TOTP(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockChallenges.On("FindByTokenHash", "hash:challenge").Return(activeChallenge(), nil)
+	mockChallenges.On("ConsumeAttempt", "ch-1", auth_entity.MaxMFAChallengeAttempts).Return(true, nil)
+	mockRepo.On("FindByUserID", "user-1").Return(confirmedCredential(), nil)
+	mockOTP.On("Validate", "SECRET", "123456", testNow).Return(int64(11), true)
+	mockRepo.On("AdvanceStep", "user-1", int64(11)).Return(true, nil)
+	mockChallenges.On("MarkUsed", "ch-1", testNow).Return(true, nil)
+
+	userID, codes, err := service.Verify("challenge", "123456", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+	assert.Nil(t, codes)
+	mockChallenges.AssertExpectations(t)
+}
+
+func TestMFAService_Verify_ReplayedCode(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockChallenges.On("FindByTokenHash", "hash:challenge").Return(activeChallenge(), nil)
+	mockChallenges.On("ConsumeAttempt", "ch-1", auth_entity.MaxMFAChallengeAttempts).Return(true, nil)
+	mockRepo.On("FindByUserID", "user-1").Return(confirmedCredential(), nil)
+	mockOTP.On("Validate", "SECRET", "123456", testNow).Return(int64(10), true)
+	mockRepo.On("AdvanceStep", "user-1", int64(10)).Return(false, nil)
+
+	_, _, err := service.Verify("challenge", "123456", "")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFACode)
+	mockChallenges.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+}
+
+func TestMFAService_Verify_WrongCode(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockChallenges.On("FindByTokenHash", "hash:challenge").Return(activeChallenge(), nil)
+	mockChallenges.On("ConsumeAttempt", "ch-1", auth_entity.MaxMFAChallengeAttempts).Return(true, nil)
+	mockRepo.On("FindByUserID", "user-1").Return(confirmedCredential(), nil)
+	mockOTP.On("Validate", "SECRET", "000000", testNow).Return(int64(0), false)
+
+	_, _, err := service.Verify("challenge", "000000", "")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFACode)
+}
+
+func TestMFAService_Verify_RecoveryCode(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockChallenges.On("FindByTokenHash", "hash:challenge").Return(activeChallenge(), nil)
+	mockChallenges.On("ConsumeAttempt", "ch-1", auth_entity.MaxMFAChallengeAttempts).Return(true, nil)
+	mockRepo.On("FindByUserID", "user-1").Return(confirmedCredential(), nil)
+	mockRepo.On("UseRecoveryCode", "user-1", "hash:abcdefghijklmnop", testNow).Return(true, nil)
+	mockChallenges.On("MarkUsed", "ch-1", testNow).Return(true, nil)
+
+	userID, _, err := service.Verify("challenge", "", "ABCDEFGH-ijklmnop")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMFAService_Verify_UsedRecoveryCode(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockChallenges.On("FindByTokenHash", "hash:challenge").Return(activeChallenge(), nil)
+	mockChallenges.On("ConsumeAttempt", "ch-1", auth_entity.MaxMFAChallengeAttempts).Return(true, nil)
+	mockRepo.On("FindByUserID", "user-1").Return(confirmedCredential(), nil)
+	mockRepo.On("UseRecoveryCode", "user-1", "hash:abcdefghijklmnop", testNow).Return(false, nil)
+
+	_, _, err := service.Verify("challenge", "", "abcdefgh-ijklmnop")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFACode)
+}
+
+func TestMFAService_Verify_ConfirmsPendingEnrollment(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockChallenges.On("FindByTokenHash", "hash:challenge").Return(activeChallenge(), nil)
+	mockChallenges.On("ConsumeAttempt", "ch-1", auth_entity.MaxMFAChallengeAttempts).Return(true, nil)
+	mockRepo.On("FindByUserID", "user-1").Return(auth_entity.NewMFACredential("user-1", "PENDING"), nil)
+	mockOTP.On("Validate", "PENDING", "123456", testNow).Return(int64(11), true)
+	mockCodes.On("Generate", RecoveryCodeCount).Return([]string{"aaaa-bbbb", "cccc-dddd"}, nil)
+	mockRepo.On("Confirm", "user-1", int64(11), testNow, mock.MatchedBy(func(codes []*auth_entity.RecoveryCode) bool {
+		return len(codes) == 2 && codes[0].CodeHash == "hash:aaaabbbb" && codes[1].CodeHash == "hash:ccccdddd"
+	})).Return(nil)
+	mockChallenges.On("MarkUsed", "ch-1", testNow).Return(true, nil)
+
+	userID, codes, err := service.Verify("challenge", "123456", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+	assert.Equal(t, []string{"aaaa-bbbb", "cccc-dddd"}, codes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMFAService_Verify_PendingEnrollmentRejectsRecoveryCode(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockChallenges.On("FindByTokenHash", "hash:challenge").Return(activeChallenge(), nil)
+	mockChallenges.On("ConsumeAttempt", "ch-1", auth_entity.MaxMFAChallengeAttempts).Return(true, nil)
+	mockRepo.On("FindByUserID", "user-1").Return(auth_entity.NewMFACredential("user-1", "PENDING"), nil)
+
+	_, _, err := service.Verify("challenge", "", "aaaa-bbbb")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFACode)
+}
+
+func TestMFAService_Verify_ExpiredChallenge(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	challenge := activeChallenge()
+	mockChallenges.On("FindByTokenHash", "hash:challenge").Return(challenge, nil)
+	service.now = func() time.Time { return challenge.ExpiresAt }
+
+	_, _, err := service.Verify("challenge", "123456", "")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFAChallenge)
+	mockChallenges.AssertNotCalled(t, "ConsumeAttempt", mock.Anything, mock.Anything)
+}
+
+func TestMFAService_Verify_UsedChallenge(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	challenge := activeChallenge()
+	usedAt := testNow.Add(-time.Second)
+	challenge.UsedAt = &usedAt
+	mockChallenges.On("FindByTokenHash", "hash:challenge").Return(challenge, nil)
+
+	_, _, err := service.Verify("challenge", "123456", "")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFAChallenge)
+}
+
+func TestMFAService_Verify_TooManyAttempts(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockChallenges.On("FindByTokenHash", "hash:challenge").Return(activeChallenge(), nil)
+	mockChallenges.On("ConsumeAttempt", "ch-1", auth_entity.MaxMFAChallengeAttempts).Return(false, nil)
+
+	_, _, err := service.Verify("challenge", "123456", "")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFAChallenge)
+	mockRepo.AssertNotCalled(t, "FindByUserID", mock.Anything)
+}
+
+func TestMFAService_Verify_UnknownChallenge(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockChallenges.On("FindByTokenHash", "hash:missing").Return(nil, auth_entity.ErrMFAChallengeNotFound)
+
+	_, _, err := service.Verify("missing", "123456", "")
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFAChallenge)
+
+	_, _, err = service.Verify("", "123456", "")
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFAChallenge)
+}
+
+func TestMFAService_Verify_MissingCode(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockChallenges.On("FindByTokenHash", "hash:challenge").Return(activeChallenge(), nil)
+	mockChallenges.On("ConsumeAttempt", "ch-1", auth_entity.MaxMFAChallengeAttempts).Return(true, nil)
+	mockRepo.On("FindByUserID", "user-1").Return(confirmedCredential(), nil)
+
+	_, _, err := service.Verify("challenge", "", "")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFACode)
+}
+
+func TestMFAService_Enroll(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockRepo.On("FindByUserID", "user-1").Return(nil, auth_entity.ErrMFANotEnrolled)
+	mockOTP.On("GenerateSecret").Return("NEWSECRET", nil)
+	mockRepo.On("Save", mock.Anything).Return(auth_entity.NewMFACredential("user-1", "NEWSECRET"), nil)
+	mockOTP.On("URI", "NEWSECRET", "john@example.com").Return("otpauth://totp/x")
+
+	enrollment, err := service.Enroll("user-1", "john@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "NEWSECRET", enrollment.Secret)
+	assert.Equal(t, "otpauth://totp/x", enrollment.URI)
+}
+
+func TestMFAService_Enroll_AlreadyEnabled(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockRepo.On("FindByUserID", "user-1").Return(confirmedCredential(), nil)
+
+	enrollment, err := service.Enroll("user-1", "john@example.com")
+
+	assert.ErrorIs(t, err, auth_entity.ErrMFAAlreadyEnabled)
+	assert.Nil(t, enrollment)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestMFAService_Confirm(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockRepo.On("FindByUserID", "user-1").Return(auth_entity.NewMFACredential("user-1", "PENDING"), nil)
+	mockOTP.On("Validate", "PENDING", "123456", testNow).Return(int64(11), true)
+	mockCodes.On("Generate", RecoveryCodeCount).Return([]string{"aaaa-bbbb"}, nil)
+	mockRepo.On("Confirm", "user-1", int64(11), testNow, mock.Anything).Return(nil)
+
+	codes, err := service.Confirm("user-1", "123456")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"aaaa-bbbb"}, codes)
+}
+
+func TestMFAService_Confirm_Errors(t *testing.T) {
+	mockRepo := new(MockMFARepository)
+	mockChallenges := new(MockMFAChallengeRepository)
+	mockOTP := new(MockOTPProvider)
+	mockCodes := new(MockRecoveryCodeGenerator)
+	mockTokens := new(MockSecureTokenGenerator)
+	mockPermissions := new(MockPermissionService)
+	service := NewMFAService(mockRepo, mockChallenges, mockOTP, mockCodes, mockTokens, mockPermissions, []string{"permissions"}, 5*time.Minute)
+	service.now = func() time.Time { return testNow }
+
+	mockRepo.On("FindByUserID", "missing").Return(nil, auth_entity.ErrMFANotEnrolled)
+	mockRepo.On("FindByUserID", "enabled").Return(confirmedCredential(), nil)
+	mockRepo.On("FindByUserID", "pending").Return(auth_entity.NewMFACredential("pending", "PENDING"), nil)
+	mockOTP.On("Validate", "PENDING", "000000", testNow).Return(int64(0), false)
+
+	_, err := service.Confirm("missing", "123456")
+	assert.ErrorIs(t, err, auth_entity.ErrMFANotEnrolled)
+
+	_, err = service.Confirm("enabled", "123456")
+	assert.ErrorIs(t, err, auth_entity.ErrMFAAlreadyEnabled)
+
+	_, err = service.Confirm("pending", "000000")
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFACode)
+}
@@ -0,0 +1,399 @@
+package auth_usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_revocation "github.com/williamkoller/system-education/internal/auth/port/revocation"
+	port_auth_service "github.com/williamkoller/system-education/internal/auth/port/service"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
+	port_email_notifier "github.com/williamkoller/system-education/internal/user/port/email"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	port_user_service "github.com/williamkoller/system-education/internal/user/port/service"
+)
+
+// AccountUsecase runs the flows that prove control of an email address:
+// password resets, email verification and email changes. Each mails a
+// single-use link whose token is only stored hashed.
+type AccountUsecase struct {
+	repo                  port_user_repository.UserRepository
+	passwordHasher        port_cryptography.Bcrypt
+	passwords             port_user_service.PasswordPolicy
+	tokens                port_auth_repository.OneTimeTokenRepository
+	tokenGenerator        port_auth_cryptography.SecureTokenGenerator
+	notifier              port_email_notifier.EmailNotifier
+	revocations           port_auth_revocation.RevocationStore
+	refreshRepo           port_auth_repository.RefreshTokenRepository
+	sessions              port_auth_repository.SessionRepository
+	throttle              port_auth_service.LoginThrottle
+	publicURL             string
+	resetExpiresIn        time.Duration
+	verificationExpiresIn time.Duration
+	now                   func() time.Time
+	// deliver sends mail off the request path, so requests for known and
+	// unknown addresses take the same time.
+	deliver func(func())
+}
+
+var _ port_auth_usecase.AccountUsecase = &AccountUsecase{}
+
+type AccountOption func(*AccountUsecase)
+
+// WithAccountSessions ends the user's sessions when their password is reset.
+func WithAccountSessions(sessions port_auth_repository.SessionRepository) AccountOption {
+	return func(a *AccountUsecase) {
+		a.sessions = sessions
+	}
+}
+
+// WithAccountLoginThrottle lifts a login lockout once the password is reset,
+// since whoever reset it has proven control of the address.
+func WithAccountLoginThrottle(throttle port_auth_service.LoginThrottle) AccountOption {
+	return func(a *AccountUsecase) {
+		a.throttle = throttle
+	}
+}
+
+func NewAccountUsecase(
+	repo port_user_repository.UserRepository,
+	passwordHasher port_cryptography.Bcrypt,
+	passwords port_user_service.PasswordPolicy,
+	tokens port_auth_repository.OneTimeTokenRepository,
+	tokenGenerator port_auth_cryptography.SecureTokenGenerator,
+	notifier port_email_notifier.EmailNotifier,
+	revocations port_auth_revocation.RevocationStore,
+	refreshRepo port_auth_repository.RefreshTokenRepository,
+	publicURL string,
+	resetExpiresIn time.Duration,
+	verificationExpiresIn time.Duration,
+	opts ...AccountOption,
+) *AccountUsecase {
+	usecase := &AccountUsecase{
+		repo:                  repo,
+		passwordHasher:        passwordHasher,
+		passwords:             passwords,
+		tokens:                tokens,
+		tokenGenerator:        tokenGenerator,
+		notifier:              notifier,
+		revocations:           revocations,
+		refreshRepo:           refreshRepo,
+		publicURL:             strings.TrimRight(publicURL, "/"),
+		resetExpiresIn:        resetExpiresIn,
+		verificationExpiresIn: verificationExpiresIn,
+		now:                   time.Now,
+		deliver:               func(send func()) { go send() },
+	}
+	for _, opt := range opts {
+		opt(usecase)
+	}
+	return usecase
+}
+
+// ForgotPassword mails a reset link when the email belongs to a user. It
+// succeeds either way so the endpoint cannot be used to probe for accounts.
+func (a *AccountUsecase) ForgotPassword(email string) error {
+	user, err := a.repo.FindByEmail(email)
+	if err != nil || user == nil {
+		return nil
+	}
+
+	token, err := a.issue(user.ID, auth_entity.TokenPurposePasswordReset, a.resetExpiresIn, "")
+	if err != nil {
+		return err
+	}
+
+	link := a.link("/reset-password", token)
+	a.deliver(func() {
+		if err := a.notifier.SendPasswordResetEmail(user.Name, user.Email, link); err != nil {
+			log.Printf("Erro ao enviar e-mail de redefinição de senha: %v", err)
+		}
+	})
+	return nil
+}
+
+// ResetPassword sets a new password, signs the user out everywhere and
+// lifts any login lockout. The link arrived by email, so following it also
+// verifies the address. A password the policy rejects leaves the token
+// usable for another try.
+func (a *AccountUsecase) ResetPassword(token, newPassword string) error {
+	stored, err := a.find(auth_entity.TokenPurposePasswordReset, token)
+	if err != nil {
+		return err
+	}
+
+	user, err := a.repo.FindByID(stored.UserID)
+	if err != nil {
+		return auth_entity.ErrInvalidOneTimeToken
+	}
+
+	if err := a.passwords.Validate(user, newPassword); err != nil {
+		return err
+	}
+
+	if err := a.markUsed(stored); err != nil {
+		return err
+	}
+
+	hashed, err := a.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := a.now()
+	user.Password = hashed
+	user.MarkEmailVerified(now)
+	if _, err := a.repo.Update(user.ID, user); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := a.passwords.Remember(user.ID, hashed); err != nil {
+		log.Printf("Erro ao registrar histórico de senha: %v", err)
+	}
+
+	if err := a.tokens.InvalidateForUser(user.ID, auth_entity.TokenPurposePasswordReset, now); err != nil {
+		return fmt.Errorf("failed to invalidate reset tokens: %w", err)
+	}
+
+	if err := a.revocations.RevokeAllForUser(user.ID); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+
+	if err := a.refreshRepo.RevokeAllForUser(user.ID, now); err != nil {
+		return fmt.Errorf("failed to revoke user refresh tokens: %w", err)
+	}
+
+	if a.sessions != nil {
+		if err := a.sessions.EndAllForUser(user.ID, now); err != nil {
+			return fmt.Errorf("failed to end user sessions: %w", err)
+		}
+	}
+
+	if a.throttle != nil {
+		if err := a.throttle.Unlock(user.Email); err != nil {
+			log.Printf("Erro ao desbloquear login após redefinição de senha: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// SendEmailVerification mails a verification link to a user who has not
+// confirmed their address yet.
+func (a *AccountUsecase) SendEmailVerification(userID string) error {
+	user, err := a.repo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	return a.sendVerification(user)
+}
+
+// ResendEmailVerification is the unauthenticated variant for users who are
+// locked out of login until they verify. Like ForgotPassword it never
+// reveals whether the email is known.
+func (a *AccountUsecase) ResendEmailVerification(email string) error {
+	user, err := a.repo.FindByEmail(email)
+	if err != nil || user == nil {
+		return nil
+	}
+	return a.sendVerification(user)
+}
+
+func (a *AccountUsecase) VerifyEmail(token string) error {
+	stored, err := a.consume(auth_entity.TokenPurposeEmailVerification, token)
+	if err != nil {
+		return err
+	}
+
+	user, err := a.repo.FindByID(stored.UserID)
+	if err != nil {
+		return auth_entity.ErrInvalidOneTimeToken
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	user.MarkEmailVerified(a.now())
+	if _, err := a.repo.Update(user.ID, user); err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return nil
+}
+
+// RequestEmailChange mails a confirmation link to the new address. The
+// current password is required so a stolen session cannot move the
+// account, and nothing changes until the link is followed.
+func (a *AccountUsecase) RequestEmailChange(userID, password, newEmail string) error {
+	newEmail = strings.TrimSpace(newEmail)
+
+	user, err := a.repo.FindByID(userID)
+	if err != nil {
+		return port_user_repository.ErrUserNotFound
+	}
+
+	matches, err := a.passwordHasher.HashComparer(password, user.Password)
+	if err != nil || !matches {
+		return auth_entity.ErrInvalidCredentials
+	}
+
+	errs := &user_entity.ValidationError{}
+	if !strings.Contains(newEmail, "@") {
+		errs.Add("email", "email", "email is invalid")
+	} else if strings.EqualFold(newEmail, user.Email) {
+		errs.Add("email", "unchanged", "email is already in use by this account")
+	}
+	if errs.HasErrors() {
+		return errs
+	}
+
+	if err := a.ensureEmailAvailable(user.ID, newEmail); err != nil {
+		return err
+	}
+
+	token, err := a.issue(user.ID, auth_entity.TokenPurposeEmailChange, a.verificationExpiresIn, newEmail)
+	if err != nil {
+		return err
+	}
+
+	link := a.link("/confirm-email-change", token)
+	a.deliver(func() {
+		if err := a.notifier.SendEmailChangeConfirmation(user.Name, newEmail, link); err != nil {
+			log.Printf("Erro ao enviar confirmação de troca de e-mail: %v", err)
+		}
+	})
+	return nil
+}
+
+// ConfirmEmailChange moves the user to the address the link was mailed to.
+// The address is checked again because another account may have taken it
+// since the link was sent.
+func (a *AccountUsecase) ConfirmEmailChange(token string) error {
+	stored, err := a.find(auth_entity.TokenPurposeEmailChange, token)
+	if err != nil {
+		return err
+	}
+
+	user, err := a.repo.FindByID(stored.UserID)
+	if err != nil {
+		return auth_entity.ErrInvalidOneTimeToken
+	}
+
+	if err := a.ensureEmailAvailable(user.ID, stored.NewEmail); err != nil {
+		return err
+	}
+
+	if err := a.markUsed(stored); err != nil {
+		return err
+	}
+
+	user.ChangeEmail(stored.NewEmail, a.now())
+	if _, err := a.repo.Update(user.ID, user); err != nil {
+		return fmt.Errorf("failed to change email: %w", err)
+	}
+
+	return nil
+}
+
+func (a *AccountUsecase) ensureEmailAvailable(userID, email string) error {
+	taken, err := a.repo.FindByEmail(email)
+	if err == nil && taken != nil && taken.ID != userID {
+		return port_user_repository.ErrUserAlreadyExists
+	}
+	return nil
+}
+
+func (a *AccountUsecase) sendVerification(user *user_entity.User) error {
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	token, err := a.issue(user.ID, auth_entity.TokenPurposeEmailVerification, a.verificationExpiresIn, "")
+	if err != nil {
+		return err
+	}
+
+	link := a.link("/verify-email", token)
+	a.deliver(func() {
+		if err := a.notifier.SendEmailVerification(user.Name, user.Email, link); err != nil {
+			log.Printf("Erro ao enviar e-mail de verificação: %v", err)
+		}
+	})
+	return nil
+}
+
+// issue replaces any outstanding token of the purpose with a fresh one.
+// newEmail is only set for email changes.
+func (a *AccountUsecase) issue(userID string, purpose auth_entity.TokenPurpose, expiresIn time.Duration, newEmail string) (string, error) {
+	now := a.now()
+	if err := a.tokens.InvalidateForUser(userID, purpose, now); err != nil {
+		return "", fmt.Errorf("failed to invalidate previous tokens: %w", err)
+	}
+
+	token, hash, err := a.tokenGenerator.Generate()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	stored := auth_entity.NewOneTimeToken(uuid.New().String(), userID, purpose, hash, now.Add(expiresIn))
+	stored.NewEmail = newEmail
+	if _, err := a.tokens.Save(stored); err != nil {
+		return "", fmt.Errorf("failed to save token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (a *AccountUsecase) consume(purpose auth_entity.TokenPurpose, token string) (*auth_entity.OneTimeToken, error) {
+	stored, err := a.find(purpose, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.markUsed(stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// find returns the token if it can still be used, without using it.
+func (a *AccountUsecase) find(purpose auth_entity.TokenPurpose, token string) (*auth_entity.OneTimeToken, error) {
+	if token == "" {
+		return nil, auth_entity.ErrInvalidOneTimeToken
+	}
+
+	stored, err := a.tokens.FindByTokenHash(purpose, a.tokenGenerator.Hash(token))
+	if err != nil {
+		return nil, auth_entity.ErrInvalidOneTimeToken
+	}
+
+	if stored.IsUsed() || stored.IsExpired(a.now()) {
+		return nil, auth_entity.ErrInvalidOneTimeToken
+	}
+
+	return stored, nil
+}
+
+func (a *AccountUsecase) markUsed(stored *auth_entity.OneTimeToken) error {
+	marked, err := a.tokens.MarkUsed(stored.ID, a.now())
+	if err != nil {
+		return fmt.Errorf("failed to consume token: %w", err)
+	}
+	if !marked {
+		return auth_entity.ErrInvalidOneTimeToken
+	}
+	return nil
+}
+
+func (a *AccountUsecase) link(path, token string) string {
+	return a.publicURL + path + "?token=" + url.QueryEscape(token)
+}
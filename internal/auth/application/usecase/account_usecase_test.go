@@ -0,0 +1,573 @@
+package auth_usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type MockOneTimeTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockOneTimeTokenRepository) Save(t *auth_entity.OneTimeToken) (*auth_entity.OneTimeToken, error) {
+	args := m.Called(t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.OneTimeToken), args.Error(1)
+}
+
+func (m *MockOneTimeTokenRepository) FindByTokenHash(purpose auth_entity.TokenPurpose, hash string) (*auth_entity.OneTimeToken, error) {
+	args := m.Called(purpose, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.OneTimeToken), args.Error(1)
+}
+
+func (m *MockOneTimeTokenRepository) MarkUsed(id string, usedAt time.Time) (bool, error) {
+	args := m.Called(id, usedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOneTimeTokenRepository) InvalidateForUser(userID string, purpose auth_entity.TokenPurpose, at time.Time) error {
+	args := m.Called(userID, purpose, at)
+	return args.Error(0)
+}
+
+type MockEmailNotifier struct {
+	mock.Mock
+}
+
+func (m *MockEmailNotifier) SendWelcomeEmail(name, email string) error {
+	return m.Called(name, email).Error(0)
+}
+
+func (m *MockEmailNotifier) SendPasswordResetEmail(name, email, link string) error {
+	return m.Called(name, email, link).Error(0)
+}
+
+func (m *MockEmailNotifier) SendEmailVerification(name, email, link string) error {
+	return m.Called(name, email, link).Error(0)
+}
+
+func (m *MockEmailNotifier) SendEmailChangeConfirmation(name, email, link string) error {
+	return m.Called(name, email, link).Error(0)
+}
+
+type MockPasswordPolicy struct {
+	mock.Mock
+}
+
+func (m *MockPasswordPolicy) Validate(user *userEntity.User, password string) error {
+	return m.Called(user, password).Error(0)
+}
+
+func (m *MockPasswordPolicy) Remember(userID, hash string) error {
+	return m.Called(userID, hash).Error(0)
+}
+
+var accountNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestAccountUsecase_ForgotPassword_SendsResetLink(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPasswords := new(MockPasswordPolicy)
+	mockTokens := new(MockOneTimeTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockNotifier := new(MockEmailNotifier)
+	mockRevocations := new(MockRevocationStore)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	mockThrottle := new(MockLoginThrottle)
+	usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+		WithAccountSessions(mockSessions),
+		WithAccountLoginThrottle(mockThrottle),
+	)
+	usecase.now = func() time.Time { return accountNow }
+	usecase.deliver = func(send func()) { send() }
+
+	user := &userEntity.User{ID: "user-1", Name: "Ana", Email: "ana@example.com"}
+	mockRepo.On("FindByEmail", "ana@example.com").Return(user, nil)
+	mockTokens.On("InvalidateForUser", "user-1", auth_entity.TokenPurposePasswordReset, accountNow).Return(nil)
+	mockGenerator.On("Generate").Return("reset+token", "reset-hash", nil)
+	mockTokens.On("Save", mock.MatchedBy(func(t *auth_entity.OneTimeToken) bool {
+		return t.UserID == "user-1" &&
+			t.Purpose == auth_entity.TokenPurposePasswordReset &&
+			t.TokenHash == "reset-hash" &&
+			t.ExpiresAt.Equal(accountNow.Add(30*time.Minute))
+	})).Return(&auth_entity.OneTimeToken{}, nil)
+	mockNotifier.On("SendPasswordResetEmail", "Ana", "ana@example.com", "https://app.example.com/reset-password?token=reset%2Btoken").Return(nil)
+
+	err := usecase.ForgotPassword("ana@example.com")
+
+	assert.NoError(t, err)
+	mockTokens.AssertExpectations(t)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestAccountUsecase_ForgotPassword_UnknownEmailIsSilent(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPasswords := new(MockPasswordPolicy)
+	mockTokens := new(MockOneTimeTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockNotifier := new(MockEmailNotifier)
+	mockRevocations := new(MockRevocationStore)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	mockThrottle := new(MockLoginThrottle)
+	usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+		WithAccountSessions(mockSessions),
+		WithAccountLoginThrottle(mockThrottle),
+	)
+	usecase.now = func() time.Time { return accountNow }
+	usecase.deliver = func(send func()) { send() }
+
+	mockRepo.On("FindByEmail", "ghost@example.com").Return(nil, errors.New("not found"))
+
+	err := usecase.ForgotPassword("ghost@example.com")
+
+	assert.NoError(t, err)
+	mockTokens.AssertNotCalled(t, "Save", mock.Anything)
+	mockNotifier.AssertNotCalled(t, "SendPasswordResetEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccountUsecase_ResetPassword_UpdatesPasswordAndRevokesSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPasswords := new(MockPasswordPolicy)
+	mockTokens := new(MockOneTimeTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockNotifier := new(MockEmailNotifier)
+	mockRevocations := new(MockRevocationStore)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	mockThrottle := new(MockLoginThrottle)
+	usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+		WithAccountSessions(mockSessions),
+		WithAccountLoginThrottle(mockThrottle),
+	)
+	usecase.now = func() time.Time { return accountNow }
+	usecase.deliver = func(send func()) { send() }
+
+	stored := &auth_entity.OneTimeToken{ID: "t-1", UserID: "user-1", Purpose: auth_entity.TokenPurposePasswordReset, ExpiresAt: accountNow.Add(time.Minute)}
+	user := &userEntity.User{ID: "user-1", Email: "ana@example.com", Password: "old-hash"}
+
+	mockGenerator.On("Hash", "reset-token").Return("reset-hash")
+	mockTokens.On("FindByTokenHash", auth_entity.TokenPurposePasswordReset, "reset-hash").Return(stored, nil)
+	mockTokens.On("MarkUsed", "t-1", accountNow).Return(true, nil)
+	mockRepo.On("FindByID", "user-1").Return(user, nil)
+	mockPasswords.On("Validate", user, "newPassword123").Return(nil)
+	mockBcrypt.On("Hash", "newPassword123").Return("new-hash", nil)
+	mockRepo.On("Update", "user-1", mock.MatchedBy(func(u *userEntity.User) bool {
+		return u.Password == "new-hash" && u.IsEmailVerified()
+	})).Return(user, nil)
+	mockPasswords.On("Remember", "user-1", "new-hash").Return(nil)
+	mockTokens.On("InvalidateForUser", "user-1", auth_entity.TokenPurposePasswordReset, accountNow).Return(nil)
+	mockRevocations.On("RevokeAllForUser", "user-1").Return(nil)
+	mockRefreshRepo.On("RevokeAllForUser", "user-1", accountNow).Return(nil)
+	mockSessions.On("EndAllForUser", "user-1", accountNow).Return(nil)
+	mockThrottle.On("Unlock", "ana@example.com").Return(nil)
+
+	err := usecase.ResetPassword("reset-token", "newPassword123")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockPasswords.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
+	mockThrottle.AssertExpectations(t)
+}
+
+func TestAccountUsecase_ResetPassword_RejectsInvalidTokens(t *testing.T) {
+	used := accountNow.Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		stored *auth_entity.OneTimeToken
+		err    error
+		marked bool
+	}{
+		{name: "not found", err: auth_entity.ErrOneTimeTokenNotFound},
+		{name: "expired", stored: &auth_entity.OneTimeToken{ID: "t-1", ExpiresAt: accountNow}},
+		{name: "already used", stored: &auth_entity.OneTimeToken{ID: "t-1", ExpiresAt: accountNow.Add(time.Minute), UsedAt: &used}},
+		{name: "used concurrently", stored: &auth_entity.OneTimeToken{ID: "t-1", ExpiresAt: accountNow.Add(time.Minute)}, marked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockBcrypt := new(MockBcrypt)
+			mockPasswords := new(MockPasswordPolicy)
+			mockTokens := new(MockOneTimeTokenRepository)
+			mockGenerator := new(MockSecureTokenGenerator)
+			mockNotifier := new(MockEmailNotifier)
+			mockRevocations := new(MockRevocationStore)
+			mockRefreshRepo := new(MockRefreshTokenRepository)
+			mockSessions := new(MockSessionRepository)
+			mockThrottle := new(MockLoginThrottle)
+			usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+				WithAccountSessions(mockSessions),
+				WithAccountLoginThrottle(mockThrottle),
+			)
+			usecase.now = func() time.Time { return accountNow }
+			usecase.deliver = func(send func()) { send() }
+
+			mockGenerator.On("Hash", "reset-token").Return("reset-hash")
+			if tt.stored != nil {
+				mockTokens.On("FindByTokenHash", auth_entity.TokenPurposePasswordReset, "reset-hash").Return(tt.stored, nil)
+			} else {
+				mockTokens.On("FindByTokenHash", auth_entity.TokenPurposePasswordReset, "reset-hash").Return(nil, tt.err)
+			}
+			mockRepo.On("FindByID", mock.Anything).Return(&userEntity.User{ID: "user-1"}, nil)
+			mockPasswords.On("Validate", mock.Anything, "newPassword123").Return(nil)
+			mockTokens.On("MarkUsed", "t-1", accountNow).Return(tt.marked, nil)
+
+			err := usecase.ResetPassword("reset-token", "newPassword123")
+
+			assert.ErrorIs(t, err, auth_entity.ErrInvalidOneTimeToken)
+			mockBcrypt.AssertNotCalled(t, "Hash", mock.Anything)
+		})
+	}
+}
+
+func TestAccountUsecase_ResetPassword_PolicyViolationKeepsToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPasswords := new(MockPasswordPolicy)
+	mockTokens := new(MockOneTimeTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockNotifier := new(MockEmailNotifier)
+	mockRevocations := new(MockRevocationStore)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	mockThrottle := new(MockLoginThrottle)
+	usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+		WithAccountSessions(mockSessions),
+		WithAccountLoginThrottle(mockThrottle),
+	)
+	usecase.now = func() time.Time { return accountNow }
+	usecase.deliver = func(send func()) { send() }
+
+	stored := &auth_entity.OneTimeToken{ID: "t-1", UserID: "user-1", Purpose: auth_entity.TokenPurposePasswordReset, ExpiresAt: accountNow.Add(time.Minute)}
+	user := &userEntity.User{ID: "user-1"}
+	violation := &userEntity.ValidationError{}
+	violation.Add("password", "min_length", "password must be at least 8 characters")
+
+	mockGenerator.On("Hash", "reset-token").Return("reset-hash")
+	mockTokens.On("FindByTokenHash", auth_entity.TokenPurposePasswordReset, "reset-hash").Return(stored, nil)
+	mockRepo.On("FindByID", "user-1").Return(user, nil)
+	mockPasswords.On("Validate", user, "short").Return(violation)
+
+	err := usecase.ResetPassword("reset-token", "short")
+
+	var validation *userEntity.ValidationError
+	assert.ErrorAs(t, err, &validation)
+	mockTokens.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+	mockBcrypt.AssertNotCalled(t, "Hash", mock.Anything)
+}
+
+func TestAccountUsecase_SendEmailVerification(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPasswords := new(MockPasswordPolicy)
+	mockTokens := new(MockOneTimeTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockNotifier := new(MockEmailNotifier)
+	mockRevocations := new(MockRevocationStore)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	mockThrottle := new(MockLoginThrottle)
+	usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+		WithAccountSessions(mockSessions),
+		WithAccountLoginThrottle(mockThrottle),
+	)
+	usecase.now = func() time.Time { return accountNow }
+	usecase.deliver = func(send func()) { send() }
+
+	user := &userEntity.User{ID: "user-1", Name: "Ana", Email: "ana@example.com"}
+	mockRepo.On("FindByID", "user-1").Return(user, nil)
+	mockTokens.On("InvalidateForUser", "user-1", auth_entity.TokenPurposeEmailVerification, accountNow).Return(nil)
+	mockGenerator.On("Generate").Return("verify-token", "verify-hash", nil)
+	mockTokens.On("Save", mock.MatchedBy(func(t *auth_entity.OneTimeToken) bool {
+		return t.Purpose == auth_entity.TokenPurposeEmailVerification && t.ExpiresAt.Equal(accountNow.Add(24*time.Hour))
+	})).Return(&auth_entity.OneTimeToken{}, nil)
+	mockNotifier.On("SendEmailVerification", "Ana", "ana@example.com", "https://app.example.com/verify-email?token=verify-token").Return(nil)
+
+	err := usecase.SendEmailVerification("user-1")
+
+	assert.NoError(t, err)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestAccountUsecase_ResendEmailVerification_SkipsVerifiedUsers(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPasswords := new(MockPasswordPolicy)
+	mockTokens := new(MockOneTimeTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockNotifier := new(MockEmailNotifier)
+	mockRevocations := new(MockRevocationStore)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	mockThrottle := new(MockLoginThrottle)
+	usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+		WithAccountSessions(mockSessions),
+		WithAccountLoginThrottle(mockThrottle),
+	)
+	usecase.now = func() time.Time { return accountNow }
+	usecase.deliver = func(send func()) { send() }
+
+	verifiedAt := accountNow
+	mockRepo.On("FindByEmail", "ana@example.com").Return(&userEntity.User{ID: "user-1", EmailVerifiedAt: &verifiedAt}, nil)
+
+	err := usecase.ResendEmailVerification("ana@example.com")
+
+	assert.NoError(t, err)
+	mockGenerator.AssertNotCalled(t, "Generate")
+	mockNotifier.AssertNotCalled(t, "SendEmailVerification", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccountUsecase_VerifyEmail(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPasswords := new(MockPasswordPolicy)
+	mockTokens := new(MockOneTimeTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockNotifier := new(MockEmailNotifier)
+	mockRevocations := new(MockRevocationStore)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	mockThrottle := new(MockLoginThrottle)
+	usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+		WithAccountSessions(mockSessions),
+		WithAccountLoginThrottle(mockThrottle),
+	)
+	usecase.now = func() time.Time { return accountNow }
+	usecase.deliver = func(send func()) { send() }
+
+	stored := &auth_entity.OneTimeToken{ID: "t-1", UserID: "user-1", Purpose: auth_entity.TokenPurposeEmailVerification, ExpiresAt: accountNow.Add(time.Hour)}
+	user := &userEntity.User{ID: "user-1"}
+
+	mockGenerator.On("Hash", "verify-token").Return("verify-hash")
+	mockTokens.On("FindByTokenHash", auth_entity.TokenPurposeEmailVerification, "verify-hash").Return(stored, nil)
+	mockTokens.On("MarkUsed", "t-1", accountNow).Return(true, nil)
+	mockRepo.On("FindByID", "user-1").Return(user, nil)
+	mockRepo.On("Update", "user-1", mock.MatchedBy(func(u *userEntity.User) bool {
+		return u.EmailVerifiedAt != nil && u.EmailVerifiedAt.Equal(accountNow)
+	})).Return(user, nil)
+
+	err := usecase.VerifyEmail("verify-token")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAccountUsecase_VerifyEmail_EmptyToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPasswords := new(MockPasswordPolicy)
+	mockTokens := new(MockOneTimeTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockNotifier := new(MockEmailNotifier)
+	mockRevocations := new(MockRevocationStore)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	mockThrottle := new(MockLoginThrottle)
+	usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+		WithAccountSessions(mockSessions),
+		WithAccountLoginThrottle(mockThrottle),
+	)
+	usecase.now = func() time.Time { return accountNow }
+	usecase.deliver = func(send func()) { send() }
+
+	err := usecase.VerifyEmail("")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidOneTimeToken)
+}
+
+func TestAccountUsecase_RequestEmailChange_MailsNewAddress(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPasswords := new(MockPasswordPolicy)
+	mockTokens := new(MockOneTimeTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockNotifier := new(MockEmailNotifier)
+	mockRevocations := new(MockRevocationStore)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	mockThrottle := new(MockLoginThrottle)
+	usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+		WithAccountSessions(mockSessions),
+		WithAccountLoginThrottle(mockThrottle),
+	)
+	usecase.now = func() time.Time { return accountNow }
+	usecase.deliver = func(send func()) { send() }
+
+	user := &userEntity.User{ID: "user-1", Name: "Ana", Email: "ana@example.com", Password: "hash"}
+	mockRepo.On("FindByID", "user-1").Return(user, nil)
+	mockBcrypt.On("HashComparer", "secret", "hash").Return(true, nil)
+	mockRepo.On("FindByEmail", "ana@new.example.com").Return(nil, port_user_repository.ErrUserNotFound)
+	mockTokens.On("InvalidateForUser", "user-1", auth_entity.TokenPurposeEmailChange, accountNow).Return(nil)
+	mockGenerator.On("Generate").Return("change-token", "change-hash", nil)
+	mockTokens.On("Save", mock.MatchedBy(func(t *auth_entity.OneTimeToken) bool {
+		return t.Purpose == auth_entity.TokenPurposeEmailChange && t.NewEmail == "ana@new.example.com"
+	})).Return(&auth_entity.OneTimeToken{}, nil)
+	mockNotifier.On("SendEmailChangeConfirmation", "Ana", "ana@new.example.com", "https://app.example.com/confirm-email-change?token=change-token").Return(nil)
+
+	err := usecase.RequestEmailChange("user-1", "secret", " ana@new.example.com ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ana@example.com", user.Email)
+	mockTokens.AssertExpectations(t)
+	mockNotifier.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestAccountUsecase_RequestEmailChange_Rejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		email    string
+		taken    *userEntity.User
+		err      error
+	}{
+		{name: "wrong password", password: "wrong", email: "ana@new.example.com", err: auth_entity.ErrInvalidCredentials},
+		{name: "taken address", password: "secret", email: "bia@example.com", taken: &userEntity.User{ID: "user-2"}, err: port_user_repository.ErrUserAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockBcrypt := new(MockBcrypt)
+			mockPasswords := new(MockPasswordPolicy)
+			mockTokens := new(MockOneTimeTokenRepository)
+			mockGenerator := new(MockSecureTokenGenerator)
+			mockNotifier := new(MockEmailNotifier)
+			mockRevocations := new(MockRevocationStore)
+			mockRefreshRepo := new(MockRefreshTokenRepository)
+			mockSessions := new(MockSessionRepository)
+			mockThrottle := new(MockLoginThrottle)
+			usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+				WithAccountSessions(mockSessions),
+				WithAccountLoginThrottle(mockThrottle),
+			)
+			usecase.now = func() time.Time { return accountNow }
+			usecase.deliver = func(send func()) { send() }
+
+			mockRepo.On("FindByID", "user-1").Return(&userEntity.User{ID: "user-1", Email: "ana@example.com", Password: "hash"}, nil)
+			mockBcrypt.On("HashComparer", tt.password, "hash").Return(tt.password == "secret", nil)
+			mockRepo.On("FindByEmail", tt.email).Return(tt.taken, nil)
+
+			err := usecase.RequestEmailChange("user-1", tt.password, tt.email)
+
+			assert.ErrorIs(t, err, tt.err)
+			mockTokens.AssertNotCalled(t, "Save", mock.Anything)
+		})
+	}
+
+	t.Run("same address", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockBcrypt := new(MockBcrypt)
+		mockPasswords := new(MockPasswordPolicy)
+		mockTokens := new(MockOneTimeTokenRepository)
+		mockGenerator := new(MockSecureTokenGenerator)
+		mockNotifier := new(MockEmailNotifier)
+		mockRevocations := new(MockRevocationStore)
+		mockRefreshRepo := new(MockRefreshTokenRepository)
+		mockSessions := new(MockSessionRepository)
+		mockThrottle := new(MockLoginThrottle)
+		usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+			WithAccountSessions(mockSessions),
+			WithAccountLoginThrottle(mockThrottle),
+		)
+		usecase.now = func() time.Time { return accountNow }
+		usecase.deliver = func(send func()) { send() }
+
+		mockRepo.On("FindByID", "user-1").Return(&userEntity.User{ID: "user-1", Email: "ana@example.com", Password: "hash"}, nil)
+		mockBcrypt.On("HashComparer", "secret", "hash").Return(true, nil)
+
+		err := usecase.RequestEmailChange("user-1", "secret", "ANA@example.com")
+
+		var validation *userEntity.ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+}
+
+func TestAccountUsecase_ConfirmEmailChange(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPasswords := new(MockPasswordPolicy)
+	mockTokens := new(MockOneTimeTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockNotifier := new(MockEmailNotifier)
+	mockRevocations := new(MockRevocationStore)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	mockThrottle := new(MockLoginThrottle)
+	usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+		WithAccountSessions(mockSessions),
+		WithAccountLoginThrottle(mockThrottle),
+	)
+	usecase.now = func() time.Time { return accountNow }
+	usecase.deliver = func(send func()) { send() }
+
+	stored := &auth_entity.OneTimeToken{ID: "t-1", UserID: "user-1", Purpose: auth_entity.TokenPurposeEmailChange, NewEmail: "ana@new.example.com", ExpiresAt: accountNow.Add(time.Hour)}
+	user := &userEntity.User{ID: "user-1", Email: "ana@example.com"}
+
+	mockGenerator.On("Hash", "change-token").Return("change-hash")
+	mockTokens.On("FindByTokenHash", auth_entity.TokenPurposeEmailChange, "change-hash").Return(stored, nil)
+	mockRepo.On("FindByID", "user-1").Return(user, nil)
+	mockRepo.On("FindByEmail", "ana@new.example.com").Return(nil, port_user_repository.ErrUserNotFound)
+	mockTokens.On("MarkUsed", "t-1", accountNow).Return(true, nil)
+	mockRepo.On("Update", "user-1", mock.MatchedBy(func(u *userEntity.User) bool {
+		return u.Email == "ana@new.example.com" && u.EmailVerifiedAt != nil && u.EmailVerifiedAt.Equal(accountNow)
+	})).Return(user, nil)
+
+	err := usecase.ConfirmEmailChange("change-token")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockTokens.AssertExpectations(t)
+}
+
+func TestAccountUsecase_ConfirmEmailChange_AddressTakenMeanwhile(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPasswords := new(MockPasswordPolicy)
+	mockTokens := new(MockOneTimeTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockNotifier := new(MockEmailNotifier)
+	mockRevocations := new(MockRevocationStore)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockSessions := new(MockSessionRepository)
+	mockThrottle := new(MockLoginThrottle)
+	usecase := NewAccountUsecase(mockRepo, mockBcrypt, mockPasswords, mockTokens, mockGenerator, mockNotifier, mockRevocations, mockRefreshRepo, "https://app.example.com/", 30*time.Minute, 24*time.Hour,
+		WithAccountSessions(mockSessions),
+		WithAccountLoginThrottle(mockThrottle),
+	)
+	usecase.now = func() time.Time { return accountNow }
+	usecase.deliver = func(send func()) { send() }
+
+	stored := &auth_entity.OneTimeToken{ID: "t-1", UserID: "user-1", Purpose: auth_entity.TokenPurposeEmailChange, NewEmail: "ana@new.example.com", ExpiresAt: accountNow.Add(time.Hour)}
+
+	mockGenerator.On("Hash", "change-token").Return("change-hash")
+	mockTokens.On("FindByTokenHash", auth_entity.TokenPurposeEmailChange, "change-hash").Return(stored, nil)
+	mockRepo.On("FindByID", "user-1").Return(&userEntity.User{ID: "user-1", Email: "ana@example.com"}, nil)
+	mockRepo.On("FindByEmail", "ana@new.example.com").Return(&userEntity.User{ID: "user-2"}, nil)
+
+	err := usecase.ConfirmEmailChange("change-token")
+
+	assert.ErrorIs(t, err, port_user_repository.ErrUserAlreadyExists)
+	mockTokens.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,170 @@
+package auth_usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+// apiKeyLastUsedResolution bounds how often a busy key writes its last use.
+const apiKeyLastUsedResolution = time.Minute
+
+// APIKeyUsecase issues and checks personal API keys. A key acts with its
+// owner's permissions as they are at request time, narrowed to its scopes,
+// so revoking a permission from the owner also takes it from their keys.
+type APIKeyUsecase struct {
+	keys           port_auth_repository.APIKeyRepository
+	users          port_user_repository.UserRepository
+	permissions    port_permission_service.PermissionService
+	tokenGenerator port_auth_cryptography.SecureTokenGenerator
+	now            func() time.Time
+}
+
+var _ port_auth_usecase.APIKeyUsecase = &APIKeyUsecase{}
+
+func NewAPIKeyUsecase(
+	keys port_auth_repository.APIKeyRepository,
+	users port_user_repository.UserRepository,
+	permissions port_permission_service.PermissionService,
+	tokenGenerator port_auth_cryptography.SecureTokenGenerator,
+) *APIKeyUsecase {
+	return &APIKeyUsecase{
+		keys:           keys,
+		users:          users,
+		permissions:    permissions,
+		tokenGenerator: tokenGenerator,
+		now:            time.Now,
+	}
+}
+
+// Create issues a key for userID. Every scope must be a "module:action" pair
+// the user currently holds, so a key can never reach further than its owner.
+func (a *APIKeyUsecase) Create(userID, name string, scopes []string, expiresAt *time.Time) (*auth_entity.APIKeyIssued, error) {
+	now := a.now()
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", auth_entity.ErrInvalidAPIKeyInput)
+	}
+	scopes, err := auth_entity.ParseScope(strings.Join(scopes, " "))
+	if err != nil || len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: scopes must be a non-empty list of module:action pairs", auth_entity.ErrInvalidAPIKeyInput)
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", auth_entity.ErrInvalidAPIKeyInput)
+	}
+
+	effective, err := a.permissions.Refresh(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+	held := effective.Grants()
+	for _, scope := range scopes {
+		module, action, _ := strings.Cut(scope, ":")
+		if !permission_entity.Evaluate(held, []string{module}, []string{action}).Allowed {
+			return nil, fmt.Errorf("%w: %s", auth_entity.ErrAPIKeyScopeNotHeld, scope)
+		}
+	}
+
+	secret, _, err := a.tokenGenerator.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key := auth_entity.APIKeyPrefix + secret
+
+	saved, err := a.keys.Save(&auth_entity.APIKey{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    auth_entity.APIKeyDisplayPrefix(key),
+		KeyHash:   a.tokenGenerator.Hash(key),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save api key: %w", err)
+	}
+
+	return &auth_entity.APIKeyIssued{APIKey: saved, Key: key}, nil
+}
+
+func (a *APIKeyUsecase) List(userID string) ([]*auth_entity.APIKey, error) {
+	keys, err := a.keys.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find api keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (a *APIKeyUsecase) Revoke(userID, id string) error {
+	return a.keys.Revoke(id, userID, a.now())
+}
+
+func (a *APIKeyUsecase) RevokeAllForUser(userID string) error {
+	if err := a.keys.RevokeAllForUser(userID, a.now()); err != nil {
+		return fmt.Errorf("failed to revoke api keys: %w", err)
+	}
+	return nil
+}
+
+func (a *APIKeyUsecase) Authenticate(key string) (*auth_entity.APIKeyPrincipal, error) {
+	now := a.now()
+
+	if !auth_entity.IsAPIKey(key) {
+		return nil, auth_entity.ErrInvalidAPIKey
+	}
+
+	apiKey, err := a.keys.FindByKeyHash(a.tokenGenerator.Hash(key))
+	if err != nil {
+		if errors.Is(err, auth_entity.ErrAPIKeyNotFound) {
+			return nil, auth_entity.ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("failed to find api key: %w", err)
+	}
+	if !apiKey.IsActive(now) {
+		return nil, auth_entity.ErrInvalidAPIKey
+	}
+
+	user, err := a.users.FindByID(apiKey.UserID)
+	if err != nil {
+		if errors.Is(err, port_user_repository.ErrUserNotFound) {
+			return nil, auth_entity.ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	effective, err := a.permissions.Resolve(apiKey.UserID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+
+	grants, modules, actions := scopedClaims(effective, apiKey.Scopes)
+	principal := &auth_entity.APIKeyPrincipal{
+		KeyID:   apiKey.ID,
+		UserID:  apiKey.UserID,
+		Email:   user.Email,
+		Modules: modules,
+		Actions: actions,
+		Grants:  grants,
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedResolution {
+		if err := a.keys.TouchLastUsed(apiKey.ID, now); err != nil {
+			log.Printf("Failed to record api key use %s: %v", apiKey.ID, err)
+		}
+	}
+
+	return principal, nil
+}
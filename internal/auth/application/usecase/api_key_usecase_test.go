@@ -0,0 +1,211 @@
+package auth_usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Save(k *auth_entity.APIKey) (*auth_entity.APIKey, error) {
+	args := m.Called(k)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByKeyHash(hash string) (*auth_entity.APIKey, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByUser(userID string) ([]*auth_entity.APIKey, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*auth_entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(id, userID string, revokedAt time.Time) error {
+	return m.Called(id, userID, revokedAt).Error(0)
+}
+
+func (m *MockAPIKeyRepository) RevokeAllForUser(userID string, revokedAt time.Time) error {
+	return m.Called(userID, revokedAt).Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(id string, usedAt time.Time) error {
+	return m.Called(id, usedAt).Error(0)
+}
+
+var apiKeyNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newAPIKeyTestUsecase() (*APIKeyUsecase, *MockAPIKeyRepository, *MockUserRepository, *MockPermissionService, *MockSecureTokenGenerator) {
+	keys := new(MockAPIKeyRepository)
+	users := new(MockUserRepository)
+	permissions := new(MockPermissionService)
+	generator := new(MockSecureTokenGenerator)
+
+	usecase := NewAPIKeyUsecase(keys, users, permissions, generator)
+	usecase.now = func() time.Time { return apiKeyNow }
+
+	return usecase, keys, users, permissions, generator
+}
+
+func TestAPIKeyUsecase_Create(t *testing.T) {
+	usecase, keys, _, permissions, generator := newAPIKeyTestUsecase()
+	expiresAt := apiKeyNow.Add(24 * time.Hour)
+
+	permissions.On("Refresh", "user-1").Return(userPermissions("user-1"), nil)
+	generator.On("Generate").Return("abcdefghijklmnop", "unused", nil)
+	generator.On("Hash", "sek_abcdefghijklmnop").Return("key-hash")
+	keys.On("Save", mock.MatchedBy(func(k *auth_entity.APIKey) bool {
+		return k.UserID == "user-1" && k.Name == "ci" && k.KeyHash == "key-hash" && k.Prefix == "sek_abcdefgh" &&
+			assert.ObjectsAreEqual([]string{"roles:read", "users:read"}, k.Scopes) && k.ExpiresAt.Equal(expiresAt)
+	})).Return(&auth_entity.APIKey{ID: "key-1", UserID: "user-1"}, nil)
+
+	issued, err := usecase.Create("user-1", " ci ", []string{"users:read", "roles:read"}, &expiresAt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sek_abcdefghijklmnop", issued.Key)
+	assert.Equal(t, "key-1", issued.APIKey.ID)
+	keys.AssertExpectations(t)
+}
+
+func TestAPIKeyUsecase_Create_RejectsScopesTheOwnerDoesNotHold(t *testing.T) {
+	usecase, keys, _, permissions, _ := newAPIKeyTestUsecase()
+
+	permissions.On("Refresh", "user-1").Return(userPermissions("user-1"), nil)
+
+	_, err := usecase.Create("user-1", "ci", []string{"users:read", "roles:update"}, nil)
+
+	assert.ErrorIs(t, err, auth_entity.ErrAPIKeyScopeNotHeld)
+	assert.Contains(t, err.Error(), "roles:update")
+	keys.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestAPIKeyUsecase_Create_Validation(t *testing.T) {
+	past := apiKeyNow.Add(-time.Minute)
+	cases := map[string]struct {
+		name      string
+		scopes    []string
+		expiresAt *time.Time
+	}{
+		"missing name":   {name: " ", scopes: []string{"users:read"}},
+		"no scopes":      {name: "ci"},
+		"invalid scope":  {name: "ci", scopes: []string{"users"}},
+		"expiry in past": {name: "ci", scopes: []string{"users:read"}, expiresAt: &past},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			usecase, keys, _, _, _ := newAPIKeyTestUsecase()
+
+			_, err := usecase.Create("user-1", tc.name, tc.scopes, tc.expiresAt)
+
+			assert.ErrorIs(t, err, auth_entity.ErrInvalidAPIKeyInput)
+			keys.AssertNotCalled(t, "Save", mock.Anything)
+		})
+	}
+}
+
+func TestAPIKeyUsecase_Authenticate(t *testing.T) {
+	usecase, keys, users, permissions, generator := newAPIKeyTestUsecase()
+
+	generator.On("Hash", "sek_secret").Return("key-hash")
+	keys.On("FindByKeyHash", "key-hash").Return(&auth_entity.APIKey{
+		ID: "key-1", UserID: "user-1", Scopes: []string{"users:read", "roles:read"},
+	}, nil)
+	users.On("FindByID", "user-1").Return(&userEntity.User{ID: "user-1", Email: "ci@example.com"}, nil)
+	permissions.On("Resolve", "user-1", int64(0)).Return(userPermissions("user-1"), nil)
+	keys.On("TouchLastUsed", "key-1", apiKeyNow).Return(nil)
+
+	principal, err := usecase.Authenticate("sek_secret")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", principal.UserID)
+	assert.Equal(t, "ci@example.com", principal.Email)
+	assert.Equal(t, []string{"users:read", "roles:read:restricted"}, principal.Grants)
+	assert.Equal(t, []string{"users"}, principal.Modules)
+	assert.Equal(t, []string{"read"}, principal.Actions)
+	keys.AssertExpectations(t)
+}
+
+func TestAPIKeyUsecase_Authenticate_SkipsRecentLastUsedWrite(t *testing.T) {
+	usecase, keys, users, permissions, generator := newAPIKeyTestUsecase()
+	lastUsed := apiKeyNow.Add(-10 * time.Second)
+
+	generator.On("Hash", "sek_secret").Return("key-hash")
+	keys.On("FindByKeyHash", "key-hash").Return(&auth_entity.APIKey{
+		ID: "key-1", UserID: "user-1", Scopes: []string{"users:read"}, LastUsedAt: &lastUsed,
+	}, nil)
+	users.On("FindByID", "user-1").Return(&userEntity.User{ID: "user-1"}, nil)
+	permissions.On("Resolve", "user-1", int64(0)).Return(userPermissions("user-1"), nil)
+
+	_, err := usecase.Authenticate("sek_secret")
+
+	assert.NoError(t, err)
+	keys.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything)
+}
+
+func TestAPIKeyUsecase_Authenticate_RejectsUnusableKeys(t *testing.T) {
+	expired := apiKeyNow.Add(-time.Minute)
+	cases := map[string]func(keys *MockAPIKeyRepository, users *MockUserRepository){
+		"unknown": func(keys *MockAPIKeyRepository, users *MockUserRepository) {
+			keys.On("FindByKeyHash", "key-hash").Return(nil, auth_entity.ErrAPIKeyNotFound)
+		},
+		"expired": func(keys *MockAPIKeyRepository, users *MockUserRepository) {
+			keys.On("FindByKeyHash", "key-hash").Return(&auth_entity.APIKey{ID: "key-1", UserID: "user-1", ExpiresAt: &expired}, nil)
+		},
+		"revoked": func(keys *MockAPIKeyRepository, users *MockUserRepository) {
+			keys.On("FindByKeyHash", "key-hash").Return(&auth_entity.APIKey{ID: "key-1", UserID: "user-1", RevokedAt: &expired}, nil)
+		},
+		"owner deleted": func(keys *MockAPIKeyRepository, users *MockUserRepository) {
+			keys.On("FindByKeyHash", "key-hash").Return(&auth_entity.APIKey{ID: "key-1", UserID: "user-1"}, nil)
+			users.On("FindByID", "user-1").Return(nil, port_user_repository.ErrUserNotFound)
+		},
+	}
+
+	for name, setup := range cases {
+		t.Run(name, func(t *testing.T) {
+			usecase, keys, users, _, generator := newAPIKeyTestUsecase()
+			generator.On("Hash", "sek_secret").Return("key-hash")
+			setup(keys, users)
+
+			_, err := usecase.Authenticate("sek_secret")
+
+			assert.ErrorIs(t, err, auth_entity.ErrInvalidAPIKey)
+		})
+	}
+
+	usecase, keys, _, _, _ := newAPIKeyTestUsecase()
+	_, err := usecase.Authenticate("not-a-key")
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidAPIKey)
+	keys.AssertNotCalled(t, "FindByKeyHash", mock.Anything)
+}
+
+func TestAPIKeyUsecase_Revoke(t *testing.T) {
+	usecase, keys, _, _, _ := newAPIKeyTestUsecase()
+
+	keys.On("Revoke", "key-1", "user-1", apiKeyNow).Return(nil)
+	keys.On("Revoke", "key-2", "user-1", apiKeyNow).Return(auth_entity.ErrAPIKeyNotFound)
+	keys.On("RevokeAllForUser", "user-2", apiKeyNow).Return(errors.New("db down"))
+
+	assert.NoError(t, usecase.Revoke("user-1", "key-1"))
+	assert.ErrorIs(t, usecase.Revoke("user-1", "key-2"), auth_entity.ErrAPIKeyNotFound)
+	assert.Error(t, usecase.RevokeAllForUser("user-2"))
+}
@@ -2,85 +2,537 @@ package auth_usecase
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_event "github.com/williamkoller/system-education/internal/auth/domain/event"
 	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_event "github.com/williamkoller/system-education/internal/auth/port/event"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_revocation "github.com/williamkoller/system-education/internal/auth/port/revocation"
+	port_auth_service "github.com/williamkoller/system-education/internal/auth/port/service"
 	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
-	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
 	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
 	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
 )
 
 type AuthUsecase struct {
-	repo            port_user_repository.UserRepository
-	permissionRepo  port_permission_repository.PermissionRepository
-	jwtTokenManager port_auth_cryptography.TokenManager
-	passwordHasher  port_cryptography.Bcrypt
+	repo             port_user_repository.UserRepository
+	permissions      port_permission_service.PermissionService
+	jwtTokenManager  port_auth_cryptography.TokenManager
+	passwordHasher   port_cryptography.Bcrypt
+	refreshRepo      port_auth_repository.RefreshTokenRepository
+	tokenGenerator   port_auth_cryptography.SecureTokenGenerator
+	revocations      port_auth_revocation.RevocationStore
+	event            port_auth_event.Dispatcher
+	accessExpiresIn  time.Duration
+	refreshExpiresIn time.Duration
+	permissionMode   auth_entity.PermissionMode
+	mfa              port_auth_service.MFAService
+	throttle         port_auth_service.LoginThrottle
+	sessions         port_auth_repository.SessionRepository
+	requireVerified  bool
+	now              func() time.Time
+
+	dummyHashOnce sync.Once
+	dummyHash     string
 }
 
 var _ port_auth_usecase.AuthUsecase = &AuthUsecase{}
 
-func NewAuthUsecase(
-	repo port_user_repository.UserRepository,
-	permissionRepo port_permission_repository.PermissionRepository,
-	jwtTokenManager port_auth_cryptography.TokenManager,
-	passwordHasher port_cryptography.Bcrypt,
-) *AuthUsecase {
-	return &AuthUsecase{
-		repo:            repo,
-		permissionRepo:  permissionRepo,
-		jwtTokenManager: jwtTokenManager,
-		passwordHasher:  passwordHasher,
+type Option func(*AuthUsecase)
+
+// WithLoginThrottle rate-limits failed logins per account and client address.
+func WithLoginThrottle(throttle port_auth_service.LoginThrottle) Option {
+	return func(a *AuthUsecase) {
+		a.throttle = throttle
+	}
+}
+
+// WithEmailVerification refuses logins, even with the right password, until
+// the user has confirmed their email address.
+func WithEmailVerification() Option {
+	return func(a *AuthUsecase) {
+		a.requireVerified = true
+	}
+}
+
+// WithMFA puts logins behind the second factor managed by mfa. Without it
+// Login always issues tokens after the password check and the MFA methods
+// report ErrMFANotEnrolled.
+func WithMFA(mfa port_auth_service.MFAService) Option {
+	return func(a *AuthUsecase) {
+		a.mfa = mfa
+	}
+}
+
+// WithSessions records a session per login, listed through Sessions, and
+// ties the tokens issued under it to the session through the "sid" claim.
+func WithSessions(sessions port_auth_repository.SessionRepository) Option {
+	return func(a *AuthUsecase) {
+		a.sessions = sessions
+	}
+}
+
+// AuthDependencies are what every AuthUsecase needs; optional behavior is
+// switched on through Options.
+type AuthDependencies struct {
+	Users            port_user_repository.UserRepository
+	Permissions      port_permission_service.PermissionService
+	TokenManager     port_auth_cryptography.TokenManager
+	PasswordHasher   port_cryptography.Bcrypt
+	RefreshTokens    port_auth_repository.RefreshTokenRepository
+	TokenGenerator   port_auth_cryptography.SecureTokenGenerator
+	Revocations      port_auth_revocation.RevocationStore
+	Events           port_auth_event.Dispatcher
+	AccessExpiresIn  time.Duration
+	RefreshExpiresIn time.Duration
+	PermissionMode   auth_entity.PermissionMode
+}
+
+func NewAuthUsecase(deps AuthDependencies, opts ...Option) *AuthUsecase {
+	usecase := &AuthUsecase{
+		repo:             deps.Users,
+		permissions:      deps.Permissions,
+		jwtTokenManager:  deps.TokenManager,
+		passwordHasher:   deps.PasswordHasher,
+		refreshRepo:      deps.RefreshTokens,
+		tokenGenerator:   deps.TokenGenerator,
+		revocations:      deps.Revocations,
+		event:            deps.Events,
+		accessExpiresIn:  deps.AccessExpiresIn,
+		refreshExpiresIn: deps.RefreshExpiresIn,
+		permissionMode:   deps.PermissionMode,
+		now:              time.Now,
+	}
+	for _, opt := range opts {
+		opt(usecase)
+	}
+	return usecase
+}
+
+// Login checks the password and either issues tokens or, for users behind
+// MFA, returns a challenge to be completed through VerifyMFA. Unknown emails
+// and wrong passwords fail with the same error after the same amount of
+// hashing work.
+func (a *AuthUsecase) Login(email, password string, client auth_entity.ClientInfo) (*auth_entity.LoginResult, error) {
+	if a.throttle != nil {
+		if err := a.throttle.Check(email, client.IP); err != nil {
+			if errors.Is(err, auth_entity.ErrTooManyLoginAttempts) {
+				a.event.Dispatch(auth_event.NewLoginFailedEvent(email, "", auth_event.LoginFailureThrottled, client.IP, client.UserAgent))
+			}
+			return nil, err
+		}
+	}
+
+	// Lookup errors are treated like an unknown email so the response does
+	// not depend on them.
+	user, _ := a.repo.FindByEmail(email)
+
+	if !a.checkPassword(user, password) {
+		a.event.Dispatch(auth_event.NewLoginFailedEvent(email, "", auth_event.LoginFailureInvalidCredentials, client.IP, client.UserAgent))
+		if a.throttle != nil {
+			if err := a.throttle.RecordFailure(email, client.IP); err != nil {
+				return nil, err
+			}
+		}
+		return nil, auth_entity.ErrInvalidCredentials
+	}
+
+	if a.throttle != nil {
+		if err := a.throttle.RecordSuccess(email, client.IP); err != nil {
+			return nil, err
+		}
+	}
+
+	a.upgradePasswordHash(user, password)
+
+	if a.requireVerified && !user.IsEmailVerified() {
+		a.event.Dispatch(auth_event.NewLoginFailedEvent(email, user.ID, auth_event.LoginFailureEmailNotVerified, client.IP, client.UserAgent))
+		return nil, auth_entity.ErrEmailNotVerified
+	}
+
+	return a.completeLogin(user, client)
+}
+
+// CompleteLogin finishes a login whose first factor was checked elsewhere,
+// such as by an external identity provider: MFA still applies.
+func (a *AuthUsecase) CompleteLogin(userID string, client auth_entity.ClientInfo) (*auth_entity.LoginResult, error) {
+	user, err := a.repo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	return a.completeLogin(user, client)
+}
+
+func (a *AuthUsecase) completeLogin(user *user_entity.User, client auth_entity.ClientInfo) (*auth_entity.LoginResult, error) {
+	if a.mfa != nil {
+		challenge, err := a.mfa.Challenge(user.ID, user.Email)
+		if err != nil {
+			return nil, err
+		}
+		if challenge != nil {
+			return &auth_entity.LoginResult{Challenge: challenge}, nil
+		}
+	}
+
+	tokens, err := a.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &auth_entity.LoginResult{Tokens: tokens}, nil
+}
+
+// VerifyMFA completes a login challenge with a TOTP code or a recovery code.
+func (a *AuthUsecase) VerifyMFA(challengeToken, code, recoveryCode string, client auth_entity.ClientInfo) (*auth_entity.MFAVerification, error) {
+	if a.mfa == nil {
+		return nil, auth_entity.ErrInvalidMFAChallenge
+	}
+
+	userID, recoveryCodes, err := a.mfa.Verify(challengeToken, code, recoveryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := a.repo.FindByID(userID)
+	if err != nil {
+		return nil, auth_entity.ErrInvalidMFAChallenge
+	}
+
+	tokens, err := a.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &auth_entity.MFAVerification{Tokens: tokens, RecoveryCodes: recoveryCodes}, nil
+}
+
+func (a *AuthUsecase) EnrollMFA(userID string) (*auth_entity.MFAEnrollment, error) {
+	if a.mfa == nil {
+		return nil, auth_entity.ErrMFANotEnrolled
+	}
+
+	user, err := a.repo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return a.mfa.Enroll(user.ID, user.Email)
+}
+
+func (a *AuthUsecase) ConfirmMFA(userID, code string) ([]string, error) {
+	if a.mfa == nil {
+		return nil, auth_entity.ErrMFANotEnrolled
+	}
+	return a.mfa.Confirm(userID, code)
+}
+
+// Refresh rotates a refresh token: the presented token is consumed and a new
+// pair is issued in the same family. Presenting a token that was already
+// consumed revokes the whole family, since either the legitimate client or
+// an attacker is holding a stolen copy.
+func (a *AuthUsecase) Refresh(refreshToken string) (*auth_entity.TokenPair, error) {
+	if refreshToken == "" {
+		return nil, auth_entity.ErrInvalidRefreshToken
+	}
+
+	stored, err := a.refreshRepo.FindByTokenHash(a.tokenGenerator.Hash(refreshToken))
+	if err != nil {
+		return nil, auth_entity.ErrInvalidRefreshToken
+	}
+
+	now := a.now()
+
+	if stored.IsRevoked() {
+		return nil, auth_entity.ErrInvalidRefreshToken
+	}
+
+	if stored.IsUsed() {
+		return nil, a.revokeFamily(stored.FamilyID, now)
+	}
+
+	if stored.IsExpired(now) {
+		return nil, auth_entity.ErrInvalidRefreshToken
+	}
+
+	marked, err := a.refreshRepo.MarkUsed(stored.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume refresh token: %w", err)
+	}
+	if !marked {
+		return nil, a.revokeFamily(stored.FamilyID, now)
+	}
+
+	user, err := a.repo.FindByID(stored.UserID)
+	if err != nil {
+		return nil, auth_entity.ErrInvalidRefreshToken
+	}
+
+	tokens, err := a.issueTokens(user, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.sessions != nil {
+		if err := a.sessions.Touch(stored.FamilyID, now); err != nil {
+			log.Printf("Failed to update session %s: %v", stored.FamilyID, err)
+		}
+	}
+
+	return tokens, nil
+}
+
+// Logout revokes the access token identified by tokenID and, when given, the
+// refresh token family the client was holding, which ends its session.
+func (a *AuthUsecase) Logout(tokenID, userID string, expiresAt time.Time, refreshToken string) error {
+	if tokenID != "" {
+		if err := a.revocations.RevokeToken(tokenID, userID, expiresAt); err != nil {
+			return fmt.Errorf("failed to revoke token: %w", err)
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := a.refreshRepo.FindByTokenHash(a.tokenGenerator.Hash(refreshToken))
+	if err != nil || stored.UserID != userID {
+		return nil
+	}
+
+	now := a.now()
+	if err := a.refreshRepo.RevokeFamily(stored.FamilyID, now); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	if a.sessions != nil {
+		if err := a.sessions.End(stored.FamilyID, now); err != nil {
+			return fmt.Errorf("failed to end session: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (a *AuthUsecase) RevokeAllSessions(userID string) error {
+	if userID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+
+	if err := a.revocations.RevokeAllForUser(userID); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+
+	if err := a.refreshRepo.RevokeAllForUser(userID, a.now()); err != nil {
+		return fmt.Errorf("failed to revoke user refresh tokens: %w", err)
+	}
+
+	if a.sessions != nil {
+		if err := a.sessions.EndAllForUser(userID, a.now()); err != nil {
+			return fmt.Errorf("failed to end user sessions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Sessions lists the user's sessions that can still refresh their tokens.
+func (a *AuthUsecase) Sessions(userID string) ([]*auth_entity.Session, error) {
+	if a.sessions == nil {
+		return []*auth_entity.Session{}, nil
+	}
+
+	sessions, err := a.sessions.FindActiveByUser(userID, a.now().Add(-a.refreshExpiresIn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// EndSession revokes the session id itself, which the auth middleware checks
+// alongside the token's jti, so every access token issued under the session
+// stops working at once rather than when it expires. No token of the session
+// outlives accessExpiresIn from now, as its refresh tokens are revoked too.
+func (a *AuthUsecase) EndSession(userID, sessionID string) error {
+	if a.sessions == nil {
+		return auth_entity.ErrSessionNotFound
+	}
+
+	session, err := a.sessions.FindByID(sessionID)
+	if err != nil {
+		if errors.Is(err, auth_entity.ErrSessionNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to find session: %w", err)
+	}
+	if session.UserID != userID || session.IsEnded() {
+		return auth_entity.ErrSessionNotFound
 	}
+
+	now := a.now()
+
+	if err := a.revocations.RevokeToken(session.ID, userID, now.Add(a.accessExpiresIn)); err != nil {
+		return fmt.Errorf("failed to revoke session tokens: %w", err)
+	}
+
+	if err := a.refreshRepo.RevokeFamily(session.ID, now); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	if err := a.sessions.End(session.ID, now); err != nil {
+		return fmt.Errorf("failed to end session: %w", err)
+	}
+
+	return nil
+}
+
+// UnlockAccount lifts a login lockout before it expires.
+func (a *AuthUsecase) UnlockAccount(userID string) error {
+	user, err := a.repo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if a.throttle == nil {
+		return nil
+	}
+	return a.throttle.Unlock(user.Email)
 }
 
-func (a *AuthUsecase) Login(email, password string) (string, error) {
-	user, err := a.repo.FindByEmail(email)
+// checkPassword compares against a throwaway hash when the user does not
+// exist, so the response time does not reveal whether the email is known.
+func (a *AuthUsecase) checkPassword(user *user_entity.User, password string) bool {
+	if user == nil {
+		_, _ = a.passwordHasher.HashComparer(password, a.dummyPasswordHash())
+		return false
+	}
+
+	ok, err := a.passwordHasher.HashComparer(password, user.Password)
+	return ok && err == nil
+}
 
+// upgradePasswordHash rehashes the password while the plaintext is at hand
+// when the stored hash uses an older algorithm or weaker parameters. The
+// login already succeeded, so a failure here is only logged.
+func (a *AuthUsecase) upgradePasswordHash(user *user_entity.User, password string) {
+	rehasher, ok := a.passwordHasher.(port_cryptography.Rehasher)
+	if !ok || !rehasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashed, err := a.passwordHasher.Hash(password)
 	if err != nil {
-		return "", errors.New("user not found")
+		log.Printf("Failed to rehash password for user %s: %v", user.ID, err)
+		return
+	}
+
+	user.Password = hashed
+	if _, err := a.repo.Update(user.ID, user); err != nil {
+		log.Printf("Failed to store rehashed password for user %s: %v", user.ID, err)
+	}
+}
+
+func (a *AuthUsecase) dummyPasswordHash() string {
+	a.dummyHashOnce.Do(func() {
+		a.dummyHash, _ = a.passwordHasher.Hash(uuid.New().String())
+	})
+	return a.dummyHash
+}
+
+func (a *AuthUsecase) revokeFamily(familyID string, now time.Time) error {
+	if err := a.refreshRepo.RevokeFamily(familyID, now); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	if a.sessions != nil {
+		if err := a.sessions.End(familyID, now); err != nil {
+			log.Printf("Failed to end session %s: %v", familyID, err)
+		}
 	}
+	return auth_entity.ErrRefreshTokenReused
+}
+
+// startSession issues the first tokens of a new refresh token family and,
+// once they are issued, records the session they belong to.
+func (a *AuthUsecase) startSession(user *user_entity.User, client auth_entity.ClientInfo) (*auth_entity.TokenPair, error) {
+	familyID := uuid.New().String()
 
-	if _, err := a.passwordHasher.HashComparer(password, user.Password); err != nil {
-		return "", errors.New("invalid credentials")
+	tokens, err := a.issueTokens(user, familyID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Fetch user permissions and extract modules
-	modules := []string{}
-	actions := []string{}
-	permissions, err := a.permissionRepo.FindPermissionByUserID(user.ID)
-	if err == nil && len(permissions) > 0 {
-		for _, permission := range permissions {
-			modules = append(modules, permission.GetModules()...)
-			actions = append(actions, permission.GetActions()...)
+	if a.sessions != nil {
+		if _, err := a.sessions.Save(auth_entity.NewSession(familyID, user.ID, client, a.now())); err != nil {
+			return nil, fmt.Errorf("failed to save session: %w", err)
 		}
 	}
 
+	a.event.Dispatch(auth_event.NewLoginSucceededEvent(user.ID, user.Email, familyID, client.IP, client.UserAgent))
+
+	return tokens, nil
+}
+
+func (a *AuthUsecase) issueTokens(user *user_entity.User, familyID string) (*auth_entity.TokenPair, error) {
 	userSign := map[string]interface{}{
 		"user_id":    user.ID,
 		"name":       user.Name,
 		"nick_name":  user.Nickname,
 		"email":      user.Email,
-		"modules":    modules,
-		"actions":    actions,
 		"created_at": user.CreatedAt,
 		"updated_at": user.UpdatedAt,
 	}
+	if a.sessions != nil {
+		userSign["sid"] = familyID
+	}
+
+	effective, err := a.permissions.Refresh(user.ID)
+	if a.permissionMode == auth_entity.PermissionModeReference {
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve permissions: %w", err)
+		}
+		userSign["perm_version"] = effective.Version
+	} else {
+		// A failed lookup issues a token without permissions rather than
+		// failing the login.
+		modules := []string{}
+		actions := []string{}
+		grants := []string{}
+		if err == nil {
+			modules = effective.Modules()
+			actions = effective.Actions()
+			for _, grant := range effective.Grants() {
+				grants = append(grants, grant.String())
+			}
+		}
+		userSign["modules"] = modules
+		userSign["actions"] = actions
+		userSign["grants"] = grants
+	}
 
 	token, err := a.jwtTokenManager.Sign(userSign)
 
 	if err != nil {
-		return "", errors.New("error in generate token")
+		return nil, errors.New("error in generate token")
 	}
 
-	return token, nil
-}
-
-func (a *AuthUsecase) Profile(email string) (string, error) {
-	user, err := a.repo.FindByEmail(email)
-
+	refreshToken, refreshHash, err := a.tokenGenerator.Generate()
 	if err != nil {
-		return "", errors.New("user not found")
+		return nil, errors.New("error in generate refresh token")
+	}
+
+	stored := auth_entity.NewRefreshToken(uuid.New().String(), user.ID, familyID, refreshHash, a.now().Add(a.refreshExpiresIn))
+	if _, err := a.refreshRepo.Save(stored); err != nil {
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
 	}
 
-	return user.Email, nil
+	return &auth_entity.TokenPair{
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    a.accessExpiresIn,
+	}, nil
 }
@@ -7,8 +7,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_event "github.com/williamkoller/system-education/internal/auth/domain/event"
 	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
 )
 
 type MockUserRepository struct {
@@ -47,8 +50,8 @@ func (m *MockUserRepository) Update(id string, user *userEntity.User) (*userEnti
 	return args.Get(0).(*userEntity.User), args.Error(1)
 }
 
-func (m *MockUserRepository) Delete(id string) error {
-	args := m.Called(id)
+func (m *MockUserRepository) Delete(user *userEntity.User) error {
+	args := m.Called(user)
 	return args.Error(0)
 }
 
@@ -77,76 +80,110 @@ func (m *MockTokenManager) Verify(token string) (map[string]interface{}, error)
 	return args.Get(0).(map[string]interface{}), args.Error(1)
 }
 
-type MockBcrypt struct {
+type MockRefreshTokenRepository struct {
 	mock.Mock
 }
 
-func (m *MockBcrypt) Hash(password string) (string, error) {
-	args := m.Called(password)
-	return args.String(0), args.Error(1)
+func (m *MockRefreshTokenRepository) Save(t *auth_entity.RefreshToken) (*auth_entity.RefreshToken, error) {
+	args := m.Called(t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.RefreshToken), args.Error(1)
 }
 
-func (m *MockBcrypt) HashComparer(password, hash string) (bool, error) {
-	args := m.Called(password, hash)
+func (m *MockRefreshTokenRepository) FindByTokenHash(hash string) (*auth_entity.RefreshToken, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) MarkUsed(id string, usedAt time.Time) (bool, error) {
+	args := m.Called(id, usedAt)
 	return args.Bool(0), args.Error(1)
 }
 
-type MockPermissionRepository struct {
+func (m *MockRefreshTokenRepository) RevokeFamily(familyID string, revokedAt time.Time) error {
+	args := m.Called(familyID, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(userID string, revokedAt time.Time) error {
+	args := m.Called(userID, revokedAt)
+	return args.Error(0)
+}
+
+type MockSecureTokenGenerator struct {
 	mock.Mock
 }
 
-func (m *MockPermissionRepository) Save(permission *permissionEntity.Permission) (*permissionEntity.Permission, error) {
-	args := m.Called(permission)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*permissionEntity.Permission), args.Error(1)
+func (m *MockSecureTokenGenerator) Generate() (string, string, error) {
+	args := m.Called()
+	return args.String(0), args.String(1), args.Error(2)
 }
 
-func (m *MockPermissionRepository) FindByID(id string) (*permissionEntity.Permission, error) {
-	args := m.Called(id)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*permissionEntity.Permission), args.Error(1)
+func (m *MockSecureTokenGenerator) Hash(token string) string {
+	args := m.Called(token)
+	return args.String(0)
 }
 
-func (m *MockPermissionRepository) FindPermissionByUserID(userID string) ([]*permissionEntity.Permission, error) {
-	args := m.Called(userID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).([]*permissionEntity.Permission), args.Error(1)
+type MockRevocationStore struct {
+	mock.Mock
 }
 
-func (m *MockPermissionRepository) Update(id string, permission *permissionEntity.Permission) (*permissionEntity.Permission, error) {
-	args := m.Called(id, permission)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*permissionEntity.Permission), args.Error(1)
+func (m *MockRevocationStore) RevokeToken(jti, userID string, expiresAt time.Time) error {
+	args := m.Called(jti, userID, expiresAt)
+	return args.Error(0)
 }
 
-func (m *MockPermissionRepository) Delete(id string) error {
-	args := m.Called(id)
+func (m *MockRevocationStore) RevokeAllForUser(userID string) error {
+	args := m.Called(userID)
 	return args.Error(0)
 }
 
-func (m *MockPermissionRepository) FindAll() ([]*permissionEntity.Permission, error) {
-	args := m.Called()
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).([]*permissionEntity.Permission), args.Error(1)
+func (m *MockRevocationStore) IsRevoked(jti, userID string, issuedAt time.Time) (bool, error) {
+	args := m.Called(jti, userID, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockBcrypt struct {
+	mock.Mock
+}
+
+func (m *MockBcrypt) Hash(password string) (string, error) {
+	args := m.Called(password)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockBcrypt) HashComparer(password, hash string) (bool, error) {
+	args := m.Called(password, hash)
+	return args.Bool(0), args.Error(1)
 }
 
 func TestAuthUsecase_Login_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	mockPermissionRepo := new(MockPermissionRepository)
+	mockPermissions := new(MockPermissionService)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	})
 
 	email := "test@example.com"
 	password := "password123"
@@ -167,49 +204,89 @@ func TestAuthUsecase_Login_Success(t *testing.T) {
 
 	mockRepo.On("FindByEmail", email).Return(user, nil)
 	mockBcrypt.On("HashComparer", password, hashedPassword).Return(true, nil)
-	mockPermissionRepo.On("FindPermissionByUserID", "user-123").Return([]*permissionEntity.Permission{}, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, []*permissionEntity.Permission{}), nil)
 	mockTokenManager.On("Sign", mock.MatchedBy(func(data map[string]interface{}) bool {
 		return data["email"] == email && data["name"] == "John" && data["user_id"] == "user-123"
 	})).Return(expectedToken, nil)
 
-	token, err := usecase.Login(email, password)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.MatchedBy(func(rt *auth_entity.RefreshToken) bool {
+		return rt.UserID == "user-123" && rt.TokenHash == "refresh-hash" && rt.FamilyID != ""
+	})).Return(&auth_entity.RefreshToken{}, nil)
+
+	result, err := usecase.Login(email, password, auth_entity.ClientInfo{})
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedToken, token)
+	assert.Equal(t, expectedToken, result.Tokens.AccessToken)
+	assert.Equal(t, "refresh-token", result.Tokens.RefreshToken)
+	assert.Equal(t, time.Minute, result.Tokens.ExpiresIn)
+	mockRefreshRepo.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 	mockBcrypt.AssertExpectations(t)
-	mockPermissionRepo.AssertExpectations(t)
+	mockPermissions.AssertExpectations(t)
 	mockTokenManager.AssertExpectations(t)
 }
 
 func TestAuthUsecase_Login_UserNotFound(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	mockPermissionRepo := new(MockPermissionRepository)
+	mockPermissions := new(MockPermissionService)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	})
 
 	email := "nonexistent@example.com"
 	password := "password123"
 
 	mockRepo.On("FindByEmail", email).Return(nil, errors.New("not found"))
+	mockBcrypt.On("Hash", mock.Anything).Return("dummy-hash", nil).Once()
+	mockBcrypt.On("HashComparer", password, "dummy-hash").Return(false, errors.New("password mismatch"))
 
-	token, err := usecase.Login(email, password)
+	result, err := usecase.Login(email, password, auth_entity.ClientInfo{})
 
-	assert.Error(t, err)
-	assert.Equal(t, "", token)
-	assert.Equal(t, "user not found", err.Error())
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidCredentials)
+	assert.Nil(t, result)
 	mockRepo.AssertExpectations(t)
+	mockBcrypt.AssertExpectations(t)
 }
 
 func TestAuthUsecase_Login_InvalidPassword(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	mockPermissionRepo := new(MockPermissionRepository)
+	mockPermissions := new(MockPermissionService)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	})
 
 	email := "test@example.com"
 	password := "wrongpassword"
@@ -230,11 +307,11 @@ func TestAuthUsecase_Login_InvalidPassword(t *testing.T) {
 	mockBcrypt.On("HashComparer", password, hashedPassword).Return(false, errors.New("password mismatch"))
 
 	// Act
-	token, err := usecase.Login(email, password)
+	result, err := usecase.Login(email, password, auth_entity.ClientInfo{})
 
 	// Assert
 	assert.Error(t, err)
-	assert.Equal(t, "", token)
+	assert.Nil(t, result)
 	assert.Equal(t, "invalid credentials", err.Error())
 	mockRepo.AssertExpectations(t)
 	mockBcrypt.AssertExpectations(t)
@@ -242,11 +319,26 @@ func TestAuthUsecase_Login_InvalidPassword(t *testing.T) {
 
 func TestAuthUsecase_Login_TokenGenerationError(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	mockPermissionRepo := new(MockPermissionRepository)
+	mockPermissions := new(MockPermissionService)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	})
 
 	email := "test@example.com"
 	password := "password123"
@@ -265,77 +357,42 @@ func TestAuthUsecase_Login_TokenGenerationError(t *testing.T) {
 
 	mockRepo.On("FindByEmail", email).Return(user, nil)
 	mockBcrypt.On("HashComparer", password, hashedPassword).Return(true, nil)
-	mockPermissionRepo.On("FindPermissionByUserID", "user-123").Return([]*permissionEntity.Permission{}, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, []*permissionEntity.Permission{}), nil)
 	mockTokenManager.On("Sign", mock.Anything).Return("", errors.New("token generation failed"))
 
-	token, err := usecase.Login(email, password)
+	result, err := usecase.Login(email, password, auth_entity.ClientInfo{})
 
 	assert.Error(t, err)
-	assert.Equal(t, "", token)
+	assert.Nil(t, result)
 	assert.Equal(t, "error in generate token", err.Error())
 	mockRepo.AssertExpectations(t)
 	mockBcrypt.AssertExpectations(t)
-	mockPermissionRepo.AssertExpectations(t)
+	mockPermissions.AssertExpectations(t)
 	mockTokenManager.AssertExpectations(t)
 }
 
-func TestAuthUsecase_Profile_Success(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	mockPermissionRepo := new(MockPermissionRepository)
-	mockTokenManager := new(MockTokenManager)
-	mockBcrypt := new(MockBcrypt)
-
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
-
-	email := "test@example.com"
-
-	user := &userEntity.User{
-		ID:        "user-123",
-		Name:      "John",
-		Surname:   "Doe",
-		Nickname:  "johndoe",
-		Email:     email,
-		Password:  "$2a$10$hashedpassword",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	mockRepo.On("FindByEmail", email).Return(user, nil)
-
-	result, err := usecase.Profile(email)
-
-	assert.NoError(t, err)
-	assert.Equal(t, email, result)
-	mockRepo.AssertExpectations(t)
-}
-
-func TestAuthUsecase_Profile_UserNotFound(t *testing.T) {
-	mockRepo := new(MockUserRepository)
-	mockPermissionRepo := new(MockPermissionRepository)
-	mockTokenManager := new(MockTokenManager)
-	mockBcrypt := new(MockBcrypt)
-
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
-
-	email := "nonexistent@example.com"
-
-	mockRepo.On("FindByEmail", email).Return(nil, errors.New("not found"))
-
-	result, err := usecase.Profile(email)
-
-	assert.Error(t, err)
-	assert.Equal(t, "", result)
-	assert.Equal(t, "user not found", err.Error())
-	mockRepo.AssertExpectations(t)
-}
-
 func TestAuthUsecase_Login_WithPermissionsAndModules(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	mockPermissionRepo := new(MockPermissionRepository)
+	mockPermissions := new(MockPermissionService)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	})
 
 	email := "test@example.com"
 	password := "password123"
@@ -356,10 +413,12 @@ func TestAuthUsecase_Login_WithPermissionsAndModules(t *testing.T) {
 	permission1 := &permissionEntity.Permission{}
 	permission1.UserID = "user-123"
 	permission1.Modules = []string{"admin", "user"}
+	permission1.Level = "allowed"
 
 	permission2 := &permissionEntity.Permission{}
 	permission2.UserID = "user-123"
 	permission2.Modules = []string{"reports"}
+	permission2.Level = "allowed"
 
 	permissions := []*permissionEntity.Permission{permission1, permission2}
 
@@ -367,7 +426,7 @@ func TestAuthUsecase_Login_WithPermissionsAndModules(t *testing.T) {
 
 	mockRepo.On("FindByEmail", email).Return(user, nil)
 	mockBcrypt.On("HashComparer", password, hashedPassword).Return(true, nil)
-	mockPermissionRepo.On("FindPermissionByUserID", "user-123").Return(permissions, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, permissions), nil)
 	mockTokenManager.On("Sign", mock.MatchedBy(func(data map[string]interface{}) bool {
 		modules, ok := data["modules"].([]string)
 		if !ok {
@@ -379,23 +438,46 @@ func TestAuthUsecase_Login_WithPermissionsAndModules(t *testing.T) {
 			data["user_id"] == "user-123"
 	})).Return(expectedToken, nil)
 
-	token, err := usecase.Login(email, password)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.MatchedBy(func(rt *auth_entity.RefreshToken) bool {
+		return rt.UserID == "user-123" && rt.TokenHash == "refresh-hash" && rt.FamilyID != ""
+	})).Return(&auth_entity.RefreshToken{}, nil)
+
+	result, err := usecase.Login(email, password, auth_entity.ClientInfo{})
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedToken, token)
+	assert.Equal(t, expectedToken, result.Tokens.AccessToken)
+	assert.Equal(t, "refresh-token", result.Tokens.RefreshToken)
+	assert.Equal(t, time.Minute, result.Tokens.ExpiresIn)
+	mockRefreshRepo.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 	mockBcrypt.AssertExpectations(t)
-	mockPermissionRepo.AssertExpectations(t)
+	mockPermissions.AssertExpectations(t)
 	mockTokenManager.AssertExpectations(t)
 }
 
 func TestAuthUsecase_Login_PermissionFetchError(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	mockPermissionRepo := new(MockPermissionRepository)
+	mockPermissions := new(MockPermissionService)
 	mockTokenManager := new(MockTokenManager)
 	mockBcrypt := new(MockBcrypt)
 
-	usecase := NewAuthUsecase(mockRepo, mockPermissionRepo, mockTokenManager, mockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	})
 
 	email := "test@example.com"
 	password := "password123"
@@ -417,7 +499,7 @@ func TestAuthUsecase_Login_PermissionFetchError(t *testing.T) {
 	mockRepo.On("FindByEmail", email).Return(user, nil)
 	mockBcrypt.On("HashComparer", password, hashedPassword).Return(true, nil)
 	// Permission fetch fails, but login should still succeed with empty modules
-	mockPermissionRepo.On("FindPermissionByUserID", "user-123").Return(nil, errors.New("permission db error"))
+	mockPermissions.On("Refresh", "user-123").Return(nil, errors.New("permission db error"))
 	mockTokenManager.On("Sign", mock.MatchedBy(func(data map[string]interface{}) bool {
 		modules, ok := data["modules"].([]string)
 		if !ok {
@@ -429,12 +511,1291 @@ func TestAuthUsecase_Login_PermissionFetchError(t *testing.T) {
 			data["user_id"] == "user-123"
 	})).Return(expectedToken, nil)
 
-	token, err := usecase.Login(email, password)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.MatchedBy(func(rt *auth_entity.RefreshToken) bool {
+		return rt.UserID == "user-123" && rt.TokenHash == "refresh-hash" && rt.FamilyID != ""
+	})).Return(&auth_entity.RefreshToken{}, nil)
+
+	result, err := usecase.Login(email, password, auth_entity.ClientInfo{})
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedToken, token)
+	assert.Equal(t, expectedToken, result.Tokens.AccessToken)
+	assert.Equal(t, "refresh-token", result.Tokens.RefreshToken)
+	assert.Equal(t, time.Minute, result.Tokens.ExpiresIn)
+	mockRefreshRepo.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 	mockBcrypt.AssertExpectations(t)
-	mockPermissionRepo.AssertExpectations(t)
+	mockPermissions.AssertExpectations(t)
+	mockTokenManager.AssertExpectations(t)
+}
+
+func newRefreshTestUsecase() (*AuthUsecase, *MockUserRepository, *MockPermissionService, *MockTokenManager, *MockRefreshTokenRepository, *MockSecureTokenGenerator) {
+	usecase, mockRepo, mockPermissions, mockTokenManager, mockRefreshRepo, mockGenerator, _ := newSessionTestUsecase()
+
+	return usecase, mockRepo, mockPermissions, mockTokenManager, mockRefreshRepo, mockGenerator
+}
+
+func newSessionTestUsecase() (*AuthUsecase, *MockUserRepository, *MockPermissionService, *MockTokenManager, *MockRefreshTokenRepository, *MockSecureTokenGenerator, *MockRevocationStore) {
+	mockRepo := new(MockUserRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   new(MockBcrypt),
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      mockRevocations,
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	})
+
+	return usecase, mockRepo, mockPermissions, mockTokenManager, mockRefreshRepo, mockGenerator, mockRevocations
+}
+
+func TestAuthUsecase_Refresh_RotatesToken(t *testing.T) {
+	usecase, mockRepo, mockPermissions, mockTokenManager, mockRefreshRepo, mockGenerator := newRefreshTestUsecase()
+	now := time.Now()
+	usecase.now = func() time.Time { return now }
+
+	stored := auth_entity.NewRefreshToken("rt-1", "user-123", "family-1", "old-hash", now.Add(time.Hour))
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com"}
+
+	mockGenerator.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByTokenHash", "old-hash").Return(stored, nil)
+	mockRefreshRepo.On("MarkUsed", "rt-1", now).Return(true, nil)
+	mockRepo.On("FindByID", "user-123").Return(user, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, []*permissionEntity.Permission{}), nil)
+	mockTokenManager.On("Sign", mock.Anything).Return("new-access", nil)
+	mockGenerator.On("Generate").Return("new-token", "new-hash", nil)
+	mockRefreshRepo.On("Save", mock.MatchedBy(func(rt *auth_entity.RefreshToken) bool {
+		return rt.FamilyID == "family-1" && rt.TokenHash == "new-hash" && rt.ExpiresAt.Equal(now.Add(time.Hour))
+	})).Return(&auth_entity.RefreshToken{}, nil)
+
+	tokens, err := usecase.Refresh("old-token")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new-access", tokens.AccessToken)
+	assert.Equal(t, "new-token", tokens.RefreshToken)
+	mockRefreshRepo.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
 	mockTokenManager.AssertExpectations(t)
 }
+
+func TestAuthUsecase_Refresh_ReuseRevokesFamily(t *testing.T) {
+	usecase, _, _, mockTokenManager, mockRefreshRepo, mockGenerator := newRefreshTestUsecase()
+	now := time.Now()
+	usecase.now = func() time.Time { return now }
+
+	usedAt := now.Add(-time.Minute)
+	stored := auth_entity.NewRefreshToken("rt-1", "user-123", "family-1", "old-hash", now.Add(time.Hour))
+	stored.UsedAt = &usedAt
+
+	mockGenerator.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByTokenHash", "old-hash").Return(stored, nil)
+	mockRefreshRepo.On("RevokeFamily", "family-1", now).Return(nil)
+
+	tokens, err := usecase.Refresh("old-token")
+
+	assert.ErrorIs(t, err, auth_entity.ErrRefreshTokenReused)
+	assert.Nil(t, tokens)
+	mockRefreshRepo.AssertExpectations(t)
+	mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+}
+
+func TestAuthUsecase_Refresh_ConcurrentUseRevokesFamily(t *testing.T) {
+	usecase, _, _, _, mockRefreshRepo, mockGenerator := newRefreshTestUsecase()
+	now := time.Now()
+	usecase.now = func() time.Time { return now }
+
+	stored := auth_entity.NewRefreshToken("rt-1", "user-123", "family-1", "old-hash", now.Add(time.Hour))
+
+	mockGenerator.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByTokenHash", "old-hash").Return(stored, nil)
+	mockRefreshRepo.On("MarkUsed", "rt-1", now).Return(false, nil)
+	mockRefreshRepo.On("RevokeFamily", "family-1", now).Return(nil)
+
+	tokens, err := usecase.Refresh("old-token")
+
+	assert.ErrorIs(t, err, auth_entity.ErrRefreshTokenReused)
+	assert.Nil(t, tokens)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Refresh_Expired(t *testing.T) {
+	usecase, _, _, _, mockRefreshRepo, mockGenerator := newRefreshTestUsecase()
+	now := time.Now()
+	usecase.now = func() time.Time { return now }
+
+	stored := auth_entity.NewRefreshToken("rt-1", "user-123", "family-1", "old-hash", now.Add(-time.Second))
+
+	mockGenerator.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByTokenHash", "old-hash").Return(stored, nil)
+
+	tokens, err := usecase.Refresh("old-token")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidRefreshToken)
+	assert.Nil(t, tokens)
+	mockRefreshRepo.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Refresh_Revoked(t *testing.T) {
+	usecase, _, _, _, mockRefreshRepo, mockGenerator := newRefreshTestUsecase()
+	now := time.Now()
+
+	stored := auth_entity.NewRefreshToken("rt-1", "user-123", "family-1", "old-hash", now.Add(time.Hour))
+	stored.RevokedAt = &now
+
+	mockGenerator.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByTokenHash", "old-hash").Return(stored, nil)
+
+	tokens, err := usecase.Refresh("old-token")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidRefreshToken)
+	assert.Nil(t, tokens)
+	mockRefreshRepo.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Refresh_NotFound(t *testing.T) {
+	usecase, _, _, _, mockRefreshRepo, mockGenerator := newRefreshTestUsecase()
+
+	mockGenerator.On("Hash", "unknown").Return("unknown-hash")
+	mockRefreshRepo.On("FindByTokenHash", "unknown-hash").Return(nil, auth_entity.ErrRefreshTokenNotFound)
+
+	tokens, err := usecase.Refresh("unknown")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidRefreshToken)
+	assert.Nil(t, tokens)
+}
+
+func TestAuthUsecase_Refresh_EmptyToken(t *testing.T) {
+	usecase, _, _, _, mockRefreshRepo, _ := newRefreshTestUsecase()
+
+	tokens, err := usecase.Refresh("")
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidRefreshToken)
+	assert.Nil(t, tokens)
+	mockRefreshRepo.AssertNotCalled(t, "FindByTokenHash", mock.Anything)
+}
+
+func TestAuthUsecase_Logout_RevokesAccessAndRefreshTokens(t *testing.T) {
+	usecase, _, _, _, mockRefreshRepo, mockGenerator, mockRevocations := newSessionTestUsecase()
+	now := time.Now()
+	usecase.now = func() time.Time { return now }
+	expiresAt := now.Add(time.Minute)
+
+	stored := auth_entity.NewRefreshToken("rt-1", "user-123", "family-1", "refresh-hash", now.Add(time.Hour))
+
+	mockRevocations.On("RevokeToken", "jti-1", "user-123", expiresAt).Return(nil)
+	mockGenerator.On("Hash", "refresh-token").Return("refresh-hash")
+	mockRefreshRepo.On("FindByTokenHash", "refresh-hash").Return(stored, nil)
+	mockRefreshRepo.On("RevokeFamily", "family-1", now).Return(nil)
+
+	err := usecase.Logout("jti-1", "user-123", expiresAt, "refresh-token")
+
+	assert.NoError(t, err)
+	mockRevocations.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Logout_IgnoresRefreshTokenOfAnotherUser(t *testing.T) {
+	usecase, _, _, _, mockRefreshRepo, mockGenerator, mockRevocations := newSessionTestUsecase()
+	expiresAt := time.Now().Add(time.Minute)
+
+	stored := auth_entity.NewRefreshToken("rt-1", "someone-else", "family-1", "refresh-hash", time.Now().Add(time.Hour))
+
+	mockRevocations.On("RevokeToken", "jti-1", "user-123", expiresAt).Return(nil)
+	mockGenerator.On("Hash", "refresh-token").Return("refresh-hash")
+	mockRefreshRepo.On("FindByTokenHash", "refresh-hash").Return(stored, nil)
+
+	err := usecase.Logout("jti-1", "user-123", expiresAt, "refresh-token")
+
+	assert.NoError(t, err)
+	mockRefreshRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Logout_RevocationError(t *testing.T) {
+	usecase, _, _, _, _, _, mockRevocations := newSessionTestUsecase()
+	expiresAt := time.Now().Add(time.Minute)
+
+	mockRevocations.On("RevokeToken", "jti-1", "user-123", expiresAt).Return(errors.New("db down"))
+
+	err := usecase.Logout("jti-1", "user-123", expiresAt, "")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to revoke token")
+}
+
+func TestAuthUsecase_RevokeAllSessions(t *testing.T) {
+	usecase, _, _, _, mockRefreshRepo, _, mockRevocations := newSessionTestUsecase()
+	now := time.Now()
+	usecase.now = func() time.Time { return now }
+
+	mockRevocations.On("RevokeAllForUser", "user-123").Return(nil)
+	mockRefreshRepo.On("RevokeAllForUser", "user-123", now).Return(nil)
+
+	err := usecase.RevokeAllSessions("user-123")
+
+	assert.NoError(t, err)
+	mockRevocations.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_RevokeAllSessions_EmptyUserID(t *testing.T) {
+	usecase, _, _, _, _, _, mockRevocations := newSessionTestUsecase()
+
+	err := usecase.RevokeAllSessions("")
+
+	assert.Error(t, err)
+	mockRevocations.AssertNotCalled(t, "RevokeAllForUser", mock.Anything)
+}
+
+type MockPermissionService struct {
+	mock.Mock
+}
+
+func (m *MockPermissionService) Resolve(userID string, minVersion int64) (*permissionEntity.EffectivePermissions, error) {
+	args := m.Called(userID, minVersion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permissionEntity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Refresh(userID string) (*permissionEntity.EffectivePermissions, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permissionEntity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Invalidate(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func newReferenceTestUsecase() (*AuthUsecase, *MockUserRepository, *MockPermissionService, *MockTokenManager, *MockBcrypt, *MockRefreshTokenRepository, *MockSecureTokenGenerator) {
+	mockRepo := new(MockUserRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeReference,
+	})
+
+	return usecase, mockRepo, mockPermissions, mockTokenManager, mockBcrypt, mockRefreshRepo, mockGenerator
+}
+
+func TestAuthUsecase_Login_ReferenceMode(t *testing.T) {
+	usecase, mockRepo, mockPermissions, mockTokenManager, mockBcrypt, mockRefreshRepo, mockGenerator := newReferenceTestUsecase()
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}
+
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 7, nil), nil)
+	mockTokenManager.On("Sign", mock.MatchedBy(func(data map[string]interface{}) bool {
+		_, hasModules := data["modules"]
+		_, hasActions := data["actions"]
+		return data["perm_version"] == int64(7) && data["user_id"] == "user-123" && !hasModules && !hasActions
+	})).Return("jwt.token", nil)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.Anything).Return(&auth_entity.RefreshToken{}, nil)
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt.token", result.Tokens.AccessToken)
+	mockPermissions.AssertExpectations(t)
+	mockTokenManager.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Login_ReferenceModeVersionError(t *testing.T) {
+	usecase, mockRepo, mockPermissions, mockTokenManager, mockBcrypt, _, _ := newReferenceTestUsecase()
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}
+
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockPermissions.On("Refresh", "user-123").Return(nil, errors.New("db error"))
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.Nil(t, result)
+	assert.ErrorContains(t, err, "failed to resolve permissions")
+	mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+}
+
+func TestAuthUsecase_Login_EmbedsGrantsWithLevels(t *testing.T) {
+	usecase, mockRepo, mockPermissions, mockTokenManager, mockRefreshRepo, mockGenerator := newRefreshTestUsecase()
+	mockBcrypt := usecase.passwordHasher.(*MockBcrypt)
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}
+
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, []*permissionEntity.Permission{
+		{Modules: []string{"users"}, Actions: []string{"read"}, Level: "allowed"},
+		{Modules: []string{"permissions"}, Actions: []string{"delete"}, Level: "allowed"},
+		{Modules: []string{"reports"}, Actions: []string{"read"}, Level: "restricted"},
+		{Modules: []string{"users"}, Actions: []string{"delete"}, Level: "denied"},
+	}), nil)
+	mockTokenManager.On("Sign", mock.MatchedBy(func(data map[string]interface{}) bool {
+		grants, ok := data["grants"].([]string)
+		return ok &&
+			assert.ObjectsAreEqual([]string{"users:read", "permissions:delete", "reports:read:restricted", "users:delete:denied"}, grants) &&
+			assert.ObjectsAreEqual([]string{"users", "permissions"}, data["modules"]) &&
+			assert.ObjectsAreEqual([]string{"read", "delete"}, data["actions"])
+	})).Return("jwt.token", nil)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.Anything).Return(&auth_entity.RefreshToken{}, nil)
+
+	_, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	mockTokenManager.AssertExpectations(t)
+}
+
+type MockMFAService struct {
+	mock.Mock
+}
+
+func (m *MockMFAService) Challenge(userID, account string) (*auth_entity.MFAChallengeTicket, error) {
+	args := m.Called(userID, account)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.MFAChallengeTicket), args.Error(1)
+}
+
+func (m *MockMFAService) Verify(challengeToken, code, recoveryCode string) (string, []string, error) {
+	args := m.Called(challengeToken, code, recoveryCode)
+	if args.Get(1) == nil {
+		return args.String(0), nil, args.Error(2)
+	}
+	return args.String(0), args.Get(1).([]string), args.Error(2)
+}
+
+func (m *MockMFAService) Enroll(userID, account string) (*auth_entity.MFAEnrollment, error) {
+	args := m.Called(userID, account)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.MFAEnrollment), args.Error(1)
+}
+
+func (m *MockMFAService) Confirm(userID, code string) ([]string, error) {
+	args := m.Called(userID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func newMFATestUsecase() (*AuthUsecase, *MockUserRepository, *MockPermissionService, *MockTokenManager, *MockBcrypt, *MockRefreshTokenRepository, *MockSecureTokenGenerator, *MockMFAService) {
+	mockRepo := new(MockUserRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockMFA := new(MockMFAService)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	}, WithMFA(mockMFA))
+
+	return usecase, mockRepo, mockPermissions, mockTokenManager, mockBcrypt, mockRefreshRepo, mockGenerator, mockMFA
+}
+
+func TestAuthUsecase_Login_ReturnsMFAChallenge(t *testing.T) {
+	usecase, mockRepo, mockPermissions, mockTokenManager, mockBcrypt, _, _, mockMFA := newMFATestUsecase()
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}
+	ticket := &auth_entity.MFAChallengeTicket{Token: "challenge", ExpiresIn: 5 * time.Minute}
+
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockMFA.On("Challenge", "user-123", "test@example.com").Return(ticket, nil)
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.True(t, result.MFARequired())
+	assert.Nil(t, result.Tokens)
+	assert.Equal(t, ticket, result.Challenge)
+	mockPermissions.AssertNotCalled(t, "Refresh", mock.Anything)
+	mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+}
+
+func TestAuthUsecase_Login_WithoutMFANeeded(t *testing.T) {
+	usecase, mockRepo, mockPermissions, mockTokenManager, mockBcrypt, mockRefreshRepo, mockGenerator, mockMFA := newMFATestUsecase()
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}
+
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockMFA.On("Challenge", "user-123", "test@example.com").Return(nil, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, nil), nil)
+	mockTokenManager.On("Sign", mock.Anything).Return("jwt.token", nil)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.Anything).Return(&auth_entity.RefreshToken{}, nil)
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.False(t, result.MFARequired())
+	assert.Equal(t, "jwt.token", result.Tokens.AccessToken)
+}
+
+func TestAuthUsecase_Login_MFAChallengeError(t *testing.T) {
+	usecase, mockRepo, _, mockTokenManager, mockBcrypt, _, _, mockMFA := newMFATestUsecase()
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}
+
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockMFA.On("Challenge", "user-123", "test@example.com").Return(nil, errors.New("failed to resolve permissions: db down"))
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.Nil(t, result)
+	assert.Error(t, err)
+	mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+}
+
+func TestAuthUsecase_CompleteLogin_AppliesMFA(t *testing.T) {
+	usecase, mockRepo, _, mockTokenManager, mockBcrypt, _, _, mockMFA := newMFATestUsecase()
+
+	ticket := &auth_entity.MFAChallengeTicket{Token: "challenge", ExpiresIn: 5 * time.Minute}
+	mockRepo.On("FindByID", "user-123").Return(&userEntity.User{ID: "user-123", Email: "test@example.com"}, nil)
+	mockMFA.On("Challenge", "user-123", "test@example.com").Return(ticket, nil)
+
+	result, err := usecase.CompleteLogin("user-123", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ticket, result.Challenge)
+	mockBcrypt.AssertNotCalled(t, "HashComparer", mock.Anything, mock.Anything)
+	mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+}
+
+func TestAuthUsecase_CompleteLogin_UserNotFound(t *testing.T) {
+	usecase, mockRepo, _, _, _, _, _, _ := newMFATestUsecase()
+
+	mockRepo.On("FindByID", "missing").Return(nil, errors.New("not found"))
+
+	result, err := usecase.CompleteLogin("missing", auth_entity.ClientInfo{})
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestAuthUsecase_VerifyMFA_IssuesTokens(t *testing.T) {
+	usecase, mockRepo, mockPermissions, mockTokenManager, _, mockRefreshRepo, mockGenerator, mockMFA := newMFATestUsecase()
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com"}
+
+	mockMFA.On("Verify", "challenge", "123456", "").Return("user-123", []string{"aaaa-bbbb"}, nil)
+	mockRepo.On("FindByID", "user-123").Return(user, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, nil), nil)
+	mockTokenManager.On("Sign", mock.Anything).Return("jwt.token", nil)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.Anything).Return(&auth_entity.RefreshToken{}, nil)
+
+	verification, err := usecase.VerifyMFA("challenge", "123456", "", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt.token", verification.Tokens.AccessToken)
+	assert.Equal(t, "refresh-token", verification.Tokens.RefreshToken)
+	assert.Equal(t, []string{"aaaa-bbbb"}, verification.RecoveryCodes)
+}
+
+func TestAuthUsecase_VerifyMFA_InvalidCode(t *testing.T) {
+	usecase, mockRepo, _, mockTokenManager, _, _, _, mockMFA := newMFATestUsecase()
+
+	mockMFA.On("Verify", "challenge", "000000", "").Return("", nil, auth_entity.ErrInvalidMFACode)
+
+	verification, err := usecase.VerifyMFA("challenge", "000000", "", auth_entity.ClientInfo{})
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFACode)
+	assert.Nil(t, verification)
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+	mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+}
+
+func TestAuthUsecase_VerifyMFA_WithoutMFA(t *testing.T) {
+	usecase, _, _, _, _, _ := newRefreshTestUsecase()
+
+	verification, err := usecase.VerifyMFA("challenge", "123456", "", auth_entity.ClientInfo{})
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidMFAChallenge)
+	assert.Nil(t, verification)
+}
+
+func TestAuthUsecase_EnrollMFA(t *testing.T) {
+	usecase, mockRepo, _, _, _, _, _, mockMFA := newMFATestUsecase()
+
+	enrollment := &auth_entity.MFAEnrollment{Secret: "SECRET", URI: "otpauth://totp/x"}
+	mockRepo.On("FindByID", "user-123").Return(&userEntity.User{ID: "user-123", Email: "test@example.com"}, nil)
+	mockMFA.On("Enroll", "user-123", "test@example.com").Return(enrollment, nil)
+
+	result, err := usecase.EnrollMFA("user-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, enrollment, result)
+}
+
+func TestAuthUsecase_ConfirmMFA(t *testing.T) {
+	usecase, _, _, _, _, _, _, mockMFA := newMFATestUsecase()
+
+	mockMFA.On("Confirm", "user-123", "123456").Return([]string{"aaaa-bbbb"}, nil)
+
+	codes, err := usecase.ConfirmMFA("user-123", "123456")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"aaaa-bbbb"}, codes)
+}
+
+type MockLoginThrottle struct {
+	mock.Mock
+}
+
+func (m *MockLoginThrottle) Check(email, clientIP string) error {
+	args := m.Called(email, clientIP)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottle) RecordFailure(email, clientIP string) error {
+	args := m.Called(email, clientIP)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottle) RecordSuccess(email, clientIP string) error {
+	args := m.Called(email, clientIP)
+	return args.Error(0)
+}
+
+func (m *MockLoginThrottle) Unlock(email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+func newThrottleTestUsecase() (*AuthUsecase, *MockUserRepository, *MockPermissionService, *MockTokenManager, *MockBcrypt, *MockRefreshTokenRepository, *MockSecureTokenGenerator, *MockLoginThrottle) {
+	mockRepo := new(MockUserRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockThrottle := new(MockLoginThrottle)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	}, WithLoginThrottle(mockThrottle))
+
+	return usecase, mockRepo, mockPermissions, mockTokenManager, mockBcrypt, mockRefreshRepo, mockGenerator, mockThrottle
+}
+
+func TestAuthUsecase_Login_RejectsFalseComparisonWithoutError(t *testing.T) {
+	usecase, mockRepo, _, mockTokenManager, mockBcrypt, _, _ := newReferenceTestUsecase()
+
+	mockRepo.On("FindByEmail", "test@example.com").Return(&userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(false, nil)
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidCredentials)
+	assert.Nil(t, result)
+	mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+}
+
+func TestAuthUsecase_Login_UnknownEmailMatchesWrongPassword(t *testing.T) {
+	usecase, mockRepo, _, _, mockBcrypt, _, _ := newReferenceTestUsecase()
+
+	mockRepo.On("FindByEmail", "known@example.com").Return(&userEntity.User{ID: "user-123", Email: "known@example.com", Password: "hashed"}, nil)
+	mockRepo.On("FindByEmail", "unknown@example.com").Return(nil, errors.New("not found"))
+	mockBcrypt.On("Hash", mock.Anything).Return("dummy-hash", nil).Once()
+	mockBcrypt.On("HashComparer", "wrong", mock.Anything).Return(false, errors.New("password mismatch"))
+
+	_, knownErr := usecase.Login("known@example.com", "wrong", auth_entity.ClientInfo{})
+	_, unknownErr := usecase.Login("unknown@example.com", "wrong", auth_entity.ClientInfo{})
+	_, _ = usecase.Login("unknown@example.com", "wrong", auth_entity.ClientInfo{})
+
+	assert.Equal(t, knownErr, unknownErr)
+	mockBcrypt.AssertNumberOfCalls(t, "HashComparer", 3)
+	mockBcrypt.AssertNumberOfCalls(t, "Hash", 1)
+}
+
+func TestAuthUsecase_Login_Throttled(t *testing.T) {
+	usecase, mockRepo, _, _, mockBcrypt, _, _, mockThrottle := newThrottleTestUsecase()
+
+	throttled := &auth_entity.LoginThrottledError{RetryAfter: time.Minute, Locked: true}
+	mockThrottle.On("Check", "test@example.com", "10.0.0.1").Return(throttled)
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{IP: "10.0.0.1"})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, auth_entity.ErrTooManyLoginAttempts)
+	mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+	mockBcrypt.AssertNotCalled(t, "HashComparer", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Login_RecordsFailure(t *testing.T) {
+	usecase, mockRepo, _, _, mockBcrypt, _, _, mockThrottle := newThrottleTestUsecase()
+
+	mockThrottle.On("Check", "test@example.com", "10.0.0.1").Return(nil)
+	mockRepo.On("FindByEmail", "test@example.com").Return(&userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}, nil)
+	mockBcrypt.On("HashComparer", "wrong", "hashed").Return(false, errors.New("password mismatch"))
+	mockThrottle.On("RecordFailure", "test@example.com", "10.0.0.1").Return(nil)
+
+	_, err := usecase.Login("test@example.com", "wrong", auth_entity.ClientInfo{IP: "10.0.0.1"})
+
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidCredentials)
+	mockThrottle.AssertExpectations(t)
+	mockThrottle.AssertNotCalled(t, "RecordSuccess", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Login_RecordFailureError(t *testing.T) {
+	usecase, mockRepo, _, _, mockBcrypt, _, _, mockThrottle := newThrottleTestUsecase()
+
+	mockThrottle.On("Check", "test@example.com", "10.0.0.1").Return(nil)
+	mockRepo.On("FindByEmail", "test@example.com").Return(nil, errors.New("not found"))
+	mockBcrypt.On("Hash", mock.Anything).Return("dummy-hash", nil)
+	mockBcrypt.On("HashComparer", "wrong", "dummy-hash").Return(false, errors.New("password mismatch"))
+	mockThrottle.On("RecordFailure", "test@example.com", "10.0.0.1").Return(errors.New("failed to record login attempt: db down"))
+
+	_, err := usecase.Login("test@example.com", "wrong", auth_entity.ClientInfo{IP: "10.0.0.1"})
+
+	assert.ErrorContains(t, err, "failed to record login attempt")
+}
+
+func TestAuthUsecase_Login_RecordsSuccess(t *testing.T) {
+	usecase, mockRepo, mockPermissions, mockTokenManager, mockBcrypt, mockRefreshRepo, mockGenerator, mockThrottle := newThrottleTestUsecase()
+
+	mockThrottle.On("Check", "test@example.com", "10.0.0.1").Return(nil)
+	mockRepo.On("FindByEmail", "test@example.com").Return(&userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockThrottle.On("RecordSuccess", "test@example.com", "10.0.0.1").Return(nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, nil), nil)
+	mockTokenManager.On("Sign", mock.Anything).Return("jwt.token", nil)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.Anything).Return(&auth_entity.RefreshToken{}, nil)
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{IP: "10.0.0.1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt.token", result.Tokens.AccessToken)
+	mockThrottle.AssertExpectations(t)
+}
+
+func TestAuthUsecase_UnlockAccount(t *testing.T) {
+	usecase, mockRepo, _, _, _, _, _, mockThrottle := newThrottleTestUsecase()
+
+	mockRepo.On("FindByID", "user-123").Return(&userEntity.User{ID: "user-123", Email: "test@example.com"}, nil)
+	mockThrottle.On("Unlock", "test@example.com").Return(nil)
+
+	assert.NoError(t, usecase.UnlockAccount("user-123"))
+	mockThrottle.AssertExpectations(t)
+}
+
+func TestAuthUsecase_UnlockAccount_UserNotFound(t *testing.T) {
+	usecase, mockRepo, _, _, _, _, _, mockThrottle := newThrottleTestUsecase()
+
+	mockRepo.On("FindByID", "missing").Return(nil, errors.New("not found"))
+
+	assert.EqualError(t, usecase.UnlockAccount("missing"), "user not found")
+	mockThrottle.AssertNotCalled(t, "Unlock", mock.Anything)
+}
+
+func TestAuthUsecase_Login_RequiresVerifiedEmail(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockTokenManager := new(MockTokenManager)
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      new(MockPermissionService),
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    new(MockRefreshTokenRepository),
+		TokenGenerator:   new(MockSecureTokenGenerator),
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	}, WithEmailVerification())
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, auth_entity.ErrEmailNotVerified)
+	mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+}
+
+func TestAuthUsecase_Login_VerifiedEmailPasses(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockBcrypt := new(MockBcrypt)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	}, WithEmailVerification())
+
+	verifiedAt := time.Now()
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed", EmailVerifiedAt: &verifiedAt}
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, nil), nil)
+	mockTokenManager.On("Sign", mock.Anything).Return("jwt.token", nil)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.Anything).Return(&auth_entity.RefreshToken{}, nil)
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt.token", result.Tokens.AccessToken)
+}
+
+type MockRehashingBcrypt struct {
+	MockBcrypt
+}
+
+func (m *MockRehashingBcrypt) NeedsRehash(hash string) bool {
+	return m.Called(hash).Bool(0)
+}
+
+func newRehashTestUsecase() (*AuthUsecase, *MockUserRepository, *MockRehashingBcrypt) {
+	mockRepo := new(MockUserRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockHasher := new(MockRehashingBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, nil), nil)
+	mockTokenManager.On("Sign", mock.Anything).Return("jwt.token", nil)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.Anything).Return(&auth_entity.RefreshToken{}, nil)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockHasher,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	})
+	return usecase, mockRepo, mockHasher
+}
+
+func TestAuthUsecase_Login_RehashesWeakHash(t *testing.T) {
+	usecase, mockRepo, mockHasher := newRehashTestUsecase()
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "$2a$10$legacy"}
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockHasher.On("HashComparer", "password123", "$2a$10$legacy").Return(true, nil)
+	mockHasher.On("NeedsRehash", "$2a$10$legacy").Return(true)
+	mockHasher.On("Hash", "password123").Return("$argon2id$upgraded", nil)
+	mockRepo.On("Update", "user-123", mock.MatchedBy(func(u *userEntity.User) bool {
+		return u.Password == "$argon2id$upgraded"
+	})).Return(user, nil)
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Tokens)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Login_KeepsCurrentHash(t *testing.T) {
+	usecase, mockRepo, mockHasher := newRehashTestUsecase()
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "$argon2id$current"}
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockHasher.On("HashComparer", "password123", "$argon2id$current").Return(true, nil)
+	mockHasher.On("NeedsRehash", "$argon2id$current").Return(false)
+
+	_, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	mockHasher.AssertNotCalled(t, "Hash", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestAuthUsecase_Login_RehashFailureDoesNotFailLogin(t *testing.T) {
+	usecase, mockRepo, mockHasher := newRehashTestUsecase()
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "$2a$10$legacy"}
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockHasher.On("HashComparer", "password123", "$2a$10$legacy").Return(true, nil)
+	mockHasher.On("NeedsRehash", "$2a$10$legacy").Return(true)
+	mockHasher.On("Hash", "password123").Return("$argon2id$upgraded", nil)
+	mockRepo.On("Update", "user-123", mock.Anything).Return(nil, errors.New("db error"))
+
+	result, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Tokens)
+}
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Save(s *auth_entity.Session) (*auth_entity.Session, error) {
+	args := m.Called(s)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) FindByID(id string) (*auth_entity.Session, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) FindActiveByUser(userID string, since time.Time) ([]*auth_entity.Session, error) {
+	args := m.Called(userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*auth_entity.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Touch(id string, seenAt time.Time) error {
+	return m.Called(id, seenAt).Error(0)
+}
+
+func (m *MockSessionRepository) End(id string, endedAt time.Time) error {
+	return m.Called(id, endedAt).Error(0)
+}
+
+func (m *MockSessionRepository) EndAllForUser(userID string, endedAt time.Time) error {
+	return m.Called(userID, endedAt).Error(0)
+}
+
+var sessionNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestAuthUsecase_Login_RecordsSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	mockSessions := new(MockSessionRepository)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      mockRevocations,
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	}, WithSessions(mockSessions))
+	usecase.now = func() time.Time { return sessionNow }
+
+	user := &userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}
+	client := auth_entity.ClientInfo{IP: "10.0.0.1", UserAgent: "curl/8.4.0"}
+
+	var familyID string
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, nil), nil)
+	mockTokenManager.On("Sign", mock.MatchedBy(func(claims map[string]interface{}) bool {
+		familyID, _ = claims["sid"].(string)
+		return familyID != ""
+	})).Return("jwt.token", nil)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.Anything).Return(&auth_entity.RefreshToken{}, nil)
+	mockSessions.On("Save", mock.MatchedBy(func(s *auth_entity.Session) bool {
+		return s.ID == familyID && s.UserID == "user-123" && s.IP == "10.0.0.1" && s.Device == "curl" && s.LastSeenAt.Equal(sessionNow)
+	})).Return(&auth_entity.Session{}, nil)
+
+	result, err := usecase.Login("test@example.com", "password123", client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt.token", result.Tokens.AccessToken)
+	mockSessions.AssertExpectations(t)
+	mockRefreshRepo.AssertCalled(t, "Save", mock.MatchedBy(func(rt *auth_entity.RefreshToken) bool {
+		return rt.FamilyID == familyID
+	}))
+}
+
+func TestAuthUsecase_Refresh_TouchesSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	mockSessions := new(MockSessionRepository)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      mockRevocations,
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	}, WithSessions(mockSessions))
+	usecase.now = func() time.Time { return sessionNow }
+
+	stored := auth_entity.NewRefreshToken("rt-1", "user-123", "family-1", "old-hash", sessionNow.Add(time.Hour))
+
+	mockGenerator.On("Hash", "old-token").Return("old-hash")
+	mockRefreshRepo.On("FindByTokenHash", "old-hash").Return(stored, nil)
+	mockRefreshRepo.On("MarkUsed", "rt-1", sessionNow).Return(true, nil)
+	mockRepo.On("FindByID", "user-123").Return(&userEntity.User{ID: "user-123"}, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, nil), nil)
+	mockTokenManager.On("Sign", mock.MatchedBy(func(claims map[string]interface{}) bool {
+		return claims["sid"] == "family-1"
+	})).Return("new-access", nil)
+	mockGenerator.On("Generate").Return("new-token", "new-hash", nil)
+	mockRefreshRepo.On("Save", mock.Anything).Return(&auth_entity.RefreshToken{}, nil)
+	mockSessions.On("Touch", "family-1", sessionNow).Return(nil)
+
+	_, err := usecase.Refresh("old-token")
+
+	assert.NoError(t, err)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Sessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	mockSessions := new(MockSessionRepository)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      mockRevocations,
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	}, WithSessions(mockSessions))
+	usecase.now = func() time.Time { return sessionNow }
+
+	sessions := []*auth_entity.Session{{ID: "family-1", UserID: "user-123"}}
+
+	mockSessions.On("FindActiveByUser", "user-123", sessionNow.Add(-time.Hour)).Return(sessions, nil)
+
+	result, err := usecase.Sessions("user-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, sessions, result)
+}
+
+func TestAuthUsecase_EndSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	mockSessions := new(MockSessionRepository)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      mockRevocations,
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	}, WithSessions(mockSessions))
+	usecase.now = func() time.Time { return sessionNow }
+
+	mockSessions.On("FindByID", "family-1").Return(&auth_entity.Session{ID: "family-1", UserID: "user-123"}, nil)
+	mockRevocations.On("RevokeToken", "family-1", "user-123", sessionNow.Add(time.Minute)).Return(nil)
+	mockRefreshRepo.On("RevokeFamily", "family-1", sessionNow).Return(nil)
+	mockSessions.On("End", "family-1", sessionNow).Return(nil)
+
+	err := usecase.EndSession("user-123", "family-1")
+
+	assert.NoError(t, err)
+	mockRevocations.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthUsecase_EndSession_NotFound(t *testing.T) {
+	endedAt := sessionNow.Add(-time.Minute)
+	cases := map[string]*auth_entity.Session{
+		"another user's session": {ID: "family-1", UserID: "user-999"},
+		"already ended":          {ID: "family-1", UserID: "user-123", EndedAt: &endedAt},
+	}
+
+	for name, session := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockPermissions := new(MockPermissionService)
+			mockTokenManager := new(MockTokenManager)
+			mockBcrypt := new(MockBcrypt)
+			mockRefreshRepo := new(MockRefreshTokenRepository)
+			mockGenerator := new(MockSecureTokenGenerator)
+			mockRevocations := new(MockRevocationStore)
+			mockSessions := new(MockSessionRepository)
+
+			usecase := NewAuthUsecase(AuthDependencies{
+				Users:            mockRepo,
+				Permissions:      mockPermissions,
+				TokenManager:     mockTokenManager,
+				PasswordHasher:   mockBcrypt,
+				RefreshTokens:    mockRefreshRepo,
+				TokenGenerator:   mockGenerator,
+				Revocations:      mockRevocations,
+				Events:           shared_event.NewDispatcher(),
+				AccessExpiresIn:  time.Minute,
+				RefreshExpiresIn: time.Hour,
+				PermissionMode:   auth_entity.PermissionModeEmbedded,
+			}, WithSessions(mockSessions))
+			usecase.now = func() time.Time { return sessionNow }
+
+			mockSessions.On("FindByID", "family-1").Return(session, nil)
+
+			err := usecase.EndSession("user-123", "family-1")
+
+			assert.ErrorIs(t, err, auth_entity.ErrSessionNotFound)
+			mockRevocations.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthUsecase_Logout_EndsSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	mockSessions := new(MockSessionRepository)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      mockRevocations,
+		Events:           shared_event.NewDispatcher(),
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	}, WithSessions(mockSessions))
+	usecase.now = func() time.Time { return sessionNow }
+
+	stored := auth_entity.NewRefreshToken("rt-1", "user-123", "family-1", "hash", sessionNow.Add(time.Hour))
+
+	mockRevocations.On("RevokeToken", "jti-1", "user-123", sessionNow).Return(nil)
+	mockGenerator.On("Hash", "refresh-token").Return("hash")
+	mockRefreshRepo.On("FindByTokenHash", "hash").Return(stored, nil)
+	mockRefreshRepo.On("RevokeFamily", "family-1", sessionNow).Return(nil)
+	mockSessions.On("End", "family-1", sessionNow).Return(nil)
+
+	err := usecase.Logout("jti-1", "user-123", sessionNow, "refresh-token")
+
+	assert.NoError(t, err)
+	mockSessions.AssertExpectations(t)
+}
+
+func newLoginEventsTestUsecase(opts ...Option) (*AuthUsecase, *MockUserRepository, *MockPermissionService, *MockTokenManager, *MockBcrypt, *MockRefreshTokenRepository, *MockSecureTokenGenerator, *MockDispatcher) {
+	mockRepo := new(MockUserRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockBcrypt := new(MockBcrypt)
+	mockRefreshRepo := new(MockRefreshTokenRepository)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockDispatcher := new(MockDispatcher)
+
+	usecase := NewAuthUsecase(AuthDependencies{
+		Users:            mockRepo,
+		Permissions:      mockPermissions,
+		TokenManager:     mockTokenManager,
+		PasswordHasher:   mockBcrypt,
+		RefreshTokens:    mockRefreshRepo,
+		TokenGenerator:   mockGenerator,
+		Revocations:      new(MockRevocationStore),
+		Events:           mockDispatcher,
+		AccessExpiresIn:  time.Minute,
+		RefreshExpiresIn: time.Hour,
+		PermissionMode:   auth_entity.PermissionModeEmbedded,
+	}, opts...)
+
+	return usecase, mockRepo, mockPermissions, mockTokenManager, mockBcrypt, mockRefreshRepo, mockGenerator, mockDispatcher
+}
+
+func TestAuthUsecase_Login_DispatchesSucceeded(t *testing.T) {
+	usecase, mockRepo, mockPermissions, mockTokenManager, mockBcrypt, mockRefreshRepo, mockGenerator, mockDispatcher := newLoginEventsTestUsecase()
+
+	mockRepo.On("FindByEmail", "test@example.com").Return(&userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}, nil)
+	mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+	mockPermissions.On("Refresh", "user-123").Return(permissionEntity.NewEffectivePermissions("user-123", 0, nil), nil)
+	mockTokenManager.On("Sign", mock.Anything).Return("access-token", nil)
+	mockGenerator.On("Generate").Return("refresh-token", "refresh-hash", nil)
+	mockRefreshRepo.On("Save", mock.Anything).Return(&auth_entity.RefreshToken{}, nil)
+	mockDispatcher.On("Dispatch", mock.MatchedBy(func(e *auth_event.LoginSucceededEvent) bool {
+		return e.UserID == "user-123" && e.Email == "test@example.com" && e.SessionID != "" && e.IP == "10.0.0.1" && e.UserAgent == "curl"
+	})).Return()
+
+	_, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{IP: "10.0.0.1", UserAgent: "curl"})
+
+	assert.NoError(t, err)
+	mockDispatcher.AssertExpectations(t)
+}
+
+func TestAuthUsecase_Login_DispatchesFailed(t *testing.T) {
+	t.Run("should report invalid credentials without the user id", func(t *testing.T) {
+		usecase, mockRepo, _, _, mockBcrypt, _, _, mockDispatcher := newLoginEventsTestUsecase()
+
+		mockRepo.On("FindByEmail", "test@example.com").Return(&userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}, nil)
+		mockBcrypt.On("HashComparer", "wrong", "hashed").Return(false, nil)
+		mockDispatcher.On("Dispatch", mock.MatchedBy(func(e *auth_event.LoginFailedEvent) bool {
+			return e.Email == "test@example.com" && e.UserID == "" && e.Reason == auth_event.LoginFailureInvalidCredentials
+		})).Return()
+
+		_, err := usecase.Login("test@example.com", "wrong", auth_entity.ClientInfo{})
+
+		assert.ErrorIs(t, err, auth_entity.ErrInvalidCredentials)
+		mockDispatcher.AssertExpectations(t)
+	})
+
+	t.Run("should report throttled attempts", func(t *testing.T) {
+		mockThrottle := new(MockLoginThrottle)
+		usecase, mockRepo, _, _, _, _, _, mockDispatcher := newLoginEventsTestUsecase(WithLoginThrottle(mockThrottle))
+
+		mockThrottle.On("Check", "test@example.com", "10.0.0.1").Return(&auth_entity.LoginThrottledError{RetryAfter: time.Minute, Locked: true})
+		mockDispatcher.On("Dispatch", mock.MatchedBy(func(e *auth_event.LoginFailedEvent) bool {
+			return e.Email == "test@example.com" && e.Reason == auth_event.LoginFailureThrottled && e.IP == "10.0.0.1"
+		})).Return()
+
+		_, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{IP: "10.0.0.1"})
+
+		assert.ErrorIs(t, err, auth_entity.ErrTooManyLoginAttempts)
+		mockDispatcher.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+	})
+
+	t.Run("should not report throttle store errors as failed logins", func(t *testing.T) {
+		mockThrottle := new(MockLoginThrottle)
+		usecase, _, _, _, _, _, _, mockDispatcher := newLoginEventsTestUsecase(WithLoginThrottle(mockThrottle))
+
+		mockThrottle.On("Check", "test@example.com", "10.0.0.1").Return(errors.New("db error"))
+
+		_, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{IP: "10.0.0.1"})
+
+		assert.Error(t, err)
+		mockDispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+	})
+
+	t.Run("should report unverified emails with the user id", func(t *testing.T) {
+		usecase, mockRepo, _, mockTokenManager, mockBcrypt, _, _, mockDispatcher := newLoginEventsTestUsecase(WithEmailVerification())
+
+		mockRepo.On("FindByEmail", "test@example.com").Return(&userEntity.User{ID: "user-123", Email: "test@example.com", Password: "hashed"}, nil)
+		mockBcrypt.On("HashComparer", "password123", "hashed").Return(true, nil)
+		mockDispatcher.On("Dispatch", mock.MatchedBy(func(e *auth_event.LoginFailedEvent) bool {
+			return e.UserID == "user-123" && e.Reason == auth_event.LoginFailureEmailNotVerified
+		})).Return()
+
+		_, err := usecase.Login("test@example.com", "password123", auth_entity.ClientInfo{})
+
+		assert.ErrorIs(t, err, auth_entity.ErrEmailNotVerified)
+		mockDispatcher.AssertExpectations(t)
+		mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+	})
+}
@@ -0,0 +1,477 @@
+package auth_usecase
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_revocation "github.com/williamkoller/system-education/internal/auth/port/revocation"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+)
+
+// OAuthUsecase is the authorization server for third-party clients. Access
+// tokens are signed by the same TokenManager as first-party tokens, so the
+// auth middleware accepts both, but they carry a client_id and only the
+// caller's grants that fall within the token's scopes. Scopes are
+// "module:action" pairs. No refresh tokens are issued: clients go through
+// the authorization again, which skips the consent step once given.
+type OAuthUsecase struct {
+	clients         port_auth_repository.OAuthClientRepository
+	codes           port_auth_repository.OAuthCodeRepository
+	consents        port_auth_repository.OAuthConsentRepository
+	permissions     port_permission_service.PermissionService
+	jwtTokenManager port_auth_cryptography.TokenManager
+	tokenGenerator  port_auth_cryptography.SecureTokenGenerator
+	revocations     port_auth_revocation.RevocationStore
+	accessExpiresIn time.Duration
+	codeExpiresIn   time.Duration
+	now             func() time.Time
+}
+
+var _ port_auth_usecase.OAuthUsecase = &OAuthUsecase{}
+
+func NewOAuthUsecase(
+	clients port_auth_repository.OAuthClientRepository,
+	codes port_auth_repository.OAuthCodeRepository,
+	consents port_auth_repository.OAuthConsentRepository,
+	permissions port_permission_service.PermissionService,
+	jwtTokenManager port_auth_cryptography.TokenManager,
+	tokenGenerator port_auth_cryptography.SecureTokenGenerator,
+	revocations port_auth_revocation.RevocationStore,
+	accessExpiresIn time.Duration,
+	codeExpiresIn time.Duration,
+) *OAuthUsecase {
+	return &OAuthUsecase{
+		clients:         clients,
+		codes:           codes,
+		consents:        consents,
+		permissions:     permissions,
+		jwtTokenManager: jwtTokenManager,
+		tokenGenerator:  tokenGenerator,
+		revocations:     revocations,
+		accessExpiresIn: accessExpiresIn,
+		codeExpiresIn:   codeExpiresIn,
+		now:             time.Now,
+	}
+}
+
+// RegisterClient validates and stores a client. Confidential clients get a
+// secret, returned only here.
+func (o *OAuthUsecase) RegisterClient(ownerID string, input *auth_entity.OAuthClient) (*auth_entity.OAuthClientRegistration, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, invalidRequest("name is required")
+	}
+	if len(input.GrantTypes) == 0 {
+		return nil, invalidRequest("at least one grant type is required")
+	}
+	for _, grantType := range input.GrantTypes {
+		switch grantType {
+		case auth_entity.GrantTypeAuthorizationCode:
+			if len(input.RedirectURIs) == 0 {
+				return nil, invalidRequest("authorization_code clients need a redirect uri")
+			}
+		case auth_entity.GrantTypeClientCredentials:
+			if !input.Confidential {
+				return nil, invalidRequest("client_credentials is only available to confidential clients")
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s", auth_entity.ErrOAuthUnsupportedGrantType, grantType)
+		}
+	}
+	for _, redirectURI := range input.RedirectURIs {
+		if err := auth_entity.ValidateRedirectURI(redirectURI); err != nil {
+			return nil, err
+		}
+	}
+	scopes, err := auth_entity.ParseScope(strings.Join(input.Scopes, " "))
+	if err != nil {
+		return nil, err
+	}
+	if len(scopes) == 0 {
+		return nil, invalidRequest("at least one scope is required")
+	}
+
+	client := &auth_entity.OAuthClient{
+		ID:           uuid.New().String(),
+		ClientID:     uuid.New().String(),
+		Name:         strings.TrimSpace(input.Name),
+		RedirectURIs: input.RedirectURIs,
+		GrantTypes:   input.GrantTypes,
+		Scopes:       scopes,
+		Confidential: input.Confidential,
+		OwnerID:      ownerID,
+		CreatedAt:    o.now(),
+	}
+
+	var secret string
+	if client.Confidential {
+		secret, client.SecretHash, err = o.tokenGenerator.Generate()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate client secret: %w", err)
+		}
+	}
+
+	saved, err := o.clients.Save(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save client: %w", err)
+	}
+
+	return &auth_entity.OAuthClientRegistration{Client: saved, ClientSecret: secret}, nil
+}
+
+func (o *OAuthUsecase) Authorize(userID string, req *auth_entity.OAuthAuthorizationRequest) (*auth_entity.OAuthAuthorization, error) {
+	client, scopes, err := o.validateAuthorization(req)
+	if err != nil {
+		return nil, err
+	}
+
+	consent, err := o.consents.Find(userID, client.ClientID)
+	if err != nil && !errors.Is(err, auth_entity.ErrOAuthConsentNotFound) {
+		return nil, fmt.Errorf("failed to find consent: %w", err)
+	}
+	if !consent.Covers(scopes) {
+		return &auth_entity.OAuthAuthorization{Client: client, Scopes: scopes, ConsentRequired: true}, nil
+	}
+
+	return o.issueCode(userID, client, scopes, req)
+}
+
+func (o *OAuthUsecase) Decide(userID string, req *auth_entity.OAuthAuthorizationRequest, approved bool) (*auth_entity.OAuthAuthorization, error) {
+	client, scopes, err := o.validateAuthorization(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !approved {
+		return &auth_entity.OAuthAuthorization{
+			Client:     client,
+			Scopes:     scopes,
+			RedirectTo: redirectWith(req.RedirectURI, url.Values{"error": {auth_entity.ErrOAuthAccessDenied.Code}}, req.State),
+		}, nil
+	}
+
+	now := o.now()
+	consented := scopes
+	if consent, err := o.consents.Find(userID, client.ClientID); err == nil {
+		consented, _ = auth_entity.ParseScope(auth_entity.ScopeString(append(consent.Scopes, scopes...)))
+	}
+	if err := o.consents.Save(&auth_entity.OAuthConsent{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		ClientID:  client.ClientID,
+		Scopes:    consented,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save consent: %w", err)
+	}
+
+	return o.issueCode(userID, client, scopes, req)
+}
+
+func (o *OAuthUsecase) Token(req *auth_entity.OAuthTokenRequest) (*auth_entity.OAuthToken, error) {
+	switch req.GrantType {
+	case auth_entity.GrantTypeAuthorizationCode:
+		return o.exchangeCode(req)
+	case auth_entity.GrantTypeClientCredentials:
+		return o.clientCredentials(req)
+	default:
+		return nil, auth_entity.ErrOAuthUnsupportedGrantType
+	}
+}
+
+// Introspect describes any token signed by us (RFC 7662) to an
+// authenticated confidential client. Invalid, expired and revoked tokens
+// are reported inactive rather than as errors.
+func (o *OAuthUsecase) Introspect(clientID, clientSecret, token string) (*auth_entity.OAuthIntrospection, error) {
+	client, err := o.authenticateClient(clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if !client.Confidential {
+		return nil, auth_entity.ErrOAuthUnauthorizedClient
+	}
+
+	inactive := &auth_entity.OAuthIntrospection{Active: false}
+
+	claims, err := o.jwtTokenManager.Verify(token)
+	if err != nil {
+		return inactive, nil
+	}
+
+	jti, _ := claims["jti"].(string)
+	userID, _ := claims["user_id"].(string)
+	issuedAt := auth_middleware.ClaimTime(claims, "iat")
+
+	revoked, err := o.revocations.IsRevoked(jti, userID, issuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check revocation: %w", err)
+	}
+	if revoked {
+		return inactive, nil
+	}
+
+	tokenClientID, _ := claims["client_id"].(string)
+	scope, _ := claims["scope"].(string)
+	subject := userID
+	if subject == "" {
+		subject = tokenClientID
+	}
+
+	return &auth_entity.OAuthIntrospection{
+		Active:    true,
+		Scope:     scope,
+		ClientID:  tokenClientID,
+		Subject:   subject,
+		TokenID:   jti,
+		IssuedAt:  issuedAt,
+		ExpiresAt: auth_middleware.ClaimTime(claims, "exp"),
+	}, nil
+}
+
+func (o *OAuthUsecase) Consents(userID string) ([]*auth_entity.OAuthConsent, error) {
+	consents, err := o.consents.FindByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find consents: %w", err)
+	}
+	return consents, nil
+}
+
+// RevokeConsent makes the next authorization ask again. Tokens already
+// issued stay valid until they expire.
+func (o *OAuthUsecase) RevokeConsent(userID, clientID string) error {
+	return o.consents.Delete(userID, clientID)
+}
+
+func (o *OAuthUsecase) validateAuthorization(req *auth_entity.OAuthAuthorizationRequest) (*auth_entity.OAuthClient, []string, error) {
+	client, err := o.clients.FindByClientID(req.ClientID)
+	if err != nil {
+		if errors.Is(err, auth_entity.ErrOAuthClientNotFound) {
+			return nil, nil, auth_entity.ErrOAuthInvalidClient
+		}
+		return nil, nil, fmt.Errorf("failed to find client: %w", err)
+	}
+	if !client.AllowsRedirectURI(req.RedirectURI) {
+		return nil, nil, invalidRequest("redirect_uri is not registered for this client")
+	}
+	if req.ResponseType != "code" {
+		return nil, nil, auth_entity.ErrOAuthUnsupportedResponse
+	}
+	if !client.AllowsGrantType(auth_entity.GrantTypeAuthorizationCode) {
+		return nil, nil, auth_entity.ErrOAuthUnauthorizedClient
+	}
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != "S256" {
+		return nil, nil, invalidRequest("a PKCE code_challenge with method S256 is required")
+	}
+
+	requested, err := auth_entity.ParseScope(req.Scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	scopes, err := client.AllowedScopes(requested)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, scopes, nil
+}
+
+func (o *OAuthUsecase) issueCode(userID string, client *auth_entity.OAuthClient, scopes []string, req *auth_entity.OAuthAuthorizationRequest) (*auth_entity.OAuthAuthorization, error) {
+	code, codeHash, err := o.tokenGenerator.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate authorization code: %w", err)
+	}
+
+	now := o.now()
+	if err := o.codes.DeleteExpired(now); err != nil {
+		log.Printf("failed to delete expired authorization codes: %v", err)
+	}
+
+	if err := o.codes.Save(&auth_entity.OAuthAuthorizationCode{
+		ID:            uuid.New().String(),
+		CodeHash:      codeHash,
+		ClientID:      client.ClientID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+		ExpiresAt:     now.Add(o.codeExpiresIn),
+		CreatedAt:     now,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save authorization code: %w", err)
+	}
+
+	return &auth_entity.OAuthAuthorization{
+		Client:     client,
+		Scopes:     scopes,
+		RedirectTo: redirectWith(req.RedirectURI, url.Values{"code": {code}}, req.State),
+	}, nil
+}
+
+func (o *OAuthUsecase) exchangeCode(req *auth_entity.OAuthTokenRequest) (*auth_entity.OAuthToken, error) {
+	client, err := o.authenticateClient(req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if !client.AllowsGrantType(auth_entity.GrantTypeAuthorizationCode) {
+		return nil, auth_entity.ErrOAuthUnauthorizedClient
+	}
+	if req.Code == "" || req.CodeVerifier == "" {
+		return nil, invalidRequest("code and code_verifier are required")
+	}
+
+	code, err := o.codes.Consume(o.tokenGenerator.Hash(req.Code))
+	if err != nil {
+		return nil, err
+	}
+	if code.ClientID != client.ClientID || code.RedirectURI != req.RedirectURI || code.IsExpired(o.now()) {
+		return nil, auth_entity.ErrOAuthInvalidGrant
+	}
+	if subtle.ConstantTimeCompare([]byte(auth_entity.PKCEChallenge(req.CodeVerifier)), []byte(code.CodeChallenge)) != 1 {
+		return nil, fmt.Errorf("%w: code_verifier does not match", auth_entity.ErrOAuthInvalidGrant)
+	}
+
+	return o.issueToken(client.ClientID, code.UserID, code.UserID, code.Scopes)
+}
+
+// clientCredentials issues a token for the client itself. It acts with its
+// owner's current permissions narrowed to the requested scopes, so a client
+// never holds more than the person who registered it.
+func (o *OAuthUsecase) clientCredentials(req *auth_entity.OAuthTokenRequest) (*auth_entity.OAuthToken, error) {
+	client, err := o.authenticateClient(req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if !client.Confidential || !client.AllowsGrantType(auth_entity.GrantTypeClientCredentials) {
+		return nil, auth_entity.ErrOAuthUnauthorizedClient
+	}
+
+	requested, err := auth_entity.ParseScope(req.Scope)
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := client.AllowedScopes(requested)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.issueToken(client.ClientID, "", client.OwnerID, scopes)
+}
+
+// authenticateClient checks the secret of confidential clients. Public
+// clients are only identified; PKCE protects their codes instead.
+func (o *OAuthUsecase) authenticateClient(clientID, clientSecret string) (*auth_entity.OAuthClient, error) {
+	if clientID == "" {
+		return nil, auth_entity.ErrOAuthInvalidClient
+	}
+
+	client, err := o.clients.FindByClientID(clientID)
+	if err != nil {
+		if errors.Is(err, auth_entity.ErrOAuthClientNotFound) {
+			return nil, auth_entity.ErrOAuthInvalidClient
+		}
+		return nil, fmt.Errorf("failed to find client: %w", err)
+	}
+
+	if client.Confidential {
+		hash := o.tokenGenerator.Hash(clientSecret)
+		if clientSecret == "" || subtle.ConstantTimeCompare([]byte(hash), []byte(client.SecretHash)) != 1 {
+			return nil, auth_entity.ErrOAuthInvalidClient
+		}
+	}
+
+	return client, nil
+}
+
+// issueToken signs an access token for userID (empty for client
+// credentials) holding the grants of permissionsOf within scopes. Grants
+// are always embedded: a reference token would resolve the user's full
+// permissions and ignore the scopes.
+func (o *OAuthUsecase) issueToken(clientID, userID, permissionsOf string, scopes []string) (*auth_entity.OAuthToken, error) {
+	effective, err := o.permissions.Refresh(permissionsOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+
+	grants, modules, actions := scopedClaims(effective, scopes)
+
+	claims := map[string]interface{}{
+		"client_id": clientID,
+		"scope":     auth_entity.ScopeString(scopes),
+		"modules":   modules,
+		"actions":   actions,
+		"grants":    grants,
+	}
+	if userID != "" {
+		claims["user_id"] = userID
+	}
+
+	token, err := o.jwtTokenManager.Sign(claims)
+	if err != nil {
+		return nil, errors.New("error in generate token")
+	}
+
+	return &auth_entity.OAuthToken{AccessToken: token, ExpiresIn: o.accessExpiresIn, Scopes: scopes}, nil
+}
+
+// scopedClaims keeps the grants whose module/action pair is in scopes. The
+// flat modules and actions claims come from EffectivePermissions.Modules and
+// Actions, narrowed to the scoped pairs the grants actually allow, so a
+// restricted or denied grant never shows up in them.
+func scopedClaims(effective *permission_entity.EffectivePermissions, scopes []string) (grants, modules, actions []string) {
+	held := effective.Grants()
+	scopedModules, scopedActions := map[string]bool{}, map[string]bool{}
+
+	grants = []string{}
+	for _, grant := range held {
+		if !slices.Contains(scopes, grant.Module+":"+grant.Action) {
+			continue
+		}
+		grants = append(grants, grant.String())
+		if grant.Level == permission_entity.LevelAllowed &&
+			permission_entity.Evaluate(held, []string{grant.Module}, []string{grant.Action}).Allowed {
+			scopedModules[grant.Module] = true
+			scopedActions[grant.Action] = true
+		}
+	}
+
+	return grants, keepOnce(effective.Modules(), scopedModules), keepOnce(effective.Actions(), scopedActions)
+}
+
+// keepOnce returns the values found in keep, in order and without repeats.
+func keepOnce(values []string, keep map[string]bool) []string {
+	kept := []string{}
+	for _, value := range values {
+		if keep[value] && !slices.Contains(kept, value) {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
+
+func redirectWith(redirectURI string, params url.Values, state string) string {
+	if state != "" {
+		params.Set("state", state)
+	}
+	separator := "?"
+	if strings.Contains(redirectURI, "?") {
+		separator = "&"
+	}
+	return redirectURI + separator + params.Encode()
+}
+
+func invalidRequest(message string) error {
+	return &auth_entity.OAuthError{Code: auth_entity.ErrOAuthInvalidRequest.Code, Message: message}
+}
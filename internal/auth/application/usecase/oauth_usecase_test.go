@@ -0,0 +1,587 @@
+package auth_usecase
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+type MockOAuthClientRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthClientRepository) Save(c *auth_entity.OAuthClient) (*auth_entity.OAuthClient, error) {
+	args := m.Called(c)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.OAuthClient), args.Error(1)
+}
+
+func (m *MockOAuthClientRepository) FindByClientID(clientID string) (*auth_entity.OAuthClient, error) {
+	args := m.Called(clientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.OAuthClient), args.Error(1)
+}
+
+type MockOAuthCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthCodeRepository) Save(c *auth_entity.OAuthAuthorizationCode) error {
+	return m.Called(c).Error(0)
+}
+
+func (m *MockOAuthCodeRepository) Consume(codeHash string) (*auth_entity.OAuthAuthorizationCode, error) {
+	args := m.Called(codeHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.OAuthAuthorizationCode), args.Error(1)
+}
+
+func (m *MockOAuthCodeRepository) DeleteExpired(before time.Time) error {
+	return m.Called(before).Error(0)
+}
+
+type MockOAuthConsentRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthConsentRepository) Find(userID, clientID string) (*auth_entity.OAuthConsent, error) {
+	args := m.Called(userID, clientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.OAuthConsent), args.Error(1)
+}
+
+func (m *MockOAuthConsentRepository) Save(c *auth_entity.OAuthConsent) error {
+	return m.Called(c).Error(0)
+}
+
+func (m *MockOAuthConsentRepository) FindByUser(userID string) ([]*auth_entity.OAuthConsent, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*auth_entity.OAuthConsent), args.Error(1)
+}
+
+func (m *MockOAuthConsentRepository) Delete(userID, clientID string) error {
+	return m.Called(userID, clientID).Error(0)
+}
+
+var oauthNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+const lmsRedirect = "https://lms.example.com/callback"
+
+func lmsClient() *auth_entity.OAuthClient {
+	return &auth_entity.OAuthClient{
+		ClientID:     "lms",
+		SecretHash:   "secret-hash",
+		RedirectURIs: []string{lmsRedirect},
+		GrantTypes:   []string{auth_entity.GrantTypeAuthorizationCode, auth_entity.GrantTypeClientCredentials},
+		Scopes:       []string{"roles:read", "users:read", "users:update"},
+		Confidential: true,
+		OwnerID:      "owner-1",
+	}
+}
+
+func authorizationRequest() *auth_entity.OAuthAuthorizationRequest {
+	return &auth_entity.OAuthAuthorizationRequest{
+		ResponseType:        "code",
+		ClientID:            "lms",
+		RedirectURI:         lmsRedirect,
+		Scope:               "users:read",
+		State:               "xyz",
+		CodeChallenge:       auth_entity.PKCEChallenge("verifier"),
+		CodeChallengeMethod: "S256",
+	}
+}
+
+func assertOAuthCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var oauthErr *auth_entity.OAuthError
+	if assert.True(t, errors.As(err, &oauthErr), "expected an OAuthError, got %v", err) {
+		assert.Equal(t, code, oauthErr.Code)
+	}
+}
+
+func userPermissions(userID string) *permissionEntity.EffectivePermissions {
+	return permissionEntity.NewEffectivePermissions(userID, 1, []*permissionEntity.Permission{
+		{Modules: []string{"users"}, Actions: []string{"read", "update", "delete"}, Level: "allowed"},
+		{Modules: []string{"roles"}, Actions: []string{"read"}, Level: "restricted"},
+	})
+}
+
+func TestOAuthUsecase_RegisterClient(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	mockGenerator.On("Generate").Return("client-secret", "secret-hash", nil)
+	mockClients.On("Save", mock.MatchedBy(func(c *auth_entity.OAuthClient) bool {
+		return c.Name == "Payments" && c.OwnerID == "admin-1" && c.SecretHash == "secret-hash" &&
+			c.ClientID != "" && assert.ObjectsAreEqual([]string{"roles:read", "users:read"}, c.Scopes)
+	})).Return(func() *auth_entity.OAuthClient { c := lmsClient(); c.Name = "Payments"; return c }(), nil)
+
+	registration, err := usecase.RegisterClient("admin-1", &auth_entity.OAuthClient{
+		Name:         "Payments",
+		GrantTypes:   []string{auth_entity.GrantTypeClientCredentials},
+		Scopes:       []string{"users:read", "roles:read"},
+		Confidential: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "client-secret", registration.ClientSecret)
+	mockClients.AssertExpectations(t)
+}
+
+func TestOAuthUsecase_RegisterClient_Validation(t *testing.T) {
+	cases := map[string]*auth_entity.OAuthClient{
+		"missing name":              {GrantTypes: []string{"authorization_code"}, RedirectURIs: []string{lmsRedirect}, Scopes: []string{"users:read"}},
+		"no grant types":            {Name: "x", Scopes: []string{"users:read"}},
+		"unknown grant type":        {Name: "x", GrantTypes: []string{"password"}, Scopes: []string{"users:read"}},
+		"public client credentials": {Name: "x", GrantTypes: []string{"client_credentials"}, Scopes: []string{"users:read"}},
+		"code without redirect":     {Name: "x", GrantTypes: []string{"authorization_code"}, Scopes: []string{"users:read"}},
+		"insecure redirect":         {Name: "x", GrantTypes: []string{"authorization_code"}, RedirectURIs: []string{"http://lms.example.com/cb"}, Scopes: []string{"users:read"}},
+		"invalid scope":             {Name: "x", GrantTypes: []string{"authorization_code"}, RedirectURIs: []string{lmsRedirect}, Scopes: []string{"users"}},
+		"no scopes":                 {Name: "x", GrantTypes: []string{"authorization_code"}, RedirectURIs: []string{lmsRedirect}},
+	}
+
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockClients := new(MockOAuthClientRepository)
+			mockCodes := new(MockOAuthCodeRepository)
+			mockConsents := new(MockOAuthConsentRepository)
+			mockPermissions := new(MockPermissionService)
+			mockTokenManager := new(MockTokenManager)
+			mockGenerator := new(MockSecureTokenGenerator)
+			mockRevocations := new(MockRevocationStore)
+			usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+			usecase.now = func() time.Time { return oauthNow }
+
+			_, err := usecase.RegisterClient("admin-1", input)
+
+			var oauthErr *auth_entity.OAuthError
+			assert.True(t, errors.As(err, &oauthErr))
+			mockClients.AssertNotCalled(t, "Save", mock.Anything)
+		})
+	}
+}
+
+func TestOAuthUsecase_Authorize_AsksForConsent(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	mockClients.On("FindByClientID", "lms").Return(lmsClient(), nil)
+	mockConsents.On("Find", "user-1", "lms").Return(nil, auth_entity.ErrOAuthConsentNotFound)
+
+	result, err := usecase.Authorize("user-1", authorizationRequest())
+
+	assert.NoError(t, err)
+	assert.True(t, result.ConsentRequired)
+	assert.Equal(t, []string{"users:read"}, result.Scopes)
+	assert.Empty(t, result.RedirectTo)
+	mockCodes.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestOAuthUsecase_Authorize_IssuesCodeWhenConsented(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	mockClients.On("FindByClientID", "lms").Return(lmsClient(), nil)
+	mockConsents.On("Find", "user-1", "lms").Return(&auth_entity.OAuthConsent{Scopes: []string{"roles:read", "users:read"}}, nil)
+	mockGenerator.On("Generate").Return("the-code", "code-hash", nil)
+	mockCodes.On("DeleteExpired", oauthNow).Return(nil)
+	mockCodes.On("Save", mock.MatchedBy(func(c *auth_entity.OAuthAuthorizationCode) bool {
+		return c.CodeHash == "code-hash" && c.UserID == "user-1" && c.ClientID == "lms" &&
+			c.RedirectURI == lmsRedirect && c.CodeChallenge == auth_entity.PKCEChallenge("verifier") &&
+			c.ExpiresAt.Equal(oauthNow.Add(time.Minute))
+	})).Return(nil)
+
+	result, err := usecase.Authorize("user-1", authorizationRequest())
+
+	assert.NoError(t, err)
+	assert.False(t, result.ConsentRequired)
+	redirect, err := url.Parse(result.RedirectTo)
+	assert.NoError(t, err)
+	assert.Equal(t, "the-code", redirect.Query().Get("code"))
+	assert.Equal(t, "xyz", redirect.Query().Get("state"))
+	mockCodes.AssertExpectations(t)
+}
+
+func TestOAuthUsecase_Authorize_RejectsInvalidRequests(t *testing.T) {
+	cases := map[string]struct {
+		modify func(*auth_entity.OAuthAuthorizationRequest)
+		code   string
+	}{
+		"unknown client":        {func(r *auth_entity.OAuthAuthorizationRequest) { r.ClientID = "ghost" }, "invalid_client"},
+		"unregistered redirect": {func(r *auth_entity.OAuthAuthorizationRequest) { r.RedirectURI = "https://evil.example.com/cb" }, "invalid_request"},
+		"response type":         {func(r *auth_entity.OAuthAuthorizationRequest) { r.ResponseType = "token" }, "unsupported_response_type"},
+		"missing pkce":          {func(r *auth_entity.OAuthAuthorizationRequest) { r.CodeChallenge = "" }, "invalid_request"},
+		"plain pkce":            {func(r *auth_entity.OAuthAuthorizationRequest) { r.CodeChallengeMethod = "plain" }, "invalid_request"},
+		"scope not registered":  {func(r *auth_entity.OAuthAuthorizationRequest) { r.Scope = "users:delete" }, "invalid_scope"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockClients := new(MockOAuthClientRepository)
+			mockCodes := new(MockOAuthCodeRepository)
+			mockConsents := new(MockOAuthConsentRepository)
+			mockPermissions := new(MockPermissionService)
+			mockTokenManager := new(MockTokenManager)
+			mockGenerator := new(MockSecureTokenGenerator)
+			mockRevocations := new(MockRevocationStore)
+			usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+			usecase.now = func() time.Time { return oauthNow }
+
+			mockClients.On("FindByClientID", "lms").Return(lmsClient(), nil)
+			mockClients.On("FindByClientID", "ghost").Return(nil, auth_entity.ErrOAuthClientNotFound)
+			req := authorizationRequest()
+			tc.modify(req)
+
+			_, err := usecase.Authorize("user-1", req)
+
+			assertOAuthCode(t, err, tc.code)
+		})
+	}
+}
+
+func TestOAuthUsecase_Decide_ApproveMergesConsent(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	mockClients.On("FindByClientID", "lms").Return(lmsClient(), nil)
+	mockConsents.On("Find", "user-1", "lms").Return(&auth_entity.OAuthConsent{Scopes: []string{"roles:read"}}, nil)
+	mockConsents.On("Save", mock.MatchedBy(func(c *auth_entity.OAuthConsent) bool {
+		return c.UserID == "user-1" && c.ClientID == "lms" &&
+			assert.ObjectsAreEqual([]string{"roles:read", "users:read"}, c.Scopes)
+	})).Return(nil)
+	mockGenerator.On("Generate").Return("the-code", "code-hash", nil)
+	mockCodes.On("DeleteExpired", oauthNow).Return(nil)
+	mockCodes.On("Save", mock.Anything).Return(nil)
+
+	result, err := usecase.Decide("user-1", authorizationRequest(), true)
+
+	assert.NoError(t, err)
+	assert.Contains(t, result.RedirectTo, "code=the-code")
+	mockConsents.AssertExpectations(t)
+}
+
+func TestOAuthUsecase_Decide_Deny(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	mockClients.On("FindByClientID", "lms").Return(lmsClient(), nil)
+
+	result, err := usecase.Decide("user-1", authorizationRequest(), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, lmsRedirect+"?error=access_denied&state=xyz", result.RedirectTo)
+	mockConsents.AssertNotCalled(t, "Save", mock.Anything)
+	mockCodes.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func codeRequest() *auth_entity.OAuthTokenRequest {
+	return &auth_entity.OAuthTokenRequest{
+		GrantType:    auth_entity.GrantTypeAuthorizationCode,
+		ClientID:     "lms",
+		ClientSecret: "client-secret",
+		Code:         "the-code",
+		RedirectURI:  lmsRedirect,
+		CodeVerifier: "verifier",
+	}
+}
+
+func storedCode() *auth_entity.OAuthAuthorizationCode {
+	return &auth_entity.OAuthAuthorizationCode{
+		ClientID:      "lms",
+		UserID:        "user-1",
+		RedirectURI:   lmsRedirect,
+		Scopes:        []string{"roles:read", "users:read"},
+		CodeChallenge: auth_entity.PKCEChallenge("verifier"),
+		ExpiresAt:     oauthNow.Add(time.Minute),
+	}
+}
+
+func expectOAuthClientAuth(clients *MockOAuthClientRepository, generator *MockSecureTokenGenerator) {
+	clients.On("FindByClientID", "lms").Return(lmsClient(), nil)
+	generator.On("Hash", "client-secret").Return("secret-hash")
+}
+
+func TestOAuthUsecase_Token_AuthorizationCode(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	expectOAuthClientAuth(mockClients, mockGenerator)
+	mockGenerator.On("Hash", "the-code").Return("code-hash")
+	mockCodes.On("Consume", "code-hash").Return(storedCode(), nil)
+	mockPermissions.On("Refresh", "user-1").Return(userPermissions("user-1"), nil)
+	mockTokenManager.On("Sign", mock.MatchedBy(func(claims map[string]interface{}) bool {
+		return claims["user_id"] == "user-1" && claims["client_id"] == "lms" &&
+			claims["scope"] == "roles:read users:read" &&
+			assert.ObjectsAreEqual([]string{"users:read", "roles:read:restricted"}, claims["grants"]) &&
+			assert.ObjectsAreEqual([]string{"users"}, claims["modules"])
+	})).Return("access.token", nil)
+
+	token, err := usecase.Token(codeRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "access.token", token.AccessToken)
+	assert.Equal(t, 15*time.Minute, token.ExpiresIn)
+	mockTokenManager.AssertExpectations(t)
+}
+
+func TestOAuthUsecase_Token_AuthorizationCodeRejections(t *testing.T) {
+	cases := map[string]struct {
+		modifyRequest func(*auth_entity.OAuthTokenRequest)
+		modifyCode    func(*auth_entity.OAuthAuthorizationCode)
+		code          string
+	}{
+		"wrong verifier":    {func(r *auth_entity.OAuthTokenRequest) { r.CodeVerifier = "guess" }, nil, "invalid_grant"},
+		"other redirect":    {func(r *auth_entity.OAuthTokenRequest) { r.RedirectURI = "https://lms.example.com/other" }, nil, "invalid_grant"},
+		"code of other app": {nil, func(c *auth_entity.OAuthAuthorizationCode) { c.ClientID = "other" }, "invalid_grant"},
+		"expired code":      {nil, func(c *auth_entity.OAuthAuthorizationCode) { c.ExpiresAt = oauthNow }, "invalid_grant"},
+		"missing verifier":  {func(r *auth_entity.OAuthTokenRequest) { r.CodeVerifier = "" }, nil, "invalid_request"},
+		"wrong secret":      {func(r *auth_entity.OAuthTokenRequest) { r.ClientSecret = "guess" }, nil, "invalid_client"},
+		"unsupported grant": {func(r *auth_entity.OAuthTokenRequest) { r.GrantType = "password" }, nil, "unsupported_grant_type"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockClients := new(MockOAuthClientRepository)
+			mockCodes := new(MockOAuthCodeRepository)
+			mockConsents := new(MockOAuthConsentRepository)
+			mockPermissions := new(MockPermissionService)
+			mockTokenManager := new(MockTokenManager)
+			mockGenerator := new(MockSecureTokenGenerator)
+			mockRevocations := new(MockRevocationStore)
+			usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+			usecase.now = func() time.Time { return oauthNow }
+
+			expectOAuthClientAuth(mockClients, mockGenerator)
+			mockGenerator.On("Hash", "guess").Return("other-hash")
+			mockGenerator.On("Hash", "the-code").Return("code-hash")
+			code := storedCode()
+			if tc.modifyCode != nil {
+				tc.modifyCode(code)
+			}
+			mockCodes.On("Consume", "code-hash").Return(code, nil)
+			req := codeRequest()
+			if tc.modifyRequest != nil {
+				tc.modifyRequest(req)
+			}
+
+			_, err := usecase.Token(req)
+
+			assertOAuthCode(t, err, tc.code)
+			mockTokenManager.AssertNotCalled(t, "Sign", mock.Anything)
+		})
+	}
+}
+
+func TestOAuthUsecase_Token_FlatClaimsSkipWithheldScopes(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	code := storedCode()
+	code.Scopes = []string{"users:read", "users:delete"}
+	expectOAuthClientAuth(mockClients, mockGenerator)
+	mockGenerator.On("Hash", "the-code").Return("code-hash")
+	mockCodes.On("Consume", "code-hash").Return(code, nil)
+	mockPermissions.On("Refresh", "user-1").Return(permissionEntity.NewEffectivePermissions("user-1", 1, []*permissionEntity.Permission{
+		{Modules: []string{"users", "roles"}, Actions: []string{"read", "delete"}, Level: "allowed"},
+		{Modules: []string{"users"}, Actions: []string{"delete"}, Level: "denied"},
+	}), nil)
+	mockTokenManager.On("Sign", mock.MatchedBy(func(claims map[string]interface{}) bool {
+		return assert.ObjectsAreEqual([]string{"users"}, claims["modules"]) &&
+			assert.ObjectsAreEqual([]string{"read"}, claims["actions"])
+	})).Return("access.token", nil)
+
+	_, err := usecase.Token(codeRequest())
+
+	assert.NoError(t, err)
+	mockTokenManager.AssertExpectations(t)
+}
+
+func TestOAuthUsecase_Token_ClientCredentials(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	expectOAuthClientAuth(mockClients, mockGenerator)
+	mockPermissions.On("Refresh", "owner-1").Return(userPermissions("owner-1"), nil)
+	mockTokenManager.On("Sign", mock.MatchedBy(func(claims map[string]interface{}) bool {
+		_, hasUser := claims["user_id"]
+		return !hasUser && claims["client_id"] == "lms" && claims["scope"] == "users:update" &&
+			assert.ObjectsAreEqual([]string{"users:update"}, claims["grants"])
+	})).Return("access.token", nil)
+
+	token, err := usecase.Token(&auth_entity.OAuthTokenRequest{
+		GrantType:    auth_entity.GrantTypeClientCredentials,
+		ClientID:     "lms",
+		ClientSecret: "client-secret",
+		Scope:        "users:update",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"users:update"}, token.Scopes)
+	mockTokenManager.AssertExpectations(t)
+}
+
+func TestOAuthUsecase_Token_ClientCredentialsNeedsConfidentialClient(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	public := lmsClient()
+	public.Confidential = false
+	mockClients.On("FindByClientID", "lms").Return(public, nil)
+
+	_, err := usecase.Token(&auth_entity.OAuthTokenRequest{GrantType: auth_entity.GrantTypeClientCredentials, ClientID: "lms"})
+
+	assertOAuthCode(t, err, "unauthorized_client")
+}
+
+func TestOAuthUsecase_Introspect(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	expectOAuthClientAuth(mockClients, mockGenerator)
+	mockTokenManager.On("Verify", "access.token").Return(map[string]interface{}{
+		"jti":       "jti-1",
+		"user_id":   "user-1",
+		"client_id": "lms",
+		"scope":     "users:read",
+		"iat":       float64(oauthNow.Unix()),
+		"exp":       float64(oauthNow.Add(15 * time.Minute).Unix()),
+	}, nil)
+	mockTokenManager.On("Verify", "garbage").Return(nil, errors.New("invalid"))
+	mockRevocations.On("IsRevoked", "jti-1", "user-1", time.Unix(oauthNow.Unix(), 0)).Return(false, nil)
+
+	result, err := usecase.Introspect("lms", "client-secret", "access.token")
+	require.NoError(t, err)
+	assert.True(t, result.Active)
+	assert.Equal(t, "user-1", result.Subject)
+	assert.Equal(t, "lms", result.ClientID)
+	assert.Equal(t, "users:read", result.Scope)
+
+	result, err = usecase.Introspect("lms", "client-secret", "garbage")
+	require.NoError(t, err)
+	assert.False(t, result.Active)
+}
+
+func TestOAuthUsecase_Introspect_Revoked(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	expectOAuthClientAuth(mockClients, mockGenerator)
+	mockTokenManager.On("Verify", "access.token").Return(map[string]interface{}{"jti": "jti-1", "user_id": "user-1"}, nil)
+	mockRevocations.On("IsRevoked", "jti-1", "user-1", time.Time{}).Return(true, nil)
+
+	result, err := usecase.Introspect("lms", "client-secret", "access.token")
+
+	assert.NoError(t, err)
+	assert.False(t, result.Active)
+}
+
+func TestOAuthUsecase_RevokeConsent(t *testing.T) {
+	mockClients := new(MockOAuthClientRepository)
+	mockCodes := new(MockOAuthCodeRepository)
+	mockConsents := new(MockOAuthConsentRepository)
+	mockPermissions := new(MockPermissionService)
+	mockTokenManager := new(MockTokenManager)
+	mockGenerator := new(MockSecureTokenGenerator)
+	mockRevocations := new(MockRevocationStore)
+	usecase := NewOAuthUsecase(mockClients, mockCodes, mockConsents, mockPermissions, mockTokenManager, mockGenerator, mockRevocations, 15*time.Minute, time.Minute)
+	usecase.now = func() time.Time { return oauthNow }
+
+	mockConsents.On("Delete", "user-1", "lms").Return(auth_entity.ErrOAuthConsentNotFound)
+
+	assert.ErrorIs(t, usecase.RevokeConsent("user-1", "lms"), auth_entity.ErrOAuthConsentNotFound)
+}
@@ -0,0 +1,259 @@
+package auth_usecase
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_event "github.com/williamkoller/system-education/internal/auth/port/event"
+	port_auth_oidc "github.com/williamkoller/system-education/internal/auth/port/oidc"
+	port_auth_permission "github.com/williamkoller/system-education/internal/auth/port/permission"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+// OIDCUsecase signs users in through external OpenID Connect providers.
+// Identities are matched by provider subject, then linked to an existing
+// user with the same verified email, and otherwise a user is provisioned on
+// the spot. The login then finishes like a password login, MFA included.
+type OIDCUsecase struct {
+	providers      map[string]port_auth_oidc.IdentityProvider
+	states         port_auth_repository.OIDCStateRepository
+	identities     port_auth_repository.ExternalIdentityRepository
+	repo           port_user_repository.UserRepository
+	passwordHasher port_cryptography.Bcrypt
+	tokenGenerator port_auth_cryptography.SecureTokenGenerator
+	granter        port_auth_permission.PermissionGranter
+	event          port_auth_event.Dispatcher
+	login          port_auth_usecase.AuthUsecase
+	stateExpiresIn time.Duration
+	now            func() time.Time
+}
+
+var _ port_auth_usecase.OIDCUsecase = &OIDCUsecase{}
+
+func NewOIDCUsecase(
+	providers []port_auth_oidc.IdentityProvider,
+	states port_auth_repository.OIDCStateRepository,
+	identities port_auth_repository.ExternalIdentityRepository,
+	repo port_user_repository.UserRepository,
+	passwordHasher port_cryptography.Bcrypt,
+	tokenGenerator port_auth_cryptography.SecureTokenGenerator,
+	granter port_auth_permission.PermissionGranter,
+	event port_auth_event.Dispatcher,
+	login port_auth_usecase.AuthUsecase,
+	stateExpiresIn time.Duration,
+) *OIDCUsecase {
+	byName := make(map[string]port_auth_oidc.IdentityProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &OIDCUsecase{
+		providers:      byName,
+		states:         states,
+		identities:     identities,
+		repo:           repo,
+		passwordHasher: passwordHasher,
+		tokenGenerator: tokenGenerator,
+		granter:        granter,
+		event:          event,
+		login:          login,
+		stateExpiresIn: stateExpiresIn,
+		now:            time.Now,
+	}
+}
+
+func (o *OIDCUsecase) Begin(providerName string) (string, error) {
+	provider, ok := o.providers[providerName]
+	if !ok {
+		return "", auth_entity.ErrUnknownIdentityProvider
+	}
+
+	state, stateHash, err := o.tokenGenerator.Generate()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	nonce, _, err := o.tokenGenerator.Generate()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	verifier, _, err := o.tokenGenerator.Generate()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+
+	now := o.now()
+	if err := o.states.DeleteExpired(now); err != nil {
+		log.Printf("failed to delete expired OIDC states: %v", err)
+	}
+
+	if err := o.states.Save(&auth_entity.OIDCLoginState{
+		ID:           uuid.New().String(),
+		Provider:     providerName,
+		StateHash:    stateHash,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    now.Add(o.stateExpiresIn),
+		CreatedAt:    now,
+	}); err != nil {
+		return "", fmt.Errorf("failed to save login state: %w", err)
+	}
+
+	return provider.AuthCodeURL(state, nonce, auth_entity.PKCEChallenge(verifier))
+}
+
+func (o *OIDCUsecase) Callback(providerName, state, code string, client auth_entity.ClientInfo) (*auth_entity.LoginResult, error) {
+	provider, ok := o.providers[providerName]
+	if !ok {
+		return nil, auth_entity.ErrUnknownIdentityProvider
+	}
+	if state == "" || code == "" {
+		return nil, auth_entity.ErrInvalidOIDCState
+	}
+
+	stored, err := o.states.Consume(o.tokenGenerator.Hash(state))
+	if err != nil {
+		return nil, err
+	}
+	if stored.Provider != providerName || stored.IsExpired(o.now()) {
+		return nil, auth_entity.ErrInvalidOIDCState
+	}
+
+	claims, err := provider.Exchange(code, stored.CodeVerifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(stored.Nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", auth_entity.ErrInvalidIDToken)
+	}
+	if claims.EmailDomain() == "" || !claims.EmailVerified {
+		return nil, auth_entity.ErrExternalEmailNotVerified
+	}
+	if !provider.AllowsDomain(claims.EmailDomain()) {
+		return nil, auth_entity.ErrExternalDomainNotAllowed
+	}
+
+	userID, err := o.resolveUser(providerName, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.login.CompleteLogin(userID, client)
+}
+
+func (o *OIDCUsecase) resolveUser(providerName string, claims *auth_entity.ExternalClaims) (string, error) {
+	identity, err := o.identities.FindByProviderSubject(providerName, claims.Subject)
+	if err == nil {
+		return identity.UserID, nil
+	}
+	if !errors.Is(err, auth_entity.ErrExternalIdentityNotFound) {
+		return "", fmt.Errorf("failed to find external identity: %w", err)
+	}
+
+	user, err := o.repo.FindByEmail(claims.Email)
+	if err != nil || user == nil {
+		user, err = o.provision(claims)
+		if err != nil {
+			return "", err
+		}
+	} else if !user.IsEmailVerified() {
+		// The address on the account has not been proven, for instance
+		// because an operator just changed it, so it may not belong to the
+		// account's owner. Linking would hand the account to whoever
+		// controls the address at the provider.
+		return "", auth_entity.ErrExternalAccountNotVerified
+	}
+
+	if _, err := o.identities.Save(&auth_entity.ExternalIdentity{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Provider:  providerName,
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		CreatedAt: o.now(),
+	}); err != nil {
+		return "", fmt.Errorf("failed to link external identity: %w", err)
+	}
+
+	return user.ID, nil
+}
+
+// provision creates the user on first sign-in. The password is random and
+// never shown, so the account is only reachable through the provider until
+// the user resets it.
+func (o *OIDCUsecase) provision(claims *auth_entity.ExternalClaims) (*user_entity.User, error) {
+	secret, _, err := o.tokenGenerator.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+	hash, err := o.passwordHasher.Hash(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	name, surname := externalName(claims)
+	newUser := user_entity.NewUser(&user_entity.User{
+		ID:       uuid.New().String(),
+		Name:     name,
+		Surname:  surname,
+		Nickname: emailLocalPart(claims.Email),
+		Email:    claims.Email,
+		Password: hash,
+	})
+	if newUser == nil {
+		return nil, errors.New("invalid user data")
+	}
+	newUser.MarkEmailVerified(o.now())
+
+	user, err := o.repo.Save(newUser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save user: %w", err)
+	}
+
+	if err := o.granter.GrantDefault(user.ID); err != nil {
+		return nil, fmt.Errorf("failed to grant default permissions: %w", err)
+	}
+
+	// Repositories backed by the outbox have already taken the events; any
+	// left over are dispatched in-process.
+	for _, domainEvent := range newUser.PullDomainEvents() {
+		o.event.Dispatch(domainEvent)
+	}
+
+	return user, nil
+}
+
+// externalName prefers the structured name claims and falls back to
+// splitting the display name, then to the email's local part.
+func externalName(claims *auth_entity.ExternalClaims) (string, string) {
+	name, surname := strings.TrimSpace(claims.GivenName), strings.TrimSpace(claims.FamilyName)
+	if name == "" || surname == "" {
+		parts := strings.Fields(claims.Name)
+		if len(parts) > 0 {
+			name, surname = parts[0], strings.Join(parts[1:], " ")
+		}
+	}
+	if name == "" {
+		name = emailLocalPart(claims.Email)
+	}
+	if surname == "" {
+		surname = "-"
+	}
+	return name, surname
+}
+
+func emailLocalPart(email string) string {
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		return email[:at]
+	}
+	return email
+}
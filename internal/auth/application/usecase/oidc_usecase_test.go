@@ -0,0 +1,411 @@
+package auth_usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_oidc "github.com/williamkoller/system-education/internal/auth/port/oidc"
+	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type MockIdentityProvider struct {
+	mock.Mock
+}
+
+func (m *MockIdentityProvider) Name() string {
+	return "google"
+}
+
+func (m *MockIdentityProvider) AuthCodeURL(state, nonce, codeChallenge string) (string, error) {
+	args := m.Called(state, nonce, codeChallenge)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockIdentityProvider) Exchange(code, codeVerifier string) (*auth_entity.ExternalClaims, error) {
+	args := m.Called(code, codeVerifier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.ExternalClaims), args.Error(1)
+}
+
+func (m *MockIdentityProvider) AllowsDomain(domain string) bool {
+	return m.Called(domain).Bool(0)
+}
+
+type MockOIDCStateRepository struct {
+	mock.Mock
+}
+
+func (m *MockOIDCStateRepository) Save(s *auth_entity.OIDCLoginState) error {
+	return m.Called(s).Error(0)
+}
+
+func (m *MockOIDCStateRepository) Consume(stateHash string) (*auth_entity.OIDCLoginState, error) {
+	args := m.Called(stateHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.OIDCLoginState), args.Error(1)
+}
+
+func (m *MockOIDCStateRepository) DeleteExpired(before time.Time) error {
+	return m.Called(before).Error(0)
+}
+
+type MockExternalIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockExternalIdentityRepository) FindByProviderSubject(provider, subject string) (*auth_entity.ExternalIdentity, error) {
+	args := m.Called(provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.ExternalIdentity), args.Error(1)
+}
+
+func (m *MockExternalIdentityRepository) Save(i *auth_entity.ExternalIdentity) (*auth_entity.ExternalIdentity, error) {
+	args := m.Called(i)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.ExternalIdentity), args.Error(1)
+}
+
+type MockPermissionGranter struct {
+	mock.Mock
+}
+
+func (m *MockPermissionGranter) GrantDefault(userID string) error {
+	return m.Called(userID).Error(0)
+}
+
+type MockDispatcher struct {
+	mock.Mock
+}
+
+func (m *MockDispatcher) Dispatch(event interface{}) {
+	m.Called(event)
+}
+
+func (m *MockDispatcher) Register(eventName string, handler shared_event.Handler) {
+	m.Called(eventName, handler)
+}
+
+var (
+	oidcNow    = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	oidcTicket = &auth_entity.MFAChallengeTicket{Token: "challenge", ExpiresIn: 5 * time.Minute}
+)
+
+func expectOIDCState(generator *MockSecureTokenGenerator, states *MockOIDCStateRepository) {
+	generator.On("Hash", "state").Return("state-hash")
+	states.On("Consume", "state-hash").Return(&auth_entity.OIDCLoginState{
+		Provider:     "google",
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    oidcNow.Add(time.Minute),
+	}, nil)
+}
+
+func expectOIDCClaims(provider *MockIdentityProvider, claims *auth_entity.ExternalClaims) {
+	provider.On("Exchange", "code", "verifier").Return(claims, nil)
+	provider.On("AllowsDomain", claims.EmailDomain()).Return(true).Maybe()
+}
+
+// expectOIDCLogin has the AuthUsecase from newMFATestUsecase answer the
+// login with oidcTicket, so a successful callback is one that reaches the
+// second factor.
+func expectOIDCLogin(repo *MockUserRepository, mfa *MockMFAService, user *userEntity.User) {
+	repo.On("FindByID", user.ID).Return(user, nil)
+	mfa.On("Challenge", user.ID, user.Email).Return(oidcTicket, nil)
+}
+
+func googleClaims() *auth_entity.ExternalClaims {
+	return &auth_entity.ExternalClaims{
+		Subject:       "google-sub",
+		Email:         "ana@school.edu",
+		EmailVerified: true,
+		GivenName:     "Ana",
+		FamilyName:    "Silva",
+		Nonce:         "nonce",
+	}
+}
+
+func TestOIDCUsecase_Begin(t *testing.T) {
+	login, mockRepo, _, _, mockBcrypt, _, mockGenerator, _ := newMFATestUsecase()
+	mockProvider := new(MockIdentityProvider)
+	mockStates := new(MockOIDCStateRepository)
+	mockIdentities := new(MockExternalIdentityRepository)
+	mockGranter := new(MockPermissionGranter)
+	mockEvent := new(MockDispatcher)
+	usecase := NewOIDCUsecase([]port_auth_oidc.IdentityProvider{mockProvider}, mockStates, mockIdentities, mockRepo, mockBcrypt, mockGenerator, mockGranter, mockEvent, login, 10*time.Minute)
+	usecase.now = func() time.Time { return oidcNow }
+
+	mockGenerator.On("Generate").Return("state", "state-hash", nil).Once()
+	mockGenerator.On("Generate").Return("nonce", "nonce-hash", nil).Once()
+	mockGenerator.On("Generate").Return("verifier", "verifier-hash", nil).Once()
+	mockStates.On("DeleteExpired", oidcNow).Return(nil)
+	mockStates.On("Save", mock.MatchedBy(func(s *auth_entity.OIDCLoginState) bool {
+		return s.Provider == "google" && s.StateHash == "state-hash" &&
+			s.Nonce == "nonce" && s.CodeVerifier == "verifier" &&
+			s.ExpiresAt.Equal(oidcNow.Add(10*time.Minute))
+	})).Return(nil)
+	mockProvider.On("AuthCodeURL", "state", "nonce", auth_entity.PKCEChallenge("verifier")).Return("https://idp/authorize?x", nil)
+
+	authURL, err := usecase.Begin("google")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://idp/authorize?x", authURL)
+	mockStates.AssertExpectations(t)
+}
+
+func TestOIDCUsecase_UnknownProvider(t *testing.T) {
+	login, mockRepo, _, _, mockBcrypt, _, mockGenerator, _ := newMFATestUsecase()
+	mockProvider := new(MockIdentityProvider)
+	mockStates := new(MockOIDCStateRepository)
+	mockIdentities := new(MockExternalIdentityRepository)
+	mockGranter := new(MockPermissionGranter)
+	mockEvent := new(MockDispatcher)
+	usecase := NewOIDCUsecase([]port_auth_oidc.IdentityProvider{mockProvider}, mockStates, mockIdentities, mockRepo, mockBcrypt, mockGenerator, mockGranter, mockEvent, login, 10*time.Minute)
+	usecase.now = func() time.Time { return oidcNow }
+
+	_, err := usecase.Begin("github")
+	assert.ErrorIs(t, err, auth_entity.ErrUnknownIdentityProvider)
+
+	_, err = usecase.Callback("github", "state", "code", auth_entity.ClientInfo{})
+	assert.ErrorIs(t, err, auth_entity.ErrUnknownIdentityProvider)
+}
+
+func TestOIDCUsecase_Callback_KnownIdentity(t *testing.T) {
+	login, mockRepo, _, _, mockBcrypt, _, mockGenerator, mockMFA := newMFATestUsecase()
+	mockProvider := new(MockIdentityProvider)
+	mockStates := new(MockOIDCStateRepository)
+	mockIdentities := new(MockExternalIdentityRepository)
+	mockGranter := new(MockPermissionGranter)
+	mockEvent := new(MockDispatcher)
+	usecase := NewOIDCUsecase([]port_auth_oidc.IdentityProvider{mockProvider}, mockStates, mockIdentities, mockRepo, mockBcrypt, mockGenerator, mockGranter, mockEvent, login, 10*time.Minute)
+	usecase.now = func() time.Time { return oidcNow }
+
+	user := &userEntity.User{ID: "user-1", Email: "ana@school.edu"}
+	expectOIDCState(mockGenerator, mockStates)
+	expectOIDCClaims(mockProvider, googleClaims())
+	mockIdentities.On("FindByProviderSubject", "google", "google-sub").Return(&auth_entity.ExternalIdentity{UserID: "user-1"}, nil)
+	expectOIDCLogin(mockRepo, mockMFA, user)
+
+	result, err := usecase.Callback("google", "state", "code", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, oidcTicket, result.Challenge)
+	mockRepo.AssertNotCalled(t, "FindByEmail", mock.Anything)
+	mockIdentities.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestOIDCUsecase_Callback_LinksExistingUserByEmail(t *testing.T) {
+	login, mockRepo, _, _, mockBcrypt, _, mockGenerator, mockMFA := newMFATestUsecase()
+	mockProvider := new(MockIdentityProvider)
+	mockStates := new(MockOIDCStateRepository)
+	mockIdentities := new(MockExternalIdentityRepository)
+	mockGranter := new(MockPermissionGranter)
+	mockEvent := new(MockDispatcher)
+	usecase := NewOIDCUsecase([]port_auth_oidc.IdentityProvider{mockProvider}, mockStates, mockIdentities, mockRepo, mockBcrypt, mockGenerator, mockGranter, mockEvent, login, 10*time.Minute)
+	usecase.now = func() time.Time { return oidcNow }
+
+	verifiedAt := oidcNow
+	user := &userEntity.User{ID: "user-1", Email: "ana@school.edu", EmailVerifiedAt: &verifiedAt}
+	expectOIDCState(mockGenerator, mockStates)
+	expectOIDCClaims(mockProvider, googleClaims())
+	mockIdentities.On("FindByProviderSubject", "google", "google-sub").Return(nil, auth_entity.ErrExternalIdentityNotFound)
+	mockRepo.On("FindByEmail", "ana@school.edu").Return(user, nil)
+	mockIdentities.On("Save", mock.MatchedBy(func(i *auth_entity.ExternalIdentity) bool {
+		return i.UserID == "user-1" && i.Provider == "google" && i.Subject == "google-sub"
+	})).Return(&auth_entity.ExternalIdentity{}, nil)
+	expectOIDCLogin(mockRepo, mockMFA, user)
+
+	result, err := usecase.Callback("google", "state", "code", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, oidcTicket, result.Challenge)
+	mockIdentities.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockGranter.AssertNotCalled(t, "GrantDefault", mock.Anything)
+}
+
+func TestOIDCUsecase_Callback_RefusesToLinkUnverifiedAccount(t *testing.T) {
+	login, mockRepo, _, _, mockBcrypt, _, mockGenerator, _ := newMFATestUsecase()
+	mockProvider := new(MockIdentityProvider)
+	mockStates := new(MockOIDCStateRepository)
+	mockIdentities := new(MockExternalIdentityRepository)
+	mockGranter := new(MockPermissionGranter)
+	mockEvent := new(MockDispatcher)
+	usecase := NewOIDCUsecase([]port_auth_oidc.IdentityProvider{mockProvider}, mockStates, mockIdentities, mockRepo, mockBcrypt, mockGenerator, mockGranter, mockEvent, login, 10*time.Minute)
+	usecase.now = func() time.Time { return oidcNow }
+
+	expectOIDCState(mockGenerator, mockStates)
+	expectOIDCClaims(mockProvider, googleClaims())
+	mockIdentities.On("FindByProviderSubject", "google", "google-sub").Return(nil, auth_entity.ErrExternalIdentityNotFound)
+	mockRepo.On("FindByEmail", "ana@school.edu").Return(&userEntity.User{ID: "user-1", Email: "ana@school.edu"}, nil)
+
+	_, err := usecase.Callback("google", "state", "code", auth_entity.ClientInfo{})
+
+	assert.ErrorIs(t, err, auth_entity.ErrExternalAccountNotVerified)
+	mockIdentities.AssertNotCalled(t, "Save", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestOIDCUsecase_Callback_ProvisionsNewUser(t *testing.T) {
+	login, mockRepo, _, _, mockBcrypt, _, mockGenerator, mockMFA := newMFATestUsecase()
+	mockProvider := new(MockIdentityProvider)
+	mockStates := new(MockOIDCStateRepository)
+	mockIdentities := new(MockExternalIdentityRepository)
+	mockGranter := new(MockPermissionGranter)
+	mockEvent := new(MockDispatcher)
+	usecase := NewOIDCUsecase([]port_auth_oidc.IdentityProvider{mockProvider}, mockStates, mockIdentities, mockRepo, mockBcrypt, mockGenerator, mockGranter, mockEvent, login, 10*time.Minute)
+	usecase.now = func() time.Time { return oidcNow }
+
+	expectOIDCState(mockGenerator, mockStates)
+	expectOIDCClaims(mockProvider, googleClaims())
+	mockIdentities.On("FindByProviderSubject", "google", "google-sub").Return(nil, auth_entity.ErrExternalIdentityNotFound)
+	mockRepo.On("FindByEmail", "ana@school.edu").Return(nil, errors.New("not found"))
+	mockGenerator.On("Generate").Return("random-secret", "random-hash", nil)
+	mockBcrypt.On("Hash", "random-secret").Return("hashed", nil)
+
+	provisioned := &userEntity.User{ID: "user-new", Email: "ana@school.edu"}
+	mockRepo.On("Save", mock.MatchedBy(func(u *userEntity.User) bool {
+		return u.Name == "Ana" && u.Surname == "Silva" && u.Nickname == "ana" &&
+			u.Email == "ana@school.edu" && u.Password == "hashed" && u.IsEmailVerified()
+	})).Return(provisioned, nil)
+	mockGranter.On("GrantDefault", "user-new").Return(nil)
+	mockEvent.On("Dispatch", mock.Anything).Return()
+	mockIdentities.On("Save", mock.MatchedBy(func(i *auth_entity.ExternalIdentity) bool {
+		return i.UserID == "user-new" && i.Subject == "google-sub"
+	})).Return(&auth_entity.ExternalIdentity{}, nil)
+	expectOIDCLogin(mockRepo, mockMFA, provisioned)
+
+	result, err := usecase.Callback("google", "state", "code", auth_entity.ClientInfo{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, oidcTicket, result.Challenge)
+	mockRepo.AssertExpectations(t)
+	mockGranter.AssertExpectations(t)
+	mockIdentities.AssertExpectations(t)
+	mockEvent.AssertNumberOfCalls(t, "Dispatch", 1)
+}
+
+func TestOIDCUsecase_Callback_RejectsInvalidState(t *testing.T) {
+	login, mockRepo, _, _, mockBcrypt, _, mockGenerator, _ := newMFATestUsecase()
+	mockProvider := new(MockIdentityProvider)
+	mockStates := new(MockOIDCStateRepository)
+	mockIdentities := new(MockExternalIdentityRepository)
+	mockGranter := new(MockPermissionGranter)
+	mockEvent := new(MockDispatcher)
+	usecase := NewOIDCUsecase([]port_auth_oidc.IdentityProvider{mockProvider}, mockStates, mockIdentities, mockRepo, mockBcrypt, mockGenerator, mockGranter, mockEvent, login, 10*time.Minute)
+	usecase.now = func() time.Time { return oidcNow }
+
+	mockGenerator.On("Hash", "forged").Return("forged-hash")
+	mockStates.On("Consume", "forged-hash").Return(nil, auth_entity.ErrInvalidOIDCState)
+
+	_, err := usecase.Callback("google", "forged", "code", auth_entity.ClientInfo{})
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidOIDCState)
+
+	_, err = usecase.Callback("google", "", "code", auth_entity.ClientInfo{})
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidOIDCState)
+	mockProvider.AssertNotCalled(t, "Exchange", mock.Anything, mock.Anything)
+}
+
+func TestOIDCUsecase_Callback_RejectsExpiredOrForeignState(t *testing.T) {
+	states := map[string]*auth_entity.OIDCLoginState{
+		"expired": {Provider: "google", ExpiresAt: oidcNow},
+		"foreign": {Provider: "microsoft", ExpiresAt: oidcNow.Add(time.Minute)},
+	}
+
+	for name, state := range states {
+		t.Run(name, func(t *testing.T) {
+			login, mockRepo, _, _, mockBcrypt, _, mockGenerator, _ := newMFATestUsecase()
+			mockProvider := new(MockIdentityProvider)
+			mockStates := new(MockOIDCStateRepository)
+			mockIdentities := new(MockExternalIdentityRepository)
+			mockGranter := new(MockPermissionGranter)
+			mockEvent := new(MockDispatcher)
+			usecase := NewOIDCUsecase([]port_auth_oidc.IdentityProvider{mockProvider}, mockStates, mockIdentities, mockRepo, mockBcrypt, mockGenerator, mockGranter, mockEvent, login, 10*time.Minute)
+			usecase.now = func() time.Time { return oidcNow }
+
+			mockGenerator.On("Hash", "state").Return("state-hash")
+			mockStates.On("Consume", "state-hash").Return(state, nil)
+
+			_, err := usecase.Callback("google", "state", "code", auth_entity.ClientInfo{})
+
+			assert.ErrorIs(t, err, auth_entity.ErrInvalidOIDCState)
+			mockProvider.AssertNotCalled(t, "Exchange", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestOIDCUsecase_Callback_RejectsClaims(t *testing.T) {
+	cases := map[string]struct {
+		modify func(*auth_entity.ExternalClaims)
+		allow  bool
+		want   error
+	}{
+		"nonce mismatch":     {func(c *auth_entity.ExternalClaims) { c.Nonce = "replayed" }, true, auth_entity.ErrInvalidIDToken},
+		"unverified email":   {func(c *auth_entity.ExternalClaims) { c.EmailVerified = false }, true, auth_entity.ErrExternalEmailNotVerified},
+		"missing email":      {func(c *auth_entity.ExternalClaims) { c.Email = "" }, true, auth_entity.ErrExternalEmailNotVerified},
+		"domain not allowed": {func(c *auth_entity.ExternalClaims) {}, false, auth_entity.ErrExternalDomainNotAllowed},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			login, mockRepo, _, _, mockBcrypt, _, mockGenerator, _ := newMFATestUsecase()
+			mockProvider := new(MockIdentityProvider)
+			mockStates := new(MockOIDCStateRepository)
+			mockIdentities := new(MockExternalIdentityRepository)
+			mockGranter := new(MockPermissionGranter)
+			mockEvent := new(MockDispatcher)
+			usecase := NewOIDCUsecase([]port_auth_oidc.IdentityProvider{mockProvider}, mockStates, mockIdentities, mockRepo, mockBcrypt, mockGenerator, mockGranter, mockEvent, login, 10*time.Minute)
+			usecase.now = func() time.Time { return oidcNow }
+
+			claims := googleClaims()
+			tc.modify(claims)
+			expectOIDCState(mockGenerator, mockStates)
+			mockProvider.On("Exchange", "code", "verifier").Return(claims, nil)
+			mockProvider.On("AllowsDomain", mock.Anything).Return(tc.allow)
+
+			result, err := usecase.Callback("google", "state", "code", auth_entity.ClientInfo{})
+
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, tc.want)
+			mockIdentities.AssertNotCalled(t, "FindByProviderSubject", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestOIDCUsecase_Callback_GrantError(t *testing.T) {
+	login, mockRepo, _, _, mockBcrypt, _, mockGenerator, _ := newMFATestUsecase()
+	mockProvider := new(MockIdentityProvider)
+	mockStates := new(MockOIDCStateRepository)
+	mockIdentities := new(MockExternalIdentityRepository)
+	mockGranter := new(MockPermissionGranter)
+	mockEvent := new(MockDispatcher)
+	usecase := NewOIDCUsecase([]port_auth_oidc.IdentityProvider{mockProvider}, mockStates, mockIdentities, mockRepo, mockBcrypt, mockGenerator, mockGranter, mockEvent, login, 10*time.Minute)
+	usecase.now = func() time.Time { return oidcNow }
+
+	expectOIDCState(mockGenerator, mockStates)
+	expectOIDCClaims(mockProvider, googleClaims())
+	mockIdentities.On("FindByProviderSubject", "google", "google-sub").Return(nil, auth_entity.ErrExternalIdentityNotFound)
+	mockRepo.On("FindByEmail", "ana@school.edu").Return(nil, errors.New("not found"))
+	mockGenerator.On("Generate").Return("random-secret", "random-hash", nil)
+	mockBcrypt.On("Hash", "random-secret").Return("hashed", nil)
+	mockRepo.On("Save", mock.Anything).Return(&userEntity.User{ID: "user-new"}, nil)
+	mockGranter.On("GrantDefault", mock.Anything).Return(errors.New("db error"))
+
+	result, err := usecase.Callback("google", "state", "code", auth_entity.ClientInfo{})
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "failed to grant default permissions: db error")
+	mockIdentities.AssertNotCalled(t, "Save", mock.Anything)
+}
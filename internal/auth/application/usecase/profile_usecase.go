@@ -0,0 +1,112 @@
+package auth_usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+// ProfileUsecase serves the signed-in user's view of their own account.
+type ProfileUsecase struct {
+	users port_user_repository.UserRepository
+	// permissions resolves direct and role grants alike, the same set the
+	// user's tokens are built from.
+	permissions port_permission_service.PermissionService
+	sessions    port_auth_repository.SessionRepository
+	mfa         port_auth_repository.MFARepository
+	// refreshExpiresIn bounds how long an idle session still counts as active.
+	refreshExpiresIn time.Duration
+	now              func() time.Time
+}
+
+var _ port_auth_usecase.ProfileUsecase = &ProfileUsecase{}
+
+func NewProfileUsecase(
+	users port_user_repository.UserRepository,
+	permissions port_permission_service.PermissionService,
+	sessions port_auth_repository.SessionRepository,
+	mfa port_auth_repository.MFARepository,
+	refreshExpiresIn time.Duration,
+) *ProfileUsecase {
+	return &ProfileUsecase{
+		users:            users,
+		permissions:      permissions,
+		sessions:         sessions,
+		mfa:              mfa,
+		refreshExpiresIn: refreshExpiresIn,
+		now:              time.Now,
+	}
+}
+
+func (p *ProfileUsecase) Me(userID string) (*port_auth_usecase.Profile, error) {
+	user, err := p.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return p.profile(user)
+}
+
+// UpdateMe only reaches the fields in ProfileUpdate; email, password and
+// verification state go through their own flows.
+func (p *ProfileUsecase) UpdateMe(userID string, update port_auth_usecase.ProfileUpdate) (*port_auth_usecase.Profile, error) {
+	user, err := p.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := user.UpdateProfile(update.Name, update.Surname, update.Nickname, update.Age); err != nil {
+		return nil, err
+	}
+
+	updated, err := p.users.Update(user.ID, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return p.profile(updated)
+}
+
+func (p *ProfileUsecase) findUser(userID string) (*user_entity.User, error) {
+	user, err := p.users.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, port_user_repository.ErrUserNotFound) {
+			return nil, port_user_repository.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, port_user_repository.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (p *ProfileUsecase) profile(user *user_entity.User) (*port_auth_usecase.Profile, error) {
+	effective, err := p.permissions.Resolve(user.ID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+
+	sessions, err := p.sessions.FindActiveByUser(user.ID, p.now().Add(-p.refreshExpiresIn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+
+	credential, err := p.mfa.FindByUserID(user.ID)
+	if err != nil && !errors.Is(err, auth_entity.ErrMFANotEnrolled) {
+		return nil, fmt.Errorf("failed to find mfa credential: %w", err)
+	}
+
+	return &port_auth_usecase.Profile{
+		User:           user,
+		Permissions:    effective.Permissions,
+		ActiveSessions: len(sessions),
+		MFAEnabled:     credential.IsConfirmed(),
+	}, nil
+}
@@ -0,0 +1,174 @@
+package auth_usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	permissionEntity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type MockMFARepository struct {
+	mock.Mock
+}
+
+func (m *MockMFARepository) FindByUserID(userID string) (*auth_entity.MFACredential, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.MFACredential), args.Error(1)
+}
+
+func (m *MockMFARepository) Save(c *auth_entity.MFACredential) (*auth_entity.MFACredential, error) {
+	args := m.Called(c)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.MFACredential), args.Error(1)
+}
+
+func (m *MockMFARepository) Confirm(userID string, step int64, confirmedAt time.Time, codes []*auth_entity.RecoveryCode) error {
+	return m.Called(userID, step, confirmedAt, codes).Error(0)
+}
+
+func (m *MockMFARepository) AdvanceStep(userID string, step int64) (bool, error) {
+	args := m.Called(userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) UseRecoveryCode(userID, codeHash string, usedAt time.Time) (bool, error) {
+	args := m.Called(userID, codeHash, usedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+var profileNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newProfileTestUsecase() (*ProfileUsecase, *MockUserRepository, *MockPermissionService, *MockSessionRepository, *MockMFARepository) {
+	users := new(MockUserRepository)
+	permissions := new(MockPermissionService)
+	sessions := new(MockSessionRepository)
+	mfa := new(MockMFARepository)
+
+	usecase := NewProfileUsecase(users, permissions, sessions, mfa, 24*time.Hour)
+	usecase.now = func() time.Time { return profileNow }
+
+	return usecase, users, permissions, sessions, mfa
+}
+
+func newProfileTestUser() *userEntity.User {
+	return &userEntity.User{
+		ID:       "user-123",
+		Name:     "John",
+		Surname:  "Doe",
+		Nickname: "johndoe",
+		Age:      30,
+		Email:    "john@example.com",
+		Password: "$2a$10$hashedpassword",
+	}
+}
+
+func TestProfileUsecase_Me(t *testing.T) {
+	usecase, users, permissions, sessions, mfa := newProfileTestUsecase()
+	user := newProfileTestUser()
+	granted := []*permissionEntity.Permission{
+		{ID: "perm-1", UserID: user.ID, Modules: []string{"users"}, Actions: []string{"read"}},
+		{ID: "role-perm-1", Modules: []string{"roles"}, Actions: []string{"read"}},
+	}
+	confirmedAt := profileNow.Add(-time.Hour)
+
+	users.On("FindByID", user.ID).Return(user, nil)
+	permissions.On("Resolve", user.ID, int64(0)).Return(permissionEntity.NewEffectivePermissions(user.ID, 1, granted), nil)
+	sessions.On("FindActiveByUser", user.ID, profileNow.Add(-24*time.Hour)).
+		Return([]*auth_entity.Session{{ID: "s1"}, {ID: "s2"}}, nil)
+	mfa.On("FindByUserID", user.ID).Return(&auth_entity.MFACredential{UserID: user.ID, ConfirmedAt: &confirmedAt}, nil)
+
+	profile, err := usecase.Me(user.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, user, profile.User)
+	assert.Equal(t, granted, profile.Permissions)
+	assert.Equal(t, 2, profile.ActiveSessions)
+	assert.True(t, profile.MFAEnabled)
+}
+
+func TestProfileUsecase_Me_NotEnrolledInMFA(t *testing.T) {
+	usecase, users, permissions, sessions, mfa := newProfileTestUsecase()
+	user := newProfileTestUser()
+
+	users.On("FindByID", user.ID).Return(user, nil)
+	permissions.On("Resolve", user.ID, int64(0)).Return(permissionEntity.NewEffectivePermissions(user.ID, 1, nil), nil)
+	sessions.On("FindActiveByUser", user.ID, mock.Anything).Return([]*auth_entity.Session{}, nil)
+	mfa.On("FindByUserID", user.ID).Return(nil, auth_entity.ErrMFANotEnrolled)
+
+	profile, err := usecase.Me(user.ID)
+
+	assert.NoError(t, err)
+	assert.False(t, profile.MFAEnabled)
+	assert.Equal(t, 0, profile.ActiveSessions)
+}
+
+func TestProfileUsecase_Me_UserNotFound(t *testing.T) {
+	usecase, users, _, _, _ := newProfileTestUsecase()
+
+	users.On("FindByID", "missing").Return(nil, port_user_repository.ErrUserNotFound)
+
+	profile, err := usecase.Me("missing")
+
+	assert.Nil(t, profile)
+	assert.ErrorIs(t, err, port_user_repository.ErrUserNotFound)
+}
+
+func TestProfileUsecase_Me_PermissionLookupFails(t *testing.T) {
+	usecase, users, permissions, _, _ := newProfileTestUsecase()
+	user := newProfileTestUser()
+
+	users.On("FindByID", user.ID).Return(user, nil)
+	permissions.On("Resolve", user.ID, int64(0)).Return(nil, errors.New("db down"))
+
+	profile, err := usecase.Me(user.ID)
+
+	assert.Nil(t, profile)
+	assert.EqualError(t, err, "failed to resolve permissions: db down")
+}
+
+func TestProfileUsecase_UpdateMe(t *testing.T) {
+	usecase, users, permissions, sessions, mfa := newProfileTestUsecase()
+	user := newProfileTestUser()
+	nickname := "jd"
+
+	users.On("FindByID", user.ID).Return(user, nil)
+	users.On("Update", user.ID, mock.MatchedBy(func(u *userEntity.User) bool {
+		return u.Nickname == "jd" && u.Email == "john@example.com" && u.Password == "$2a$10$hashedpassword"
+	})).Return(user, nil)
+	permissions.On("Resolve", user.ID, int64(0)).Return(permissionEntity.NewEffectivePermissions(user.ID, 1, nil), nil)
+	sessions.On("FindActiveByUser", user.ID, mock.Anything).Return([]*auth_entity.Session{}, nil)
+	mfa.On("FindByUserID", user.ID).Return(nil, auth_entity.ErrMFANotEnrolled)
+
+	profile, err := usecase.UpdateMe(user.ID, port_auth_usecase.ProfileUpdate{Nickname: &nickname})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jd", profile.User.Nickname)
+	users.AssertExpectations(t)
+}
+
+func TestProfileUsecase_UpdateMe_InvalidInput(t *testing.T) {
+	usecase, users, _, _, _ := newProfileTestUsecase()
+	user := newProfileTestUser()
+	name := " "
+
+	users.On("FindByID", user.ID).Return(user, nil)
+
+	profile, err := usecase.UpdateMe(user.ID, port_auth_usecase.ProfileUpdate{Name: &name})
+
+	assert.Nil(t, profile)
+	var validation *userEntity.ValidationError
+	assert.ErrorAs(t, err, &validation)
+	users.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
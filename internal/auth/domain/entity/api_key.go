@@ -0,0 +1,72 @@
+package auth_entity
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to recognise in
+// logs and by secret scanners.
+const APIKeyPrefix = "sek_"
+
+// apiKeyDisplayLength is how much of the key is stored in clear as its
+// Prefix, enough for an owner to tell their keys apart.
+const apiKeyDisplayLength = len(APIKeyPrefix) + 8
+
+// APIKey lets automation authenticate as its owner without a password. It
+// acts with the owner's current grants narrowed to Scopes, which use the
+// "module:action" vocabulary of ModuleAccessMiddleware. Only the hash of the
+// key is stored.
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k *APIKey) IsActive(now time.Time) bool {
+	return !k.IsRevoked() && !k.IsExpired(now)
+}
+
+// APIKeyIssued carries the key itself, which is only shown once.
+type APIKeyIssued struct {
+	APIKey *APIKey
+	Key    string
+}
+
+// APIKeyPrincipal is who a request authenticated with an API key acts as.
+// Grants hold only the owner's grants within the key's scopes.
+type APIKeyPrincipal struct {
+	KeyID   string
+	UserID  string
+	Email   string
+	Modules []string
+	Actions []string
+	Grants  []string
+}
+
+// APIKeyDisplayPrefix returns the part of key kept in clear.
+func APIKeyDisplayPrefix(key string) string {
+	if len(key) <= apiKeyDisplayLength {
+		return key
+	}
+	return key[:apiKeyDisplayLength]
+}
+
+func IsAPIKey(value string) bool {
+	return strings.HasPrefix(value, APIKeyPrefix)
+}
@@ -0,0 +1,27 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_IsActive(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	assert.True(t, (&APIKey{}).IsActive(now))
+	assert.True(t, (&APIKey{ExpiresAt: &future}).IsActive(now))
+	assert.False(t, (&APIKey{ExpiresAt: &past}).IsActive(now))
+	assert.False(t, (&APIKey{ExpiresAt: &now}).IsActive(now))
+	assert.False(t, (&APIKey{RevokedAt: &past}).IsActive(now))
+}
+
+func TestAPIKeyDisplayPrefix(t *testing.T) {
+	assert.Equal(t, "sek_abcdefgh", APIKeyDisplayPrefix("sek_abcdefghijklmnop"))
+	assert.Equal(t, "sek_ab", APIKeyDisplayPrefix("sek_ab"))
+	assert.True(t, IsAPIKey("sek_abcdefgh"))
+	assert.False(t, IsAPIKey("eyJhbGciOi"))
+}
@@ -0,0 +1,41 @@
+package auth_entity
+
+import "errors"
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrInvalidRefreshToken  = errors.New("invalid refresh token")
+	ErrRefreshTokenReused   = errors.New("refresh token reuse detected")
+	ErrMFANotEnrolled       = errors.New("mfa is not enrolled")
+	ErrMFAAlreadyEnabled    = errors.New("mfa is already enabled")
+	ErrMFAChallengeNotFound = errors.New("mfa challenge not found")
+	ErrInvalidMFAChallenge  = errors.New("invalid mfa challenge")
+	ErrInvalidMFACode       = errors.New("invalid mfa code")
+	// ErrInvalidCredentials is returned for unknown emails and wrong
+	// passwords alike so logins cannot be used to enumerate accounts.
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrTooManyLoginAttempts = errors.New("too many login attempts, try again later")
+	ErrEmailNotVerified     = errors.New("email address is not verified")
+	ErrOneTimeTokenNotFound = errors.New("token not found")
+	ErrInvalidOneTimeToken  = errors.New("invalid or expired token")
+
+	ErrUnknownIdentityProvider  = errors.New("unknown identity provider")
+	ErrInvalidOIDCState         = errors.New("invalid or expired login state")
+	ErrInvalidIDToken           = errors.New("invalid id token")
+	ErrExternalEmailNotVerified = errors.New("identity provider did not verify the email address")
+	ErrExternalDomainNotAllowed = errors.New("email domain is not allowed for this identity provider")
+	ErrExternalIdentityNotFound = errors.New("external identity not found")
+	// ErrExternalAccountNotVerified stops a provider identity from being
+	// linked to an account whose address was never verified.
+	ErrExternalAccountNotVerified = errors.New("verify the account email before signing in with an identity provider")
+
+	ErrOAuthClientNotFound  = errors.New("oauth client not found")
+	ErrOAuthConsentNotFound = errors.New("oauth consent not found")
+
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrInvalidAPIKey      = errors.New("invalid api key")
+	ErrInvalidAPIKeyInput = errors.New("invalid api key request")
+	ErrAPIKeyScopeNotHeld = errors.New("api key scopes must be held by the owner")
+
+	ErrSessionNotFound = errors.New("session not found")
+)
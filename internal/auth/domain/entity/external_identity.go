@@ -0,0 +1,63 @@
+package auth_entity
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+)
+
+// ExternalIdentity links a user to an account at an OpenID Connect provider.
+// Subject is the provider's stable identifier; emails can change hands.
+type ExternalIdentity struct {
+	ID        string
+	UserID    string
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+// ExternalClaims are the verified claims of an ID token.
+type ExternalClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	GivenName     string
+	FamilyName    string
+	Nonce         string
+}
+
+// EmailDomain returns the lowercased part after the last "@".
+func (c *ExternalClaims) EmailDomain() string {
+	at := strings.LastIndex(c.Email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(c.Email[at+1:])
+}
+
+// OIDCLoginState is what the relying party remembers between redirecting
+// to the provider and the callback: the state is only stored hashed, while
+// the nonce and PKCE verifier are needed in the clear to finish the flow.
+type OIDCLoginState struct {
+	ID           string
+	Provider     string
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+func (s *OIDCLoginState) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// PKCEChallenge derives the S256 code challenge sent with the authorization
+// request from the verifier kept for the token request (RFC 7636).
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
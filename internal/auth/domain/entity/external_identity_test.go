@@ -0,0 +1,26 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPKCEChallenge(t *testing.T) {
+	// Example from RFC 7636, appendix B.
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestExternalClaims_EmailDomain(t *testing.T) {
+	assert.Equal(t, "school.edu", (&ExternalClaims{Email: "Ana@School.EDU"}).EmailDomain())
+	assert.Equal(t, "", (&ExternalClaims{Email: "not-an-email"}).EmailDomain())
+}
+
+func TestOIDCLoginState_IsExpired(t *testing.T) {
+	now := time.Now()
+	state := &OIDCLoginState{ExpiresAt: now}
+
+	assert.True(t, state.IsExpired(now))
+	assert.False(t, state.IsExpired(now.Add(-time.Second)))
+}
@@ -0,0 +1,91 @@
+package auth_entity
+
+import "time"
+
+// LockoutPolicy turns a failure count into a wait. The first FreeAttempts
+// failures cost nothing; after that each failure doubles the delay from
+// BaseDelay up to MaxDelay, and reaching LockoutThreshold locks the key for
+// LockoutDuration. Failures older than Window are forgotten. A zero
+// LockoutThreshold disables the lockout and leaves only the backoff.
+type LockoutPolicy struct {
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	Window           time.Duration
+}
+
+func DefaultAccountLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		FreeAttempts:     3,
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		LockoutThreshold: 10,
+		LockoutDuration:  15 * time.Minute,
+		Window:           time.Hour,
+	}
+}
+
+// DefaultIPLockoutPolicy is looser than the account policy because many
+// users can share an address, and it never locks: blocking an IP outright
+// is left to the network layer.
+func DefaultIPLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		FreeAttempts: 10,
+		BaseDelay:    time.Second,
+		MaxDelay:     5 * time.Minute,
+		Window:       time.Hour,
+	}
+}
+
+func (p LockoutPolicy) active(a *LoginAttempts, now time.Time) bool {
+	return a != nil && a.Failures > 0 && (p.Window <= 0 || now.Sub(a.LastFailureAt) < p.Window)
+}
+
+// IsLocked reports whether the attempts put the key in lockout, regardless
+// of whether the lockout already elapsed.
+func (p LockoutPolicy) IsLocked(a *LoginAttempts) bool {
+	return p.LockoutThreshold > 0 && a != nil && a.Failures >= p.LockoutThreshold
+}
+
+// LockedUntil is when the lockout reached by a ends.
+func (p LockoutPolicy) LockedUntil(a *LoginAttempts) time.Time {
+	return a.LastFailureAt.Add(p.LockoutDuration)
+}
+
+// RetryAfter is how long to wait before another attempt; zero means an
+// attempt is allowed now.
+func (p LockoutPolicy) RetryAfter(a *LoginAttempts, now time.Time) time.Duration {
+	if !p.active(a, now) {
+		return 0
+	}
+
+	var next time.Time
+	if p.IsLocked(a) {
+		next = p.LockedUntil(a)
+	} else {
+		next = a.LastFailureAt.Add(p.delay(a.Failures))
+	}
+
+	if wait := next.Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+func (p LockoutPolicy) delay(failures int) time.Duration {
+	over := failures - p.FreeAttempts
+	if over <= 0 || p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < over && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,85 @@
+package auth_entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		FreeAttempts:     3,
+		BaseDelay:        time.Second,
+		MaxDelay:         8 * time.Second,
+		LockoutThreshold: 10,
+		LockoutDuration:  15 * time.Minute,
+		Window:           time.Hour,
+	}
+}
+
+func TestLockoutPolicy_FreeAttempts(t *testing.T) {
+	policy := testLockoutPolicy()
+	now := time.Now()
+
+	assert.Zero(t, policy.RetryAfter(nil, now))
+	assert.Zero(t, policy.RetryAfter(&LoginAttempts{Failures: 3, LastFailureAt: now}, now))
+}
+
+func TestLockoutPolicy_ExponentialBackoff(t *testing.T) {
+	policy := testLockoutPolicy()
+	now := time.Now()
+
+	expected := map[int]time.Duration{
+		4: time.Second,
+		5: 2 * time.Second,
+		6: 4 * time.Second,
+		7: 8 * time.Second,
+		9: 8 * time.Second,
+	}
+	for failures, wait := range expected {
+		attempts := &LoginAttempts{Failures: failures, LastFailureAt: now}
+		assert.Equal(t, wait, policy.RetryAfter(attempts, now), "failures %d", failures)
+	}
+
+	attempts := &LoginAttempts{Failures: 5, LastFailureAt: now}
+	assert.Equal(t, time.Second, policy.RetryAfter(attempts, now.Add(time.Second)))
+	assert.Zero(t, policy.RetryAfter(attempts, now.Add(2*time.Second)))
+}
+
+func TestLockoutPolicy_Lockout(t *testing.T) {
+	policy := testLockoutPolicy()
+	now := time.Now()
+	attempts := &LoginAttempts{Failures: 10, LastFailureAt: now}
+
+	assert.True(t, policy.IsLocked(attempts))
+	assert.Equal(t, now.Add(15*time.Minute), policy.LockedUntil(attempts))
+	assert.Equal(t, 15*time.Minute, policy.RetryAfter(attempts, now))
+	assert.Zero(t, policy.RetryAfter(attempts, now.Add(15*time.Minute)))
+}
+
+func TestLockoutPolicy_WithoutLockout(t *testing.T) {
+	policy := testLockoutPolicy()
+	policy.LockoutThreshold = 0
+	now := time.Now()
+	attempts := &LoginAttempts{Failures: 50, LastFailureAt: now}
+
+	assert.False(t, policy.IsLocked(attempts))
+	assert.Equal(t, 8*time.Second, policy.RetryAfter(attempts, now))
+}
+
+func TestLockoutPolicy_ForgetsOldFailures(t *testing.T) {
+	policy := testLockoutPolicy()
+	now := time.Now()
+	attempts := &LoginAttempts{Failures: 10, LastFailureAt: now.Add(-time.Hour)}
+
+	assert.Zero(t, policy.RetryAfter(attempts, now))
+}
+
+func TestLoginThrottledError(t *testing.T) {
+	err := error(&LoginThrottledError{RetryAfter: 1500 * time.Millisecond})
+
+	assert.True(t, errors.Is(err, ErrTooManyLoginAttempts))
+	assert.Contains(t, err.Error(), "retry after 2s")
+}
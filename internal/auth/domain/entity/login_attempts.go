@@ -0,0 +1,29 @@
+package auth_entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoginAttempts counts consecutive failed logins for one throttling key,
+// either an account or a client IP.
+type LoginAttempts struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// LoginThrottledError carries how long the caller has to wait before the
+// next attempt is considered. It matches ErrTooManyLoginAttempts.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%s (retry after %s)", ErrTooManyLoginAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
@@ -0,0 +1,37 @@
+package auth_entity
+
+import "time"
+
+// LoginResult is what the password step of a login produces: either the
+// final token pair or, for users behind MFA, a challenge to complete first.
+type LoginResult struct {
+	Tokens    *TokenPair
+	Challenge *MFAChallengeTicket
+}
+
+func (r *LoginResult) MFARequired() bool {
+	return r != nil && r.Challenge != nil
+}
+
+// MFAChallengeTicket is handed to the client in place of tokens. Enrollment
+// is set when MFA is enforced for a user who has not enrolled yet; the
+// first valid code confirms it.
+type MFAChallengeTicket struct {
+	Token      string
+	ExpiresIn  time.Duration
+	Enrollment *MFAEnrollment
+}
+
+// MFAEnrollment carries a freshly generated TOTP secret and the otpauth URI
+// authenticator apps read from a QR code.
+type MFAEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// MFAVerification is the result of completing a challenge. RecoveryCodes is
+// only set when the challenge also confirmed a pending enrollment.
+type MFAVerification struct {
+	Tokens        *TokenPair
+	RecoveryCodes []string
+}
@@ -0,0 +1,36 @@
+package auth_entity
+
+import "time"
+
+// MaxMFAChallengeAttempts bounds how many codes can be tried against a
+// single challenge before the user has to log in with the password again.
+const MaxMFAChallengeAttempts = 5
+
+// MFAChallenge is the server side of the short-lived token handed out by the
+// password step of a login. Only the hash of the token is stored.
+type MFAChallenge struct {
+	ID        string
+	UserID    string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	Attempts  int
+	CreatedAt time.Time
+}
+
+func NewMFAChallenge(id, userID, tokenHash string, expiresAt time.Time) *MFAChallenge {
+	return &MFAChallenge{
+		ID:        id,
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (c *MFAChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+func (c *MFAChallenge) IsUsed() bool {
+	return c.UsedAt != nil
+}
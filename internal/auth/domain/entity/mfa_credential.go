@@ -0,0 +1,27 @@
+package auth_entity
+
+import "time"
+
+// MFACredential is a user's TOTP secret. It stays pending until the user
+// proves they can generate codes from it; only confirmed credentials gate
+// logins. LastUsedStep is the last accepted TOTP time step, so a code can
+// not be replayed within its validity window.
+type MFACredential struct {
+	UserID       string
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func NewMFACredential(userID, secret string) *MFACredential {
+	return &MFACredential{
+		UserID: userID,
+		Secret: secret,
+	}
+}
+
+func (c *MFACredential) IsConfirmed() bool {
+	return c != nil && c.ConfirmedAt != nil
+}
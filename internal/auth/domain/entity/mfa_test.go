@@ -0,0 +1,42 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMFACredential_IsConfirmed(t *testing.T) {
+	credential := NewMFACredential("user-1", "SECRET")
+	assert.False(t, credential.IsConfirmed())
+
+	now := time.Now()
+	credential.ConfirmedAt = &now
+	assert.True(t, credential.IsConfirmed())
+
+	var missing *MFACredential
+	assert.False(t, missing.IsConfirmed())
+}
+
+func TestMFAChallenge_IsExpired(t *testing.T) {
+	now := time.Now()
+	challenge := NewMFAChallenge("ch-1", "user-1", "hash", now)
+
+	assert.True(t, challenge.IsExpired(now))
+	assert.False(t, challenge.IsExpired(now.Add(-time.Second)))
+	assert.False(t, challenge.IsUsed())
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	assert.Equal(t, "abcdefghijklmnop", NormalizeRecoveryCode("abcdefgh-ijklmnop"))
+	assert.Equal(t, "abcdefghijklmnop", NormalizeRecoveryCode("ABCDEFGH IJKLMNOP"))
+}
+
+func TestLoginResult_MFARequired(t *testing.T) {
+	assert.False(t, (&LoginResult{Tokens: &TokenPair{}}).MFARequired())
+	assert.True(t, (&LoginResult{Challenge: &MFAChallengeTicket{}}).MFARequired())
+
+	var missing *LoginResult
+	assert.False(t, missing.MFARequired())
+}
@@ -0,0 +1,232 @@
+package auth_entity
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// OAuthError is an error with an RFC 6749 error code, so the token endpoint
+// can answer in the format clients expect.
+type OAuthError struct {
+	Code    string
+	Message string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Message
+}
+
+var (
+	ErrOAuthInvalidRequest       = &OAuthError{Code: "invalid_request", Message: "invalid request"}
+	ErrOAuthInvalidClient        = &OAuthError{Code: "invalid_client", Message: "client authentication failed"}
+	ErrOAuthInvalidGrant         = &OAuthError{Code: "invalid_grant", Message: "invalid or expired grant"}
+	ErrOAuthUnauthorizedClient   = &OAuthError{Code: "unauthorized_client", Message: "client may not use this grant type"}
+	ErrOAuthUnsupportedGrantType = &OAuthError{Code: "unsupported_grant_type", Message: "unsupported grant type"}
+	ErrOAuthInvalidScope         = &OAuthError{Code: "invalid_scope", Message: "invalid scope"}
+	ErrOAuthAccessDenied         = &OAuthError{Code: "access_denied", Message: "access denied"}
+	ErrOAuthUnsupportedResponse  = &OAuthError{Code: "unsupported_response_type", Message: "unsupported response type"}
+)
+
+// OAuthClient is a third-party application registered to call the API.
+// Confidential clients authenticate with a secret and may use the client
+// credentials grant, acting with their owner's permissions narrowed to
+// their scopes. Public clients only get the authorization code grant.
+type OAuthClient struct {
+	ID           string
+	ClientID     string
+	SecretHash   string
+	Name         string
+	RedirectURIs []string
+	GrantTypes   []string
+	Scopes       []string
+	Confidential bool
+	OwnerID      string
+	CreatedAt    time.Time
+}
+
+func (c *OAuthClient) AllowsGrantType(grantType string) bool {
+	return containsString(c.GrantTypes, grantType)
+}
+
+// AllowsRedirectURI requires an exact match, as RFC 6749 recommends.
+func (c *OAuthClient) AllowsRedirectURI(redirectURI string) bool {
+	return containsString(c.RedirectURIs, redirectURI)
+}
+
+// AllowedScopes narrows requested to what the client is registered for; an
+// empty request means every registered scope. It fails on a scope outside
+// the registration.
+func (c *OAuthClient) AllowedScopes(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return c.Scopes, nil
+	}
+	for _, scope := range requested {
+		if !containsString(c.Scopes, scope) {
+			return nil, &OAuthError{Code: ErrOAuthInvalidScope.Code, Message: "scope not allowed for client: " + scope}
+		}
+	}
+	return requested, nil
+}
+
+// OAuthAuthorizationCode is a single-use code handed to the client after the
+// user consents, bound to the redirect URI and the PKCE challenge.
+type OAuthAuthorizationCode struct {
+	ID            string
+	CodeHash      string
+	ClientID      string
+	UserID        string
+	RedirectURI   string
+	Scopes        []string
+	CodeChallenge string
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+}
+
+func (c *OAuthAuthorizationCode) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+// OAuthConsent records the scopes a user approved for a client, so later
+// authorizations within them skip the consent step.
+type OAuthConsent struct {
+	ID        string
+	UserID    string
+	ClientID  string
+	Scopes    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (c *OAuthConsent) Covers(scopes []string) bool {
+	if c == nil {
+		return false
+	}
+	for _, scope := range scopes {
+		if !containsString(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// OAuthClientRegistration carries the client secret, which is only shown
+// once; the client keeps just its hash.
+type OAuthClientRegistration struct {
+	Client       *OAuthClient
+	ClientSecret string
+}
+
+// OAuthAuthorizationRequest holds the query parameters of an authorization
+// request (RFC 6749 4.1.1, RFC 7636 4.3).
+type OAuthAuthorizationRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// OAuthAuthorization is the outcome of an authorization request: either the
+// consent to ask the user for, or where to send the browser next.
+type OAuthAuthorization struct {
+	Client          *OAuthClient
+	Scopes          []string
+	ConsentRequired bool
+	RedirectTo      string
+}
+
+// OAuthTokenRequest holds the token endpoint parameters, with the client
+// credentials taken from either the Basic header or the form.
+type OAuthTokenRequest struct {
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	Scope        string
+}
+
+type OAuthToken struct {
+	AccessToken string
+	ExpiresIn   time.Duration
+	Scopes      []string
+}
+
+// OAuthIntrospection describes a token as in RFC 7662. Subject is the user
+// for delegated tokens and the client for client credentials tokens.
+type OAuthIntrospection struct {
+	Active    bool
+	Scope     string
+	ClientID  string
+	Subject   string
+	TokenID   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// ParseScope reads a space-separated scope string. Scopes use the grant
+// vocabulary of ModuleAccessMiddleware, "module:action", e.g. "users:read".
+func ParseScope(value string) ([]string, error) {
+	scopes := []string{}
+	for _, scope := range strings.Fields(value) {
+		if err := ValidateScope(scope); err != nil {
+			return nil, err
+		}
+		if !containsString(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes, nil
+}
+
+func ValidateScope(scope string) error {
+	module, action, ok := strings.Cut(scope, ":")
+	if !ok || module == "" || action == "" || strings.Contains(action, ":") {
+		return &OAuthError{Code: ErrOAuthInvalidScope.Code, Message: "invalid scope: " + scope}
+	}
+	return nil
+}
+
+// ValidateRedirectURI accepts absolute URIs without a fragment. Plain http
+// is only allowed for loopback addresses, for native and development
+// clients.
+func ValidateRedirectURI(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" || parsed.Fragment != "" {
+		return &OAuthError{Code: ErrOAuthInvalidRequest.Code, Message: "invalid redirect uri: " + value}
+	}
+	switch parsed.Scheme {
+	case "https":
+		return nil
+	case "http":
+		host := parsed.Hostname()
+		if host == "localhost" || host == "127.0.0.1" || host == "::1" {
+			return nil
+		}
+	}
+	return &OAuthError{Code: ErrOAuthInvalidRequest.Code, Message: "redirect uri must use https: " + value}
+}
+
+func ScopeString(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
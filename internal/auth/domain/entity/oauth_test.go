@@ -0,0 +1,82 @@
+package auth_entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScope(t *testing.T) {
+	scopes, err := ParseScope("users:read  roles:read users:read")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"roles:read", "users:read"}, scopes)
+
+	scopes, err = ParseScope("")
+	require.NoError(t, err)
+	assert.Empty(t, scopes)
+
+	for _, invalid := range []string{"users", ":read", "users:", "users:read:denied"} {
+		_, err := ParseScope(invalid)
+
+		var oauthErr *OAuthError
+		assert.True(t, errors.As(err, &oauthErr), invalid)
+		assert.Equal(t, "invalid_scope", oauthErr.Code)
+	}
+}
+
+func TestValidateRedirectURI(t *testing.T) {
+	for _, valid := range []string{"https://lms.example.com/callback", "http://localhost:8080/cb", "http://127.0.0.1/cb"} {
+		assert.NoError(t, ValidateRedirectURI(valid), valid)
+	}
+	for _, invalid := range []string{"http://lms.example.com/callback", "/callback", "https://lms.example.com/cb#frag", "lms.example.com"} {
+		assert.Error(t, ValidateRedirectURI(invalid), invalid)
+	}
+}
+
+func TestOAuthClient_AllowedScopes(t *testing.T) {
+	client := &OAuthClient{Scopes: []string{"users:read", "roles:read"}}
+
+	scopes, err := client.AllowedScopes(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users:read", "roles:read"}, scopes)
+
+	scopes, err = client.AllowedScopes([]string{"users:read"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users:read"}, scopes)
+
+	_, err = client.AllowedScopes([]string{"users:delete"})
+	assert.ErrorContains(t, err, "users:delete")
+}
+
+func TestOAuthClient_Allows(t *testing.T) {
+	client := &OAuthClient{
+		RedirectURIs: []string{"https://lms.example.com/callback"},
+		GrantTypes:   []string{GrantTypeAuthorizationCode},
+	}
+
+	assert.True(t, client.AllowsRedirectURI("https://lms.example.com/callback"))
+	assert.False(t, client.AllowsRedirectURI("https://lms.example.com/callback/"))
+	assert.True(t, client.AllowsGrantType(GrantTypeAuthorizationCode))
+	assert.False(t, client.AllowsGrantType(GrantTypeClientCredentials))
+}
+
+func TestOAuthConsent_Covers(t *testing.T) {
+	consent := &OAuthConsent{Scopes: []string{"users:read", "roles:read"}}
+
+	assert.True(t, consent.Covers([]string{"users:read"}))
+	assert.False(t, consent.Covers([]string{"users:read", "users:update"}))
+
+	var missing *OAuthConsent
+	assert.False(t, missing.Covers([]string{"users:read"}))
+}
+
+func TestOAuthAuthorizationCode_IsExpired(t *testing.T) {
+	now := time.Now()
+	code := &OAuthAuthorizationCode{ExpiresAt: now.Add(time.Minute)}
+
+	assert.False(t, code.IsExpired(now))
+	assert.True(t, code.IsExpired(now.Add(time.Minute)))
+}
@@ -0,0 +1,44 @@
+package auth_entity
+
+import "time"
+
+type TokenPurpose string
+
+const (
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+	TokenPurposeEmailChange       TokenPurpose = "email_change"
+)
+
+// OneTimeToken backs the links mailed for password resets, email
+// verification and email changes. Only the hash is stored and each token
+// works once.
+type OneTimeToken struct {
+	ID        string
+	UserID    string
+	Purpose   TokenPurpose
+	TokenHash string
+	// NewEmail is the address an email change moves the user to.
+	NewEmail  string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func NewOneTimeToken(id, userID string, purpose TokenPurpose, tokenHash string, expiresAt time.Time) *OneTimeToken {
+	return &OneTimeToken{
+		ID:        id,
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (t *OneTimeToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+func (t *OneTimeToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
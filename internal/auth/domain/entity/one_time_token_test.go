@@ -0,0 +1,20 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOneTimeToken_ExpiryAndUse(t *testing.T) {
+	now := time.Now()
+	token := NewOneTimeToken("t-1", "user-1", TokenPurposePasswordReset, "hash", now)
+
+	assert.True(t, token.IsExpired(now))
+	assert.False(t, token.IsExpired(now.Add(-time.Second)))
+	assert.False(t, token.IsUsed())
+
+	token.UsedAt = &now
+	assert.True(t, token.IsUsed())
+}
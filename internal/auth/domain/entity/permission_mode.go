@@ -0,0 +1,12 @@
+package auth_entity
+
+// PermissionMode controls how a user's permissions travel in access tokens.
+type PermissionMode string
+
+const (
+	// PermissionModeEmbedded copies modules and actions into the token.
+	PermissionModeEmbedded PermissionMode = "embedded"
+	// PermissionModeReference only carries a permission version; the
+	// permissions themselves are resolved on each request.
+	PermissionModeReference PermissionMode = "reference"
+)
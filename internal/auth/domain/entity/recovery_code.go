@@ -0,0 +1,41 @@
+package auth_entity
+
+import (
+	"strings"
+	"time"
+)
+
+// RecoveryCode is a single-use fallback for a lost authenticator. Codes are
+// shown once when MFA is confirmed and only their hashes are kept.
+type RecoveryCode struct {
+	ID        string
+	UserID    string
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func NewRecoveryCode(id, userID, codeHash string) *RecoveryCode {
+	return &RecoveryCode{
+		ID:       id,
+		UserID:   userID,
+		CodeHash: codeHash,
+	}
+}
+
+func (r *RecoveryCode) IsUsed() bool {
+	return r.UsedAt != nil
+}
+
+// NormalizeRecoveryCode drops separators, spaces and case so a code typed
+// as "abcde-fghij" or "ABCDE FGHIJ" hashes the same way.
+func NormalizeRecoveryCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(code) {
+		if r == '-' || r == ' ' {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRefreshToken(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	token := NewRefreshToken("rt-1", "user-1", "family-1", "hash", expiresAt)
+
+	assert.Equal(t, "rt-1", token.ID)
+	assert.Equal(t, "user-1", token.UserID)
+	assert.Equal(t, "family-1", token.FamilyID)
+	assert.Equal(t, "hash", token.TokenHash)
+	assert.Equal(t, expiresAt, token.ExpiresAt)
+	assert.False(t, token.IsUsed())
+	assert.False(t, token.IsRevoked())
+}
+
+func TestRefreshToken_IsExpired(t *testing.T) {
+	now := time.Now()
+	token := NewRefreshToken("rt-1", "user-1", "family-1", "hash", now)
+
+	assert.True(t, token.IsExpired(now))
+	assert.True(t, token.IsExpired(now.Add(time.Second)))
+	assert.False(t, token.IsExpired(now.Add(-time.Second)))
+}
+
+func TestRefreshToken_UsedAndRevoked(t *testing.T) {
+	now := time.Now()
+	token := NewRefreshToken("rt-1", "user-1", "family-1", "hash", now.Add(time.Hour))
+
+	token.UsedAt = &now
+	token.RevokedAt = &now
+
+	assert.True(t, token.IsUsed())
+	assert.True(t, token.IsRevoked())
+}
@@ -0,0 +1,34 @@
+package auth_entity
+
+import "time"
+
+type RevokedToken struct {
+	JTI       string
+	UserID    string
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
+
+func NewRevokedToken(jti, userID string, expiresAt, revokedAt time.Time) *RevokedToken {
+	return &RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+		RevokedAt: revokedAt,
+	}
+}
+
+// UserRevocation invalidates every token issued to the user at or before
+// RevokedAt, which is how "log out everywhere" is expressed without having
+// to enumerate the outstanding token ids.
+type UserRevocation struct {
+	UserID    string
+	RevokedAt time.Time
+}
+
+func (r *UserRevocation) Covers(issuedAt time.Time) bool {
+	if r == nil {
+		return false
+	}
+	return !issuedAt.After(r.RevokedAt.Truncate(time.Second))
+}
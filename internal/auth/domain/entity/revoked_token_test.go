@@ -0,0 +1,35 @@
+package auth_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRevokedToken(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	revokedAt := time.Now()
+
+	token := NewRevokedToken("jti-1", "user-1", expiresAt, revokedAt)
+
+	assert.Equal(t, "jti-1", token.JTI)
+	assert.Equal(t, "user-1", token.UserID)
+	assert.Equal(t, expiresAt, token.ExpiresAt)
+	assert.Equal(t, revokedAt, token.RevokedAt)
+}
+
+func TestUserRevocation_Covers(t *testing.T) {
+	revokedAt := time.Date(2025, 1, 1, 12, 0, 0, 500, time.UTC)
+	revocation := &UserRevocation{UserID: "user-1", RevokedAt: revokedAt}
+
+	assert.True(t, revocation.Covers(revokedAt.Add(-time.Hour)))
+	assert.True(t, revocation.Covers(revokedAt.Truncate(time.Second)))
+	assert.False(t, revocation.Covers(revokedAt.Add(time.Second)))
+}
+
+func TestUserRevocation_Covers_Nil(t *testing.T) {
+	var revocation *UserRevocation
+
+	assert.False(t, revocation.Covers(time.Now()))
+}
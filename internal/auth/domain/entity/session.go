@@ -0,0 +1,92 @@
+package auth_entity
+
+import (
+	"strings"
+	"time"
+)
+
+// ClientInfo describes where a login came from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// Session is one login on one device. Its ID is the refresh token family
+// issued at login and travels in the access tokens as the "sid" claim, so
+// ending the session revokes every token issued under it.
+type Session struct {
+	ID         string
+	UserID     string
+	Device     string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	EndedAt    *time.Time
+}
+
+func NewSession(id, userID string, client ClientInfo, now time.Time) *Session {
+	return &Session{
+		ID:         id,
+		UserID:     userID,
+		Device:     DescribeDevice(client.UserAgent),
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+}
+
+func (s *Session) IsEnded() bool {
+	return s.EndedAt != nil
+}
+
+var browsers = []struct{ token, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+	{"PostmanRuntime/", "Postman"},
+	{"okhttp/", "OkHttp"},
+	{"Go-http-client/", "Go HTTP client"},
+}
+
+var platforms = []struct{ token, name string }{
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Android", "Android"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// DescribeDevice turns a User-Agent into a short label such as "Chrome on
+// macOS". Order matters: Chromium browsers also claim to be Safari, and
+// Android also claims to be Linux.
+func DescribeDevice(userAgent string) string {
+	browser := firstMatch(userAgent, browsers)
+	platform := firstMatch(userAgent, platforms)
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return "Unknown device"
+	}
+}
+
+func firstMatch(userAgent string, candidates []struct{ token, name string }) string {
+	for _, candidate := range candidates {
+		if strings.Contains(userAgent, candidate.token) {
+			return candidate.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,9 @@
+package auth_entity
+
+import "time"
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
@@ -0,0 +1,27 @@
+package auth_event
+
+import "time"
+
+type AccountLockedEvent struct {
+	Email       string
+	Failures    int
+	LockedUntil time.Time
+	Date        time.Time
+}
+
+func NewAccountLockedEvent(email string, failures int, lockedUntil time.Time) *AccountLockedEvent {
+	return &AccountLockedEvent{
+		Email:       email,
+		Failures:    failures,
+		LockedUntil: lockedUntil,
+		Date:        time.Now(),
+	}
+}
+
+func (e *AccountLockedEvent) EventName() string {
+	return "auth.account_locked"
+}
+
+func (e *AccountLockedEvent) OccurredOn() time.Time {
+	return e.Date
+}
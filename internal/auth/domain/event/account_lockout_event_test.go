@@ -0,0 +1,58 @@
+package auth_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAccountLockedEvent(t *testing.T) {
+	until := time.Now().Add(15 * time.Minute)
+	event := NewAccountLockedEvent("john@example.com", 10, until)
+
+	assert.Equal(t, "john@example.com", event.Email)
+	assert.Equal(t, 10, event.Failures)
+	assert.Equal(t, until, event.LockedUntil)
+	assert.Equal(t, "auth.account_locked", event.EventName())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
+
+func TestNewAccountUnlockedEvent(t *testing.T) {
+	event := NewAccountUnlockedEvent("john@example.com", UnlockReasonAdmin)
+
+	assert.Equal(t, "john@example.com", event.Email)
+	assert.Equal(t, UnlockReasonAdmin, event.Reason)
+	assert.Equal(t, "auth.account_unlocked", event.EventName())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
+
+func TestNewLoginSucceededEvent(t *testing.T) {
+	event := NewLoginSucceededEvent("user-123", "john@example.com", "session-1", "10.0.0.1", "curl")
+
+	assert.Equal(t, "user-123", event.UserID)
+	assert.Equal(t, "session-1", event.SessionID)
+	assert.Equal(t, "10.0.0.1", event.IP)
+	assert.Equal(t, "curl", event.UserAgent)
+	assert.Equal(t, "auth.login_succeeded", event.EventName())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
+
+func TestNewLoginFailedEvent(t *testing.T) {
+	event := NewLoginFailedEvent("john@example.com", "", LoginFailureInvalidCredentials, "10.0.0.1", "curl")
+
+	assert.Equal(t, "john@example.com", event.Email)
+	assert.Empty(t, event.UserID)
+	assert.Equal(t, LoginFailureInvalidCredentials, event.Reason)
+	assert.Equal(t, "auth.login_failed", event.EventName())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
+
+func TestCatalog(t *testing.T) {
+	var names []string
+	for _, factory := range Catalog() {
+		names = append(names, factory().EventName())
+	}
+
+	assert.Equal(t, []string{"auth.login_succeeded", "auth.login_failed", "auth.account_locked", "auth.account_unlocked"}, names)
+}
@@ -0,0 +1,33 @@
+package auth_event
+
+import "time"
+
+const (
+	// UnlockReasonExpired is used when a locked account logs in again after
+	// the lockout elapsed.
+	UnlockReasonExpired = "expired"
+	// UnlockReasonAdmin is used when an administrator lifts the lockout.
+	UnlockReasonAdmin = "admin"
+)
+
+type AccountUnlockedEvent struct {
+	Email  string
+	Reason string
+	Date   time.Time
+}
+
+func NewAccountUnlockedEvent(email string, reason string) *AccountUnlockedEvent {
+	return &AccountUnlockedEvent{
+		Email:  email,
+		Reason: reason,
+		Date:   time.Now(),
+	}
+}
+
+func (e *AccountUnlockedEvent) EventName() string {
+	return "auth.account_unlocked"
+}
+
+func (e *AccountUnlockedEvent) OccurredOn() time.Time {
+	return e.Date
+}
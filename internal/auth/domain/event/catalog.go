@@ -0,0 +1,13 @@
+package auth_event
+
+import shared_event "github.com/williamkoller/system-education/shared/domain/event"
+
+// Catalog lists the events the auth module raises.
+func Catalog() []func() shared_event.Event {
+	return []func() shared_event.Event{
+		func() shared_event.Event { return &LoginSucceededEvent{} },
+		func() shared_event.Event { return &LoginFailedEvent{} },
+		func() shared_event.Event { return &AccountLockedEvent{} },
+		func() shared_event.Event { return &AccountUnlockedEvent{} },
+	}
+}
@@ -0,0 +1,45 @@
+package auth_event
+
+import "time"
+
+const (
+	// LoginFailureInvalidCredentials covers unknown emails and wrong
+	// passwords alike, as the login response does.
+	LoginFailureInvalidCredentials = "invalid_credentials"
+	// LoginFailureThrottled is used when the attempt was refused before the
+	// password was checked because of earlier failures.
+	LoginFailureThrottled = "throttled"
+	// LoginFailureEmailNotVerified is used when the password was right but
+	// the address has not been confirmed yet.
+	LoginFailureEmailNotVerified = "email_not_verified"
+)
+
+// LoginFailedEvent carries the user ID only when the password was right, so
+// subscribers cannot tell registered emails apart from the event.
+type LoginFailedEvent struct {
+	Email     string
+	UserID    string
+	Reason    string
+	IP        string
+	UserAgent string
+	Date      time.Time
+}
+
+func NewLoginFailedEvent(email, userID, reason, ip, userAgent string) *LoginFailedEvent {
+	return &LoginFailedEvent{
+		Email:     email,
+		UserID:    userID,
+		Reason:    reason,
+		IP:        ip,
+		UserAgent: userAgent,
+		Date:      time.Now(),
+	}
+}
+
+func (e *LoginFailedEvent) EventName() string {
+	return "auth.login_failed"
+}
+
+func (e *LoginFailedEvent) OccurredOn() time.Time {
+	return e.Date
+}
@@ -0,0 +1,31 @@
+package auth_event
+
+import "time"
+
+type LoginSucceededEvent struct {
+	UserID    string
+	Email     string
+	SessionID string
+	IP        string
+	UserAgent string
+	Date      time.Time
+}
+
+func NewLoginSucceededEvent(userID, email, sessionID, ip, userAgent string) *LoginSucceededEvent {
+	return &LoginSucceededEvent{
+		UserID:    userID,
+		Email:     email,
+		SessionID: sessionID,
+		IP:        ip,
+		UserAgent: userAgent,
+		Date:      time.Now(),
+	}
+}
+
+func (e *LoginSucceededEvent) EventName() string {
+	return "auth.login_succeeded"
+}
+
+func (e *LoginSucceededEvent) OccurredOn() time.Time {
+	return e.Date
+}
@@ -0,0 +1,113 @@
+package infra_cryptography
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+)
+
+// AsymmetricTokenManager signs with a single active key and verifies against
+// every key it knows about, so tokens signed with a retired key stay valid
+// until they expire while the new key is rolled out.
+type AsymmetricTokenManager struct {
+	signingKey       *SigningKey
+	verificationKeys map[string]*VerificationKey
+	expiresIn        time.Duration
+}
+
+var (
+	_ port_cryptography.TokenManager   = &AsymmetricTokenManager{}
+	_ port_cryptography.KeySetProvider = &AsymmetricTokenManager{}
+)
+
+func NewAsymmetricTokenManager(signingKey *SigningKey, verificationKeys []*VerificationKey, expiresIn time.Duration) (*AsymmetricTokenManager, error) {
+	if signingKey == nil || signingKey.ID == "" {
+		return nil, errors.New("signing key with a key id is required")
+	}
+
+	keys := map[string]*VerificationKey{signingKey.ID: signingKey.VerificationKey()}
+	for _, key := range verificationKeys {
+		if key == nil || key.ID == "" {
+			return nil, errors.New("verification keys must have a key id")
+		}
+		if _, exists := keys[key.ID]; exists {
+			return nil, errors.New("duplicate key id: " + key.ID)
+		}
+		keys[key.ID] = key
+	}
+
+	return &AsymmetricTokenManager{
+		signingKey:       signingKey,
+		verificationKeys: keys,
+		expiresIn:        expiresIn,
+	}, nil
+}
+
+func (m *AsymmetricTokenManager) Sign(data map[string]interface{}) (string, error) {
+	token := jwt.NewWithClaims(m.signingKey.Method, buildClaims(data, m.expiresIn))
+	token.Header["kid"] = m.signingKey.ID
+	return token.SignedString(m.signingKey.PrivateKey)
+}
+
+func (m *AsymmetricTokenManager) Verify(tokenStr string) (map[string]interface{}, error) {
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := m.verificationKeys[kid]
+		if !ok {
+			return nil, errors.New("unknown key id")
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.PublicKey, nil
+	})
+
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if claims, ok := claimsToMap(token); ok {
+		return claims, nil
+	}
+
+	return nil, errors.New("could not parse claims")
+}
+
+func (m *AsymmetricTokenManager) PublicKeys() auth_entity.JSONWebKeySet {
+	ids := make([]string, 0, len(m.verificationKeys))
+	for id := range m.verificationKeys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	set := auth_entity.JSONWebKeySet{Keys: make([]auth_entity.JSONWebKey, 0, len(ids))}
+	for _, id := range ids {
+		key := m.verificationKeys[id]
+		jwk := auth_entity.JSONWebKey{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
@@ -0,0 +1,215 @@
+package infra_cryptography
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func newRSAKeyFile(t *testing.T) (string, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)), key
+}
+
+func newEd25519KeyFile(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return writePEM(t, "PRIVATE KEY", der), key
+}
+
+func TestLoadSigningKey_RSA(t *testing.T) {
+	path, _ := newRSAKeyFile(t)
+
+	key, err := LoadSigningKey("rsa-1", path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "rsa-1", key.ID)
+	assert.Equal(t, "RS256", key.Method.Alg())
+}
+
+func TestLoadSigningKey_Ed25519(t *testing.T) {
+	path, _ := newEd25519KeyFile(t)
+
+	key, err := LoadSigningKey("ed-1", path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "EdDSA", key.Method.Alg())
+}
+
+func TestLoadSigningKey_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(path, []byte("not a pem"), 0o600))
+
+	_, err := LoadSigningKey("kid", path)
+	assert.Error(t, err)
+
+	_, err = LoadSigningKey("kid", filepath.Join(t.TempDir(), "missing.pem"))
+	assert.Error(t, err)
+}
+
+func TestLoadVerificationKey_PublicKey(t *testing.T) {
+	_, key := newRSAKeyFile(t)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	path := writePEM(t, "PUBLIC KEY", der)
+
+	verification, err := LoadVerificationKey("rsa-old", path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "rsa-old", verification.ID)
+	assert.Equal(t, "RS256", verification.Method.Alg())
+}
+
+func TestAsymmetricTokenManager_SignAndVerify(t *testing.T) {
+	for name, newKey := range map[string]func(*testing.T) string{
+		"rsa":     func(t *testing.T) string { p, _ := newRSAKeyFile(t); return p },
+		"ed25519": func(t *testing.T) string { p, _ := newEd25519KeyFile(t); return p },
+	} {
+		t.Run(name, func(t *testing.T) {
+			signing, err := LoadSigningKey("key-1", newKey(t))
+			require.NoError(t, err)
+			manager, err := NewAsymmetricTokenManager(signing, nil, time.Hour)
+			require.NoError(t, err)
+
+			token, err := manager.Sign(map[string]interface{}{"user_id": "user-123"})
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, "key-1", parsed.Header["kid"])
+
+			claims, err := manager.Verify(token)
+			require.NoError(t, err)
+			assert.Equal(t, "user-123", claims["user_id"])
+			assert.NotEmpty(t, claims["jti"])
+		})
+	}
+}
+
+func TestAsymmetricTokenManager_VerifiesRotatedKeys(t *testing.T) {
+	oldPath, _ := newRSAKeyFile(t)
+	newPath, _ := newEd25519KeyFile(t)
+
+	oldKey, err := LoadSigningKey("old", oldPath)
+	require.NoError(t, err)
+	newKey, err := LoadSigningKey("new", newPath)
+	require.NoError(t, err)
+
+	oldManager, err := NewAsymmetricTokenManager(oldKey, nil, time.Hour)
+	require.NoError(t, err)
+	token, err := oldManager.Sign(map[string]interface{}{"user_id": "user-123"})
+	require.NoError(t, err)
+
+	rotated, err := NewAsymmetricTokenManager(newKey, []*VerificationKey{oldKey.VerificationKey()}, time.Hour)
+	require.NoError(t, err)
+
+	claims, err := rotated.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", claims["user_id"])
+
+	retired, err := NewAsymmetricTokenManager(newKey, nil, time.Hour)
+	require.NoError(t, err)
+	_, err = retired.Verify(token)
+	assert.EqualError(t, err, "invalid token")
+}
+
+func TestAsymmetricTokenManager_RejectsAlgorithmMismatch(t *testing.T) {
+	path, _ := newRSAKeyFile(t)
+	signing, err := LoadSigningKey("key-1", path)
+	require.NoError(t, err)
+	manager, err := NewAsymmetricTokenManager(signing, nil, time.Hour)
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()})
+	token.Header["kid"] = "key-1"
+	forged, err := token.SignedString([]byte("guessed-secret"))
+	require.NoError(t, err)
+
+	_, err = manager.Verify(forged)
+	assert.EqualError(t, err, "invalid token")
+}
+
+func TestAsymmetricTokenManager_RejectsHMACTokens(t *testing.T) {
+	path, _ := newRSAKeyFile(t)
+	signing, err := LoadSigningKey("key-1", path)
+	require.NoError(t, err)
+	manager, err := NewAsymmetricTokenManager(signing, nil, time.Hour)
+	require.NoError(t, err)
+
+	token, err := NewJWTTokenManager("secret", time.Hour).Sign(map[string]interface{}{"user_id": "user-123"})
+	require.NoError(t, err)
+
+	_, err = manager.Verify(token)
+	assert.EqualError(t, err, "invalid token")
+}
+
+func TestNewAsymmetricTokenManager_Validation(t *testing.T) {
+	path, _ := newRSAKeyFile(t)
+	signing, err := LoadSigningKey("key-1", path)
+	require.NoError(t, err)
+
+	_, err = NewAsymmetricTokenManager(nil, nil, time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewAsymmetricTokenManager(signing, []*VerificationKey{signing.VerificationKey()}, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestAsymmetricTokenManager_PublicKeys(t *testing.T) {
+	rsaPath, rsaKey := newRSAKeyFile(t)
+	edPath, _ := newEd25519KeyFile(t)
+
+	signing, err := LoadSigningKey("rsa-1", rsaPath)
+	require.NoError(t, err)
+	previous, err := LoadVerificationKey("ed-1", edPath)
+	require.NoError(t, err)
+
+	manager, err := NewAsymmetricTokenManager(signing, []*VerificationKey{previous}, time.Hour)
+	require.NoError(t, err)
+
+	set := manager.PublicKeys()
+
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, "ed-1", set.Keys[0].Kid)
+	assert.Equal(t, "OKP", set.Keys[0].Kty)
+	assert.Equal(t, "Ed25519", set.Keys[0].Crv)
+	assert.NotEmpty(t, set.Keys[0].X)
+
+	assert.Equal(t, "rsa-1", set.Keys[1].Kid)
+	assert.Equal(t, "RSA", set.Keys[1].Kty)
+	assert.Equal(t, "RS256", set.Keys[1].Alg)
+	assert.Equal(t, "sig", set.Keys[1].Use)
+	assert.Equal(t, "AQAB", set.Keys[1].E)
+	assert.NotEmpty(t, set.Keys[1].N)
+	assert.Equal(t, rsaKey.PublicKey.N.Bytes(), mustDecode(t, set.Keys[1].N))
+}
+
+func mustDecode(t *testing.T, value string) []byte {
+	t.Helper()
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	require.NoError(t, err)
+	return decoded
+}
@@ -0,0 +1,39 @@
+package infra_cryptography
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func buildClaims(data map[string]interface{}, expiresIn time.Duration) jwt.MapClaims {
+	claims := jwt.MapClaims{}
+
+	for k, v := range data {
+		claims[k] = v
+	}
+
+	if _, ok := claims["jti"]; !ok {
+		claims["jti"] = uuid.New().String()
+	}
+
+	now := time.Now()
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(expiresIn).Unix()
+
+	return claims
+}
+
+func claimsToMap(token *jwt.Token) (map[string]interface{}, bool) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, false
+	}
+
+	result := make(map[string]interface{})
+	for k, v := range claims {
+		result[k] = v
+	}
+	return result, true
+}
@@ -31,25 +31,15 @@ func (j *JWTTokenManager) Verify(tokenStr string) (map[string]interface{}, error
 		return nil, errors.New("invalid token")
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok {
-		result := make(map[string]interface{})
-		for k, v := range claims {
-			result[k] = v
-		}
-		return result, nil
+	if claims, ok := claimsToMap(token); ok {
+		return claims, nil
 	}
 
 	return nil, errors.New("could not parse claims")
 }
 
 func (j *JWTTokenManager) Sign(data map[string]interface{}) (string, error) {
-	claims := jwt.MapClaims{}
-
-	for k, v := range data {
-		claims[k] = v
-	}
-
-	claims["exp"] = time.Now().Add(j.expiresIn).Unix()
+	claims := buildClaims(data, j.expiresIn)
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(j.secretKey))
 }
@@ -159,6 +159,35 @@ func TestJWTTokenManager_Sign_WithMultipleFields(t *testing.T) {
 	assert.NotNil(t, parsedData["exp"]) // exp should be added automatically
 }
 
+func TestJWTTokenManager_Sign_AddsUniqueJTI(t *testing.T) {
+	manager := NewJWTTokenManager("secret-key", time.Hour)
+
+	first, err := manager.Sign(map[string]interface{}{"user_id": "user-123"})
+	require.NoError(t, err)
+	second, err := manager.Sign(map[string]interface{}{"user_id": "user-123"})
+	require.NoError(t, err)
+
+	firstClaims, err := manager.Verify(first)
+	require.NoError(t, err)
+	secondClaims, err := manager.Verify(second)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, firstClaims["jti"])
+	assert.NotEqual(t, firstClaims["jti"], secondClaims["jti"])
+	assert.NotNil(t, firstClaims["iat"])
+}
+
+func TestJWTTokenManager_Sign_KeepsProvidedJTI(t *testing.T) {
+	manager := NewJWTTokenManager("secret-key", time.Hour)
+
+	token, err := manager.Sign(map[string]interface{}{"jti": "fixed-id"})
+	require.NoError(t, err)
+
+	claims, err := manager.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "fixed-id", claims["jti"])
+}
+
 func TestNewJWTTokenManager(t *testing.T) {
 	secret := "test-secret"
 	expiresIn := 2 * time.Hour
@@ -0,0 +1,34 @@
+package infra_cryptography
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+
+	port_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+)
+
+// recoveryCodeSize gives 80 bits per code, printed as two groups of eight
+// base32 characters.
+const recoveryCodeSize = 10
+
+type RecoveryCodeGenerator struct{}
+
+var _ port_cryptography.RecoveryCodeGenerator = &RecoveryCodeGenerator{}
+
+func NewRecoveryCodeGenerator() *RecoveryCodeGenerator {
+	return &RecoveryCodeGenerator{}
+}
+
+func (g *RecoveryCodeGenerator) Generate(count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		buf := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		encoded := strings.ToLower(base32.StdEncoding.EncodeToString(buf))
+		codes = append(codes, encoded[:8]+"-"+encoded[8:])
+	}
+	return codes, nil
+}
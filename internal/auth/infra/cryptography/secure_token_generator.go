@@ -0,0 +1,40 @@
+package infra_cryptography
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	port_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+)
+
+const defaultSecureTokenSize = 32
+
+type SecureTokenGenerator struct {
+	size int
+}
+
+var _ port_cryptography.SecureTokenGenerator = &SecureTokenGenerator{}
+
+func NewSecureTokenGenerator(size int) *SecureTokenGenerator {
+	if size <= 0 {
+		size = defaultSecureTokenSize
+	}
+	return &SecureTokenGenerator{size: size}
+}
+
+func (g *SecureTokenGenerator) Generate() (string, string, error) {
+	buf := make([]byte, g.size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, g.Hash(token), nil
+}
+
+func (g *SecureTokenGenerator) Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,37 @@
+package infra_cryptography
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecureTokenGenerator_Generate(t *testing.T) {
+	generator := NewSecureTokenGenerator(32)
+
+	token, hash, err := generator.Generate()
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, token)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, generator.Hash(token), hash)
+	assert.NotEqual(t, token, hash)
+}
+
+func TestSecureTokenGenerator_Generate_Unique(t *testing.T) {
+	generator := NewSecureTokenGenerator(32)
+
+	first, _, err := generator.Generate()
+	require.NoError(t, err)
+	second, _, err := generator.Generate()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestNewSecureTokenGenerator_DefaultSize(t *testing.T) {
+	generator := NewSecureTokenGenerator(0)
+
+	assert.Equal(t, defaultSecureTokenSize, generator.size)
+}
@@ -0,0 +1,106 @@
+package infra_cryptography
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA1 seed from RFC 6238 appendix B, base32 encoded.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTP_Code_RFC6238Vectors(t *testing.T) {
+	totp := NewTOTP("system-education")
+
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := totp.Code(rfc6238Secret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestTOTP_Validate_AcceptsAdjacentSteps(t *testing.T) {
+	totp := NewTOTP("")
+	at := time.Unix(1111111109, 0)
+
+	step, ok := totp.Validate(rfc6238Secret, "081804", at)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1111111109/30), step)
+
+	step, ok = totp.Validate(rfc6238Secret, "081804", at.Add(30*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1111111109/30), step)
+
+	_, ok = totp.Validate(rfc6238Secret, "081804", at.Add(90*time.Second))
+	assert.False(t, ok)
+}
+
+func TestTOTP_Validate_RejectsMalformedInput(t *testing.T) {
+	totp := NewTOTP("")
+	at := time.Unix(59, 0)
+
+	_, ok := totp.Validate(rfc6238Secret, "28708", at)
+	assert.False(t, ok)
+
+	_, ok = totp.Validate(rfc6238Secret, "000000", at)
+	assert.False(t, ok)
+
+	_, ok = totp.Validate("not base32!", "287082", at)
+	assert.False(t, ok)
+}
+
+func TestTOTP_GenerateSecret(t *testing.T) {
+	totp := NewTOTP("")
+
+	first, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	second, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+
+	code, err := totp.Code(first, time.Now())
+	require.NoError(t, err)
+	_, ok := totp.Validate(first, code, time.Now())
+	assert.True(t, ok)
+}
+
+func TestTOTP_URI(t *testing.T) {
+	totp := NewTOTP("system-education")
+
+	uri, err := url.Parse(totp.URI(rfc6238Secret, "john@example.com"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/system-education:john@example.com", uri.Path)
+	assert.Equal(t, rfc6238Secret, uri.Query().Get("secret"))
+	assert.Equal(t, "system-education", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
+
+func TestRecoveryCodeGenerator_Generate(t *testing.T) {
+	codes, err := NewRecoveryCodeGenerator().Generate(10)
+	require.NoError(t, err)
+
+	assert.Len(t, codes, 10)
+	seen := map[string]bool{}
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-z2-7]{8}-[a-z2-7]{8}$`, code)
+		assert.False(t, seen[code])
+		seen[code] = true
+	}
+}
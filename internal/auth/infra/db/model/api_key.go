@@ -0,0 +1,61 @@
+package auth_model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type APIKey struct {
+	ID         string `gorm:"primaryKey;type:uuid"`
+	UserID     string `gorm:"index"`
+	Name       string
+	Prefix     string
+	KeyHash    string         `gorm:"uniqueIndex"`
+	Scopes     pq.StringArray `gorm:"type:text[]"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+func FromAPIKeyEntity(k *auth_entity.APIKey) *APIKey {
+	if k == nil {
+		return nil
+	}
+	return &APIKey{
+		ID:         k.ID,
+		UserID:     k.UserID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		KeyHash:    k.KeyHash,
+		Scopes:     pq.StringArray(k.Scopes),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+func ToAPIKeyEntity(k *APIKey) *auth_entity.APIKey {
+	if k == nil {
+		return nil
+	}
+	return &auth_entity.APIKey{
+		ID:         k.ID,
+		UserID:     k.UserID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		KeyHash:    k.KeyHash,
+		Scopes:     []string(k.Scopes),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package auth_model
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type LoginAttempt struct {
+	Key           string `gorm:"primaryKey"`
+	Failures      int
+	LastFailureAt time.Time
+}
+
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+func ToLoginAttemptsEntity(a *LoginAttempt) *auth_entity.LoginAttempts {
+	if a == nil {
+		return nil
+	}
+	return &auth_entity.LoginAttempts{
+		Key:           a.Key,
+		Failures:      a.Failures,
+		LastFailureAt: a.LastFailureAt,
+	}
+}
@@ -0,0 +1,117 @@
+package auth_model
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type MFACredential struct {
+	UserID       string `gorm:"primaryKey;type:uuid"`
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (MFACredential) TableName() string {
+	return "mfa_credentials"
+}
+
+type RecoveryCode struct {
+	ID        string `gorm:"primaryKey;type:uuid"`
+	UserID    string `gorm:"index"`
+	CodeHash  string `gorm:"uniqueIndex"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (RecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+type MFAChallenge struct {
+	ID        string `gorm:"primaryKey;type:uuid"`
+	UserID    string `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	Attempts  int
+	CreatedAt time.Time
+}
+
+func (MFAChallenge) TableName() string {
+	return "mfa_challenges"
+}
+
+func FromMFACredentialEntity(c *auth_entity.MFACredential) *MFACredential {
+	if c == nil {
+		return nil
+	}
+	return &MFACredential{
+		UserID:       c.UserID,
+		Secret:       c.Secret,
+		ConfirmedAt:  c.ConfirmedAt,
+		LastUsedStep: c.LastUsedStep,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+}
+
+func ToMFACredentialEntity(c *MFACredential) *auth_entity.MFACredential {
+	if c == nil {
+		return nil
+	}
+	return &auth_entity.MFACredential{
+		UserID:       c.UserID,
+		Secret:       c.Secret,
+		ConfirmedAt:  c.ConfirmedAt,
+		LastUsedStep: c.LastUsedStep,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+}
+
+func FromRecoveryCodeEntity(r *auth_entity.RecoveryCode) *RecoveryCode {
+	if r == nil {
+		return nil
+	}
+	return &RecoveryCode{
+		ID:        r.ID,
+		UserID:    r.UserID,
+		CodeHash:  r.CodeHash,
+		UsedAt:    r.UsedAt,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func FromMFAChallengeEntity(c *auth_entity.MFAChallenge) *MFAChallenge {
+	if c == nil {
+		return nil
+	}
+	return &MFAChallenge{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		TokenHash: c.TokenHash,
+		ExpiresAt: c.ExpiresAt,
+		UsedAt:    c.UsedAt,
+		Attempts:  c.Attempts,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func ToMFAChallengeEntity(c *MFAChallenge) *auth_entity.MFAChallenge {
+	if c == nil {
+		return nil
+	}
+	return &auth_entity.MFAChallenge{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		TokenHash: c.TokenHash,
+		ExpiresAt: c.ExpiresAt,
+		UsedAt:    c.UsedAt,
+		Attempts:  c.Attempts,
+		CreatedAt: c.CreatedAt,
+	}
+}
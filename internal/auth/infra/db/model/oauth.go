@@ -0,0 +1,152 @@
+package auth_model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type OAuthClient struct {
+	ID           string `gorm:"primaryKey;type:uuid"`
+	ClientID     string `gorm:"uniqueIndex"`
+	SecretHash   string
+	Name         string
+	RedirectURIs pq.StringArray `gorm:"type:text[]"`
+	GrantTypes   pq.StringArray `gorm:"type:text[]"`
+	Scopes       pq.StringArray `gorm:"type:text[]"`
+	Confidential bool
+	OwnerID      string
+	CreatedAt    time.Time
+}
+
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+func FromOAuthClientEntity(c *auth_entity.OAuthClient) *OAuthClient {
+	if c == nil {
+		return nil
+	}
+	return &OAuthClient{
+		ID:           c.ID,
+		ClientID:     c.ClientID,
+		SecretHash:   c.SecretHash,
+		Name:         c.Name,
+		RedirectURIs: pq.StringArray(c.RedirectURIs),
+		GrantTypes:   pq.StringArray(c.GrantTypes),
+		Scopes:       pq.StringArray(c.Scopes),
+		Confidential: c.Confidential,
+		OwnerID:      c.OwnerID,
+		CreatedAt:    c.CreatedAt,
+	}
+}
+
+func ToOAuthClientEntity(c *OAuthClient) *auth_entity.OAuthClient {
+	if c == nil {
+		return nil
+	}
+	return &auth_entity.OAuthClient{
+		ID:           c.ID,
+		ClientID:     c.ClientID,
+		SecretHash:   c.SecretHash,
+		Name:         c.Name,
+		RedirectURIs: []string(c.RedirectURIs),
+		GrantTypes:   []string(c.GrantTypes),
+		Scopes:       []string(c.Scopes),
+		Confidential: c.Confidential,
+		OwnerID:      c.OwnerID,
+		CreatedAt:    c.CreatedAt,
+	}
+}
+
+type OAuthAuthorizationCode struct {
+	ID            string `gorm:"primaryKey;type:uuid"`
+	CodeHash      string `gorm:"uniqueIndex"`
+	ClientID      string
+	UserID        string
+	RedirectURI   string
+	Scopes        pq.StringArray `gorm:"type:text[]"`
+	CodeChallenge string
+	ExpiresAt     time.Time `gorm:"index"`
+	CreatedAt     time.Time
+}
+
+func (OAuthAuthorizationCode) TableName() string {
+	return "oauth_authorization_codes"
+}
+
+func FromOAuthAuthorizationCodeEntity(c *auth_entity.OAuthAuthorizationCode) *OAuthAuthorizationCode {
+	if c == nil {
+		return nil
+	}
+	return &OAuthAuthorizationCode{
+		ID:            c.ID,
+		CodeHash:      c.CodeHash,
+		ClientID:      c.ClientID,
+		UserID:        c.UserID,
+		RedirectURI:   c.RedirectURI,
+		Scopes:        pq.StringArray(c.Scopes),
+		CodeChallenge: c.CodeChallenge,
+		ExpiresAt:     c.ExpiresAt,
+		CreatedAt:     c.CreatedAt,
+	}
+}
+
+func ToOAuthAuthorizationCodeEntity(c *OAuthAuthorizationCode) *auth_entity.OAuthAuthorizationCode {
+	if c == nil {
+		return nil
+	}
+	return &auth_entity.OAuthAuthorizationCode{
+		ID:            c.ID,
+		CodeHash:      c.CodeHash,
+		ClientID:      c.ClientID,
+		UserID:        c.UserID,
+		RedirectURI:   c.RedirectURI,
+		Scopes:        []string(c.Scopes),
+		CodeChallenge: c.CodeChallenge,
+		ExpiresAt:     c.ExpiresAt,
+		CreatedAt:     c.CreatedAt,
+	}
+}
+
+type OAuthConsent struct {
+	ID        string         `gorm:"primaryKey;type:uuid"`
+	UserID    string         `gorm:"uniqueIndex:idx_oauth_consents_user_client"`
+	ClientID  string         `gorm:"uniqueIndex:idx_oauth_consents_user_client"`
+	Scopes    pq.StringArray `gorm:"type:text[]"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (OAuthConsent) TableName() string {
+	return "oauth_consents"
+}
+
+func FromOAuthConsentEntity(c *auth_entity.OAuthConsent) *OAuthConsent {
+	if c == nil {
+		return nil
+	}
+	return &OAuthConsent{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		ClientID:  c.ClientID,
+		Scopes:    pq.StringArray(c.Scopes),
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+func ToOAuthConsentEntity(c *OAuthConsent) *auth_entity.OAuthConsent {
+	if c == nil {
+		return nil
+	}
+	return &auth_entity.OAuthConsent{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		ClientID:  c.ClientID,
+		Scopes:    []string(c.Scopes),
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
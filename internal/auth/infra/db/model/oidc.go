@@ -0,0 +1,92 @@
+package auth_model
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type OIDCLoginState struct {
+	ID           string `gorm:"primaryKey;type:uuid"`
+	Provider     string
+	StateHash    string `gorm:"uniqueIndex"`
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time `gorm:"index"`
+	CreatedAt    time.Time
+}
+
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+func FromOIDCLoginStateEntity(s *auth_entity.OIDCLoginState) *OIDCLoginState {
+	if s == nil {
+		return nil
+	}
+	return &OIDCLoginState{
+		ID:           s.ID,
+		Provider:     s.Provider,
+		StateHash:    s.StateHash,
+		Nonce:        s.Nonce,
+		CodeVerifier: s.CodeVerifier,
+		ExpiresAt:    s.ExpiresAt,
+		CreatedAt:    s.CreatedAt,
+	}
+}
+
+func ToOIDCLoginStateEntity(s *OIDCLoginState) *auth_entity.OIDCLoginState {
+	if s == nil {
+		return nil
+	}
+	return &auth_entity.OIDCLoginState{
+		ID:           s.ID,
+		Provider:     s.Provider,
+		StateHash:    s.StateHash,
+		Nonce:        s.Nonce,
+		CodeVerifier: s.CodeVerifier,
+		ExpiresAt:    s.ExpiresAt,
+		CreatedAt:    s.CreatedAt,
+	}
+}
+
+type ExternalIdentity struct {
+	ID        string `gorm:"primaryKey;type:uuid"`
+	UserID    string `gorm:"index"`
+	Provider  string `gorm:"uniqueIndex:idx_external_identities_provider_subject"`
+	Subject   string `gorm:"uniqueIndex:idx_external_identities_provider_subject"`
+	Email     string
+	CreatedAt time.Time
+}
+
+func (ExternalIdentity) TableName() string {
+	return "external_identities"
+}
+
+func FromExternalIdentityEntity(i *auth_entity.ExternalIdentity) *ExternalIdentity {
+	if i == nil {
+		return nil
+	}
+	return &ExternalIdentity{
+		ID:        i.ID,
+		UserID:    i.UserID,
+		Provider:  i.Provider,
+		Subject:   i.Subject,
+		Email:     i.Email,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+func ToExternalIdentityEntity(i *ExternalIdentity) *auth_entity.ExternalIdentity {
+	if i == nil {
+		return nil
+	}
+	return &auth_entity.ExternalIdentity{
+		ID:        i.ID,
+		UserID:    i.UserID,
+		Provider:  i.Provider,
+		Subject:   i.Subject,
+		Email:     i.Email,
+		CreatedAt: i.CreatedAt,
+	}
+}
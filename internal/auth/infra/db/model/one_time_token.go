@@ -0,0 +1,54 @@
+package auth_model
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type OneTimeToken struct {
+	ID        string `gorm:"primaryKey;type:uuid"`
+	UserID    string `gorm:"index:idx_one_time_tokens_user_id_purpose"`
+	Purpose   string `gorm:"index:idx_one_time_tokens_user_id_purpose"`
+	TokenHash string `gorm:"uniqueIndex"`
+	NewEmail  string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (OneTimeToken) TableName() string {
+	return "one_time_tokens"
+}
+
+func FromOneTimeTokenEntity(t *auth_entity.OneTimeToken) *OneTimeToken {
+	if t == nil {
+		return nil
+	}
+	return &OneTimeToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		Purpose:   string(t.Purpose),
+		TokenHash: t.TokenHash,
+		NewEmail:  t.NewEmail,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+func ToOneTimeTokenEntity(t *OneTimeToken) *auth_entity.OneTimeToken {
+	if t == nil {
+		return nil
+	}
+	return &auth_entity.OneTimeToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		Purpose:   auth_entity.TokenPurpose(t.Purpose),
+		TokenHash: t.TokenHash,
+		NewEmail:  t.NewEmail,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		CreatedAt: t.CreatedAt,
+	}
+}
@@ -0,0 +1,49 @@
+package auth_model
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type RevokedToken struct {
+	JTI       string `gorm:"primaryKey;column:jti"`
+	UserID    string `gorm:"index"`
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+type UserRevocation struct {
+	UserID    string `gorm:"primaryKey"`
+	RevokedAt time.Time
+}
+
+func (UserRevocation) TableName() string {
+	return "user_token_revocations"
+}
+
+func FromRevokedTokenEntity(t *auth_entity.RevokedToken) *RevokedToken {
+	if t == nil {
+		return nil
+	}
+	return &RevokedToken{
+		JTI:       t.JTI,
+		UserID:    t.UserID,
+		ExpiresAt: t.ExpiresAt,
+		RevokedAt: t.RevokedAt,
+	}
+}
+
+func ToUserRevocationEntity(r *UserRevocation) *auth_entity.UserRevocation {
+	if r == nil {
+		return nil
+	}
+	return &auth_entity.UserRevocation{
+		UserID:    r.UserID,
+		RevokedAt: r.RevokedAt,
+	}
+}
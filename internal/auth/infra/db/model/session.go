@@ -0,0 +1,54 @@
+package auth_model
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type Session struct {
+	ID         string `gorm:"primaryKey;type:uuid"`
+	UserID     string `gorm:"index"`
+	Device     string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	EndedAt    *time.Time
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+func FromSessionEntity(s *auth_entity.Session) *Session {
+	if s == nil {
+		return nil
+	}
+	return &Session{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		Device:     s.Device,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		EndedAt:    s.EndedAt,
+	}
+}
+
+func ToSessionEntity(s *Session) *auth_entity.Session {
+	if s == nil {
+		return nil
+	}
+	return &auth_entity.Session{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		Device:     s.Device,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		EndedAt:    s.EndedAt,
+	}
+}
@@ -0,0 +1,77 @@
+package auth_repository
+
+import (
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+)
+
+type APIKeyGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewAPIKeyGormRepository(db *gorm.DB) *APIKeyGormRepository {
+	return &APIKeyGormRepository{DB: db}
+}
+
+var _ port_auth_repository.APIKeyRepository = &APIKeyGormRepository{}
+
+func (r *APIKeyGormRepository) Save(k *auth_entity.APIKey) (*auth_entity.APIKey, error) {
+	model := auth_model.FromAPIKeyEntity(k)
+	if err := r.DB.Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return auth_model.ToAPIKeyEntity(model), nil
+}
+
+func (r *APIKeyGormRepository) FindByKeyHash(hash string) (*auth_entity.APIKey, error) {
+	var model auth_model.APIKey
+	if err := r.DB.First(&model, "key_hash = ?", hash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth_entity.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToAPIKeyEntity(&model), nil
+}
+
+func (r *APIKeyGormRepository) FindByUser(userID string) ([]*auth_entity.APIKey, error) {
+	var models []auth_model.APIKey
+	if err := r.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	keys := make([]*auth_entity.APIKey, 0, len(models))
+	for i := range models {
+		keys = append(keys, auth_model.ToAPIKeyEntity(&models[i]))
+	}
+	return keys, nil
+}
+
+func (r *APIKeyGormRepository) Revoke(id, userID string, revokedAt time.Time) error {
+	result := r.DB.Model(&auth_model.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return auth_entity.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *APIKeyGormRepository) RevokeAllForUser(userID string, revokedAt time.Time) error {
+	return r.DB.Model(&auth_model.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).Error
+}
+
+func (r *APIKeyGormRepository) TouchLastUsed(id string, usedAt time.Time) error {
+	return r.DB.Model(&auth_model.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+}
@@ -0,0 +1,82 @@
+package auth_repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type APIKeyGormRepositorySuite struct {
+	suite.Suite
+	repo *APIKeyGormRepository
+}
+
+func (s *APIKeyGormRepositorySuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(db.AutoMigrate(&auth_model.APIKey{}))
+
+	s.repo = NewAPIKeyGormRepository(db)
+}
+
+func (s *APIKeyGormRepositorySuite) save(id, userID, hash string) {
+	_, err := s.repo.Save(&auth_entity.APIKey{
+		ID: id, UserID: userID, Name: "ci", Prefix: "sek_abcdefgh", KeyHash: hash, Scopes: []string{"users:read"}, CreatedAt: time.Now(),
+	})
+	s.Require().NoError(err)
+}
+
+func (s *APIKeyGormRepositorySuite) TestSaveAndFindByKeyHash() {
+	s.save("key-1", "user-1", "hash-1")
+
+	found, err := s.repo.FindByKeyHash("hash-1")
+	s.NoError(err)
+	s.Equal("user-1", found.UserID)
+	s.Equal([]string{"users:read"}, found.Scopes)
+	s.Nil(found.ExpiresAt)
+
+	_, err = s.repo.FindByKeyHash("missing")
+	s.ErrorIs(err, auth_entity.ErrAPIKeyNotFound)
+}
+
+func (s *APIKeyGormRepositorySuite) TestRevokeOnlyOwnKeys() {
+	s.save("key-1", "user-1", "hash-1")
+
+	s.ErrorIs(s.repo.Revoke("key-1", "user-2", time.Now()), auth_entity.ErrAPIKeyNotFound)
+	s.NoError(s.repo.Revoke("key-1", "user-1", time.Now()))
+	s.ErrorIs(s.repo.Revoke("key-1", "user-1", time.Now()), auth_entity.ErrAPIKeyNotFound)
+
+	found, err := s.repo.FindByKeyHash("hash-1")
+	s.NoError(err)
+	s.True(found.IsRevoked())
+}
+
+func (s *APIKeyGormRepositorySuite) TestRevokeAllForUserAndTouch() {
+	s.save("key-1", "user-1", "hash-1")
+	s.save("key-2", "user-1", "hash-2")
+	s.save("key-3", "user-2", "hash-3")
+
+	s.NoError(s.repo.TouchLastUsed("key-3", time.Now()))
+	s.NoError(s.repo.RevokeAllForUser("user-1", time.Now()))
+
+	keys, err := s.repo.FindByUser("user-1")
+	s.NoError(err)
+	s.Len(keys, 2)
+	for _, key := range keys {
+		s.True(key.IsRevoked())
+	}
+
+	other, err := s.repo.FindByKeyHash("hash-3")
+	s.NoError(err)
+	s.False(other.IsRevoked())
+	s.NotNil(other.LastUsedAt)
+}
+
+func TestAPIKeyGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(APIKeyGormRepositorySuite))
+}
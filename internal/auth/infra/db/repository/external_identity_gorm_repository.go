@@ -0,0 +1,39 @@
+package auth_repository
+
+import (
+	"errors"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+)
+
+type ExternalIdentityGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewExternalIdentityGormRepository(db *gorm.DB) *ExternalIdentityGormRepository {
+	return &ExternalIdentityGormRepository{DB: db}
+}
+
+var _ port_auth_repository.ExternalIdentityRepository = &ExternalIdentityGormRepository{}
+
+func (r *ExternalIdentityGormRepository) FindByProviderSubject(provider, subject string) (*auth_entity.ExternalIdentity, error) {
+	var model auth_model.ExternalIdentity
+	if err := r.DB.First(&model, "provider = ? AND subject = ?", provider, subject).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth_entity.ErrExternalIdentityNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToExternalIdentityEntity(&model), nil
+}
+
+func (r *ExternalIdentityGormRepository) Save(i *auth_entity.ExternalIdentity) (*auth_entity.ExternalIdentity, error) {
+	model := auth_model.FromExternalIdentityEntity(i)
+	if err := r.DB.Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return auth_model.ToExternalIdentityEntity(model), nil
+}
@@ -0,0 +1,67 @@
+package auth_repository
+
+import (
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_throttle "github.com/williamkoller/system-education/internal/auth/port/throttle"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptGormRepository shares failed-login counters between instances
+// through the database.
+type LoginAttemptGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewLoginAttemptGormRepository(db *gorm.DB) *LoginAttemptGormRepository {
+	return &LoginAttemptGormRepository{DB: db}
+}
+
+var _ port_auth_throttle.LoginAttemptStore = &LoginAttemptGormRepository{}
+
+func (r *LoginAttemptGormRepository) Get(key string) (*auth_entity.LoginAttempts, error) {
+	var model auth_model.LoginAttempt
+	if err := r.DB.First(&model, "key = ?", key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &auth_entity.LoginAttempts{Key: key}, nil
+		}
+		return nil, err
+	}
+	return auth_model.ToLoginAttemptsEntity(&model), nil
+}
+
+func (r *LoginAttemptGormRepository) RecordFailure(key string, at time.Time, window time.Duration) (*auth_entity.LoginAttempts, error) {
+	// A zero window never expires, which the cutoff expresses as the zero
+	// time every stored failure is after.
+	var cutoff time.Time
+	if window > 0 {
+		cutoff = at.Add(-window)
+	}
+
+	var model auth_model.LoginAttempt
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failures":        gorm.Expr("CASE WHEN login_attempts.last_failure_at <= ? THEN 1 ELSE login_attempts.failures + 1 END", cutoff),
+				"last_failure_at": at,
+			}),
+		}).Create(&auth_model.LoginAttempt{Key: key, Failures: 1, LastFailureAt: at}).Error
+		if err != nil {
+			return err
+		}
+		return tx.First(&model, "key = ?", key).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return auth_model.ToLoginAttemptsEntity(&model), nil
+}
+
+func (r *LoginAttemptGormRepository) Reset(key string) error {
+	return r.DB.Where("key = ?", key).Delete(&auth_model.LoginAttempt{}).Error
+}
@@ -0,0 +1,77 @@
+package auth_repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type LoginAttemptGormRepositorySuite struct {
+	suite.Suite
+	repository *LoginAttemptGormRepository
+}
+
+func (s *LoginAttemptGormRepositorySuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(db.AutoMigrate(&auth_model.LoginAttempt{}))
+
+	s.repository = NewLoginAttemptGormRepository(db)
+}
+
+func (s *LoginAttemptGormRepositorySuite) TestGet_Empty() {
+	attempts, err := s.repository.Get("account:john@example.com")
+
+	s.NoError(err)
+	s.Equal("account:john@example.com", attempts.Key)
+	s.Equal(0, attempts.Failures)
+}
+
+func (s *LoginAttemptGormRepositorySuite) TestRecordFailure_Increments() {
+	now := time.Now().UTC()
+
+	attempts, err := s.repository.RecordFailure("account:john@example.com", now, time.Hour)
+	s.NoError(err)
+	s.Equal(1, attempts.Failures)
+
+	attempts, err = s.repository.RecordFailure("account:john@example.com", now.Add(time.Second), time.Hour)
+	s.NoError(err)
+	s.Equal(2, attempts.Failures)
+	s.WithinDuration(now.Add(time.Second), attempts.LastFailureAt, time.Millisecond)
+
+	other, err := s.repository.RecordFailure("ip:10.0.0.1", now, time.Hour)
+	s.NoError(err)
+	s.Equal(1, other.Failures)
+}
+
+func (s *LoginAttemptGormRepositorySuite) TestRecordFailure_WindowRestartsCount() {
+	now := time.Now().UTC()
+
+	_, err := s.repository.RecordFailure("account:john@example.com", now, time.Hour)
+	s.Require().NoError(err)
+	_, err = s.repository.RecordFailure("account:john@example.com", now, time.Hour)
+	s.Require().NoError(err)
+
+	attempts, err := s.repository.RecordFailure("account:john@example.com", now.Add(time.Hour), time.Hour)
+	s.NoError(err)
+	s.Equal(1, attempts.Failures)
+}
+
+func (s *LoginAttemptGormRepositorySuite) TestReset() {
+	_, err := s.repository.RecordFailure("account:john@example.com", time.Now().UTC(), time.Hour)
+	s.Require().NoError(err)
+
+	s.NoError(s.repository.Reset("account:john@example.com"))
+
+	attempts, err := s.repository.Get("account:john@example.com")
+	s.NoError(err)
+	s.Equal(0, attempts.Failures)
+}
+
+func TestLoginAttemptGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(LoginAttemptGormRepositorySuite))
+}
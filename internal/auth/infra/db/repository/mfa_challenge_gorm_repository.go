@@ -0,0 +1,60 @@
+package auth_repository
+
+import (
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+)
+
+type MFAChallengeGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewMFAChallengeGormRepository(db *gorm.DB) *MFAChallengeGormRepository {
+	return &MFAChallengeGormRepository{DB: db}
+}
+
+var _ port_auth_repository.MFAChallengeRepository = &MFAChallengeGormRepository{}
+
+func (r *MFAChallengeGormRepository) Save(c *auth_entity.MFAChallenge) (*auth_entity.MFAChallenge, error) {
+	model := auth_model.FromMFAChallengeEntity(c)
+	if err := r.DB.Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return auth_model.ToMFAChallengeEntity(model), nil
+}
+
+func (r *MFAChallengeGormRepository) FindByTokenHash(hash string) (*auth_entity.MFAChallenge, error) {
+	var model auth_model.MFAChallenge
+	if err := r.DB.First(&model, "token_hash = ?", hash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth_entity.ErrMFAChallengeNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToMFAChallengeEntity(&model), nil
+}
+
+func (r *MFAChallengeGormRepository) ConsumeAttempt(id string, maxAttempts int) (bool, error) {
+	result := r.DB.Model(&auth_model.MFAChallenge{}).
+		Where("id = ? AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *MFAChallengeGormRepository) MarkUsed(id string, usedAt time.Time) (bool, error) {
+	result := r.DB.Model(&auth_model.MFAChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
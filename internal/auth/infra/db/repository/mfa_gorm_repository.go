@@ -0,0 +1,92 @@
+package auth_repository
+
+import (
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MFAGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewMFAGormRepository(db *gorm.DB) *MFAGormRepository {
+	return &MFAGormRepository{DB: db}
+}
+
+var _ port_auth_repository.MFARepository = &MFAGormRepository{}
+
+func (r *MFAGormRepository) FindByUserID(userID string) (*auth_entity.MFACredential, error) {
+	var model auth_model.MFACredential
+	if err := r.DB.First(&model, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth_entity.ErrMFANotEnrolled
+		}
+		return nil, err
+	}
+	return auth_model.ToMFACredentialEntity(&model), nil
+}
+
+func (r *MFAGormRepository) Save(c *auth_entity.MFACredential) (*auth_entity.MFACredential, error) {
+	model := auth_model.FromMFACredentialEntity(c)
+	err := r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "confirmed_at", "last_used_step", "updated_at"}),
+	}).Create(model).Error
+	if err != nil {
+		return nil, err
+	}
+	return auth_model.ToMFACredentialEntity(model), nil
+}
+
+func (r *MFAGormRepository) Confirm(userID string, step int64, confirmedAt time.Time, codes []*auth_entity.RecoveryCode) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&auth_model.MFACredential{}).
+			Where("user_id = ? AND confirmed_at IS NULL", userID).
+			Updates(map[string]interface{}{"confirmed_at": confirmedAt, "last_used_step": step})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return auth_entity.ErrMFANotEnrolled
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&auth_model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		models := make([]*auth_model.RecoveryCode, 0, len(codes))
+		for _, code := range codes {
+			models = append(models, auth_model.FromRecoveryCodeEntity(code))
+		}
+		if len(models) == 0 {
+			return nil
+		}
+		return tx.Create(&models).Error
+	})
+}
+
+func (r *MFAGormRepository) AdvanceStep(userID string, step int64) (bool, error) {
+	result := r.DB.Model(&auth_model.MFACredential{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *MFAGormRepository) UseRecoveryCode(userID, codeHash string, usedAt time.Time) (bool, error) {
+	result := r.DB.Model(&auth_model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,152 @@
+package auth_repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type MFAGormRepositorySuite struct {
+	suite.Suite
+	db         *gorm.DB
+	repository *MFAGormRepository
+	challenges *MFAChallengeGormRepository
+}
+
+func (s *MFAGormRepositorySuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(db.AutoMigrate(&auth_model.MFACredential{}, &auth_model.RecoveryCode{}, &auth_model.MFAChallenge{}))
+
+	s.db = db
+	s.repository = NewMFAGormRepository(db)
+	s.challenges = NewMFAChallengeGormRepository(db)
+}
+
+func (s *MFAGormRepositorySuite) TestFindByUserID_NotEnrolled() {
+	found, err := s.repository.FindByUserID("user-1")
+
+	s.ErrorIs(err, auth_entity.ErrMFANotEnrolled)
+	s.Nil(found)
+}
+
+func (s *MFAGormRepositorySuite) TestSave_ReplacesPendingSecret() {
+	_, err := s.repository.Save(auth_entity.NewMFACredential("user-1", "SECRET1"))
+	s.Require().NoError(err)
+	_, err = s.repository.Save(auth_entity.NewMFACredential("user-1", "SECRET2"))
+	s.Require().NoError(err)
+
+	found, err := s.repository.FindByUserID("user-1")
+	s.NoError(err)
+	s.Equal("SECRET2", found.Secret)
+	s.False(found.IsConfirmed())
+}
+
+func (s *MFAGormRepositorySuite) TestConfirm_StoresStepAndRecoveryCodes() {
+	_, err := s.repository.Save(auth_entity.NewMFACredential("user-1", "SECRET"))
+	s.Require().NoError(err)
+
+	codes := []*auth_entity.RecoveryCode{
+		auth_entity.NewRecoveryCode("rc-1", "user-1", "hash-1"),
+		auth_entity.NewRecoveryCode("rc-2", "user-1", "hash-2"),
+	}
+	s.NoError(s.repository.Confirm("user-1", 42, time.Now(), codes))
+
+	found, err := s.repository.FindByUserID("user-1")
+	s.NoError(err)
+	s.True(found.IsConfirmed())
+	s.Equal(int64(42), found.LastUsedStep)
+
+	var count int64
+	s.db.Model(&auth_model.RecoveryCode{}).Where("user_id = ?", "user-1").Count(&count)
+	s.Equal(int64(2), count)
+}
+
+func (s *MFAGormRepositorySuite) TestConfirm_RequiresPendingCredential() {
+	err := s.repository.Confirm("user-1", 42, time.Now(), nil)
+
+	s.ErrorIs(err, auth_entity.ErrMFANotEnrolled)
+}
+
+func (s *MFAGormRepositorySuite) TestAdvanceStep_RejectsReplay() {
+	_, err := s.repository.Save(auth_entity.NewMFACredential("user-1", "SECRET"))
+	s.Require().NoError(err)
+	s.Require().NoError(s.repository.Confirm("user-1", 10, time.Now(), nil))
+
+	advanced, err := s.repository.AdvanceStep("user-1", 10)
+	s.NoError(err)
+	s.False(advanced)
+
+	advanced, err = s.repository.AdvanceStep("user-1", 11)
+	s.NoError(err)
+	s.True(advanced)
+}
+
+func (s *MFAGormRepositorySuite) TestUseRecoveryCode_OnlyOnce() {
+	_, err := s.repository.Save(auth_entity.NewMFACredential("user-1", "SECRET"))
+	s.Require().NoError(err)
+	codes := []*auth_entity.RecoveryCode{auth_entity.NewRecoveryCode("rc-1", "user-1", "hash-1")}
+	s.Require().NoError(s.repository.Confirm("user-1", 1, time.Now(), codes))
+
+	used, err := s.repository.UseRecoveryCode("user-2", "hash-1", time.Now())
+	s.NoError(err)
+	s.False(used)
+
+	used, err = s.repository.UseRecoveryCode("user-1", "hash-1", time.Now())
+	s.NoError(err)
+	s.True(used)
+
+	used, err = s.repository.UseRecoveryCode("user-1", "hash-1", time.Now())
+	s.NoError(err)
+	s.False(used)
+}
+
+func (s *MFAGormRepositorySuite) TestChallenge_SaveAndFindByTokenHash() {
+	_, err := s.challenges.Save(auth_entity.NewMFAChallenge("ch-1", "user-1", "hash-1", time.Now().Add(time.Minute)))
+	s.Require().NoError(err)
+
+	found, err := s.challenges.FindByTokenHash("hash-1")
+	s.NoError(err)
+	s.Equal("ch-1", found.ID)
+	s.Equal("user-1", found.UserID)
+
+	_, err = s.challenges.FindByTokenHash("missing")
+	s.ErrorIs(err, auth_entity.ErrMFAChallengeNotFound)
+}
+
+func (s *MFAGormRepositorySuite) TestChallenge_ConsumeAttemptStopsAtMax() {
+	_, err := s.challenges.Save(auth_entity.NewMFAChallenge("ch-1", "user-1", "hash-1", time.Now().Add(time.Minute)))
+	s.Require().NoError(err)
+
+	for i := 0; i < 2; i++ {
+		consumed, err := s.challenges.ConsumeAttempt("ch-1", 2)
+		s.NoError(err)
+		s.True(consumed)
+	}
+
+	consumed, err := s.challenges.ConsumeAttempt("ch-1", 2)
+	s.NoError(err)
+	s.False(consumed)
+}
+
+func (s *MFAGormRepositorySuite) TestChallenge_MarkUsedOnlyOnce() {
+	_, err := s.challenges.Save(auth_entity.NewMFAChallenge("ch-1", "user-1", "hash-1", time.Now().Add(time.Minute)))
+	s.Require().NoError(err)
+
+	marked, err := s.challenges.MarkUsed("ch-1", time.Now())
+	s.NoError(err)
+	s.True(marked)
+
+	marked, err = s.challenges.MarkUsed("ch-1", time.Now())
+	s.NoError(err)
+	s.False(marked)
+}
+
+func TestMFAGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(MFAGormRepositorySuite))
+}
@@ -0,0 +1,133 @@
+package auth_repository
+
+import (
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OAuthClientGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewOAuthClientGormRepository(db *gorm.DB) *OAuthClientGormRepository {
+	return &OAuthClientGormRepository{DB: db}
+}
+
+var _ port_auth_repository.OAuthClientRepository = &OAuthClientGormRepository{}
+
+func (r *OAuthClientGormRepository) Save(c *auth_entity.OAuthClient) (*auth_entity.OAuthClient, error) {
+	model := auth_model.FromOAuthClientEntity(c)
+	if err := r.DB.Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return auth_model.ToOAuthClientEntity(model), nil
+}
+
+func (r *OAuthClientGormRepository) FindByClientID(clientID string) (*auth_entity.OAuthClient, error) {
+	var model auth_model.OAuthClient
+	if err := r.DB.First(&model, "client_id = ?", clientID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth_entity.ErrOAuthClientNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToOAuthClientEntity(&model), nil
+}
+
+type OAuthCodeGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewOAuthCodeGormRepository(db *gorm.DB) *OAuthCodeGormRepository {
+	return &OAuthCodeGormRepository{DB: db}
+}
+
+var _ port_auth_repository.OAuthCodeRepository = &OAuthCodeGormRepository{}
+
+func (r *OAuthCodeGormRepository) Save(c *auth_entity.OAuthAuthorizationCode) error {
+	return r.DB.Create(auth_model.FromOAuthAuthorizationCodeEntity(c)).Error
+}
+
+// Consume works like OIDCStateGormRepository.Consume: only the caller whose
+// delete removed the row gets the code.
+func (r *OAuthCodeGormRepository) Consume(codeHash string) (*auth_entity.OAuthAuthorizationCode, error) {
+	var model auth_model.OAuthAuthorizationCode
+	if err := r.DB.First(&model, "code_hash = ?", codeHash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth_entity.ErrOAuthInvalidGrant
+		}
+		return nil, err
+	}
+
+	result := r.DB.Delete(&auth_model.OAuthAuthorizationCode{}, "id = ?", model.ID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return nil, auth_entity.ErrOAuthInvalidGrant
+	}
+
+	return auth_model.ToOAuthAuthorizationCodeEntity(&model), nil
+}
+
+func (r *OAuthCodeGormRepository) DeleteExpired(before time.Time) error {
+	return r.DB.Delete(&auth_model.OAuthAuthorizationCode{}, "expires_at <= ?", before).Error
+}
+
+type OAuthConsentGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewOAuthConsentGormRepository(db *gorm.DB) *OAuthConsentGormRepository {
+	return &OAuthConsentGormRepository{DB: db}
+}
+
+var _ port_auth_repository.OAuthConsentRepository = &OAuthConsentGormRepository{}
+
+func (r *OAuthConsentGormRepository) Find(userID, clientID string) (*auth_entity.OAuthConsent, error) {
+	var model auth_model.OAuthConsent
+	if err := r.DB.First(&model, "user_id = ? AND client_id = ?", userID, clientID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth_entity.ErrOAuthConsentNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToOAuthConsentEntity(&model), nil
+}
+
+func (r *OAuthConsentGormRepository) Save(c *auth_entity.OAuthConsent) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"scopes", "updated_at"}),
+	}).Create(auth_model.FromOAuthConsentEntity(c)).Error
+}
+
+func (r *OAuthConsentGormRepository) FindByUser(userID string) ([]*auth_entity.OAuthConsent, error) {
+	var models []*auth_model.OAuthConsent
+	if err := r.DB.Where("user_id = ?", userID).Order("created_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	consents := make([]*auth_entity.OAuthConsent, 0, len(models))
+	for _, model := range models {
+		consents = append(consents, auth_model.ToOAuthConsentEntity(model))
+	}
+	return consents, nil
+}
+
+func (r *OAuthConsentGormRepository) Delete(userID, clientID string) error {
+	result := r.DB.Delete(&auth_model.OAuthConsent{}, "user_id = ? AND client_id = ?", userID, clientID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return auth_entity.ErrOAuthConsentNotFound
+	}
+	return nil
+}
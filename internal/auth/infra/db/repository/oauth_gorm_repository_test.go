@@ -0,0 +1,104 @@
+package auth_repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type OAuthGormRepositorySuite struct {
+	suite.Suite
+	clients  *OAuthClientGormRepository
+	codes    *OAuthCodeGormRepository
+	consents *OAuthConsentGormRepository
+}
+
+func (s *OAuthGormRepositorySuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(db.AutoMigrate(&auth_model.OAuthClient{}, &auth_model.OAuthAuthorizationCode{}, &auth_model.OAuthConsent{}))
+
+	s.clients = NewOAuthClientGormRepository(db)
+	s.codes = NewOAuthCodeGormRepository(db)
+	s.consents = NewOAuthConsentGormRepository(db)
+}
+
+func (s *OAuthGormRepositorySuite) TestClient_SaveAndFind() {
+	_, err := s.clients.Save(&auth_entity.OAuthClient{
+		ID:           "c-1",
+		ClientID:     "lms",
+		Name:         "LMS plugin",
+		RedirectURIs: []string{"https://lms.example.com/callback"},
+		GrantTypes:   []string{auth_entity.GrantTypeAuthorizationCode},
+		Scopes:       []string{"users:read"},
+	})
+	s.Require().NoError(err)
+
+	found, err := s.clients.FindByClientID("lms")
+	s.NoError(err)
+	s.Equal("LMS plugin", found.Name)
+	s.Equal([]string{"https://lms.example.com/callback"}, found.RedirectURIs)
+	s.Equal([]string{"users:read"}, found.Scopes)
+
+	_, err = s.clients.FindByClientID("missing")
+	s.ErrorIs(err, auth_entity.ErrOAuthClientNotFound)
+}
+
+func (s *OAuthGormRepositorySuite) TestCode_ConsumeOnlyOnce() {
+	s.Require().NoError(s.codes.Save(&auth_entity.OAuthAuthorizationCode{
+		ID: "code-1", CodeHash: "hash", ClientID: "lms", UserID: "user-1", Scopes: []string{"users:read"}, ExpiresAt: time.Now().Add(time.Minute),
+	}))
+
+	code, err := s.codes.Consume("hash")
+	s.NoError(err)
+	s.Equal("user-1", code.UserID)
+	s.Equal([]string{"users:read"}, code.Scopes)
+
+	_, err = s.codes.Consume("hash")
+	s.ErrorIs(err, auth_entity.ErrOAuthInvalidGrant)
+}
+
+func (s *OAuthGormRepositorySuite) TestCode_DeleteExpired() {
+	now := time.Now()
+	s.Require().NoError(s.codes.Save(&auth_entity.OAuthAuthorizationCode{ID: "code-1", CodeHash: "old", ExpiresAt: now.Add(-time.Minute)}))
+	s.Require().NoError(s.codes.Save(&auth_entity.OAuthAuthorizationCode{ID: "code-2", CodeHash: "new", ExpiresAt: now.Add(time.Minute)}))
+
+	s.NoError(s.codes.DeleteExpired(now))
+
+	_, err := s.codes.Consume("old")
+	s.ErrorIs(err, auth_entity.ErrOAuthInvalidGrant)
+	_, err = s.codes.Consume("new")
+	s.NoError(err)
+}
+
+func (s *OAuthGormRepositorySuite) TestConsent_SaveReplacesScopes() {
+	s.Require().NoError(s.consents.Save(&auth_entity.OAuthConsent{ID: "con-1", UserID: "user-1", ClientID: "lms", Scopes: []string{"users:read"}}))
+	s.Require().NoError(s.consents.Save(&auth_entity.OAuthConsent{ID: "con-2", UserID: "user-1", ClientID: "lms", Scopes: []string{"roles:read", "users:read"}}))
+
+	consent, err := s.consents.Find("user-1", "lms")
+	s.NoError(err)
+	s.Equal([]string{"roles:read", "users:read"}, consent.Scopes)
+
+	consents, err := s.consents.FindByUser("user-1")
+	s.NoError(err)
+	s.Len(consents, 1)
+}
+
+func (s *OAuthGormRepositorySuite) TestConsent_Delete() {
+	s.Require().NoError(s.consents.Save(&auth_entity.OAuthConsent{ID: "con-1", UserID: "user-1", ClientID: "lms", Scopes: []string{"users:read"}}))
+
+	s.NoError(s.consents.Delete("user-1", "lms"))
+
+	_, err := s.consents.Find("user-1", "lms")
+	s.ErrorIs(err, auth_entity.ErrOAuthConsentNotFound)
+	s.ErrorIs(s.consents.Delete("user-1", "lms"), auth_entity.ErrOAuthConsentNotFound)
+}
+
+func TestOAuthGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(OAuthGormRepositorySuite))
+}
@@ -0,0 +1,74 @@
+package auth_repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type OIDCGormRepositorySuite struct {
+	suite.Suite
+	states     *OIDCStateGormRepository
+	identities *ExternalIdentityGormRepository
+}
+
+func (s *OIDCGormRepositorySuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(db.AutoMigrate(&auth_model.OIDCLoginState{}, &auth_model.ExternalIdentity{}))
+
+	s.states = NewOIDCStateGormRepository(db)
+	s.identities = NewExternalIdentityGormRepository(db)
+}
+
+func (s *OIDCGormRepositorySuite) TestConsume_OnlyOnce() {
+	s.Require().NoError(s.states.Save(&auth_entity.OIDCLoginState{
+		ID: "s-1", Provider: "google", StateHash: "hash", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: time.Now().Add(time.Minute),
+	}))
+
+	state, err := s.states.Consume("hash")
+	s.NoError(err)
+	s.Equal("google", state.Provider)
+	s.Equal("nonce", state.Nonce)
+	s.Equal("verifier", state.CodeVerifier)
+
+	_, err = s.states.Consume("hash")
+	s.ErrorIs(err, auth_entity.ErrInvalidOIDCState)
+}
+
+func (s *OIDCGormRepositorySuite) TestDeleteExpired() {
+	now := time.Now()
+	s.Require().NoError(s.states.Save(&auth_entity.OIDCLoginState{ID: "s-1", StateHash: "old", ExpiresAt: now.Add(-time.Minute)}))
+	s.Require().NoError(s.states.Save(&auth_entity.OIDCLoginState{ID: "s-2", StateHash: "new", ExpiresAt: now.Add(time.Minute)}))
+
+	s.NoError(s.states.DeleteExpired(now))
+
+	_, err := s.states.Consume("old")
+	s.ErrorIs(err, auth_entity.ErrInvalidOIDCState)
+	_, err = s.states.Consume("new")
+	s.NoError(err)
+}
+
+func (s *OIDCGormRepositorySuite) TestExternalIdentity_FindByProviderSubject() {
+	_, err := s.identities.Save(&auth_entity.ExternalIdentity{ID: "i-1", UserID: "user-1", Provider: "google", Subject: "sub-1", Email: "ana@school.edu"})
+	s.Require().NoError(err)
+
+	found, err := s.identities.FindByProviderSubject("google", "sub-1")
+	s.NoError(err)
+	s.Equal("user-1", found.UserID)
+
+	_, err = s.identities.FindByProviderSubject("microsoft", "sub-1")
+	s.ErrorIs(err, auth_entity.ErrExternalIdentityNotFound)
+
+	_, err = s.identities.Save(&auth_entity.ExternalIdentity{ID: "i-2", UserID: "user-2", Provider: "google", Subject: "sub-1"})
+	s.Error(err)
+}
+
+func TestOIDCGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(OIDCGormRepositorySuite))
+}
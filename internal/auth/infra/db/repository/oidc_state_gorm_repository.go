@@ -0,0 +1,51 @@
+package auth_repository
+
+import (
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+)
+
+type OIDCStateGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewOIDCStateGormRepository(db *gorm.DB) *OIDCStateGormRepository {
+	return &OIDCStateGormRepository{DB: db}
+}
+
+var _ port_auth_repository.OIDCStateRepository = &OIDCStateGormRepository{}
+
+func (r *OIDCStateGormRepository) Save(s *auth_entity.OIDCLoginState) error {
+	return r.DB.Create(auth_model.FromOIDCLoginStateEntity(s)).Error
+}
+
+// Consume only returns the state to the caller whose delete removed it, so
+// two callbacks racing with the same state cannot both succeed.
+func (r *OIDCStateGormRepository) Consume(stateHash string) (*auth_entity.OIDCLoginState, error) {
+	var model auth_model.OIDCLoginState
+	if err := r.DB.First(&model, "state_hash = ?", stateHash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth_entity.ErrInvalidOIDCState
+		}
+		return nil, err
+	}
+
+	result := r.DB.Delete(&auth_model.OIDCLoginState{}, "id = ?", model.ID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return nil, auth_entity.ErrInvalidOIDCState
+	}
+
+	return auth_model.ToOIDCLoginStateEntity(&model), nil
+}
+
+func (r *OIDCStateGormRepository) DeleteExpired(before time.Time) error {
+	return r.DB.Delete(&auth_model.OIDCLoginState{}, "expires_at <= ?", before).Error
+}
@@ -0,0 +1,56 @@
+package auth_repository
+
+import (
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+)
+
+type OneTimeTokenGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewOneTimeTokenGormRepository(db *gorm.DB) *OneTimeTokenGormRepository {
+	return &OneTimeTokenGormRepository{DB: db}
+}
+
+var _ port_auth_repository.OneTimeTokenRepository = &OneTimeTokenGormRepository{}
+
+func (r *OneTimeTokenGormRepository) Save(t *auth_entity.OneTimeToken) (*auth_entity.OneTimeToken, error) {
+	model := auth_model.FromOneTimeTokenEntity(t)
+	if err := r.DB.Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return auth_model.ToOneTimeTokenEntity(model), nil
+}
+
+func (r *OneTimeTokenGormRepository) FindByTokenHash(purpose auth_entity.TokenPurpose, hash string) (*auth_entity.OneTimeToken, error) {
+	var model auth_model.OneTimeToken
+	if err := r.DB.First(&model, "purpose = ? AND token_hash = ?", string(purpose), hash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth_entity.ErrOneTimeTokenNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToOneTimeTokenEntity(&model), nil
+}
+
+func (r *OneTimeTokenGormRepository) MarkUsed(id string, usedAt time.Time) (bool, error) {
+	result := r.DB.Model(&auth_model.OneTimeToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *OneTimeTokenGormRepository) InvalidateForUser(userID string, purpose auth_entity.TokenPurpose, at time.Time) error {
+	return r.DB.Model(&auth_model.OneTimeToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, string(purpose)).
+		Update("used_at", at).Error
+}
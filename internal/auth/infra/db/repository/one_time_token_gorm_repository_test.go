@@ -0,0 +1,73 @@
+package auth_repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type OneTimeTokenGormRepositorySuite struct {
+	suite.Suite
+	repository *OneTimeTokenGormRepository
+}
+
+func (s *OneTimeTokenGormRepositorySuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(db.AutoMigrate(&auth_model.OneTimeToken{}))
+
+	s.repository = NewOneTimeTokenGormRepository(db)
+}
+
+func (s *OneTimeTokenGormRepositorySuite) newToken(id string, purpose auth_entity.TokenPurpose, hash string) *auth_entity.OneTimeToken {
+	return auth_entity.NewOneTimeToken(id, "user-1", purpose, hash, time.Now().Add(time.Hour))
+}
+
+func (s *OneTimeTokenGormRepositorySuite) TestFindByTokenHash_MatchesPurpose() {
+	_, err := s.repository.Save(s.newToken("t-1", auth_entity.TokenPurposePasswordReset, "hash-1"))
+	s.Require().NoError(err)
+
+	found, err := s.repository.FindByTokenHash(auth_entity.TokenPurposePasswordReset, "hash-1")
+	s.NoError(err)
+	s.Equal("t-1", found.ID)
+	s.Equal(auth_entity.TokenPurposePasswordReset, found.Purpose)
+
+	_, err = s.repository.FindByTokenHash(auth_entity.TokenPurposeEmailVerification, "hash-1")
+	s.ErrorIs(err, auth_entity.ErrOneTimeTokenNotFound)
+}
+
+func (s *OneTimeTokenGormRepositorySuite) TestMarkUsed_OnlyOnce() {
+	_, err := s.repository.Save(s.newToken("t-1", auth_entity.TokenPurposePasswordReset, "hash-1"))
+	s.Require().NoError(err)
+
+	marked, err := s.repository.MarkUsed("t-1", time.Now())
+	s.NoError(err)
+	s.True(marked)
+
+	marked, err = s.repository.MarkUsed("t-1", time.Now())
+	s.NoError(err)
+	s.False(marked)
+}
+
+func (s *OneTimeTokenGormRepositorySuite) TestInvalidateForUser_OnlyTouchesPurpose() {
+	_, err := s.repository.Save(s.newToken("t-1", auth_entity.TokenPurposePasswordReset, "hash-1"))
+	s.Require().NoError(err)
+	_, err = s.repository.Save(s.newToken("t-2", auth_entity.TokenPurposeEmailVerification, "hash-2"))
+	s.Require().NoError(err)
+
+	s.NoError(s.repository.InvalidateForUser("user-1", auth_entity.TokenPurposePasswordReset, time.Now()))
+
+	reset, _ := s.repository.FindByTokenHash(auth_entity.TokenPurposePasswordReset, "hash-1")
+	verification, _ := s.repository.FindByTokenHash(auth_entity.TokenPurposeEmailVerification, "hash-2")
+	s.True(reset.IsUsed())
+	s.False(verification.IsUsed())
+}
+
+func TestOneTimeTokenGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(OneTimeTokenGormRepositorySuite))
+}
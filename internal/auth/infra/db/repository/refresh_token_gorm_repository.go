@@ -0,0 +1,62 @@
+package auth_repository
+
+import (
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewRefreshTokenGormRepository(db *gorm.DB) *RefreshTokenGormRepository {
+	return &RefreshTokenGormRepository{DB: db}
+}
+
+var _ port_auth_repository.RefreshTokenRepository = &RefreshTokenGormRepository{}
+
+func (r *RefreshTokenGormRepository) Save(t *auth_entity.RefreshToken) (*auth_entity.RefreshToken, error) {
+	model := auth_model.FromRefreshTokenEntity(t)
+	if err := r.DB.Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return auth_model.ToRefreshTokenEntity(model), nil
+}
+
+func (r *RefreshTokenGormRepository) FindByTokenHash(hash string) (*auth_entity.RefreshToken, error) {
+	var model auth_model.RefreshToken
+	if err := r.DB.First(&model, "token_hash = ?", hash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth_entity.ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToRefreshTokenEntity(&model), nil
+}
+
+func (r *RefreshTokenGormRepository) MarkUsed(id string, usedAt time.Time) (bool, error) {
+	result := r.DB.Model(&auth_model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *RefreshTokenGormRepository) RevokeFamily(familyID string, revokedAt time.Time) error {
+	return r.DB.Model(&auth_model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", revokedAt).Error
+}
+
+func (r *RefreshTokenGormRepository) RevokeAllForUser(userID string, revokedAt time.Time) error {
+	return r.DB.Model(&auth_model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).Error
+}
@@ -0,0 +1,105 @@
+package auth_repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type RefreshTokenGormRepositorySuite struct {
+	suite.Suite
+	db         *gorm.DB
+	repository *RefreshTokenGormRepository
+}
+
+func (s *RefreshTokenGormRepositorySuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(db.AutoMigrate(&auth_model.RefreshToken{}))
+
+	s.db = db
+	s.repository = NewRefreshTokenGormRepository(db)
+}
+
+func (s *RefreshTokenGormRepositorySuite) newToken(id, familyID, hash string) *auth_entity.RefreshToken {
+	return auth_entity.NewRefreshToken(id, "user-1", familyID, hash, time.Now().Add(time.Hour))
+}
+
+func (s *RefreshTokenGormRepositorySuite) TestSaveAndFindByTokenHash() {
+	saved, err := s.repository.Save(s.newToken("rt-1", "family-1", "hash-1"))
+	s.NoError(err)
+	s.Equal("rt-1", saved.ID)
+
+	found, err := s.repository.FindByTokenHash("hash-1")
+	s.NoError(err)
+	s.Equal("rt-1", found.ID)
+	s.Equal("family-1", found.FamilyID)
+	s.Equal("user-1", found.UserID)
+	s.False(found.IsUsed())
+	s.False(found.IsRevoked())
+}
+
+func (s *RefreshTokenGormRepositorySuite) TestFindByTokenHash_NotFound() {
+	found, err := s.repository.FindByTokenHash("missing")
+
+	s.ErrorIs(err, auth_entity.ErrRefreshTokenNotFound)
+	s.Nil(found)
+}
+
+func (s *RefreshTokenGormRepositorySuite) TestMarkUsed_OnlyOnce() {
+	_, err := s.repository.Save(s.newToken("rt-1", "family-1", "hash-1"))
+	s.Require().NoError(err)
+
+	marked, err := s.repository.MarkUsed("rt-1", time.Now())
+	s.NoError(err)
+	s.True(marked)
+
+	marked, err = s.repository.MarkUsed("rt-1", time.Now())
+	s.NoError(err)
+	s.False(marked)
+
+	found, err := s.repository.FindByTokenHash("hash-1")
+	s.NoError(err)
+	s.True(found.IsUsed())
+}
+
+func (s *RefreshTokenGormRepositorySuite) TestRevokeFamily() {
+	_, err := s.repository.Save(s.newToken("rt-1", "family-1", "hash-1"))
+	s.Require().NoError(err)
+	_, err = s.repository.Save(s.newToken("rt-2", "family-1", "hash-2"))
+	s.Require().NoError(err)
+	_, err = s.repository.Save(s.newToken("rt-3", "family-2", "hash-3"))
+	s.Require().NoError(err)
+
+	s.NoError(s.repository.RevokeFamily("family-1", time.Now()))
+
+	first, _ := s.repository.FindByTokenHash("hash-1")
+	second, _ := s.repository.FindByTokenHash("hash-2")
+	other, _ := s.repository.FindByTokenHash("hash-3")
+	s.True(first.IsRevoked())
+	s.True(second.IsRevoked())
+	s.False(other.IsRevoked())
+}
+
+func (s *RefreshTokenGormRepositorySuite) TestRevokeAllForUser() {
+	_, err := s.repository.Save(s.newToken("rt-1", "family-1", "hash-1"))
+	s.Require().NoError(err)
+	_, err = s.repository.Save(s.newToken("rt-2", "family-2", "hash-2"))
+	s.Require().NoError(err)
+
+	s.NoError(s.repository.RevokeAllForUser("user-1", time.Now()))
+
+	first, _ := s.repository.FindByTokenHash("hash-1")
+	second, _ := s.repository.FindByTokenHash("hash-2")
+	s.True(first.IsRevoked())
+	s.True(second.IsRevoked())
+}
+
+func TestRefreshTokenGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenGormRepositorySuite))
+}
@@ -0,0 +1,58 @@
+package auth_repository
+
+import (
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevocationGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewRevocationGormRepository(db *gorm.DB) *RevocationGormRepository {
+	return &RevocationGormRepository{DB: db}
+}
+
+var _ port_auth_repository.RevocationRepository = &RevocationGormRepository{}
+
+func (r *RevocationGormRepository) RevokeToken(t *auth_entity.RevokedToken) error {
+	model := auth_model.FromRevokedTokenEntity(t)
+	return r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&model).Error
+}
+
+func (r *RevocationGormRepository) IsTokenRevoked(jti string) (bool, error) {
+	var count int64
+	if err := r.DB.Model(&auth_model.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *RevocationGormRepository) RevokeUser(userID string, revokedAt time.Time) error {
+	model := &auth_model.UserRevocation{UserID: userID, RevokedAt: revokedAt}
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_at"}),
+	}).Create(model).Error
+}
+
+func (r *RevocationGormRepository) FindUserRevocation(userID string) (*auth_entity.UserRevocation, error) {
+	var model auth_model.UserRevocation
+	if err := r.DB.First(&model, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return auth_model.ToUserRevocationEntity(&model), nil
+}
+
+func (r *RevocationGormRepository) DeleteExpired(now time.Time) error {
+	return r.DB.Where("expires_at < ?", now).Delete(&auth_model.RevokedToken{}).Error
+}
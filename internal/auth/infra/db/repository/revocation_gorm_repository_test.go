@@ -0,0 +1,79 @@
+package auth_repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type RevocationGormRepositorySuite struct {
+	suite.Suite
+	db         *gorm.DB
+	repository *RevocationGormRepository
+}
+
+func (s *RevocationGormRepositorySuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(db.AutoMigrate(&auth_model.RevokedToken{}, &auth_model.UserRevocation{}))
+
+	s.db = db
+	s.repository = NewRevocationGormRepository(db)
+}
+
+func (s *RevocationGormRepositorySuite) TestRevokeToken() {
+	token := auth_entity.NewRevokedToken("jti-1", "user-1", time.Now().Add(time.Hour), time.Now())
+
+	s.NoError(s.repository.RevokeToken(token))
+	s.NoError(s.repository.RevokeToken(token), "revoking twice must be idempotent")
+
+	revoked, err := s.repository.IsTokenRevoked("jti-1")
+	s.NoError(err)
+	s.True(revoked)
+
+	revoked, err = s.repository.IsTokenRevoked("jti-2")
+	s.NoError(err)
+	s.False(revoked)
+}
+
+func (s *RevocationGormRepositorySuite) TestRevokeUser_Upserts() {
+	first := time.Now().Add(-time.Hour).UTC()
+	second := time.Now().UTC()
+
+	s.NoError(s.repository.RevokeUser("user-1", first))
+	s.NoError(s.repository.RevokeUser("user-1", second))
+
+	revocation, err := s.repository.FindUserRevocation("user-1")
+	s.NoError(err)
+	s.NotNil(revocation)
+	s.WithinDuration(second, revocation.RevokedAt, time.Millisecond)
+}
+
+func (s *RevocationGormRepositorySuite) TestFindUserRevocation_None() {
+	revocation, err := s.repository.FindUserRevocation("user-1")
+
+	s.NoError(err)
+	s.Nil(revocation)
+}
+
+func (s *RevocationGormRepositorySuite) TestDeleteExpired() {
+	now := time.Now()
+	s.NoError(s.repository.RevokeToken(auth_entity.NewRevokedToken("expired", "user-1", now.Add(-time.Minute), now)))
+	s.NoError(s.repository.RevokeToken(auth_entity.NewRevokedToken("active", "user-1", now.Add(time.Minute), now)))
+
+	s.NoError(s.repository.DeleteExpired(now))
+
+	revoked, _ := s.repository.IsTokenRevoked("expired")
+	s.False(revoked)
+	revoked, _ = s.repository.IsTokenRevoked("active")
+	s.True(revoked)
+}
+
+func TestRevocationGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RevocationGormRepositorySuite))
+}
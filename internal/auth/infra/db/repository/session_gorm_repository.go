@@ -0,0 +1,73 @@
+package auth_repository
+
+import (
+	"errors"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	"gorm.io/gorm"
+)
+
+type SessionGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewSessionGormRepository(db *gorm.DB) *SessionGormRepository {
+	return &SessionGormRepository{DB: db}
+}
+
+var _ port_auth_repository.SessionRepository = &SessionGormRepository{}
+
+func (r *SessionGormRepository) Save(s *auth_entity.Session) (*auth_entity.Session, error) {
+	model := auth_model.FromSessionEntity(s)
+	if err := r.DB.Create(&model).Error; err != nil {
+		return nil, err
+	}
+	return auth_model.ToSessionEntity(model), nil
+}
+
+func (r *SessionGormRepository) FindByID(id string) (*auth_entity.Session, error) {
+	var model auth_model.Session
+	if err := r.DB.First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth_entity.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return auth_model.ToSessionEntity(&model), nil
+}
+
+func (r *SessionGormRepository) FindActiveByUser(userID string, since time.Time) ([]*auth_entity.Session, error) {
+	var models []auth_model.Session
+	if err := r.DB.
+		Where("user_id = ? AND ended_at IS NULL AND last_seen_at > ?", userID, since).
+		Order("last_seen_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	sessions := make([]*auth_entity.Session, 0, len(models))
+	for i := range models {
+		sessions = append(sessions, auth_model.ToSessionEntity(&models[i]))
+	}
+	return sessions, nil
+}
+
+func (r *SessionGormRepository) Touch(id string, seenAt time.Time) error {
+	return r.DB.Model(&auth_model.Session{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Update("last_seen_at", seenAt).Error
+}
+
+func (r *SessionGormRepository) End(id string, endedAt time.Time) error {
+	return r.DB.Model(&auth_model.Session{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Update("ended_at", endedAt).Error
+}
+
+func (r *SessionGormRepository) EndAllForUser(userID string, endedAt time.Time) error {
+	return r.DB.Model(&auth_model.Session{}).
+		Where("user_id = ? AND ended_at IS NULL", userID).
+		Update("ended_at", endedAt).Error
+}
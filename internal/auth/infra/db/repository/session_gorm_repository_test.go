@@ -0,0 +1,81 @@
+package auth_repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	auth_model "github.com/williamkoller/system-education/internal/auth/infra/db/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type SessionGormRepositorySuite struct {
+	suite.Suite
+	repo *SessionGormRepository
+	now  time.Time
+}
+
+func (s *SessionGormRepositorySuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(db.AutoMigrate(&auth_model.Session{}))
+
+	s.repo = NewSessionGormRepository(db)
+	s.now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (s *SessionGormRepositorySuite) save(id, userID string, seenAt time.Time) {
+	_, err := s.repo.Save(auth_entity.NewSession(id, userID, auth_entity.ClientInfo{IP: "10.0.0.1", UserAgent: "curl/8.4.0"}, seenAt))
+	s.Require().NoError(err)
+}
+
+func (s *SessionGormRepositorySuite) TestSaveAndFindByID() {
+	s.save("session-1", "user-1", s.now)
+
+	found, err := s.repo.FindByID("session-1")
+	s.NoError(err)
+	s.Equal("user-1", found.UserID)
+	s.Equal("curl", found.Device)
+	s.Equal("10.0.0.1", found.IP)
+
+	_, err = s.repo.FindByID("missing")
+	s.ErrorIs(err, auth_entity.ErrSessionNotFound)
+}
+
+func (s *SessionGormRepositorySuite) TestFindActiveByUser() {
+	s.save("session-old", "user-1", s.now.Add(-48*time.Hour))
+	s.save("session-1", "user-1", s.now.Add(-time.Hour))
+	s.save("session-2", "user-1", s.now.Add(-time.Hour))
+	s.save("session-other", "user-2", s.now)
+
+	s.NoError(s.repo.Touch("session-2", s.now))
+	s.NoError(s.repo.End("session-1", s.now))
+
+	sessions, err := s.repo.FindActiveByUser("user-1", s.now.Add(-24*time.Hour))
+	s.NoError(err)
+	s.Require().Len(sessions, 1)
+	s.Equal("session-2", sessions[0].ID)
+	s.True(sessions[0].LastSeenAt.Equal(s.now))
+}
+
+func (s *SessionGormRepositorySuite) TestEndAllForUser() {
+	s.save("session-1", "user-1", s.now)
+	s.save("session-2", "user-1", s.now)
+	s.save("session-other", "user-2", s.now)
+
+	s.NoError(s.repo.EndAllForUser("user-1", s.now))
+
+	sessions, err := s.repo.FindActiveByUser("user-1", s.now.Add(-time.Hour))
+	s.NoError(err)
+	s.Empty(sessions)
+
+	other, err := s.repo.FindActiveByUser("user-2", s.now.Add(-time.Hour))
+	s.NoError(err)
+	s.Len(other, 1)
+}
+
+func TestSessionGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(SessionGormRepositorySuite))
+}
@@ -0,0 +1,250 @@
+// Package oidctest provides a local OpenID Connect provider for tests and
+// local development. It implements discovery, JWKS and the authorization
+// code flow with PKCE, and signs RS256 ID tokens with whatever claims the
+// caller asks for.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type pendingCode struct {
+	redirectURI   string
+	codeChallenge string
+	claims        jwt.MapClaims
+}
+
+type StubIdP struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	mu    sync.Mutex
+	key   *rsa.PrivateKey
+	kid   string
+	codes map[string]pendingCode
+}
+
+func NewStubIdP(clientID, clientSecret string) (*StubIdP, error) {
+	s := &StubIdP{ClientID: clientID, ClientSecret: clientSecret, codes: map[string]pendingCode{}}
+	if err := s.RotateKey(); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("/jwks", s.handleJWKS)
+	mux.HandleFunc("/authorize", s.handleAuthorize)
+	mux.HandleFunc("/token", s.handleToken)
+	s.Server = httptest.NewServer(mux)
+	return s, nil
+}
+
+func (s *StubIdP) Issuer() string {
+	return s.Server.URL
+}
+
+func (s *StubIdP) Close() {
+	s.Server.Close()
+}
+
+// RotateKey replaces the signing key, as providers do periodically.
+func (s *StubIdP) RotateKey() error {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+	kid, err := randomString(8)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.key, s.kid = key, kid
+	s.mu.Unlock()
+	return nil
+}
+
+// Authorize plays the user approving the request in authURL and returns
+// the code and state the provider would redirect back with. claims are
+// merged over the defaults (iss, aud, sub, iat, exp and the request's
+// nonce), so tests can also forge bad tokens.
+func (s *StubIdP) Authorize(authURL string, claims map[string]any) (code, state string, err error) {
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	query := parsed.Query()
+	if query.Get("client_id") != s.ClientID {
+		return "", "", errors.New("unknown client_id")
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		return "", "", errors.New("PKCE S256 challenge required")
+	}
+
+	now := time.Now()
+	merged := jwt.MapClaims{
+		"iss":   s.Issuer(),
+		"aud":   s.ClientID,
+		"sub":   "stub-subject",
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": query.Get("nonce"),
+	}
+	for name, value := range claims {
+		merged[name] = value
+	}
+
+	code, err = randomString(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	s.mu.Lock()
+	s.codes[code] = pendingCode{
+		redirectURI:   query.Get("redirect_uri"),
+		codeChallenge: query.Get("code_challenge"),
+		claims:        merged,
+	}
+	s.mu.Unlock()
+
+	return code, query.Get("state"), nil
+}
+
+// SignIDToken signs claims with the current key, bypassing the code flow.
+func (s *StubIdP) SignIDToken(claims jwt.MapClaims) (string, error) {
+	s.mu.Lock()
+	key, kid := s.key, s.kid
+	s.mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(key)
+}
+
+func (s *StubIdP) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.Issuer(),
+		"authorization_endpoint": s.Issuer() + "/authorize",
+		"token_endpoint":         s.Issuer() + "/token",
+		"jwks_uri":               s.Issuer() + "/jwks",
+	})
+}
+
+func (s *StubIdP) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	key, kid := s.key, s.kid
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, auth_entity.JSONWebKeySet{Keys: []auth_entity.JSONWebKey{{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+}
+
+// handleAuthorize approves every request immediately, signing in as the
+// user given by the optional login_hint, and redirects back with a code.
+func (s *StubIdP) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	email := r.URL.Query().Get("login_hint")
+	if email == "" {
+		email = "stub.user@example.com"
+	}
+
+	code, state, err := s.Authorize(s.Issuer()+r.URL.RequestURI(), map[string]any{
+		"sub":            "stub-" + email,
+		"email":          email,
+		"email_verified": true,
+		"name":           "Stub User",
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	redirect := r.URL.Query().Get("redirect_uri") + "?" + url.Values{"code": {code}, "state": {state}}.Encode()
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+func (s *StubIdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+	if r.PostForm.Get("client_id") != s.ClientID || r.PostForm.Get("client_secret") != s.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	s.mu.Lock()
+	pending, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	if !ok || pending.redirectURI != r.PostForm.Get("redirect_uri") {
+		tokenError(w, "invalid_grant")
+		return
+	}
+	if auth_entity.PKCEChallenge(r.PostForm.Get("code_verifier")) != pending.codeChallenge {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	idToken, err := s.SignIDToken(pending.claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	accessToken, _ := randomString(16)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random string: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
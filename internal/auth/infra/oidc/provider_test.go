@@ -0,0 +1,155 @@
+package infra_oidc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	"github.com/williamkoller/system-education/internal/auth/infra/oidc/oidctest"
+)
+
+const (
+	verifier    = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	redirectURL = "http://localhost:3000/auth/oidc/stub/callback"
+)
+
+func newStub(t *testing.T) *oidctest.StubIdP {
+	t.Helper()
+	stub, err := oidctest.NewStubIdP("client-id", "client-secret")
+	require.NoError(t, err)
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func newProvider(stub *oidctest.StubIdP, modify ...func(*ProviderConfig)) *Provider {
+	cfg := ProviderConfig{
+		Name:         "stub",
+		Issuer:       stub.Issuer(),
+		ClientID:     stub.ClientID,
+		ClientSecret: stub.ClientSecret,
+		RedirectURL:  redirectURL,
+	}
+	for _, m := range modify {
+		m(&cfg)
+	}
+	return NewProvider(cfg, nil)
+}
+
+func authorize(t *testing.T, stub *oidctest.StubIdP, p *Provider, claims map[string]any) string {
+	t.Helper()
+	authURL, err := p.AuthCodeURL("state-1", "nonce-1", auth_entity.PKCEChallenge(verifier))
+	require.NoError(t, err)
+	code, state, err := stub.Authorize(authURL, claims)
+	require.NoError(t, err)
+	assert.Equal(t, "state-1", state)
+	return code
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	stub := newStub(t)
+	p := newProvider(stub)
+
+	code := authorize(t, stub, p, map[string]any{
+		"sub":            "subject-1",
+		"email":          "ada@example.com",
+		"email_verified": true,
+		"name":           "Ada Lovelace",
+	})
+
+	claims, err := p.Exchange(code, verifier)
+	require.NoError(t, err)
+	assert.Equal(t, "subject-1", claims.Subject)
+	assert.Equal(t, "ada@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, "Ada Lovelace", claims.Name)
+	assert.Equal(t, "nonce-1", claims.Nonce)
+
+	_, err = p.Exchange(code, verifier)
+	assert.Error(t, err, "codes are single use")
+}
+
+func TestProvider_ExchangeRejectsWrongVerifier(t *testing.T) {
+	stub := newStub(t)
+	p := newProvider(stub)
+	code := authorize(t, stub, p, nil)
+
+	_, err := p.Exchange(code, "another-verifier")
+	assert.ErrorContains(t, err, "invalid_grant")
+}
+
+func TestProvider_ExchangeRejectsInvalidIDTokens(t *testing.T) {
+	cases := map[string]map[string]any{
+		"wrong audience":       {"aud": "someone-else"},
+		"wrong issuer":         {"iss": "https://evil.example.com"},
+		"expired":              {"exp": time.Now().Add(-time.Hour).Unix()},
+		"missing expiry":       {"exp": nil},
+		"missing subject":      {"sub": ""},
+		"foreign azp":          {"aud": []string{"client-id", "other"}, "azp": "other"},
+		"multi aud, no azp":    {"aud": []string{"client-id", "other"}},
+		"issued in the future": {"iat": time.Now().Add(time.Hour).Unix()},
+	}
+
+	for name, claims := range cases {
+		t.Run(name, func(t *testing.T) {
+			stub := newStub(t)
+			p := newProvider(stub)
+			code := authorize(t, stub, p, claims)
+
+			_, err := p.Exchange(code, verifier)
+			assert.ErrorIs(t, err, auth_entity.ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestProvider_RefetchesKeysAfterRotation(t *testing.T) {
+	stub := newStub(t)
+	p := newProvider(stub)
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	_, err := p.Exchange(authorize(t, stub, p, nil), verifier)
+	require.NoError(t, err)
+
+	require.NoError(t, stub.RotateKey())
+	_, err = p.Exchange(authorize(t, stub, p, nil), verifier)
+	assert.ErrorIs(t, err, auth_entity.ErrInvalidIDToken, "keys were refreshed too recently")
+
+	now = now.Add(keyRefreshInterval)
+	_, err = p.Exchange(authorize(t, stub, p, nil), verifier)
+	assert.NoError(t, err)
+}
+
+func TestProvider_TrustEmail(t *testing.T) {
+	stub := newStub(t)
+	p := newProvider(stub, func(cfg *ProviderConfig) { cfg.TrustEmail = true })
+
+	claims, err := p.Exchange(authorize(t, stub, p, map[string]any{"preferred_username": "ada@contoso.com"}), verifier)
+	require.NoError(t, err)
+	assert.Equal(t, "ada@contoso.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+
+	untrusted := newProvider(stub)
+	claims, err = untrusted.Exchange(authorize(t, stub, untrusted, map[string]any{"email": "ada@contoso.com"}), verifier)
+	require.NoError(t, err)
+	assert.False(t, claims.EmailVerified)
+}
+
+func TestProvider_AllowsDomain(t *testing.T) {
+	stub := newStub(t)
+
+	assert.True(t, newProvider(stub).AllowsDomain("anything.com"))
+
+	restricted := newProvider(stub, func(cfg *ProviderConfig) { cfg.AllowedDomains = []string{"Example.com"} })
+	assert.True(t, restricted.AllowsDomain("example.com"))
+	assert.False(t, restricted.AllowsDomain("example.org"))
+}
+
+func TestProvider_RejectsMismatchedDiscoveryIssuer(t *testing.T) {
+	stub := newStub(t)
+	p := newProvider(stub, func(cfg *ProviderConfig) { cfg.Issuer = stub.Issuer() + "/tenant" })
+
+	_, err := p.AuthCodeURL("state", "nonce", "challenge")
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package infra_revocation
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_repository "github.com/williamkoller/system-education/internal/auth/port/repository"
+	port_auth_revocation "github.com/williamkoller/system-education/internal/auth/port/revocation"
+)
+
+type tokenEntry struct {
+	revoked   bool
+	expiresAt time.Time
+	checkedAt time.Time
+}
+
+type userEntry struct {
+	revocation *auth_entity.UserRevocation
+	checkedAt  time.Time
+}
+
+// CachedRevocationStore keeps revocations in front of the database. Positive
+// answers for a token id are final and kept until the token would have
+// expired anyway; negative answers and user-wide revocations are reloaded
+// after ttl so revocations made by other instances are eventually observed.
+// Stale entries are swept at most once per ttl, on whichever write comes
+// first, so the cache holds roughly the ids seen in the last two ttls.
+type CachedRevocationStore struct {
+	repo     port_auth_repository.RevocationRepository
+	ttl      time.Duration
+	now      func() time.Time
+	mu       sync.RWMutex
+	tokens   map[string]tokenEntry
+	users    map[string]userEntry
+	prunedAt time.Time
+}
+
+var _ port_auth_revocation.RevocationStore = &CachedRevocationStore{}
+
+func NewCachedRevocationStore(repo port_auth_repository.RevocationRepository, ttl time.Duration) *CachedRevocationStore {
+	return &CachedRevocationStore{
+		repo:   repo,
+		ttl:    ttl,
+		now:    time.Now,
+		tokens: make(map[string]tokenEntry),
+		users:  make(map[string]userEntry),
+	}
+}
+
+func (s *CachedRevocationStore) RevokeToken(jti, userID string, expiresAt time.Time) error {
+	now := s.now()
+	if err := s.repo.RevokeToken(auth_entity.NewRevokedToken(jti, userID, expiresAt, now)); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteExpired(now); err != nil {
+		log.Printf("failed to prune expired revoked tokens: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[jti] = tokenEntry{revoked: true, expiresAt: expiresAt, checkedAt: now}
+	s.pruneLocked(now)
+	s.prunedAt = now
+	return nil
+}
+
+func (s *CachedRevocationStore) RevokeAllForUser(userID string) error {
+	now := s.now()
+	if err := s.repo.RevokeUser(userID, now); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[userID] = userEntry{
+		revocation: &auth_entity.UserRevocation{UserID: userID, RevokedAt: now},
+		checkedAt:  now,
+	}
+	s.pruneDueLocked(now)
+	return nil
+}
+
+func (s *CachedRevocationStore) IsRevoked(jti, userID string, issuedAt time.Time) (bool, error) {
+	if jti != "" {
+		revoked, err := s.isTokenRevoked(jti)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	if userID == "" {
+		return false, nil
+	}
+
+	revocation, err := s.userRevocation(userID)
+	if err != nil {
+		return false, err
+	}
+	return revocation.Covers(issuedAt), nil
+}
+
+func (s *CachedRevocationStore) isTokenRevoked(jti string) (bool, error) {
+	now := s.now()
+
+	s.mu.RLock()
+	entry, ok := s.tokens[jti]
+	s.mu.RUnlock()
+
+	if ok && (entry.revoked || now.Sub(entry.checkedAt) < s.ttl) {
+		return entry.revoked, nil
+	}
+
+	revoked, err := s.repo.IsTokenRevoked(jti)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	s.tokens[jti] = tokenEntry{revoked: revoked, checkedAt: now}
+	s.pruneDueLocked(now)
+	s.mu.Unlock()
+
+	return revoked, nil
+}
+
+func (s *CachedRevocationStore) userRevocation(userID string) (*auth_entity.UserRevocation, error) {
+	now := s.now()
+
+	s.mu.RLock()
+	entry, ok := s.users[userID]
+	s.mu.RUnlock()
+
+	if ok && now.Sub(entry.checkedAt) < s.ttl {
+		return entry.revocation, nil
+	}
+
+	revocation, err := s.repo.FindUserRevocation(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.users[userID] = userEntry{revocation: revocation, checkedAt: now}
+	s.pruneDueLocked(now)
+	s.mu.Unlock()
+
+	return revocation, nil
+}
+
+func (s *CachedRevocationStore) pruneDueLocked(now time.Time) {
+	if now.Sub(s.prunedAt) < s.ttl {
+		return
+	}
+	s.pruneLocked(now)
+	s.prunedAt = now
+}
+
+// pruneLocked drops entries that would be reloaded anyway. Revocations read
+// back from the database carry no expiry, so they go after ttl like negative
+// answers; asking again is harmless.
+func (s *CachedRevocationStore) pruneLocked(now time.Time) {
+	for jti, entry := range s.tokens {
+		if entry.revoked && !entry.expiresAt.IsZero() {
+			if now.After(entry.expiresAt) {
+				delete(s.tokens, jti)
+			}
+			continue
+		}
+		if now.Sub(entry.checkedAt) >= s.ttl {
+			delete(s.tokens, jti)
+		}
+	}
+	for userID, entry := range s.users {
+		if now.Sub(entry.checkedAt) >= s.ttl {
+			delete(s.users, userID)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package infra_revocation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type MockRevocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockRevocationRepository) RevokeToken(t *auth_entity.RevokedToken) error {
+	args := m.Called(t)
+	return args.Error(0)
+}
+
+func (m *MockRevocationRepository) IsTokenRevoked(jti string) (bool, error) {
+	args := m.Called(jti)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRevocationRepository) RevokeUser(userID string, revokedAt time.Time) error {
+	args := m.Called(userID, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockRevocationRepository) FindUserRevocation(userID string) (*auth_entity.UserRevocation, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.UserRevocation), args.Error(1)
+}
+
+func (m *MockRevocationRepository) DeleteExpired(now time.Time) error {
+	args := m.Called(now)
+	return args.Error(0)
+}
+
+func newTestStore(repo *MockRevocationRepository, now *time.Time) *CachedRevocationStore {
+	store := NewCachedRevocationStore(repo, 30*time.Second)
+	store.now = func() time.Time { return *now }
+	return store
+}
+
+func TestCachedRevocationStore_RevokeToken_ServedFromCache(t *testing.T) {
+	repo := new(MockRevocationRepository)
+	now := time.Now()
+	store := newTestStore(repo, &now)
+
+	repo.On("RevokeToken", mock.MatchedBy(func(tk *auth_entity.RevokedToken) bool {
+		return tk.JTI == "jti-1" && tk.UserID == "user-1"
+	})).Return(nil)
+	repo.On("DeleteExpired", now).Return(nil)
+
+	assert.NoError(t, store.RevokeToken("jti-1", "user-1", now.Add(time.Hour)))
+
+	revoked, err := store.IsRevoked("jti-1", "user-1", now)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	repo.AssertNotCalled(t, "IsTokenRevoked", mock.Anything)
+}
+
+func TestCachedRevocationStore_IsRevoked_CachesNegativeUntilTTL(t *testing.T) {
+	repo := new(MockRevocationRepository)
+	now := time.Now()
+	store := newTestStore(repo, &now)
+
+	repo.On("IsTokenRevoked", "jti-1").Return(false, nil).Once()
+	repo.On("FindUserRevocation", "user-1").Return(nil, nil).Once()
+
+	revoked, err := store.IsRevoked("jti-1", "user-1", now)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = store.IsRevoked("jti-1", "user-1", now)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+	repo.AssertExpectations(t)
+
+	now = now.Add(31 * time.Second)
+	repo.On("IsTokenRevoked", "jti-1").Return(true, nil).Once()
+
+	revoked, err = store.IsRevoked("jti-1", "user-1", now)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	repo.AssertExpectations(t)
+}
+
+func TestCachedRevocationStore_RevokeAllForUser(t *testing.T) {
+	repo := new(MockRevocationRepository)
+	now := time.Now()
+	store := newTestStore(repo, &now)
+
+	repo.On("RevokeUser", "user-1", now).Return(nil)
+	repo.On("IsTokenRevoked", mock.Anything).Return(false, nil)
+
+	assert.NoError(t, store.RevokeAllForUser("user-1"))
+
+	revoked, err := store.IsRevoked("jti-old", "user-1", now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = store.IsRevoked("jti-new", "user-1", now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+	repo.AssertNotCalled(t, "FindUserRevocation", mock.Anything)
+}
+
+func TestCachedRevocationStore_IsRevoked_RepositoryError(t *testing.T) {
+	repo := new(MockRevocationRepository)
+	now := time.Now()
+	store := newTestStore(repo, &now)
+
+	repo.On("IsTokenRevoked", "jti-1").Return(false, errors.New("db down"))
+
+	revoked, err := store.IsRevoked("jti-1", "user-1", now)
+
+	assert.Error(t, err)
+	assert.False(t, revoked)
+}
+
+func TestCachedRevocationStore_RevokeToken_RepositoryError(t *testing.T) {
+	repo := new(MockRevocationRepository)
+	now := time.Now()
+	store := newTestStore(repo, &now)
+
+	repo.On("RevokeToken", mock.Anything).Return(errors.New("db down"))
+	repo.On("IsTokenRevoked", "jti-1").Return(false, nil)
+	repo.On("FindUserRevocation", "user-1").Return(nil, nil)
+
+	assert.Error(t, store.RevokeToken("jti-1", "user-1", now.Add(time.Hour)))
+
+	revoked, err := store.IsRevoked("jti-1", "user-1", now)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestCachedRevocationStore_IsRevoked_WithoutJTI(t *testing.T) {
+	repo := new(MockRevocationRepository)
+	now := time.Now()
+	store := newTestStore(repo, &now)
+
+	repo.On("FindUserRevocation", "user-1").Return(&auth_entity.UserRevocation{UserID: "user-1", RevokedAt: now}, nil)
+
+	revoked, err := store.IsRevoked("", "user-1", now.Add(-time.Hour))
+
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	repo.AssertNotCalled(t, "IsTokenRevoked", mock.Anything)
+}
+
+func TestCachedRevocationStore_IsRevoked_PrunesStaleEntriesOnRead(t *testing.T) {
+	repo := new(MockRevocationRepository)
+	now := time.Now()
+	store := newTestStore(repo, &now)
+
+	repo.On("IsTokenRevoked", mock.Anything).Return(false, nil)
+	repo.On("FindUserRevocation", mock.Anything).Return(nil, nil)
+
+	for _, jti := range []string{"jti-1", "jti-2", "jti-3"} {
+		_, err := store.IsRevoked(jti, "user-1", now)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, store.tokens, 3)
+
+	now = now.Add(31 * time.Second)
+	_, err := store.IsRevoked("jti-4", "user-2", now)
+	assert.NoError(t, err)
+
+	assert.Len(t, store.tokens, 1)
+	assert.Contains(t, store.tokens, "jti-4")
+	assert.Len(t, store.users, 1)
+	assert.Contains(t, store.users, "user-2")
+}
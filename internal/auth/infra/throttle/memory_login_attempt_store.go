@@ -0,0 +1,77 @@
+package infra_throttle
+
+import (
+	"sync"
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_throttle "github.com/williamkoller/system-education/internal/auth/port/throttle"
+)
+
+// MemoryLoginAttemptStore keeps counters in process. Each instance counts on
+// its own, so behind a load balancer an attacker gets one budget per
+// instance; use the database store when running more than one.
+type MemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]auth_entity.LoginAttempts
+	prunedAt time.Time
+}
+
+const pruneInterval = time.Minute
+
+var _ port_auth_throttle.LoginAttemptStore = &MemoryLoginAttemptStore{}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{attempts: make(map[string]auth_entity.LoginAttempts)}
+}
+
+func (s *MemoryLoginAttemptStore) Get(key string) (*auth_entity.LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts, ok := s.attempts[key]
+	if !ok {
+		return &auth_entity.LoginAttempts{Key: key}, nil
+	}
+	return &attempts, nil
+}
+
+func (s *MemoryLoginAttemptStore) RecordFailure(key string, at time.Time, window time.Duration) (*auth_entity.LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts, ok := s.attempts[key]
+	if !ok || (window > 0 && at.Sub(attempts.LastFailureAt) >= window) {
+		attempts = auth_entity.LoginAttempts{Key: key}
+	}
+	attempts.Failures++
+	attempts.LastFailureAt = at
+	s.attempts[key] = attempts
+
+	s.prune(at, window)
+
+	return &attempts, nil
+}
+
+func (s *MemoryLoginAttemptStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+// prune drops counters that fell out of the window so the map does not grow
+// with every address that ever failed a login. It runs at most once per
+// pruneInterval.
+func (s *MemoryLoginAttemptStore) prune(now time.Time, window time.Duration) {
+	if window <= 0 || now.Sub(s.prunedAt) < pruneInterval {
+		return
+	}
+	s.prunedAt = now
+	for key, attempts := range s.attempts {
+		if now.Sub(attempts.LastFailureAt) >= window {
+			delete(s.attempts, key)
+		}
+	}
+}
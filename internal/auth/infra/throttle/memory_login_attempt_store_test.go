@@ -0,0 +1,66 @@
+package infra_throttle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLoginAttemptStore_RecordFailure(t *testing.T) {
+	store := NewMemoryLoginAttemptStore()
+	now := time.Now()
+
+	attempts, err := store.RecordFailure("account:john@example.com", now, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts.Failures)
+
+	attempts, err = store.RecordFailure("account:john@example.com", now.Add(time.Second), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts.Failures)
+	assert.Equal(t, now.Add(time.Second), attempts.LastFailureAt)
+
+	found, err := store.Get("account:john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 2, found.Failures)
+}
+
+func TestMemoryLoginAttemptStore_WindowRestartsCount(t *testing.T) {
+	store := NewMemoryLoginAttemptStore()
+	now := time.Now()
+
+	_, err := store.RecordFailure("ip:10.0.0.1", now, time.Hour)
+	require.NoError(t, err)
+	_, err = store.RecordFailure("ip:10.0.0.1", now, time.Hour)
+	require.NoError(t, err)
+
+	attempts, err := store.RecordFailure("ip:10.0.0.1", now.Add(time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts.Failures)
+}
+
+func TestMemoryLoginAttemptStore_Reset(t *testing.T) {
+	store := NewMemoryLoginAttemptStore()
+
+	_, err := store.RecordFailure("account:john@example.com", time.Now(), time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, store.Reset("account:john@example.com"))
+
+	found, err := store.Get("account:john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 0, found.Failures)
+	assert.Equal(t, "account:john@example.com", found.Key)
+}
+
+func TestMemoryLoginAttemptStore_PrunesExpiredKeys(t *testing.T) {
+	store := NewMemoryLoginAttemptStore()
+	now := time.Now()
+
+	_, err := store.RecordFailure("ip:10.0.0.1", now, time.Hour)
+	require.NoError(t, err)
+	_, err = store.RecordFailure("ip:10.0.0.2", now.Add(2*time.Hour), time.Hour)
+	require.NoError(t, err)
+
+	assert.Len(t, store.attempts, 1)
+}
@@ -0,0 +1,7 @@
+package port_auth_cryptography
+
+import auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+
+type KeySetProvider interface {
+	PublicKeys() auth_entity.JSONWebKeySet
+}
@@ -0,0 +1,13 @@
+package port_auth_cryptography
+
+import "time"
+
+// OTPProvider generates and checks time-based one-time passwords. The time
+// is always passed in so callers control the clock.
+type OTPProvider interface {
+	GenerateSecret() (string, error)
+	URI(secret, account string) string
+	// Validate reports whether code is valid for secret at the given time
+	// and, if so, the time step it matched.
+	Validate(secret, code string, at time.Time) (step int64, ok bool)
+}
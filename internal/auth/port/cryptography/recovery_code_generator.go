@@ -0,0 +1,5 @@
+package port_auth_cryptography
+
+type RecoveryCodeGenerator interface {
+	Generate(count int) ([]string, error)
+}
@@ -0,0 +1,6 @@
+package port_auth_cryptography
+
+type SecureTokenGenerator interface {
+	Generate() (token string, hash string, err error)
+	Hash(token string) string
+}
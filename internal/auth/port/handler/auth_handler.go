@@ -4,5 +4,49 @@ import "github.com/gin-gonic/gin"
 
 type AuthHandler interface {
 	Login(c *gin.Context)
-	Profile( c *gin.Context)
+	VerifyMFA(c *gin.Context)
+	EnrollMFA(c *gin.Context)
+	ConfirmMFA(c *gin.Context)
+	Refresh(c *gin.Context)
+	Logout(c *gin.Context)
+	RevokeUserSessions(c *gin.Context)
+	Sessions(c *gin.Context)
+	EndSession(c *gin.Context)
+	UnlockAccount(c *gin.Context)
+}
+
+type ProfileHandler interface {
+	Me(c *gin.Context)
+	UpdateMe(c *gin.Context)
+}
+
+type AccountHandler interface {
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
+	VerifyEmail(c *gin.Context)
+	ResendEmailVerification(c *gin.Context)
+	RequestEmailChange(c *gin.Context)
+	ConfirmEmailChange(c *gin.Context)
+}
+
+type OIDCHandler interface {
+	Login(c *gin.Context)
+	Callback(c *gin.Context)
+}
+
+type OAuthHandler interface {
+	RegisterClient(c *gin.Context)
+	Authorize(c *gin.Context)
+	Decide(c *gin.Context)
+	Token(c *gin.Context)
+	Introspect(c *gin.Context)
+	Consents(c *gin.Context)
+	RevokeConsent(c *gin.Context)
+}
+
+type APIKeyHandler interface {
+	Create(c *gin.Context)
+	List(c *gin.Context)
+	Revoke(c *gin.Context)
+	RevokeUserKeys(c *gin.Context)
 }
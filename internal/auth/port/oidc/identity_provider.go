@@ -0,0 +1,18 @@
+package port_auth_oidc
+
+import auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+
+// IdentityProvider is an OpenID Connect provider the users can sign in with.
+type IdentityProvider interface {
+	Name() string
+	// AuthCodeURL is where the browser is sent to authenticate.
+	AuthCodeURL(state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems an authorization code and returns the claims of the
+	// ID token after checking its signature, issuer, audience and expiry.
+	// Checking the nonce is left to the caller, which knows the expected
+	// value.
+	Exchange(code, codeVerifier string) (*auth_entity.ExternalClaims, error)
+	// AllowsDomain reports whether users with emails in domain may sign in
+	// through this provider.
+	AllowsDomain(domain string) bool
+}
@@ -0,0 +1,7 @@
+package port_auth_permission
+
+type PermissionGranter interface {
+	// GrantDefault gives a newly provisioned user the default permission
+	// set.
+	GrantDefault(userID string) error
+}
@@ -0,0 +1,18 @@
+package port_auth_repository
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type APIKeyRepository interface {
+	Save(k *auth_entity.APIKey) (*auth_entity.APIKey, error)
+	FindByKeyHash(hash string) (*auth_entity.APIKey, error)
+	FindByUser(userID string) ([]*auth_entity.APIKey, error)
+	// Revoke revokes the user's key and returns ErrAPIKeyNotFound when the
+	// user has no such unrevoked key.
+	Revoke(id, userID string, revokedAt time.Time) error
+	RevokeAllForUser(userID string, revokedAt time.Time) error
+	TouchLastUsed(id string, usedAt time.Time) error
+}
@@ -0,0 +1,8 @@
+package port_auth_repository
+
+import auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+
+type ExternalIdentityRepository interface {
+	FindByProviderSubject(provider, subject string) (*auth_entity.ExternalIdentity, error)
+	Save(i *auth_entity.ExternalIdentity) (*auth_entity.ExternalIdentity, error)
+}
@@ -0,0 +1,17 @@
+package port_auth_repository
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type MFAChallengeRepository interface {
+	Save(c *auth_entity.MFAChallenge) (*auth_entity.MFAChallenge, error)
+	FindByTokenHash(hash string) (*auth_entity.MFAChallenge, error)
+	// ConsumeAttempt counts one verification attempt and reports false once
+	// maxAttempts have been spent.
+	ConsumeAttempt(id string, maxAttempts int) (bool, error)
+	// MarkUsed reports false when the challenge was already completed.
+	MarkUsed(id string, usedAt time.Time) (bool, error)
+}
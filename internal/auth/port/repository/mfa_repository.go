@@ -0,0 +1,23 @@
+package port_auth_repository
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type MFARepository interface {
+	FindByUserID(userID string) (*auth_entity.MFACredential, error)
+	// Save stores a pending credential, replacing any previous one for the
+	// same user.
+	Save(c *auth_entity.MFACredential) (*auth_entity.MFACredential, error)
+	// Confirm marks the credential confirmed at the given step and replaces
+	// the user's recovery codes in one go.
+	Confirm(userID string, step int64, confirmedAt time.Time, codes []*auth_entity.RecoveryCode) error
+	// AdvanceStep records step as the last accepted TOTP step and reports
+	// false when it is not newer than the stored one, i.e. a replay.
+	AdvanceStep(userID string, step int64) (bool, error)
+	// UseRecoveryCode consumes an unused code and reports false when there
+	// is none with that hash.
+	UseRecoveryCode(userID, codeHash string, usedAt time.Time) (bool, error)
+}
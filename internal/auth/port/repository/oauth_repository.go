@@ -0,0 +1,30 @@
+package port_auth_repository
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type OAuthClientRepository interface {
+	Save(c *auth_entity.OAuthClient) (*auth_entity.OAuthClient, error)
+	// FindByClientID returns ErrOAuthClientNotFound when there is none.
+	FindByClientID(clientID string) (*auth_entity.OAuthClient, error)
+}
+
+type OAuthCodeRepository interface {
+	Save(c *auth_entity.OAuthAuthorizationCode) error
+	// Consume deletes and returns the code, so each is redeemed once. It
+	// returns ErrOAuthInvalidGrant when there is none.
+	Consume(codeHash string) (*auth_entity.OAuthAuthorizationCode, error)
+	DeleteExpired(before time.Time) error
+}
+
+type OAuthConsentRepository interface {
+	// Find returns ErrOAuthConsentNotFound when the user never consented.
+	Find(userID, clientID string) (*auth_entity.OAuthConsent, error)
+	// Save creates the consent or replaces its scopes.
+	Save(c *auth_entity.OAuthConsent) error
+	FindByUser(userID string) ([]*auth_entity.OAuthConsent, error)
+	Delete(userID, clientID string) error
+}
@@ -0,0 +1,15 @@
+package port_auth_repository
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type OIDCStateRepository interface {
+	Save(s *auth_entity.OIDCLoginState) error
+	// Consume deletes and returns the state, so each can complete one
+	// login. It returns ErrInvalidOIDCState when there is none.
+	Consume(stateHash string) (*auth_entity.OIDCLoginState, error)
+	DeleteExpired(before time.Time) error
+}
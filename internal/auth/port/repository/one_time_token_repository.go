@@ -0,0 +1,17 @@
+package port_auth_repository
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type OneTimeTokenRepository interface {
+	Save(t *auth_entity.OneTimeToken) (*auth_entity.OneTimeToken, error)
+	FindByTokenHash(purpose auth_entity.TokenPurpose, hash string) (*auth_entity.OneTimeToken, error)
+	// MarkUsed reports false when the token was already used.
+	MarkUsed(id string, usedAt time.Time) (bool, error)
+	// InvalidateForUser uses up every outstanding token of the purpose, so
+	// only the most recently mailed link works.
+	InvalidateForUser(userID string, purpose auth_entity.TokenPurpose, at time.Time) error
+}
@@ -0,0 +1,17 @@
+package port_auth_repository
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type RefreshTokenRepository interface {
+	Save(t *auth_entity.RefreshToken) (*auth_entity.RefreshToken, error)
+	FindByTokenHash(hash string) (*auth_entity.RefreshToken, error)
+	// MarkUsed flags the token as consumed and reports false when another
+	// request already consumed it, which callers must treat as reuse.
+	MarkUsed(id string, usedAt time.Time) (bool, error)
+	RevokeFamily(familyID string, revokedAt time.Time) error
+	RevokeAllForUser(userID string, revokedAt time.Time) error
+}
@@ -0,0 +1,15 @@
+package port_auth_repository
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type RevocationRepository interface {
+	RevokeToken(t *auth_entity.RevokedToken) error
+	IsTokenRevoked(jti string) (bool, error)
+	RevokeUser(userID string, revokedAt time.Time) error
+	FindUserRevocation(userID string) (*auth_entity.UserRevocation, error)
+	DeleteExpired(now time.Time) error
+}
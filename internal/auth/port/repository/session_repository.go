@@ -0,0 +1,19 @@
+package port_auth_repository
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+type SessionRepository interface {
+	Save(s *auth_entity.Session) (*auth_entity.Session, error)
+	// FindByID returns ErrSessionNotFound when there is no such session.
+	FindByID(id string) (*auth_entity.Session, error)
+	// FindActiveByUser lists the user's sessions that have not ended and were
+	// seen after since, most recently seen first.
+	FindActiveByUser(userID string, since time.Time) ([]*auth_entity.Session, error)
+	Touch(id string, seenAt time.Time) error
+	End(id string, endedAt time.Time) error
+	EndAllForUser(userID string, endedAt time.Time) error
+}
@@ -0,0 +1,9 @@
+package port_auth_revocation
+
+import "time"
+
+type RevocationStore interface {
+	RevokeToken(jti, userID string, expiresAt time.Time) error
+	RevokeAllForUser(userID string) error
+	IsRevoked(jti, userID string, issuedAt time.Time) (bool, error)
+}
@@ -0,0 +1,11 @@
+package port_auth_service
+
+type LoginThrottle interface {
+	// Check returns an auth_entity.LoginThrottledError when the account or
+	// the client address has to wait before trying again.
+	Check(email, clientIP string) error
+	RecordFailure(email, clientIP string) error
+	RecordSuccess(email, clientIP string) error
+	// Unlock clears the account's failures ahead of time.
+	Unlock(email string) error
+}
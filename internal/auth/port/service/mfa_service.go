@@ -0,0 +1,14 @@
+package port_auth_service
+
+import auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+
+type MFAService interface {
+	// Challenge starts the second login step for a user who passed the
+	// password check. It returns nil when the user does not need MFA.
+	Challenge(userID, account string) (*auth_entity.MFAChallengeTicket, error)
+	// Verify completes a challenge with either a TOTP code or a recovery
+	// code and returns the user it was issued for.
+	Verify(challengeToken, code, recoveryCode string) (userID string, recoveryCodes []string, err error)
+	Enroll(userID, account string) (*auth_entity.MFAEnrollment, error)
+	Confirm(userID, code string) ([]string, error)
+}
@@ -0,0 +1,19 @@
+package port_auth_throttle
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+// LoginAttemptStore keeps failed-login counters per throttling key.
+type LoginAttemptStore interface {
+	// Get returns the counters for key, with zero failures when there are
+	// none.
+	Get(key string) (*auth_entity.LoginAttempts, error)
+	// RecordFailure counts a failure at the given time and returns the
+	// updated counters. A key whose last failure is older than window starts
+	// over from one.
+	RecordFailure(key string, at time.Time, window time.Duration) (*auth_entity.LoginAttempts, error)
+	Reset(key string) error
+}
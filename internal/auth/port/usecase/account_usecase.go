@@ -0,0 +1,11 @@
+package port_auth_usecase
+
+type AccountUsecase interface {
+	ForgotPassword(email string) error
+	ResetPassword(token, newPassword string) error
+	SendEmailVerification(userID string) error
+	ResendEmailVerification(email string) error
+	VerifyEmail(token string) error
+	RequestEmailChange(userID, password, newEmail string) error
+	ConfirmEmailChange(token string) error
+}
@@ -0,0 +1,22 @@
+package port_auth_usecase
+
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
+// APIKeyAuthenticator is what the auth middleware needs to accept
+// "Authorization: ApiKey ..." headers. Authenticate returns ErrInvalidAPIKey
+// for unknown, expired and revoked keys alike.
+type APIKeyAuthenticator interface {
+	Authenticate(key string) (*auth_entity.APIKeyPrincipal, error)
+}
+
+type APIKeyUsecase interface {
+	APIKeyAuthenticator
+	Create(userID, name string, scopes []string, expiresAt *time.Time) (*auth_entity.APIKeyIssued, error)
+	List(userID string) ([]*auth_entity.APIKey, error)
+	Revoke(userID, id string) error
+	RevokeAllForUser(userID string) error
+}
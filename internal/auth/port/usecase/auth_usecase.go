@@ -1,6 +1,23 @@
 package port_auth_usecase
 
+import (
+	"time"
+
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+)
+
 type AuthUsecase interface {
-	Login(email, password string) (string, error)
-	Profile(email string) (string, error)
+	Login(email, password string, client auth_entity.ClientInfo) (*auth_entity.LoginResult, error)
+	CompleteLogin(userID string, client auth_entity.ClientInfo) (*auth_entity.LoginResult, error)
+	VerifyMFA(challengeToken, code, recoveryCode string, client auth_entity.ClientInfo) (*auth_entity.MFAVerification, error)
+	EnrollMFA(userID string) (*auth_entity.MFAEnrollment, error)
+	ConfirmMFA(userID, code string) ([]string, error)
+	Refresh(refreshToken string) (*auth_entity.TokenPair, error)
+	Logout(tokenID, userID string, expiresAt time.Time, refreshToken string) error
+	RevokeAllSessions(userID string) error
+	Sessions(userID string) ([]*auth_entity.Session, error)
+	// EndSession signs the session out everywhere its tokens are used. It
+	// returns ErrSessionNotFound for sessions of other users.
+	EndSession(userID, sessionID string) error
+	UnlockAccount(userID string) error
 }
@@ -0,0 +1,17 @@
+package port_auth_usecase
+
+import auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+
+type OAuthUsecase interface {
+	RegisterClient(ownerID string, input *auth_entity.OAuthClient) (*auth_entity.OAuthClientRegistration, error)
+	// Authorize checks an authorization request for the signed-in user. It
+	// answers with a redirect carrying the code when the user already
+	// consented to the scopes, and with the consent to ask for otherwise.
+	Authorize(userID string, req *auth_entity.OAuthAuthorizationRequest) (*auth_entity.OAuthAuthorization, error)
+	// Decide records the user's answer to the consent screen.
+	Decide(userID string, req *auth_entity.OAuthAuthorizationRequest, approved bool) (*auth_entity.OAuthAuthorization, error)
+	Token(req *auth_entity.OAuthTokenRequest) (*auth_entity.OAuthToken, error)
+	Introspect(clientID, clientSecret, token string) (*auth_entity.OAuthIntrospection, error)
+	Consents(userID string) ([]*auth_entity.OAuthConsent, error)
+	RevokeConsent(userID, clientID string) error
+}
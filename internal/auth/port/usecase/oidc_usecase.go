@@ -0,0 +1,9 @@
+package port_auth_usecase
+
+import auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+
+type OIDCUsecase interface {
+	// Begin returns the provider URL the browser is sent to.
+	Begin(provider string) (string, error)
+	Callback(provider, state, code string, client auth_entity.ClientInfo) (*auth_entity.LoginResult, error)
+}
@@ -0,0 +1,28 @@
+package port_auth_usecase
+
+import (
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+)
+
+// Profile is what a signed-in user can see about their own account.
+type Profile struct {
+	User           *user_entity.User
+	Permissions    []*permission_entity.Permission
+	ActiveSessions int
+	MFAEnabled     bool
+}
+
+// ProfileUpdate holds the fields a user may change about themselves. Nil
+// fields are left untouched.
+type ProfileUpdate struct {
+	Name     *string
+	Surname  *string
+	Nickname *string
+	Age      *int32
+}
+
+type ProfileUsecase interface {
+	Me(userID string) (*Profile, error)
+	UpdateMe(userID string, update ProfileUpdate) (*Profile, error)
+}
@@ -1,6 +1,116 @@
 package auth_dtos
 
+import "time"
+
 type AuthDto struct {
 	Email    string `json:"email" validate:"required,email" example:"john.doe@example.com"`
 	Password string `json:"password" validate:"required,min=8" example:"strongPassword123"`
 }
+
+type RefreshTokenDto struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type LogoutDto struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type MFAVerifyDto struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" example:"123456"`
+	RecoveryCode   string `json:"recovery_code" example:"abcdefgh-ijklmnop"`
+}
+
+type MFAConfirmDto struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+type ForgotPasswordDto struct {
+	Email string `json:"email" binding:"required,email" example:"john.doe@example.com"`
+}
+
+type ResetPasswordDto struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required" example:"strongPassword123"`
+}
+
+type VerifyEmailDto struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendEmailVerificationDto struct {
+	Email string `json:"email" binding:"required,email" example:"john.doe@example.com"`
+}
+
+type RequestEmailChangeDto struct {
+	Email    string `json:"email" binding:"required,email" example:"john.new@example.com"`
+	Password string `json:"password" binding:"required" example:"strongPassword123"`
+}
+
+type ConfirmEmailChangeDto struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// OIDCCallbackDto is the query string the provider redirects back with.
+type OIDCCallbackDto struct {
+	Code             string `form:"code"`
+	State            string `form:"state"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+type RegisterOAuthClientDto struct {
+	Name         string   `json:"name" binding:"required" example:"Reporting dashboard"`
+	RedirectURIs []string `json:"redirect_uris" example:"https://dashboard.example.com/callback"`
+	GrantTypes   []string `json:"grant_types" binding:"required" example:"authorization_code"`
+	Scopes       []string `json:"scopes" binding:"required" example:"users:read"`
+	Confidential bool     `json:"confidential"`
+}
+
+// OAuthAuthorizeDto is the authorization request of RFC 6749 4.1.1 with the
+// PKCE parameters of RFC 7636.
+type OAuthAuthorizeDto struct {
+	ResponseType        string `form:"response_type" json:"response_type"`
+	ClientID            string `form:"client_id" json:"client_id" binding:"required"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri" binding:"required"`
+	Scope               string `form:"scope" json:"scope"`
+	State               string `form:"state" json:"state"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method"`
+}
+
+type OAuthDecisionDto struct {
+	OAuthAuthorizeDto
+	Approve bool `json:"approve"`
+}
+
+type OAuthTokenDto struct {
+	GrantType    string `form:"grant_type"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	Scope        string `form:"scope"`
+}
+
+type OAuthIntrospectDto struct {
+	Token        string `form:"token"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+type CreateAPIKeyDto struct {
+	Name      string     `json:"name" binding:"required" example:"CI pipeline"`
+	Scopes    []string   `json:"scopes" binding:"required" example:"users:read"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2030-01-01T00:00:00Z"`
+}
+
+// UpdateProfileDto lists every field a user may change on their own account.
+// Requests carrying any other field are rejected.
+type UpdateProfileDto struct {
+	Name     *string `json:"name" example:"John"`
+	Surname  *string `json:"surname" example:"Doe"`
+	Nickname *string `json:"nickname" example:"johndoe"`
+	Age      *int32  `json:"age" example:"30"`
+}
@@ -0,0 +1,158 @@
+package auth_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type AccountHandler struct {
+	usecase port_auth_usecase.AccountUsecase
+}
+
+func NewAccountHandler(usecase port_auth_usecase.AccountUsecase) *AccountHandler {
+	return &AccountHandler{usecase: usecase}
+}
+
+var _ port_auth_handler.AccountHandler = &AccountHandler{}
+
+func (h *AccountHandler) ForgotPassword(c *gin.Context) {
+	var input auth_dtos.ForgotPasswordDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.ForgotPassword(input.Email); err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the email is registered, a reset link was sent"})
+}
+
+func (h *AccountHandler) ResetPassword(c *gin.Context) {
+	var input auth_dtos.ResetPasswordDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.ResetPassword(input.Token, input.Password); err != nil {
+		var validation *user_entity.ValidationError
+		switch {
+		case errors.As(err, &validation), errors.Is(err, auth_entity.ErrInvalidOneTimeToken):
+			c.Status(http.StatusBadRequest)
+		default:
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password updated"})
+}
+
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	var input auth_dtos.VerifyEmailDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.VerifyEmail(input.Token); err != nil {
+		if errors.Is(err, auth_entity.ErrInvalidOneTimeToken) {
+			c.Status(http.StatusBadRequest)
+		} else {
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "email verified"})
+}
+
+func (h *AccountHandler) ResendEmailVerification(c *gin.Context) {
+	var input auth_dtos.ResendEmailVerificationDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.ResendEmailVerification(input.Email); err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the email is registered and unverified, a verification link was sent"})
+}
+
+func (h *AccountHandler) RequestEmailChange(c *gin.Context) {
+	var input auth_dtos.RequestEmailChangeDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.RequestEmailChange(c.GetString("userID"), input.Password, input.Email); err != nil {
+		c.Status(emailChangeStatus(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "a confirmation link was sent to the new email"})
+}
+
+func (h *AccountHandler) ConfirmEmailChange(c *gin.Context) {
+	var input auth_dtos.ConfirmEmailChangeDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.ConfirmEmailChange(input.Token); err != nil {
+		c.Status(emailChangeStatus(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "email changed"})
+}
+
+func emailChangeStatus(err error) int {
+	var validation *user_entity.ValidationError
+	switch {
+	case errors.As(err, &validation), errors.Is(err, auth_entity.ErrInvalidOneTimeToken):
+		return http.StatusBadRequest
+	case errors.Is(err, auth_entity.ErrInvalidCredentials):
+		return http.StatusUnauthorized
+	case errors.Is(err, port_user_repository.ErrUserNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, port_user_repository.ErrUserAlreadyExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,86 @@
+package auth_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_mapper "github.com/williamkoller/system-education/internal/auth/application/mapper"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+)
+
+type APIKeyHandler struct {
+	usecase port_auth_usecase.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(usecase port_auth_usecase.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{usecase: usecase}
+}
+
+var _ port_auth_handler.APIKeyHandler = &APIKeyHandler{}
+
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var input auth_dtos.CreateAPIKeyDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	issued, err := h.usecase.Create(c.GetString("userID"), input.Name, input.Scopes, input.ExpiresAt)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, auth_entity.ErrInvalidAPIKeyInput):
+			c.Status(http.StatusBadRequest)
+		case errors.Is(err, auth_entity.ErrAPIKeyScopeNotHeld):
+			c.Status(http.StatusForbidden)
+		default:
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusCreated, auth_mapper.ToCreatedAPIKeyResponse(issued))
+}
+
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.usecase.List(c.GetString("userID"))
+
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToAPIKeyResponses(keys))
+}
+
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	if err := h.usecase.Revoke(c.GetString("userID"), c.Param("id")); err != nil {
+		if errors.Is(err, auth_entity.ErrAPIKeyNotFound) {
+			c.Status(http.StatusNotFound)
+		} else {
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *APIKeyHandler) RevokeUserKeys(c *gin.Context) {
+	if err := h.usecase.RevokeAllForUser(c.Param("id")); err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user api keys revoked"})
+}
@@ -1,9 +1,15 @@
 package auth_handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	auth_mapper "github.com/williamkoller/system-education/internal/auth/application/mapper"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
 	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
 	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
@@ -28,28 +34,198 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.usecase.Login(input.Email, input.Password)
+	result, err := h.usecase.Login(input.Email, input.Password, clientInfo(c))
 
 	if err != nil {
+		var throttled *auth_entity.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.Status(http.StatusTooManyRequests)
+		case errors.Is(err, auth_entity.ErrInvalidCredentials):
+			c.Status(http.StatusUnauthorized)
+		case errors.Is(err, auth_entity.ErrEmailNotVerified):
+			c.Status(http.StatusForbidden)
+		default:
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if result.MFARequired() {
+		c.JSON(http.StatusOK, auth_mapper.ToMFAChallengeResponse(result.Challenge))
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToTokenResponse(result.Tokens))
+}
+
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var input auth_dtos.MFAVerifyDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	verification, err := h.usecase.VerifyMFA(input.ChallengeToken, input.Code, input.RecoveryCode, clientInfo(c))
+
+	if err != nil {
+		if errors.Is(err, auth_entity.ErrInvalidMFAChallenge) || errors.Is(err, auth_entity.ErrInvalidMFACode) {
+			c.Status(http.StatusUnauthorized)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToMFAVerificationResponse(verification))
+}
+
+func (h *AuthHandler) EnrollMFA(c *gin.Context) {
+	enrollment, err := h.usecase.EnrollMFA(c.GetString("userID"))
+
+	if err != nil {
+		if errors.Is(err, auth_entity.ErrMFAAlreadyEnabled) {
+			c.Status(http.StatusConflict)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToMFAEnrollmentResponse(enrollment))
+}
+
+func (h *AuthHandler) ConfirmMFA(c *gin.Context) {
+	var input auth_dtos.MFAConfirmDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	codes, err := h.usecase.ConfirmMFA(c.GetString("userID"), input.Code)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, auth_entity.ErrInvalidMFACode), errors.Is(err, auth_entity.ErrMFANotEnrolled):
+			c.Status(http.StatusBadRequest)
+		case errors.Is(err, auth_entity.ErrMFAAlreadyEnabled):
+			c.Status(http.StatusConflict)
+		default:
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var input auth_dtos.RefreshTokenDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
 		c.Status(http.StatusBadRequest)
 		c.Error(err).SetType(gin.ErrorTypePublic)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	tokens, err := h.usecase.Refresh(input.RefreshToken)
+
+	if err != nil {
+		if errors.Is(err, auth_entity.ErrInvalidRefreshToken) || errors.Is(err, auth_entity.ErrRefreshTokenReused) {
+			c.Status(http.StatusUnauthorized)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToTokenResponse(tokens))
 }
 
-func (h *AuthHandler) Profile(c *gin.Context) {
-	userEmail, _ := c.Get("userEmail")
-	email, err := h.usecase.Profile(userEmail.(string))
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var input auth_dtos.LogoutDto
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.Status(http.StatusBadRequest)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
+	}
+
+	tokenID := c.GetString("tokenID")
+	userID := c.GetString("userID")
+	expiresAt, _ := c.Get("tokenExpiresAt")
+	expiry, _ := expiresAt.(time.Time)
+
+	if err := h.usecase.Logout(tokenID, userID, expiry, input.RefreshToken); err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
+func (h *AuthHandler) RevokeUserSessions(c *gin.Context) {
+	if err := h.usecase.RevokeAllSessions(c.Param("id")); err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user sessions revoked"})
+}
+
+func (h *AuthHandler) Sessions(c *gin.Context) {
+	sessions, err := h.usecase.Sessions(c.GetString("userID"))
 
 	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToSessionResponses(sessions, c.GetString("sessionID")))
+}
+
+func (h *AuthHandler) EndSession(c *gin.Context) {
+	if err := h.usecase.EndSession(c.GetString("userID"), c.Param("id")); err != nil {
+		if errors.Is(err, auth_entity.ErrSessionNotFound) {
+			c.Status(http.StatusNotFound)
+		} else {
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session ended"})
+}
+
+func (h *AuthHandler) UnlockAccount(c *gin.Context) {
+	if err := h.usecase.UnlockAccount(c.Param("id")); err != nil {
 		c.Status(http.StatusBadRequest)
 		c.Error(err).SetType(gin.ErrorTypePublic)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Welcome to your profile",
-		"email":   email,
-	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "account unlocked"})
+}
+
+func clientInfo(c *gin.Context) auth_entity.ClientInfo {
+	return auth_entity.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
 }
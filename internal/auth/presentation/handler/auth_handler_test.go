@@ -0,0 +1,70 @@
+package auth_handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	auth_service "github.com/williamkoller/system-education/internal/auth/application/service"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	infra_throttle "github.com/williamkoller/system-education/internal/auth/infra/throttle"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	"github.com/williamkoller/system-education/shared/middleware"
+)
+
+// newThrottledEngine fails every login, throttling by the address clientInfo
+// reports, the way the auth usecase does.
+func newThrottledEngine(t *testing.T, trustedProxies []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	g, err := middleware.NewEngine(trustedProxies)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	throttle := auth_service.NewLoginThrottle(infra_throttle.NewMemoryLoginAttemptStore(),
+		auth_entity.DefaultAccountLockoutPolicy(), auth_entity.DefaultIPLockoutPolicy(), shared_event.NewDispatcher())
+	g.POST("/login/:email", func(c *gin.Context) {
+		ip := clientInfo(c).IP
+		if err := throttle.Check(c.Param("email"), ip); err != nil {
+			c.Status(http.StatusTooManyRequests)
+			return
+		}
+		_ = throttle.RecordFailure(c.Param("email"), ip)
+		c.Status(http.StatusUnauthorized)
+	})
+	return g
+}
+
+// login sends attempts for different accounts, so only the per-address
+// counter can throttle them, each with a different forged client address.
+func login(g *gin.Engine, attempts int) int {
+	status := 0
+	for i := 0; i < attempts; i++ {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/login/user%d@example.com", i), nil)
+		req.RemoteAddr = "192.0.2.10:4321"
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+		status = w.Code
+	}
+	return status
+}
+
+func TestClientInfo_IgnoresForgedForwardedFor(t *testing.T) {
+	g := newThrottledEngine(t, nil)
+
+	free := auth_entity.DefaultIPLockoutPolicy().FreeAttempts
+
+	assert.Equal(t, http.StatusTooManyRequests, login(g, free+2))
+}
+
+func TestClientInfo_TrustsForwardedForFromConfiguredProxies(t *testing.T) {
+	g := newThrottledEngine(t, []string{"192.0.2.0/24"})
+
+	free := auth_entity.DefaultIPLockoutPolicy().FreeAttempts
+
+	assert.Equal(t, http.StatusUnauthorized, login(g, free+2))
+}
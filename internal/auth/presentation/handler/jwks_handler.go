@@ -0,0 +1,24 @@
+package auth_handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+)
+
+type JWKSHandler struct {
+	provider port_auth_cryptography.KeySetProvider
+}
+
+func NewJWKSHandler(provider port_auth_cryptography.KeySetProvider) *JWKSHandler {
+	return &JWKSHandler{provider: provider}
+}
+
+var _ port_auth_handler.JWKSHandler = &JWKSHandler{}
+
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.provider.PublicKeys())
+}
@@ -0,0 +1,214 @@
+package auth_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_mapper "github.com/williamkoller/system-education/internal/auth/application/mapper"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+)
+
+type OAuthHandler struct {
+	usecase port_auth_usecase.OAuthUsecase
+}
+
+func NewOAuthHandler(usecase port_auth_usecase.OAuthUsecase) *OAuthHandler {
+	return &OAuthHandler{usecase: usecase}
+}
+
+var _ port_auth_handler.OAuthHandler = &OAuthHandler{}
+
+func (h *OAuthHandler) RegisterClient(c *gin.Context) {
+	var input auth_dtos.RegisterOAuthClientDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	registration, err := h.usecase.RegisterClient(c.GetString("userID"), &auth_entity.OAuthClient{
+		Name:         input.Name,
+		RedirectURIs: input.RedirectURIs,
+		GrantTypes:   input.GrantTypes,
+		Scopes:       input.Scopes,
+		Confidential: input.Confidential,
+	})
+
+	if err != nil {
+		c.Status(oauthStatus(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusCreated, auth_mapper.ToOAuthClientResponse(registration))
+}
+
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	var input auth_dtos.OAuthAuthorizeDto
+
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	authorization, err := h.usecase.Authorize(c.GetString("userID"), toAuthorizationRequest(&input))
+
+	if err != nil {
+		c.Status(oauthStatus(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToOAuthAuthorizationResponse(authorization))
+}
+
+func (h *OAuthHandler) Decide(c *gin.Context) {
+	var input auth_dtos.OAuthDecisionDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	authorization, err := h.usecase.Decide(c.GetString("userID"), toAuthorizationRequest(&input.OAuthAuthorizeDto), input.Approve)
+
+	if err != nil {
+		c.Status(oauthStatus(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToOAuthAuthorizationResponse(authorization))
+}
+
+// Token answers in the RFC 6749 5.2 error format rather than the API's own,
+// since it is called by OAuth client libraries.
+func (h *OAuthHandler) Token(c *gin.Context) {
+	var input auth_dtos.OAuthTokenDto
+
+	if err := c.ShouldBind(&input); err != nil {
+		oauthError(c, auth_entity.ErrOAuthInvalidRequest)
+		return
+	}
+
+	clientID, clientSecret := clientCredentials(c, input.ClientID, input.ClientSecret)
+
+	token, err := h.usecase.Token(&auth_entity.OAuthTokenRequest{
+		GrantType:    input.GrantType,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Code:         input.Code,
+		RedirectURI:  input.RedirectURI,
+		CodeVerifier: input.CodeVerifier,
+		Scope:        input.Scope,
+	})
+
+	if err != nil {
+		oauthError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, auth_mapper.ToOAuthTokenResponse(token))
+}
+
+func (h *OAuthHandler) Introspect(c *gin.Context) {
+	var input auth_dtos.OAuthIntrospectDto
+
+	if err := c.ShouldBind(&input); err != nil || input.Token == "" {
+		oauthError(c, auth_entity.ErrOAuthInvalidRequest)
+		return
+	}
+
+	clientID, clientSecret := clientCredentials(c, input.ClientID, input.ClientSecret)
+
+	introspection, err := h.usecase.Introspect(clientID, clientSecret, input.Token)
+
+	if err != nil {
+		oauthError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, auth_mapper.ToOAuthIntrospectionResponse(introspection))
+}
+
+func (h *OAuthHandler) Consents(c *gin.Context) {
+	consents, err := h.usecase.Consents(c.GetString("userID"))
+
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToOAuthConsentResponses(consents))
+}
+
+func (h *OAuthHandler) RevokeConsent(c *gin.Context) {
+	if err := h.usecase.RevokeConsent(c.GetString("userID"), c.Param("client_id")); err != nil {
+		if errors.Is(err, auth_entity.ErrOAuthConsentNotFound) {
+			c.Status(http.StatusNotFound)
+		} else {
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func toAuthorizationRequest(input *auth_dtos.OAuthAuthorizeDto) *auth_entity.OAuthAuthorizationRequest {
+	return &auth_entity.OAuthAuthorizationRequest{
+		ResponseType:        input.ResponseType,
+		ClientID:            input.ClientID,
+		RedirectURI:         input.RedirectURI,
+		Scope:               input.Scope,
+		State:               input.State,
+		CodeChallenge:       input.CodeChallenge,
+		CodeChallengeMethod: input.CodeChallengeMethod,
+	}
+}
+
+// clientCredentials prefers HTTP Basic authentication, which RFC 6749 2.3.1
+// requires servers to support, over credentials in the form body.
+func clientCredentials(c *gin.Context, clientID, clientSecret string) (string, string) {
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		return id, secret
+	}
+	return clientID, clientSecret
+}
+
+func oauthStatus(err error) int {
+	var oauthErr *auth_entity.OAuthError
+	if errors.As(err, &oauthErr) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func oauthError(c *gin.Context, err error) {
+	var oauthErr *auth_entity.OAuthError
+	if !errors.As(err, &oauthErr) {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	status := http.StatusBadRequest
+	if oauthErr.Code == auth_entity.ErrOAuthInvalidClient.Code {
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		status = http.StatusUnauthorized
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, gin.H{"error": oauthErr.Code, "error_description": oauthErr.Message})
+}
@@ -0,0 +1,83 @@
+package auth_handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_mapper "github.com/williamkoller/system-education/internal/auth/application/mapper"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+)
+
+type OIDCHandler struct {
+	usecase port_auth_usecase.OIDCUsecase
+}
+
+func NewOIDCHandler(usecase port_auth_usecase.OIDCUsecase) *OIDCHandler {
+	return &OIDCHandler{usecase: usecase}
+}
+
+var _ port_auth_handler.OIDCHandler = &OIDCHandler{}
+
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authURL, err := h.usecase.Begin(c.Param("provider"))
+
+	if err != nil {
+		if errors.Is(err, auth_entity.ErrUnknownIdentityProvider) {
+			c.Status(http.StatusNotFound)
+		} else {
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var input auth_dtos.OIDCCallbackDto
+
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if input.Error != "" {
+		c.Status(http.StatusUnauthorized)
+		c.Error(fmt.Errorf("identity provider denied the login: %s %s", input.Error, input.ErrorDescription)).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	result, err := h.usecase.Callback(c.Param("provider"), input.State, input.Code, clientInfo(c))
+
+	if err != nil {
+		switch {
+		case errors.Is(err, auth_entity.ErrUnknownIdentityProvider):
+			c.Status(http.StatusNotFound)
+		case errors.Is(err, auth_entity.ErrInvalidOIDCState):
+			c.Status(http.StatusBadRequest)
+		case errors.Is(err, auth_entity.ErrInvalidIDToken):
+			c.Status(http.StatusUnauthorized)
+		case errors.Is(err, auth_entity.ErrExternalEmailNotVerified), errors.Is(err, auth_entity.ErrExternalDomainNotAllowed),
+			errors.Is(err, auth_entity.ErrExternalAccountNotVerified):
+			c.Status(http.StatusForbidden)
+		default:
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if result.MFARequired() {
+		c.JSON(http.StatusOK, auth_mapper.ToMFAChallengeResponse(result.Challenge))
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToTokenResponse(result.Tokens))
+}
@@ -0,0 +1,78 @@
+package auth_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	auth_mapper "github.com/williamkoller/system-education/internal/auth/application/mapper"
+	port_auth_handler "github.com/williamkoller/system-education/internal/auth/port/handler"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_dtos "github.com/williamkoller/system-education/internal/auth/presentation/dtos"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+)
+
+type ProfileHandler struct {
+	usecase port_auth_usecase.ProfileUsecase
+}
+
+func NewProfileHandler(usecase port_auth_usecase.ProfileUsecase) *ProfileHandler {
+	return &ProfileHandler{usecase: usecase}
+}
+
+var _ port_auth_handler.ProfileHandler = &ProfileHandler{}
+
+func (h *ProfileHandler) Me(c *gin.Context) {
+	profile, err := h.usecase.Me(c.GetString("userID"))
+
+	if err != nil {
+		c.Status(profileStatus(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToProfileResponse(profile))
+}
+
+// UpdateMe decodes strictly so that attempts to set email, password or any
+// other field outside UpdateProfileDto fail instead of being ignored.
+func (h *ProfileHandler) UpdateMe(c *gin.Context) {
+	var input auth_dtos.UpdateProfileDto
+
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	profile, err := h.usecase.UpdateMe(c.GetString("userID"), port_auth_usecase.ProfileUpdate{
+		Name:     input.Name,
+		Surname:  input.Surname,
+		Nickname: input.Nickname,
+		Age:      input.Age,
+	})
+
+	if err != nil {
+		c.Status(profileStatus(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, auth_mapper.ToProfileResponse(profile))
+}
+
+func profileStatus(err error) int {
+	var validation *user_entity.ValidationError
+	switch {
+	case errors.As(err, &validation):
+		return http.StatusBadRequest
+	case errors.Is(err, port_user_repository.ErrUserNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
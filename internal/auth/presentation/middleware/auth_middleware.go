@@ -1,15 +1,63 @@
 package auth_middleware
 
 import (
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
 	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_revocation "github.com/williamkoller/system-education/internal/auth/port/revocation"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 )
 
-func AuthMiddleware(jwt port_auth_cryptography.TokenManager) gin.HandlerFunc {
+const apiKeyScheme = "ApiKey "
+
+type authOptions struct {
+	revocations    port_auth_revocation.RevocationStore
+	apiKeys        port_auth_usecase.APIKeyAuthenticator
+	firstPartyOnly bool
+}
+
+type Option func(*authOptions)
+
+func WithRevocationStore(store port_auth_revocation.RevocationStore) Option {
+	return func(o *authOptions) {
+		o.revocations = store
+	}
+}
+
+// WithAPIKeys also accepts "Authorization: ApiKey <key>", setting the same
+// context keys as a token with embedded permissions.
+func WithAPIKeys(authenticator port_auth_usecase.APIKeyAuthenticator) Option {
+	return func(o *authOptions) {
+		o.apiKeys = authenticator
+	}
+}
+
+// FirstPartyOnly rejects API keys and tokens issued to third-party OAuth
+// clients, for routes such as consent, MFA or API key management that they
+// must never reach even when their scopes would allow it.
+func FirstPartyOnly() Option {
+	return func(o *authOptions) {
+		o.firstPartyOnly = true
+	}
+}
+
+func AuthMiddleware(jwt port_auth_cryptography.TokenManager, opts ...Option) gin.HandlerFunc {
+	options := &authOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		if options.apiKeys != nil && strings.HasPrefix(authHeader, apiKeyScheme) {
+			authenticateAPIKey(c, options, authHeader[len(apiKeyScheme):])
+			return
+		}
 		if len(authHeader) < 7 || authHeader[:7] != "Bearer " {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid token"})
 			return
@@ -21,12 +69,53 @@ func AuthMiddleware(jwt port_auth_cryptography.TokenManager) gin.HandlerFunc {
 			return
 		}
 
+		jti, _ := claims["jti"].(string)
+		userID, _ := claims["user_id"].(string)
+		clientID, _ := claims["client_id"].(string)
+
+		if options.firstPartyOnly && clientID != "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "not available to third-party clients"})
+			return
+		}
+
+		sessionID, _ := claims["sid"].(string)
+
+		if options.revocations != nil {
+			revoked, err := options.revocations.IsRevoked(jti, userID, ClaimTime(claims, "iat"))
+			// Ending a session revokes its id, covering every token it issued.
+			if err == nil && !revoked && sessionID != "" {
+				revoked, err = options.revocations.IsRevoked(sessionID, "", time.Time{})
+			}
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "could not validate token"})
+				return
+			}
+			if revoked {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+				return
+			}
+		}
+
 		c.Set("userEmail", claims["email"])
 
 		if userID, ok := claims["user_id"]; ok {
 			c.Set("userID", userID)
 		}
 
+		if jti != "" {
+			c.Set("tokenID", jti)
+			c.Set("tokenExpiresAt", ClaimTime(claims, "exp"))
+		}
+
+		if sessionID != "" {
+			c.Set("sessionID", sessionID)
+		}
+
+		if clientID != "" {
+			c.Set("clientID", clientID)
+			c.Set("scope", claims["scope"])
+		}
+
 		if modules, ok := claims["modules"]; ok {
 			c.Set("modules", modules)
 		}
@@ -35,6 +124,78 @@ func AuthMiddleware(jwt port_auth_cryptography.TokenManager) gin.HandlerFunc {
 			c.Set("actions", actions)
 		}
 
+		if grants, ok := claims["grants"]; ok {
+			c.Set("grants", grants)
+		}
+
+		if version, ok := claimInt(claims, "perm_version"); ok {
+			c.Set("permVersion", version)
+		}
+
 		c.Next()
 	}
 }
+
+func authenticateAPIKey(c *gin.Context, options *authOptions, key string) {
+	if options.firstPartyOnly {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "not available to api keys"})
+		return
+	}
+
+	principal, err := options.apiKeys.Authenticate(key)
+	if err != nil {
+		if errors.Is(err, auth_entity.ErrInvalidAPIKey) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "could not validate api key"})
+		return
+	}
+
+	c.Set("userEmail", principal.Email)
+	c.Set("userID", principal.UserID)
+	c.Set("apiKeyID", principal.KeyID)
+	c.Set("modules", toInterfaces(principal.Modules))
+	c.Set("actions", toInterfaces(principal.Actions))
+	c.Set("grants", toInterfaces(principal.Grants))
+
+	c.Next()
+}
+
+// toInterfaces matches the shape of list claims decoded from a JWT, which is
+// what the permission middleware reads.
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		result = append(result, v)
+	}
+	return result
+}
+
+func claimInt(claims map[string]interface{}, key string) (int64, bool) {
+	switch v := claims[key].(type) {
+	case float64:
+		return int64(v), true
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// ClaimTime reads a NumericDate claim. Claims decoded from JSON carry numbers
+// as float64, while claims built in-process usually hold int64.
+func ClaimTime(claims map[string]interface{}, key string) time.Time {
+	switch v := claims[key].(type) {
+	case float64:
+		return time.Unix(int64(v), 0)
+	case int64:
+		return time.Unix(v, 0)
+	case int:
+		return time.Unix(int64(v), 0)
+	default:
+		return time.Time{}
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
 )
 
 type MockTokenManager struct {
@@ -28,6 +30,37 @@ func (m *MockTokenManager) Verify(token string) (map[string]interface{}, error)
 	return args.Get(0).(map[string]interface{}), args.Error(1)
 }
 
+type MockRevocationStore struct {
+	mock.Mock
+}
+
+func (m *MockRevocationStore) RevokeToken(jti, userID string, expiresAt time.Time) error {
+	args := m.Called(jti, userID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) RevokeAllForUser(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) IsRevoked(jti, userID string, issuedAt time.Time) (bool, error) {
+	args := m.Called(jti, userID, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockAPIKeyAuthenticator struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyAuthenticator) Authenticate(key string) (*auth_entity.APIKeyPrincipal, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_entity.APIKeyPrincipal), args.Error(1)
+}
+
 func TestAuthMiddleware_Success_WithAllClaims(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -330,3 +363,344 @@ func TestAuthMiddleware_WithActionsInClaims(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "update")
 	mockJWT.AssertExpectations(t)
 }
+
+func TestAuthMiddleware_RevokedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	mockStore := new(MockRevocationStore)
+	token := "revoked.jwt.token"
+	issuedAt := time.Now().Add(-time.Minute).Unix()
+
+	claims := map[string]interface{}{
+		"email":   "user@example.com",
+		"user_id": "user-123",
+		"jti":     "jti-1",
+		"iat":     float64(issuedAt),
+	}
+
+	mockJWT.On("Verify", token).Return(claims, nil)
+	mockStore.On("IsRevoked", "jti-1", "user-123", time.Unix(issuedAt, 0)).Return(true, nil)
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT, WithRevocationStore(mockStore)), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "token revoked")
+	mockStore.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_NotRevokedSetsTokenContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	mockStore := new(MockRevocationStore)
+	token := "valid.jwt.token"
+	expiresAt := time.Now().Add(time.Hour).Unix()
+
+	claims := map[string]interface{}{
+		"email":   "user@example.com",
+		"user_id": "user-123",
+		"jti":     "jti-1",
+		"exp":     float64(expiresAt),
+	}
+
+	mockJWT.On("Verify", token).Return(claims, nil)
+	mockStore.On("IsRevoked", "jti-1", "user-123", time.Time{}).Return(false, nil)
+
+	var capturedTokenID interface{}
+	var capturedExpiresAt interface{}
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT, WithRevocationStore(mockStore)), func(c *gin.Context) {
+		capturedTokenID, _ = c.Get("tokenID")
+		capturedExpiresAt, _ = c.Get("tokenExpiresAt")
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "jti-1", capturedTokenID)
+	assert.Equal(t, time.Unix(expiresAt, 0), capturedExpiresAt)
+	mockStore.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_RevocationStoreError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	mockStore := new(MockRevocationStore)
+	token := "valid.jwt.token"
+
+	claims := map[string]interface{}{
+		"email":   "user@example.com",
+		"user_id": "user-123",
+		"jti":     "jti-1",
+	}
+
+	mockJWT.On("Verify", token).Return(claims, nil)
+	mockStore.On("IsRevoked", "jti-1", "user-123", time.Time{}).Return(false, errors.New("db down"))
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT, WithRevocationStore(mockStore)), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "could not validate token")
+}
+
+func TestClaimTime(t *testing.T) {
+	claims := map[string]interface{}{
+		"float": float64(100),
+		"int64": int64(200),
+		"int":   300,
+		"bad":   "400",
+	}
+
+	assert.Equal(t, time.Unix(100, 0), ClaimTime(claims, "float"))
+	assert.Equal(t, time.Unix(200, 0), ClaimTime(claims, "int64"))
+	assert.Equal(t, time.Unix(300, 0), ClaimTime(claims, "int"))
+	assert.True(t, ClaimTime(claims, "bad").IsZero())
+	assert.True(t, ClaimTime(claims, "missing").IsZero())
+}
+
+func TestAuthMiddleware_SetsPermissionReferenceClaims(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	mockJWT.On("Verify", "valid.jwt.token").Return(map[string]interface{}{
+		"email":        "user@example.com",
+		"user_id":      "user-123",
+		"perm_version": float64(4),
+		"grants":       []interface{}{"users:read"},
+	}, nil)
+
+	var version, grants interface{}
+	var modulesExist bool
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
+		version, _ = c.Get("permVersion")
+		grants, _ = c.Get("grants")
+		_, modulesExist = c.Get("modules")
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer valid.jwt.token")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(4), version)
+	assert.Equal(t, []interface{}{"users:read"}, grants)
+	assert.False(t, modulesExist)
+}
+
+func TestAuthMiddleware_SetsClientContextForDelegatedTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	token := "oauth.jwt.token"
+
+	mockJWT.On("Verify", token).Return(map[string]interface{}{
+		"user_id":   "user-123",
+		"client_id": "client-1",
+		"scope":     "users:read",
+	}, nil)
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT), func(c *gin.Context) {
+		clientID, _ := c.Get("clientID")
+		scope, _ := c.Get("scope")
+		c.JSON(http.StatusOK, gin.H{"client_id": clientID, "scope": scope})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"client_id":"client-1","scope":"users:read"}`, w.Body.String())
+}
+
+func TestAuthMiddleware_FirstPartyOnlyRejectsDelegatedTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	mockJWT.On("Verify", "oauth.jwt.token").Return(map[string]interface{}{
+		"user_id":   "user-123",
+		"client_id": "client-1",
+	}, nil)
+	mockJWT.On("Verify", "first.party.token").Return(map[string]interface{}{
+		"user_id": "user-123",
+	}, nil)
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT, FirstPartyOnly()), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer oauth.jwt.token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer first.party.token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	apiKeys := new(MockAPIKeyAuthenticator)
+	apiKeys.On("Authenticate", "sek_secret").Return(&auth_entity.APIKeyPrincipal{
+		KeyID:   "key-1",
+		UserID:  "user-123",
+		Email:   "ci@example.com",
+		Modules: []string{"users"},
+		Actions: []string{"read"},
+		Grants:  []string{"users:read"},
+	}, nil)
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT, WithAPIKeys(apiKeys)), func(c *gin.Context) {
+		modules, _ := c.Get("modules")
+		actions, _ := c.Get("actions")
+		grants, _ := c.Get("grants")
+		assert.Equal(t, []interface{}{"users"}, modules)
+		assert.Equal(t, []interface{}{"read"}, actions)
+		assert.Equal(t, []interface{}{"users:read"}, grants)
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetString("userID"), "api_key_id": c.GetString("apiKeyID")})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "ApiKey sek_secret")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"user_id":"user-123","api_key_id":"key-1"}`, w.Body.String())
+	mockJWT.AssertNotCalled(t, "Verify", mock.Anything)
+}
+
+func TestAuthMiddleware_APIKeyFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := map[string]struct {
+		options []Option
+		err     error
+		status  int
+	}{
+		"invalid key":      {err: auth_entity.ErrInvalidAPIKey, status: http.StatusUnauthorized},
+		"lookup failure":   {err: errors.New("db down"), status: http.StatusServiceUnavailable},
+		"first party only": {options: []Option{FirstPartyOnly()}, status: http.StatusForbidden},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			apiKeys := new(MockAPIKeyAuthenticator)
+			apiKeys.On("Authenticate", "sek_secret").Return(nil, tc.err)
+
+			options := append([]Option{WithAPIKeys(apiKeys)}, tc.options...)
+
+			router := gin.New()
+			router.GET("/test", AuthMiddleware(new(MockTokenManager), options...), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("Authorization", "ApiKey sek_secret")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_APIKeyNotAcceptedWithoutOption(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(new(MockTokenManager)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "ApiKey sek_secret")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthMiddleware_EndedSessionRevokesToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockJWT := new(MockTokenManager)
+	mockStore := new(MockRevocationStore)
+
+	mockJWT.On("Verify", "ended.jwt.token").Return(map[string]interface{}{
+		"user_id": "user-123",
+		"jti":     "jti-1",
+		"sid":     "session-ended",
+	}, nil)
+	mockJWT.On("Verify", "active.jwt.token").Return(map[string]interface{}{
+		"user_id": "user-123",
+		"jti":     "jti-2",
+		"sid":     "session-active",
+	}, nil)
+	mockStore.On("IsRevoked", "jti-1", "user-123", time.Time{}).Return(false, nil)
+	mockStore.On("IsRevoked", "jti-2", "user-123", time.Time{}).Return(false, nil)
+	mockStore.On("IsRevoked", "session-ended", "", time.Time{}).Return(true, nil)
+	mockStore.On("IsRevoked", "session-active", "", time.Time{}).Return(false, nil)
+
+	router := gin.New()
+	router.GET("/test", AuthMiddleware(mockJWT, WithRevocationStore(mockStore)), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"session_id": c.GetString("sessionID")})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer ended.jwt.token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Authorization", "Bearer active.jwt.token")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"session_id":"session-active"}`, w.Body.String())
+}
@@ -1,30 +1,216 @@
 package auth_router
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	auth_service "github.com/williamkoller/system-education/internal/auth/application/service"
 	auth_usecase "github.com/williamkoller/system-education/internal/auth/application/usecase"
+	auth_entity "github.com/williamkoller/system-education/internal/auth/domain/entity"
 	infra_cryptography "github.com/williamkoller/system-education/internal/auth/infra/cryptography"
+	auth_repository "github.com/williamkoller/system-education/internal/auth/infra/db/repository"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_oidc "github.com/williamkoller/system-education/internal/auth/port/oidc"
+	port_auth_permission "github.com/williamkoller/system-education/internal/auth/port/permission"
+	port_auth_revocation "github.com/williamkoller/system-education/internal/auth/port/revocation"
+	port_auth_throttle "github.com/williamkoller/system-education/internal/auth/port/throttle"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 	auth_handler "github.com/williamkoller/system-education/internal/auth/presentation/handler"
 	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
-	permission_repository "github.com/williamkoller/system-education/internal/permission/infra/db/repository"
-	user_cryptography "github.com/williamkoller/system-education/internal/user/infra/cryptography"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+	user_event "github.com/williamkoller/system-education/internal/user/domain/event"
 	user_repository "github.com/williamkoller/system-education/internal/user/infra/db/repository"
+	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
+	port_email_notifier "github.com/williamkoller/system-education/internal/user/port/email"
+	port_user_service "github.com/williamkoller/system-education/internal/user/port/service"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	"github.com/williamkoller/system-education/shared/infra/outbox"
 	"gorm.io/gorm"
 )
 
-func AuthRouter(r *gin.Engine, db *gorm.DB, secret string, expiresIn time.Duration) {
-	repository := user_repository.NewUserGormRepository(db)
-	permissionRepo := permission_repository.NewPermissionGormRepository(db)
-	jwt := infra_cryptography.NewJWTTokenManager(secret, expiresIn)
-	crypto := user_cryptography.NewBcryptHasher(12)
+// Dependencies wires the auth routes. Everything the routes build
+// themselves, such as repositories over DB, is left out.
+type Dependencies struct {
+	DB             *gorm.DB
+	TokenManager   port_auth_cryptography.TokenManager
+	Revocations    port_auth_revocation.RevocationStore
+	Permissions    port_permission_service.PermissionService
+	PermissionMode auth_entity.PermissionMode
+	AccessControl  port_permission_middleware.PermissionMiddleware
+	APIKeys        port_auth_usecase.APIKeyUsecase
+	Events         *shared_event.Dispatcher
 
-	usecase := auth_usecase.NewAuthUsecase(repository, permissionRepo, jwt, crypto)
+	AccessExpiresIn  time.Duration
+	RefreshExpiresIn time.Duration
+
+	MFAIssuer             string
+	MFARequiredModules    []string
+	MFAChallengeExpiresIn time.Duration
+
+	LoginAttempts port_auth_throttle.LoginAttemptStore
+	Lockout       auth_entity.LockoutPolicy
+
+	PasswordHasher           port_cryptography.Bcrypt
+	PasswordPolicy           port_user_service.PasswordPolicy
+	Notifier                 port_email_notifier.EmailNotifier
+	PublicURL                string
+	ResetExpiresIn           time.Duration
+	VerificationExpiresIn    time.Duration
+	RequireEmailVerification bool
+
+	IdentityProviders  []port_auth_oidc.IdentityProvider
+	PermissionGranter  port_auth_permission.PermissionGranter
+	OIDCStateExpiresIn time.Duration
+
+	OAuthCodeExpiresIn time.Duration
+}
+
+func AuthRouter(r *gin.Engine, deps Dependencies) {
+	repository := user_repository.NewUserGormRepository(deps.DB)
+	refreshRepo := auth_repository.NewRefreshTokenGormRepository(deps.DB)
+	tokenGenerator := infra_cryptography.NewSecureTokenGenerator(32)
+	authenticate := auth_middleware.AuthMiddleware(deps.TokenManager, auth_middleware.WithRevocationStore(deps.Revocations), auth_middleware.WithAPIKeys(deps.APIKeys))
+	firstParty := auth_middleware.AuthMiddleware(deps.TokenManager, auth_middleware.WithRevocationStore(deps.Revocations), auth_middleware.WithAPIKeys(deps.APIKeys), auth_middleware.FirstPartyOnly())
+	mfaRepo := auth_repository.NewMFAGormRepository(deps.DB)
+	sessions := auth_repository.NewSessionGormRepository(deps.DB)
+
+	mfa := auth_service.NewMFAService(
+		mfaRepo,
+		auth_repository.NewMFAChallengeGormRepository(deps.DB),
+		infra_cryptography.NewTOTP(deps.MFAIssuer),
+		infra_cryptography.NewRecoveryCodeGenerator(),
+		tokenGenerator,
+		deps.Permissions,
+		deps.MFARequiredModules,
+		deps.MFAChallengeExpiresIn,
+	)
+
+	throttle := auth_service.NewLoginThrottle(deps.LoginAttempts, deps.Lockout, auth_entity.DefaultIPLockoutPolicy(), deps.Events)
+
+	options := []auth_usecase.Option{
+		auth_usecase.WithMFA(mfa),
+		auth_usecase.WithLoginThrottle(throttle),
+		auth_usecase.WithSessions(sessions),
+	}
+	if deps.RequireEmailVerification {
+		options = append(options, auth_usecase.WithEmailVerification())
+	}
+
+	usecase := auth_usecase.NewAuthUsecase(auth_usecase.AuthDependencies{
+		Users:            repository,
+		Permissions:      deps.Permissions,
+		TokenManager:     deps.TokenManager,
+		PasswordHasher:   deps.PasswordHasher,
+		RefreshTokens:    refreshRepo,
+		TokenGenerator:   tokenGenerator,
+		Revocations:      deps.Revocations,
+		Events:           deps.Events,
+		AccessExpiresIn:  deps.AccessExpiresIn,
+		RefreshExpiresIn: deps.RefreshExpiresIn,
+		PermissionMode:   deps.PermissionMode,
+	}, options...)
 	handler := auth_handler.NewAuthHandler(usecase)
+
+	profile := auth_usecase.NewProfileUsecase(repository, deps.Permissions, sessions, mfaRepo, deps.RefreshExpiresIn)
+	profileHandler := auth_handler.NewProfileHandler(profile)
+
+	account := auth_usecase.NewAccountUsecase(repository, deps.PasswordHasher, deps.PasswordPolicy, auth_repository.NewOneTimeTokenGormRepository(deps.DB), tokenGenerator, deps.Notifier, deps.Revocations, refreshRepo, deps.PublicURL, deps.ResetExpiresIn, deps.VerificationExpiresIn,
+		auth_usecase.WithAccountSessions(sessions),
+		auth_usecase.WithAccountLoginThrottle(throttle),
+	)
+	accountHandler := auth_handler.NewAccountHandler(account)
+
+	oidc := auth_usecase.NewOIDCUsecase(deps.IdentityProviders, auth_repository.NewOIDCStateGormRepository(deps.DB), auth_repository.NewExternalIdentityGormRepository(deps.DB), repository, deps.PasswordHasher, tokenGenerator, deps.PermissionGranter, deps.Events, usecase, deps.OIDCStateExpiresIn)
+	oidcHandler := auth_handler.NewOIDCHandler(oidc)
+
+	oauth := auth_usecase.NewOAuthUsecase(
+		auth_repository.NewOAuthClientGormRepository(deps.DB),
+		auth_repository.NewOAuthCodeGormRepository(deps.DB),
+		auth_repository.NewOAuthConsentGormRepository(deps.DB),
+		deps.Permissions,
+		deps.TokenManager,
+		tokenGenerator,
+		deps.Revocations,
+		deps.AccessExpiresIn,
+		deps.OAuthCodeExpiresIn,
+	)
+	oauthHandler := auth_handler.NewOAuthHandler(oauth)
+
+	apiKeyHandler := auth_handler.NewAPIKeyHandler(deps.APIKeys)
+
+	err := shared_event.Subscribe(deps.Events, func(ctx context.Context, evt *user_event.UserCreatedEvent) error {
+		if err := account.SendEmailVerification(evt.UserID); err != nil {
+			return fmt.Errorf("falha ao enviar verificação de e-mail: %w", err)
+		}
+		return nil
+	}, shared_event.Deduplicate(outbox.NewProcessedGormStore(deps.DB), "email_verification"))
+	if err != nil {
+		log.Fatalf("Falha ao registrar handler de user.created: %v", err)
+	}
+
+	// An address set by an operator has not been proven, so its owner is
+	// asked to verify it.
+	err = shared_event.Subscribe(deps.Events, func(ctx context.Context, evt *user_event.UserEmailChangedEvent) error {
+		if evt.Verified {
+			return nil
+		}
+		if err := account.SendEmailVerification(evt.UserID); err != nil {
+			return fmt.Errorf("falha ao enviar verificação do novo e-mail: %w", err)
+		}
+		return nil
+	}, shared_event.Deduplicate(outbox.NewProcessedGormStore(deps.DB), "email_change_verification"))
+	if err != nil {
+		log.Fatalf("Falha ao registrar handler de user.email_changed: %v", err)
+	}
+
 	auth := r.Group("auth")
 	{
 		auth.POST("login", handler.Login)
-		auth.POST("profile", auth_middleware.AuthMiddleware(jwt), handler.Profile)
+		auth.POST("refresh", handler.Refresh)
+		auth.POST("mfa/verify", handler.VerifyMFA)
+		auth.POST("password/forgot", accountHandler.ForgotPassword)
+		auth.POST("password/reset", accountHandler.ResetPassword)
+		auth.POST("email/verify", accountHandler.VerifyEmail)
+		auth.POST("email/verify/resend", accountHandler.ResendEmailVerification)
+		auth.POST("email/change/confirm", accountHandler.ConfirmEmailChange)
+		auth.GET("oidc/:provider/login", oidcHandler.Login)
+		auth.GET("oidc/:provider/callback", oidcHandler.Callback)
+		auth.POST("mfa/enroll", firstParty, handler.EnrollMFA)
+		auth.POST("mfa/confirm", firstParty, handler.ConfirmMFA)
+		auth.POST("logout", firstParty, handler.Logout)
+		auth.GET("me", authenticate, profileHandler.Me)
+		auth.PATCH("me", firstParty, profileHandler.UpdateMe)
+		auth.POST("email/change", firstParty, accountHandler.RequestEmailChange)
+		auth.GET("sessions", firstParty, handler.Sessions)
+		auth.DELETE("sessions/:id", firstParty, handler.EndSession)
+		auth.POST("users/:id/revoke-sessions", authenticate,
+			deps.AccessControl.ModuleAccessMiddleware([]string{"users"}, []string{"revoke"}), handler.RevokeUserSessions)
+		auth.POST("users/:id/unlock", authenticate,
+			deps.AccessControl.ModuleAccessMiddleware([]string{"users"}, []string{"update"}), handler.UnlockAccount)
+		auth.POST("api-keys", firstParty, apiKeyHandler.Create)
+		auth.GET("api-keys", firstParty, apiKeyHandler.List)
+		auth.DELETE("api-keys/:id", firstParty, apiKeyHandler.Revoke)
+		auth.POST("users/:id/revoke-api-keys", authenticate,
+			deps.AccessControl.ModuleAccessMiddleware([]string{"users"}, []string{"revoke"}), apiKeyHandler.RevokeUserKeys)
+	}
+
+	oauthGroup := r.Group("oauth")
+	{
+		oauthGroup.POST("token", oauthHandler.Token)
+		oauthGroup.POST("introspect", oauthHandler.Introspect)
+		oauthGroup.GET("authorize", firstParty, oauthHandler.Authorize)
+		oauthGroup.POST("authorize", firstParty, oauthHandler.Decide)
+		oauthGroup.GET("consents", firstParty, oauthHandler.Consents)
+		oauthGroup.DELETE("consents/:client_id", firstParty, oauthHandler.RevokeConsent)
+		oauthGroup.POST("clients", firstParty,
+			deps.AccessControl.ModuleAccessMiddleware([]string{"permissions"}, []string{"create"}), oauthHandler.RegisterClient)
+	}
+
+	if provider, ok := deps.TokenManager.(port_auth_cryptography.KeySetProvider); ok {
+		r.GET("/.well-known/jwks.json", auth_handler.NewJWKSHandler(provider).JWKS)
 	}
 }
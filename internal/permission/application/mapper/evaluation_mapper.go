@@ -0,0 +1,17 @@
+package permission_mapper
+
+import permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+
+type EvaluationResponse struct {
+	Policy      string                        `json:"policy,omitempty"`
+	Allowed     bool                          `json:"allowed"`
+	Explanation permission_policy.Explanation `json:"explanation"`
+}
+
+func ToEvaluation(policy string, e *permission_policy.Explanation) *EvaluationResponse {
+	return &EvaluationResponse{
+		Policy:      policy,
+		Allowed:     e.Allowed,
+		Explanation: *e,
+	}
+}
@@ -0,0 +1,50 @@
+package permission_service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+)
+
+var (
+	AdministratorModules = []string{"users", "permissions", "roles"}
+	AdministratorActions = []string{"create", "read", "update", "delete", "admin", "assign", "revoke"}
+)
+
+// AdministratorGranter writes the permission held by the first administrator.
+// It bypasses the escalation guard on purpose: there is no one to grant from
+// yet.
+type AdministratorGranter struct {
+	repo        port_permission_repository.PermissionRepository
+	permissions port_permission_service.PermissionService
+}
+
+func NewAdministratorGranter(repo port_permission_repository.PermissionRepository, permissions port_permission_service.PermissionService) *AdministratorGranter {
+	return &AdministratorGranter{repo: repo, permissions: permissions}
+}
+
+func (g *AdministratorGranter) GrantAdministrator(userID string) error {
+	permission, err := permission_entity.NewPermission(&permission_entity.Permission{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Modules:     AdministratorModules,
+		Actions:     AdministratorActions,
+		Level:       string(permission_entity.LevelAllowed),
+		Description: "administrator",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create permission: %w", err)
+	}
+
+	if _, err := g.repo.Save(permission); err != nil {
+		return fmt.Errorf("failed to save permission: %w", err)
+	}
+
+	if err := g.permissions.Invalidate(userID); err != nil {
+		return fmt.Errorf("failed to invalidate permissions: %w", err)
+	}
+	return nil
+}
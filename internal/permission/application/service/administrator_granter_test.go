@@ -0,0 +1,80 @@
+package permission_service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+type MockPermissionService struct {
+	mock.Mock
+}
+
+func (m *MockPermissionService) Resolve(userID string, minVersion int64) (*permission_entity.EffectivePermissions, error) {
+	args := m.Called(userID, minVersion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Refresh(userID string) (*permission_entity.EffectivePermissions, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Invalidate(userID string) error {
+	return m.Called(userID).Error(0)
+}
+
+func TestGrantAdministrator(t *testing.T) {
+	repo := new(MockPermissionRepository)
+	permissions := new(MockPermissionService)
+	repo.On("Save", mock.MatchedBy(func(p *permission_entity.Permission) bool {
+		return p.UserID == "user-1" && p.Level == "allowed" &&
+			permission_entity.Evaluate(p.Grants(), []string{"permissions"}, []string{"create"}).Allowed &&
+			permission_entity.Evaluate(p.Grants(), []string{"roles"}, []string{"assign"}).Allowed &&
+			permission_entity.Evaluate(p.Grants(), []string{"users"}, []string{"admin"}).Allowed
+	})).Return(&permission_entity.Permission{ID: "perm-1"}, nil)
+	permissions.On("Invalidate", "user-1").Return(nil)
+
+	err := NewAdministratorGranter(repo, permissions).GrantAdministrator("user-1")
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	permissions.AssertExpectations(t)
+}
+
+func TestGrantAdministrator_Errors(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		err := NewAdministratorGranter(new(MockPermissionRepository), new(MockPermissionService)).GrantAdministrator("")
+
+		assert.ErrorContains(t, err, "failed to create permission")
+	})
+
+	t.Run("save", func(t *testing.T) {
+		repo := new(MockPermissionRepository)
+		repo.On("Save", mock.Anything).Return(nil, errors.New("db error"))
+
+		err := NewAdministratorGranter(repo, new(MockPermissionService)).GrantAdministrator("user-1")
+
+		assert.EqualError(t, err, "failed to save permission: db error")
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		repo := new(MockPermissionRepository)
+		permissions := new(MockPermissionService)
+		repo.On("Save", mock.Anything).Return(&permission_entity.Permission{ID: "perm-1"}, nil)
+		permissions.On("Invalidate", "user-1").Return(errors.New("db error"))
+
+		err := NewAdministratorGranter(repo, permissions).GrantAdministrator("user-1")
+
+		assert.EqualError(t, err, "failed to invalidate permissions: db error")
+	})
+}
@@ -0,0 +1,52 @@
+package permission_service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+)
+
+// DefaultGranter writes the permission every provisioned user starts with.
+// Like AdministratorGranter it bypasses the escalation guard: the grant is
+// made by the system, not by another user.
+type DefaultGranter struct {
+	repo        port_permission_repository.PermissionRepository
+	permissions port_permission_service.PermissionService
+	modules     []string
+	actions     []string
+}
+
+func NewDefaultGranter(repo port_permission_repository.PermissionRepository, permissions port_permission_service.PermissionService, modules, actions []string) *DefaultGranter {
+	return &DefaultGranter{repo: repo, permissions: permissions, modules: modules, actions: actions}
+}
+
+// GrantDefault does nothing when no default modules are configured.
+func (g *DefaultGranter) GrantDefault(userID string) error {
+	if len(g.modules) == 0 || len(g.actions) == 0 {
+		return nil
+	}
+
+	permission, err := permission_entity.NewPermission(&permission_entity.Permission{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Modules:     g.modules,
+		Actions:     g.actions,
+		Level:       string(permission_entity.LevelAllowed),
+		Description: "default",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create permission: %w", err)
+	}
+
+	if _, err := g.repo.Save(permission); err != nil {
+		return fmt.Errorf("failed to save permission: %w", err)
+	}
+
+	if err := g.permissions.Invalidate(userID); err != nil {
+		return fmt.Errorf("failed to invalidate permissions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package permission_service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+func TestGrantDefault(t *testing.T) {
+	repo := new(MockPermissionRepository)
+	permissions := new(MockPermissionService)
+	repo.On("Save", mock.MatchedBy(func(p *permission_entity.Permission) bool {
+		return p.UserID == "user-1" && p.Level == "allowed" &&
+			permission_entity.Evaluate(p.Grants(), []string{"users"}, []string{"read"}).Allowed &&
+			!permission_entity.Evaluate(p.Grants(), []string{"users"}, []string{"delete"}).Allowed
+	})).Return(&permission_entity.Permission{ID: "perm-1"}, nil)
+	permissions.On("Invalidate", "user-1").Return(nil)
+
+	err := NewDefaultGranter(repo, permissions, []string{"users"}, []string{"read"}).GrantDefault("user-1")
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	permissions.AssertExpectations(t)
+}
+
+func TestGrantDefault_NothingConfigured(t *testing.T) {
+	repo := new(MockPermissionRepository)
+
+	err := NewDefaultGranter(repo, new(MockPermissionService), nil, []string{"read"}).GrantDefault("user-1")
+
+	assert.NoError(t, err)
+	repo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestGrantDefault_SaveError(t *testing.T) {
+	repo := new(MockPermissionRepository)
+	repo.On("Save", mock.Anything).Return(nil, errors.New("db error"))
+
+	err := NewDefaultGranter(repo, new(MockPermissionService), []string{"users"}, []string{"read"}).GrantDefault("user-1")
+
+	assert.EqualError(t, err, "failed to save permission: db error")
+}
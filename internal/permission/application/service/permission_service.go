@@ -0,0 +1,105 @@
+package permission_service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+)
+
+type cacheEntry struct {
+	permissions *permission_entity.EffectivePermissions
+	loadedAt    time.Time
+}
+
+// CachedPermissionService resolves effective permissions for tokens that
+// carry them by reference. Entries are dropped on Invalidate, reloaded when a
+// token presents a newer version than the cached one, and otherwise expire
+// after ttl so changes made on other instances are eventually observed.
+type CachedPermissionService struct {
+	repo     port_permission_repository.PermissionRepository
+	versions port_permission_repository.PermissionVersionRepository
+	sources  []port_permission_service.PermissionSource
+	ttl      time.Duration
+	now      func() time.Time
+	mu       sync.RWMutex
+	entries  map[string]cacheEntry
+}
+
+var _ port_permission_service.PermissionService = &CachedPermissionService{}
+
+func NewCachedPermissionService(
+	repo port_permission_repository.PermissionRepository,
+	versions port_permission_repository.PermissionVersionRepository,
+	ttl time.Duration,
+	sources ...port_permission_service.PermissionSource,
+) *CachedPermissionService {
+	return &CachedPermissionService{
+		repo:     repo,
+		versions: versions,
+		sources:  sources,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]cacheEntry),
+	}
+}
+
+func (s *CachedPermissionService) Resolve(userID string, minVersion int64) (*permission_entity.EffectivePermissions, error) {
+	now := s.now()
+
+	s.mu.RLock()
+	entry, ok := s.entries[userID]
+	s.mu.RUnlock()
+
+	if ok && entry.permissions.Version >= minVersion && now.Sub(entry.loadedAt) < s.ttl {
+		return entry.permissions, nil
+	}
+
+	return s.Refresh(userID)
+}
+
+func (s *CachedPermissionService) Refresh(userID string) (*permission_entity.EffectivePermissions, error) {
+	now := s.now()
+
+	// The version is read before the grants so a concurrent change can only
+	// make the cached copy look older than it is, never newer.
+	version, err := s.versions.Current(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find permission version: %w", err)
+	}
+
+	permissions, err := s.repo.FindPermissionByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find permission by user id: %w", err)
+	}
+
+	for _, source := range s.sources {
+		inherited, err := source.PermissionsForUser(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find inherited permissions: %w", err)
+		}
+		permissions = append(permissions, inherited...)
+	}
+
+	effective := permission_entity.NewEffectivePermissions(userID, version, permissions)
+
+	s.mu.Lock()
+	s.entries[userID] = cacheEntry{permissions: effective, loadedAt: now}
+	s.mu.Unlock()
+
+	return effective, nil
+}
+
+func (s *CachedPermissionService) Invalidate(userID string) error {
+	s.mu.Lock()
+	delete(s.entries, userID)
+	s.mu.Unlock()
+
+	if _, err := s.versions.Increment(userID); err != nil {
+		return fmt.Errorf("failed to increment permission version: %w", err)
+	}
+	return nil
+}
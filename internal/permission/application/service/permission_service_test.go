@@ -0,0 +1,272 @@
+package permission_service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+type MockPermissionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPermissionRepository) Save(p *permission_entity.Permission) (*permission_entity.Permission, error) {
+	args := m.Called(p)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) FindAll() ([]*permission_entity.Permission, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*permission_entity.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) FindPermissionByUserID(userID string) ([]*permission_entity.Permission, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*permission_entity.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) Update(id string, p *permission_entity.Permission) (*permission_entity.Permission, error) {
+	args := m.Called(id, p)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) Delete(p *permission_entity.Permission) error {
+	args := m.Called(p)
+	return args.Error(0)
+}
+
+func (m *MockPermissionRepository) FindByID(id string) (*permission_entity.Permission, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.Permission), args.Error(1)
+}
+
+type MockPermissionVersionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPermissionVersionRepository) Current(userID string) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPermissionVersionRepository) Increment(userID string) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newTestService(now *time.Time) (*CachedPermissionService, *MockPermissionRepository, *MockPermissionVersionRepository) {
+	repo := new(MockPermissionRepository)
+	versions := new(MockPermissionVersionRepository)
+	service := NewCachedPermissionService(repo, versions, 30*time.Second)
+	service.now = func() time.Time { return *now }
+	return service, repo, versions
+}
+
+var userPermissions = []*permission_entity.Permission{
+	{ID: "p-1", UserID: "user-1", Modules: []string{"users"}, Actions: []string{"read"}, Level: "allowed"},
+}
+
+func TestResolve_ServedFromCache(t *testing.T) {
+	now := time.Now()
+	service, repo, versions := newTestService(&now)
+
+	versions.On("Current", "user-1").Return(int64(2), nil).Once()
+	repo.On("FindPermissionByUserID", "user-1").Return(userPermissions, nil).Once()
+
+	first, err := service.Resolve("user-1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), first.Version)
+	assert.Equal(t, []string{"users"}, first.Modules())
+
+	now = now.Add(10 * time.Second)
+	second, err := service.Resolve("user-1", 1)
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+
+	repo.AssertExpectations(t)
+	versions.AssertExpectations(t)
+}
+
+func TestResolve_ReloadsWhenTokenIsNewer(t *testing.T) {
+	now := time.Now()
+	service, repo, versions := newTestService(&now)
+
+	versions.On("Current", "user-1").Return(int64(1), nil).Once()
+	versions.On("Current", "user-1").Return(int64(2), nil).Once()
+	repo.On("FindPermissionByUserID", "user-1").Return(userPermissions, nil).Twice()
+
+	_, err := service.Resolve("user-1", 1)
+	assert.NoError(t, err)
+
+	effective, err := service.Resolve("user-1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), effective.Version)
+
+	repo.AssertExpectations(t)
+	versions.AssertExpectations(t)
+}
+
+func TestResolve_ReloadsAfterTTL(t *testing.T) {
+	now := time.Now()
+	service, repo, versions := newTestService(&now)
+
+	versions.On("Current", "user-1").Return(int64(0), nil).Twice()
+	repo.On("FindPermissionByUserID", "user-1").Return(userPermissions, nil).Twice()
+
+	_, err := service.Resolve("user-1", 0)
+	assert.NoError(t, err)
+
+	now = now.Add(31 * time.Second)
+	_, err = service.Resolve("user-1", 0)
+	assert.NoError(t, err)
+
+	repo.AssertExpectations(t)
+	versions.AssertExpectations(t)
+}
+
+func TestResolve_Errors(t *testing.T) {
+	now := time.Now()
+
+	t.Run("version lookup fails", func(t *testing.T) {
+		service, _, versions := newTestService(&now)
+		versions.On("Current", "user-1").Return(int64(0), errors.New("db error"))
+
+		effective, err := service.Resolve("user-1", 0)
+
+		assert.Nil(t, effective)
+		assert.ErrorContains(t, err, "failed to find permission version")
+	})
+
+	t.Run("permission lookup fails", func(t *testing.T) {
+		service, repo, versions := newTestService(&now)
+		versions.On("Current", "user-1").Return(int64(0), nil)
+		repo.On("FindPermissionByUserID", "user-1").Return(nil, errors.New("db error"))
+
+		effective, err := service.Resolve("user-1", 0)
+
+		assert.Nil(t, effective)
+		assert.ErrorContains(t, err, "failed to find permission by user id")
+	})
+}
+
+func TestInvalidate_DropsCacheAndBumpsVersion(t *testing.T) {
+	now := time.Now()
+	service, repo, versions := newTestService(&now)
+
+	versions.On("Current", "user-1").Return(int64(0), nil).Once()
+	versions.On("Increment", "user-1").Return(int64(1), nil).Once()
+	versions.On("Current", "user-1").Return(int64(1), nil).Once()
+	repo.On("FindPermissionByUserID", "user-1").Return(userPermissions, nil).Twice()
+
+	_, err := service.Resolve("user-1", 0)
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.Invalidate("user-1"))
+
+	effective, err := service.Resolve("user-1", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), effective.Version)
+
+	repo.AssertExpectations(t)
+	versions.AssertExpectations(t)
+}
+
+func TestInvalidate_Error(t *testing.T) {
+	now := time.Now()
+	service, _, versions := newTestService(&now)
+	versions.On("Increment", "user-1").Return(int64(0), errors.New("db error"))
+
+	err := service.Invalidate("user-1")
+
+	assert.ErrorContains(t, err, "failed to increment permission version")
+}
+
+func TestRefresh_BypassesCache(t *testing.T) {
+	now := time.Now()
+	service, repo, versions := newTestService(&now)
+
+	versions.On("Current", "user-1").Return(int64(1), nil).Once()
+	versions.On("Current", "user-1").Return(int64(2), nil).Once()
+	repo.On("FindPermissionByUserID", "user-1").Return(userPermissions, nil).Twice()
+
+	_, err := service.Resolve("user-1", 0)
+	assert.NoError(t, err)
+
+	refreshed, err := service.Refresh("user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), refreshed.Version)
+
+	cached, err := service.Resolve("user-1", 2)
+	assert.NoError(t, err)
+	assert.Same(t, refreshed, cached)
+
+	repo.AssertExpectations(t)
+	versions.AssertExpectations(t)
+}
+
+type MockPermissionSource struct {
+	mock.Mock
+}
+
+func (m *MockPermissionSource) PermissionsForUser(userID string) ([]*permission_entity.Permission, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*permission_entity.Permission), args.Error(1)
+}
+
+func TestResolve_MergesSources(t *testing.T) {
+	repo := new(MockPermissionRepository)
+	versions := new(MockPermissionVersionRepository)
+	source := new(MockPermissionSource)
+	service := NewCachedPermissionService(repo, versions, 30*time.Second, source)
+
+	inherited := []*permission_entity.Permission{
+		{ID: "role-1", UserID: "user-1", Modules: []string{"finance"}, Actions: []string{"read"}, Level: "allowed"},
+	}
+
+	versions.On("Current", "user-1").Return(int64(0), nil)
+	repo.On("FindPermissionByUserID", "user-1").Return(userPermissions, nil)
+	source.On("PermissionsForUser", "user-1").Return(inherited, nil)
+
+	effective, err := service.Resolve("user-1", 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"users", "finance"}, effective.Modules())
+}
+
+func TestResolve_SourceError(t *testing.T) {
+	repo := new(MockPermissionRepository)
+	versions := new(MockPermissionVersionRepository)
+	source := new(MockPermissionSource)
+	service := NewCachedPermissionService(repo, versions, 30*time.Second, source)
+
+	versions.On("Current", "user-1").Return(int64(0), nil)
+	repo.On("FindPermissionByUserID", "user-1").Return(userPermissions, nil)
+	source.On("PermissionsForUser", "user-1").Return(nil, errors.New("db error"))
+
+	effective, err := service.Resolve("user-1", 0)
+
+	assert.Nil(t, effective)
+	assert.ErrorContains(t, err, "failed to find inherited permissions")
+}
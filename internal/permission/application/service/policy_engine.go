@@ -0,0 +1,81 @@
+package permission_service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+)
+
+// CachedPolicyEngine keeps the policies from repo in memory and reloads them
+// after ttl, so edits to the policy file or table are picked up without a
+// restart. A set that fails validation is rejected as a whole.
+type CachedPolicyEngine struct {
+	repo     port_permission_repository.PolicyRepository
+	ttl      time.Duration
+	now      func() time.Time
+	mu       sync.RWMutex
+	policies map[string]*permission_policy.Policy
+	loadedAt time.Time
+}
+
+var _ port_permission_service.PolicyEngine = &CachedPolicyEngine{}
+
+func NewCachedPolicyEngine(repo port_permission_repository.PolicyRepository, ttl time.Duration) *CachedPolicyEngine {
+	return &CachedPolicyEngine{
+		repo: repo,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+func (e *CachedPolicyEngine) Evaluate(name string, req permission_policy.Request) (permission_policy.Explanation, error) {
+	policies, err := e.load()
+	if err != nil {
+		return permission_policy.Explanation{}, err
+	}
+
+	policy, ok := policies[name]
+	if !ok {
+		return permission_policy.Explanation{}, permission_policy.ErrNotFound
+	}
+
+	return policy.Evaluate(req), nil
+}
+
+func (e *CachedPolicyEngine) load() (map[string]*permission_policy.Policy, error) {
+	now := e.now()
+
+	e.mu.RLock()
+	policies, loadedAt := e.policies, e.loadedAt
+	e.mu.RUnlock()
+
+	if policies != nil && now.Sub(loadedAt) < e.ttl {
+		return policies, nil
+	}
+
+	found, err := e.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies: %w", err)
+	}
+
+	policies = make(map[string]*permission_policy.Policy, len(found))
+	for _, policy := range found {
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to load policies: %w", err)
+		}
+		if _, exists := policies[policy.Name]; exists {
+			return nil, fmt.Errorf("failed to load policies: duplicate policy %s", policy.Name)
+		}
+		policies[policy.Name] = policy
+	}
+
+	e.mu.Lock()
+	e.policies, e.loadedAt = policies, now
+	e.mu.Unlock()
+
+	return policies, nil
+}
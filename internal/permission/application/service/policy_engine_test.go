@@ -0,0 +1,98 @@
+package permission_service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+)
+
+type MockPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockPolicyRepository) FindAll() ([]*permission_policy.Policy, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*permission_policy.Policy), args.Error(1)
+}
+
+func usersRead() *permission_policy.Policy {
+	return &permission_policy.Policy{
+		Name: "users.read",
+		Rule: permission_policy.RequireAccess([]string{"users"}, []string{"read"}),
+	}
+}
+
+func TestPolicyEngine_Evaluate(t *testing.T) {
+	repo := new(MockPolicyRepository)
+	repo.On("FindAll").Return([]*permission_policy.Policy{usersRead()}, nil).Once()
+	engine := NewCachedPolicyEngine(repo, time.Minute)
+
+	explanation, err := engine.Evaluate("users.read", permission_policy.Request{
+		Grants: []permission_entity.Grant{permission_entity.NewGrant("users", "read", permission_entity.LevelAllowed)},
+	})
+	assert.NoError(t, err)
+	assert.True(t, explanation.Allowed)
+
+	explanation, err = engine.Evaluate("users.read", permission_policy.Request{})
+	assert.NoError(t, err)
+	assert.False(t, explanation.Allowed)
+
+	_, err = engine.Evaluate("users.delete", permission_policy.Request{})
+	assert.ErrorIs(t, err, permission_policy.ErrNotFound)
+
+	repo.AssertExpectations(t)
+}
+
+func TestPolicyEngine_ReloadsAfterTTL(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+	repo := new(MockPolicyRepository)
+	repo.On("FindAll").Return([]*permission_policy.Policy{}, nil).Once()
+	repo.On("FindAll").Return([]*permission_policy.Policy{usersRead()}, nil).Once()
+	engine := NewCachedPolicyEngine(repo, time.Minute)
+	engine.now = func() time.Time { return now }
+
+	_, err := engine.Evaluate("users.read", permission_policy.Request{})
+	assert.ErrorIs(t, err, permission_policy.ErrNotFound)
+
+	now = now.Add(time.Minute)
+	_, err = engine.Evaluate("users.read", permission_policy.Request{})
+	assert.NoError(t, err)
+
+	repo.AssertExpectations(t)
+}
+
+func TestPolicyEngine_LoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []*permission_policy.Policy
+		err      error
+		expected string
+	}{
+		{name: "repository error", err: errors.New("db error"), expected: "failed to load policies: db error"},
+		{name: "invalid policy", policies: []*permission_policy.Policy{{Name: "broken"}}, expected: "policy broken"},
+		{name: "duplicate policy", policies: []*permission_policy.Policy{usersRead(), usersRead()}, expected: "duplicate policy users.read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockPolicyRepository)
+			if tt.err != nil {
+				repo.On("FindAll").Return(nil, tt.err)
+			} else {
+				repo.On("FindAll").Return(tt.policies, nil)
+			}
+
+			_, err := NewCachedPolicyEngine(repo, time.Minute).Evaluate("users.read", permission_policy.Request{})
+
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
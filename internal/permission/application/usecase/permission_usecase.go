@@ -5,24 +5,30 @@ import (
 
 	"github.com/google/uuid"
 	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_event "github.com/williamkoller/system-education/internal/permission/port/event"
 	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
 	port_permission_usecase "github.com/williamkoller/system-education/internal/permission/port/usecase"
 	permission_dtos "github.com/williamkoller/system-education/internal/permission/presentation/dtos"
 )
 
 type PermissionUsecase struct {
 	permissionRepository port_permission_repository.PermissionRepository
+	permissions          port_permission_service.PermissionService
+	event                port_permission_event.Dispatcher
 }
 
-func NewPermissionUsecase(permissionRepository port_permission_repository.PermissionRepository) *PermissionUsecase {
+func NewPermissionUsecase(permissionRepository port_permission_repository.PermissionRepository, permissions port_permission_service.PermissionService, event port_permission_event.Dispatcher) *PermissionUsecase {
 	return &PermissionUsecase{
 		permissionRepository: permissionRepository,
+		permissions:          permissions,
+		event:                event,
 	}
 }
 
 var _ port_permission_usecase.PermissionUsecase = &PermissionUsecase{}
 
-func (p *PermissionUsecase) Create(input permission_dtos.AddPermissionDto) (*permission_entity.Permission, error) {
+func (p *PermissionUsecase) Create(actorID string, input permission_dtos.AddPermissionDto) (*permission_entity.Permission, error) {
 	newPermission, err := permission_entity.NewPermission(&permission_entity.Permission{
 		ID:          uuid.New().String(),
 		UserID:      input.UserID,
@@ -36,11 +42,21 @@ func (p *PermissionUsecase) Create(input permission_dtos.AddPermissionDto) (*per
 		return nil, fmt.Errorf("failed to create permission: %w", err)
 	}
 
+	if err := p.checkGrantable(actorID, newPermission); err != nil {
+		return nil, err
+	}
+
 	permission, err := p.permissionRepository.Save(newPermission)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save permission: %w", err)
 	}
 
+	if err := p.permissions.Invalidate(permission.UserID); err != nil {
+		return nil, fmt.Errorf("failed to invalidate permissions: %w", err)
+	}
+
+	p.dispatch(newPermission)
+
 	return permission, nil
 }
 
@@ -60,30 +76,63 @@ func (p *PermissionUsecase) FindById(id string) (*permission_entity.Permission,
 	return permission, nil
 }
 
-func (p *PermissionUsecase) Update(id string, input permission_dtos.UpdatePermissionDto) (*permission_entity.Permission, error) {
-	permission, err := p.permissionRepository.FindByID(id)
+func (p *PermissionUsecase) Update(actorID string, id string, input permission_dtos.UpdatePermissionDto) (*permission_entity.Permission, error) {
+	existing, err := p.permissionRepository.FindByID(id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find permission by id: %w", err)
 	}
 
-	permission, err = permission.UpdatePermission(input.Modules, input.Actions, input.Level, input.Description)
+	// The current grants are checked as well as the new ones, so an update
+	// cannot turn someone else's grant into a deny, or lift a deny, beyond
+	// the actor's own access. UpdatePermission changes existing in place.
+	previous := *existing
+
+	permission, err := existing.UpdatePermission(input.Modules, input.Actions, input.Level, input.Description)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update permission: %w", err)
 	}
 
+	if err := p.checkGrantable(actorID, &previous, permission); err != nil {
+		return nil, err
+	}
+
 	permission, err = p.permissionRepository.Update(id, permission)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update permission: %w", err)
 	}
+
+	if err := p.permissions.Invalidate(permission.UserID); err != nil {
+		return nil, fmt.Errorf("failed to invalidate permissions: %w", err)
+	}
+
+	p.dispatch(existing)
+
 	return permission, nil
 }
 
-func (p *PermissionUsecase) Delete(id string) error {
-	user, err := p.permissionRepository.FindByID(id)
+func (p *PermissionUsecase) Delete(actorID string, id string) error {
+	permission, err := p.permissionRepository.FindByID(id)
 	if err != nil {
 		return fmt.Errorf("failed to find permission by id: %w", err)
 	}
-	return p.permissionRepository.Delete(user.ID)
+
+	if err := p.checkGrantable(actorID, permission); err != nil {
+		return err
+	}
+
+	permission.Revoke()
+
+	if err := p.permissionRepository.Delete(permission); err != nil {
+		return err
+	}
+
+	if err := p.permissions.Invalidate(permission.UserID); err != nil {
+		return fmt.Errorf("failed to invalidate permissions: %w", err)
+	}
+
+	p.dispatch(permission)
+
+	return nil
 }
 
 func (p *PermissionUsecase) FindPermissionByUserID(userID string) ([]*permission_entity.Permission, error) {
@@ -93,3 +142,25 @@ func (p *PermissionUsecase) FindPermissionByUserID(userID string) ([]*permission
 	}
 	return permissions, nil
 }
+
+// checkGrantable rejects permissions that grant or deny access the actor does
+// not have. The actor's grants are read from the permission
+// service rather than the token so a just-revoked grant cannot be passed on.
+func (p *PermissionUsecase) checkGrantable(actorID string, permissions ...*permission_entity.Permission) error {
+	held, err := p.permissions.Resolve(actorID, 0)
+	if err != nil {
+		return fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+	for _, permission := range permissions {
+		if err := permission_entity.CheckGrantable(held.Grants(), permission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *PermissionUsecase) dispatch(permission *permission_entity.Permission) {
+	for _, domainEvent := range permission.PullDomainEvents() {
+		p.event.Dispatch(domainEvent)
+	}
+}
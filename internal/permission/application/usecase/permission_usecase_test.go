@@ -7,7 +7,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	permission_event "github.com/williamkoller/system-education/internal/permission/domain/event"
 	permission_dtos "github.com/williamkoller/system-education/internal/permission/presentation/dtos"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
 )
 
 type MockPermissionRepository struct {
@@ -46,8 +48,8 @@ func (m *MockPermissionRepository) Update(id string, p *permission_entity.Permis
 	return args.Get(0).(*permission_entity.Permission), args.Error(1)
 }
 
-func (m *MockPermissionRepository) Delete(id string) error {
-	args := m.Called(id)
+func (m *MockPermissionRepository) Delete(p *permission_entity.Permission) error {
+	args := m.Called(p)
 	return args.Error(0)
 }
 
@@ -59,16 +61,48 @@ func (m *MockPermissionRepository) FindByID(id string) (*permission_entity.Permi
 	return args.Get(0).(*permission_entity.Permission), args.Error(1)
 }
 
+type MockPermissionService struct {
+	mock.Mock
+}
+
+func (m *MockPermissionService) Resolve(userID string, minVersion int64) (*permission_entity.EffectivePermissions, error) {
+	args := m.Called(userID, minVersion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Refresh(userID string) (*permission_entity.EffectivePermissions, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Invalidate(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func adminPermissions() *permission_entity.EffectivePermissions {
+	return permission_entity.NewEffectivePermissions("admin-1", 1, []*permission_entity.Permission{
+		{Modules: []string{"module1", "module2"}, Actions: []string{"read", "write"}, Level: "allowed"},
+	})
+}
+
 func TestPermissionUsecase_Create(t *testing.T) {
 	t.Run("should create permission successfully", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		input := permission_dtos.AddPermissionDto{
 			UserID:      "user-1",
 			Modules:     []string{"module1"},
 			Actions:     []string{"read"},
-			Level:       "admin",
+			Level:       "allowed",
 			Description: "test permission",
 		}
 
@@ -80,24 +114,56 @@ func TestPermissionUsecase_Create(t *testing.T) {
 			Level:       input.Level,
 			Description: input.Description,
 		}, nil)
+		mockPermissions.On("Invalidate", "user-1").Return(nil)
+
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
 
-		permission, err := usecase.Create(input)
+		permission, err := usecase.Create("admin-1", input)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, permission)
 		assert.Equal(t, input.UserID, permission.UserID)
 		mockRepo.AssertExpectations(t)
+		mockPermissions.AssertExpectations(t)
+	})
+
+	t.Run("should return error when invalidation fails", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
+
+		input := permission_dtos.AddPermissionDto{
+			UserID:      "user-1",
+			Modules:     []string{"module1"},
+			Actions:     []string{"read"},
+			Level:       "allowed",
+			Description: "test permission",
+		}
+
+		mockRepo.On("Save", mock.AnythingOfType("*permission_entity.Permission")).Return(&permission_entity.Permission{
+			ID:     "123",
+			UserID: input.UserID,
+		}, nil)
+		mockPermissions.On("Invalidate", "user-1").Return(errors.New("db error"))
+
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+
+		permission, err := usecase.Create("admin-1", input)
+
+		assert.Nil(t, permission)
+		assert.ErrorContains(t, err, "failed to invalidate permissions")
 	})
 
 	t.Run("should return error when validation fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		input := permission_dtos.AddPermissionDto{
 			UserID: "", // Invalid
 		}
 
-		permission, err := usecase.Create(input)
+		permission, err := usecase.Create("admin-1", input)
 
 		assert.Error(t, err)
 		assert.Nil(t, permission)
@@ -106,19 +172,22 @@ func TestPermissionUsecase_Create(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		input := permission_dtos.AddPermissionDto{
 			UserID:      "user-1",
 			Modules:     []string{"module1"},
 			Actions:     []string{"read"},
-			Level:       "admin",
+			Level:       "allowed",
 			Description: "test permission",
 		}
 
 		mockRepo.On("Save", mock.AnythingOfType("*permission_entity.Permission")).Return(nil, errors.New("db error"))
 
-		permission, err := usecase.Create(input)
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+
+		permission, err := usecase.Create("admin-1", input)
 
 		assert.Error(t, err)
 		assert.Nil(t, permission)
@@ -126,10 +195,94 @@ func TestPermissionUsecase_Create(t *testing.T) {
 	})
 }
 
+func TestPermissionUsecase_Create_Escalation(t *testing.T) {
+	t.Run("should reject grants the actor does not hold", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
+
+		input := permission_dtos.AddPermissionDto{
+			UserID:  "user-1",
+			Modules: []string{"module1", "permissions"},
+			Actions: []string{"read"},
+			Level:   "allowed",
+		}
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+
+		permission, err := usecase.Create("admin-1", input)
+
+		assert.Nil(t, permission)
+		assert.ErrorIs(t, err, permission_entity.ErrEscalation)
+		assert.ErrorContains(t, err, "permissions:read")
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+		mockPermissions.AssertNotCalled(t, "Invalidate", mock.Anything)
+	})
+
+	t.Run("should reject denying grants the actor does not hold", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
+
+		input := permission_dtos.AddPermissionDto{
+			UserID:  "user-1",
+			Modules: []string{"permissions"},
+			Actions: []string{"delete"},
+			Level:   "denied",
+		}
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+
+		_, err := usecase.Create("admin-1", input)
+
+		assert.ErrorIs(t, err, permission_entity.ErrEscalation)
+		assert.ErrorContains(t, err, "permissions:delete:denied")
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("should allow denying grants the actor holds", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
+
+		input := permission_dtos.AddPermissionDto{
+			UserID:  "user-1",
+			Modules: []string{"module1"},
+			Actions: []string{"write"},
+			Level:   "denied",
+		}
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+		mockRepo.On("Save", mock.AnythingOfType("*permission_entity.Permission")).Return(&permission_entity.Permission{ID: "123", UserID: "user-1"}, nil)
+		mockPermissions.On("Invalidate", "user-1").Return(nil)
+
+		_, err := usecase.Create("admin-1", input)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return error when actor permissions cannot be resolved", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
+
+		input := permission_dtos.AddPermissionDto{
+			UserID:  "user-1",
+			Modules: []string{"module1"},
+			Actions: []string{"read"},
+			Level:   "allowed",
+		}
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(nil, errors.New("db error"))
+
+		permission, err := usecase.Create("admin-1", input)
+
+		assert.Nil(t, permission)
+		assert.EqualError(t, err, "failed to resolve permissions: db error")
+	})
+}
+
 func TestPermissionUsecase_FindAll(t *testing.T) {
 	t.Run("should return all permissions", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		expectedPermissions := []*permission_entity.Permission{
 			{ID: "1", UserID: "user-1"},
@@ -147,7 +300,8 @@ func TestPermissionUsecase_FindAll(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		mockRepo.On("FindAll").Return(nil, errors.New("db error"))
 
@@ -162,7 +316,8 @@ func TestPermissionUsecase_FindAll(t *testing.T) {
 func TestPermissionUsecase_FindById(t *testing.T) {
 	t.Run("should return permission by id", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		expectedPermission := &permission_entity.Permission{ID: "123", UserID: "user-1"}
 
@@ -177,7 +332,8 @@ func TestPermissionUsecase_FindById(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		mockRepo.On("FindByID", "123").Return(nil, errors.New("db error"))
 
@@ -192,12 +348,13 @@ func TestPermissionUsecase_FindById(t *testing.T) {
 func TestPermissionUsecase_Update(t *testing.T) {
 	t.Run("should update permission successfully", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		id := "123"
 		modules := []string{"module2"}
 		actions := []string{"write"}
-		level := "user"
+		level := "restricted"
 		description := "updated permission"
 
 		input := permission_dtos.UpdatePermissionDto{
@@ -212,7 +369,7 @@ func TestPermissionUsecase_Update(t *testing.T) {
 			UserID:      "user-1",
 			Modules:     []string{"module1"},
 			Actions:     []string{"read"},
-			Level:       "admin",
+			Level:       "allowed",
 			Description: "test permission",
 		}
 
@@ -230,24 +387,29 @@ func TestPermissionUsecase_Update(t *testing.T) {
 		// We can't easily match the exact object pointer because it's modified in place,
 		// but we can match the content or just use mock.AnythingOfType
 		mockRepo.On("Update", id, mock.AnythingOfType("*permission_entity.Permission")).Return(updatedPermission, nil)
+		mockPermissions.On("Invalidate", "user-1").Return(nil)
+
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
 
-		permission, err := usecase.Update(id, input)
+		permission, err := usecase.Update("admin-1", id, input)
 
 		assert.NoError(t, err)
 		assert.Equal(t, updatedPermission, permission)
 		mockRepo.AssertExpectations(t)
+		mockPermissions.AssertExpectations(t)
 	})
 
 	t.Run("should return error when find by id fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		id := "123"
 		input := permission_dtos.UpdatePermissionDto{}
 
 		mockRepo.On("FindByID", id).Return(nil, permission_entity.ErrNotFound)
 
-		permission, err := usecase.Update(id, input)
+		permission, err := usecase.Update("admin-1", id, input)
 
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, permission_entity.ErrNotFound)
@@ -257,7 +419,8 @@ func TestPermissionUsecase_Update(t *testing.T) {
 
 	t.Run("should return error when update fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		id := "123"
 		modules := []string{"module2"}
@@ -268,13 +431,15 @@ func TestPermissionUsecase_Update(t *testing.T) {
 		existingPermission := &permission_entity.Permission{
 			ID:      id,
 			Modules: []string{"module1"},
-			Level:   "user",
+			Level:   "restricted",
 		}
 
 		mockRepo.On("FindByID", id).Return(existingPermission, nil)
 		mockRepo.On("Update", id, mock.AnythingOfType("*permission_entity.Permission")).Return(nil, errors.New("db error"))
 
-		permission, err := usecase.Update(id, input)
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+
+		permission, err := usecase.Update("admin-1", id, input)
 
 		assert.Error(t, err)
 		assert.Nil(t, permission)
@@ -283,7 +448,8 @@ func TestPermissionUsecase_Update(t *testing.T) {
 
 	t.Run("should return error when validation fails during update", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		id := "123"
 		level := ""
@@ -293,12 +459,12 @@ func TestPermissionUsecase_Update(t *testing.T) {
 
 		existingPermission := &permission_entity.Permission{
 			ID:    id,
-			Level: "admin",
+			Level: "allowed",
 		}
 
 		mockRepo.On("FindByID", id).Return(existingPermission, nil)
 
-		permission, err := usecase.Update(id, input)
+		permission, err := usecase.Update("admin-1", id, input)
 
 		assert.Error(t, err)
 		assert.Nil(t, permission)
@@ -307,32 +473,99 @@ func TestPermissionUsecase_Update(t *testing.T) {
 	})
 }
 
+func TestPermissionUsecase_Update_Escalation(t *testing.T) {
+	mockRepo := new(MockPermissionRepository)
+	mockPermissions := new(MockPermissionService)
+	usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
+
+	actions := []string{"read", "delete"}
+	mockRepo.On("FindByID", "123").Return(&permission_entity.Permission{
+		ID:      "123",
+		UserID:  "user-1",
+		Modules: []string{"module1"},
+		Actions: []string{"read"},
+		Level:   "allowed",
+	}, nil)
+	mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+
+	permission, err := usecase.Update("admin-1", "123", permission_dtos.UpdatePermissionDto{Actions: &actions})
+
+	assert.Nil(t, permission)
+	assert.ErrorIs(t, err, permission_entity.ErrEscalation)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPermissionUsecase_Update_EscalationOnExistingGrants(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *permission_entity.Permission
+		level    string
+		missing  string
+	}{
+		{
+			name:     "turning a grant the actor does not hold into a deny",
+			existing: &permission_entity.Permission{ID: "123", UserID: "user-1", Modules: []string{"permissions"}, Actions: []string{"create"}, Level: "allowed"},
+			level:    "denied",
+			missing:  "permissions:create",
+		},
+		{
+			name:     "lifting a deny the actor does not hold",
+			existing: &permission_entity.Permission{ID: "123", UserID: "admin-1", Modules: []string{"permissions"}, Actions: []string{"delete"}, Level: "denied"},
+			level:    "restricted",
+			missing:  "permissions:delete:denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockPermissionRepository)
+			mockPermissions := new(MockPermissionService)
+			usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
+
+			mockRepo.On("FindByID", "123").Return(tt.existing, nil)
+			mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+
+			permission, err := usecase.Update("admin-1", "123", permission_dtos.UpdatePermissionDto{Level: &tt.level})
+
+			assert.Nil(t, permission)
+			assert.ErrorIs(t, err, permission_entity.ErrEscalation)
+			assert.ErrorContains(t, err, tt.missing)
+			mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestPermissionUsecase_Delete(t *testing.T) {
 	t.Run("should delete permission successfully", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		id := "123"
 
 		// Mock FindByID first as Delete now calls it
-		mockRepo.On("FindByID", id).Return(&permission_entity.Permission{ID: id}, nil)
-		mockRepo.On("Delete", id).Return(nil)
+		mockRepo.On("FindByID", id).Return(&permission_entity.Permission{ID: id, UserID: "user-1"}, nil)
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+		mockRepo.On("Delete", mock.AnythingOfType("*permission_entity.Permission")).Return(nil)
+		mockPermissions.On("Invalidate", "user-1").Return(nil)
 
-		err := usecase.Delete(id)
+		err := usecase.Delete("admin-1", id)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
+		mockPermissions.AssertExpectations(t)
 	})
 
 	t.Run("should return error when find by id fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		id := "123"
 
 		mockRepo.On("FindByID", id).Return(nil, permission_entity.ErrNotFound)
 
-		err := usecase.Delete(id)
+		err := usecase.Delete("admin-1", id)
 
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, permission_entity.ErrNotFound)
@@ -341,24 +574,49 @@ func TestPermissionUsecase_Delete(t *testing.T) {
 
 	t.Run("should return error when delete fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		id := "123"
 
 		mockRepo.On("FindByID", id).Return(&permission_entity.Permission{ID: id}, nil)
-		mockRepo.On("Delete", id).Return(errors.New("db error"))
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+		mockRepo.On("Delete", mock.AnythingOfType("*permission_entity.Permission")).Return(errors.New("db error"))
 
-		err := usecase.Delete(id)
+		err := usecase.Delete("admin-1", id)
 
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
+		mockPermissions.AssertNotCalled(t, "Invalidate", mock.Anything)
+	})
+
+	t.Run("should reject removing a deny the actor does not hold", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
+
+		mockRepo.On("FindByID", "123").Return(&permission_entity.Permission{
+			ID:      "123",
+			UserID:  "admin-1",
+			Modules: []string{"permissions"},
+			Actions: []string{"delete"},
+			Level:   "denied",
+		}, nil)
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+
+		err := usecase.Delete("admin-1", "123")
+
+		assert.ErrorIs(t, err, permission_entity.ErrEscalation)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+		mockPermissions.AssertNotCalled(t, "Invalidate", mock.Anything)
 	})
 }
 
 func TestPermissionUsecase_FindPermissionByUserID(t *testing.T) {
 	t.Run("should return permissions by user id", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		userID := "user-1"
 		expectedPermissions := []*permission_entity.Permission{
@@ -376,7 +634,8 @@ func TestPermissionUsecase_FindPermissionByUserID(t *testing.T) {
 
 	t.Run("should return error when repository fails", func(t *testing.T) {
 		mockRepo := new(MockPermissionRepository)
-		usecase := NewPermissionUsecase(mockRepo)
+		mockPermissions := new(MockPermissionService)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, shared_event.NewDispatcher())
 
 		userID := "user-1"
 
@@ -389,3 +648,117 @@ func TestPermissionUsecase_FindPermissionByUserID(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+type MockDispatcher struct {
+	mock.Mock
+}
+
+func (m *MockDispatcher) Dispatch(event interface{}) {
+	m.Called(event)
+}
+
+func (m *MockDispatcher) Register(eventName string, handler shared_event.Handler) {
+	m.Called(eventName, handler)
+}
+
+func TestPermissionUsecase_DispatchesEvents(t *testing.T) {
+	t.Run("should dispatch created event", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		mockPermissions := new(MockPermissionService)
+		dispatcher := new(MockDispatcher)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, dispatcher)
+
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+		mockRepo.On("Save", mock.AnythingOfType("*permission_entity.Permission")).Return(&permission_entity.Permission{ID: "123", UserID: "user-1"}, nil)
+		mockPermissions.On("Invalidate", "user-1").Return(nil)
+		dispatcher.On("Dispatch", mock.AnythingOfType("*permission_event.PermissionCreatedEvent")).Return()
+
+		_, err := usecase.Create("admin-1", permission_dtos.AddPermissionDto{
+			UserID:  "user-1",
+			Modules: []string{"module1"},
+			Actions: []string{"read"},
+			Level:   "allowed",
+		})
+
+		assert.NoError(t, err)
+		dispatcher.AssertNumberOfCalls(t, "Dispatch", 1)
+	})
+
+	t.Run("should dispatch updated event with the new values", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		mockPermissions := new(MockPermissionService)
+		dispatcher := new(MockDispatcher)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, dispatcher)
+
+		existing := &permission_entity.Permission{ID: "123", UserID: "user-1", Modules: []string{"module1"}, Actions: []string{"read"}, Level: "allowed"}
+		actions := []string{"write"}
+
+		mockRepo.On("FindByID", "123").Return(existing, nil)
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+		// The repository returns a fresh value, as the GORM one does.
+		mockRepo.On("Update", "123", existing).Return(&permission_entity.Permission{ID: "123", UserID: "user-1"}, nil)
+		mockPermissions.On("Invalidate", "user-1").Return(nil)
+		dispatcher.On("Dispatch", mock.MatchedBy(func(e *permission_event.PermissionUpdatedEvent) bool {
+			return e.PermissionID == "123" && assert.ObjectsAreEqual([]string{"write"}, e.Actions)
+		})).Return()
+
+		_, err := usecase.Update("admin-1", "123", permission_dtos.UpdatePermissionDto{Actions: &actions})
+
+		assert.NoError(t, err)
+		dispatcher.AssertExpectations(t)
+	})
+
+	t.Run("should not dispatch when update is rejected", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		mockPermissions := new(MockPermissionService)
+		dispatcher := new(MockDispatcher)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, dispatcher)
+
+		existing := &permission_entity.Permission{ID: "123", UserID: "user-1", Modules: []string{"module1"}, Actions: []string{"read"}, Level: "allowed"}
+		modules := []string{"payments"}
+
+		mockRepo.On("FindByID", "123").Return(existing, nil)
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+
+		_, err := usecase.Update("admin-1", "123", permission_dtos.UpdatePermissionDto{Modules: &modules})
+
+		assert.Error(t, err)
+		dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+	})
+
+	t.Run("should dispatch revoked event on delete", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		mockPermissions := new(MockPermissionService)
+		dispatcher := new(MockDispatcher)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, dispatcher)
+
+		mockRepo.On("FindByID", "123").Return(&permission_entity.Permission{ID: "123", UserID: "user-1"}, nil)
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+		mockRepo.On("Delete", mock.AnythingOfType("*permission_entity.Permission")).Return(nil)
+		mockPermissions.On("Invalidate", "user-1").Return(nil)
+		dispatcher.On("Dispatch", mock.MatchedBy(func(e *permission_event.PermissionRevokedEvent) bool {
+			return e.PermissionID == "123" && e.UserID == "user-1"
+		})).Return()
+
+		err := usecase.Delete("admin-1", "123")
+
+		assert.NoError(t, err)
+		dispatcher.AssertExpectations(t)
+	})
+
+	t.Run("should not dispatch when delete fails", func(t *testing.T) {
+		mockRepo := new(MockPermissionRepository)
+		mockPermissions := new(MockPermissionService)
+		dispatcher := new(MockDispatcher)
+		usecase := NewPermissionUsecase(mockRepo, mockPermissions, dispatcher)
+
+		mockRepo.On("FindByID", "123").Return(&permission_entity.Permission{ID: "123", UserID: "user-1"}, nil)
+		mockPermissions.On("Resolve", "admin-1", int64(0)).Return(adminPermissions(), nil)
+		mockRepo.On("Delete", mock.AnythingOfType("*permission_entity.Permission")).Return(errors.New("db error"))
+
+		err := usecase.Delete("admin-1", "123")
+
+		assert.Error(t, err)
+		dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+	})
+}
@@ -0,0 +1,70 @@
+package permission_usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+	port_permission_usecase "github.com/williamkoller/system-education/internal/permission/port/usecase"
+	permission_dtos "github.com/williamkoller/system-education/internal/permission/presentation/dtos"
+)
+
+type PolicyUsecase struct {
+	engine      port_permission_service.PolicyEngine
+	permissions port_permission_service.PermissionService
+	now         func() time.Time
+}
+
+func NewPolicyUsecase(engine port_permission_service.PolicyEngine, permissions port_permission_service.PermissionService) *PolicyUsecase {
+	return &PolicyUsecase{engine: engine, permissions: permissions, now: time.Now}
+}
+
+var _ port_permission_usecase.PolicyUsecase = &PolicyUsecase{}
+
+func (u *PolicyUsecase) Evaluate(input permission_dtos.EvaluatePolicyDto) (*permission_policy.Explanation, error) {
+	if (input.Policy == "") == (input.Rule == nil) {
+		return nil, fmt.Errorf("%w: provide either policy or rule", permission_policy.ErrInvalidRule)
+	}
+	if input.Rule != nil {
+		if err := input.Rule.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", permission_policy.ErrInvalidRule, err)
+		}
+	}
+
+	effective, err := u.permissions.Resolve(input.UserID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+
+	claims := map[string]interface{}{"userID": input.UserID}
+	for key, value := range input.Claims {
+		claims[key] = value
+	}
+
+	req := permission_policy.Request{
+		Grants: effective.Grants(),
+		Claims: claims,
+		Params: input.Params,
+		Time:   u.now(),
+	}
+	if input.Time != nil {
+		req.Time = *input.Time
+	}
+
+	if input.Rule != nil {
+		explanation := input.Rule.Evaluate(req)
+		return &explanation, nil
+	}
+
+	if u.engine == nil {
+		return nil, errors.New("policy engine not configured")
+	}
+
+	explanation, err := u.engine.Evaluate(input.Policy, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate policy: %w", err)
+	}
+	return &explanation, nil
+}
@@ -0,0 +1,115 @@
+package permission_usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+	permission_dtos "github.com/williamkoller/system-education/internal/permission/presentation/dtos"
+)
+
+type MockPolicyEngine struct {
+	mock.Mock
+}
+
+func (m *MockPolicyEngine) Evaluate(name string, req permission_policy.Request) (permission_policy.Explanation, error) {
+	args := m.Called(name, req)
+	return args.Get(0).(permission_policy.Explanation), args.Error(1)
+}
+
+func teacherPermissions() *permission_entity.EffectivePermissions {
+	return permission_entity.NewEffectivePermissions("user-1", 1, []*permission_entity.Permission{
+		{Modules: []string{"users"}, Actions: []string{"read"}, Level: "allowed"},
+	})
+}
+
+func TestPolicyUsecase_EvaluateInlineRule(t *testing.T) {
+	permissions := new(MockPermissionService)
+	permissions.On("Resolve", "user-1", int64(0)).Return(teacherPermissions(), nil)
+	usecase := NewPolicyUsecase(new(MockPolicyEngine), permissions)
+
+	rule := permission_policy.AllOf(
+		permission_policy.RequireAccess([]string{"users"}, []string{"read"}),
+		permission_policy.RequireOwnership(permission_entity.NewOwnershipPolicy("users"), "id"),
+	)
+
+	explanation, err := usecase.Evaluate(permission_dtos.EvaluatePolicyDto{
+		Rule:   &rule,
+		UserID: "user-1",
+		Params: map[string]string{"id": "user-1"},
+	})
+	assert.NoError(t, err)
+	assert.True(t, explanation.Allowed)
+
+	explanation, err = usecase.Evaluate(permission_dtos.EvaluatePolicyDto{
+		Rule:   &rule,
+		UserID: "user-1",
+		Params: map[string]string{"id": "user-2"},
+	})
+	assert.NoError(t, err)
+	assert.False(t, explanation.Allowed)
+	assert.Equal(t, "no rule held", explanation.Children[1].Reason)
+}
+
+func TestPolicyUsecase_EvaluateNamedPolicy(t *testing.T) {
+	at := time.Date(2025, time.January, 8, 10, 0, 0, 0, time.UTC)
+	permissions := new(MockPermissionService)
+	permissions.On("Resolve", "user-1", int64(0)).Return(teacherPermissions(), nil)
+	engine := new(MockPolicyEngine)
+	engine.On("Evaluate", "users.read", mock.MatchedBy(func(req permission_policy.Request) bool {
+		return req.Time.Equal(at) && req.Claims["userID"] == "user-1" && req.Claims["tenant"] == "north" && len(req.Grants) == 1
+	})).Return(permission_policy.Explanation{Allowed: true}, nil)
+	usecase := NewPolicyUsecase(engine, permissions)
+
+	explanation, err := usecase.Evaluate(permission_dtos.EvaluatePolicyDto{
+		Policy: "users.read",
+		UserID: "user-1",
+		Claims: map[string]interface{}{"tenant": "north"},
+		Time:   &at,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, explanation.Allowed)
+	engine.AssertExpectations(t)
+}
+
+func TestPolicyUsecase_EvaluateErrors(t *testing.T) {
+	validRule := permission_policy.RequireAccess([]string{"users"}, nil)
+
+	t.Run("neither policy nor rule", func(t *testing.T) {
+		_, err := NewPolicyUsecase(nil, nil).Evaluate(permission_dtos.EvaluatePolicyDto{UserID: "user-1"})
+		assert.ErrorIs(t, err, permission_policy.ErrInvalidRule)
+	})
+
+	t.Run("both policy and rule", func(t *testing.T) {
+		_, err := NewPolicyUsecase(nil, nil).Evaluate(permission_dtos.EvaluatePolicyDto{Policy: "x", Rule: &validRule, UserID: "user-1"})
+		assert.ErrorIs(t, err, permission_policy.ErrInvalidRule)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		_, err := NewPolicyUsecase(nil, nil).Evaluate(permission_dtos.EvaluatePolicyDto{Rule: &permission_policy.Rule{}, UserID: "user-1"})
+		assert.ErrorIs(t, err, permission_policy.ErrInvalidRule)
+	})
+
+	t.Run("resolve error", func(t *testing.T) {
+		permissions := new(MockPermissionService)
+		permissions.On("Resolve", "user-1", int64(0)).Return(nil, errors.New("db error"))
+
+		_, err := NewPolicyUsecase(nil, permissions).Evaluate(permission_dtos.EvaluatePolicyDto{Rule: &validRule, UserID: "user-1"})
+		assert.EqualError(t, err, "failed to resolve permissions: db error")
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		permissions := new(MockPermissionService)
+		permissions.On("Resolve", "user-1", int64(0)).Return(teacherPermissions(), nil)
+		engine := new(MockPolicyEngine)
+		engine.On("Evaluate", "missing", mock.Anything).Return(permission_policy.Explanation{}, permission_policy.ErrNotFound)
+
+		_, err := NewPolicyUsecase(engine, permissions).Evaluate(permission_dtos.EvaluatePolicyDto{Policy: "missing", UserID: "user-1"})
+		assert.ErrorIs(t, err, permission_policy.ErrNotFound)
+	})
+}
@@ -0,0 +1,49 @@
+package permission_entity
+
+// EffectivePermissions is the set of grants a user holds at a given
+// permission version. The version is bumped every time one of the user's
+// permissions changes, so a cached copy can be compared against it.
+type EffectivePermissions struct {
+	UserID      string
+	Version     int64
+	Permissions []*Permission
+}
+
+func NewEffectivePermissions(userID string, version int64, permissions []*Permission) *EffectivePermissions {
+	return &EffectivePermissions{
+		UserID:      userID,
+		Version:     version,
+		Permissions: permissions,
+	}
+}
+
+// Modules and Actions flatten the allowed permissions for tokens and checks
+// that cannot express pairs or levels. Restricted and denied permissions are
+// left out rather than widened.
+func (e *EffectivePermissions) Modules() []string {
+	if e == nil {
+		return nil
+	}
+	modules := []string{}
+	for _, permission := range e.Permissions {
+		if Level(permission.GetLevel()) != LevelAllowed {
+			continue
+		}
+		modules = append(modules, permission.GetModules()...)
+	}
+	return modules
+}
+
+func (e *EffectivePermissions) Actions() []string {
+	if e == nil {
+		return nil
+	}
+	actions := []string{}
+	for _, permission := range e.Permissions {
+		if Level(permission.GetLevel()) != LevelAllowed {
+			continue
+		}
+		actions = append(actions, permission.GetActions()...)
+	}
+	return actions
+}
@@ -0,0 +1,41 @@
+package permission_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEffectivePermissions_ModulesAndActions(t *testing.T) {
+	effective := NewEffectivePermissions("user-1", 3, []*Permission{
+		{Modules: []string{"users"}, Actions: []string{"read"}, Level: "allowed"},
+		{Modules: []string{"permissions"}, Actions: []string{"update", "delete"}, Level: "allowed"},
+	})
+
+	assert.Equal(t, "user-1", effective.UserID)
+	assert.Equal(t, int64(3), effective.Version)
+	assert.Equal(t, []string{"users", "permissions"}, effective.Modules())
+	assert.Equal(t, []string{"read", "update", "delete"}, effective.Actions())
+}
+
+func TestEffectivePermissions_FlattensAllowedOnly(t *testing.T) {
+	effective := NewEffectivePermissions("user-1", 1, []*Permission{
+		{Modules: []string{"users"}, Actions: []string{"read"}, Level: "allowed"},
+		{Modules: []string{"reports"}, Actions: []string{"read"}, Level: "restricted"},
+		{Modules: []string{"permissions"}, Actions: []string{"delete"}, Level: "denied"},
+	})
+
+	assert.Equal(t, []string{"users"}, effective.Modules())
+	assert.Equal(t, []string{"read"}, effective.Actions())
+}
+
+func TestEffectivePermissions_Empty(t *testing.T) {
+	effective := NewEffectivePermissions("user-1", 0, nil)
+
+	assert.Empty(t, effective.Modules())
+	assert.Empty(t, effective.Actions())
+
+	var nilEffective *EffectivePermissions
+	assert.Nil(t, nilEffective.Modules())
+	assert.Nil(t, nilEffective.Actions())
+}
@@ -64,9 +64,17 @@ func (p *Permission) UpdatePermission(modules, actions *[]string, level, descrip
 		return nil, err
 	}
 
+	p.AddDomainEvent(permission_event.NewPermissionUpdatedEvent(p.ID, p.UserID, p.Modules, p.Actions, p.Level, p.Description))
+
 	return vp, nil
 }
 
+// Revoke records that the permission is being taken away. Removing it from
+// storage is left to the repository.
+func (p *Permission) Revoke() {
+	p.AddDomainEvent(permission_event.NewPermissionRevokedEvent(p.ID, p.UserID, p.Modules, p.Actions))
+}
+
 func (p *Permission) GetID() string {
 	if p == nil {
 		return ""
@@ -15,7 +15,7 @@ func TestNewPermission(t *testing.T) {
 			UserID:      "user-123",
 			Modules:     []string{"module1"},
 			Actions:     []string{"read"},
-			Level:       "admin",
+			Level:       "allowed",
 			Description: "test permission",
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
@@ -42,7 +42,7 @@ func TestNewPermission(t *testing.T) {
 			UserID:  "user-123",
 			Modules: []string{"module1"},
 			Actions: []string{"read"},
-			Level:   "admin",
+			Level:   "allowed",
 		}
 
 		permission, err := NewPermission(p)
@@ -57,7 +57,7 @@ func TestNewPermission(t *testing.T) {
 			ID:      "123",
 			Modules: []string{"module1"},
 			Actions: []string{"read"},
-			Level:   "admin",
+			Level:   "allowed",
 		}
 
 		permission, err := NewPermission(p)
@@ -72,7 +72,7 @@ func TestNewPermission(t *testing.T) {
 			ID:      "123",
 			UserID:  "user-123",
 			Actions: []string{"read"},
-			Level:   "admin",
+			Level:   "allowed",
 		}
 
 		permission, err := NewPermission(p)
@@ -87,7 +87,7 @@ func TestNewPermission(t *testing.T) {
 			ID:      "123",
 			UserID:  "user-123",
 			Modules: []string{"module1"},
-			Level:   "admin",
+			Level:   "allowed",
 		}
 
 		permission, err := NewPermission(p)
@@ -128,7 +128,7 @@ func TestPermission_Getters(t *testing.T) {
 			UserID:      "user-123",
 			Modules:     []string{"module1"},
 			Actions:     []string{"read"},
-			Level:       "admin",
+			Level:       "allowed",
 			Description: "test permission",
 			CreatedAt:   now,
 			UpdatedAt:   now,
@@ -138,7 +138,7 @@ func TestPermission_Getters(t *testing.T) {
 		assert.Equal(t, "user-123", p.GetUserID())
 		assert.Equal(t, []string{"module1"}, p.GetModules())
 		assert.Equal(t, []string{"read"}, p.GetActions())
-		assert.Equal(t, "admin", p.GetLevel())
+		assert.Equal(t, "allowed", p.GetLevel())
 		assert.Equal(t, "test permission", p.GetDescription())
 		assert.Equal(t, now, p.GetCreatedAt())
 		assert.Equal(t, now, p.GetUpdatedAt())
@@ -188,13 +188,13 @@ func TestPermission_UpdatePermission(t *testing.T) {
 			ID:          "123",
 			Modules:     []string{"module1"},
 			Actions:     []string{"read"},
-			Level:       "admin",
+			Level:       "allowed",
 			Description: "test permission",
 		}
 
 		modules := []string{"module2"}
 		actions := []string{"write"}
-		level := "user"
+		level := "restricted"
 		description := "updated permission"
 
 		updatedP, err := p.UpdatePermission(&modules, &actions, &level, &description)
@@ -212,11 +212,11 @@ func TestPermission_UpdatePermission(t *testing.T) {
 			ID:          "123",
 			Modules:     []string{"module1"},
 			Actions:     []string{"read"},
-			Level:       "admin",
+			Level:       "allowed",
 			Description: "test permission",
 		}
 
-		level := "user"
+		level := "restricted"
 
 		updatedP, err := p.UpdatePermission(nil, nil, &level, nil)
 
@@ -233,7 +233,7 @@ func TestPermission_UpdatePermission(t *testing.T) {
 			ID:          "123",
 			Modules:     []string{"module1"},
 			Actions:     []string{"read"},
-			Level:       "admin",
+			Level:       "allowed",
 			Description: "test permission",
 		}
 
@@ -246,3 +246,35 @@ func TestPermission_UpdatePermission(t *testing.T) {
 		assert.Contains(t, err.Error(), "level cannot be empty")
 	})
 }
+
+func TestPermission_UpdatePermission_RecordsEvent(t *testing.T) {
+	p := &Permission{ID: "123", UserID: "user-123", Modules: []string{"module1"}, Actions: []string{"read"}, Level: "allowed"}
+	actions := []string{"write"}
+
+	_, err := p.UpdatePermission(nil, &actions, nil, nil)
+	assert.NoError(t, err)
+
+	events := p.PullDomainEvents()
+	assert.Len(t, events, 1)
+	updated := events[0].(*permission_event.PermissionUpdatedEvent)
+	assert.Equal(t, "123", updated.PermissionID)
+	assert.Equal(t, []string{"write"}, updated.Actions)
+
+	level := "superuser"
+	_, err = p.UpdatePermission(nil, nil, &level, nil)
+	assert.Error(t, err)
+	assert.Empty(t, p.PullDomainEvents())
+}
+
+func TestPermission_Revoke(t *testing.T) {
+	p := &Permission{ID: "123", UserID: "user-123", Modules: []string{"module1"}, Actions: []string{"read"}}
+
+	p.Revoke()
+
+	events := p.PullDomainEvents()
+	assert.Len(t, events, 1)
+	revoked := events[0].(*permission_event.PermissionRevokedEvent)
+	assert.Equal(t, "123", revoked.PermissionID)
+	assert.Equal(t, "user-123", revoked.UserID)
+	assert.Equal(t, []string{"module1"}, revoked.Modules)
+}
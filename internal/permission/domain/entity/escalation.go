@@ -0,0 +1,37 @@
+package permission_entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrEscalation = errors.New("cannot grant permissions you do not hold")
+
+// MissingGrants returns the grants of p that held does not cover. A denied
+// grant needs the same access as an allowed one: otherwise anyone able to
+// create permissions could lock an administrator out of what they hold.
+func MissingGrants(held []Grant, p *Permission) []Grant {
+	var missing []Grant
+	for _, grant := range p.Grants() {
+		if !Evaluate(held, []string{grant.Module}, []string{grant.Action}).Allowed {
+			missing = append(missing, grant)
+		}
+	}
+	return missing
+}
+
+// CheckGrantable returns ErrEscalation, naming the uncovered grants, when a
+// caller holding held may not hand out, change or take away p.
+func CheckGrantable(held []Grant, p *Permission) error {
+	missing := MissingGrants(held, p)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(missing))
+	for _, grant := range missing {
+		names = append(names, grant.String())
+	}
+	return fmt.Errorf("%w: %s", ErrEscalation, strings.Join(names, ", "))
+}
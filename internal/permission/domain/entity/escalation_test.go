@@ -0,0 +1,78 @@
+package permission_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckGrantable(t *testing.T) {
+	held := []Grant{
+		NewGrant("users", "read", LevelAllowed),
+		NewGrant("users", "update", LevelAllowed),
+		NewGrant("grades", "read", LevelRestricted),
+		NewGrant("grades", "update", LevelRestricted),
+		NewGrant("permissions", "create", LevelAllowed),
+		NewGrant("permissions", "create", LevelDenied),
+	}
+
+	tests := []struct {
+		name       string
+		permission *Permission
+		missing    string
+	}{
+		{
+			name:       "subset of held grants",
+			permission: &Permission{Modules: []string{"users"}, Actions: []string{"read", "update"}, Level: "allowed"},
+		},
+		{
+			name:       "restricted read is covered by restricted read",
+			permission: &Permission{Modules: []string{"grades"}, Actions: []string{"read"}, Level: "restricted"},
+		},
+		{
+			name:       "restricted update is not covered by restricted update",
+			permission: &Permission{Modules: []string{"grades"}, Actions: []string{"update"}, Level: "restricted"},
+			missing:    "grades:update:restricted",
+		},
+		{
+			name:       "action on another module",
+			permission: &Permission{Modules: []string{"users", "roles"}, Actions: []string{"read"}, Level: "allowed"},
+			missing:    "roles:read",
+		},
+		{
+			name:       "held grant overridden by deny",
+			permission: &Permission{Modules: []string{"permissions"}, Actions: []string{"create"}, Level: "allowed"},
+			missing:    "permissions:create",
+		},
+		{
+			name:       "deny of a held grant",
+			permission: &Permission{Modules: []string{"users"}, Actions: []string{"update"}, Level: "denied"},
+		},
+		{
+			name:       "deny of a grant the caller does not hold",
+			permission: &Permission{Modules: []string{"roles"}, Actions: []string{"delete"}, Level: "denied"},
+			missing:    "roles:delete:denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckGrantable(held, tt.permission)
+			if tt.missing == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrEscalation)
+			assert.EqualError(t, err, "cannot grant permissions you do not hold: "+tt.missing)
+		})
+	}
+}
+
+func TestMissingGrants_NoHeldGrants(t *testing.T) {
+	permission := &Permission{Modules: []string{"users"}, Actions: []string{"read", "delete"}, Level: "allowed"}
+
+	assert.Equal(t, []Grant{
+		NewGrant("users", "read", LevelAllowed),
+		NewGrant("users", "delete", LevelAllowed),
+	}, MissingGrants(nil, permission))
+}
@@ -0,0 +1,77 @@
+package permission_entity
+
+type DecisionReason string
+
+const (
+	ReasonGranted          DecisionReason = "granted"
+	ReasonModuleNotGranted DecisionReason = "module not granted"
+	ReasonActionNotGranted DecisionReason = "action not granted"
+	ReasonDenied           DecisionReason = "denied"
+	ReasonRestricted       DecisionReason = "restricted to read"
+)
+
+type Decision struct {
+	Allowed bool
+	Reason  DecisionReason
+}
+
+func allow() Decision {
+	return Decision{Allowed: true, Reason: ReasonGranted}
+}
+
+func deny(reason DecisionReason) Decision {
+	return Decision{Allowed: false, Reason: reason}
+}
+
+// Evaluate decides whether grants satisfy a requirement of any one of
+// requiredModules paired with any one of requiredActions on that module.
+//
+// A denied grant on a required pair overrides every other grant. A restricted
+// grant only satisfies ReadAction. With no required actions, any allowed or
+// restricted grant on a required module is enough; denied grants name
+// specific pairs and do not block module-only requirements.
+func Evaluate(grants []Grant, requiredModules, requiredActions []string) Decision {
+	moduleGranted := false
+	actionGranted := false
+	restrictedOnly := false
+
+	for _, grant := range grants {
+		if !contains(requiredModules, grant.Module) {
+			continue
+		}
+		matchesAction := len(requiredActions) == 0 || contains(requiredActions, grant.Action)
+
+		switch grant.Level {
+		case LevelAllowed:
+			moduleGranted = true
+			if matchesAction {
+				actionGranted = true
+			}
+		case LevelRestricted:
+			moduleGranted = true
+			if !matchesAction {
+				continue
+			}
+			if len(requiredActions) == 0 || grant.Action == ReadAction {
+				actionGranted = true
+			} else {
+				restrictedOnly = true
+			}
+		default:
+			if matchesAction && len(requiredActions) > 0 {
+				return deny(ReasonDenied)
+			}
+		}
+	}
+
+	switch {
+	case actionGranted:
+		return allow()
+	case restrictedOnly:
+		return deny(ReasonRestricted)
+	case moduleGranted:
+		return deny(ReasonActionNotGranted)
+	default:
+		return deny(ReasonModuleNotGranted)
+	}
+}
@@ -0,0 +1,170 @@
+package permission_entity
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var allLevels = []Level{LevelAllowed, LevelRestricted, LevelDenied}
+
+func TestLevel_IsValid(t *testing.T) {
+	for _, level := range allLevels {
+		assert.True(t, level.IsValid(), level)
+	}
+	assert.False(t, Level("").IsValid())
+	assert.False(t, Level("admin").IsValid())
+	assert.False(t, Level("Allowed").IsValid())
+}
+
+// TestEvaluate_SingleGrant covers every level against every way a single
+// grant can relate to a requirement.
+func TestEvaluate_SingleGrant(t *testing.T) {
+	type shape struct {
+		name    string
+		grant   Grant
+		modules []string
+		actions []string
+	}
+
+	shapes := []shape{
+		{name: "read pair", grant: Grant{Module: "users", Action: "read"}, modules: []string{"users"}, actions: []string{"read"}},
+		{name: "write pair", grant: Grant{Module: "users", Action: "update"}, modules: []string{"users"}, actions: []string{"update"}},
+		{name: "module only", grant: Grant{Module: "users", Action: "update"}, modules: []string{"users"}},
+		{name: "other action", grant: Grant{Module: "users", Action: "read"}, modules: []string{"users"}, actions: []string{"delete"}},
+		{name: "other module", grant: Grant{Module: "reports", Action: "read"}, modules: []string{"users"}, actions: []string{"read"}},
+		{name: "other module, module only", grant: Grant{Module: "reports", Action: "read"}, modules: []string{"users"}},
+	}
+
+	expected := map[string]map[Level]Decision{
+		"read pair": {
+			LevelAllowed:    allow(),
+			LevelRestricted: allow(),
+			LevelDenied:     deny(ReasonDenied),
+		},
+		"write pair": {
+			LevelAllowed:    allow(),
+			LevelRestricted: deny(ReasonRestricted),
+			LevelDenied:     deny(ReasonDenied),
+		},
+		"module only": {
+			LevelAllowed:    allow(),
+			LevelRestricted: allow(),
+			LevelDenied:     deny(ReasonModuleNotGranted),
+		},
+		"other action": {
+			LevelAllowed:    deny(ReasonActionNotGranted),
+			LevelRestricted: deny(ReasonActionNotGranted),
+			LevelDenied:     deny(ReasonModuleNotGranted),
+		},
+		"other module": {
+			LevelAllowed:    deny(ReasonModuleNotGranted),
+			LevelRestricted: deny(ReasonModuleNotGranted),
+			LevelDenied:     deny(ReasonModuleNotGranted),
+		},
+		"other module, module only": {
+			LevelAllowed:    deny(ReasonModuleNotGranted),
+			LevelRestricted: deny(ReasonModuleNotGranted),
+			LevelDenied:     deny(ReasonModuleNotGranted),
+		},
+	}
+
+	for _, s := range shapes {
+		for _, level := range allLevels {
+			t.Run(fmt.Sprintf("%s/%s", s.name, level), func(t *testing.T) {
+				grant := s.grant
+				grant.Level = level
+
+				assert.Equal(t, expected[s.name][level], Evaluate([]Grant{grant}, s.modules, s.actions))
+			})
+		}
+	}
+}
+
+// TestEvaluate_SamePairPrecedence checks every combination of two grants on
+// the same pair: denied beats allowed, and allowed beats restricted.
+func TestEvaluate_SamePairPrecedence(t *testing.T) {
+	for _, action := range []string{"read", "update"} {
+		for _, first := range allLevels {
+			for _, second := range allLevels {
+				t.Run(fmt.Sprintf("%s/%s+%s", action, first, second), func(t *testing.T) {
+					grants := []Grant{
+						NewGrant("users", action, first),
+						NewGrant("users", action, second),
+					}
+
+					decision := Evaluate(grants, []string{"users"}, []string{action})
+
+					switch {
+					case first == LevelDenied || second == LevelDenied:
+						assert.Equal(t, deny(ReasonDenied), decision)
+					case first == LevelAllowed || second == LevelAllowed:
+						assert.Equal(t, allow(), decision)
+					case action == ReadAction:
+						assert.Equal(t, allow(), decision)
+					default:
+						assert.Equal(t, deny(ReasonRestricted), decision)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestEvaluate_DenyOverridesAcrossAlternatives(t *testing.T) {
+	grants := []Grant{
+		NewGrant("reports", "read", LevelAllowed),
+		NewGrant("users", "read", LevelDenied),
+	}
+
+	decision := Evaluate(grants, []string{"users", "reports"}, []string{"read"})
+
+	assert.Equal(t, deny(ReasonDenied), decision)
+}
+
+func TestEvaluate_DenyOnlyCoversNamedPairs(t *testing.T) {
+	grants := []Grant{
+		NewGrant("users", "read", LevelAllowed),
+		NewGrant("users", "delete", LevelDenied),
+	}
+
+	assert.Equal(t, allow(), Evaluate(grants, []string{"users"}, []string{"read"}))
+	assert.Equal(t, allow(), Evaluate(grants, []string{"users"}, nil))
+	assert.Equal(t, deny(ReasonDenied), Evaluate(grants, []string{"users"}, []string{"delete"}))
+	assert.Equal(t, deny(ReasonActionNotGranted), Evaluate(grants, []string{"users"}, []string{"update"}))
+}
+
+func TestEvaluate_RestrictedWithAnyOfActions(t *testing.T) {
+	grants := []Grant{
+		NewGrant("users", "read", LevelRestricted),
+		NewGrant("users", "update", LevelRestricted),
+	}
+
+	assert.Equal(t, allow(), Evaluate(grants, []string{"users"}, []string{"update", "read"}))
+	assert.Equal(t, deny(ReasonRestricted), Evaluate(grants, []string{"users"}, []string{"update"}))
+}
+
+func TestEvaluate_CrossModulePairs(t *testing.T) {
+	grants := []Grant{
+		NewGrant("users", "read", LevelAllowed),
+		NewGrant("permissions", "delete", LevelAllowed),
+	}
+
+	assert.Equal(t, deny(ReasonActionNotGranted), Evaluate(grants, []string{"users"}, []string{"delete"}))
+	assert.Equal(t, allow(), Evaluate(grants, []string{"users", "permissions"}, []string{"delete"}))
+}
+
+func TestEvaluate_NoGrantsOrRequirements(t *testing.T) {
+	assert.Equal(t, deny(ReasonModuleNotGranted), Evaluate(nil, []string{"users"}, []string{"read"}))
+	assert.Equal(t, deny(ReasonModuleNotGranted), Evaluate(nil, []string{"users"}, nil))
+	assert.Equal(t, deny(ReasonModuleNotGranted), Evaluate([]Grant{NewGrant("users", "read", LevelAllowed)}, nil, []string{"read"}))
+	assert.Equal(t, deny(ReasonModuleNotGranted), Evaluate([]Grant{NewGrant("users", "read", LevelAllowed)}, nil, nil))
+}
+
+func TestEvaluate_UnknownLevelNeverGrants(t *testing.T) {
+	grants := []Grant{NewGrant("users", "read", Level("admin"))}
+
+	assert.False(t, Evaluate(grants, []string{"users"}, []string{"read"}).Allowed)
+	assert.False(t, Evaluate(grants, []string{"users"}, nil).Allowed)
+}
@@ -0,0 +1,83 @@
+package permission_entity
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidGrant = errors.New("invalid grant")
+
+// Grant is a single action on a single module at a given level. A permission
+// grants each of its actions on each of its modules, and on no other module.
+type Grant struct {
+	Module string
+	Action string
+	Level  Level
+}
+
+func NewGrant(module, action string, level Level) Grant {
+	return Grant{Module: module, Action: action, Level: level}
+}
+
+// ParseGrant reads the "module:action[:level]" form used in access tokens.
+// The level is omitted for allowed grants.
+func ParseGrant(value string) (Grant, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return Grant{}, ErrInvalidGrant
+	}
+
+	level := LevelAllowed
+	if len(parts) == 3 {
+		level = Level(parts[2])
+		if !level.IsValid() {
+			return Grant{}, ErrInvalidGrant
+		}
+	}
+	return NewGrant(parts[0], parts[1], level), nil
+}
+
+func (g Grant) String() string {
+	if g.Level == LevelAllowed {
+		return g.Module + ":" + g.Action
+	}
+	return g.Module + ":" + g.Action + ":" + string(g.Level)
+}
+
+func (p *Permission) Grants() []Grant {
+	if p == nil {
+		return nil
+	}
+	level := Level(p.Level)
+	if !level.IsValid() {
+		level = LevelDenied
+	}
+
+	grants := make([]Grant, 0, len(p.Modules)*len(p.Actions))
+	for _, module := range p.Modules {
+		for _, action := range p.Actions {
+			grants = append(grants, NewGrant(module, action, level))
+		}
+	}
+	return grants
+}
+
+func (e *EffectivePermissions) Grants() []Grant {
+	if e == nil {
+		return nil
+	}
+	grants := []Grant{}
+	for _, permission := range e.Permissions {
+		grants = append(grants, permission.Grants()...)
+	}
+	return grants
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package permission_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGrant(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Grant
+		wantErr bool
+	}{
+		{name: "allowed by default", value: "users:read", want: Grant{Module: "users", Action: "read", Level: LevelAllowed}},
+		{name: "restricted", value: "users:read:restricted", want: Grant{Module: "users", Action: "read", Level: LevelRestricted}},
+		{name: "denied", value: "users:delete:denied", want: Grant{Module: "users", Action: "delete", Level: LevelDenied}},
+		{name: "unknown level", value: "users:read:admin", wantErr: true},
+		{name: "explicit allowed is not canonical", value: "users:read:allowed", want: Grant{Module: "users", Action: "read", Level: LevelAllowed}},
+		{name: "missing separator", value: "users", wantErr: true},
+		{name: "empty module", value: ":read", wantErr: true},
+		{name: "empty action", value: "users:", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grant, err := ParseGrant(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidGrant)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, grant)
+
+			roundTrip, err := ParseGrant(grant.String())
+			assert.NoError(t, err)
+			assert.Equal(t, grant, roundTrip)
+		})
+	}
+}
+
+func TestGrant_String(t *testing.T) {
+	assert.Equal(t, "users:read", NewGrant("users", "read", LevelAllowed).String())
+	assert.Equal(t, "users:read:restricted", NewGrant("users", "read", LevelRestricted).String())
+	assert.Equal(t, "users:delete:denied", NewGrant("users", "delete", LevelDenied).String())
+}
+
+func TestPermission_Grants(t *testing.T) {
+	permission := &Permission{Modules: []string{"users", "reports"}, Actions: []string{"read", "update"}, Level: "restricted"}
+
+	assert.Equal(t, []Grant{
+		{Module: "users", Action: "read", Level: LevelRestricted},
+		{Module: "users", Action: "update", Level: LevelRestricted},
+		{Module: "reports", Action: "read", Level: LevelRestricted},
+		{Module: "reports", Action: "update", Level: LevelRestricted},
+	}, permission.Grants())
+
+	unknown := &Permission{Modules: []string{"users"}, Actions: []string{"read"}, Level: "admin"}
+	assert.Equal(t, []Grant{{Module: "users", Action: "read", Level: LevelDenied}}, unknown.Grants(),
+		"an unknown level must not grant anything")
+
+	var nilPermission *Permission
+	assert.Nil(t, nilPermission.Grants())
+}
+
+func TestEffectivePermissions_Grants(t *testing.T) {
+	effective := NewEffectivePermissions("user-1", 1, []*Permission{
+		{Modules: []string{"users"}, Actions: []string{"read"}, Level: "allowed"},
+		{Modules: []string{"permissions"}, Actions: []string{"delete"}, Level: "denied"},
+	})
+
+	assert.Equal(t, []Grant{
+		{Module: "users", Action: "read", Level: LevelAllowed},
+		{Module: "permissions", Action: "delete", Level: LevelDenied},
+	}, effective.Grants())
+}
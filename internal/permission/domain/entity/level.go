@@ -0,0 +1,24 @@
+package permission_entity
+
+type Level string
+
+const (
+	LevelAllowed Level = "allowed"
+	// LevelRestricted grants read access only: a restricted grant covers
+	// ReadAction and never an action that changes data.
+	LevelRestricted Level = "restricted"
+	// LevelDenied revokes the named module/action pairs, overriding any
+	// allowed or restricted grant for the same pair.
+	LevelDenied Level = "denied"
+)
+
+const ReadAction = "read"
+
+func (l Level) IsValid() bool {
+	switch l {
+	case LevelAllowed, LevelRestricted, LevelDenied:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,33 @@
+package permission_entity
+
+// AdminAction is the action that lets a caller act on records owned by
+// someone else when a route is guarded by an OwnershipPolicy.
+const AdminAction = "admin"
+
+const ReasonNotOwner DecisionReason = "not owner"
+
+// OwnershipPolicy restricts a route to the owner of the record unless the
+// caller holds the override grant on Module.
+type OwnershipPolicy struct {
+	Module         string
+	OverrideAction string
+}
+
+func NewOwnershipPolicy(module string) OwnershipPolicy {
+	return OwnershipPolicy{Module: module, OverrideAction: AdminAction}
+}
+
+// Overridden reports whether grants let the caller bypass the owner check.
+func (p OwnershipPolicy) Overridden(grants []Grant) bool {
+	return Evaluate(grants, []string{p.Module}, []string{p.OverrideAction}).Allowed
+}
+
+func (p OwnershipPolicy) Evaluate(grants []Grant, callerID, ownerID string) Decision {
+	if p.Overridden(grants) {
+		return allow()
+	}
+	if callerID != "" && callerID == ownerID {
+		return allow()
+	}
+	return deny(ReasonNotOwner)
+}
@@ -0,0 +1,74 @@
+package permission_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnershipPolicy_Evaluate(t *testing.T) {
+	policy := NewOwnershipPolicy("users")
+
+	tests := []struct {
+		name     string
+		grants   []Grant
+		callerID string
+		ownerID  string
+		expected Decision
+	}{
+		{name: "owner", callerID: "user-1", ownerID: "user-1", expected: allow()},
+		{name: "other user", callerID: "user-1", ownerID: "user-2", expected: deny(ReasonNotOwner)},
+		{name: "anonymous caller", callerID: "", ownerID: "", expected: deny(ReasonNotOwner)},
+		{
+			name:     "admin grant overrides",
+			grants:   []Grant{NewGrant("users", "admin", LevelAllowed)},
+			callerID: "user-1",
+			ownerID:  "user-2",
+			expected: allow(),
+		},
+		{
+			name:     "admin grant on another module",
+			grants:   []Grant{NewGrant("permissions", "admin", LevelAllowed)},
+			callerID: "user-1",
+			ownerID:  "user-2",
+			expected: deny(ReasonNotOwner),
+		},
+		{
+			name:     "restricted admin grant does not override",
+			grants:   []Grant{NewGrant("users", "admin", LevelRestricted)},
+			callerID: "user-1",
+			ownerID:  "user-2",
+			expected: deny(ReasonNotOwner),
+		},
+		{
+			name: "denied admin grant wins",
+			grants: []Grant{
+				NewGrant("users", "admin", LevelAllowed),
+				NewGrant("users", "admin", LevelDenied),
+			},
+			callerID: "user-1",
+			ownerID:  "user-2",
+			expected: deny(ReasonNotOwner),
+		},
+		{
+			name:     "update grant is not an override",
+			grants:   []Grant{NewGrant("users", "update", LevelAllowed)},
+			callerID: "user-1",
+			ownerID:  "user-2",
+			expected: deny(ReasonNotOwner),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, policy.Evaluate(tt.grants, tt.callerID, tt.ownerID))
+		})
+	}
+}
+
+func TestOwnershipPolicy_CustomOverride(t *testing.T) {
+	policy := OwnershipPolicy{Module: "permissions", OverrideAction: "manage"}
+
+	assert.True(t, policy.Overridden([]Grant{NewGrant("permissions", "manage", LevelAllowed)}))
+	assert.False(t, policy.Overridden([]Grant{NewGrant("permissions", "admin", LevelAllowed)}))
+}
@@ -38,6 +38,8 @@ func ValidatePermission(permission *Permission) (*Permission, error) {
 
 	if permission.Level == "" {
 		errs = append(errs, "level is required")
+	} else if !Level(permission.Level).IsValid() {
+		errs = append(errs, "level must be one of allowed, restricted or denied")
 	}
 
 	if len(errs) > 0 {
@@ -52,6 +54,8 @@ func ValidationUpdatePermission(permission *Permission) (*Permission, error) {
 
 	if permission.Level == "" {
 		errs = append(errs, "level cannot be empty")
+	} else if !Level(permission.Level).IsValid() {
+		errs = append(errs, "level must be one of allowed, restricted or denied")
 	}
 
 	if len(errs) > 0 {
@@ -13,7 +13,7 @@ func TestValidatePermission(t *testing.T) {
 			UserID:  "user-123",
 			Modules: []string{"module1"},
 			Actions: []string{"read"},
-			Level:   "admin",
+			Level:   "allowed",
 		}
 
 		validatedPermission, err := ValidatePermission(p)
@@ -28,7 +28,7 @@ func TestValidatePermission(t *testing.T) {
 			UserID:  "user-123",
 			Modules: []string{"module1"},
 			Actions: []string{"read"},
-			Level:   "admin",
+			Level:   "allowed",
 		}
 
 		validatedPermission, err := ValidatePermission(p)
@@ -44,7 +44,7 @@ func TestValidatePermission(t *testing.T) {
 			ID:      "123",
 			Modules: []string{"module1"},
 			Actions: []string{"read"},
-			Level:   "admin",
+			Level:   "allowed",
 		}
 
 		validatedPermission, err := ValidatePermission(p)
@@ -60,7 +60,7 @@ func TestValidatePermission(t *testing.T) {
 			ID:      "123",
 			UserID:  "user-123",
 			Actions: []string{"read"},
-			Level:   "admin",
+			Level:   "allowed",
 		}
 
 		validatedPermission, err := ValidatePermission(p)
@@ -76,7 +76,7 @@ func TestValidatePermission(t *testing.T) {
 			ID:      "123",
 			UserID:  "user-123",
 			Modules: []string{"module1"},
-			Level:   "admin",
+			Level:   "allowed",
 		}
 
 		validatedPermission, err := ValidatePermission(p)
@@ -103,6 +103,22 @@ func TestValidatePermission(t *testing.T) {
 		assert.Contains(t, err.Error(), "level is required")
 	})
 
+	t.Run("should return error when level is unknown", func(t *testing.T) {
+		p := &Permission{
+			ID:      "123",
+			UserID:  "user-123",
+			Modules: []string{"module1"},
+			Actions: []string{"read"},
+			Level:   "admin",
+		}
+
+		validatedPermission, err := ValidatePermission(p)
+
+		assert.Error(t, err)
+		assert.Nil(t, validatedPermission)
+		assert.Contains(t, err.Error(), "level must be one of allowed, restricted or denied")
+	})
+
 	t.Run("should return multiple errors", func(t *testing.T) {
 		p := &Permission{}
 
@@ -130,7 +146,7 @@ func TestValidationError_Error(t *testing.T) {
 func TestValidationUpdatePermission(t *testing.T) {
 	t.Run("should validate update permission successfully", func(t *testing.T) {
 		p := &Permission{
-			Level: "admin",
+			Level: "allowed",
 		}
 
 		validatedP, err := ValidationUpdatePermission(p)
@@ -150,4 +166,16 @@ func TestValidationUpdatePermission(t *testing.T) {
 		assert.Nil(t, validatedP)
 		assert.Contains(t, err.Error(), "level cannot be empty")
 	})
+
+	t.Run("should return error when level is unknown", func(t *testing.T) {
+		p := &Permission{
+			Level: "admin",
+		}
+
+		validatedP, err := ValidationUpdatePermission(p)
+
+		assert.Error(t, err)
+		assert.Nil(t, validatedP)
+		assert.Contains(t, err.Error(), "level must be one of allowed, restricted or denied")
+	})
 }
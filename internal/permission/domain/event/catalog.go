@@ -0,0 +1,12 @@
+package permission_event
+
+import shared_event "github.com/williamkoller/system-education/shared/domain/event"
+
+// Catalog lists the events the permission module raises.
+func Catalog() []func() shared_event.Event {
+	return []func() shared_event.Event{
+		func() shared_event.Event { return &PermissionCreatedEvent{} },
+		func() shared_event.Event { return &PermissionUpdatedEvent{} },
+		func() shared_event.Event { return &PermissionRevokedEvent{} },
+	}
+}
@@ -0,0 +1,40 @@
+package permission_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPermissionUpdatedEvent(t *testing.T) {
+	event := NewPermissionUpdatedEvent("123", "user-123", []string{"module1"}, []string{"write"}, "allowed", "updated")
+
+	assert.Equal(t, "123", event.PermissionID)
+	assert.Equal(t, "user-123", event.UserID)
+	assert.Equal(t, []string{"write"}, event.Actions)
+	assert.Equal(t, "allowed", event.Level)
+	assert.Equal(t, "permission.updated", event.EventName())
+	assert.Equal(t, event.Date, event.OccurredOn())
+	assert.WithinDuration(t, time.Now(), event.Date, time.Second)
+}
+
+func TestNewPermissionRevokedEvent(t *testing.T) {
+	event := NewPermissionRevokedEvent("123", "user-123", []string{"module1"}, []string{"read"})
+
+	assert.Equal(t, "123", event.PermissionID)
+	assert.Equal(t, "user-123", event.UserID)
+	assert.Equal(t, []string{"module1"}, event.Modules)
+	assert.Equal(t, "permission.revoked", event.EventName())
+	assert.Equal(t, event.Date, event.OccurredOn())
+	assert.WithinDuration(t, time.Now(), event.Date, time.Second)
+}
+
+func TestCatalog(t *testing.T) {
+	var names []string
+	for _, factory := range Catalog() {
+		names = append(names, factory().EventName())
+	}
+
+	assert.Equal(t, []string{"permission.created", "permission.updated", "permission.revoked"}, names)
+}
@@ -0,0 +1,29 @@
+package permission_event
+
+import "time"
+
+type PermissionRevokedEvent struct {
+	PermissionID string
+	UserID       string
+	Modules      []string
+	Actions      []string
+	Date         time.Time
+}
+
+func NewPermissionRevokedEvent(permissionID string, userID string, modules []string, actions []string) *PermissionRevokedEvent {
+	return &PermissionRevokedEvent{
+		PermissionID: permissionID,
+		UserID:       userID,
+		Modules:      modules,
+		Actions:      actions,
+		Date:         time.Now(),
+	}
+}
+
+func (e *PermissionRevokedEvent) EventName() string {
+	return "permission.revoked"
+}
+
+func (e *PermissionRevokedEvent) OccurredOn() time.Time {
+	return e.Date
+}
@@ -0,0 +1,33 @@
+package permission_event
+
+import "time"
+
+type PermissionUpdatedEvent struct {
+	PermissionID string
+	UserID       string
+	Modules      []string
+	Actions      []string
+	Level        string
+	Description  string
+	Date         time.Time
+}
+
+func NewPermissionUpdatedEvent(permissionID string, userID string, modules []string, actions []string, level string, description string) *PermissionUpdatedEvent {
+	return &PermissionUpdatedEvent{
+		PermissionID: permissionID,
+		UserID:       userID,
+		Modules:      modules,
+		Actions:      actions,
+		Level:        level,
+		Description:  description,
+		Date:         time.Now(),
+	}
+}
+
+func (e *PermissionUpdatedEvent) EventName() string {
+	return "permission.updated"
+}
+
+func (e *PermissionUpdatedEvent) OccurredOn() time.Time {
+	return e.Date
+}
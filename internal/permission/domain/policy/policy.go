@@ -0,0 +1,218 @@
+package permission_policy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+var (
+	ErrNotFound    = errors.New("policy not found")
+	ErrInvalidRule = errors.New("invalid policy rule")
+)
+
+type Policy struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Rule        Rule   `json:"rule"`
+}
+
+func (p *Policy) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("policy name is required")
+	}
+	if err := p.Rule.Validate(); err != nil {
+		return fmt.Errorf("policy %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// Request is everything a rule may look at.
+type Request struct {
+	Grants []permission_entity.Grant
+	Claims map[string]interface{}
+	Params map[string]string
+	Time   time.Time
+}
+
+// Explanation mirrors the rule tree and records why each node held or not.
+type Explanation struct {
+	Rule     string        `json:"rule"`
+	Allowed  bool          `json:"allowed"`
+	Reason   string        `json:"reason"`
+	Children []Explanation `json:"children,omitempty"`
+}
+
+func (p *Policy) Evaluate(req Request) Explanation {
+	return p.Rule.Evaluate(req)
+}
+
+func (r Rule) Evaluate(req Request) Explanation {
+	switch {
+	case len(r.AllOf) > 0:
+		children := evaluateAll(r.AllOf, req)
+		for _, child := range children {
+			if !child.Allowed {
+				return Explanation{Rule: "allOf", Reason: "a required rule did not hold", Children: children}
+			}
+		}
+		return Explanation{Rule: "allOf", Allowed: true, Reason: "every rule held", Children: children}
+	case len(r.AnyOf) > 0:
+		children := evaluateAll(r.AnyOf, req)
+		for _, child := range children {
+			if child.Allowed {
+				return Explanation{Rule: "anyOf", Allowed: true, Reason: "at least one rule held", Children: children}
+			}
+		}
+		return Explanation{Rule: "anyOf", Reason: "no rule held", Children: children}
+	case r.Not != nil:
+		child := r.Not.Evaluate(req)
+		reason := "negated rule did not hold"
+		if child.Allowed {
+			reason = "negated rule held"
+		}
+		return Explanation{Rule: "not", Allowed: !child.Allowed, Reason: reason, Children: []Explanation{child}}
+	case r.Grant != nil:
+		return r.Grant.evaluate(req)
+	case r.Attribute != nil:
+		return r.Attribute.evaluate(req)
+	case r.Time != nil:
+		return r.Time.evaluate(req)
+	}
+	return Explanation{Rule: "empty", Reason: "rule is empty"}
+}
+
+// evaluateAll evaluates every rule rather than short-circuiting, so the
+// explanation shows each branch.
+func evaluateAll(rules []Rule, req Request) []Explanation {
+	children := make([]Explanation, 0, len(rules))
+	for _, rule := range rules {
+		children = append(children, rule.Evaluate(req))
+	}
+	return children
+}
+
+func (g *GrantCondition) evaluate(req Request) Explanation {
+	decision := permission_entity.Evaluate(req.Grants, g.Modules, g.Actions)
+	rule := fmt.Sprintf("grant %s:%s", strings.Join(g.Modules, "|"), strings.Join(g.Actions, "|"))
+	return Explanation{Rule: rule, Allowed: decision.Allowed, Reason: string(decision.Reason)}
+}
+
+func (a *AttributeCondition) evaluate(req Request) Explanation {
+	rule := fmt.Sprintf("attribute %s %s", a.Key, a.Operator)
+
+	left, ok := req.lookup(a.Key)
+	if a.Operator == OperatorExists {
+		if ok {
+			return Explanation{Rule: rule, Allowed: true, Reason: a.Key + " is present"}
+		}
+		return Explanation{Rule: rule, Reason: a.Key + " is missing"}
+	}
+	if !ok {
+		return Explanation{Rule: rule, Reason: a.Key + " is missing"}
+	}
+
+	right := a.Value
+	if a.Ref != "" {
+		rule += " " + a.Ref
+		if right, ok = req.lookup(a.Ref); !ok {
+			return Explanation{Rule: rule, Reason: a.Ref + " is missing"}
+		}
+	}
+
+	var allowed bool
+	switch a.Operator {
+	case OperatorEquals:
+		allowed = equal(left, right)
+	case OperatorNotEquals:
+		allowed = !equal(left, right)
+	case OperatorIn:
+		allowed = contains(right, left)
+	}
+
+	reason := fmt.Sprintf("%v %s %v", left, a.Operator, right)
+	if !allowed {
+		reason = "not " + reason
+	}
+	return Explanation{Rule: rule, Allowed: allowed, Reason: reason}
+}
+
+func (t *TimeCondition) evaluate(req Request) Explanation {
+	location, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		return Explanation{Rule: "time", Reason: "invalid timezone " + t.Timezone}
+	}
+	now := req.Time.In(location)
+
+	if len(t.Weekdays) > 0 {
+		onDay := false
+		for _, day := range t.Weekdays {
+			if weekdays[strings.ToLower(day)] == now.Weekday() {
+				onDay = true
+				break
+			}
+		}
+		if !onDay {
+			return Explanation{Rule: "time", Reason: now.Weekday().String() + " is not an allowed weekday"}
+		}
+	}
+
+	clock := now.Format("15:04")
+	if t.After != "" && clock < t.After {
+		return Explanation{Rule: "time", Reason: clock + " is before " + t.After}
+	}
+	if t.Before != "" && clock >= t.Before {
+		return Explanation{Rule: "time", Reason: clock + " is not before " + t.Before}
+	}
+	return Explanation{Rule: "time", Allowed: true, Reason: clock + " is within the allowed window"}
+}
+
+func (req Request) lookup(key string) (interface{}, bool) {
+	source, name, _ := strings.Cut(key, ".")
+	switch source {
+	case "claims":
+		value, ok := req.Claims[name]
+		if !ok || value == nil || value == "" {
+			return nil, false
+		}
+		return value, true
+	case "params":
+		value, ok := req.Params[name]
+		if !ok || value == "" {
+			return nil, false
+		}
+		return value, true
+	}
+	return nil, false
+}
+
+// equal compares loosely so that JSON numbers and strings from the policy
+// file match the claim and parameter types seen at runtime.
+func equal(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func contains(list interface{}, value interface{}) bool {
+	switch values := list.(type) {
+	case []interface{}:
+		for _, v := range values {
+			if equal(v, value) {
+				return true
+			}
+		}
+	case []string:
+		for _, v := range values {
+			if equal(v, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package permission_policy
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+func grants(values ...string) []permission_entity.Grant {
+	result := make([]permission_entity.Grant, 0, len(values))
+	for _, value := range values {
+		grant, err := permission_entity.ParseGrant(value)
+		if err != nil {
+			panic(err)
+		}
+		result = append(result, grant)
+	}
+	return result
+}
+
+// The grant rule must agree with ModuleAccessMiddleware for every route
+// declaration, so existing routes can move to policies unchanged.
+func TestRequireAccess_MatchesEvaluate(t *testing.T) {
+	grantSets := [][]permission_entity.Grant{
+		nil,
+		grants("users:read"),
+		grants("users:read:restricted", "users:update:restricted"),
+		grants("users:update", "users:update:denied"),
+		grants("users:read", "permissions:delete"),
+	}
+	requirements := []struct {
+		modules []string
+		actions []string
+	}{
+		{[]string{"users"}, nil},
+		{[]string{"users"}, []string{"read"}},
+		{[]string{"users"}, []string{"update"}},
+		{[]string{"users", "permissions"}, []string{"delete"}},
+	}
+
+	for _, set := range grantSets {
+		for _, req := range requirements {
+			expected := permission_entity.Evaluate(set, req.modules, req.actions)
+			explanation := RequireAccess(req.modules, req.actions).Evaluate(Request{Grants: set})
+
+			assert.Equal(t, expected.Allowed, explanation.Allowed, "%v %v %v", set, req.modules, req.actions)
+			assert.Equal(t, string(expected.Reason), explanation.Reason)
+		}
+	}
+}
+
+func TestRequireOwnership_MatchesOwnershipPolicy(t *testing.T) {
+	policy := permission_entity.NewOwnershipPolicy("users")
+	rule := RequireOwnership(policy, "id")
+
+	cases := []struct {
+		grants   []permission_entity.Grant
+		callerID string
+		ownerID  string
+	}{
+		{nil, "user-1", "user-1"},
+		{nil, "user-1", "user-2"},
+		{nil, "", ""},
+		{grants("users:admin"), "user-1", "user-2"},
+		{grants("users:admin:restricted"), "user-1", "user-2"},
+		{grants("users:admin", "users:admin:denied"), "user-1", "user-2"},
+	}
+
+	for _, tt := range cases {
+		expected := policy.Evaluate(tt.grants, tt.callerID, tt.ownerID)
+		explanation := rule.Evaluate(Request{
+			Grants: tt.grants,
+			Claims: map[string]interface{}{"userID": tt.callerID},
+			Params: map[string]string{"id": tt.ownerID},
+		})
+
+		assert.Equal(t, expected.Allowed, explanation.Allowed, "%v %s %s", tt.grants, tt.callerID, tt.ownerID)
+	}
+}
+
+func TestRule_Combinators(t *testing.T) {
+	read := RequireAccess([]string{"users"}, []string{"read"})
+	update := RequireAccess([]string{"users"}, []string{"update"})
+	req := Request{Grants: grants("users:read")}
+
+	all := AllOf(read, update).Evaluate(req)
+	assert.False(t, all.Allowed)
+	assert.Equal(t, "allOf", all.Rule)
+	assert.Len(t, all.Children, 2)
+	assert.True(t, all.Children[0].Allowed)
+	assert.Equal(t, "action not granted", all.Children[1].Reason)
+
+	anyOf := Rule{AnyOf: []Rule{update, read}}.Evaluate(req)
+	assert.True(t, anyOf.Allowed)
+	assert.Len(t, anyOf.Children, 2)
+
+	not := Rule{Not: &update}.Evaluate(req)
+	assert.True(t, not.Allowed)
+	assert.Equal(t, "negated rule did not hold", not.Reason)
+
+	assert.False(t, Rule{}.Evaluate(req).Allowed)
+}
+
+func TestAttributeCondition(t *testing.T) {
+	req := Request{
+		Claims: map[string]interface{}{
+			"userID":      "user-1",
+			"userEmail":   "ana@school.edu",
+			"permVersion": int64(3),
+			"modules":     []interface{}{"users", "grades"},
+		},
+		Params: map[string]string{"id": "user-1", "other": "user-2"},
+	}
+
+	tests := []struct {
+		name      string
+		condition AttributeCondition
+		allowed   bool
+		reason    string
+	}{
+		{name: "eq ref", condition: AttributeCondition{Key: "claims.userID", Operator: OperatorEquals, Ref: "params.id"}, allowed: true},
+		{name: "eq ref mismatch", condition: AttributeCondition{Key: "claims.userID", Operator: OperatorEquals, Ref: "params.other"}},
+		{name: "eq missing ref", condition: AttributeCondition{Key: "claims.userID", Operator: OperatorEquals, Ref: "params.missing"}, reason: "params.missing is missing"},
+		{name: "eq missing key", condition: AttributeCondition{Key: "claims.tenant", Operator: OperatorEquals, Value: "a"}, reason: "claims.tenant is missing"},
+		{name: "neq value", condition: AttributeCondition{Key: "claims.userID", Operator: OperatorNotEquals, Value: "user-2"}, allowed: true},
+		{name: "eq number from json", condition: AttributeCondition{Key: "claims.permVersion", Operator: OperatorEquals, Value: float64(3)}, allowed: true},
+		{name: "in list", condition: AttributeCondition{Key: "claims.userEmail", Operator: OperatorIn, Value: []interface{}{"ana@school.edu"}}, allowed: true},
+		{name: "in claim list", condition: AttributeCondition{Key: "params.module", Operator: OperatorIn, Ref: "claims.modules"}, reason: "params.module is missing"},
+		{name: "exists", condition: AttributeCondition{Key: "claims.userEmail", Operator: OperatorExists}, allowed: true},
+		{name: "not exists", condition: AttributeCondition{Key: "claims.tenant", Operator: OperatorExists}, reason: "claims.tenant is missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := tt.condition
+			explanation := Rule{Attribute: &condition}.Evaluate(req)
+
+			assert.Equal(t, tt.allowed, explanation.Allowed, explanation.Reason)
+			if tt.reason != "" {
+				assert.Equal(t, tt.reason, explanation.Reason)
+			}
+		})
+	}
+}
+
+func TestTimeCondition(t *testing.T) {
+	// Wednesday 10:30 UTC, 07:30 in São Paulo.
+	now := time.Date(2025, time.January, 8, 10, 30, 0, 0, time.UTC)
+	req := Request{Time: now}
+
+	tests := []struct {
+		name      string
+		condition TimeCondition
+		allowed   bool
+	}{
+		{name: "no restriction", condition: TimeCondition{}, allowed: true},
+		{name: "within window", condition: TimeCondition{After: "08:00", Before: "18:00"}, allowed: true},
+		{name: "before window", condition: TimeCondition{After: "11:00"}},
+		{name: "before is exclusive", condition: TimeCondition{Before: "10:30"}},
+		{name: "weekday", condition: TimeCondition{Weekdays: []string{"mon", "wed"}}, allowed: true},
+		{name: "weekend only", condition: TimeCondition{Weekdays: []string{"Sat", "sun"}}},
+		{name: "timezone", condition: TimeCondition{After: "08:00", Timezone: "America/Sao_Paulo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := tt.condition
+			explanation := Rule{Time: &condition}.Evaluate(req)
+
+			assert.Equal(t, tt.allowed, explanation.Allowed, explanation.Reason)
+		})
+	}
+}
+
+func TestPolicy_UnmarshalJSON(t *testing.T) {
+	document := `{
+		"name": "grades.update",
+		"rule": {"allOf": [
+			{"grant": {"modules": ["grades"], "actions": ["update"]}},
+			{"not": {"attribute": {"key": "params.status", "operator": "eq", "value": "closed"}}},
+			{"time": {"after": "07:00", "before": "19:00", "weekdays": ["mon", "tue", "wed", "thu", "fri"]}}
+		]}
+	}`
+
+	var policy Policy
+	assert.NoError(t, json.Unmarshal([]byte(document), &policy))
+	assert.NoError(t, policy.Validate())
+
+	req := Request{
+		Grants: grants("grades:update"),
+		Params: map[string]string{"status": "open"},
+		Time:   time.Date(2025, time.January, 8, 10, 0, 0, 0, time.UTC),
+	}
+	assert.True(t, policy.Evaluate(req).Allowed)
+
+	req.Params["status"] = "closed"
+	explanation := policy.Evaluate(req)
+	assert.False(t, explanation.Allowed)
+	assert.False(t, explanation.Children[1].Allowed)
+}
@@ -0,0 +1,177 @@
+package permission_policy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+// Rule is a node of the policy language. Exactly one field is set: AllOf,
+// AnyOf and Not combine other rules, Grant checks module/action pairs with
+// the same semantics as ModuleAccessMiddleware, Attribute compares request
+// attributes and Time restricts when the rule holds.
+type Rule struct {
+	AllOf     []Rule              `json:"allOf,omitempty"`
+	AnyOf     []Rule              `json:"anyOf,omitempty"`
+	Not       *Rule               `json:"not,omitempty"`
+	Grant     *GrantCondition     `json:"grant,omitempty"`
+	Attribute *AttributeCondition `json:"attribute,omitempty"`
+	Time      *TimeCondition      `json:"time,omitempty"`
+}
+
+type GrantCondition struct {
+	Modules []string `json:"modules"`
+	Actions []string `json:"actions,omitempty"`
+}
+
+type Operator string
+
+const (
+	OperatorEquals    Operator = "eq"
+	OperatorNotEquals Operator = "neq"
+	OperatorIn        Operator = "in"
+	OperatorExists    Operator = "exists"
+)
+
+// AttributeCondition compares the attribute at Key with either a literal
+// Value or the attribute at Ref. Keys are "claims.<name>" for values set by
+// the auth middleware and "params.<name>" for path parameters.
+type AttributeCondition struct {
+	Key      string      `json:"key"`
+	Operator Operator    `json:"operator"`
+	Value    interface{} `json:"value,omitempty"`
+	Ref      string      `json:"ref,omitempty"`
+}
+
+// TimeCondition holds between After and Before (HH:MM, Before exclusive) on
+// the listed Weekdays ("mon".."sun"), in Timezone or UTC. Empty fields do not
+// restrict.
+type TimeCondition struct {
+	After    string   `json:"after,omitempty"`
+	Before   string   `json:"before,omitempty"`
+	Weekdays []string `json:"weekdays,omitempty"`
+	Timezone string   `json:"timezone,omitempty"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// RequireAccess expresses ModuleAccessMiddleware(modules, actions).
+func RequireAccess(modules, actions []string) Rule {
+	return Rule{Grant: &GrantCondition{Modules: modules, Actions: actions}}
+}
+
+// RequireOwnership expresses OwnershipMiddleware with the owner taken from
+// the path parameter param.
+func RequireOwnership(policy permission_entity.OwnershipPolicy, param string) Rule {
+	return Rule{AnyOf: []Rule{
+		RequireAccess([]string{policy.Module}, []string{policy.OverrideAction}),
+		{Attribute: &AttributeCondition{Key: "claims.userID", Operator: OperatorEquals, Ref: "params." + param}},
+	}}
+}
+
+func AllOf(rules ...Rule) Rule {
+	return Rule{AllOf: rules}
+}
+
+func (r Rule) Validate() error {
+	set := 0
+	for _, present := range []bool{
+		len(r.AllOf) > 0, len(r.AnyOf) > 0, r.Not != nil, r.Grant != nil, r.Attribute != nil, r.Time != nil,
+	} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("rule must set exactly one of allOf, anyOf, not, grant, attribute or time")
+	}
+
+	switch {
+	case len(r.AllOf) > 0:
+		return validateAll(r.AllOf)
+	case len(r.AnyOf) > 0:
+		return validateAll(r.AnyOf)
+	case r.Not != nil:
+		return r.Not.Validate()
+	case r.Grant != nil:
+		if len(r.Grant.Modules) == 0 {
+			return errors.New("grant requires modules")
+		}
+	case r.Attribute != nil:
+		return r.Attribute.validate()
+	case r.Time != nil:
+		return r.Time.validate()
+	}
+	return nil
+}
+
+func validateAll(rules []Rule) error {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AttributeCondition) validate() error {
+	if !validKey(a.Key) {
+		return fmt.Errorf("invalid attribute key %q", a.Key)
+	}
+	if a.Ref != "" && !validKey(a.Ref) {
+		return fmt.Errorf("invalid attribute ref %q", a.Ref)
+	}
+
+	switch a.Operator {
+	case OperatorEquals, OperatorNotEquals:
+	case OperatorIn:
+		if _, ok := a.Value.([]interface{}); !ok && a.Ref == "" {
+			return errors.New("operator in requires a list value")
+		}
+	case OperatorExists:
+		return nil
+	default:
+		return fmt.Errorf("unknown operator %q", a.Operator)
+	}
+
+	if a.Value == nil && a.Ref == "" {
+		return fmt.Errorf("operator %s requires a value or ref", a.Operator)
+	}
+	return nil
+}
+
+func validKey(key string) bool {
+	source, name, ok := strings.Cut(key, ".")
+	return ok && name != "" && (source == "claims" || source == "params")
+}
+
+func (t *TimeCondition) validate() error {
+	for _, clock := range []string{t.After, t.Before} {
+		if clock == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", clock); err != nil {
+			return fmt.Errorf("invalid time %q, expected HH:MM", clock)
+		}
+	}
+	for _, day := range t.Weekdays {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid weekday %q", day)
+		}
+	}
+	if _, err := time.LoadLocation(t.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", t.Timezone)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package permission_policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule_Validate(t *testing.T) {
+	grant := RequireAccess([]string{"users"}, []string{"read"})
+
+	tests := []struct {
+		name     string
+		rule     Rule
+		expected string
+	}{
+		{name: "grant", rule: grant},
+		{name: "nested", rule: AllOf(grant, Rule{Not: &grant})},
+		{name: "empty", rule: Rule{}, expected: "exactly one"},
+		{name: "two kinds", rule: Rule{Grant: grant.Grant, Not: &grant}, expected: "exactly one"},
+		{name: "grant without modules", rule: Rule{Grant: &GrantCondition{}}, expected: "grant requires modules"},
+		{name: "invalid nested", rule: AllOf(grant, Rule{}), expected: "exactly one"},
+		{
+			name:     "unknown attribute source",
+			rule:     Rule{Attribute: &AttributeCondition{Key: "headers.x", Operator: OperatorExists}},
+			expected: "invalid attribute key",
+		},
+		{
+			name:     "invalid ref",
+			rule:     Rule{Attribute: &AttributeCondition{Key: "claims.userID", Operator: OperatorEquals, Ref: "userID"}},
+			expected: "invalid attribute ref",
+		},
+		{
+			name:     "unknown operator",
+			rule:     Rule{Attribute: &AttributeCondition{Key: "claims.userID", Operator: "like", Value: "x"}},
+			expected: "unknown operator",
+		},
+		{
+			name:     "missing value",
+			rule:     Rule{Attribute: &AttributeCondition{Key: "claims.userID", Operator: OperatorEquals}},
+			expected: "requires a value or ref",
+		},
+		{
+			name:     "in without list",
+			rule:     Rule{Attribute: &AttributeCondition{Key: "claims.userID", Operator: OperatorIn, Value: "x"}},
+			expected: "requires a list value",
+		},
+		{name: "invalid clock", rule: Rule{Time: &TimeCondition{After: "8am"}}, expected: "expected HH:MM"},
+		{name: "invalid weekday", rule: Rule{Time: &TimeCondition{Weekdays: []string{"funday"}}}, expected: "invalid weekday"},
+		{name: "invalid timezone", rule: Rule{Time: &TimeCondition{Timezone: "Mars/Base"}}, expected: "invalid timezone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	assert.ErrorContains(t, (&Policy{Rule: RequireAccess([]string{"users"}, nil)}).Validate(), "policy name is required")
+	assert.ErrorContains(t, (&Policy{Name: "users.read"}).Validate(), "policy users.read")
+	assert.NoError(t, (&Policy{Name: "users.read", Rule: RequireAccess([]string{"users"}, nil)}).Validate())
+}
@@ -0,0 +1,13 @@
+package permission_model
+
+import "time"
+
+type PermissionVersion struct {
+	UserID    string `gorm:"primaryKey"`
+	Version   int64
+	UpdatedAt time.Time
+}
+
+func (PermissionVersion) TableName() string {
+	return "permission_versions"
+}
@@ -0,0 +1,28 @@
+package permission_model
+
+import (
+	"encoding/json"
+	"time"
+
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+)
+
+type Policy struct {
+	Name        string `gorm:"primaryKey"`
+	Description string
+	Rule        string `gorm:"type:jsonb"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (Policy) TableName() string {
+	return "policies"
+}
+
+func (p *Policy) ToEntity() (*permission_policy.Policy, error) {
+	policy := &permission_policy.Policy{Name: p.Name, Description: p.Description}
+	if err := json.Unmarshal([]byte(p.Rule), &policy.Rule); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
@@ -4,6 +4,7 @@ import (
 	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	permission_model "github.com/williamkoller/system-education/internal/permission/infra/db/model"
 	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	"github.com/williamkoller/system-education/shared/infra/outbox"
 	"gorm.io/gorm"
 )
 
@@ -17,9 +18,17 @@ func NewPermissionGormRepository(db *gorm.DB) *PermissionGormRepository {
 
 var _ port_permission_repository.PermissionRepository = &PermissionGormRepository{}
 
+// Save writes the permission's pending domain events to the outbox in the
+// same transaction as the permission.
 func (r *PermissionGormRepository) Save(p *permission_entity.Permission) (*permission_entity.Permission, error) {
 	model := permission_model.FromEntity(p)
-	if err := r.DB.Create(&model).Error; err != nil {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model).Error; err != nil {
+			return err
+		}
+		return outbox.Append(tx, p.PullDomainEvents())
+	})
+	if err != nil {
 		return nil, err
 	}
 	return permission_model.ToEntity(model), nil
@@ -48,30 +57,41 @@ func (r *PermissionGormRepository) FindAll() ([]*permission_entity.Permission, e
 
 func (r *PermissionGormRepository) Update(id string, p *permission_entity.Permission) (*permission_entity.Permission, error) {
 	model := permission_model.FromEntity(p)
-	result := r.DB.Model(&permission_model.Permission{}).
-		Where("id = ?", id).
-		Updates(&model)
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&permission_model.Permission{}).
+			Where("id = ?", id).
+			Updates(&model)
 
-	if result.Error != nil {
-		return nil, result.Error
-	}
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return permission_entity.ErrNotFound
+		}
 
-	if result.RowsAffected == 0 {
-		return nil, permission_entity.ErrNotFound
+		return outbox.Append(tx, p.PullDomainEvents())
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return permission_model.ToEntity(model), nil
 }
 
-func (r *PermissionGormRepository) Delete(id string) error {
-	result := r.DB.Unscoped().Delete(&permission_model.Permission{}, "id = ?", id)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return permission_entity.ErrNotFound
-	}
-	return nil
+// Delete writes the permission.revoked event Revoke recorded to the outbox in
+// the same transaction as the removal.
+func (r *PermissionGormRepository) Delete(p *permission_entity.Permission) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Delete(&permission_model.Permission{}, "id = ?", p.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return permission_entity.ErrNotFound
+		}
+		return outbox.Append(tx, p.PullDomainEvents())
+	})
 }
 
 func (r *PermissionGormRepository) FindPermissionByUserID(userID string) ([]*permission_entity.Permission, error) {
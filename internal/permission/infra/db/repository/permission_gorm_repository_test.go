@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	permission_model "github.com/williamkoller/system-education/internal/permission/infra/db/model"
+	"github.com/williamkoller/system-education/shared/infra/outbox"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -26,7 +27,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&permission_model.Permission{})
+	err = db.AutoMigrate(&permission_model.Permission{}, &outbox.OutboxMessage{})
 	assert.NoError(t, err)
 
 	return db
@@ -54,6 +55,28 @@ func (s *PermissionGormRepositorySuite) TestCreate() {
 	s.Equal(permission.Description, createdPermission.Description)
 }
 
+func (s *PermissionGormRepositorySuite) TestCreate_WritesDomainEventsToOutbox() {
+	permission, err := permission_entity.NewPermission(&permission_entity.Permission{
+		ID:          "perm-1",
+		UserID:      "user-123",
+		Modules:     []string{"users"},
+		Actions:     []string{"read"},
+		Level:       "allowed",
+		Description: "test permission",
+	})
+	s.NoError(err)
+
+	_, err = s.repository.Save(permission)
+
+	s.NoError(err)
+	s.Empty(permission.PullDomainEvents())
+
+	var messages []outbox.OutboxMessage
+	s.NoError(s.db.Find(&messages).Error)
+	s.Len(messages, 1)
+	s.Equal("permission.created", messages[0].EventName)
+}
+
 func (s *PermissionGormRepositorySuite) TestCreate_Error() {
 	permission := &permission_entity.Permission{
 		ID:          "perm-2",
@@ -189,7 +212,7 @@ func (s *PermissionGormRepositorySuite) TestDelete() {
 	permission := &permission_entity.Permission{ID: "perm-8", UserID: "user-123"}
 	created, _ := s.repository.Save(permission)
 
-	err := s.repository.Delete(created.ID)
+	err := s.repository.Delete(created)
 
 	s.NoError(err)
 
@@ -198,8 +221,28 @@ func (s *PermissionGormRepositorySuite) TestDelete() {
 	s.Nil(found)
 }
 
+func (s *PermissionGormRepositorySuite) TestUpdateAndDelete_WriteDomainEventsToOutbox() {
+	created, err := s.repository.Save(&permission_entity.Permission{ID: "perm-8", UserID: "user-123", Modules: []string{"users"}, Actions: []string{"read"}, Level: "allowed"})
+	s.NoError(err)
+
+	level := "denied"
+	updated, err := created.UpdatePermission(nil, nil, &level, nil)
+	s.NoError(err)
+	_, err = s.repository.Update(updated.ID, updated)
+	s.NoError(err)
+
+	updated.Revoke()
+	s.NoError(s.repository.Delete(updated))
+	s.Empty(updated.PullDomainEvents())
+
+	var messages []outbox.OutboxMessage
+	s.NoError(s.db.Find(&messages).Error)
+	s.Len(messages, 2)
+	s.ElementsMatch([]string{"permission.updated", "permission.revoked"}, []string{messages[0].EventName, messages[1].EventName})
+}
+
 func (s *PermissionGormRepositorySuite) TestDelete_Error() {
-	err := s.repository.Delete("some-id")
+	err := s.repository.Delete(&permission_entity.Permission{ID: "some-id"})
 
 	s.Error(err)
 	s.Equal(permission_entity.ErrNotFound, err)
@@ -209,7 +252,7 @@ func (s *PermissionGormRepositorySuite) TestDelete_DBError() {
 	sqlDB, _ := s.db.DB()
 	sqlDB.Close()
 
-	err := s.repository.Delete("some-id")
+	err := s.repository.Delete(&permission_entity.Permission{ID: "some-id"})
 
 	s.Error(err)
 	s.NotEqual(permission_entity.ErrNotFound, err)
@@ -0,0 +1,49 @@
+package permission_repository
+
+import (
+	"errors"
+	"time"
+
+	permission_model "github.com/williamkoller/system-education/internal/permission/infra/db/model"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PermissionVersionGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewPermissionVersionGormRepository(db *gorm.DB) *PermissionVersionGormRepository {
+	return &PermissionVersionGormRepository{DB: db}
+}
+
+var _ port_permission_repository.PermissionVersionRepository = &PermissionVersionGormRepository{}
+
+func (r *PermissionVersionGormRepository) Current(userID string) (int64, error) {
+	var model permission_model.PermissionVersion
+	if err := r.DB.First(&model, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return model.Version, nil
+}
+
+func (r *PermissionVersionGormRepository) Increment(userID string) (int64, error) {
+	model := permission_model.PermissionVersion{UserID: userID, Version: 1, UpdatedAt: time.Now()}
+
+	err := r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"version":    gorm.Expr("permission_versions.version + 1"),
+			"updated_at": model.UpdatedAt,
+		}),
+	}).Create(&model).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return r.Current(userID)
+}
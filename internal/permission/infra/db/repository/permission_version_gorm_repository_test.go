@@ -0,0 +1,52 @@
+package permission_repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	permission_model "github.com/williamkoller/system-education/internal/permission/infra/db/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type PermissionVersionGormRepositorySuite struct {
+	suite.Suite
+	repository *PermissionVersionGormRepository
+}
+
+func (s *PermissionVersionGormRepositorySuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(db.AutoMigrate(&permission_model.PermissionVersion{}))
+
+	s.repository = NewPermissionVersionGormRepository(db)
+}
+
+func (s *PermissionVersionGormRepositorySuite) TestCurrent_DefaultsToZero() {
+	version, err := s.repository.Current("user-1")
+
+	s.NoError(err)
+	s.Equal(int64(0), version)
+}
+
+func (s *PermissionVersionGormRepositorySuite) TestIncrement() {
+	version, err := s.repository.Increment("user-1")
+	s.NoError(err)
+	s.Equal(int64(1), version)
+
+	version, err = s.repository.Increment("user-1")
+	s.NoError(err)
+	s.Equal(int64(2), version)
+
+	version, err = s.repository.Current("user-1")
+	s.NoError(err)
+	s.Equal(int64(2), version)
+
+	other, err := s.repository.Current("user-2")
+	s.NoError(err)
+	s.Equal(int64(0), other)
+}
+
+func TestPermissionVersionGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(PermissionVersionGormRepositorySuite))
+}
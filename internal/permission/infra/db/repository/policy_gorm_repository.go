@@ -0,0 +1,37 @@
+package permission_repository
+
+import (
+	"fmt"
+
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+	permission_model "github.com/williamkoller/system-education/internal/permission/infra/db/model"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	"gorm.io/gorm"
+)
+
+type PolicyGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewPolicyGormRepository(db *gorm.DB) *PolicyGormRepository {
+	return &PolicyGormRepository{DB: db}
+}
+
+var _ port_permission_repository.PolicyRepository = &PolicyGormRepository{}
+
+func (r *PolicyGormRepository) FindAll() ([]*permission_policy.Policy, error) {
+	var models []*permission_model.Policy
+	if err := r.DB.Order("name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	policies := make([]*permission_policy.Policy, 0, len(models))
+	for _, model := range models {
+		policy, err := model.ToEntity()
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule for policy %s: %w", model.Name, err)
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
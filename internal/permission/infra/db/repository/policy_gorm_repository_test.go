@@ -0,0 +1,64 @@
+package permission_repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+	permission_model "github.com/williamkoller/system-education/internal/permission/infra/db/model"
+	"gorm.io/gorm"
+)
+
+type PolicyGormRepositorySuite struct {
+	suite.Suite
+	db         *gorm.DB
+	repository *PolicyGormRepository
+}
+
+func (s *PolicyGormRepositorySuite) SetupTest() {
+	s.db = setupTestDB(s.T())
+	s.NoError(s.db.AutoMigrate(&permission_model.Policy{}))
+	s.repository = NewPolicyGormRepository(s.db)
+}
+
+func (s *PolicyGormRepositorySuite) TestFindAll() {
+	s.db.Create(&permission_model.Policy{
+		Name: "users.read",
+		Rule: `{"grant": {"modules": ["users"], "actions": ["read"]}}`,
+	})
+	s.db.Create(&permission_model.Policy{
+		Name: "grades.read",
+		Rule: `{"not": {"attribute": {"key": "claims.userID", "operator": "exists"}}}`,
+	})
+
+	policies, err := s.repository.FindAll()
+
+	s.NoError(err)
+	s.Len(policies, 2)
+	s.Equal("grades.read", policies[0].Name)
+	s.Equal(permission_policy.OperatorExists, policies[0].Rule.Not.Attribute.Operator)
+	s.Equal(permission_policy.RequireAccess([]string{"users"}, []string{"read"}), policies[1].Rule)
+}
+
+func (s *PolicyGormRepositorySuite) TestFindAll_InvalidRule() {
+	s.db.Create(&permission_model.Policy{Name: "broken", Rule: "{"})
+
+	policies, err := s.repository.FindAll()
+
+	s.ErrorContains(err, "invalid rule for policy broken")
+	s.Nil(policies)
+}
+
+func (s *PolicyGormRepositorySuite) TestFindAll_DBError() {
+	sqlDB, _ := s.db.DB()
+	sqlDB.Close()
+
+	policies, err := s.repository.FindAll()
+
+	s.Error(err)
+	s.Nil(policies)
+}
+
+func TestPolicyGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(PolicyGormRepositorySuite))
+}
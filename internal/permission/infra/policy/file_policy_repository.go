@@ -0,0 +1,41 @@
+package infra_policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+)
+
+// FilePolicyRepository reads policies from a JSON document of the form
+// {"policies": [{"name": ..., "rule": ...}]}. The file is read on every
+// FindAll; callers cache.
+type FilePolicyRepository struct {
+	path string
+}
+
+type policyFile struct {
+	Policies []*permission_policy.Policy `json:"policies"`
+}
+
+var _ port_permission_repository.PolicyRepository = &FilePolicyRepository{}
+
+func NewFilePolicyRepository(path string) *FilePolicyRepository {
+	return &FilePolicyRepository{path: path}
+}
+
+func (r *FilePolicyRepository) FindAll() ([]*permission_policy.Policy, error) {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file policyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", r.path, err)
+	}
+
+	return file.Policies, nil
+}
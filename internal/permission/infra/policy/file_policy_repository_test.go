@@ -0,0 +1,50 @@
+package infra_policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+)
+
+func TestFindAll_BundledPolicies(t *testing.T) {
+	policies, err := NewFilePolicyRepository("../../../../config/policies.json").FindAll()
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, policies)
+	for _, policy := range policies {
+		assert.NoError(t, policy.Validate())
+	}
+}
+
+// The bundled users.update policy must keep the behavior of the route
+// declaration it replaced: ModuleAccessMiddleware(users, update) + ownership
+// on :id.
+func TestFindAll_BundledPoliciesMatchRoutes(t *testing.T) {
+	policies, err := NewFilePolicyRepository("../../../../config/policies.json").FindAll()
+	assert.NoError(t, err)
+
+	byName := map[string]*permission_policy.Policy{}
+	for _, policy := range policies {
+		byName[policy.Name] = policy
+	}
+	expected := permission_policy.AllOf(
+		permission_policy.RequireAccess([]string{"users"}, []string{"update"}),
+		permission_policy.RequireOwnership(permission_entity.NewOwnershipPolicy("users"), "id"),
+	)
+	assert.Equal(t, expected, byName["users.update"].Rule)
+}
+
+func TestFindAll_Errors(t *testing.T) {
+	_, err := NewFilePolicyRepository(filepath.Join(t.TempDir(), "missing.json")).FindAll()
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "policies.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+
+	_, err = NewFilePolicyRepository(path).FindAll()
+	assert.ErrorContains(t, err, "invalid policy file")
+}
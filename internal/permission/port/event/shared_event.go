@@ -0,0 +1,8 @@
+package port_permission_event
+
+import shared_event "github.com/williamkoller/system-education/shared/domain/event"
+
+type Dispatcher interface {
+	Dispatch(event interface{})
+	Register(eventName string, handler shared_event.Handler)
+}
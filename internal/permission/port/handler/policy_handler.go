@@ -0,0 +1,7 @@
+package port_permission_handler
+
+import "github.com/gin-gonic/gin"
+
+type PolicyHandler interface {
+	EvaluatePolicy(c *gin.Context)
+}
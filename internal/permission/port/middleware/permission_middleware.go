@@ -2,8 +2,23 @@ package port_permission_middleware
 
 import (
 	"github.com/gin-gonic/gin"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 )
 
+// OwnerResolver returns the ID of the user who owns the record addressed by
+// the request, or an empty string when the record does not exist.
+type OwnerResolver func(c *gin.Context) (string, error)
+
 type PermissionMiddleware interface {
 	ModuleAccessMiddleware(requiredModules []string, requiredActions []string) gin.HandlerFunc
+	OwnershipMiddleware(policy permission_entity.OwnershipPolicy, owner OwnerResolver) gin.HandlerFunc
+	PolicyMiddleware(name string) gin.HandlerFunc
+}
+
+// OwnerFromParam resolves the owner directly from a path parameter, for
+// routes addressed by user ID.
+func OwnerFromParam(name string) OwnerResolver {
+	return func(c *gin.Context) (string, error) {
+		return c.Param(name), nil
+	}
 }
@@ -7,6 +7,6 @@ type PermissionRepository interface {
 	FindAll() ([]*permission_entity.Permission, error)
 	FindPermissionByUserID(userID string) ([]*permission_entity.Permission, error)
 	Update(id string, p *permission_entity.Permission) (*permission_entity.Permission, error)
-	Delete(id string) error
+	Delete(p *permission_entity.Permission) error
 	FindByID(id string) (*permission_entity.Permission, error)
 }
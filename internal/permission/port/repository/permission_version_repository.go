@@ -0,0 +1,8 @@
+package port_permission_repository
+
+type PermissionVersionRepository interface {
+	// Current returns the user's permission version, or 0 when their
+	// permissions have never changed.
+	Current(userID string) (int64, error)
+	Increment(userID string) (int64, error)
+}
@@ -0,0 +1,7 @@
+package port_permission_repository
+
+import permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+
+type PolicyRepository interface {
+	FindAll() ([]*permission_policy.Policy, error)
+}
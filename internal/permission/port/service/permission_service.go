@@ -0,0 +1,19 @@
+package port_permission_service
+
+import permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+
+type PermissionService interface {
+	// Resolve returns the user's effective permissions, reloading them when
+	// the cached copy is older than minVersion.
+	Resolve(userID string, minVersion int64) (*permission_entity.EffectivePermissions, error)
+	// Refresh reloads the user's effective permissions, bypassing the cache.
+	Refresh(userID string) (*permission_entity.EffectivePermissions, error)
+	// Invalidate bumps the user's permission version and drops any cached copy.
+	Invalidate(userID string) error
+}
+
+// PermissionSource contributes permissions to a user's effective set besides
+// the ones granted directly, e.g. those inherited from roles.
+type PermissionSource interface {
+	PermissionsForUser(userID string) ([]*permission_entity.Permission, error)
+}
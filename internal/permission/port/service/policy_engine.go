@@ -0,0 +1,9 @@
+package port_permission_service
+
+import permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+
+type PolicyEngine interface {
+	// Evaluate runs the named policy. It returns permission_policy.ErrNotFound
+	// for unknown names.
+	Evaluate(name string, req permission_policy.Request) (permission_policy.Explanation, error)
+}
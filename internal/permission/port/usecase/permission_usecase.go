@@ -6,10 +6,10 @@ import (
 )
 
 type PermissionUsecase interface {
-	Create(input permission_dtos.AddPermissionDto) (*permission_entity.Permission, error)
+	Create(actorID string, input permission_dtos.AddPermissionDto) (*permission_entity.Permission, error)
 	FindAll() ([]*permission_entity.Permission, error)
 	FindById(id string) (*permission_entity.Permission, error)
-	Update(id string, input permission_dtos.UpdatePermissionDto) (*permission_entity.Permission, error)
-	Delete(id string) error
+	Update(actorID string, id string, input permission_dtos.UpdatePermissionDto) (*permission_entity.Permission, error)
+	Delete(actorID string, id string) error
 	FindPermissionByUserID(userID string) ([]*permission_entity.Permission, error)
 }
@@ -0,0 +1,10 @@
+package port_permission_usecase
+
+import (
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+	permission_dtos "github.com/williamkoller/system-education/internal/permission/presentation/dtos"
+)
+
+type PolicyUsecase interface {
+	Evaluate(input permission_dtos.EvaluatePolicyDto) (*permission_policy.Explanation, error)
+}
@@ -0,0 +1,18 @@
+package permission_dtos
+
+import (
+	"time"
+
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+)
+
+// EvaluatePolicyDto describes a dry run: either a stored Policy by name or an
+// inline Rule, evaluated for UserID's current permissions.
+type EvaluatePolicyDto struct {
+	Policy string                  `json:"policy"`
+	Rule   *permission_policy.Rule `json:"rule"`
+	UserID string                  `json:"user_id" binding:"required"`
+	Claims map[string]interface{}  `json:"claims"`
+	Params map[string]string       `json:"params"`
+	Time   *time.Time              `json:"time"`
+}
@@ -33,8 +33,13 @@ func (h *PermissionHandler) CreatePermission(c *gin.Context) {
 		return
 	}
 
-	p, err := h.usecase.Create(input)
+	p, err := h.usecase.Create(c.GetString("userID"), input)
 	if err != nil {
+		if errors.Is(err, permission_entity.ErrEscalation) {
+			c.Status(http.StatusForbidden)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
 		c.Status(http.StatusInternalServerError)
 		c.Error(err).SetType(gin.ErrorTypePublic)
 		return
@@ -80,13 +85,18 @@ func (h *PermissionHandler) UpdatePermission(c *gin.Context) {
 		return
 	}
 
-	p, err := h.usecase.Update(c.Param("id"), input)
+	p, err := h.usecase.Update(c.GetString("userID"), c.Param("id"), input)
 	if err != nil {
 		if errors.Is(err, permission_entity.ErrNotFound) {
 			c.Status(http.StatusNotFound)
 			c.Error(err).SetType(gin.ErrorTypePublic)
 			return
 		}
+		if errors.Is(err, permission_entity.ErrEscalation) {
+			c.Status(http.StatusForbidden)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
 		c.Status(http.StatusInternalServerError)
 		c.Error(err).SetType(gin.ErrorTypePublic)
 		return
@@ -98,12 +108,17 @@ func (h *PermissionHandler) UpdatePermission(c *gin.Context) {
 }
 
 func (h *PermissionHandler) DeletePermission(c *gin.Context) {
-	if err := h.usecase.Delete(c.Param("id")); err != nil {
+	if err := h.usecase.Delete(c.GetString("userID"), c.Param("id")); err != nil {
 		if errors.Is(err, permission_entity.ErrNotFound) {
 			c.Status(http.StatusNotFound)
 			c.Error(err).SetType(gin.ErrorTypePublic)
 			return
 		}
+		if errors.Is(err, permission_entity.ErrEscalation) {
+			c.Status(http.StatusForbidden)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
 		c.Status(http.StatusInternalServerError)
 		c.Error(err).SetType(gin.ErrorTypePublic)
 		return
@@ -0,0 +1,49 @@
+package permission_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	permission_mapper "github.com/williamkoller/system-education/internal/permission/application/mapper"
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
+	port_permission_handler "github.com/williamkoller/system-education/internal/permission/port/handler"
+	port_permission_usecase "github.com/williamkoller/system-education/internal/permission/port/usecase"
+	permission_dtos "github.com/williamkoller/system-education/internal/permission/presentation/dtos"
+)
+
+type PolicyHandler struct {
+	usecase port_permission_usecase.PolicyUsecase
+}
+
+func NewPolicyHandler(usecase port_permission_usecase.PolicyUsecase) *PolicyHandler {
+	return &PolicyHandler{usecase: usecase}
+}
+
+var _ port_permission_handler.PolicyHandler = &PolicyHandler{}
+
+func (h *PolicyHandler) EvaluatePolicy(c *gin.Context) {
+	var input permission_dtos.EvaluatePolicyDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	explanation, err := h.usecase.Evaluate(input)
+	if err != nil {
+		switch {
+		case errors.Is(err, permission_policy.ErrInvalidRule):
+			c.Status(http.StatusBadRequest)
+		case errors.Is(err, permission_policy.ErrNotFound):
+			c.Status(http.StatusNotFound)
+		default:
+			c.Status(http.StatusInternalServerError)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, permission_mapper.ToEvaluation(input.Policy, explanation))
+}
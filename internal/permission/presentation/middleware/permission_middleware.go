@@ -1,102 +1,372 @@
 package permission_middleware
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
 	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
 )
 
 var _ port_permission_middleware.PermissionMiddleware = &PermissionMiddleware{}
 
-type PermissionMiddleware struct{}
+type PermissionMiddleware struct {
+	permissions  port_permission_service.PermissionService
+	policies     port_permission_service.PolicyEngine
+	flatMatching bool
+	now          func() time.Time
+}
+
+type Option func(*PermissionMiddleware)
 
-func NewPermissionMiddleware() *PermissionMiddleware {
-	return &PermissionMiddleware{}
+var decisionMessages = map[permission_entity.DecisionReason]string{
+	permission_entity.ReasonModuleNotGranted: "Access denied to required modules",
+	permission_entity.ReasonActionNotGranted: "Access denied to required actions",
+	permission_entity.ReasonDenied:           "Access denied by permission level",
+	permission_entity.ReasonRestricted:       "Access restricted to read-only",
+}
+
+// WithPermissionService lets the middleware resolve permissions for tokens
+// that carry a permission version instead of embedded modules and actions.
+func WithPermissionService(service port_permission_service.PermissionService) Option {
+	return func(m *PermissionMiddleware) {
+		m.permissions = service
+	}
+}
+
+// WithFlatMatching restores the legacy check, where any required module and
+// any required action are matched independently of each other. It exists for
+// tokens issued without grants and should not be used otherwise: an action
+// held on one module is then accepted on every other module.
+func WithFlatMatching() Option {
+	return func(m *PermissionMiddleware) {
+		m.flatMatching = true
+	}
+}
+
+// WithPolicyEngine enables PolicyMiddleware.
+func WithPolicyEngine(engine port_permission_service.PolicyEngine) Option {
+	return func(m *PermissionMiddleware) {
+		m.policies = engine
+	}
+}
+
+func NewPermissionMiddleware(opts ...Option) *PermissionMiddleware {
+	m := &PermissionMiddleware{now: time.Now}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 func (m *PermissionMiddleware) ModuleAccessMiddleware(requiredModules []string, requiredActions []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Validate modules
-		modulesInterface, ok := c.Get("modules")
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permissions not found in token"})
+		if !m.resolvePermissions(c) {
 			return
 		}
 
-		modulesSlice, ok := modulesInterface.([]interface{})
+		if m.flatMatching {
+			flatAccess(c, requiredModules, requiredActions)
+			return
+		}
+
+		grants, ok := grantsFromContext(c)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Invalid permissions format"})
 			return
 		}
 
-		userModules := make([]string, 0, len(modulesSlice))
-		for _, mod := range modulesSlice {
-			if modStr, ok := mod.(string); ok {
-				userModules = append(userModules, modStr)
-			}
+		decision := permission_entity.Evaluate(grants, requiredModules, requiredActions)
+		if !decision.Allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": decisionMessages[decision.Reason]})
+			return
 		}
 
-		// Check if user has at least one required module
-		hasModule := false
-		for _, required := range requiredModules {
-			for _, userMod := range userModules {
-				if userMod == required {
-					hasModule = true
-					break
-				}
-			}
-			if hasModule {
-				break
+		c.Next()
+	}
+}
+
+// OwnershipMiddleware lets the request through when the caller owns the
+// addressed record or holds the policy's override grant. It is meant to run
+// after ModuleAccessMiddleware, which has already resolved the grants.
+func (m *PermissionMiddleware) OwnershipMiddleware(policy permission_entity.OwnershipPolicy, owner port_permission_middleware.OwnerResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.resolvePermissions(c) {
+			return
+		}
+
+		// Tokens without grants (flat matching) can still act on their own
+		// records, they just cannot override the owner check.
+		var grants []permission_entity.Grant
+		if _, ok := c.Get("grants"); ok {
+			if grants, ok = grantsFromContext(c); !ok {
+				return
 			}
 		}
 
-		if !hasModule {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied to required modules"})
+		if policy.Overridden(grants) {
+			c.Next()
 			return
 		}
 
-		// Validate actions (if required)
-		if len(requiredActions) > 0 {
-			actionsInterface, ok := c.Get("actions")
-			if !ok {
-				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Actions not found in token"})
+		ownerID, err := owner(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "could not resolve resource owner"})
+			return
+		}
+
+		if !policy.Evaluate(grants, c.GetString("userID"), ownerID).Allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied to resources owned by other users"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// PolicyMiddleware evaluates the named policy against the caller's grants,
+// the values set by the auth middleware and the path parameters. Under
+// WithFlatMatching the grants are every held module paired with every held
+// action, the same reading ModuleAccessMiddleware gives those claims.
+func (m *PermissionMiddleware) PolicyMiddleware(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.policies == nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "policy engine not configured"})
+			return
+		}
+
+		if !m.resolvePermissions(c) {
+			return
+		}
+
+		var grants []permission_entity.Grant
+		if m.flatMatching {
+			grants = flatGrants(c)
+		} else if _, ok := c.Get("grants"); ok {
+			if grants, ok = grantsFromContext(c); !ok {
 				return
 			}
+		}
+
+		params := make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			params[param.Key] = param.Value
+		}
+
+		claims := make(map[string]interface{}, len(c.Keys))
+		for key, value := range c.Keys {
+			if name, ok := key.(string); ok {
+				claims[name] = value
+			}
+		}
 
-			actionsSlice, ok := actionsInterface.([]interface{})
-			if !ok {
-				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Invalid actions format"})
+		explanation, err := m.policies.Evaluate(name, permission_policy.Request{
+			Grants: grants,
+			Claims: claims,
+			Params: params,
+			Time:   m.now(),
+		})
+		if err != nil {
+			if errors.Is(err, permission_policy.ErrNotFound) {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "policy not found: " + name})
 				return
 			}
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "could not evaluate policy"})
+			return
+		}
 
-			userActions := make([]string, 0, len(actionsSlice))
-			for _, act := range actionsSlice {
-				if actStr, ok := act.(string); ok {
-					userActions = append(userActions, actStr)
-				}
+		if !explanation.Allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied by policy " + name})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// grantsFromContext parses the caller's grants, aborting the request when
+// they are missing or malformed. Invalid entries are skipped.
+func grantsFromContext(c *gin.Context) ([]permission_entity.Grant, bool) {
+	grantsInterface, ok := c.Get("grants")
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permissions not found in token"})
+		return nil, false
+	}
+
+	grantsSlice, ok := grantsInterface.([]interface{})
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Invalid permissions format"})
+		return nil, false
+	}
+
+	grants := make([]permission_entity.Grant, 0, len(grantsSlice))
+	for _, value := range grantsSlice {
+		grantStr, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if grant, err := permission_entity.ParseGrant(grantStr); err == nil {
+			grants = append(grants, grant)
+		}
+	}
+	return grants, true
+}
+
+// flatGrants pairs the modules and actions claims. Missing or malformed
+// claims yield no grants, which every grant condition denies.
+func flatGrants(c *gin.Context) []permission_entity.Grant {
+	modules, _ := c.Get("modules")
+	actions, _ := c.Get("actions")
+	moduleSlice, _ := modules.([]interface{})
+	actionSlice, _ := actions.([]interface{})
+
+	var grants []permission_entity.Grant
+	for _, module := range moduleSlice {
+		moduleStr, ok := module.(string)
+		if !ok {
+			continue
+		}
+		for _, action := range actionSlice {
+			if actionStr, ok := action.(string); ok {
+				grants = append(grants, permission_entity.NewGrant(moduleStr, actionStr, permission_entity.LevelAllowed))
 			}
+		}
+	}
+	return grants
+}
 
-			// Check if user has at least one required action
-			hasAction := false
-			for _, required := range requiredActions {
-				for _, userAct := range userActions {
-					if userAct == required {
-						hasAction = true
-						break
-					}
-				}
-				if hasAction {
+func flatAccess(c *gin.Context, requiredModules []string, requiredActions []string) {
+	// Validate modules
+	modulesInterface, ok := c.Get("modules")
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permissions not found in token"})
+		return
+	}
+
+	modulesSlice, ok := modulesInterface.([]interface{})
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Invalid permissions format"})
+		return
+	}
+
+	userModules := make([]string, 0, len(modulesSlice))
+	for _, mod := range modulesSlice {
+		if modStr, ok := mod.(string); ok {
+			userModules = append(userModules, modStr)
+		}
+	}
+
+	// Check if user has at least one required module
+	hasModule := false
+	for _, required := range requiredModules {
+		for _, userMod := range userModules {
+			if userMod == required {
+				hasModule = true
+				break
+			}
+		}
+		if hasModule {
+			break
+		}
+	}
+
+	if !hasModule {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied to required modules"})
+		return
+	}
+
+	// Validate actions (if required)
+	if len(requiredActions) > 0 {
+		actionsInterface, ok := c.Get("actions")
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Actions not found in token"})
+			return
+		}
+
+		actionsSlice, ok := actionsInterface.([]interface{})
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Invalid actions format"})
+			return
+		}
+
+		userActions := make([]string, 0, len(actionsSlice))
+		for _, act := range actionsSlice {
+			if actStr, ok := act.(string); ok {
+				userActions = append(userActions, actStr)
+			}
+		}
+
+		// Check if user has at least one required action
+		hasAction := false
+		for _, required := range requiredActions {
+			for _, userAct := range userActions {
+				if userAct == required {
+					hasAction = true
 					break
 				}
 			}
-
-			if !hasAction {
-				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied to required actions"})
-				return
+			if hasAction {
+				break
 			}
 		}
 
-		c.Next()
+		if !hasAction {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied to required actions"})
+			return
+		}
+	}
+
+	c.Next()
+}
+
+// resolvePermissions loads the caller's modules and actions into the context
+// when the token references them by version. It reports false when the
+// request was aborted.
+func (m *PermissionMiddleware) resolvePermissions(c *gin.Context) bool {
+	if m.permissions == nil {
+		return true
+	}
+	if _, embedded := c.Get("modules"); embedded {
+		return true
+	}
+
+	version, ok := c.Get("permVersion")
+	if !ok {
+		return true
+	}
+	minVersion, _ := version.(int64)
+	userID := c.GetString("userID")
+	if userID == "" {
+		return true
+	}
+
+	effective, err := m.permissions.Resolve(userID, minVersion)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "could not resolve permissions"})
+		return false
+	}
+
+	c.Set("modules", toInterfaces(effective.Modules()))
+	c.Set("actions", toInterfaces(effective.Actions()))
+	c.Set("grants", toInterfaces(grantStrings(effective.Grants())))
+	return true
+}
+
+func grantStrings(grants []permission_entity.Grant) []string {
+	result := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		result = append(result, grant.String())
+	}
+	return result
+}
+
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		result = append(result, v)
 	}
+	return result
 }
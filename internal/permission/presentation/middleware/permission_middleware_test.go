@@ -1,12 +1,16 @@
 package permission_middleware
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	permission_policy "github.com/williamkoller/system-education/internal/permission/domain/policy"
 )
 
 func TestNewPermissionMiddleware(t *testing.T) {
@@ -19,7 +23,7 @@ func TestNewPermissionMiddleware(t *testing.T) {
 func TestModuleAccessMiddleware_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin", "user"}
 
 	// Create a test router
@@ -48,7 +52,7 @@ func TestModuleAccessMiddleware_Success(t *testing.T) {
 func TestModuleAccessMiddleware_NoModulesInContext(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 
 	router := gin.New()
@@ -68,7 +72,7 @@ func TestModuleAccessMiddleware_NoModulesInContext(t *testing.T) {
 func TestModuleAccessMiddleware_InvalidModulesFormat(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 
 	router := gin.New()
@@ -95,7 +99,7 @@ func TestModuleAccessMiddleware_InvalidModulesFormat(t *testing.T) {
 func TestModuleAccessMiddleware_UserHasRequiredModule(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin", "user"}
 
 	router := gin.New()
@@ -121,7 +125,7 @@ func TestModuleAccessMiddleware_UserHasRequiredModule(t *testing.T) {
 func TestModuleAccessMiddleware_UserDoesNotHaveRequiredModule(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin", "superuser"}
 
 	router := gin.New()
@@ -147,7 +151,7 @@ func TestModuleAccessMiddleware_UserDoesNotHaveRequiredModule(t *testing.T) {
 func TestModuleAccessMiddleware_EmptyModulesList(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 
 	router := gin.New()
@@ -173,7 +177,7 @@ func TestModuleAccessMiddleware_EmptyModulesList(t *testing.T) {
 func TestModuleAccessMiddleware_MultipleModulesMatch(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin", "user", "reports"}
 
 	router := gin.New()
@@ -199,7 +203,7 @@ func TestModuleAccessMiddleware_MultipleModulesMatch(t *testing.T) {
 func TestModuleAccessMiddleware_ModulesWithNonStringValues(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 
 	router := gin.New()
@@ -227,7 +231,7 @@ func TestModuleAccessMiddleware_ModulesWithNonStringValues(t *testing.T) {
 func TestModuleAccessMiddleware_NilModuleValue(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 
 	router := gin.New()
@@ -253,7 +257,7 @@ func TestModuleAccessMiddleware_NilModuleValue(t *testing.T) {
 func TestModuleAccessMiddleware_SingleRequiredModuleMatch(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"reports"}
 
 	router := gin.New()
@@ -279,7 +283,7 @@ func TestModuleAccessMiddleware_SingleRequiredModuleMatch(t *testing.T) {
 func TestModuleAccessMiddleware_CaseSensitiveModules(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"Admin"}
 
 	router := gin.New()
@@ -307,7 +311,7 @@ func TestModuleAccessMiddleware_CaseSensitiveModules(t *testing.T) {
 func TestModuleAccessMiddleware_SpecialCharactersInModules(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin-panel", "user_management"}
 
 	router := gin.New()
@@ -333,7 +337,7 @@ func TestModuleAccessMiddleware_SpecialCharactersInModules(t *testing.T) {
 func TestModuleAccessMiddleware_EmptyRequiredModules(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{} // Empty required modules
 
 	router := gin.New()
@@ -360,7 +364,7 @@ func TestModuleAccessMiddleware_EmptyRequiredModules(t *testing.T) {
 func TestModuleAccessMiddleware_NumericModuleNames(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"module1", "module2"}
 
 	router := gin.New()
@@ -386,7 +390,7 @@ func TestModuleAccessMiddleware_NumericModuleNames(t *testing.T) {
 func TestModuleAccessMiddleware_FirstMatchWins(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin", "user", "reports"}
 
 	router := gin.New()
@@ -415,7 +419,7 @@ func TestModuleAccessMiddleware_FirstMatchWins(t *testing.T) {
 func TestModuleAccessMiddleware_WithValidAction(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"read", "delete"}
 
@@ -443,7 +447,7 @@ func TestModuleAccessMiddleware_WithValidAction(t *testing.T) {
 func TestModuleAccessMiddleware_WithoutRequiredAction(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"delete"}
 
@@ -471,7 +475,7 @@ func TestModuleAccessMiddleware_WithoutRequiredAction(t *testing.T) {
 func TestModuleAccessMiddleware_NoActionsInContext(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"read"}
 
@@ -499,7 +503,7 @@ func TestModuleAccessMiddleware_NoActionsInContext(t *testing.T) {
 func TestModuleAccessMiddleware_InvalidActionsFormat(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"read"}
 
@@ -527,7 +531,7 @@ func TestModuleAccessMiddleware_InvalidActionsFormat(t *testing.T) {
 func TestModuleAccessMiddleware_EmptyActionsInContext(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"read"}
 
@@ -555,7 +559,7 @@ func TestModuleAccessMiddleware_EmptyActionsInContext(t *testing.T) {
 func TestModuleAccessMiddleware_MultipleActionsOneMatches(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"read", "delete", "update"}
 
@@ -583,7 +587,7 @@ func TestModuleAccessMiddleware_MultipleActionsOneMatches(t *testing.T) {
 func TestModuleAccessMiddleware_ActionsWithNonStringValues(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"read"}
 
@@ -613,7 +617,7 @@ func TestModuleAccessMiddleware_ActionsWithNonStringValues(t *testing.T) {
 func TestModuleAccessMiddleware_BackwardCompatibility_EmptyRequiredActions(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 	requiredActions := []string{} // Empty - should skip action validation
 
@@ -641,7 +645,7 @@ func TestModuleAccessMiddleware_BackwardCompatibility_EmptyRequiredActions(t *te
 func TestModuleAccessMiddleware_CaseSensitiveActions(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"Read"}
 
@@ -671,7 +675,7 @@ func TestModuleAccessMiddleware_CaseSensitiveActions(t *testing.T) {
 func TestModuleAccessMiddleware_AllCRUDActions(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"users"}
 	requiredActions := []string{"create", "read", "update", "delete"}
 
@@ -699,7 +703,7 @@ func TestModuleAccessMiddleware_AllCRUDActions(t *testing.T) {
 func TestModuleAccessMiddleware_ModuleMatchButNoActionMatch(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	middleware := NewPermissionMiddleware()
+	middleware := NewPermissionMiddleware(WithFlatMatching())
 	requiredModules := []string{"admin"}
 	requiredActions := []string{"delete"}
 
@@ -724,3 +728,523 @@ func TestModuleAccessMiddleware_ModuleMatchButNoActionMatch(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, w.Code)
 	assert.Contains(t, w.Body.String(), "Access denied to required actions")
 }
+
+type MockPermissionService struct {
+	mock.Mock
+}
+
+func (m *MockPermissionService) Resolve(userID string, minVersion int64) (*permission_entity.EffectivePermissions, error) {
+	args := m.Called(userID, minVersion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Refresh(userID string) (*permission_entity.EffectivePermissions, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Invalidate(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func serveWithReference(middleware *PermissionMiddleware, requiredModules, requiredActions []string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		c.Set("permVersion", int64(2))
+		c.Next()
+	})
+	router.GET("/test", middleware.ModuleAccessMiddleware(requiredModules, requiredActions), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	return w
+}
+
+func TestModuleAccessMiddleware_ResolvesReferencedPermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := new(MockPermissionService)
+	service.On("Resolve", "user-1", int64(2)).Return(permission_entity.NewEffectivePermissions("user-1", 2, []*permission_entity.Permission{
+		{Modules: []string{"users"}, Actions: []string{"read"}, Level: "allowed"},
+		{Modules: []string{"users"}, Actions: []string{"delete"}, Level: "denied"},
+	}), nil)
+	middleware := NewPermissionMiddleware(WithPermissionService(service))
+
+	w := serveWithReference(middleware, []string{"users"}, []string{"read"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serveWithReference(middleware, []string{"users"}, []string{"delete"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Access denied by permission level")
+
+	service.AssertExpectations(t)
+}
+
+func TestModuleAccessMiddleware_ReferencedPermissionsServiceError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := new(MockPermissionService)
+	service.On("Resolve", "user-1", int64(2)).Return(nil, errors.New("db error"))
+	middleware := NewPermissionMiddleware(WithPermissionService(service))
+
+	w := serveWithReference(middleware, []string{"users"}, nil)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "could not resolve permissions")
+}
+
+func TestModuleAccessMiddleware_ReferencedPermissionsWithoutService(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := serveWithReference(NewPermissionMiddleware(), []string{"users"}, nil)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Permissions not found in token")
+}
+
+func TestModuleAccessMiddleware_EmbeddedPermissionsSkipService(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	service := new(MockPermissionService)
+	middleware := NewPermissionMiddleware(WithPermissionService(service))
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		c.Set("modules", []interface{}{"users"})
+		c.Set("grants", []interface{}{"users:read"})
+		c.Next()
+	})
+	router.GET("/test", middleware.ModuleAccessMiddleware([]string{"users"}, nil), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	service.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything)
+}
+
+func serveWithClaims(middleware *PermissionMiddleware, claims map[string]interface{}, requiredModules, requiredActions []string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		for key, value := range claims {
+			c.Set(key, value)
+		}
+		c.Next()
+	})
+	router.GET("/test", middleware.ModuleAccessMiddleware(requiredModules, requiredActions), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	return w
+}
+
+func TestModuleAccessMiddleware_PairedGrants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// {users: [read]} and {permissions: [delete]}, as issued at login.
+	claims := map[string]interface{}{
+		"modules": []interface{}{"users", "permissions"},
+		"actions": []interface{}{"read", "delete"},
+		"grants":  []interface{}{"users:read", "permissions:delete"},
+	}
+
+	tests := []struct {
+		name            string
+		requiredModules []string
+		requiredActions []string
+		expectedStatus  int
+		expectedError   string
+	}{
+		{name: "action granted on the module", requiredModules: []string{"users"}, requiredActions: []string{"read"}, expectedStatus: http.StatusOK},
+		{name: "second grant", requiredModules: []string{"permissions"}, requiredActions: []string{"delete"}, expectedStatus: http.StatusOK},
+		{name: "module only", requiredModules: []string{"permissions"}, expectedStatus: http.StatusOK},
+		{name: "cross-module delete on users", requiredModules: []string{"users"}, requiredActions: []string{"delete"}, expectedStatus: http.StatusForbidden, expectedError: "Access denied to required actions"},
+		{name: "cross-module read on permissions", requiredModules: []string{"permissions"}, requiredActions: []string{"read"}, expectedStatus: http.StatusForbidden, expectedError: "Access denied to required actions"},
+		{name: "any-of modules still pairs", requiredModules: []string{"users", "reports"}, requiredActions: []string{"delete"}, expectedStatus: http.StatusForbidden, expectedError: "Access denied to required actions"},
+		{name: "any-of modules with a matching pair", requiredModules: []string{"reports", "permissions"}, requiredActions: []string{"delete"}, expectedStatus: http.StatusOK},
+		{name: "any-of actions with a matching pair", requiredModules: []string{"users"}, requiredActions: []string{"delete", "read"}, expectedStatus: http.StatusOK},
+		{name: "module not granted", requiredModules: []string{"reports"}, requiredActions: []string{"read"}, expectedStatus: http.StatusForbidden, expectedError: "Access denied to required modules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveWithClaims(NewPermissionMiddleware(), claims, tt.requiredModules, tt.requiredActions)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestModuleAccessMiddleware_FlatMatchingAllowsCrossModuleActions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	claims := map[string]interface{}{
+		"modules": []interface{}{"users", "permissions"},
+		"actions": []interface{}{"read", "delete"},
+		"grants":  []interface{}{"users:read", "permissions:delete"},
+	}
+
+	w := serveWithClaims(NewPermissionMiddleware(WithFlatMatching()), claims, []string{"users"}, []string{"delete"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serveWithClaims(NewPermissionMiddleware(), claims, []string{"users"}, []string{"delete"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestModuleAccessMiddleware_GrantsClaimErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		claims        map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:          "token without grants",
+			claims:        map[string]interface{}{"modules": []interface{}{"users"}, "actions": []interface{}{"read"}},
+			expectedError: "Permissions not found in token",
+		},
+		{
+			name:          "invalid grants format",
+			claims:        map[string]interface{}{"grants": "users:read"},
+			expectedError: "Invalid permissions format",
+		},
+		{
+			name:          "malformed and non-string grants are ignored",
+			claims:        map[string]interface{}{"grants": []interface{}{"users", 123, nil, ":read"}},
+			expectedError: "Access denied to required modules",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveWithClaims(NewPermissionMiddleware(), tt.claims, []string{"users"}, []string{"read"})
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedError)
+		})
+	}
+}
+
+func TestModuleAccessMiddleware_Levels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		grants          []interface{}
+		requiredModules []string
+		requiredActions []string
+		expectedStatus  int
+		expectedError   string
+	}{
+		{
+			name:            "denied grant blocks an allowed grant on the same pair",
+			grants:          []interface{}{"users:delete", "users:delete:denied"},
+			requiredModules: []string{"users"},
+			requiredActions: []string{"delete"},
+			expectedStatus:  http.StatusForbidden,
+			expectedError:   "Access denied by permission level",
+		},
+		{
+			name:            "denied grant only covers its own pair",
+			grants:          []interface{}{"users:read", "users:delete:denied"},
+			requiredModules: []string{"users"},
+			requiredActions: []string{"read"},
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:            "denied module is not granted",
+			grants:          []interface{}{"users:read:denied"},
+			requiredModules: []string{"users"},
+			expectedStatus:  http.StatusForbidden,
+			expectedError:   "Access denied to required modules",
+		},
+		{
+			name:            "restricted grant allows read",
+			grants:          []interface{}{"users:read:restricted"},
+			requiredModules: []string{"users"},
+			requiredActions: []string{"read"},
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:            "restricted grant does not allow writes",
+			grants:          []interface{}{"users:update:restricted"},
+			requiredModules: []string{"users"},
+			requiredActions: []string{"update"},
+			expectedStatus:  http.StatusForbidden,
+			expectedError:   "Access restricted to read-only",
+		},
+		{
+			name:            "unknown level is ignored",
+			grants:          []interface{}{"users:update:admin"},
+			requiredModules: []string{"users"},
+			requiredActions: []string{"update"},
+			expectedStatus:  http.StatusForbidden,
+			expectedError:   "Access denied to required modules",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := map[string]interface{}{"grants": tt.grants}
+
+			w := serveWithClaims(NewPermissionMiddleware(), claims, tt.requiredModules, tt.requiredActions)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func serveOwned(middleware *PermissionMiddleware, claims map[string]interface{}, path string, owner func(c *gin.Context) (string, error)) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		for key, value := range claims {
+			c.Set(key, value)
+		}
+		c.Next()
+	})
+	router.PUT("/users/:id",
+		middleware.ModuleAccessMiddleware([]string{"users"}, []string{"update"}),
+		middleware.OwnershipMiddleware(permission_entity.NewOwnershipPolicy("users"), owner),
+		func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "success"})
+		},
+	)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, path, nil))
+	return w
+}
+
+func TestOwnershipMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	fromParam := func(c *gin.Context) (string, error) { return c.Param("id"), nil }
+
+	tests := []struct {
+		name           string
+		claims         map[string]interface{}
+		path           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "owner may update own record",
+			claims:         map[string]interface{}{"userID": "user-1", "grants": []interface{}{"users:update"}},
+			path:           "/users/user-1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "update grant does not reach other records",
+			claims:         map[string]interface{}{"userID": "user-1", "grants": []interface{}{"users:update"}},
+			path:           "/users/user-2",
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "Access denied to resources owned by other users",
+		},
+		{
+			name:           "admin grant reaches other records",
+			claims:         map[string]interface{}{"userID": "user-1", "grants": []interface{}{"users:update", "users:admin"}},
+			path:           "/users/user-2",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "denied admin grant does not override",
+			claims:         map[string]interface{}{"userID": "user-1", "grants": []interface{}{"users:update", "users:admin", "users:admin:denied"}},
+			path:           "/users/user-2",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "module access is still required",
+			claims:         map[string]interface{}{"userID": "user-1", "grants": []interface{}{"users:read"}},
+			path:           "/users/user-1",
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "Access denied to required actions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveOwned(NewPermissionMiddleware(), tt.claims, tt.path, fromParam)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestOwnershipMiddleware_OwnerResolverError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	claims := map[string]interface{}{"userID": "user-1", "grants": []interface{}{"users:update"}}
+	w := serveOwned(NewPermissionMiddleware(), claims, "/users/user-1", func(c *gin.Context) (string, error) {
+		return "", errors.New("db error")
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "could not resolve resource owner")
+}
+
+func TestOwnershipMiddleware_AdminSkipsOwnerLookup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	called := false
+	claims := map[string]interface{}{"userID": "user-1", "grants": []interface{}{"users:update", "users:admin"}}
+	w := serveOwned(NewPermissionMiddleware(), claims, "/users/user-2", func(c *gin.Context) (string, error) {
+		called = true
+		return "user-2", nil
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, called)
+}
+
+func TestOwnershipMiddleware_FlatMatchingWithoutGrants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	fromParam := func(c *gin.Context) (string, error) { return c.Param("id"), nil }
+	claims := map[string]interface{}{
+		"userID":  "user-1",
+		"modules": []interface{}{"users"},
+		"actions": []interface{}{"update"},
+	}
+
+	w := serveOwned(NewPermissionMiddleware(WithFlatMatching()), claims, "/users/user-1", fromParam)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = serveOwned(NewPermissionMiddleware(WithFlatMatching()), claims, "/users/user-2", fromParam)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+type MockPolicyEngine struct {
+	mock.Mock
+}
+
+func (m *MockPolicyEngine) Evaluate(name string, req permission_policy.Request) (permission_policy.Explanation, error) {
+	args := m.Called(name, req)
+	return args.Get(0).(permission_policy.Explanation), args.Error(1)
+}
+
+func serveWithPolicy(middleware *PermissionMiddleware, claims map[string]interface{}, name string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		for key, value := range claims {
+			c.Set(key, value)
+		}
+		c.Next()
+	})
+	router.PUT("/users/:id", middleware.PolicyMiddleware(name), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/user-2", nil))
+	return w
+}
+
+func TestPolicyMiddleware_PassesRequestToEngine(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := new(MockPolicyEngine)
+	engine.On("Evaluate", "users.update", mock.MatchedBy(func(req permission_policy.Request) bool {
+		return req.Params["id"] == "user-2" &&
+			req.Claims["userID"] == "user-1" &&
+			len(req.Grants) == 1 && req.Grants[0].String() == "users:update" &&
+			!req.Time.IsZero()
+	})).Return(permission_policy.Explanation{Allowed: true}, nil)
+
+	claims := map[string]interface{}{"userID": "user-1", "grants": []interface{}{"users:update"}}
+	w := serveWithPolicy(NewPermissionMiddleware(WithPolicyEngine(engine)), claims, "users.update")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	engine.AssertExpectations(t)
+}
+
+func TestPolicyMiddleware_FlatMatchingPairsModulesAndActions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := new(MockPolicyEngine)
+	engine.On("Evaluate", "users.update", mock.MatchedBy(func(req permission_policy.Request) bool {
+		var grants []string
+		for _, grant := range req.Grants {
+			grants = append(grants, grant.String())
+		}
+		return assert.ObjectsAreEqual([]string{"users:read", "users:update", "roles:read", "roles:update"}, grants)
+	})).Return(permission_policy.Explanation{Allowed: true}, nil)
+
+	claims := map[string]interface{}{
+		"userID":  "user-1",
+		"modules": []interface{}{"users", "roles"},
+		"actions": []interface{}{"read", "update"},
+		"grants":  []interface{}{"users:read"},
+	}
+	w := serveWithPolicy(NewPermissionMiddleware(WithPolicyEngine(engine), WithFlatMatching()), claims, "users.update")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	engine.AssertExpectations(t)
+}
+
+func TestPolicyMiddleware_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		explanation    permission_policy.Explanation
+		err            error
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "denied", expectedStatus: http.StatusForbidden, expectedError: "Access denied by policy users.update"},
+		{name: "unknown policy", err: permission_policy.ErrNotFound, expectedStatus: http.StatusInternalServerError, expectedError: "policy not found: users.update"},
+		{name: "load error", err: errors.New("db error"), expectedStatus: http.StatusServiceUnavailable, expectedError: "could not evaluate policy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := new(MockPolicyEngine)
+			engine.On("Evaluate", "users.update", mock.Anything).Return(tt.explanation, tt.err)
+
+			w := serveWithPolicy(NewPermissionMiddleware(WithPolicyEngine(engine)), map[string]interface{}{"userID": "user-1"}, "users.update")
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedError)
+		})
+	}
+
+	t.Run("engine not configured", func(t *testing.T) {
+		w := serveWithPolicy(NewPermissionMiddleware(), nil, "users.update")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "policy engine not configured")
+	})
+
+	t.Run("malformed grants", func(t *testing.T) {
+		engine := new(MockPolicyEngine)
+		w := serveWithPolicy(NewPermissionMiddleware(WithPolicyEngine(engine)), map[string]interface{}{"grants": "users:update"}, "users.update")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		engine.AssertNotCalled(t, "Evaluate", mock.Anything, mock.Anything)
+	})
+}
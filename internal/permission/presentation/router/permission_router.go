@@ -1,37 +1,76 @@
 package permission_router
 
 import (
-	"time"
+	"errors"
 
 	"github.com/gin-gonic/gin"
-	infra_cryptography "github.com/williamkoller/system-education/internal/auth/infra/cryptography"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_revocation "github.com/williamkoller/system-education/internal/auth/port/revocation"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
 	permission_usecase "github.com/williamkoller/system-education/internal/permission/application/usecase"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
 	permission_repository "github.com/williamkoller/system-education/internal/permission/infra/db/repository"
+	port_permission_event "github.com/williamkoller/system-education/internal/permission/port/event"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+	port_permission_repository "github.com/williamkoller/system-education/internal/permission/port/repository"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
 	permission_handler "github.com/williamkoller/system-education/internal/permission/presentation/handler"
-	permission_middleware "github.com/williamkoller/system-education/internal/permission/presentation/middleware"
 	"gorm.io/gorm"
 )
 
-func PermissionRouter(e *gin.Engine, db *gorm.DB, secret string, expiresIn time.Duration) {
-	repo := permission_repository.NewPermissionGormRepository(db)
-	jwt := infra_cryptography.NewJWTTokenManager(secret, expiresIn)
+// Dependencies wires the permission routes.
+type Dependencies struct {
+	DB            *gorm.DB
+	TokenManager  port_auth_cryptography.TokenManager
+	Revocations   port_auth_revocation.RevocationStore
+	APIKeys       port_auth_usecase.APIKeyAuthenticator
+	Permissions   port_permission_service.PermissionService
+	Policies      port_permission_service.PolicyEngine
+	AccessControl port_permission_middleware.PermissionMiddleware
+	Events        port_permission_event.Dispatcher
+}
+
+func PermissionRouter(e *gin.Engine, deps Dependencies) {
+	repo := permission_repository.NewPermissionGormRepository(deps.DB)
 
-	usecase := permission_usecase.NewPermissionUsecase(repo)
+	usecase := permission_usecase.NewPermissionUsecase(repo, deps.Permissions, deps.Events)
 	handler := permission_handler.NewPermissionHandler(usecase)
-	middleware := permission_middleware.NewPermissionMiddleware()
+	policyHandler := permission_handler.NewPolicyHandler(permission_usecase.NewPolicyUsecase(deps.Policies, deps.Permissions))
+	authenticate := auth_middleware.AuthMiddleware(deps.TokenManager, auth_middleware.WithRevocationStore(deps.Revocations), auth_middleware.WithAPIKeys(deps.APIKeys))
+
+	// Only reads are opened to owners: letting users edit their own
+	// permissions would let them escalate.
+	ownership := permission_entity.NewOwnershipPolicy("permissions")
 
 	p := e.Group("/permissions")
 	{
-		p.POST("", handler.CreatePermission)
-		p.GET("", handler.FindAllPermission)
-		p.GET("/user/:user_id", auth_middleware.AuthMiddleware(jwt),
-			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}), handler.FindPermissionByUserID)
-		p.PUT("/:id", auth_middleware.AuthMiddleware(jwt),
-			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"update"}), handler.UpdatePermission)
-		p.DELETE("/:id", auth_middleware.AuthMiddleware(jwt),
-			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"delete"}), handler.DeletePermission)
-		p.GET("/:id", auth_middleware.AuthMiddleware(jwt),
-			middleware.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}), handler.FindPermissionById)
+		p.POST("", authenticate,
+			deps.AccessControl.ModuleAccessMiddleware([]string{"permissions"}, []string{"create"}), handler.CreatePermission)
+		p.GET("", authenticate,
+			deps.AccessControl.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}), handler.FindAllPermission)
+		p.POST("/evaluate", authenticate,
+			deps.AccessControl.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}), policyHandler.EvaluatePolicy)
+		p.GET("/user/:user_id", authenticate,
+			deps.AccessControl.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}),
+			deps.AccessControl.OwnershipMiddleware(ownership, port_permission_middleware.OwnerFromParam("user_id")), handler.FindPermissionByUserID)
+		p.PUT("/:id", authenticate, deps.AccessControl.PolicyMiddleware("permissions.update"), handler.UpdatePermission)
+		p.DELETE("/:id", authenticate, deps.AccessControl.PolicyMiddleware("permissions.delete"), handler.DeletePermission)
+		p.GET("/:id", authenticate,
+			deps.AccessControl.ModuleAccessMiddleware([]string{"permissions"}, []string{"read"}),
+			deps.AccessControl.OwnershipMiddleware(ownership, permissionOwner(repo)), handler.FindPermissionById)
+	}
+}
+
+func permissionOwner(repo port_permission_repository.PermissionRepository) port_permission_middleware.OwnerResolver {
+	return func(c *gin.Context) (string, error) {
+		permission, err := repo.FindByID(c.Param("id"))
+		if errors.Is(err, permission_entity.ErrNotFound) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return permission.UserID, nil
 	}
 }
@@ -0,0 +1,39 @@
+package role_mapper
+
+import (
+	"time"
+
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+)
+
+type RoleResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Modules     []string  `json:"modules"`
+	Actions     []string  `json:"actions"`
+	Level       string    `json:"level"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func ToRole(r *role_entity.Role) *RoleResponse {
+	return &RoleResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Modules:     r.Modules,
+		Actions:     r.Actions,
+		Level:       r.Level,
+		Description: r.Description,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func ToRoles(rs []*role_entity.Role) []*RoleResponse {
+	responses := make([]*RoleResponse, 0, len(rs))
+	for _, r := range rs {
+		responses = append(responses, ToRole(r))
+	}
+	return responses
+}
@@ -0,0 +1,47 @@
+package role_mapper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+)
+
+func TestToRole(t *testing.T) {
+	now := time.Now()
+	role := &role_entity.Role{
+		ID:          "role-1",
+		Name:        "teacher",
+		Modules:     []string{"classes"},
+		Actions:     []string{"read"},
+		Level:       "allowed",
+		Description: "class teachers",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	response := ToRole(role)
+
+	assert.Equal(t, role.ID, response.ID)
+	assert.Equal(t, role.Name, response.Name)
+	assert.Equal(t, role.Modules, response.Modules)
+	assert.Equal(t, role.Actions, response.Actions)
+	assert.Equal(t, role.Level, response.Level)
+	assert.Equal(t, role.Description, response.Description)
+	assert.Equal(t, role.CreatedAt, response.CreatedAt)
+	assert.Equal(t, role.UpdatedAt, response.UpdatedAt)
+}
+
+func TestToRoles(t *testing.T) {
+	roles := []*role_entity.Role{
+		{ID: "role-1", Name: "teacher"},
+		{ID: "role-2", Name: "finance"},
+	}
+
+	responses := ToRoles(roles)
+
+	assert.Len(t, responses, 2)
+	assert.Equal(t, "role-1", responses[0].ID)
+	assert.Equal(t, "role-2", responses[1].ID)
+}
@@ -0,0 +1,32 @@
+package role_service
+
+import (
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+	port_role_repository "github.com/williamkoller/system-education/internal/role/port/repository"
+)
+
+// RolePermissionSource exposes the roles assigned to a user as permissions,
+// so the permission service merges them with direct grants.
+type RolePermissionSource struct {
+	repo port_role_repository.RoleRepository
+}
+
+var _ port_permission_service.PermissionSource = &RolePermissionSource{}
+
+func NewRolePermissionSource(repo port_role_repository.RoleRepository) *RolePermissionSource {
+	return &RolePermissionSource{repo: repo}
+}
+
+func (s *RolePermissionSource) PermissionsForUser(userID string) ([]*permission_entity.Permission, error) {
+	roles, err := s.repo.FindRolesByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]*permission_entity.Permission, 0, len(roles))
+	for _, role := range roles {
+		permissions = append(permissions, role.PermissionFor(userID))
+	}
+	return permissions, nil
+}
@@ -0,0 +1,51 @@
+package role_service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	port_role_repository "github.com/williamkoller/system-education/internal/role/port/repository"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+	port_role_repository.RoleRepository
+}
+
+func (m *MockRoleRepository) FindRolesByUserID(userID string) ([]*role_entity.Role, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*role_entity.Role), args.Error(1)
+}
+
+func TestPermissionsForUser(t *testing.T) {
+	repo := new(MockRoleRepository)
+	repo.On("FindRolesByUserID", "user-1").Return([]*role_entity.Role{
+		{ID: "role-1", Name: "teacher", Modules: []string{"classes"}, Actions: []string{"read"}, Level: "allowed"},
+		{ID: "role-2", Name: "finance", Modules: []string{"payments"}, Actions: []string{"delete"}, Level: "denied"},
+	}, nil)
+
+	permissions, err := NewRolePermissionSource(repo).PermissionsForUser("user-1")
+
+	assert.NoError(t, err)
+	assert.Len(t, permissions, 2)
+	assert.Equal(t, "user-1", permissions[0].UserID)
+	assert.Equal(t, "role: teacher", permissions[0].Description)
+	assert.Equal(t, "denied", permissions[1].Level)
+	assert.Equal(t, []string{"payments"}, permissions[1].Modules)
+}
+
+func TestPermissionsForUser_Error(t *testing.T) {
+	repo := new(MockRoleRepository)
+	repo.On("FindRolesByUserID", "user-1").Return(nil, errors.New("db error"))
+
+	permissions, err := NewRolePermissionSource(repo).PermissionsForUser("user-1")
+
+	assert.EqualError(t, err, "db error")
+	assert.Nil(t, permissions)
+}
@@ -0,0 +1,225 @@
+package role_usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	port_role_event "github.com/williamkoller/system-education/internal/role/port/event"
+	port_role_repository "github.com/williamkoller/system-education/internal/role/port/repository"
+	port_role_usecase "github.com/williamkoller/system-education/internal/role/port/usecase"
+	role_dtos "github.com/williamkoller/system-education/internal/role/presentation/dtos"
+)
+
+type RoleUsecase struct {
+	repo        port_role_repository.RoleRepository
+	permissions port_permission_service.PermissionService
+	event       port_role_event.Dispatcher
+}
+
+func NewRoleUsecase(repo port_role_repository.RoleRepository, permissions port_permission_service.PermissionService, event port_role_event.Dispatcher) *RoleUsecase {
+	return &RoleUsecase{repo: repo, permissions: permissions, event: event}
+}
+
+var _ port_role_usecase.RoleUsecase = &RoleUsecase{}
+
+func (u *RoleUsecase) Create(actorID string, input role_dtos.AddRoleDto) (*role_entity.Role, error) {
+	existing, err := u.repo.FindByName(input.Name)
+	if err == nil && existing != nil {
+		return nil, role_entity.ErrAlreadyExists
+	}
+	if err != nil && !errors.Is(err, role_entity.ErrNotFound) {
+		return nil, fmt.Errorf("failed to find role by name: %w", err)
+	}
+
+	newRole, err := role_entity.NewRole(&role_entity.Role{
+		ID:          uuid.New().String(),
+		Name:        input.Name,
+		Modules:     input.Modules,
+		Actions:     input.Actions,
+		Level:       input.Level,
+		Description: input.Description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	if err := u.checkGrantable(actorID, newRole.PermissionFor(actorID)); err != nil {
+		return nil, err
+	}
+
+	role, err := u.repo.Save(newRole)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (u *RoleUsecase) FindAll() ([]*role_entity.Role, error) {
+	roles, err := u.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find all roles: %w", err)
+	}
+	return roles, nil
+}
+
+func (u *RoleUsecase) FindByID(id string) (*role_entity.Role, error) {
+	role, err := u.repo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role by id: %w", err)
+	}
+	return role, nil
+}
+
+func (u *RoleUsecase) Update(actorID string, id string, input role_dtos.UpdateRoleDto) (*role_entity.Role, error) {
+	role, err := u.repo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role by id: %w", err)
+	}
+
+	if input.Name != nil && *input.Name != role.Name {
+		existing, err := u.repo.FindByName(*input.Name)
+		if err == nil && existing != nil {
+			return nil, role_entity.ErrAlreadyExists
+		}
+	}
+
+	// Taken before UpdateRole, which changes role in place.
+	previous := role.PermissionFor(actorID)
+
+	role, err = role.UpdateRole(input.Name, input.Modules, input.Actions, input.Level, input.Description)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	if err := u.checkGrantable(actorID, previous, role.PermissionFor(actorID)); err != nil {
+		return nil, err
+	}
+
+	role, err = u.repo.Update(id, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	userIDs, err := u.repo.FindUserIDsByRoleID(role.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role holders: %w", err)
+	}
+
+	if err := u.invalidateAll(userIDs); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (u *RoleUsecase) Delete(actorID string, id string) error {
+	role, err := u.repo.FindByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to find role by id: %w", err)
+	}
+
+	if err := u.checkGrantable(actorID, role.PermissionFor(actorID)); err != nil {
+		return err
+	}
+
+	// Holders are collected first: deleting the role also drops its
+	// assignments.
+	userIDs, err := u.repo.FindUserIDsByRoleID(role.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find role holders: %w", err)
+	}
+
+	if err := u.repo.Delete(role.ID); err != nil {
+		return err
+	}
+
+	return u.invalidateAll(userIDs)
+}
+
+func (u *RoleUsecase) Assign(actorID string, roleID string, userID string) error {
+	role, err := u.repo.FindByID(roleID)
+	if err != nil {
+		return fmt.Errorf("failed to find role by id: %w", err)
+	}
+
+	if err := u.checkGrantable(actorID, role.PermissionFor(actorID)); err != nil {
+		return err
+	}
+
+	if err := u.repo.Assign(role, role.AssignTo(userID)); err != nil {
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	if err := u.permissions.Invalidate(userID); err != nil {
+		return fmt.Errorf("failed to invalidate permissions: %w", err)
+	}
+
+	u.dispatch(role)
+	return nil
+}
+
+func (u *RoleUsecase) Unassign(actorID string, roleID string, userID string) error {
+	role, err := u.repo.FindByID(roleID)
+	if err != nil {
+		return fmt.Errorf("failed to find role by id: %w", err)
+	}
+
+	if err := u.checkGrantable(actorID, role.PermissionFor(actorID)); err != nil {
+		return err
+	}
+
+	role.UnassignFrom(userID)
+	if err := u.repo.Unassign(role, userID); err != nil {
+		return fmt.Errorf("failed to unassign role: %w", err)
+	}
+
+	if err := u.permissions.Invalidate(userID); err != nil {
+		return fmt.Errorf("failed to invalidate permissions: %w", err)
+	}
+
+	u.dispatch(role)
+	return nil
+}
+
+func (u *RoleUsecase) FindRolesByUserID(userID string) ([]*role_entity.Role, error) {
+	roles, err := u.repo.FindRolesByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find roles by user id: %w", err)
+	}
+	return roles, nil
+}
+
+// checkGrantable keeps roles from becoming a way around the permission
+// escalation guard: an actor may only define, hand out or take away roles
+// whose grants, denies included, they hold themselves.
+func (u *RoleUsecase) checkGrantable(actorID string, permissions ...*permission_entity.Permission) error {
+	held, err := u.permissions.Resolve(actorID, 0)
+	if err != nil {
+		return fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+	for _, permission := range permissions {
+		if err := permission_entity.CheckGrantable(held.Grants(), permission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *RoleUsecase) invalidateAll(userIDs []string) error {
+	for _, userID := range userIDs {
+		if err := u.permissions.Invalidate(userID); err != nil {
+			return fmt.Errorf("failed to invalidate permissions: %w", err)
+		}
+	}
+	return nil
+}
+
+func (u *RoleUsecase) dispatch(role *role_entity.Role) {
+	for _, domainEvent := range role.PullDomainEvents() {
+		u.event.Dispatch(domainEvent)
+	}
+}
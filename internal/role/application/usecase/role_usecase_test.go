@@ -0,0 +1,518 @@
+package role_usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	role_event "github.com/williamkoller/system-education/internal/role/domain/event"
+	role_dtos "github.com/williamkoller/system-education/internal/role/presentation/dtos"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) Save(r *role_entity.Role) (*role_entity.Role, error) {
+	args := m.Called(r)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*role_entity.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindAll() ([]*role_entity.Role, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*role_entity.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByID(id string) (*role_entity.Role, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*role_entity.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindByName(name string) (*role_entity.Role, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*role_entity.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) Update(id string, r *role_entity.Role) (*role_entity.Role, error) {
+	args := m.Called(id, r)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*role_entity.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) Delete(id string) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *MockRoleRepository) Assign(r *role_entity.Role, a *role_entity.RoleAssignment) error {
+	return m.Called(r, a).Error(0)
+}
+
+func (m *MockRoleRepository) Unassign(r *role_entity.Role, userID string) error {
+	return m.Called(r, userID).Error(0)
+}
+
+func (m *MockRoleRepository) FindRolesByUserID(userID string) ([]*role_entity.Role, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*role_entity.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) FindUserIDsByRoleID(roleID string) ([]string, error) {
+	args := m.Called(roleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type MockPermissionService struct {
+	mock.Mock
+}
+
+func (m *MockPermissionService) Resolve(userID string, minVersion int64) (*permission_entity.EffectivePermissions, error) {
+	args := m.Called(userID, minVersion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Refresh(userID string) (*permission_entity.EffectivePermissions, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*permission_entity.EffectivePermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) Invalidate(userID string) error {
+	return m.Called(userID).Error(0)
+}
+
+type MockDispatcher struct {
+	mock.Mock
+}
+
+func (m *MockDispatcher) Dispatch(event interface{}) {
+	m.Called(event)
+}
+
+func (m *MockDispatcher) Register(eventName string, handler shared_event.Handler) {
+	m.Called(eventName, handler)
+}
+
+func newTestUsecase() (*RoleUsecase, *MockRoleRepository, *MockPermissionService, *MockDispatcher) {
+	repo := new(MockRoleRepository)
+	permissions := new(MockPermissionService)
+	dispatcher := new(MockDispatcher)
+	permissions.On("Resolve", "admin-1", int64(0)).Return(permission_entity.NewEffectivePermissions("admin-1", 1, []*permission_entity.Permission{
+		{Modules: []string{"classes", "payments"}, Actions: []string{"read", "update"}, Level: "allowed"},
+	}), nil).Maybe()
+	return NewRoleUsecase(repo, permissions, dispatcher), repo, permissions, dispatcher
+}
+
+func teacher() *role_entity.Role {
+	return &role_entity.Role{
+		ID:      "role-1",
+		Name:    "teacher",
+		Modules: []string{"classes"},
+		Actions: []string{"read"},
+		Level:   "allowed",
+	}
+}
+
+func TestRoleUsecase_Create(t *testing.T) {
+	input := role_dtos.AddRoleDto{
+		Name:    "teacher",
+		Modules: []string{"classes"},
+		Actions: []string{"read"},
+		Level:   "allowed",
+	}
+
+	t.Run("should create role successfully", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindByName", "teacher").Return(nil, role_entity.ErrNotFound)
+		repo.On("Save", mock.AnythingOfType("*role_entity.Role")).Return(teacher(), nil)
+
+		role, err := usecase.Create("admin-1", input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "teacher", role.Name)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject duplicated name", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindByName", "teacher").Return(teacher(), nil)
+
+		role, err := usecase.Create("admin-1", input)
+
+		assert.Nil(t, role)
+		assert.ErrorIs(t, err, role_entity.ErrAlreadyExists)
+		repo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("should return error when lookup fails", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindByName", "teacher").Return(nil, errors.New("db error"))
+
+		role, err := usecase.Create("admin-1", input)
+
+		assert.Nil(t, role)
+		assert.EqualError(t, err, "failed to find role by name: db error")
+	})
+
+	t.Run("should return error when validation fails", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindByName", "teacher").Return(nil, role_entity.ErrNotFound)
+
+		role, err := usecase.Create("admin-1", role_dtos.AddRoleDto{Name: "teacher"})
+
+		assert.Nil(t, role)
+		assert.ErrorContains(t, err, "failed to create role")
+	})
+
+	t.Run("should return error when save fails", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindByName", "teacher").Return(nil, role_entity.ErrNotFound)
+		repo.On("Save", mock.AnythingOfType("*role_entity.Role")).Return(nil, errors.New("db error"))
+
+		role, err := usecase.Create("admin-1", input)
+
+		assert.Nil(t, role)
+		assert.EqualError(t, err, "failed to save role: db error")
+	})
+}
+
+func TestRoleUsecase_Find(t *testing.T) {
+	t.Run("find all", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindAll").Return([]*role_entity.Role{teacher()}, nil)
+
+		roles, err := usecase.FindAll()
+
+		assert.NoError(t, err)
+		assert.Len(t, roles, 1)
+	})
+
+	t.Run("find all error", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindAll").Return(nil, errors.New("db error"))
+
+		_, err := usecase.FindAll()
+
+		assert.EqualError(t, err, "failed to find all roles: db error")
+	})
+
+	t.Run("find by id not found", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(nil, role_entity.ErrNotFound)
+
+		_, err := usecase.FindByID("role-1")
+
+		assert.ErrorIs(t, err, role_entity.ErrNotFound)
+	})
+
+	t.Run("find by user id", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindRolesByUserID", "user-1").Return([]*role_entity.Role{teacher()}, nil)
+
+		roles, err := usecase.FindRolesByUserID("user-1")
+
+		assert.NoError(t, err)
+		assert.Len(t, roles, 1)
+	})
+
+	t.Run("find by user id error", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindRolesByUserID", "user-1").Return(nil, errors.New("db error"))
+
+		_, err := usecase.FindRolesByUserID("user-1")
+
+		assert.EqualError(t, err, "failed to find roles by user id: db error")
+	})
+}
+
+func TestRoleUsecase_Update(t *testing.T) {
+	t.Run("should invalidate every holder", func(t *testing.T) {
+		usecase, repo, permissions, _ := newTestUsecase()
+		level := "restricted"
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+		repo.On("Update", "role-1", mock.AnythingOfType("*role_entity.Role")).Return(teacher(), nil)
+		repo.On("FindUserIDsByRoleID", "role-1").Return([]string{"user-1", "user-2"}, nil)
+		permissions.On("Invalidate", "user-1").Return(nil)
+		permissions.On("Invalidate", "user-2").Return(nil)
+
+		role, err := usecase.Update("admin-1", "role-1", role_dtos.UpdateRoleDto{Level: &level})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, role)
+		permissions.AssertExpectations(t)
+	})
+
+	t.Run("should reject renaming to an existing role", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		name := "finance"
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+		repo.On("FindByName", "finance").Return(&role_entity.Role{ID: "role-2", Name: "finance"}, nil)
+
+		role, err := usecase.Update("admin-1", "role-1", role_dtos.UpdateRoleDto{Name: &name})
+
+		assert.Nil(t, role)
+		assert.ErrorIs(t, err, role_entity.ErrAlreadyExists)
+	})
+
+	t.Run("should return error when validation fails", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		level := "admin"
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+
+		role, err := usecase.Update("admin-1", "role-1", role_dtos.UpdateRoleDto{Level: &level})
+
+		assert.Nil(t, role)
+		assert.ErrorContains(t, err, "failed to update role")
+	})
+
+	t.Run("should return error when invalidation fails", func(t *testing.T) {
+		usecase, repo, permissions, _ := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+		repo.On("Update", "role-1", mock.AnythingOfType("*role_entity.Role")).Return(teacher(), nil)
+		repo.On("FindUserIDsByRoleID", "role-1").Return([]string{"user-1"}, nil)
+		permissions.On("Invalidate", "user-1").Return(errors.New("db error"))
+
+		role, err := usecase.Update("admin-1", "role-1", role_dtos.UpdateRoleDto{})
+
+		assert.Nil(t, role)
+		assert.EqualError(t, err, "failed to invalidate permissions: db error")
+	})
+
+	t.Run("should return error when role is missing", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(nil, role_entity.ErrNotFound)
+
+		_, err := usecase.Update("admin-1", "role-1", role_dtos.UpdateRoleDto{})
+
+		assert.ErrorIs(t, err, role_entity.ErrNotFound)
+	})
+}
+
+func TestRoleUsecase_Delete(t *testing.T) {
+	t.Run("should invalidate holders collected before delete", func(t *testing.T) {
+		usecase, repo, permissions, _ := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+		repo.On("FindUserIDsByRoleID", "role-1").Return([]string{"user-1"}, nil).Once()
+		repo.On("Delete", "role-1").Return(nil)
+		permissions.On("Invalidate", "user-1").Return(nil)
+
+		err := usecase.Delete("admin-1", "role-1")
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		permissions.AssertExpectations(t)
+	})
+
+	t.Run("should return error when delete fails", func(t *testing.T) {
+		usecase, repo, permissions, _ := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+		repo.On("FindUserIDsByRoleID", "role-1").Return([]string{"user-1"}, nil)
+		repo.On("Delete", "role-1").Return(errors.New("db error"))
+
+		err := usecase.Delete("admin-1", "role-1")
+
+		assert.EqualError(t, err, "db error")
+		permissions.AssertNotCalled(t, "Invalidate", mock.Anything)
+	})
+
+	t.Run("should return error when holders lookup fails", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+		repo.On("FindUserIDsByRoleID", "role-1").Return(nil, errors.New("db error"))
+
+		err := usecase.Delete("admin-1", "role-1")
+
+		assert.EqualError(t, err, "failed to find role holders: db error")
+		repo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func TestRoleUsecase_Assign(t *testing.T) {
+	t.Run("should assign, invalidate and dispatch", func(t *testing.T) {
+		usecase, repo, permissions, dispatcher := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+		repo.On("Assign", mock.AnythingOfType("*role_entity.Role"), mock.MatchedBy(func(a *role_entity.RoleAssignment) bool {
+			return a.UserID == "user-1" && a.RoleID == "role-1"
+		})).Return(nil)
+		permissions.On("Invalidate", "user-1").Return(nil)
+		dispatcher.On("Dispatch", mock.MatchedBy(func(e *role_event.RoleAssignedEvent) bool {
+			return e.UserID == "user-1" && e.RoleName == "teacher"
+		})).Return()
+
+		err := usecase.Assign("admin-1", "role-1", "user-1")
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		permissions.AssertExpectations(t)
+		dispatcher.AssertExpectations(t)
+	})
+
+	t.Run("should not dispatch when assignment fails", func(t *testing.T) {
+		usecase, repo, permissions, dispatcher := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+		repo.On("Assign", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		err := usecase.Assign("admin-1", "role-1", "user-1")
+
+		assert.EqualError(t, err, "failed to assign role: db error")
+		permissions.AssertNotCalled(t, "Invalidate", mock.Anything)
+		dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+	})
+
+	t.Run("should return error when role is missing", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(nil, role_entity.ErrNotFound)
+
+		err := usecase.Assign("admin-1", "role-1", "user-1")
+
+		assert.ErrorIs(t, err, role_entity.ErrNotFound)
+	})
+
+	t.Run("should return error when invalidation fails", func(t *testing.T) {
+		usecase, repo, permissions, dispatcher := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+		repo.On("Assign", mock.Anything, mock.Anything).Return(nil)
+		permissions.On("Invalidate", "user-1").Return(errors.New("db error"))
+
+		err := usecase.Assign("admin-1", "role-1", "user-1")
+
+		assert.EqualError(t, err, "failed to invalidate permissions: db error")
+		dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+	})
+}
+
+func TestRoleUsecase_Unassign(t *testing.T) {
+	t.Run("should unassign, invalidate and dispatch", func(t *testing.T) {
+		usecase, repo, permissions, dispatcher := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+		repo.On("Unassign", mock.AnythingOfType("*role_entity.Role"), "user-1").Return(nil)
+		permissions.On("Invalidate", "user-1").Return(nil)
+		dispatcher.On("Dispatch", mock.AnythingOfType("*role_event.RoleUnassignedEvent")).Return()
+
+		err := usecase.Unassign("admin-1", "role-1", "user-1")
+
+		assert.NoError(t, err)
+		dispatcher.AssertExpectations(t)
+	})
+
+	t.Run("should surface missing assignment", func(t *testing.T) {
+		usecase, repo, _, dispatcher := newTestUsecase()
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+		repo.On("Unassign", mock.AnythingOfType("*role_entity.Role"), "user-1").Return(role_entity.ErrAssignmentNotFound)
+
+		err := usecase.Unassign("admin-1", "role-1", "user-1")
+
+		assert.ErrorIs(t, err, role_entity.ErrAssignmentNotFound)
+		dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+	})
+}
+
+func TestRoleUsecase_Escalation(t *testing.T) {
+	t.Run("should reject creating a role beyond the actor's grants", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		repo.On("FindByName", "principal").Return(nil, role_entity.ErrNotFound)
+
+		role, err := usecase.Create("admin-1", role_dtos.AddRoleDto{
+			Name:    "principal",
+			Modules: []string{"classes"},
+			Actions: []string{"delete"},
+			Level:   "allowed",
+		})
+
+		assert.Nil(t, role)
+		assert.ErrorIs(t, err, permission_entity.ErrEscalation)
+		repo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("should reject widening a role beyond the actor's grants", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		modules := []string{"classes", "permissions"}
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+
+		role, err := usecase.Update("admin-1", "role-1", role_dtos.UpdateRoleDto{Modules: &modules})
+
+		assert.Nil(t, role)
+		assert.ErrorIs(t, err, permission_entity.ErrEscalation)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject assigning a role the actor does not cover", func(t *testing.T) {
+		usecase, repo, permissions, dispatcher := newTestUsecase()
+		permissions.On("Resolve", "teacher-1", int64(0)).Return(permission_entity.NewEffectivePermissions("teacher-1", 1, nil), nil)
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+
+		err := usecase.Assign("teacher-1", "role-1", "user-1")
+
+		assert.ErrorIs(t, err, permission_entity.ErrEscalation)
+		repo.AssertNotCalled(t, "Assign", mock.Anything, mock.Anything)
+		dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+	})
+
+	t.Run("should reject lifting a deny the actor does not hold", func(t *testing.T) {
+		usecase, repo, _, _ := newTestUsecase()
+		level := "restricted"
+		repo.On("FindByID", "role-2").Return(&role_entity.Role{
+			ID:      "role-2",
+			Name:    "suspended",
+			Modules: []string{"permissions"},
+			Actions: []string{"delete"},
+			Level:   "denied",
+		}, nil)
+
+		role, err := usecase.Update("admin-1", "role-2", role_dtos.UpdateRoleDto{Level: &level})
+
+		assert.Nil(t, role)
+		assert.ErrorIs(t, err, permission_entity.ErrEscalation)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject removing a role the actor does not cover", func(t *testing.T) {
+		usecase, repo, permissions, dispatcher := newTestUsecase()
+		permissions.On("Resolve", "teacher-1", int64(0)).Return(permission_entity.NewEffectivePermissions("teacher-1", 1, nil), nil)
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+
+		assert.ErrorIs(t, usecase.Unassign("teacher-1", "role-1", "teacher-1"), permission_entity.ErrEscalation)
+		assert.ErrorIs(t, usecase.Delete("teacher-1", "role-1"), permission_entity.ErrEscalation)
+		repo.AssertNotCalled(t, "Unassign", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "Delete", mock.Anything)
+		dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+	})
+
+	t.Run("should return error when actor permissions cannot be resolved", func(t *testing.T) {
+		usecase, repo, permissions, _ := newTestUsecase()
+		permissions.On("Resolve", "user-9", int64(0)).Return(nil, errors.New("db error"))
+		repo.On("FindByID", "role-1").Return(teacher(), nil)
+
+		err := usecase.Assign("user-9", "role-1", "user-1")
+
+		assert.EqualError(t, err, "failed to resolve permissions: db error")
+	})
+}
@@ -0,0 +1,112 @@
+package role_entity
+
+import (
+	"time"
+
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	role_event "github.com/williamkoller/system-education/internal/role/domain/event"
+	sharedEvent "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+// Role bundles modules, actions and a level under a name (teacher, finance,
+// ...) so the same grants can be assigned to many users at once.
+type Role struct {
+	sharedEvent.AggregateRoot
+	ID          string
+	Name        string
+	Modules     []string
+	Actions     []string
+	Level       string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type RoleAssignment struct {
+	UserID     string
+	RoleID     string
+	AssignedAt time.Time
+}
+
+func NewRole(r *Role) (*Role, error) {
+	vr, err := ValidateRole(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Role{
+		ID:          vr.ID,
+		Name:        vr.Name,
+		Modules:     vr.Modules,
+		Actions:     vr.Actions,
+		Level:       vr.Level,
+		Description: vr.Description,
+		CreatedAt:   vr.CreatedAt,
+		UpdatedAt:   vr.UpdatedAt,
+	}, nil
+}
+
+func (r *Role) UpdateRole(name *string, modules, actions *[]string, level, description *string) (*Role, error) {
+	if name != nil {
+		r.Name = *name
+	}
+
+	if modules != nil {
+		r.Modules = *modules
+	}
+
+	if actions != nil {
+		r.Actions = *actions
+	}
+
+	if level != nil {
+		r.Level = *level
+	}
+
+	if description != nil {
+		r.Description = *description
+	}
+
+	r.UpdatedAt = time.Now()
+
+	return ValidateRole(r)
+}
+
+func (r *Role) AssignTo(userID string) *RoleAssignment {
+	r.AddDomainEvent(role_event.NewRoleAssignedEvent(r.ID, r.Name, userID))
+
+	return &RoleAssignment{
+		UserID:     userID,
+		RoleID:     r.ID,
+		AssignedAt: time.Now(),
+	}
+}
+
+func (r *Role) UnassignFrom(userID string) {
+	r.AddDomainEvent(role_event.NewRoleUnassignedEvent(r.ID, r.Name, userID))
+}
+
+// PermissionFor expresses the role as a permission held by userID, so role
+// grants are evaluated exactly like direct ones.
+func (r *Role) PermissionFor(userID string) *permission_entity.Permission {
+	if r == nil {
+		return nil
+	}
+	return &permission_entity.Permission{
+		ID:          r.ID,
+		UserID:      userID,
+		Modules:     r.Modules,
+		Actions:     r.Actions,
+		Level:       r.Level,
+		Description: "role: " + r.Name,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func (r *Role) PullDomainEvents() []sharedEvent.Event {
+	if r == nil {
+		return nil
+	}
+	return r.AggregateRoot.PullDomainEvents()
+}
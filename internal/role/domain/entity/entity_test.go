@@ -0,0 +1,150 @@
+package role_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	role_event "github.com/williamkoller/system-education/internal/role/domain/event"
+)
+
+func validRole() *Role {
+	return &Role{
+		ID:          "role-1",
+		Name:        "teacher",
+		Modules:     []string{"classes", "grades"},
+		Actions:     []string{"read", "update"},
+		Level:       "allowed",
+		Description: "class teachers",
+	}
+}
+
+func TestNewRole(t *testing.T) {
+	t.Run("should create a role successfully", func(t *testing.T) {
+		role, err := NewRole(validRole())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "role-1", role.ID)
+		assert.Equal(t, "teacher", role.Name)
+		assert.Equal(t, []string{"classes", "grades"}, role.Modules)
+		assert.Equal(t, []string{"read", "update"}, role.Actions)
+		assert.Equal(t, "allowed", role.Level)
+	})
+
+	t.Run("should return error when validation fails", func(t *testing.T) {
+		role, err := NewRole(&Role{ID: "role-1"})
+
+		assert.Nil(t, role)
+		assert.IsType(t, &ValidationError{}, err)
+	})
+}
+
+func TestValidateRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(r *Role)
+		expected string
+	}{
+		{name: "id", mutate: func(r *Role) { r.ID = "" }, expected: "id is required"},
+		{name: "name", mutate: func(r *Role) { r.Name = "  " }, expected: "name is required"},
+		{name: "modules", mutate: func(r *Role) { r.Modules = nil }, expected: "modules is required"},
+		{name: "actions", mutate: func(r *Role) { r.Actions = nil }, expected: "actions is required"},
+		{name: "level", mutate: func(r *Role) { r.Level = "" }, expected: "level is required"},
+		{name: "unknown level", mutate: func(r *Role) { r.Level = "admin" }, expected: "level must be one of allowed, restricted or denied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role := validRole()
+			tt.mutate(role)
+
+			validated, err := ValidateRole(role)
+
+			assert.Nil(t, validated)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+
+	t.Run("nil role", func(t *testing.T) {
+		validated, err := ValidateRole(nil)
+
+		assert.Nil(t, validated)
+		assert.ErrorContains(t, err, "role is required")
+	})
+}
+
+func TestRole_UpdateRole(t *testing.T) {
+	t.Run("should update role successfully", func(t *testing.T) {
+		role := validRole()
+		name := "finance"
+		modules := []string{"payments"}
+		level := "restricted"
+
+		updated, err := role.UpdateRole(&name, &modules, nil, &level, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "finance", updated.Name)
+		assert.Equal(t, []string{"payments"}, updated.Modules)
+		assert.Equal(t, []string{"read", "update"}, updated.Actions)
+		assert.Equal(t, "restricted", updated.Level)
+		assert.Equal(t, "class teachers", updated.Description)
+		assert.False(t, updated.UpdatedAt.IsZero())
+	})
+
+	t.Run("should return error when validation fails", func(t *testing.T) {
+		role := validRole()
+		level := "admin"
+
+		updated, err := role.UpdateRole(nil, nil, nil, &level, nil)
+
+		assert.Nil(t, updated)
+		assert.ErrorContains(t, err, "level must be one of allowed, restricted or denied")
+	})
+}
+
+func TestRole_Assignments(t *testing.T) {
+	role := validRole()
+
+	assignment := role.AssignTo("user-1")
+	role.UnassignFrom("user-2")
+
+	assert.Equal(t, "user-1", assignment.UserID)
+	assert.Equal(t, "role-1", assignment.RoleID)
+	assert.False(t, assignment.AssignedAt.IsZero())
+
+	events := role.PullDomainEvents()
+	assert.Len(t, events, 2)
+
+	assigned, ok := events[0].(*role_event.RoleAssignedEvent)
+	assert.True(t, ok)
+	assert.Equal(t, "user-1", assigned.UserID)
+	assert.Equal(t, "teacher", assigned.RoleName)
+
+	unassigned, ok := events[1].(*role_event.RoleUnassignedEvent)
+	assert.True(t, ok)
+	assert.Equal(t, "user-2", unassigned.UserID)
+
+	assert.Empty(t, role.PullDomainEvents())
+
+	var nilRole *Role
+	assert.Nil(t, nilRole.PullDomainEvents())
+}
+
+func TestRole_PermissionFor(t *testing.T) {
+	permission := validRole().PermissionFor("user-1")
+
+	assert.Equal(t, "role-1", permission.ID)
+	assert.Equal(t, "user-1", permission.UserID)
+	assert.Equal(t, []string{"classes", "grades"}, permission.Modules)
+	assert.Equal(t, []string{"read", "update"}, permission.Actions)
+	assert.Equal(t, "allowed", permission.Level)
+	assert.Equal(t, "role: teacher", permission.Description)
+
+	var nilRole *Role
+	assert.Nil(t, nilRole.PermissionFor("user-1"))
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := &ValidationError{Errors: []string{"error1", "error2"}}
+
+	assert.Equal(t, "validation failed: error1, error2", err.Error())
+}
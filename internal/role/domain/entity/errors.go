@@ -0,0 +1,9 @@
+package role_entity
+
+import "errors"
+
+var (
+	ErrNotFound           = errors.New("role not found")
+	ErrAlreadyExists      = errors.New("role already exists")
+	ErrAssignmentNotFound = errors.New("role assignment not found")
+)
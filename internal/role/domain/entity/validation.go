@@ -0,0 +1,60 @@
+package role_entity
+
+import (
+	"fmt"
+	"strings"
+
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+)
+
+type ValidationError struct {
+	Errors []string
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("validation failed: %s", strings.Join(v.Errors, ", "))
+}
+
+func ValidateRole(role *Role) (*Role, error) {
+	if role == nil {
+		return nil, &ValidationError{Errors: []string{"role is required"}}
+	}
+
+	var errs []string
+
+	if role.ID == "" {
+		errs = append(errs, "id is required")
+	}
+
+	errs = append(errs, validateDefinition(role)...)
+
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+
+	return role, nil
+}
+
+func validateDefinition(role *Role) []string {
+	var errs []string
+
+	if strings.TrimSpace(role.Name) == "" {
+		errs = append(errs, "name is required")
+	}
+
+	if len(role.Modules) == 0 {
+		errs = append(errs, "modules is required")
+	}
+
+	if len(role.Actions) == 0 {
+		errs = append(errs, "actions is required")
+	}
+
+	if role.Level == "" {
+		errs = append(errs, "level is required")
+	} else if !permission_entity.Level(role.Level).IsValid() {
+		errs = append(errs, "level must be one of allowed, restricted or denied")
+	}
+
+	return errs
+}
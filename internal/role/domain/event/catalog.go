@@ -0,0 +1,11 @@
+package role_event
+
+import shared_event "github.com/williamkoller/system-education/shared/domain/event"
+
+// Catalog lists the events the role module raises.
+func Catalog() []func() shared_event.Event {
+	return []func() shared_event.Event{
+		func() shared_event.Event { return &RoleAssignedEvent{} },
+		func() shared_event.Event { return &RoleUnassignedEvent{} },
+	}
+}
@@ -0,0 +1,27 @@
+package role_event
+
+import "time"
+
+type RoleAssignedEvent struct {
+	RoleID   string
+	RoleName string
+	UserID   string
+	Date     time.Time
+}
+
+func NewRoleAssignedEvent(roleID string, roleName string, userID string) *RoleAssignedEvent {
+	return &RoleAssignedEvent{
+		RoleID:   roleID,
+		RoleName: roleName,
+		UserID:   userID,
+		Date:     time.Now(),
+	}
+}
+
+func (e *RoleAssignedEvent) EventName() string {
+	return "role.assigned"
+}
+
+func (e *RoleAssignedEvent) OccurredOn() time.Time {
+	return e.Date
+}
@@ -0,0 +1,28 @@
+package role_event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRoleAssignedEvent(t *testing.T) {
+	event := NewRoleAssignedEvent("role-1", "teacher", "user-1")
+
+	assert.Equal(t, "role-1", event.RoleID)
+	assert.Equal(t, "teacher", event.RoleName)
+	assert.Equal(t, "user-1", event.UserID)
+	assert.Equal(t, "role.assigned", event.EventName())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
+
+func TestNewRoleUnassignedEvent(t *testing.T) {
+	event := NewRoleUnassignedEvent("role-1", "teacher", "user-1")
+
+	assert.Equal(t, "role-1", event.RoleID)
+	assert.Equal(t, "teacher", event.RoleName)
+	assert.Equal(t, "user-1", event.UserID)
+	assert.Equal(t, "role.unassigned", event.EventName())
+	assert.WithinDuration(t, time.Now(), event.OccurredOn(), time.Second)
+}
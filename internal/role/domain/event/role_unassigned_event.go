@@ -0,0 +1,27 @@
+package role_event
+
+import "time"
+
+type RoleUnassignedEvent struct {
+	RoleID   string
+	RoleName string
+	UserID   string
+	Date     time.Time
+}
+
+func NewRoleUnassignedEvent(roleID string, roleName string, userID string) *RoleUnassignedEvent {
+	return &RoleUnassignedEvent{
+		RoleID:   roleID,
+		RoleName: roleName,
+		UserID:   userID,
+		Date:     time.Now(),
+	}
+}
+
+func (e *RoleUnassignedEvent) EventName() string {
+	return "role.unassigned"
+}
+
+func (e *RoleUnassignedEvent) OccurredOn() time.Time {
+	return e.Date
+}
@@ -0,0 +1,63 @@
+package role_model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+)
+
+type Role struct {
+	ID          string         `gorm:"primaryKey;type:uuid"`
+	Name        string         `gorm:"uniqueIndex"`
+	Modules     pq.StringArray `gorm:"type:text[]"`
+	Actions     pq.StringArray `gorm:"type:text[]"`
+	Level       string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+func FromEntity(r *role_entity.Role) *Role {
+	if r == nil {
+		return nil
+	}
+	return &Role{
+		ID:          r.ID,
+		Name:        r.Name,
+		Modules:     pq.StringArray(r.Modules),
+		Actions:     pq.StringArray(r.Actions),
+		Level:       r.Level,
+		Description: r.Description,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func ToEntity(r *Role) *role_entity.Role {
+	if r == nil {
+		return nil
+	}
+	return &role_entity.Role{
+		ID:          r.ID,
+		Name:        r.Name,
+		Modules:     []string(r.Modules),
+		Actions:     []string(r.Actions),
+		Level:       r.Level,
+		Description: r.Description,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func ToEntities(rs []*Role) []*role_entity.Role {
+	entities := make([]*role_entity.Role, 0, len(rs))
+	for _, r := range rs {
+		entities = append(entities, ToEntity(r))
+	}
+	return entities
+}
@@ -0,0 +1,13 @@
+package role_model
+
+import "time"
+
+type UserRole struct {
+	UserID     string `gorm:"primaryKey"`
+	RoleID     string `gorm:"primaryKey;index"`
+	AssignedAt time.Time
+}
+
+func (UserRole) TableName() string {
+	return "user_roles"
+}
@@ -0,0 +1,141 @@
+package role_repository
+
+import (
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	role_model "github.com/williamkoller/system-education/internal/role/infra/db/model"
+	port_role_repository "github.com/williamkoller/system-education/internal/role/port/repository"
+	"github.com/williamkoller/system-education/shared/infra/outbox"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleGormRepository struct {
+	DB *gorm.DB
+}
+
+func NewRoleGormRepository(db *gorm.DB) *RoleGormRepository {
+	return &RoleGormRepository{DB: db}
+}
+
+var _ port_role_repository.RoleRepository = &RoleGormRepository{}
+
+func (r *RoleGormRepository) Save(role *role_entity.Role) (*role_entity.Role, error) {
+	model := role_model.FromEntity(role)
+	if err := r.DB.Create(model).Error; err != nil {
+		return nil, err
+	}
+	return role_model.ToEntity(model), nil
+}
+
+func (r *RoleGormRepository) FindAll() ([]*role_entity.Role, error) {
+	var models []*role_model.Role
+	if err := r.DB.Order("name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return role_model.ToEntities(models), nil
+}
+
+func (r *RoleGormRepository) FindByID(id string) (*role_entity.Role, error) {
+	return r.findOne("id = ?", id)
+}
+
+func (r *RoleGormRepository) FindByName(name string) (*role_entity.Role, error) {
+	return r.findOne("name = ?", name)
+}
+
+func (r *RoleGormRepository) findOne(query string, arg string) (*role_entity.Role, error) {
+	var model role_model.Role
+	if err := r.DB.First(&model, query, arg).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, role_entity.ErrNotFound
+		}
+		return nil, err
+	}
+	return role_model.ToEntity(&model), nil
+}
+
+func (r *RoleGormRepository) Update(id string, role *role_entity.Role) (*role_entity.Role, error) {
+	model := role_model.FromEntity(role)
+	result := r.DB.Model(&role_model.Role{}).
+		Where("id = ?", id).
+		Updates(model)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, role_entity.ErrNotFound
+	}
+
+	return role_model.ToEntity(model), nil
+}
+
+func (r *RoleGormRepository) Delete(id string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&role_model.UserRole{}, "role_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&role_model.Role{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return role_entity.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *RoleGormRepository) Assign(role *role_entity.Role, a *role_entity.RoleAssignment) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&role_model.UserRole{
+			UserID:     a.UserID,
+			RoleID:     a.RoleID,
+			AssignedAt: a.AssignedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return outbox.Append(tx, role.PullDomainEvents())
+	})
+}
+
+func (r *RoleGormRepository) Unassign(role *role_entity.Role, userID string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&role_model.UserRole{}, "user_id = ? AND role_id = ?", userID, role.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return role_entity.ErrAssignmentNotFound
+		}
+		return outbox.Append(tx, role.PullDomainEvents())
+	})
+}
+
+func (r *RoleGormRepository) FindRolesByUserID(userID string) ([]*role_entity.Role, error) {
+	var models []*role_model.Role
+	err := r.DB.
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return role_model.ToEntities(models), nil
+}
+
+func (r *RoleGormRepository) FindUserIDsByRoleID(roleID string) ([]string, error) {
+	var userIDs []string
+	err := r.DB.Model(&role_model.UserRole{}).
+		Where("role_id = ?", roleID).
+		Order("user_id").
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
@@ -0,0 +1,234 @@
+package role_repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	role_model "github.com/williamkoller/system-education/internal/role/infra/db/model"
+	"github.com/williamkoller/system-education/shared/infra/outbox"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type RoleGormRepositorySuite struct {
+	suite.Suite
+	db         *gorm.DB
+	repository *RoleGormRepository
+}
+
+func (s *RoleGormRepositorySuite) SetupTest() {
+	s.db = setupTestDB(s.T())
+	s.repository = NewRoleGormRepository(s.db)
+}
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&role_model.Role{}, &role_model.UserRole{}, &outbox.OutboxMessage{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func newRole(id, name string) *role_entity.Role {
+	return &role_entity.Role{
+		ID:          id,
+		Name:        name,
+		Modules:     []string{"classes"},
+		Actions:     []string{"read"},
+		Level:       "allowed",
+		Description: name + " role",
+	}
+}
+
+func (s *RoleGormRepositorySuite) closeDB() {
+	sqlDB, _ := s.db.DB()
+	sqlDB.Close()
+}
+
+func (s *RoleGormRepositorySuite) TestSave() {
+	created, err := s.repository.Save(newRole("role-1", "teacher"))
+
+	s.NoError(err)
+	s.Equal("role-1", created.ID)
+	s.Equal("teacher", created.Name)
+	s.Equal([]string{"classes"}, created.Modules)
+	s.Equal([]string{"read"}, created.Actions)
+	s.Equal("allowed", created.Level)
+}
+
+func (s *RoleGormRepositorySuite) TestSave_DuplicateName() {
+	_, err := s.repository.Save(newRole("role-1", "teacher"))
+	s.NoError(err)
+
+	created, err := s.repository.Save(newRole("role-2", "teacher"))
+
+	s.Error(err)
+	s.Nil(created)
+}
+
+func (s *RoleGormRepositorySuite) TestFindByID() {
+	s.repository.Save(newRole("role-1", "teacher"))
+
+	found, err := s.repository.FindByID("role-1")
+
+	s.NoError(err)
+	s.Equal("teacher", found.Name)
+}
+
+func (s *RoleGormRepositorySuite) TestFindByID_NotFound() {
+	found, err := s.repository.FindByID("missing")
+
+	s.Equal(role_entity.ErrNotFound, err)
+	s.Nil(found)
+}
+
+func (s *RoleGormRepositorySuite) TestFindByID_DBError() {
+	s.closeDB()
+
+	found, err := s.repository.FindByID("role-1")
+
+	s.Error(err)
+	s.NotEqual(role_entity.ErrNotFound, err)
+	s.Nil(found)
+}
+
+func (s *RoleGormRepositorySuite) TestFindByName() {
+	s.repository.Save(newRole("role-1", "teacher"))
+
+	found, err := s.repository.FindByName("teacher")
+	s.NoError(err)
+	s.Equal("role-1", found.ID)
+
+	_, err = s.repository.FindByName("finance")
+	s.Equal(role_entity.ErrNotFound, err)
+}
+
+func (s *RoleGormRepositorySuite) TestFindAll() {
+	s.repository.Save(newRole("role-1", "teacher"))
+	s.repository.Save(newRole("role-2", "finance"))
+
+	roles, err := s.repository.FindAll()
+
+	s.NoError(err)
+	s.Len(roles, 2)
+	s.Equal("finance", roles[0].Name)
+}
+
+func (s *RoleGormRepositorySuite) TestFindAll_Error() {
+	s.closeDB()
+
+	roles, err := s.repository.FindAll()
+
+	s.Error(err)
+	s.Nil(roles)
+}
+
+func (s *RoleGormRepositorySuite) TestUpdate() {
+	created, _ := s.repository.Save(newRole("role-1", "teacher"))
+	created.Description = "updated"
+
+	updated, err := s.repository.Update(created.ID, created)
+
+	s.NoError(err)
+	s.Equal("updated", updated.Description)
+
+	found, _ := s.repository.FindByID(created.ID)
+	s.Equal("updated", found.Description)
+}
+
+func (s *RoleGormRepositorySuite) TestUpdate_NotFound() {
+	updated, err := s.repository.Update("missing", newRole("missing", "ghost"))
+
+	s.Equal(role_entity.ErrNotFound, err)
+	s.Nil(updated)
+}
+
+func (s *RoleGormRepositorySuite) TestDelete_RemovesAssignments() {
+	s.repository.Save(newRole("role-1", "teacher"))
+	s.NoError(s.repository.Assign(&role_entity.Role{ID: "role-1"}, &role_entity.RoleAssignment{UserID: "user-1", RoleID: "role-1", AssignedAt: time.Now()}))
+
+	err := s.repository.Delete("role-1")
+
+	s.NoError(err)
+	_, err = s.repository.FindByID("role-1")
+	s.Equal(role_entity.ErrNotFound, err)
+
+	userIDs, err := s.repository.FindUserIDsByRoleID("role-1")
+	s.NoError(err)
+	s.Empty(userIDs)
+}
+
+func (s *RoleGormRepositorySuite) TestDelete_NotFound() {
+	err := s.repository.Delete("missing")
+
+	s.Equal(role_entity.ErrNotFound, err)
+}
+
+func (s *RoleGormRepositorySuite) TestAssign_IsIdempotent() {
+	s.repository.Save(newRole("role-1", "teacher"))
+	s.repository.Save(newRole("role-2", "finance"))
+	assignment := &role_entity.RoleAssignment{UserID: "user-1", RoleID: "role-1", AssignedAt: time.Now()}
+
+	s.NoError(s.repository.Assign(&role_entity.Role{ID: "role-1"}, assignment))
+	s.NoError(s.repository.Assign(&role_entity.Role{ID: "role-1"}, assignment))
+	s.NoError(s.repository.Assign(&role_entity.Role{ID: "role-2"}, &role_entity.RoleAssignment{UserID: "user-1", RoleID: "role-2", AssignedAt: time.Now()}))
+	s.NoError(s.repository.Assign(&role_entity.Role{ID: "role-1"}, &role_entity.RoleAssignment{UserID: "user-2", RoleID: "role-1", AssignedAt: time.Now()}))
+
+	roles, err := s.repository.FindRolesByUserID("user-1")
+	s.NoError(err)
+	s.Len(roles, 2)
+	s.Equal("finance", roles[0].Name)
+	s.Equal("teacher", roles[1].Name)
+
+	userIDs, err := s.repository.FindUserIDsByRoleID("role-1")
+	s.NoError(err)
+	s.Equal([]string{"user-1", "user-2"}, userIDs)
+}
+
+func (s *RoleGormRepositorySuite) TestUnassign() {
+	s.repository.Save(newRole("role-1", "teacher"))
+	s.repository.Assign(&role_entity.Role{ID: "role-1"}, &role_entity.RoleAssignment{UserID: "user-1", RoleID: "role-1", AssignedAt: time.Now()})
+
+	s.NoError(s.repository.Unassign(&role_entity.Role{ID: "role-1"}, "user-1"))
+	s.Equal(role_entity.ErrAssignmentNotFound, s.repository.Unassign(&role_entity.Role{ID: "role-1"}, "user-1"))
+
+	roles, err := s.repository.FindRolesByUserID("user-1")
+	s.NoError(err)
+	s.Empty(roles)
+}
+
+func (s *RoleGormRepositorySuite) TestAssignAndUnassign_WriteDomainEventsToOutbox() {
+	role := newRole("role-1", "teacher")
+	s.repository.Save(role)
+
+	s.NoError(s.repository.Assign(role, role.AssignTo("user-1")))
+	role.UnassignFrom("user-1")
+	s.NoError(s.repository.Unassign(role, "user-1"))
+	s.Empty(role.PullDomainEvents())
+
+	var messages []outbox.OutboxMessage
+	s.NoError(s.db.Find(&messages).Error)
+	s.Len(messages, 2)
+	s.ElementsMatch([]string{"role.assigned", "role.unassigned"}, []string{messages[0].EventName, messages[1].EventName})
+}
+
+func (s *RoleGormRepositorySuite) TestFindRolesByUserID_Error() {
+	s.closeDB()
+
+	roles, err := s.repository.FindRolesByUserID("user-1")
+	s.Error(err)
+	s.Nil(roles)
+
+	userIDs, err := s.repository.FindUserIDsByRoleID("role-1")
+	s.Error(err)
+	s.Nil(userIDs)
+}
+
+func TestRoleGormRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RoleGormRepositorySuite))
+}
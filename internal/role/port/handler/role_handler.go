@@ -0,0 +1,14 @@
+package port_role_handler
+
+import "github.com/gin-gonic/gin"
+
+type RoleHandler interface {
+	CreateRole(c *gin.Context)
+	FindAllRoles(c *gin.Context)
+	FindRoleByID(c *gin.Context)
+	UpdateRole(c *gin.Context)
+	DeleteRole(c *gin.Context)
+	AssignRole(c *gin.Context)
+	UnassignRole(c *gin.Context)
+	FindRolesByUserID(c *gin.Context)
+}
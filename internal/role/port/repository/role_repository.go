@@ -0,0 +1,19 @@
+package port_role_repository
+
+import role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+
+type RoleRepository interface {
+	Save(r *role_entity.Role) (*role_entity.Role, error)
+	FindAll() ([]*role_entity.Role, error)
+	FindByID(id string) (*role_entity.Role, error)
+	FindByName(name string) (*role_entity.Role, error)
+	Update(id string, r *role_entity.Role) (*role_entity.Role, error)
+	Delete(id string) error
+	// Assign is idempotent: assigning a role the user already holds is a no-op.
+	// Assign and Unassign write the role's pending events to the outbox with
+	// the change.
+	Assign(r *role_entity.Role, a *role_entity.RoleAssignment) error
+	Unassign(r *role_entity.Role, userID string) error
+	FindRolesByUserID(userID string) ([]*role_entity.Role, error)
+	FindUserIDsByRoleID(roleID string) ([]string, error)
+}
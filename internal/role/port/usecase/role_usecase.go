@@ -0,0 +1,17 @@
+package port_role_usecase
+
+import (
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	role_dtos "github.com/williamkoller/system-education/internal/role/presentation/dtos"
+)
+
+type RoleUsecase interface {
+	Create(actorID string, input role_dtos.AddRoleDto) (*role_entity.Role, error)
+	FindAll() ([]*role_entity.Role, error)
+	FindByID(id string) (*role_entity.Role, error)
+	Update(actorID string, id string, input role_dtos.UpdateRoleDto) (*role_entity.Role, error)
+	Delete(actorID string, id string) error
+	Assign(actorID string, roleID string, userID string) error
+	Unassign(actorID string, roleID string, userID string) error
+	FindRolesByUserID(userID string) ([]*role_entity.Role, error)
+}
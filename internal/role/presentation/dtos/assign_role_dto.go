@@ -0,0 +1,5 @@
+package role_dtos
+
+type AssignRoleDto struct {
+	UserID string `json:"user_id" binding:"required"`
+}
@@ -0,0 +1,9 @@
+package role_dtos
+
+type UpdateRoleDto struct {
+	Name        *string   `json:"name"`
+	Modules     *[]string `json:"modules"`
+	Actions     *[]string `json:"actions"`
+	Level       *string   `json:"level"`
+	Description *string   `json:"description"`
+}
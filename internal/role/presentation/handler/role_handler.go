@@ -0,0 +1,150 @@
+package role_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	role_mapper "github.com/williamkoller/system-education/internal/role/application/mapper"
+	role_entity "github.com/williamkoller/system-education/internal/role/domain/entity"
+	port_role_handler "github.com/williamkoller/system-education/internal/role/port/handler"
+	port_role_usecase "github.com/williamkoller/system-education/internal/role/port/usecase"
+	role_dtos "github.com/williamkoller/system-education/internal/role/presentation/dtos"
+)
+
+type RoleHandler struct {
+	usecase port_role_usecase.RoleUsecase
+}
+
+func NewRoleHandler(usecase port_role_usecase.RoleUsecase) *RoleHandler {
+	return &RoleHandler{usecase: usecase}
+}
+
+var _ port_role_handler.RoleHandler = &RoleHandler{}
+
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var input role_dtos.AddRoleDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	role, err := h.usecase.Create(c.GetString("userID"), input)
+	if err != nil {
+		c.Status(statusFor(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusCreated, role_mapper.ToRole(role))
+}
+
+func (h *RoleHandler) FindAllRoles(c *gin.Context) {
+	roles, err := h.usecase.FindAll()
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, role_mapper.ToRoles(roles))
+}
+
+func (h *RoleHandler) FindRoleByID(c *gin.Context) {
+	role, err := h.usecase.FindByID(c.Param("id"))
+	if err != nil {
+		c.Status(statusFor(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, role_mapper.ToRole(role))
+}
+
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	var input role_dtos.UpdateRoleDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	role, err := h.usecase.Update(c.GetString("userID"), c.Param("id"), input)
+	if err != nil {
+		c.Status(statusFor(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, role_mapper.ToRole(role))
+}
+
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	if err := h.usecase.Delete(c.GetString("userID"), c.Param("id")); err != nil {
+		c.Status(statusFor(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	var input role_dtos.AssignRoleDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	if err := h.usecase.Assign(c.GetString("userID"), c.Param("id"), input.UserID); err != nil {
+		c.Status(statusFor(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RoleHandler) UnassignRole(c *gin.Context) {
+	if err := h.usecase.Unassign(c.GetString("userID"), c.Param("id"), c.Param("user_id")); err != nil {
+		c.Status(statusFor(err))
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RoleHandler) FindRolesByUserID(c *gin.Context) {
+	roles, err := h.usecase.FindRolesByUserID(c.Param("user_id"))
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusOK, role_mapper.ToRoles(roles))
+}
+
+func statusFor(err error) int {
+	var validation *role_entity.ValidationError
+
+	switch {
+	case errors.Is(err, role_entity.ErrNotFound), errors.Is(err, role_entity.ErrAssignmentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, role_entity.ErrAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, permission_entity.ErrEscalation):
+		return http.StatusForbidden
+	case errors.As(err, &validation):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
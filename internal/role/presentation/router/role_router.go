@@ -0,0 +1,51 @@
+package role_router
+
+import (
+	"github.com/gin-gonic/gin"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_revocation "github.com/williamkoller/system-education/internal/auth/port/revocation"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
+	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
+	permission_entity "github.com/williamkoller/system-education/internal/permission/domain/entity"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
+	role_usecase "github.com/williamkoller/system-education/internal/role/application/usecase"
+	role_repository "github.com/williamkoller/system-education/internal/role/infra/db/repository"
+	port_role_event "github.com/williamkoller/system-education/internal/role/port/event"
+	role_handler "github.com/williamkoller/system-education/internal/role/presentation/handler"
+	"gorm.io/gorm"
+)
+
+// Dependencies wires the role routes.
+type Dependencies struct {
+	DB            *gorm.DB
+	TokenManager  port_auth_cryptography.TokenManager
+	Revocations   port_auth_revocation.RevocationStore
+	APIKeys       port_auth_usecase.APIKeyAuthenticator
+	Permissions   port_permission_service.PermissionService
+	AccessControl port_permission_middleware.PermissionMiddleware
+	Events        port_role_event.Dispatcher
+}
+
+func RoleRouter(e *gin.Engine, deps Dependencies) {
+	repo := role_repository.NewRoleGormRepository(deps.DB)
+
+	usecase := role_usecase.NewRoleUsecase(repo, deps.Permissions, deps.Events)
+	handler := role_handler.NewRoleHandler(usecase)
+	authenticate := auth_middleware.AuthMiddleware(deps.TokenManager, auth_middleware.WithRevocationStore(deps.Revocations), auth_middleware.WithAPIKeys(deps.APIKeys))
+
+	r := e.Group("/roles", authenticate)
+	{
+		r.POST("", deps.AccessControl.ModuleAccessMiddleware([]string{"roles"}, []string{"create"}), handler.CreateRole)
+		r.GET("", deps.AccessControl.ModuleAccessMiddleware([]string{"roles"}, []string{"read"}), handler.FindAllRoles)
+		r.GET("/user/:user_id",
+			deps.AccessControl.ModuleAccessMiddleware([]string{"roles"}, []string{"read"}),
+			deps.AccessControl.OwnershipMiddleware(permission_entity.NewOwnershipPolicy("roles"), port_permission_middleware.OwnerFromParam("user_id")),
+			handler.FindRolesByUserID)
+		r.GET("/:id", deps.AccessControl.ModuleAccessMiddleware([]string{"roles"}, []string{"read"}), handler.FindRoleByID)
+		r.PUT("/:id", deps.AccessControl.ModuleAccessMiddleware([]string{"roles"}, []string{"update"}), handler.UpdateRole)
+		r.DELETE("/:id", deps.AccessControl.ModuleAccessMiddleware([]string{"roles"}, []string{"delete"}), handler.DeleteRole)
+		r.POST("/:id/users", deps.AccessControl.ModuleAccessMiddleware([]string{"roles"}, []string{"assign"}), handler.AssignRole)
+		r.DELETE("/:id/users/:user_id", deps.AccessControl.ModuleAccessMiddleware([]string{"roles"}, []string{"assign"}), handler.UnassignRole)
+	}
+}
@@ -0,0 +1,102 @@
+package user_service
+
+import (
+	"fmt"
+
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
+	port_user_password "github.com/williamkoller/system-education/internal/user/port/password"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	port_user_service "github.com/williamkoller/system-education/internal/user/port/service"
+)
+
+// PasswordPolicyService applies a PasswordPolicy together with the checks
+// that need stored state: the breached password list and the user's
+// password history.
+type PasswordPolicyService struct {
+	policy   user_entity.PasswordPolicy
+	hasher   port_cryptography.Bcrypt
+	history  port_user_repository.PasswordHistoryRepository
+	breaches port_user_password.BreachChecker
+}
+
+var _ port_user_service.PasswordPolicy = &PasswordPolicyService{}
+
+// NewPasswordPolicyService builds the service; breaches may be nil to skip
+// the breached password check.
+func NewPasswordPolicyService(policy user_entity.PasswordPolicy, hasher port_cryptography.Bcrypt, history port_user_repository.PasswordHistoryRepository, breaches port_user_password.BreachChecker) *PasswordPolicyService {
+	return &PasswordPolicyService{policy: policy, hasher: hasher, history: history, breaches: breaches}
+}
+
+// Validate checks the cheap rules first and only compares against stored
+// hashes when they pass, since every comparison costs a full hash.
+func (s *PasswordPolicyService) Validate(user *user_entity.User, password string) error {
+	errs := &user_entity.ValidationError{}
+	for _, field := range s.policy.Check(password, user) {
+		errs.Add(field.Field, field.Rule, field.Message)
+	}
+	if errs.HasErrors() {
+		return errs
+	}
+
+	if s.breaches != nil {
+		breached, err := s.breaches.IsBreached(password)
+		if err != nil {
+			return fmt.Errorf("failed to check breached passwords: %w", err)
+		}
+		if breached {
+			errs.Add("password", "breached", "password has appeared in a data breach")
+			return errs
+		}
+	}
+
+	reused, err := s.isReused(user, password)
+	if err != nil {
+		return err
+	}
+	if reused {
+		errs.Add("password", "reused", fmt.Sprintf("password must not match any of your last %d passwords", s.policy.HistorySize))
+		return errs
+	}
+
+	return nil
+}
+
+func (s *PasswordPolicyService) Remember(userID, hash string) error {
+	if s.policy.HistorySize <= 0 {
+		return nil
+	}
+	if err := s.history.Add(userID, hash, s.policy.HistorySize); err != nil {
+		return fmt.Errorf("failed to record password history: %w", err)
+	}
+	return nil
+}
+
+// isReused also checks the user's current hash, which has no history entry
+// for accounts created before the history was kept.
+func (s *PasswordPolicyService) isReused(user *user_entity.User, password string) (bool, error) {
+	if s.policy.HistorySize <= 0 || user == nil || user.ID == "" {
+		return false, nil
+	}
+
+	hashes, err := s.history.Recent(user.ID, s.policy.HistorySize)
+	if err != nil {
+		return false, fmt.Errorf("failed to load password history: %w", err)
+	}
+	if user.Password != "" {
+		hashes = append(hashes, user.Password)
+	}
+
+	seen := make(map[string]struct{}, len(hashes))
+	for _, hash := range hashes {
+		if _, ok := seen[hash]; ok {
+			continue
+		}
+		seen[hash] = struct{}{}
+
+		if ok, err := s.hasher.HashComparer(password, hash); err == nil && ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,146 @@
+package user_service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+)
+
+type MockBcrypt struct {
+	mock.Mock
+}
+
+func (m *MockBcrypt) Hash(plaintext string) (string, error) {
+	args := m.Called(plaintext)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockBcrypt) HashComparer(plaintext, hashed string) (bool, error) {
+	args := m.Called(plaintext, hashed)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockPasswordHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordHistoryRepository) Add(userID, hash string, keep int) error {
+	return m.Called(userID, hash, keep).Error(0)
+}
+
+func (m *MockPasswordHistoryRepository) Recent(userID string, limit int) ([]string, error) {
+	args := m.Called(userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type MockBreachChecker struct {
+	mock.Mock
+}
+
+func (m *MockBreachChecker) IsBreached(password string) (bool, error) {
+	args := m.Called(password)
+	return args.Bool(0), args.Error(1)
+}
+
+func assertRule(t *testing.T, err error, rule string) {
+	t.Helper()
+	var validation *user_entity.ValidationError
+	if assert.ErrorAs(t, err, &validation) {
+		assert.Equal(t, rule, validation.Fields[0].Rule)
+	}
+}
+
+func TestPasswordPolicyService_Validate_PolicyViolationSkipsStoredChecks(t *testing.T) {
+	hasher := new(MockBcrypt)
+	history := new(MockPasswordHistoryRepository)
+	breaches := new(MockBreachChecker)
+	service := NewPasswordPolicyService(user_entity.DefaultPasswordPolicy(), hasher, history, breaches)
+
+	err := service.Validate(&user_entity.User{ID: "user-1"}, "short")
+
+	assertRule(t, err, "min_length")
+	breaches.AssertNotCalled(t, "IsBreached", mock.Anything)
+	history.AssertNotCalled(t, "Recent", mock.Anything, mock.Anything)
+}
+
+func TestPasswordPolicyService_Validate_Breached(t *testing.T) {
+	breaches := new(MockBreachChecker)
+	service := NewPasswordPolicyService(user_entity.DefaultPasswordPolicy(), new(MockBcrypt), new(MockPasswordHistoryRepository), breaches)
+	breaches.On("IsBreached", "Passw0rd1").Return(true, nil)
+
+	err := service.Validate(&user_entity.User{}, "Passw0rd1")
+
+	assertRule(t, err, "breached")
+}
+
+func TestPasswordPolicyService_Validate_BreachCheckError(t *testing.T) {
+	breaches := new(MockBreachChecker)
+	service := NewPasswordPolicyService(user_entity.DefaultPasswordPolicy(), new(MockBcrypt), new(MockPasswordHistoryRepository), breaches)
+	breaches.On("IsBreached", "Passw0rd1").Return(false, errors.New("io error"))
+
+	err := service.Validate(&user_entity.User{}, "Passw0rd1")
+
+	assert.EqualError(t, err, "failed to check breached passwords: io error")
+}
+
+func TestPasswordPolicyService_Validate_ReusedPassword(t *testing.T) {
+	hasher := new(MockBcrypt)
+	history := new(MockPasswordHistoryRepository)
+	service := NewPasswordPolicyService(user_entity.DefaultPasswordPolicy(), hasher, history, nil)
+
+	user := &user_entity.User{ID: "user-1", Password: "current-hash"}
+	history.On("Recent", "user-1", 5).Return([]string{"current-hash", "old-hash"}, nil)
+	hasher.On("HashComparer", "Tr0ubadour", "current-hash").Return(false, nil)
+	hasher.On("HashComparer", "Tr0ubadour", "old-hash").Return(true, nil)
+
+	err := service.Validate(user, "Tr0ubadour")
+
+	assertRule(t, err, "reused")
+	hasher.AssertNumberOfCalls(t, "HashComparer", 2)
+}
+
+func TestPasswordPolicyService_Validate_ChecksCurrentHashWithoutHistory(t *testing.T) {
+	hasher := new(MockBcrypt)
+	history := new(MockPasswordHistoryRepository)
+	service := NewPasswordPolicyService(user_entity.DefaultPasswordPolicy(), hasher, history, nil)
+
+	history.On("Recent", "user-1", 5).Return([]string{}, nil)
+	hasher.On("HashComparer", "Tr0ubadour", "current-hash").Return(true, nil)
+
+	err := service.Validate(&user_entity.User{ID: "user-1", Password: "current-hash"}, "Tr0ubadour")
+
+	assertRule(t, err, "reused")
+}
+
+func TestPasswordPolicyService_Validate_NewUserSkipsHistory(t *testing.T) {
+	history := new(MockPasswordHistoryRepository)
+	service := NewPasswordPolicyService(user_entity.DefaultPasswordPolicy(), new(MockBcrypt), history, nil)
+
+	err := service.Validate(&user_entity.User{Name: "Ana"}, "Tr0ubadour")
+
+	assert.NoError(t, err)
+	history.AssertNotCalled(t, "Recent", mock.Anything, mock.Anything)
+}
+
+func TestPasswordPolicyService_Remember(t *testing.T) {
+	history := new(MockPasswordHistoryRepository)
+	service := NewPasswordPolicyService(user_entity.DefaultPasswordPolicy(), new(MockBcrypt), history, nil)
+	history.On("Add", "user-1", "hash", 5).Return(nil)
+
+	assert.NoError(t, service.Remember("user-1", "hash"))
+	history.AssertExpectations(t)
+}
+
+func TestPasswordPolicyService_Remember_DisabledHistory(t *testing.T) {
+	history := new(MockPasswordHistoryRepository)
+	service := NewPasswordPolicyService(user_entity.PasswordPolicy{}, new(MockBcrypt), history, nil)
+
+	assert.NoError(t, service.Remember("user-1", "hash"))
+	history.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+}
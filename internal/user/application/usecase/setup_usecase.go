@@ -0,0 +1,81 @@
+package user_usecase
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_permission "github.com/williamkoller/system-education/internal/user/port/permission"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	port_user_usecase "github.com/williamkoller/system-education/internal/user/port/usecase"
+	"github.com/williamkoller/system-education/internal/user/presentation/dtos"
+)
+
+// SetupUsecase creates the first administrator. It only works with the
+// configured setup token and only while there are no users, so it cannot be
+// used again once the system is in use. A claim on the setup marker makes
+// the check and the creation one step: of concurrent requests only the first
+// gets past it.
+type SetupUsecase struct {
+	users   port_user_usecase.UserUsecase
+	granter port_user_permission.PermissionGranter
+	setup   port_user_repository.SetupRepository
+	token   string
+}
+
+func NewSetupUsecase(users port_user_usecase.UserUsecase, granter port_user_permission.PermissionGranter, setup port_user_repository.SetupRepository, token string) *SetupUsecase {
+	return &SetupUsecase{users: users, granter: granter, setup: setup, token: token}
+}
+
+var _ port_user_usecase.SetupUsecase = &SetupUsecase{}
+
+func (s *SetupUsecase) Bootstrap(token string, input dtos.AddUserDto) (*user_entity.User, error) {
+	if s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		return nil, port_user_usecase.ErrInvalidSetupToken
+	}
+
+	if err := s.setup.Claim(); err != nil {
+		if errors.Is(err, port_user_repository.ErrSetupClaimed) {
+			return nil, port_user_usecase.ErrSetupCompleted
+		}
+		return nil, fmt.Errorf("failed to claim setup: %w", err)
+	}
+
+	user, err := s.bootstrap(input)
+	if err != nil && !errors.Is(err, port_user_usecase.ErrSetupCompleted) {
+		// A failed setup must not lock the system out of being set up.
+		if releaseErr := s.setup.Release(); releaseErr != nil {
+			log.Printf("Failed to release setup claim: %v", releaseErr)
+		}
+	}
+	return user, err
+}
+
+func (s *SetupUsecase) bootstrap(input dtos.AddUserDto) (*user_entity.User, error) {
+	existing, err := s.users.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, port_user_usecase.ErrSetupCompleted
+	}
+
+	user, err := s.users.Create(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.granter.GrantAdministrator(user.ID); err != nil {
+		return nil, fmt.Errorf("failed to grant administrator permissions: %w", err)
+	}
+
+	// Whoever holds the setup token vouches for the address; the first
+	// administrator must be able to log in before any mail is configured.
+	if err := s.users.MarkEmailVerified(user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,206 @@
+package user_usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	user_usecase "github.com/williamkoller/system-education/internal/user/application/usecase"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	port_user_usecase "github.com/williamkoller/system-education/internal/user/port/usecase"
+	"github.com/williamkoller/system-education/internal/user/presentation/dtos"
+)
+
+type MockUserUsecase struct {
+	mock.Mock
+}
+
+func (m *MockUserUsecase) Create(input dtos.AddUserDto) (*user_entity.User, error) {
+	args := m.Called(input)
+	result, _ := args.Get(0).(*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserUsecase) FindAll() ([]*user_entity.User, error) {
+	args := m.Called()
+	result, _ := args.Get(0).([]*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserUsecase) FindByID(id string) (*user_entity.User, error) {
+	args := m.Called(id)
+	result, _ := args.Get(0).(*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserUsecase) Update(id string, input dtos.UpdateUserDto) (*user_entity.User, error) {
+	args := m.Called(id, input)
+	result, _ := args.Get(0).(*user_entity.User)
+	return result, args.Error(1)
+}
+
+func (m *MockUserUsecase) Delete(id string) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *MockUserUsecase) MarkEmailVerified(id string) error {
+	return m.Called(id).Error(0)
+}
+
+type MockPermissionGranter struct {
+	mock.Mock
+}
+
+func (m *MockPermissionGranter) GrantAdministrator(userID string) error {
+	return m.Called(userID).Error(0)
+}
+
+type MockSetupRepository struct {
+	mock.Mock
+}
+
+func (m *MockSetupRepository) Claim() error {
+	return m.Called().Error(0)
+}
+
+func (m *MockSetupRepository) Release() error {
+	return m.Called().Error(0)
+}
+
+func TestBootstrap_Success(t *testing.T) {
+	users := new(MockUserUsecase)
+	granter := new(MockPermissionGranter)
+	setup := new(MockSetupRepository)
+	input := dtos.AddUserDto{Name: "Ana", Email: "ana@school.edu", Password: "strongPassword123"}
+
+	setup.On("Claim").Return(nil)
+	users.On("FindAll").Return([]*user_entity.User{}, nil)
+	users.On("Create", input).Return(&user_entity.User{ID: "user-1", Email: input.Email}, nil)
+	granter.On("GrantAdministrator", "user-1").Return(nil)
+	users.On("MarkEmailVerified", "user-1").Return(nil)
+
+	user, err := user_usecase.NewSetupUsecase(users, granter, setup, "s3cret").Bootstrap("s3cret", input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", user.ID)
+	users.AssertExpectations(t)
+	granter.AssertExpectations(t)
+	setup.AssertNotCalled(t, "Release")
+}
+
+func TestBootstrap_InvalidToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		presented  string
+	}{
+		{name: "wrong token", configured: "s3cret", presented: "guess"},
+		{name: "missing token", configured: "s3cret", presented: ""},
+		{name: "setup disabled", configured: "", presented: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := new(MockUserUsecase)
+			setup := new(MockSetupRepository)
+
+			user, err := user_usecase.NewSetupUsecase(users, new(MockPermissionGranter), setup, tt.configured).Bootstrap(tt.presented, dtos.AddUserDto{})
+
+			assert.Nil(t, user)
+			assert.ErrorIs(t, err, port_user_usecase.ErrInvalidSetupToken)
+			users.AssertNotCalled(t, "FindAll")
+			setup.AssertNotCalled(t, "Claim")
+		})
+	}
+}
+
+func TestBootstrap_AlreadyCompleted(t *testing.T) {
+	users := new(MockUserUsecase)
+	setup := new(MockSetupRepository)
+	setup.On("Claim").Return(nil)
+	users.On("FindAll").Return([]*user_entity.User{{ID: "user-1"}}, nil)
+
+	user, err := user_usecase.NewSetupUsecase(users, new(MockPermissionGranter), setup, "s3cret").Bootstrap("s3cret", dtos.AddUserDto{})
+
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, port_user_usecase.ErrSetupCompleted)
+	users.AssertNotCalled(t, "Create", mock.Anything)
+	setup.AssertNotCalled(t, "Release")
+}
+
+func TestBootstrap_ClaimedByAnotherRequest(t *testing.T) {
+	users := new(MockUserUsecase)
+	setup := new(MockSetupRepository)
+	setup.On("Claim").Return(port_user_repository.ErrSetupClaimed)
+
+	user, err := user_usecase.NewSetupUsecase(users, new(MockPermissionGranter), setup, "s3cret").Bootstrap("s3cret", dtos.AddUserDto{})
+
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, port_user_usecase.ErrSetupCompleted)
+	users.AssertNotCalled(t, "FindAll")
+	setup.AssertNotCalled(t, "Release")
+}
+
+func TestBootstrap_Errors(t *testing.T) {
+	t.Run("find all fails", func(t *testing.T) {
+		users := new(MockUserUsecase)
+		setup := new(MockSetupRepository)
+		setup.On("Claim").Return(nil)
+		setup.On("Release").Return(nil)
+		users.On("FindAll").Return(nil, errors.New("db error"))
+
+		_, err := user_usecase.NewSetupUsecase(users, new(MockPermissionGranter), setup, "s3cret").Bootstrap("s3cret", dtos.AddUserDto{})
+
+		assert.EqualError(t, err, "db error")
+		setup.AssertCalled(t, "Release")
+	})
+
+	t.Run("create fails", func(t *testing.T) {
+		users := new(MockUserUsecase)
+		setup := new(MockSetupRepository)
+		setup.On("Claim").Return(nil)
+		setup.On("Release").Return(nil)
+		users.On("FindAll").Return([]*user_entity.User{}, nil)
+		users.On("Create", mock.Anything).Return(nil, errors.New("invalid user data"))
+
+		_, err := user_usecase.NewSetupUsecase(users, new(MockPermissionGranter), setup, "s3cret").Bootstrap("s3cret", dtos.AddUserDto{})
+
+		assert.EqualError(t, err, "invalid user data")
+		setup.AssertCalled(t, "Release")
+	})
+
+	t.Run("grant fails", func(t *testing.T) {
+		users := new(MockUserUsecase)
+		setup := new(MockSetupRepository)
+		setup.On("Claim").Return(nil)
+		setup.On("Release").Return(nil)
+		granter := new(MockPermissionGranter)
+		users.On("FindAll").Return([]*user_entity.User{}, nil)
+		users.On("Create", mock.Anything).Return(&user_entity.User{ID: "user-1"}, nil)
+		granter.On("GrantAdministrator", "user-1").Return(errors.New("db error"))
+
+		_, err := user_usecase.NewSetupUsecase(users, granter, setup, "s3cret").Bootstrap("s3cret", dtos.AddUserDto{})
+
+		assert.EqualError(t, err, "failed to grant administrator permissions: db error")
+		setup.AssertCalled(t, "Release")
+	})
+
+	t.Run("verify email fails", func(t *testing.T) {
+		users := new(MockUserUsecase)
+		setup := new(MockSetupRepository)
+		setup.On("Claim").Return(nil)
+		setup.On("Release").Return(nil)
+		granter := new(MockPermissionGranter)
+		users.On("FindAll").Return([]*user_entity.User{}, nil)
+		users.On("Create", mock.Anything).Return(&user_entity.User{ID: "user-1"}, nil)
+		granter.On("GrantAdministrator", "user-1").Return(nil)
+		users.On("MarkEmailVerified", "user-1").Return(errors.New("db error"))
+
+		_, err := user_usecase.NewSetupUsecase(users, granter, setup, "s3cret").Bootstrap("s3cret", dtos.AddUserDto{})
+
+		assert.EqualError(t, err, "db error")
+		setup.AssertCalled(t, "Release")
+	})
+}
@@ -4,24 +4,44 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
 	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
 	port_event "github.com/williamkoller/system-education/internal/user/port/event"
 	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
+	port_user_service "github.com/williamkoller/system-education/internal/user/port/service"
+	port_user_session "github.com/williamkoller/system-education/internal/user/port/session"
 	port_user_usecase "github.com/williamkoller/system-education/internal/user/port/usecase"
 	"github.com/williamkoller/system-education/internal/user/presentation/dtos"
 )
 
 type UserUsecase struct {
-	repo   port_user_repository.UserRepository
-	crypto port_cryptography.Bcrypt
-	event  port_event.Dispacther
+	repo      port_user_repository.UserRepository
+	crypto    port_cryptography.Bcrypt
+	event     port_event.Dispacther
+	sessions  port_user_session.SessionRevoker
+	passwords port_user_service.PasswordPolicy
 }
 
-func NewUserUsecase(repo port_user_repository.UserRepository, crypto port_cryptography.Bcrypt, event port_event.Dispacther) *UserUsecase {
-	return &UserUsecase{repo: repo, crypto: crypto, event: event}
+type Option func(*UserUsecase)
+
+// WithPasswordPolicy checks new passwords on create and update. Without it
+// any non-blank password is accepted.
+func WithPasswordPolicy(passwords port_user_service.PasswordPolicy) Option {
+	return func(u *UserUsecase) {
+		u.passwords = passwords
+	}
+}
+
+func NewUserUsecase(repo port_user_repository.UserRepository, crypto port_cryptography.Bcrypt, event port_event.Dispacther, sessions port_user_session.SessionRevoker, opts ...Option) *UserUsecase {
+	usecase := &UserUsecase{repo: repo, crypto: crypto, event: event, sessions: sessions}
+	for _, opt := range opts {
+		opt(usecase)
+	}
+	return usecase
 }
 
 var _ port_user_usecase.UserUsecase = &UserUsecase{}
@@ -33,6 +53,13 @@ func (u *UserUsecase) Create(input dtos.AddUserDto) (*user_entity.User, error) {
 		return nil, port_user_repository.ErrUserAlreadyExists
 	}
 
+	if u.passwords != nil {
+		candidate := &user_entity.User{Name: input.Name, Surname: input.Surname, Nickname: input.Nickname, Email: input.Email}
+		if err := u.passwords.Validate(candidate, input.Password); err != nil {
+			return nil, err
+		}
+	}
+
 	hash, err := u.crypto.Hash(input.Password)
 
 	if err != nil {
@@ -58,9 +85,9 @@ func (u *UserUsecase) Create(input dtos.AddUserDto) (*user_entity.User, error) {
 		return nil, fmt.Errorf("failed to save user: %w", err)
 	}
 
-	for _, domainEvent := range newUser.PullDomainEvents() {
-		u.event.Dispatch(domainEvent)
-	}
+	u.rememberPassword(user.ID, hash)
+
+	u.dispatch(newUser)
 
 	return user, nil
 
@@ -103,7 +130,21 @@ func (u *UserUsecase) Update(id string, input dtos.UpdateUserDto) (*user_entity.
 		return nil, errors.New("user not found")
 	}
 
-	if input.Password != nil && *input.Password != "" {
+	if input.Email != nil && !strings.EqualFold(*input.Email, userExists.Email) {
+		taken, err := u.repo.FindByEmail(*input.Email)
+		if err == nil && taken != nil && taken.ID != userExists.ID {
+			return nil, port_user_repository.ErrUserAlreadyExists
+		}
+	}
+
+	passwordChanged := input.Password != nil && *input.Password != ""
+	if passwordChanged {
+		if u.passwords != nil {
+			if err := u.passwords.Validate(candidateForUpdate(userExists, input), *input.Password); err != nil {
+				return nil, err
+			}
+		}
+
 		hash, err := u.crypto.Hash(*input.Password)
 		if err != nil {
 			return nil, fmt.Errorf("failed to hash password: %w", err)
@@ -111,19 +152,27 @@ func (u *UserUsecase) Update(id string, input dtos.UpdateUserDto) (*user_entity.
 		input.Password = &hash
 	}
 
-	userExists.UpdateUser(
+	if _, err := userExists.UpdateUser(
 		input.Name,
 		input.Nickname,
 		input.Email,
 		input.Password,
 		input.Age,
-	)
+	); err != nil {
+		return nil, err
+	}
 
 	updatedUser, err := u.repo.Update(userExists.ID, userExists)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
+	if passwordChanged {
+		u.rememberPassword(userExists.ID, *input.Password)
+	}
+
+	u.dispatch(userExists)
+
 	return updatedUser, nil
 }
 
@@ -137,10 +186,82 @@ func (u *UserUsecase) Delete(id string) error {
 		return err
 	}
 
-	err = u.repo.Delete(userExists.ID)
+	userExists.MarkDeleted()
+
+	err = u.repo.Delete(userExists)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
+	if err := u.sessions.RevokeAllForUser(userExists.ID); err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	u.dispatch(userExists)
+
 	return nil
 }
+
+// dispatch publishes the user's pending events. Repositories backed by the
+// outbox have already taken them; any left over are dispatched in-process.
+func (u *UserUsecase) dispatch(user *user_entity.User) {
+	for _, domainEvent := range user.PullDomainEvents() {
+		u.event.Dispatch(domainEvent)
+	}
+}
+
+// MarkEmailVerified confirms a user's address without the emailed link, for
+// accounts created by a trusted operator.
+func (u *UserUsecase) MarkEmailVerified(id string) error {
+	userExists, err := u.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if userExists.IsEmailVerified() {
+		return nil
+	}
+
+	userExists.MarkEmailVerified(time.Now())
+	if _, err := u.repo.Update(userExists.ID, userExists); err != nil {
+		return fmt.Errorf("failed to verify user email: %w", err)
+	}
+
+	return nil
+}
+
+// rememberPassword records a new hash in the password history. The password
+// is already saved by then, so a failure only weakens the reuse check and is
+// logged instead of failing the request.
+func (u *UserUsecase) rememberPassword(userID, hash string) {
+	if u.passwords == nil {
+		return
+	}
+	if err := u.passwords.Remember(userID, hash); err != nil {
+		log.Printf("Failed to record password history for user %s: %v", userID, err)
+	}
+}
+
+// candidateForUpdate is the user as it will look after the update, so the
+// password is checked against the new name and email. UpdateUser does not
+// change the surname, so the stored one is kept.
+func candidateForUpdate(user *user_entity.User, input dtos.UpdateUserDto) *user_entity.User {
+	candidate := &user_entity.User{
+		ID:       user.ID,
+		Name:     user.Name,
+		Surname:  user.Surname,
+		Nickname: user.Nickname,
+		Email:    user.Email,
+		Password: user.Password,
+	}
+	if input.Name != nil {
+		candidate.Name = *input.Name
+	}
+	if input.Nickname != nil {
+		candidate.Nickname = *input.Nickname
+	}
+	if input.Email != nil {
+		candidate.Email = *input.Email
+	}
+	return candidate
+}
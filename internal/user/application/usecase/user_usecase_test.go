@@ -3,11 +3,13 @@ package user_usecase_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	user_usecase "github.com/williamkoller/system-education/internal/user/application/usecase"
 	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	user_event "github.com/williamkoller/system-education/internal/user/domain/event"
 	port_user_repository "github.com/williamkoller/system-education/internal/user/port/repository"
 	"github.com/williamkoller/system-education/internal/user/presentation/dtos"
 	shared_event "github.com/williamkoller/system-education/shared/domain/event"
@@ -21,6 +23,15 @@ type MockBcryptAdapter struct {
 	mock.Mock
 }
 
+type MockSessionRevoker struct {
+	mock.Mock
+}
+
+func (m *MockSessionRevoker) RevokeAllForUser(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
 type MockEvent struct {
 	mock.Mock
 }
@@ -74,8 +85,8 @@ func (m *MockUserRepository) FindAll() ([]*user_entity.User, error) {
 	return args.Get(0).([]*user_entity.User), args.Error(1)
 }
 
-func (m *MockUserRepository) Delete(id string) error {
-	args := m.Called(id)
+func (m *MockUserRepository) Delete(user *user_entity.User) error {
+	args := m.Called(user)
 	return args.Error(0)
 }
 
@@ -83,7 +94,8 @@ func TestCreate_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	mockSessions := new(MockSessionRevoker)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	input := dtos.AddUserDto{
 		Name:     "Alice",
@@ -124,8 +136,9 @@ func TestCreate_AlreadyExists(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	existing := &user_entity.User{Email: "alice@example.com"}
 	mockRepo.On("FindByEmail", "alice@example.com").Return(existing, nil)
@@ -140,8 +153,9 @@ func TestCreate_SaveFails(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	input := dtos.AddUserDto{
 		Name:     "Alice",
@@ -175,8 +189,9 @@ func TestCreate_HashFails(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	input := dtos.AddUserDto{
 		Name:     "Alice",
@@ -203,8 +218,9 @@ func TestCreate_InvalidUserData(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	input := dtos.AddUserDto{
 		Name:     "Alice",
@@ -230,8 +246,9 @@ func TestFindAll_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	expectedUsers := []*user_entity.User{
 		{Name: "Alice"}, {Name: "Bob"},
@@ -251,8 +268,9 @@ func TestFindByID_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	expectedUser := &user_entity.User{ID: "123", Name: "Alice"}
 	mockRepo.On("FindByID", "123").Return(expectedUser, nil)
@@ -268,29 +286,57 @@ func TestDelete_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
-	user := &user_entity.User{ID: "123"}
+	user := &user_entity.User{ID: "123", Email: "alice@example.com"}
 	mockRepo.On("FindByID", "123").Return(user, nil)
-	mockRepo.On("Delete", "123").Return(nil)
+	mockRepo.On("Delete", user).Return(nil)
+	mockSessions.On("RevokeAllForUser", "123").Return(nil)
+	mockEvent.On("Dispatch", mock.MatchedBy(func(e *user_event.UserDeletedEvent) bool {
+		return e.UserID == "123" && e.Email == "alice@example.com"
+	})).Return()
 
 	err := usecase.Delete("123")
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
+	mockEvent.AssertExpectations(t)
+}
+
+func TestDelete_RevokeSessionsFails(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockCrypto := new(MockBcryptAdapter)
+	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
+
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
+
+	user := &user_entity.User{ID: "123"}
+	mockRepo.On("FindByID", "123").Return(user, nil)
+	mockRepo.On("Delete", user).Return(nil)
+	mockSessions.On("RevokeAllForUser", "123").Return(errors.New("store down"))
+
+	err := usecase.Delete("123")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to revoke user sessions")
+	mockSessions.AssertExpectations(t)
 }
 
 func TestDelete_FailDelete(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	user := &user_entity.User{ID: "123"}
 	mockRepo.On("FindByID", "123").Return(user, nil)
-	mockRepo.On("Delete", "123").Return(errors.New("db error"))
+	mockRepo.On("Delete", user).Return(errors.New("db error"))
 
 	err := usecase.Delete("123")
 
@@ -304,8 +350,9 @@ func TestUpdate_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	id := "123"
 	existingUser := &user_entity.User{ID: id, Email: "old@example.com"}
@@ -317,11 +364,18 @@ func TestUpdate_Success(t *testing.T) {
 		Password: strPtr("newpass"),
 	}
 
+	mockRepo.On("FindByEmail", "new@example.com").Return((*user_entity.User)(nil), port_user_repository.ErrUserNotFound)
 	mockCrypto.On("Hash", "newpass").Return("hashed:newpass", nil)
 
 	mockRepo.On("Update", id, mock.MatchedBy(func(u *user_entity.User) bool {
-		return u.Email == "new@example.com" && u.Password == "hashed:newpass"
+		return u.Email == "new@example.com" && u.Password == "hashed:newpass" && !u.IsEmailVerified()
 	})).Return(existingUser, nil)
+	mockEvent.On("Dispatch", mock.MatchedBy(func(e *user_event.UserUpdatedEvent) bool {
+		return e.UserID == id && e.Name == "Updated" && e.Email == "new@example.com"
+	})).Return()
+	mockEvent.On("Dispatch", mock.MatchedBy(func(e *user_event.UserEmailChangedEvent) bool {
+		return e.UserID == id && e.PreviousEmail == "old@example.com" && e.Email == "new@example.com" && !e.Verified
+	})).Return()
 
 	user, err := usecase.Update(id, input)
 
@@ -329,14 +383,61 @@ func TestUpdate_Success(t *testing.T) {
 	assert.Equal(t, "new@example.com", user.Email)
 	mockRepo.AssertExpectations(t)
 	mockCrypto.AssertExpectations(t)
+	mockEvent.AssertExpectations(t)
+}
+
+func TestUpdate_RejectsEmailOfAnotherUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockCrypto := new(MockBcryptAdapter)
+	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
+
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
+
+	existingUser := &user_entity.User{ID: "123", Email: "old@example.com"}
+	mockRepo.On("FindByID", "123").Return(existingUser, nil)
+	mockRepo.On("FindByEmail", "taken@example.com").Return(&user_entity.User{ID: "456", Email: "taken@example.com"}, nil)
+
+	user, err := usecase.Update("123", dtos.UpdateUserDto{Email: strPtr("taken@example.com")})
+
+	assert.ErrorIs(t, err, port_user_repository.ErrUserAlreadyExists)
+	assert.Nil(t, user)
+	assert.Equal(t, "old@example.com", existingUser.Email)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdate_InvalidFields(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockCrypto := new(MockBcryptAdapter)
+	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
+
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
+
+	verifiedAt := time.Now()
+	existingUser := &user_entity.User{ID: "123", Email: "old@example.com", EmailVerifiedAt: &verifiedAt}
+	mockRepo.On("FindByID", "123").Return(existingUser, nil)
+	mockRepo.On("FindByEmail", "other@example.com").Return((*user_entity.User)(nil), port_user_repository.ErrUserNotFound)
+
+	age := int32(-1)
+	user, err := usecase.Update("123", dtos.UpdateUserDto{Email: strPtr("other@example.com"), Age: &age})
+
+	var validationErr *user_entity.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Nil(t, user)
+	assert.Equal(t, "old@example.com", existingUser.Email)
+	assert.True(t, existingUser.IsEmailVerified())
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockEvent.AssertNotCalled(t, "Dispatch", mock.Anything)
 }
 
 func TestFindAll_Error(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	mockRepo.On("FindAll").Return([]*user_entity.User(nil), errors.New("database error"))
 
@@ -352,8 +453,9 @@ func TestFindByID_EmptyID(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	user, err := usecase.FindByID("")
 
@@ -366,8 +468,9 @@ func TestFindByID_Error(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	mockRepo.On("FindByID", "123").Return((*user_entity.User)(nil), errors.New("not found"))
 
@@ -383,8 +486,9 @@ func TestUpdate_EmptyID(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	input := dtos.UpdateUserDto{
 		Name: strPtr("Updated"),
@@ -401,8 +505,9 @@ func TestUpdate_UserNotFound(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	mockRepo.On("FindByID", "999").Return((*user_entity.User)(nil), errors.New("not found"))
 
@@ -422,8 +527,9 @@ func TestUpdate_UserNil(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	// Simulate FindByID returning nil without error (edge case)
 	mockRepo.On("FindByID", "999").Return((*user_entity.User)(nil), nil)
@@ -444,8 +550,9 @@ func TestUpdate_RepositoryError(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	id := "123"
 	existingUser := &user_entity.User{ID: id, Email: "old@example.com"}
@@ -469,8 +576,9 @@ func TestUpdate_WithEmptyPassword(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	id := "123"
 	existingUser := &user_entity.User{ID: id, Email: "old@example.com", Password: "old-hash"}
@@ -485,6 +593,7 @@ func TestUpdate_WithEmptyPassword(t *testing.T) {
 		// When empty password is provided, it gets set to empty string
 		return u.Password == ""
 	})).Return(existingUser, nil)
+	mockEvent.On("Dispatch", mock.Anything).Return()
 
 	user, err := usecase.Update(id, input)
 
@@ -499,8 +608,9 @@ func TestUpdate_PasswordHashError(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	id := "123"
 	existingUser := &user_entity.User{ID: id, Email: "test@example.com", Password: "old-hash"}
@@ -527,8 +637,9 @@ func TestDelete_EmptyID(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	err := usecase.Delete("")
 
@@ -540,8 +651,9 @@ func TestDelete_UserNotFound(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockCrypto := new(MockBcryptAdapter)
 	mockEvent := new(MockEvent)
+	mockSessions := new(MockSessionRevoker)
 
-	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, mockSessions)
 
 	mockRepo.On("FindByID", "999").Return((*user_entity.User)(nil), errors.New("not found"))
 
@@ -552,6 +664,158 @@ func TestDelete_UserNotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestMarkEmailVerified_Success(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	usecase := user_usecase.NewUserUsecase(mockRepo, new(MockBcryptAdapter), new(MockEvent), new(MockSessionRevoker))
+
+	user := &user_entity.User{ID: "123"}
+	mockRepo.On("FindByID", "123").Return(user, nil)
+	mockRepo.On("Update", "123", mock.MatchedBy(func(u *user_entity.User) bool {
+		return u.IsEmailVerified()
+	})).Return(user, nil)
+
+	err := usecase.MarkEmailVerified("123")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMarkEmailVerified_AlreadyVerified(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	usecase := user_usecase.NewUserUsecase(mockRepo, new(MockBcryptAdapter), new(MockEvent), new(MockSessionRevoker))
+
+	verifiedAt := time.Now()
+	mockRepo.On("FindByID", "123").Return(&user_entity.User{ID: "123", EmailVerifiedAt: &verifiedAt}, nil)
+
+	err := usecase.MarkEmailVerified("123")
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+type MockPasswordPolicy struct {
+	mock.Mock
+}
+
+func (m *MockPasswordPolicy) Validate(user *user_entity.User, password string) error {
+	return m.Called(user, password).Error(0)
+}
+
+func (m *MockPasswordPolicy) Remember(userID, hash string) error {
+	return m.Called(userID, hash).Error(0)
+}
+
+func TestCreate_PasswordPolicyViolation(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockCrypto := new(MockBcryptAdapter)
+	mockPasswords := new(MockPasswordPolicy)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, new(MockEvent), new(MockSessionRevoker), user_usecase.WithPasswordPolicy(mockPasswords))
+
+	input := dtos.AddUserDto{Name: "Alice", Surname: "Silva", Nickname: "ali", Email: "alice@example.com", Password: "alice123"}
+	violation := &user_entity.ValidationError{}
+	violation.Add("password", "personal_info", "password must not contain your name or email")
+
+	mockRepo.On("FindByEmail", input.Email).Return(nil, port_user_repository.ErrUserNotFound)
+	mockPasswords.On("Validate", mock.MatchedBy(func(u *user_entity.User) bool {
+		return u.Name == "Alice" && u.Email == input.Email
+	}), "alice123").Return(violation)
+
+	user, err := usecase.Create(input)
+
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, violation)
+	mockCrypto.AssertNotCalled(t, "Hash", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestCreate_RemembersPassword(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockCrypto := new(MockBcryptAdapter)
+	mockEvent := new(MockEvent)
+	mockPasswords := new(MockPasswordPolicy)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, new(MockSessionRevoker), user_usecase.WithPasswordPolicy(mockPasswords))
+
+	input := dtos.AddUserDto{Name: "Alice", Surname: "Silva", Nickname: "ali", Email: "alice@example.com", Password: "Secure123"}
+
+	mockRepo.On("FindByEmail", input.Email).Return(nil, port_user_repository.ErrUserNotFound)
+	mockPasswords.On("Validate", mock.Anything, "Secure123").Return(nil)
+	mockCrypto.On("Hash", "Secure123").Return("hashed", nil)
+	mockRepo.On("Save", mock.Anything).Return(&user_entity.User{ID: "user-1", Email: input.Email}, nil)
+	mockEvent.On("Dispatch", mock.Anything).Return()
+	mockPasswords.On("Remember", "user-1", "hashed").Return(errors.New("db error"))
+
+	user, err := usecase.Create(input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", user.ID)
+	mockPasswords.AssertExpectations(t)
+}
+
+func TestUpdate_PasswordPolicyUsesUpdatedProfile(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockCrypto := new(MockBcryptAdapter)
+	mockPasswords := new(MockPasswordPolicy)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, new(MockEvent), new(MockSessionRevoker), user_usecase.WithPasswordPolicy(mockPasswords))
+
+	existing := &user_entity.User{ID: "123", Name: "Alice", Email: "alice@example.com", Password: "old-hash"}
+	violation := &user_entity.ValidationError{}
+	violation.Add("password", "personal_info", "password must not contain your name or email")
+
+	mockRepo.On("FindByID", "123").Return(existing, nil)
+	mockPasswords.On("Validate", mock.MatchedBy(func(u *user_entity.User) bool {
+		return u.ID == "123" && u.Name == "Beatriz" && u.Password == "old-hash"
+	}), "Beatriz99").Return(violation)
+
+	_, err := usecase.Update("123", dtos.UpdateUserDto{Name: strPtr("Beatriz"), Password: strPtr("Beatriz99")})
+
+	assert.ErrorIs(t, err, violation)
+	assert.Equal(t, "Alice", existing.Name)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdate_PasswordPolicyKeepsStoredSurname(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockCrypto := new(MockBcryptAdapter)
+	mockPasswords := new(MockPasswordPolicy)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, new(MockEvent), new(MockSessionRevoker), user_usecase.WithPasswordPolicy(mockPasswords))
+
+	existing := &user_entity.User{ID: "123", Name: "Alice", Surname: "Silva", Email: "alice@example.com", Password: "old-hash"}
+	violation := &user_entity.ValidationError{}
+	violation.Add("password", "personal_info", "password must not contain your name or email")
+
+	mockRepo.On("FindByID", "123").Return(existing, nil)
+	mockPasswords.On("Validate", mock.MatchedBy(func(u *user_entity.User) bool {
+		return u.Surname == "Silva"
+	}), "Silva2024!").Return(violation)
+
+	_, err := usecase.Update("123", dtos.UpdateUserDto{Surname: strPtr("Souza"), Password: strPtr("Silva2024!")})
+
+	assert.ErrorIs(t, err, violation)
+	mockPasswords.AssertExpectations(t)
+}
+
+func TestUpdate_RemembersNewPassword(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockCrypto := new(MockBcryptAdapter)
+	mockPasswords := new(MockPasswordPolicy)
+	mockEvent := new(MockEvent)
+	usecase := user_usecase.NewUserUsecase(mockRepo, mockCrypto, mockEvent, new(MockSessionRevoker), user_usecase.WithPasswordPolicy(mockPasswords))
+
+	existing := &user_entity.User{ID: "123", Email: "alice@example.com", Password: "old-hash"}
+	mockEvent.On("Dispatch", mock.Anything).Return()
+
+	mockRepo.On("FindByID", "123").Return(existing, nil)
+	mockPasswords.On("Validate", mock.Anything, "Tr0ubadour").Return(nil)
+	mockCrypto.On("Hash", "Tr0ubadour").Return("new-hash", nil)
+	mockRepo.On("Update", "123", mock.Anything).Return(existing, nil)
+	mockPasswords.On("Remember", "123", "new-hash").Return(nil)
+
+	_, err := usecase.Update("123", dtos.UpdateUserDto{Password: strPtr("Tr0ubadour")})
+
+	assert.NoError(t, err)
+	mockPasswords.AssertExpectations(t)
+}
+
 func strPtr(s string) *string {
 	return &s
 }
@@ -1,6 +1,7 @@
 package user_entity
 
 import (
+	"strings"
 	"time"
 
 	userEvent "github.com/williamkoller/system-education/internal/user/domain/event"
@@ -8,15 +9,17 @@ import (
 )
 
 type User struct {
-	ID        string
-	Name      string
-	Surname   string
-	Nickname  string
-	Age       int32
-	Email     string
-	Password  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID       string
+	Name     string
+	Surname  string
+	Nickname string
+	Age      int32
+	Email    string
+	Password string
+	// EmailVerifiedAt is set once the user proves the address is theirs.
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 	sharedEvent.AggregateRoot
 }
 
@@ -66,6 +69,16 @@ func (u *User) GetPassword() string {
 	return u.Password
 }
 
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) MarkEmailVerified(at time.Time) {
+	if u.EmailVerifiedAt == nil {
+		u.EmailVerifiedAt = &at
+	}
+}
+
 func (u *User) PullDomainEvents() []sharedEvent.Event {
 	if u == nil {
 		return nil
@@ -73,7 +86,22 @@ func (u *User) PullDomainEvents() []sharedEvent.Event {
 	return u.AggregateRoot.PullDomainEvents()
 }
 
+// UpdateUser applies an operator's edits. A new email address has not been
+// proven by anyone, so it loses its verification. Nothing is changed unless
+// every field is valid.
 func (u *User) UpdateUser(name, nickname, email, password *string, age *int32) (*User, error) {
+	candidate := &User{Age: u.Age, Email: u.Email}
+	if email != nil {
+		candidate.Email = *email
+	}
+	if age != nil {
+		candidate.Age = *age
+	}
+	if _, err := ValidationUpdateUser(candidate); err != nil {
+		return nil, err
+	}
+
+	previousEmail := u.Email
 	if name != nil {
 		u.Name = *name
 	}
@@ -90,10 +118,69 @@ func (u *User) UpdateUser(name, nickname, email, password *string, age *int32) (
 		u.Age = *age
 	}
 
-	uv, err := ValidationUpdateUser(u)
-	if err != nil {
-		return nil, err
+	u.AddDomainEvent(userEvent.NewUserUpdatedEvent(u.ID, u.Name, u.Surname, u.Nickname, u.Email))
+	if !strings.EqualFold(previousEmail, u.Email) {
+		u.EmailVerifiedAt = nil
+		u.AddDomainEvent(userEvent.NewUserEmailChangedEvent(u.ID, previousEmail, u.Email, false))
 	}
 
-	return uv, nil
+	return u, nil
+}
+
+// ChangeEmail moves the user to an address they have just proven is theirs.
+func (u *User) ChangeEmail(email string, verifiedAt time.Time) {
+	previousEmail := u.Email
+	u.Email = email
+	u.EmailVerifiedAt = &verifiedAt
+
+	u.AddDomainEvent(userEvent.NewUserUpdatedEvent(u.ID, u.Name, u.Surname, u.Nickname, u.Email))
+	u.AddDomainEvent(userEvent.NewUserEmailChangedEvent(u.ID, previousEmail, u.Email, true))
+}
+
+// MarkDeleted records that the user is being deleted. Removing the row is
+// left to the repository.
+func (u *User) MarkDeleted() {
+	u.AddDomainEvent(userEvent.NewUserDeletedEvent(u.ID, u.Email))
+}
+
+// UpdateProfile applies the edits a user may make to their own account.
+// Email and password go through the account flows that mail a link
+// (email change, password reset) and are deliberately not accepted here.
+// Nothing is changed unless every field is valid.
+func (u *User) UpdateProfile(name, surname, nickname *string, age *int32) (*User, error) {
+	errs := &ValidationError{}
+
+	if name != nil && strings.TrimSpace(*name) == "" {
+		errs.Add("name", "required", "name is required")
+	}
+	if surname != nil && strings.TrimSpace(*surname) == "" {
+		errs.Add("surname", "required", "surname is required")
+	}
+	if nickname != nil && strings.TrimSpace(*nickname) == "" {
+		errs.Add("nickname", "required", "nickname is required")
+	}
+	if age != nil && *age < 0 {
+		errs.Add("age", "min", "age cannot be negative")
+	}
+
+	if errs.HasErrors() {
+		return nil, errs
+	}
+
+	if name != nil {
+		u.Name = *name
+	}
+	if surname != nil {
+		u.Surname = *surname
+	}
+	if nickname != nil {
+		u.Nickname = *nickname
+	}
+	if age != nil {
+		u.Age = *age
+	}
+
+	u.AddDomainEvent(userEvent.NewUserUpdatedEvent(u.ID, u.Name, u.Surname, u.Nickname, u.Email))
+
+	return u, nil
 }
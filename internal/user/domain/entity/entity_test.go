@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	userEvent "github.com/williamkoller/system-education/internal/user/domain/event"
 )
 
 func TestNewUser(t *testing.T) {
@@ -442,3 +443,152 @@ func TestUpdateUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUser_MarkEmailVerified(t *testing.T) {
+	user := &User{ID: "123"}
+	assert.False(t, user.IsEmailVerified())
+
+	first := time.Now()
+	user.MarkEmailVerified(first)
+	user.MarkEmailVerified(first.Add(time.Hour))
+
+	assert.True(t, user.IsEmailVerified())
+	assert.Equal(t, first, *user.EmailVerifiedAt)
+}
+
+func TestUser_UpdateProfile(t *testing.T) {
+	newUser := func() *User {
+		return &User{
+			ID:       "123",
+			Name:     "Alice",
+			Surname:  "Smith",
+			Nickname: "alices",
+			Age:      28,
+			Email:    "alice@example.com",
+			Password: "hashed",
+		}
+	}
+	str := func(s string) *string { return &s }
+	age := func(a int32) *int32 { return &a }
+
+	t.Run("should apply provided fields only", func(t *testing.T) {
+		user := newUser()
+
+		updated, err := user.UpdateProfile(str("Alicia"), nil, str("ali"), age(29))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Alicia", updated.Name)
+		assert.Equal(t, "Smith", updated.Surname)
+		assert.Equal(t, "ali", updated.Nickname)
+		assert.Equal(t, int32(29), updated.Age)
+		assert.Equal(t, "alice@example.com", updated.Email)
+		assert.Equal(t, "hashed", updated.Password)
+
+		events := user.PullDomainEvents()
+		assert.Len(t, events, 1)
+		assert.Equal(t, "user.updated", events[0].EventName())
+	})
+
+	t.Run("should reject blank fields without changing the user", func(t *testing.T) {
+		user := newUser()
+
+		updated, err := user.UpdateProfile(str("Bob"), str("  "), nil, age(-1))
+
+		assert.Nil(t, updated)
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Contains(t, err.Error(), "surname is required")
+		assert.Contains(t, err.Error(), "age cannot be negative")
+		assert.Equal(t, "Alice", user.Name)
+		assert.Equal(t, int32(28), user.Age)
+		assert.Empty(t, user.PullDomainEvents())
+	})
+}
+
+func TestUser_UpdateUser_RecordsEvent(t *testing.T) {
+	user := &User{ID: "123", Name: "Alice", Email: "alice@example.com", Password: "hashed"}
+	name := "Alicia"
+
+	_, err := user.UpdateUser(&name, nil, nil, nil, nil)
+	assert.NoError(t, err)
+
+	events := user.PullDomainEvents()
+	assert.Len(t, events, 1)
+	updated := events[0].(*userEvent.UserUpdatedEvent)
+	assert.Equal(t, "123", updated.UserID)
+	assert.Equal(t, "Alicia", updated.Name)
+
+	email := "invalid"
+	_, err = user.UpdateUser(nil, nil, &email, nil, nil)
+	assert.Error(t, err)
+	assert.Empty(t, user.PullDomainEvents())
+}
+
+func TestUser_UpdateUser_RejectsWithoutChangingTheUser(t *testing.T) {
+	verifiedAt := time.Now()
+	user := &User{ID: "123", Name: "Alice", Email: "alice@example.com", Age: 28, Password: "hashed", EmailVerifiedAt: &verifiedAt}
+	name := "Alicia"
+	email := "other@example.com"
+	negativeAge := int32(-1)
+
+	updated, err := user.UpdateUser(&name, nil, &email, nil, &negativeAge)
+
+	assert.Nil(t, updated)
+	assert.Contains(t, err.Error(), "age cannot be negative")
+	assert.Equal(t, "Alice", user.Name)
+	assert.Equal(t, "alice@example.com", user.Email)
+	assert.Equal(t, int32(28), user.Age)
+	assert.True(t, user.IsEmailVerified())
+	assert.Empty(t, user.PullDomainEvents())
+}
+
+func TestUser_MarkDeleted(t *testing.T) {
+	user := &User{ID: "123", Email: "alice@example.com"}
+
+	user.MarkDeleted()
+
+	events := user.PullDomainEvents()
+	assert.Len(t, events, 1)
+	deleted := events[0].(*userEvent.UserDeletedEvent)
+	assert.Equal(t, "123", deleted.UserID)
+	assert.Equal(t, "alice@example.com", deleted.Email)
+}
+
+func TestUser_UpdateUser_EmailChangeClearsVerification(t *testing.T) {
+	verifiedAt := time.Now()
+	user := &User{ID: "123", Name: "Alice", Email: "alice@example.com", Password: "hashed", EmailVerifiedAt: &verifiedAt}
+
+	email := "ALICE@example.com"
+	_, err := user.UpdateUser(nil, nil, &email, nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, user.IsEmailVerified())
+	assert.Len(t, user.PullDomainEvents(), 1)
+
+	email = "other@example.com"
+	_, err = user.UpdateUser(nil, nil, &email, nil, nil)
+	assert.NoError(t, err)
+	assert.False(t, user.IsEmailVerified())
+
+	events := user.PullDomainEvents()
+	assert.Len(t, events, 2)
+	changed := events[1].(*userEvent.UserEmailChangedEvent)
+	assert.Equal(t, "ALICE@example.com", changed.PreviousEmail)
+	assert.Equal(t, "other@example.com", changed.Email)
+	assert.False(t, changed.Verified)
+}
+
+func TestUser_ChangeEmail(t *testing.T) {
+	user := &User{ID: "123", Email: "alice@example.com"}
+	at := time.Now()
+
+	user.ChangeEmail("new@example.com", at)
+
+	assert.Equal(t, "new@example.com", user.Email)
+	assert.Equal(t, at, *user.EmailVerifiedAt)
+
+	events := user.PullDomainEvents()
+	assert.Len(t, events, 2)
+	changed := events[1].(*userEvent.UserEmailChangedEvent)
+	assert.Equal(t, "alice@example.com", changed.PreviousEmail)
+	assert.True(t, changed.Verified)
+}
@@ -0,0 +1,108 @@
+package user_entity
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// PasswordPolicy holds the rules a new password must satisfy. The checks
+// that need stored state, reuse of recent passwords and breached password
+// lists, are applied by the password policy service on top of Check.
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// HistorySize is how many previous passwords cannot be reused; zero
+	// allows reuse.
+	HistorySize int
+	// ForbidPersonalInfo rejects passwords containing the user's name,
+	// surname, nickname or the local part of their email.
+	ForbidPersonalInfo bool
+}
+
+// minPersonalInfoLength keeps short names like "Li" from ruling out every
+// password that happens to contain them.
+const minPersonalInfoLength = 3
+
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:          8,
+		MaxLength:          72,
+		RequireUpper:       true,
+		RequireLower:       true,
+		RequireDigit:       true,
+		HistorySize:        5,
+		ForbidPersonalInfo: true,
+	}
+}
+
+// Check reports every rule the password breaks for the given user, who may
+// not be saved yet. Lengths are counted in bytes, which is what the hashers
+// limit.
+func (p PasswordPolicy) Check(password string, user *User) []FieldError {
+	errs := &ValidationError{}
+
+	if strings.TrimSpace(password) == "" {
+		errs.Add("password", "required", "password is required")
+		return errs.Fields
+	}
+
+	if p.MinLength > 0 && len(password) < p.MinLength {
+		errs.Add("password", "min_length", fmt.Sprintf("password must be at least %d characters", p.MinLength))
+	}
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		errs.Add("password", "max_length", fmt.Sprintf("password must be at most %d bytes", p.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		errs.Add("password", "uppercase", "password must contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		errs.Add("password", "lowercase", "password must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		errs.Add("password", "digit", "password must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		errs.Add("password", "symbol", "password must contain a symbol")
+	}
+
+	if p.ForbidPersonalInfo && user != nil && containsPersonalInfo(password, user) {
+		errs.Add("password", "personal_info", "password must not contain your name or email")
+	}
+
+	return errs.Fields
+}
+
+func containsPersonalInfo(password string, user *User) bool {
+	lowered := strings.ToLower(password)
+
+	local, _, _ := strings.Cut(user.Email, "@")
+	for _, value := range []string{user.Name, user.Surname, user.Nickname, local} {
+		for _, part := range strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if len([]rune(part)) >= minPersonalInfoLength && strings.Contains(lowered, part) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package user_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rules(fields []FieldError) []string {
+	out := []string{}
+	for _, field := range fields {
+		out = append(out, field.Rule)
+	}
+	return out
+}
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	user := &User{Name: "Maria", Surname: "Li", Nickname: "mlz", Email: "msilva@example.com"}
+
+	tests := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		want     []string
+	}{
+		{name: "valid", policy: DefaultPasswordPolicy(), password: "Tr0ubadour", want: []string{}},
+		{name: "blank", policy: DefaultPasswordPolicy(), password: "   ", want: []string{"required"}},
+		{name: "too short", policy: DefaultPasswordPolicy(), password: "Ab1", want: []string{"min_length"}},
+		{name: "too long", policy: DefaultPasswordPolicy(), password: "Ab1" + string(make([]byte, 70)), want: []string{"max_length"}},
+		{name: "missing classes", policy: DefaultPasswordPolicy(), password: "lowercaseonly", want: []string{"uppercase", "digit"}},
+		{name: "symbol required", policy: PasswordPolicy{RequireSymbol: true}, password: "NoSymbol1", want: []string{"symbol"}},
+		{name: "contains name", policy: DefaultPasswordPolicy(), password: "MARIA2024xyz", want: []string{"personal_info"}},
+		{name: "contains email local part", policy: DefaultPasswordPolicy(), password: "Xmsilva9", want: []string{"personal_info"}},
+		{name: "contains nickname", policy: DefaultPasswordPolicy(), password: "Hello-mlz-1", want: []string{"personal_info"}},
+		{name: "short surname ignored", policy: DefaultPasswordPolicy(), password: "Lithium42", want: []string{}},
+		{name: "personal info allowed", policy: PasswordPolicy{}, password: "maria", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rules(tt.policy.Check(tt.password, user)))
+		})
+	}
+}
+
+func TestPasswordPolicy_CheckWithoutUser(t *testing.T) {
+	assert.Empty(t, DefaultPasswordPolicy().Check("Tr0ubadour", nil))
+}
@@ -5,63 +5,89 @@ import (
 	"strings"
 )
 
+// FieldError describes one failed rule so clients can point at the field
+// instead of parsing the message.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
 type ValidationError struct {
 	Errors []string
+	Fields []FieldError
 }
 
 func (v *ValidationError) Error() string {
 	return fmt.Sprintf("validation failed: %s", strings.Join(v.Errors, ", "))
 }
 
+// Details exposes the field errors to the HTTP error handler.
+func (v *ValidationError) Details() any {
+	if len(v.Fields) == 0 {
+		return nil
+	}
+	return v.Fields
+}
+
+func (v *ValidationError) Add(field, rule, message string) {
+	v.Errors = append(v.Errors, message)
+	v.Fields = append(v.Fields, FieldError{Field: field, Rule: rule, Message: message})
+}
+
+func (v *ValidationError) HasErrors() bool {
+	return len(v.Errors) > 0
+}
+
 func ValidationUser(u *User) (*User, error) {
-	var errs []string
+	errs := &ValidationError{}
 
 	if strings.TrimSpace(u.Name) == "" {
-		errs = append(errs, "name is required")
+		errs.Add("name", "required", "name is required")
 	}
 
 	if strings.TrimSpace(u.Surname) == "" {
-		errs = append(errs, "surname is required")
+		errs.Add("surname", "required", "surname is required")
 	}
 
 	if u.Age < 0 {
-		errs = append(errs, "age cannot be negative")
+		errs.Add("age", "min", "age cannot be negative")
 	}
 
 	if strings.TrimSpace(u.Email) == "" {
-		errs = append(errs, "email is required")
+		errs.Add("email", "required", "email is required")
 	} else if !strings.Contains(u.Email, "@") {
-		errs = append(errs, "email is invalid")
+		errs.Add("email", "email", "email is invalid")
 	}
 
 	if strings.TrimSpace(u.Nickname) == "" {
-		errs = append(errs, "nickname is required")
+		errs.Add("nickname", "required", "nickname is required")
 	}
 
 	if strings.TrimSpace(u.Password) == "" {
-		errs = append(errs, "password is required")
+		errs.Add("password", "required", "password is required")
 	}
 
-	if len(errs) > 0 {
-		return nil, &ValidationError{Errors: errs}
+	if errs.HasErrors() {
+		return nil, errs
 	}
 
 	return u, nil
 }
 
 func ValidationUpdateUser(user *User) (*User, error) {
-	var errs []string
+	errs := &ValidationError{}
 	if user.Age < 0 {
-		errs = append(errs, "age cannot be negative")
+		errs.Add("age", "min", "age cannot be negative")
 	}
 
 	if !strings.Contains(user.Email, "@") {
-		errs = append(errs, "email is invalid")
+		errs.Add("email", "email", "email is invalid")
 	}
 
-	if len(errs) > 0 {
-		return nil, &ValidationError{Errors: errs}
+	if errs.HasErrors() {
+		return nil, errs
 	}
 
 	return user, nil
-}
\ No newline at end of file
+}
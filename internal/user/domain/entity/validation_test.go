@@ -191,3 +191,15 @@ func TestValidationUpdateUser(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationError_Add(t *testing.T) {
+	ve := &ValidationError{}
+	assert.False(t, ve.HasErrors())
+	assert.Nil(t, ve.Details())
+
+	ve.Add("password", "min_length", "password must be at least 8 characters")
+
+	assert.True(t, ve.HasErrors())
+	assert.Equal(t, []string{"password must be at least 8 characters"}, ve.Errors)
+	assert.Equal(t, []FieldError{{Field: "password", Rule: "min_length", Message: "password must be at least 8 characters"}}, ve.Details())
+}
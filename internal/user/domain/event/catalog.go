@@ -0,0 +1,13 @@
+package user_event
+
+import shared_event "github.com/williamkoller/system-education/shared/domain/event"
+
+// Catalog lists the events the user module raises.
+func Catalog() []func() shared_event.Event {
+	return []func() shared_event.Event{
+		func() shared_event.Event { return &UserCreatedEvent{} },
+		func() shared_event.Event { return &UserUpdatedEvent{} },
+		func() shared_event.Event { return &UserDeletedEvent{} },
+		func() shared_event.Event { return &UserEmailChangedEvent{} },
+	}
+}
@@ -1,8 +1,13 @@
 package user_event
 
-import "time"
+import (
+	"time"
+
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
 
 type UserCreatedEvent struct {
+	shared_event.Identity
 	UserID string
 	Name   string
 	Email  string
@@ -0,0 +1,26 @@
+package user_event
+
+import (
+	"time"
+
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type UserDeletedEvent struct {
+	shared_event.Identity
+	UserID string
+	Email  string
+	Date   time.Time
+}
+
+func NewUserDeletedEvent(id, email string) *UserDeletedEvent {
+	return &UserDeletedEvent{UserID: id, Email: email, Date: time.Now()}
+}
+
+func (e *UserDeletedEvent) EventName() string {
+	return "user.deleted"
+}
+
+func (e *UserDeletedEvent) OccurredOn() time.Time {
+	return e.Date
+}
@@ -0,0 +1,31 @@
+package user_event
+
+import (
+	"time"
+
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+// UserEmailChangedEvent is raised when a user's address changes. Verified
+// reports whether the new address was already proven, so listeners know
+// whether to ask for verification.
+type UserEmailChangedEvent struct {
+	shared_event.Identity
+	UserID        string
+	PreviousEmail string
+	Email         string
+	Verified      bool
+	Date          time.Time
+}
+
+func NewUserEmailChangedEvent(id, previousEmail, email string, verified bool) *UserEmailChangedEvent {
+	return &UserEmailChangedEvent{UserID: id, PreviousEmail: previousEmail, Email: email, Verified: verified, Date: time.Now()}
+}
+
+func (e *UserEmailChangedEvent) EventName() string {
+	return "user.email_changed"
+}
+
+func (e *UserEmailChangedEvent) OccurredOn() time.Time {
+	return e.Date
+}
@@ -0,0 +1,50 @@
+package user_event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserUpdatedEvent(t *testing.T) {
+	e := NewUserUpdatedEvent("123", "William", "Koller", "will", "william@example.com")
+
+	assert.Equal(t, "123", e.UserID)
+	assert.Equal(t, "Koller", e.Surname)
+	assert.Equal(t, "will", e.Nickname)
+	assert.Equal(t, "william@example.com", e.Email)
+	assert.Equal(t, "user.updated", e.EventName())
+	assert.Equal(t, e.Date, e.OccurredOn())
+	assert.False(t, e.Date.IsZero())
+}
+
+func TestNewUserDeletedEvent(t *testing.T) {
+	e := NewUserDeletedEvent("123", "william@example.com")
+
+	assert.Equal(t, "123", e.UserID)
+	assert.Equal(t, "william@example.com", e.Email)
+	assert.Equal(t, "user.deleted", e.EventName())
+	assert.Equal(t, e.Date, e.OccurredOn())
+	assert.False(t, e.Date.IsZero())
+}
+
+func TestNewUserEmailChangedEvent(t *testing.T) {
+	e := NewUserEmailChangedEvent("123", "old@example.com", "new@example.com", false)
+
+	assert.Equal(t, "123", e.UserID)
+	assert.Equal(t, "old@example.com", e.PreviousEmail)
+	assert.Equal(t, "new@example.com", e.Email)
+	assert.False(t, e.Verified)
+	assert.Equal(t, "user.email_changed", e.EventName())
+	assert.Equal(t, e.Date, e.OccurredOn())
+	assert.False(t, e.Date.IsZero())
+}
+
+func TestCatalog(t *testing.T) {
+	var names []string
+	for _, factory := range Catalog() {
+		names = append(names, factory().EventName())
+	}
+
+	assert.Equal(t, []string{"user.created", "user.updated", "user.deleted", "user.email_changed"}, names)
+}
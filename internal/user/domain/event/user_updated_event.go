@@ -0,0 +1,29 @@
+package user_event
+
+import (
+	"time"
+
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type UserUpdatedEvent struct {
+	shared_event.Identity
+	UserID   string
+	Name     string
+	Surname  string
+	Nickname string
+	Email    string
+	Date     time.Time
+}
+
+func NewUserUpdatedEvent(id, name, surname, nickname, email string) *UserUpdatedEvent {
+	return &UserUpdatedEvent{UserID: id, Name: name, Surname: surname, Nickname: nickname, Email: email, Date: time.Now()}
+}
+
+func (e *UserUpdatedEvent) EventName() string {
+	return "user.updated"
+}
+
+func (e *UserUpdatedEvent) OccurredOn() time.Time {
+	return e.Date
+}
@@ -1,6 +1,8 @@
 package user_cryptography
 
 import (
+	"strings"
+
 	portCryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -10,6 +12,7 @@ type BcryptAdapter struct {
 }
 
 var _ portCryptography.Bcrypt = (*BcryptAdapter)(nil)
+var _ portCryptography.Rehasher = (*BcryptAdapter)(nil)
 
 func NewBcryptHasher(cost int) *BcryptAdapter {
 	if cost == 0 {
@@ -31,3 +34,13 @@ func (b *BcryptAdapter) HashComparer(plaintext string, hashed string) (bool, err
 	}
 	return true, nil
 }
+
+// NeedsRehash reports hashes that are not bcrypt or use a lower cost.
+func (b *BcryptAdapter) NeedsRehash(hashed string) bool {
+	cost, err := bcrypt.Cost([]byte(hashed))
+	return err != nil || cost < b.cost
+}
+
+func isBcryptHash(hashed string) bool {
+	return strings.HasPrefix(hashed, "$2a$") || strings.HasPrefix(hashed, "$2b$") || strings.HasPrefix(hashed, "$2y$")
+}
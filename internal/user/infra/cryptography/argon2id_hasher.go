@@ -0,0 +1,150 @@
+package user_cryptography
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	portCryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
+	"golang.org/x/crypto/argon2"
+)
+
+var ErrInvalidArgon2Hash = errors.New("invalid argon2id hash")
+
+// Argon2Params are the argon2id cost parameters. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follows the OWASP baseline of 64 MiB and 3 passes.
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// Argon2idHasher stores hashes in the PHC string format,
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>, so the parameters a hash was
+// made with travel with it.
+type Argon2idHasher struct {
+	params Argon2Params
+}
+
+var _ portCryptography.Bcrypt = (*Argon2idHasher)(nil)
+var _ portCryptography.Rehasher = (*Argon2idHasher)(nil)
+
+func NewArgon2idHasher(params Argon2Params) *Argon2idHasher {
+	defaults := DefaultArgon2Params()
+	if params.Memory == 0 {
+		params.Memory = defaults.Memory
+	}
+	if params.Iterations == 0 {
+		params.Iterations = defaults.Iterations
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = defaults.Parallelism
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = defaults.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = defaults.KeyLength
+	}
+	return &Argon2idHasher{params: params}
+}
+
+func (a *Argon2idHasher) Hash(plaintext string) (string, error) {
+	salt := make([]byte, a.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(plaintext), salt, a.params.Iterations, a.params.Memory, a.params.Parallelism, a.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		a.params.Memory,
+		a.params.Iterations,
+		a.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// HashComparer recomputes the key with the parameters stored in hashed, so
+// hashes made before a parameter change still verify.
+func (a *Argon2idHasher) HashComparer(plaintext string, hashed string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(hashed)
+	if err != nil {
+		return false, err
+	}
+
+	computed := argon2.IDKey([]byte(plaintext), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(key, computed) != 1 {
+		return false, errors.New("password does not match")
+	}
+	return true, nil
+}
+
+// NeedsRehash reports hashes that are not argon2id or were made with less
+// memory, fewer passes, less parallelism or a shorter key than configured.
+func (a *Argon2idHasher) NeedsRehash(hashed string) bool {
+	params, _, _, err := decodeArgon2id(hashed)
+	if err != nil {
+		return true
+	}
+	return params.Memory < a.params.Memory ||
+		params.Iterations < a.params.Iterations ||
+		params.Parallelism < a.params.Parallelism ||
+		params.KeyLength < a.params.KeyLength
+}
+
+func isArgon2idHash(hashed string) bool {
+	return strings.HasPrefix(hashed, "$argon2id$")
+}
+
+func decodeArgon2id(hashed string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrInvalidArgon2Hash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrInvalidArgon2Hash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrInvalidArgon2Hash
+	}
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, ErrInvalidArgon2Hash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return params, nil, nil, ErrInvalidArgon2Hash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrInvalidArgon2Hash
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
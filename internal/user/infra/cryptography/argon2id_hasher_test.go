@@ -0,0 +1,71 @@
+package user_cryptography_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	user_cryptography "github.com/williamkoller/system-education/internal/user/infra/cryptography"
+)
+
+// Small parameters keep the tests fast; the encoding is what matters here.
+var testArgon2Params = user_cryptography.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestArgon2idHasher_HashAndCompare(t *testing.T) {
+	hasher := user_cryptography.NewArgon2idHasher(testArgon2Params)
+
+	hash, err := hasher.Hash("supersecret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	ok, err := hasher.HashComparer("supersecret", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.HashComparer("wrongpassword", hash)
+	assert.Error(t, err)
+	assert.False(t, ok)
+}
+
+func TestArgon2idHasher_SaltsEachHash(t *testing.T) {
+	hasher := user_cryptography.NewArgon2idHasher(testArgon2Params)
+
+	first, _ := hasher.Hash("supersecret")
+	second, _ := hasher.Hash("supersecret")
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestArgon2idHasher_VerifiesWithStoredParameters(t *testing.T) {
+	old, err := user_cryptography.NewArgon2idHasher(testArgon2Params).Hash("supersecret")
+	require.NoError(t, err)
+
+	stronger := user_cryptography.NewArgon2idHasher(user_cryptography.Argon2Params{Memory: 2048, Iterations: 2, Parallelism: 1})
+
+	ok, err := stronger.HashComparer("supersecret", old)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, stronger.NeedsRehash(old))
+
+	current, _ := stronger.Hash("supersecret")
+	assert.False(t, stronger.NeedsRehash(current))
+}
+
+func TestArgon2idHasher_InvalidHashes(t *testing.T) {
+	hasher := user_cryptography.NewArgon2idHasher(testArgon2Params)
+
+	for _, hash := range []string{
+		"",
+		"$2a$10$abcdefghijklmnopqrstuv",
+		"$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA",
+	} {
+		ok, err := hasher.HashComparer("supersecret", hash)
+		assert.ErrorIs(t, err, user_cryptography.ErrInvalidArgon2Hash, hash)
+		assert.False(t, ok)
+		assert.True(t, hasher.NeedsRehash(hash), hash)
+	}
+}
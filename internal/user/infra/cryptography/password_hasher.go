@@ -0,0 +1,65 @@
+package user_cryptography
+
+import (
+	"errors"
+	"fmt"
+
+	portCryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var ErrUnknownHashAlgorithm = errors.New("unknown password hash algorithm")
+
+// PasswordHasher hashes new passwords with the configured algorithm and
+// verifies hashes made by either supported one, picking the algorithm from
+// the hash prefix. Stored bcrypt hashes keep working after switching to
+// argon2id and are flagged by NeedsRehash until the user logs in again.
+type PasswordHasher struct {
+	algorithm string
+	bcrypt    *BcryptAdapter
+	argon2id  *Argon2idHasher
+}
+
+var _ portCryptography.Bcrypt = (*PasswordHasher)(nil)
+var _ portCryptography.Rehasher = (*PasswordHasher)(nil)
+
+func NewPasswordHasher(algorithm string, bcryptCost int, argon2Params Argon2Params) (*PasswordHasher, error) {
+	if algorithm != AlgorithmArgon2id && algorithm != AlgorithmBcrypt {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownHashAlgorithm, algorithm)
+	}
+
+	return &PasswordHasher{
+		algorithm: algorithm,
+		bcrypt:    NewBcryptHasher(bcryptCost),
+		argon2id:  NewArgon2idHasher(argon2Params),
+	}, nil
+}
+
+func (h *PasswordHasher) Hash(plaintext string) (string, error) {
+	if h.algorithm == AlgorithmBcrypt {
+		return h.bcrypt.Hash(plaintext)
+	}
+	return h.argon2id.Hash(plaintext)
+}
+
+func (h *PasswordHasher) HashComparer(plaintext string, hashed string) (bool, error) {
+	switch {
+	case isArgon2idHash(hashed):
+		return h.argon2id.HashComparer(plaintext, hashed)
+	case isBcryptHash(hashed):
+		return h.bcrypt.HashComparer(plaintext, hashed)
+	default:
+		return false, ErrUnknownHashAlgorithm
+	}
+}
+
+func (h *PasswordHasher) NeedsRehash(hashed string) bool {
+	if h.algorithm == AlgorithmBcrypt {
+		return !isBcryptHash(hashed) || h.bcrypt.NeedsRehash(hashed)
+	}
+	return !isArgon2idHash(hashed) || h.argon2id.NeedsRehash(hashed)
+}
@@ -0,0 +1,65 @@
+package user_cryptography_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	user_cryptography "github.com/williamkoller/system-education/internal/user/infra/cryptography"
+)
+
+func TestNewPasswordHasher_UnknownAlgorithm(t *testing.T) {
+	_, err := user_cryptography.NewPasswordHasher("md5", 4, testArgon2Params)
+
+	assert.ErrorIs(t, err, user_cryptography.ErrUnknownHashAlgorithm)
+}
+
+func TestPasswordHasher_Argon2idUpgradesBcrypt(t *testing.T) {
+	hasher, err := user_cryptography.NewPasswordHasher(user_cryptography.AlgorithmArgon2id, 4, testArgon2Params)
+	require.NoError(t, err)
+
+	legacy, err := user_cryptography.NewBcryptHasher(4).Hash("supersecret")
+	require.NoError(t, err)
+
+	ok, err := hasher.HashComparer("supersecret", legacy)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, hasher.NeedsRehash(legacy))
+
+	upgraded, err := hasher.Hash("supersecret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(upgraded, "$argon2id$"))
+	assert.False(t, hasher.NeedsRehash(upgraded))
+
+	ok, err = hasher.HashComparer("supersecret", upgraded)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestPasswordHasher_BcryptCost(t *testing.T) {
+	hasher, err := user_cryptography.NewPasswordHasher(user_cryptography.AlgorithmBcrypt, 5, testArgon2Params)
+	require.NoError(t, err)
+
+	weaker, _ := user_cryptography.NewBcryptHasher(4).Hash("supersecret")
+	current, _ := hasher.Hash("supersecret")
+	argon, _ := user_cryptography.NewArgon2idHasher(testArgon2Params).Hash("supersecret")
+
+	assert.True(t, hasher.NeedsRehash(weaker))
+	assert.False(t, hasher.NeedsRehash(current))
+	assert.True(t, hasher.NeedsRehash(argon))
+
+	ok, err := hasher.HashComparer("supersecret", argon)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestPasswordHasher_UnknownHash(t *testing.T) {
+	hasher, err := user_cryptography.NewPasswordHasher(user_cryptography.AlgorithmArgon2id, 4, testArgon2Params)
+	require.NoError(t, err)
+
+	ok, err := hasher.HashComparer("supersecret", "plaintext")
+
+	assert.ErrorIs(t, err, user_cryptography.ErrUnknownHashAlgorithm)
+	assert.False(t, ok)
+}
@@ -0,0 +1,14 @@
+package user_model
+
+import "time"
+
+type PasswordHistory struct {
+	ID           uint   `gorm:"primaryKey"`
+	UserID       string `gorm:"index"`
+	PasswordHash string
+	CreatedAt    time.Time
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
@@ -0,0 +1,14 @@
+package user_model
+
+import "time"
+
+// SetupClaim is the marker row of the first-administrator setup. Its primary
+// key only ever holds one value.
+type SetupClaim struct {
+	ID        string `gorm:"primaryKey"`
+	CreatedAt time.Time
+}
+
+func (SetupClaim) TableName() string {
+	return "setup_claims"
+}
@@ -1,19 +1,22 @@
 package user_model
 
 import (
+	"time"
+
 	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
 	"gorm.io/gorm"
 )
 
 type User struct {
 	gorm.Model
-	ID       string
-	Name     string
-	Surname  string
-	Nickname string
-	Age      int32
-	Email    string `gorm:"uniqueIndex"`
-	Password string
+	ID              string
+	Name            string
+	Surname         string
+	Nickname        string
+	Age             int32
+	Email           string `gorm:"uniqueIndex"`
+	Password        string
+	EmailVerifiedAt *time.Time
 }
 
 func (User) TableName() string {
@@ -25,13 +28,14 @@ func FromEntity(u *userEntity.User) *User {
 		return nil
 	}
 	return &User{
-		ID:       u.ID,
-		Name:     u.Name,
-		Surname:  u.Surname,
-		Nickname: u.Nickname,
-		Age:      u.Age,
-		Email:    u.Email,
-		Password: u.Password,
+		ID:              u.ID,
+		Name:            u.Name,
+		Surname:         u.Surname,
+		Nickname:        u.Nickname,
+		Age:             u.Age,
+		Email:           u.Email,
+		Password:        u.Password,
+		EmailVerifiedAt: u.EmailVerifiedAt,
 	}
 }
 
@@ -48,13 +52,14 @@ func ToEntity(u *User) *userEntity.User {
 		return nil
 	}
 	return &userEntity.User{
-		ID:       u.ID,
-		Name:     u.Name,
-		Surname:  u.Surname,
-		Nickname: u.Nickname,
-		Age:      u.Age,
-		Email:    u.Email,
-		Password: u.Password,
+		ID:              u.ID,
+		Name:            u.Name,
+		Surname:         u.Surname,
+		Nickname:        u.Nickname,
+		Age:             u.Age,
+		Email:           u.Email,
+		Password:        u.Password,
+		EmailVerifiedAt: u.EmailVerifiedAt,
 	}
 }
 
@@ -0,0 +1,52 @@
+package user_repository
+
+import (
+	user_model "github.com/williamkoller/system-education/internal/user/infra/db/model"
+	portUserRepository "github.com/williamkoller/system-education/internal/user/port/repository"
+	"gorm.io/gorm"
+)
+
+type PasswordHistoryGormRepository struct {
+	db *gorm.DB
+}
+
+var _ portUserRepository.PasswordHistoryRepository = &PasswordHistoryGormRepository{}
+
+func NewPasswordHistoryGormRepository(db *gorm.DB) *PasswordHistoryGormRepository {
+	return &PasswordHistoryGormRepository{db: db}
+}
+
+func (r *PasswordHistoryGormRepository) Add(userID, hash string, keep int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user_model.PasswordHistory{UserID: userID, PasswordHash: hash}).Error; err != nil {
+			return err
+		}
+
+		kept := tx.Model(&user_model.PasswordHistory{}).
+			Select("id").
+			Where("user_id = ?", userID).
+			Order("id DESC").
+			Limit(keep)
+
+		return tx.Where("user_id = ? AND id NOT IN (?)", userID, kept).
+			Delete(&user_model.PasswordHistory{}).Error
+	})
+}
+
+func (r *PasswordHistoryGormRepository) Recent(userID string, limit int) ([]string, error) {
+	var hashes []string
+	if limit <= 0 {
+		return hashes, nil
+	}
+
+	err := r.db.Model(&user_model.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
@@ -0,0 +1,59 @@
+package user_repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	user_model "github.com/williamkoller/system-education/internal/user/infra/db/model"
+	user_repository "github.com/williamkoller/system-education/internal/user/infra/db/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupPasswordHistoryDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&user_model.PasswordHistory{}))
+	return db
+}
+
+func TestPasswordHistoryGormRepository_RecentNewestFirst(t *testing.T) {
+	repo := user_repository.NewPasswordHistoryGormRepository(setupPasswordHistoryDB(t))
+
+	assert.NoError(t, repo.Add("user-1", "hash-1", 5))
+	assert.NoError(t, repo.Add("user-1", "hash-2", 5))
+	assert.NoError(t, repo.Add("user-2", "other", 5))
+
+	hashes, err := repo.Recent("user-1", 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hash-2", "hash-1"}, hashes)
+}
+
+func TestPasswordHistoryGormRepository_AddTrimsToKeep(t *testing.T) {
+	repo := user_repository.NewPasswordHistoryGormRepository(setupPasswordHistoryDB(t))
+
+	for _, hash := range []string{"hash-1", "hash-2", "hash-3", "hash-4"} {
+		assert.NoError(t, repo.Add("user-1", hash, 2))
+	}
+	assert.NoError(t, repo.Add("user-2", "other", 2))
+
+	hashes, err := repo.Recent("user-1", 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hash-4", "hash-3"}, hashes)
+
+	others, err := repo.Recent("user-2", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"other"}, others)
+}
+
+func TestPasswordHistoryGormRepository_RecentWithoutLimit(t *testing.T) {
+	repo := user_repository.NewPasswordHistoryGormRepository(setupPasswordHistoryDB(t))
+	assert.NoError(t, repo.Add("user-1", "hash-1", 5))
+
+	hashes, err := repo.Recent("user-1", 0)
+
+	assert.NoError(t, err)
+	assert.Empty(t, hashes)
+}
@@ -0,0 +1,38 @@
+package user_repository
+
+import (
+	user_model "github.com/williamkoller/system-education/internal/user/infra/db/model"
+	portUserRepository "github.com/williamkoller/system-education/internal/user/port/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const administratorSetup = "administrator"
+
+type SetupGormRepository struct {
+	db *gorm.DB
+}
+
+var _ portUserRepository.SetupRepository = &SetupGormRepository{}
+
+func NewSetupGormRepository(db *gorm.DB) *SetupGormRepository {
+	return &SetupGormRepository{db: db}
+}
+
+// Claim relies on the primary key: of concurrent inserts only one adds the
+// row, and the others find nothing to do.
+func (r *SetupGormRepository) Claim() error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&user_model.SetupClaim{ID: administratorSetup})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return portUserRepository.ErrSetupClaimed
+	}
+	return nil
+}
+
+func (r *SetupGormRepository) Release() error {
+	return r.db.Delete(&user_model.SetupClaim{}, "id = ?", administratorSetup).Error
+}
@@ -0,0 +1,25 @@
+package user_repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	user_model "github.com/williamkoller/system-education/internal/user/infra/db/model"
+	user_repository "github.com/williamkoller/system-education/internal/user/infra/db/repository"
+	portUserRepository "github.com/williamkoller/system-education/internal/user/port/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSetupGormRepository_ClaimOnce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.AutoMigrate(&user_model.SetupClaim{}))
+	repo := user_repository.NewSetupGormRepository(db)
+
+	assert.NoError(t, repo.Claim())
+	assert.ErrorIs(t, repo.Claim(), portUserRepository.ErrSetupClaimed)
+
+	assert.NoError(t, repo.Release())
+	assert.NoError(t, repo.Claim())
+}
@@ -1,9 +1,12 @@
 package user_repository
 
 import (
+	"errors"
+
 	userEntity "github.com/williamkoller/system-education/internal/user/domain/entity"
 	user_model "github.com/williamkoller/system-education/internal/user/infra/db/model"
 	portUserRepository "github.com/williamkoller/system-education/internal/user/port/repository"
+	"github.com/williamkoller/system-education/shared/infra/outbox"
 	"gorm.io/gorm"
 )
 
@@ -17,9 +20,17 @@ func NewUserGormRepository(db *gorm.DB) *UserGormRepository {
 	return &UserGormRepository{db: db}
 }
 
+// Save also moves the user's pending domain events to the outbox in the same
+// transaction, so they are delivered if and only if the user is stored.
 func (r *UserGormRepository) Save(u *userEntity.User) (*userEntity.User, error) {
 	model := user_model.FromEntity(u)
-	if err := r.db.Create(&model).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model).Error; err != nil {
+			return err
+		}
+		return outbox.Append(tx, u.PullDomainEvents())
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -30,6 +41,9 @@ func (r *UserGormRepository) FindByID(id string) (*userEntity.User, error) {
 	var user *userEntity.User
 
 	if err := r.db.First(&user, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, portUserRepository.ErrUserNotFound
+		}
 		return nil, err
 	}
 
@@ -46,8 +60,15 @@ func (r *UserGormRepository) FindAll() ([]*userEntity.User, error) {
 	return users, nil
 }
 
-func (r *UserGormRepository) Delete(id string) error {
-	return r.db.Unscoped().Delete(&user_model.User{}, "id = ?", id).Error
+// Delete moves the user's pending events to the outbox in the same
+// transaction as the removal, like Save and Update.
+func (r *UserGormRepository) Delete(u *userEntity.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Delete(&user_model.User{}, "id = ?", u.ID).Error; err != nil {
+			return err
+		}
+		return outbox.Append(tx, u.PullDomainEvents())
+	})
 }
 
 func (r *UserGormRepository) FindByEmail(email string) (*userEntity.User, error) {
@@ -55,6 +76,9 @@ func (r *UserGormRepository) FindByEmail(email string) (*userEntity.User, error)
 	model := user_model.FromEntity(user)
 
 	if err := r.db.First(&model, "email = ?", email).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, portUserRepository.ErrUserNotFound
+		}
 		return nil, err
 	}
 
@@ -63,10 +87,18 @@ func (r *UserGormRepository) FindByEmail(email string) (*userEntity.User, error)
 
 func (r *UserGormRepository) Update(id string, u *userEntity.User) (*userEntity.User, error) {
 	model := user_model.FromEntity(u)
-
-	if err := r.db.Model(&user_model.User{}).
-		Where("id = ?", id).
-		Updates(&model).Error; err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Listing the columns makes Updates write zero values too, such as
+		// the email_verified_at a changed address clears.
+		if err := tx.Model(&user_model.User{}).
+			Where("id = ?", id).
+			Select("name", "surname", "nickname", "age", "email", "password", "email_verified_at", "updated_at").
+			Updates(&model).Error; err != nil {
+			return err
+		}
+		return outbox.Append(tx, u.PullDomainEvents())
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -2,6 +2,7 @@ package user_repository_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	user_model "github.com/williamkoller/system-education/internal/user/infra/db/model"
@@ -10,13 +11,15 @@ import (
 	"gorm.io/gorm"
 
 	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	portUserRepository "github.com/williamkoller/system-education/internal/user/port/repository"
+	"github.com/williamkoller/system-education/shared/infra/outbox"
 )
 
 func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&user_model.User{})
+	err = db.AutoMigrate(&user_model.User{}, &outbox.OutboxMessage{})
 	assert.NoError(t, err)
 
 	return db
@@ -51,15 +54,96 @@ func TestUserGormRepository_SaveAndFindByEmail_Success(t *testing.T) {
 	// você pode também validar Name, Nickname etc se quiser
 }
 
+func TestUserGormRepository_Save_WritesDomainEventsToOutbox(t *testing.T) {
+	db := setupTestDB(t)
+	repo := user_repository.NewUserGormRepository(db)
+
+	u := user_entity.NewUser(&user_entity.User{
+		ID:       "id-456",
+		Name:     "Test",
+		Surname:  "User",
+		Nickname: "testuser",
+		Age:      25,
+		Email:    "outbox@example.com",
+		Password: "pass123",
+	})
+
+	_, err := repo.Save(u)
+	assert.NoError(t, err)
+	assert.Empty(t, u.PullDomainEvents())
+
+	var messages []outbox.OutboxMessage
+	assert.NoError(t, db.Find(&messages).Error)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "user.created", messages[0].EventName)
+	assert.Contains(t, messages[0].Payload, "outbox@example.com")
+}
+
+func TestUserGormRepository_Update_WritesDomainEventsToOutbox(t *testing.T) {
+	db := setupTestDB(t)
+	repo := user_repository.NewUserGormRepository(db)
+
+	u := &user_entity.User{
+		ID:       "id-789",
+		Name:     "Test",
+		Surname:  "User",
+		Nickname: "testuser",
+		Age:      25,
+		Email:    "before@example.com",
+		Password: "pass123",
+	}
+	_, err := repo.Save(u)
+	assert.NoError(t, err)
+
+	email := "after@example.com"
+	_, err = u.UpdateUser(nil, nil, &email, nil, nil)
+	assert.NoError(t, err)
+
+	_, err = repo.Update(u.ID, u)
+	assert.NoError(t, err)
+	assert.Empty(t, u.PullDomainEvents())
+
+	var messages []outbox.OutboxMessage
+	assert.NoError(t, db.Find(&messages).Error)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "user.updated", messages[0].EventName)
+	assert.Contains(t, messages[0].Payload, "after@example.com")
+	assert.Equal(t, "user.email_changed", messages[1].EventName)
+}
+
+func TestUserGormRepository_Save_DuplicateKeepsOutboxEmpty(t *testing.T) {
+	db := setupTestDB(t)
+	repo := user_repository.NewUserGormRepository(db)
+
+	newUser := func() *user_entity.User {
+		return user_entity.NewUser(&user_entity.User{
+			ID:       "id-789",
+			Name:     "Test",
+			Surname:  "User",
+			Nickname: "testuser",
+			Age:      25,
+			Email:    "dup@example.com",
+			Password: "pass123",
+		})
+	}
+
+	_, err := repo.Save(newUser())
+	assert.NoError(t, err)
+	_, err = repo.Save(newUser())
+	assert.Error(t, err)
+
+	var count int64
+	db.Model(&outbox.OutboxMessage{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
 func TestUserGormRepository_FindByEmail_NotFound(t *testing.T) {
 	db := setupTestDB(t)
 	repo := user_repository.NewUserGormRepository(db)
 
 	// buscar email que não existe
 	found, err := repo.FindByEmail("unknown@example.com")
-	// dependendo de como você trata “não encontrado”, GORM retorna gorm.ErrRecordNotFound
-	// você pode verificar com errors.Is(err, gorm.ErrRecordNotFound)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, portUserRepository.ErrUserNotFound)
 	assert.Nil(t, found)
 }
 
@@ -69,9 +153,7 @@ func TestUserGormRepository_FindByID_NotFound(t *testing.T) {
 
 	// buscar email que não existe
 	found, err := repo.FindByID("id‑123")
-	// dependendo de como você trata “não encontrado”, GORM retorna gorm.ErrRecordNotFound
-	// você pode verificar com errors.Is(err, gorm.ErrRecordNotFound)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, portUserRepository.ErrUserNotFound)
 	assert.Nil(t, found)
 }
 
@@ -99,9 +181,30 @@ func TestUserGormRepository_Delete(t *testing.T) {
 	u1 := &user_entity.User{ID: "id1", Name: "A", Surname: "B", Nickname: "a", Age: 20, Email: "a@example.com", Password: "p1"}
 
 	_, _ = repo.Save(u1)
-	err := repo.Delete("id1")
+	err := repo.Delete(u1)
+	assert.NoError(t, err)
+
+}
+
+func TestUserGormRepository_Delete_WritesDomainEventsToOutbox(t *testing.T) {
+	db := setupTestDB(t)
+	repo := user_repository.NewUserGormRepository(db)
+
+	u := &user_entity.User{ID: "id1", Name: "A", Surname: "B", Nickname: "a", Age: 20, Email: "a@example.com", Password: "p1"}
+	_, err := repo.Save(u)
 	assert.NoError(t, err)
 
+	u.MarkDeleted()
+	assert.NoError(t, repo.Delete(u))
+	assert.Empty(t, u.PullDomainEvents())
+
+	_, err = repo.FindByID("id1")
+	assert.Error(t, err)
+
+	var messages []outbox.OutboxMessage
+	assert.NoError(t, db.Find(&messages).Error)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "user.deleted", messages[0].EventName)
 }
 
 func TestUserGormRepository_Update(t *testing.T) {
@@ -387,3 +490,28 @@ func TestUserGormRepository_Update_NonExistentUser(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 }
+
+func TestUserGormRepository_Update_ClearsEmailVerification(t *testing.T) {
+	db := setupTestDB(t)
+	repo := user_repository.NewUserGormRepository(db)
+
+	verifiedAt := time.Now()
+	user := &user_entity.User{ID: "id1", Name: "A", Surname: "B", Nickname: "a", Age: 20, Email: "a@example.com", Password: "p1", EmailVerifiedAt: &verifiedAt}
+	_, err := repo.Save(user)
+	assert.NoError(t, err)
+
+	stored, err := repo.FindByID("id1")
+	assert.NoError(t, err)
+	assert.True(t, stored.IsEmailVerified())
+
+	email := "other@example.com"
+	_, err = stored.UpdateUser(nil, nil, &email, nil, nil)
+	assert.NoError(t, err)
+	_, err = repo.Update(stored.ID, stored)
+	assert.NoError(t, err)
+
+	reloaded, err := repo.FindByID("id1")
+	assert.NoError(t, err)
+	assert.Equal(t, "other@example.com", reloaded.Email)
+	assert.False(t, reloaded.IsEmailVerified())
+}
@@ -2,6 +2,7 @@ package infra_email
 
 import (
 	"fmt"
+	"html"
 	"time"
 
 	port_email_notifier "github.com/williamkoller/system-education/internal/user/port/email"
@@ -106,3 +107,105 @@ func (n *ResendEmailNotifier) SendWelcomeEmail(name, emailAddr string) error {
 
 	return nil
 }
+
+func (n *ResendEmailNotifier) SendPasswordResetEmail(name, emailAddr, link string) error {
+	subject := "Redefinição de senha"
+
+	html := actionEmailHTML(name, link, "Redefinir senha",
+		"Recebemos uma solicitação para redefinir a senha da sua conta na <strong>System Education</strong>.",
+		"Se você não fez essa solicitação, ignore este e-mail. Sua senha continuará a mesma.",
+	)
+
+	if err := n.client.SendEmail(emailAddr, subject, html); err != nil {
+		return fmt.Errorf("failed to send password reset email to %s: %w", emailAddr, err)
+	}
+
+	return nil
+}
+
+func (n *ResendEmailNotifier) SendEmailVerification(name, emailAddr, link string) error {
+	subject := "Confirme seu e-mail"
+
+	html := actionEmailHTML(name, link, "Confirmar e-mail",
+		"Para concluir seu cadastro na <strong>System Education</strong>, confirme que este endereço de e-mail é seu.",
+		"Se você não criou uma conta, ignore este e-mail.",
+	)
+
+	if err := n.client.SendEmail(emailAddr, subject, html); err != nil {
+		return fmt.Errorf("failed to send email verification to %s: %w", emailAddr, err)
+	}
+
+	return nil
+}
+
+func (n *ResendEmailNotifier) SendEmailChangeConfirmation(name, emailAddr, link string) error {
+	subject := "Confirme seu novo e-mail"
+
+	html := actionEmailHTML(name, link, "Confirmar novo e-mail",
+		"Recebemos uma solicitação para usar este endereço na sua conta da <strong>System Education</strong>.",
+		"Se você não fez essa solicitação, ignore este e-mail. Seu endereço atual continuará o mesmo.",
+	)
+
+	if err := n.client.SendEmail(emailAddr, subject, html); err != nil {
+		return fmt.Errorf("failed to send email change confirmation to %s: %w", emailAddr, err)
+	}
+
+	return nil
+}
+
+// actionEmailHTML renders the transactional layout shared by the emails that
+// carry a single call-to-action link.
+func actionEmailHTML(name, link, action, intro, footnote string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="pt-BR">
+  <head>
+    <meta http-equiv="Content-Type" content="text/html; charset=UTF-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+    <style>
+      * {
+        font-family: 'Inter', Helvetica, Arial, sans-serif;
+      }
+    </style>
+  </head>
+
+  <body style="margin:0; padding:0; background-color:#f5f5f7;">
+    <table width="100%%" cellpadding="0" cellspacing="0" border="0" align="center">
+      <tr>
+        <td style="padding:24px;">
+          <table width="100%%" cellpadding="0" cellspacing="0" border="0" align="center" style="max-width:600px; background:#ffffff; border-radius:8px; padding:32px;">
+            <tr>
+              <td style="text-align:left;">
+
+                <h1 style="font-size:24px; font-weight:700; color:#111; margin:0 0 16px 0;">
+                  Olá, %s!
+                </h1>
+
+                <p style="font-size:16px; color:#444; margin:0 0 24px 0; line-height:1.5;">
+                  %s
+                </p>
+
+                <a href="%s"
+                  style="display:inline-block; padding:12px 20px; background:#7C3AED; color:#ffffff; text-decoration:none; font-size:16px; font-weight:600; border-radius:6px;">
+                  %s
+                </a>
+
+                <p style="font-size:14px; color:#888; margin:24px 0 0 0; line-height:1.5;">
+                  %s
+                </p>
+
+              </td>
+            </tr>
+
+            <tr>
+              <td style="padding-top:32px; text-align:center; color:#888; font-size:12px;">
+                © %d System Education. Todos os direitos reservados.
+              </td>
+            </tr>
+
+          </table>
+        </td>
+      </tr>
+    </table>
+  </body>
+</html>`, html.EscapeString(name), intro, html.EscapeString(link), action, footnote, time.Now().Year())
+}
@@ -138,3 +138,73 @@ func TestResendEmailNotifier_SendWelcomeEmail_CorrectSubject(t *testing.T) {
 	mockClient.AssertExpectations(t)
 	mockClient.AssertCalled(t, "SendEmail", email, expectedSubject, mock.Anything)
 }
+
+func TestResendEmailNotifier_SendPasswordResetEmail_ContainsLink(t *testing.T) {
+	mockClient := new(MockResendClient)
+	notifier := NewResendEmailNotifier(mockClient)
+
+	link := "https://app.example.com/reset-password?token=abc&x=1"
+
+	var capturedHTML string
+	mockClient.On("SendEmail",
+		"john.doe@example.com",
+		"Redefinição de senha",
+		mock.MatchedBy(func(html string) bool {
+			capturedHTML = html
+			return true
+		}),
+	).Return(nil)
+
+	err := notifier.SendPasswordResetEmail("John Doe", "john.doe@example.com", link)
+
+	assert.NoError(t, err)
+	assert.Contains(t, capturedHTML, "Olá, John Doe!")
+	assert.Contains(t, capturedHTML, `href="https://app.example.com/reset-password?token=abc&amp;x=1"`)
+	mockClient.AssertExpectations(t)
+}
+
+func TestResendEmailNotifier_SendPasswordResetEmail_ClientError(t *testing.T) {
+	mockClient := new(MockResendClient)
+	notifier := NewResendEmailNotifier(mockClient)
+
+	mockClient.On("SendEmail", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("resend API error"))
+
+	err := notifier.SendPasswordResetEmail("John Doe", "john.doe@example.com", "https://link")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to send password reset email")
+}
+
+func TestResendEmailNotifier_SendEmailVerification_ContainsLink(t *testing.T) {
+	mockClient := new(MockResendClient)
+	notifier := NewResendEmailNotifier(mockClient)
+
+	var capturedHTML string
+	mockClient.On("SendEmail",
+		"maria@example.com",
+		"Confirme seu e-mail",
+		mock.MatchedBy(func(html string) bool {
+			capturedHTML = html
+			return true
+		}),
+	).Return(nil)
+
+	err := notifier.SendEmailVerification("<Maria>", "maria@example.com", "https://app.example.com/verify-email?token=abc")
+
+	assert.NoError(t, err)
+	assert.Contains(t, capturedHTML, "Olá, &lt;Maria&gt;!")
+	assert.Contains(t, capturedHTML, "https://app.example.com/verify-email?token=abc")
+	mockClient.AssertExpectations(t)
+}
+
+func TestResendEmailNotifier_SendEmailVerification_ClientError(t *testing.T) {
+	mockClient := new(MockResendClient)
+	notifier := NewResendEmailNotifier(mockClient)
+
+	mockClient.On("SendEmail", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("resend API error"))
+
+	err := notifier.SendEmailVerification("Maria", "maria@example.com", "https://link")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to send email verification")
+}
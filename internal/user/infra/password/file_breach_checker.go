@@ -0,0 +1,184 @@
+package infra_password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	port_user_password "github.com/williamkoller/system-education/internal/user/port/password"
+)
+
+const prefixLength = 5
+
+// FileBreachChecker looks passwords up in a local copy of a breached password
+// list such as the Have I Been Pwned "ordered by hash" download: one upper
+// or lower case SHA-1 hex digest per line, optionally followed by ":count",
+// sorted by hash. The list runs to tens of gigabytes, so it is never loaded:
+// each lookup binary-searches the file for the hash's first five characters,
+// the same k-anonymity ranges the online API serves, and reads only that
+// range. A remote range source can therefore replace the file without
+// changing callers. Blank lines, "#" comments and malformed lines are skipped.
+type FileBreachChecker struct {
+	list   io.ReaderAt
+	size   int64
+	closer io.Closer
+}
+
+var _ port_user_password.BreachChecker = &FileBreachChecker{}
+
+func NewFileBreachChecker(path string) (*FileBreachChecker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	checker, err := NewBreachChecker(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	checker.closer = file
+	return checker, nil
+}
+
+// NewBreachChecker searches a sorted list of size bytes read from list.
+func NewBreachChecker(list io.ReaderAt, size int64) (*FileBreachChecker, error) {
+	checker := &FileBreachChecker{list: list, size: size}
+
+	if _, err := checker.entryAt(0); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("breached password list has no SHA-1 hashes")
+		}
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	return checker, nil
+}
+
+// Close releases the file opened by NewFileBreachChecker.
+func (c *FileBreachChecker) Close() error {
+	if c.closer == nil {
+		return nil
+	}
+	return c.closer.Close()
+}
+
+// Range returns the hash suffixes known for a five character prefix.
+func (c *FileBreachChecker) Range(prefix string) ([]string, error) {
+	prefix = strings.ToUpper(prefix)
+
+	start, err := c.search(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := c.readerFrom(start)
+	var suffixes []string
+	for {
+		hash, err := nextEntry(reader)
+		if errors.Is(err, io.EOF) {
+			return suffixes, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read breached password list: %w", err)
+		}
+		if !strings.HasPrefix(hash, prefix) {
+			return suffixes, nil
+		}
+		suffixes = append(suffixes, hash[prefixLength:])
+	}
+}
+
+func (c *FileBreachChecker) IsBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := c.Range(hash[:prefixLength])
+	if err != nil {
+		return false, err
+	}
+	for _, suffix := range suffixes {
+		if suffix == hash[prefixLength:] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// search returns the offset of the first line at or after which every hash
+// sorts at or above prefix.
+func (c *FileBreachChecker) search(prefix string) (int64, error) {
+	low, high := int64(0), c.size
+	for low < high {
+		mid := low + (high-low)/2
+		hash, err := c.entryAt(mid)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, fmt.Errorf("failed to read breached password list: %w", err)
+		}
+		if err == nil && hash < prefix {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return c.lineStart(low)
+}
+
+// entryAt returns the first hash on a line that starts at or after offset.
+func (c *FileBreachChecker) entryAt(offset int64) (string, error) {
+	start, err := c.lineStart(offset)
+	if err != nil {
+		return "", err
+	}
+	return nextEntry(c.readerFrom(start))
+}
+
+// lineStart moves offset forward to the start of a line.
+func (c *FileBreachChecker) lineStart(offset int64) (int64, error) {
+	if offset == 0 {
+		return 0, nil
+	}
+	reader := c.readerFrom(offset - 1)
+	skipped, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+	return offset - 1 + int64(len(skipped)), nil
+}
+
+func (c *FileBreachChecker) readerFrom(offset int64) *bufio.Reader {
+	return bufio.NewReaderSize(io.NewSectionReader(c.list, offset, c.size-offset), 256)
+}
+
+// nextEntry reads lines until one holds a SHA-1 hash and returns it upper
+// cased.
+func nextEntry(reader *bufio.Reader) (string, error) {
+	for {
+		line, err := reader.ReadString('\n')
+		if line == "" && err != nil {
+			return "", err
+		}
+
+		hash, _, _ := strings.Cut(strings.TrimSpace(line), ":")
+		if len(hash) == sha1.Size*2 {
+			if _, decodeErr := hex.DecodeString(hash); decodeErr == nil {
+				return strings.ToUpper(hash), nil
+			}
+		}
+
+		if err != nil {
+			return "", err
+		}
+	}
+}
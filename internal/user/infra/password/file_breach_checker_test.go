@@ -0,0 +1,110 @@
+package infra_password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SHA-1 of "password" and "123456".
+const breachedList = `# sample
+5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824
+7c4a8d09ca3762af61e59520943dc26494f8941b
+
+`
+
+func newTestBreachChecker(t *testing.T, list string) *FileBreachChecker {
+	t.Helper()
+	checker, err := NewBreachChecker(strings.NewReader(list), int64(len(list)))
+	require.NoError(t, err)
+	return checker
+}
+
+func TestBreachChecker_IsBreached(t *testing.T) {
+	checker := newTestBreachChecker(t, breachedList)
+
+	for _, password := range []string{"password", "123456"} {
+		breached, err := checker.IsBreached(password)
+		assert.NoError(t, err)
+		assert.True(t, breached, password)
+	}
+
+	breached, err := checker.IsBreached("correct horse battery staple")
+	assert.NoError(t, err)
+	assert.False(t, breached)
+}
+
+func TestBreachChecker_Range(t *testing.T) {
+	checker := newTestBreachChecker(t, breachedList)
+
+	suffixes, err := checker.Range("5baa6")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1E4C9B93F3F0682250B6CF8331B7EE68FD8"}, suffixes)
+
+	for _, prefix := range []string{"00000", "6AAAA", "FFFFF"} {
+		suffixes, err = checker.Range(prefix)
+		assert.NoError(t, err)
+		assert.Empty(t, suffixes, prefix)
+	}
+}
+
+// A list large enough that lookups land mid-line, with counts, CRLF endings
+// and hashes sharing a prefix.
+func TestBreachChecker_SearchesLargeList(t *testing.T) {
+	var hashes []string
+	for i := 0; i < 2000; i++ {
+		sum := sha1.Sum([]byte(fmt.Sprintf("password-%d", i)))
+		hashes = append(hashes, strings.ToUpper(hex.EncodeToString(sum[:])))
+	}
+	hashes = append(hashes, hashes[100][:prefixLength]+strings.Repeat("0", 35), hashes[100][:prefixLength]+strings.Repeat("F", 35))
+	sort.Strings(hashes)
+
+	var list strings.Builder
+	for i, hash := range hashes {
+		fmt.Fprintf(&list, "%s:%d\r\n", hash, i)
+	}
+	checker := newTestBreachChecker(t, list.String())
+
+	for i := 0; i < 2000; i++ {
+		breached, err := checker.IsBreached(fmt.Sprintf("password-%d", i))
+		require.NoError(t, err)
+		require.True(t, breached, i)
+	}
+
+	breached, err := checker.IsBreached("password-2000")
+	assert.NoError(t, err)
+	assert.False(t, breached)
+
+	suffixes, err := checker.Range(hashes[0][:prefixLength])
+	assert.NoError(t, err)
+	assert.Contains(t, suffixes, hashes[0][prefixLength:])
+}
+
+func TestBreachChecker_EmptyList(t *testing.T) {
+	_, err := NewBreachChecker(strings.NewReader("# no hashes\n\n"), 13)
+
+	assert.EqualError(t, err, "breached password list has no SHA-1 hashes")
+}
+
+func TestNewFileBreachChecker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte(breachedList), 0o600))
+
+	checker, err := NewFileBreachChecker(path)
+	require.NoError(t, err)
+	defer checker.Close()
+
+	breached, _ := checker.IsBreached("password")
+	assert.True(t, breached)
+
+	_, err = NewFileBreachChecker(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
@@ -4,3 +4,10 @@ type Bcrypt interface {
 	Hash(plaintext string) (string, error)
 	HashComparer(plaintext string, hashed string) (bool, error)
 }
+
+// Rehasher is implemented by hashers that can tell when a stored hash was
+// made with an older algorithm or weaker parameters than they now use.
+// Callers that hold the plaintext, such as login, rehash it when it is.
+type Rehasher interface {
+	NeedsRehash(hashed string) bool
+}
@@ -2,4 +2,7 @@ package port_email_notifier
 
 type EmailNotifier interface {
 	SendWelcomeEmail(name, email string) error
-}
\ No newline at end of file
+	SendPasswordResetEmail(name, email, link string) error
+	SendEmailVerification(name, email, link string) error
+	SendEmailChangeConfirmation(name, email, link string) error
+}
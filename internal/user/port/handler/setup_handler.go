@@ -0,0 +1,7 @@
+package port_user_handler
+
+import "github.com/gin-gonic/gin"
+
+type SetupHandler interface {
+	Bootstrap(c *gin.Context)
+}
@@ -0,0 +1,7 @@
+package port_user_password
+
+// BreachChecker reports whether a password appears in a list of passwords
+// exposed in data breaches.
+type BreachChecker interface {
+	IsBreached(password string) (bool, error)
+}
@@ -0,0 +1,7 @@
+package port_user_permission
+
+type PermissionGranter interface {
+	// GrantAdministrator gives userID every permission needed to manage
+	// users, permissions and roles.
+	GrantAdministrator(userID string) error
+}
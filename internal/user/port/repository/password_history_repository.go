@@ -0,0 +1,9 @@
+package port_user_repository
+
+// PasswordHistoryRepository keeps the hashes of the passwords a user has set,
+// newest first, so old passwords can be refused.
+type PasswordHistoryRepository interface {
+	// Add records hash and drops all but the newest keep entries.
+	Add(userID, hash string, keep int) error
+	Recent(userID string, limit int) ([]string, error)
+}
@@ -0,0 +1,16 @@
+package port_user_repository
+
+import "errors"
+
+var ErrSetupClaimed = errors.New("setup already claimed")
+
+// SetupRepository keeps the single marker that makes the first-administrator
+// setup run at most once, even when several requests race for it.
+type SetupRepository interface {
+	// Claim records that setup has started, or fails with ErrSetupClaimed
+	// when another setup already did.
+	Claim() error
+	// Release removes the marker after a setup that failed, so it can be
+	// tried again.
+	Release() error
+}
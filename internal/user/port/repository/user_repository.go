@@ -10,7 +10,7 @@ type UserRepository interface {
 	Save(u *userEntity.User) (*userEntity.User, error)
 	FindByID(id string) (*userEntity.User, error)
 	FindAll() ([]*userEntity.User, error)
-	Delete(id string) error
+	Delete(u *userEntity.User) error
 	FindByEmail(email string) (*userEntity.User, error)
 	Update(id string, u *userEntity.User) (*userEntity.User, error)
 }
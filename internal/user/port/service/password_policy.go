@@ -0,0 +1,11 @@
+package port_user_service
+
+import user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+
+type PasswordPolicy interface {
+	// Validate returns a *user_entity.ValidationError when password breaks
+	// the policy for user.
+	Validate(user *user_entity.User, password string) error
+	// Remember records a newly set password hash so it cannot be reused.
+	Remember(userID, hash string) error
+}
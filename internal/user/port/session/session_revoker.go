@@ -0,0 +1,5 @@
+package port_user_session
+
+type SessionRevoker interface {
+	RevokeAllForUser(userID string) error
+}
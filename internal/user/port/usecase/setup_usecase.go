@@ -0,0 +1,17 @@
+package port_user_usecase
+
+import (
+	"errors"
+
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
+	"github.com/williamkoller/system-education/internal/user/presentation/dtos"
+)
+
+var (
+	ErrInvalidSetupToken = errors.New("invalid setup token")
+	ErrSetupCompleted    = errors.New("setup already completed")
+)
+
+type SetupUsecase interface {
+	Bootstrap(token string, input dtos.AddUserDto) (*user_entity.User, error)
+}
@@ -11,4 +11,5 @@ type UserUsecase interface {
 	FindByID(id string) (*user_entity.User, error)
 	Update(id string, input dtos.UpdateUserDto) (*user_entity.User, error)
 	Delete(id string) error
+	MarkEmailVerified(id string) error
 }
@@ -6,5 +6,6 @@ type AddUserDto struct {
 	Nickname string `json:"nickname" validate:"required,min=2,max=50" example:"johnd"`
 	Age      int32  `json:"age" validate:"required,gte=1,lte=130" example:"30"`
 	Email    string `json:"email" validate:"required,email" example:"john.doe@example.com"`
-	Password string `json:"password" validate:"required,min=8" example:"strongPassword123"`
+	// Password strength is checked by the password policy, not by tags.
+	Password string `json:"password" validate:"required" example:"strongPassword123"`
 }
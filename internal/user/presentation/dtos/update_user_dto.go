@@ -7,5 +7,5 @@ type UpdateUserDto struct {
 	Nickname *string `json:"nickname" validate:"min=2,max=50" example:"johnd"`
 	Age      *int32  `json:"age" validate:"gte=1,lte=130" example:"30"`
 	Email    *string `json:"email" validate:"email" example:"john.doe@example.com"`
-	Password *string `json:"password" validate:"min=8" example:"strongPassword123"`
+	Password *string `json:"password" example:"strongPassword123"`
 }
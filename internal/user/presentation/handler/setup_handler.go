@@ -0,0 +1,51 @@
+package user_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	user_mapper "github.com/williamkoller/system-education/internal/user/application/mapper"
+	portUserHandler "github.com/williamkoller/system-education/internal/user/port/handler"
+	portUserRepository "github.com/williamkoller/system-education/internal/user/port/repository"
+	portUserUsecase "github.com/williamkoller/system-education/internal/user/port/usecase"
+	"github.com/williamkoller/system-education/internal/user/presentation/dtos"
+)
+
+const SetupTokenHeader = "X-Setup-Token"
+
+type SetupHandler struct {
+	usecase portUserUsecase.SetupUsecase
+}
+
+func NewSetupHandler(usecase portUserUsecase.SetupUsecase) *SetupHandler {
+	return &SetupHandler{usecase: usecase}
+}
+
+var _ portUserHandler.SetupHandler = &SetupHandler{}
+
+func (h *SetupHandler) Bootstrap(c *gin.Context) {
+	var input dtos.AddUserDto
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Status(http.StatusBadRequest)
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	user, err := h.usecase.Bootstrap(c.GetHeader(SetupTokenHeader), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, portUserUsecase.ErrInvalidSetupToken):
+			c.Status(http.StatusUnauthorized)
+		case errors.Is(err, portUserUsecase.ErrSetupCompleted), errors.Is(err, portUserRepository.ErrUserAlreadyExists):
+			c.Status(http.StatusConflict)
+		default:
+			c.Status(http.StatusBadRequest)
+		}
+		c.Error(err).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user_mapper.ToUser(user))
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	user_mapper "github.com/williamkoller/system-education/internal/user/application/mapper"
+	user_entity "github.com/williamkoller/system-education/internal/user/domain/entity"
 	portUserHandler "github.com/williamkoller/system-education/internal/user/port/handler"
 	portUserRepository "github.com/williamkoller/system-education/internal/user/port/repository"
 	portUserUsecase "github.com/williamkoller/system-education/internal/user/port/usecase"
@@ -88,6 +89,19 @@ func (h *UserHandler) Update(c *gin.Context) {
 	user, err := h.usecase.Update(idParams, input)
 
 	if err != nil {
+		var validation *user_entity.ValidationError
+		if errors.As(err, &validation) {
+			c.Status(http.StatusBadRequest)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
+
+		if errors.Is(err, portUserRepository.ErrUserAlreadyExists) {
+			c.Status(http.StatusConflict)
+			c.Error(err).SetType(gin.ErrorTypePublic)
+			return
+		}
+
 		c.Status(http.StatusInternalServerError)
 		c.Error(err).SetType(gin.ErrorTypePublic)
 		return
@@ -1,68 +1,108 @@
 package user_router
 
 import (
+	"context"
+	"fmt"
 	"log"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	infra_cryptography "github.com/williamkoller/system-education/internal/auth/infra/cryptography"
+	port_auth_cryptography "github.com/williamkoller/system-education/internal/auth/port/cryptography"
+	port_auth_revocation "github.com/williamkoller/system-education/internal/auth/port/revocation"
+	port_auth_usecase "github.com/williamkoller/system-education/internal/auth/port/usecase"
 	auth_middleware "github.com/williamkoller/system-education/internal/auth/presentation/middleware"
-	permission_middleware "github.com/williamkoller/system-education/internal/permission/presentation/middleware"
+	permission_service "github.com/williamkoller/system-education/internal/permission/application/service"
+	permission_repository "github.com/williamkoller/system-education/internal/permission/infra/db/repository"
+	port_permission_middleware "github.com/williamkoller/system-education/internal/permission/port/middleware"
+	port_permission_service "github.com/williamkoller/system-education/internal/permission/port/service"
 	user_usecase "github.com/williamkoller/system-education/internal/user/application/usecase"
 	user_event "github.com/williamkoller/system-education/internal/user/domain/event"
-	user_cryptography "github.com/williamkoller/system-education/internal/user/infra/cryptography"
 	user_repository "github.com/williamkoller/system-education/internal/user/infra/db/repository"
 	infra_email "github.com/williamkoller/system-education/internal/user/infra/email"
+	port_cryptography "github.com/williamkoller/system-education/internal/user/port/cryptography"
+	port_user_service "github.com/williamkoller/system-education/internal/user/port/service"
 	user_handler "github.com/williamkoller/system-education/internal/user/presentation/handler"
 	shared_event "github.com/williamkoller/system-education/shared/domain/event"
 	"github.com/williamkoller/system-education/shared/infra/email"
+	"github.com/williamkoller/system-education/shared/infra/outbox"
 	"gorm.io/gorm"
 )
 
-func UserRouter(e *gin.Engine, db *gorm.DB, apiKey string, fromAddress string, secret string, expiresIn time.Duration) {
-	crypto := user_cryptography.NewBcryptHasher(12)
-	userRepo := user_repository.NewUserGormRepository(db)
-	event := shared_event.NewDispatcher()
-	jwt := infra_cryptography.NewJWTTokenManager(secret, expiresIn)
-	middleware := permission_middleware.NewPermissionMiddleware()
+// Dependencies wires the user routes.
+type Dependencies struct {
+	DB            *gorm.DB
+	TokenManager  port_auth_cryptography.TokenManager
+	Revocations   port_auth_revocation.RevocationStore
+	APIKeys       port_auth_usecase.APIKeyAuthenticator
+	Permissions   port_permission_service.PermissionService
+	AccessControl port_permission_middleware.PermissionMiddleware
+	Events        *shared_event.Dispatcher
 
-	client := email.NewResendClient(apiKey, fromAddress)
-	notifier := infra_email.NewResendEmailNotifier(client)
-	event.Register("user.created", func(e interface{}) {
-		evt, ok := e.(*user_event.UserCreatedEvent)
-		if !ok {
-			log.Printf("Evento inesperado: %+v", e)
-			return
-		}
+	PasswordHasher port_cryptography.Bcrypt
+	PasswordPolicy port_user_service.PasswordPolicy
+
+	ResendAPIKey string
+	FromAddress  string
+	// SetupToken enables POST /setup while it is set.
+	SetupToken string
+}
+
+func UserRouter(e *gin.Engine, deps Dependencies) {
+	userRepo := user_repository.NewUserGormRepository(deps.DB)
+	authenticate := auth_middleware.AuthMiddleware(deps.TokenManager, auth_middleware.WithRevocationStore(deps.Revocations), auth_middleware.WithAPIKeys(deps.APIKeys))
 
+	client := email.NewResendClient(deps.ResendAPIKey, deps.FromAddress)
+	notifier := infra_email.NewResendEmailNotifier(client)
+	err := shared_event.Subscribe(deps.Events, func(ctx context.Context, evt *user_event.UserCreatedEvent) error {
 		if err := notifier.SendWelcomeEmail(evt.Name, evt.Email); err != nil {
-			log.Printf("Falha ao enviar e‑mail de boas‑vindas: %v", err)
-		} else {
-			log.Printf("E‑mail de boas‑vindas enviado para: %s", evt.Email)
+			return fmt.Errorf("falha ao enviar e‑mail de boas‑vindas: %w", err)
 		}
-	})
+		log.Printf("E‑mail de boas‑vindas enviado para: %s", evt.Email)
+		return nil
+	}, shared_event.Deduplicate(outbox.NewProcessedGormStore(deps.DB), "welcome_email"))
+	if err != nil {
+		log.Fatalf("Falha ao registrar handler de user.created: %v", err)
+	}
 
-	userUsecase := user_usecase.NewUserUsecase(userRepo, crypto, event)
+	userUsecase := user_usecase.NewUserUsecase(userRepo, deps.PasswordHasher, deps.Events, deps.Revocations, user_usecase.WithPasswordPolicy(deps.PasswordPolicy))
 	userHandler := user_handler.NewUserHandler(userUsecase)
 
+	// Routes on a single user go through the named policies (see
+	// config/policies.json): the grant, plus either ownership of the record
+	// or the users:admin override.
 	users := e.Group("/users")
 	{
-		users.POST("", userHandler.CreateUser)
-		users.GET("", userHandler.FindAllUsers)
+		users.POST("",
+			authenticate,
+			deps.AccessControl.ModuleAccessMiddleware([]string{"users"}, []string{"create"}),
+			userHandler.CreateUser,
+		)
+		users.GET("",
+			authenticate,
+			deps.AccessControl.ModuleAccessMiddleware([]string{"users"}, []string{"read"}),
+			userHandler.FindAllUsers,
+		)
 		users.GET(":id",
-			auth_middleware.AuthMiddleware(jwt),
-			middleware.ModuleAccessMiddleware([]string{"users"}, []string{"read"}),
+			authenticate,
+			deps.AccessControl.PolicyMiddleware("users.read"),
 			userHandler.FindByID,
 		)
 		users.PUT(":id",
-			auth_middleware.AuthMiddleware(jwt),
-			middleware.ModuleAccessMiddleware([]string{"users"}, []string{"update"}),
+			authenticate,
+			deps.AccessControl.PolicyMiddleware("users.update"),
 			userHandler.Update,
 		)
 		users.DELETE(":id",
-			auth_middleware.AuthMiddleware(jwt),
-			middleware.ModuleAccessMiddleware([]string{"users"}, []string{"delete"}),
+			authenticate,
+			deps.AccessControl.PolicyMiddleware("users.delete"),
 			userHandler.Delete,
 		)
 	}
+
+	// The setup route only exists while a token is configured; operators
+	// should unset SETUP_TOKEN once the first administrator is created.
+	if deps.SetupToken != "" {
+		granter := permission_service.NewAdministratorGranter(permission_repository.NewPermissionGormRepository(deps.DB), deps.Permissions)
+		setupHandler := user_handler.NewSetupHandler(user_usecase.NewSetupUsecase(userUsecase, granter, user_repository.NewSetupGormRepository(deps.DB), deps.SetupToken))
+		e.POST("/setup", setupHandler.Bootstrap)
+	}
 }
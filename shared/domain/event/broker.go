@@ -0,0 +1,119 @@
+package shared_event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// Message is what travels over a broker. Events are published as an encoded
+// Envelope to a topic named after the event.
+type Message struct {
+	Topic   string
+	Key     string
+	Headers map[string]string
+	Body    []byte
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+}
+
+// Subscriber delivers messages whose topic matches pattern, in the syntax
+// of MatchTopic, until ctx is cancelled. Returning an error from the handler
+// asks for redelivery on brokers that support it.
+type Subscriber interface {
+	Subscribe(ctx context.Context, pattern string, handler func(ctx context.Context, message Message) error) error
+}
+
+type Broker interface {
+	Publisher
+	Subscriber
+	Close() error
+}
+
+type remoteSourceKey struct{}
+
+// RemoteSource returns the source of an event the Consumer received from a
+// broker, or "" when the event was raised in this process.
+func RemoteSource(ctx context.Context) string {
+	source, _ := ctx.Value(remoteSourceKey{}).(string)
+	return source
+}
+
+// Publish returns a handler that forwards events to the broker as
+// envelopes from source. Subscribe it to the events other services should
+// see, usually all of them with Handle(">", ...). Events that came from the
+// broker are not sent back.
+func Publish(publisher Publisher, source string) EventHandler {
+	return func(ctx context.Context, event Event) error {
+		if RemoteSource(ctx) != "" {
+			return nil
+		}
+
+		envelope, err := NewEnvelope(source, event)
+		if err != nil {
+			return err
+		}
+		body, err := json.Marshal(envelope)
+		if err != nil {
+			return fmt.Errorf("failed to encode envelope: %w", err)
+		}
+
+		return publisher.Publish(ctx, Message{
+			Topic:   envelope.Type,
+			Key:     envelope.ID,
+			Headers: map[string]string{"content-type": EnvelopeContentType},
+			Body:    body,
+		})
+	}
+}
+
+// Consumer feeds events from a broker to the dispatcher's handlers, which can
+// tell them apart through RemoteSource.
+type Consumer struct {
+	subscriber Subscriber
+	dispatcher *Dispatcher
+	source     string
+	logger     Logger
+}
+
+func NewConsumer(subscriber Subscriber, dispatcher *Dispatcher, source string) *Consumer {
+	return &Consumer{subscriber: subscriber, dispatcher: dispatcher, source: source, logger: log.Default()}
+}
+
+// Start subscribes to each pattern until ctx is cancelled. Envelopes this
+// same source published are skipped, since their local handlers already ran.
+func (c *Consumer) Start(ctx context.Context, patterns ...string) error {
+	for _, pattern := range patterns {
+		if err := c.subscriber.Subscribe(ctx, pattern, c.handle); err != nil {
+			return fmt.Errorf("failed to subscribe to %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// handle only fails when the handlers do. Messages that can never be
+// delivered, such as malformed envelopes, event types this build does not
+// know or newer versions of known ones, are logged and dropped rather than
+// redelivered forever.
+func (c *Consumer) handle(ctx context.Context, message Message) error {
+	var envelope Envelope
+	if err := json.Unmarshal(message.Body, &envelope); err != nil {
+		c.logger.Printf("Dropping malformed message on %s: %v", message.Topic, err)
+		return nil
+	}
+
+	if envelope.Source == c.source {
+		return nil
+	}
+
+	event, err := c.dispatcher.Open(&envelope)
+	if err != nil {
+		c.logger.Printf("Dropping event %s from %s: %v", envelope.ID, envelope.Source, err)
+		return nil
+	}
+
+	return c.dispatcher.DeliverContext(context.WithValue(ctx, remoteSourceKey{}, envelope.Source), event)
+}
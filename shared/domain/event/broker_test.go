@@ -0,0 +1,177 @@
+package shared_event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loopbackBroker delivers synchronously to every matching subscriber and
+// reports the first handler error to the publisher, so tests can see it.
+type loopbackBroker struct {
+	published []Message
+	handlers  map[string]func(ctx context.Context, message Message) error
+}
+
+func newLoopbackBroker() *loopbackBroker {
+	return &loopbackBroker{handlers: make(map[string]func(ctx context.Context, message Message) error)}
+}
+
+func (b *loopbackBroker) Publish(ctx context.Context, message Message) error {
+	b.published = append(b.published, message)
+	for pattern, handler := range b.handlers {
+		if MatchTopic(pattern, message.Topic) {
+			if err := handler(ctx, message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *loopbackBroker) Subscribe(ctx context.Context, pattern string, handler func(ctx context.Context, message Message) error) error {
+	b.handlers[pattern] = handler
+	return nil
+}
+
+type versionedEvent struct {
+	At time.Time
+}
+
+func (e *versionedEvent) EventName() string     { return "test.versioned" }
+func (e *versionedEvent) OccurredOn() time.Time { return e.At }
+func (e *versionedEvent) EventVersion() int     { return 2 }
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	event := &testEvent{Name: "ada", At: at}
+	event.SetEventID("event-1")
+
+	envelope, err := NewEnvelope("education", event)
+	require.NoError(t, err)
+
+	body, err := json.Marshal(envelope)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &fields))
+	assert.Equal(t, "1.0", fields["specversion"])
+	assert.Equal(t, "event-1", fields["id"])
+	assert.Equal(t, "education", fields["source"])
+	assert.Equal(t, "test.happened", fields["type"])
+	assert.Equal(t, "application/json", fields["datacontenttype"])
+	assert.Equal(t, float64(1), fields["dataversion"])
+
+	d := NewDispatcher()
+	require.NoError(t, d.RegisterEvent(func() Event { return &testEvent{} }))
+
+	var decoded Envelope
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	opened, err := d.Open(&decoded)
+	require.NoError(t, err)
+	assert.Equal(t, event, opened)
+}
+
+func TestEnvelope_GeneratesIDsAndVersions(t *testing.T) {
+	envelope, err := NewEnvelope("education", &versionedEvent{})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, envelope.ID)
+	assert.Equal(t, 2, envelope.DataVersion)
+}
+
+func TestDispatcher_OpenRejectsWhatItCannotRead(t *testing.T) {
+	d := NewDispatcher()
+	require.NoError(t, d.RegisterEvents(
+		func() Event { return &testEvent{} },
+		func() Event { return &versionedEvent{} },
+	))
+
+	_, err := d.Open(&Envelope{SpecVersion: "0.3", Type: "test.happened", Data: []byte(`{}`)})
+	assert.ErrorIs(t, err, ErrUnsupportedEnvelope)
+
+	_, err = d.Open(&Envelope{SpecVersion: "1.0", Type: "test.versioned", DataVersion: 3, Data: []byte(`{}`)})
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	_, err = d.Open(&Envelope{SpecVersion: "1.0", Type: "billing.paid", DataVersion: 1, Data: []byte(`{}`)})
+	assert.ErrorIs(t, err, ErrUnknownEvent)
+}
+
+func TestPublishAndConsume(t *testing.T) {
+	broker := newLoopbackBroker()
+
+	education := NewDispatcher()
+	require.NoError(t, education.RegisterEvent(func() Event { return &testEvent{} }))
+	require.NoError(t, education.Handle(">", Publish(broker, "education")))
+
+	billing := NewDispatcher()
+	require.NoError(t, billing.RegisterEvent(func() Event { return &testEvent{} }))
+	require.NoError(t, billing.Handle(">", Publish(broker, "billing")))
+
+	var received *testEvent
+	var source string
+	require.NoError(t, Subscribe(billing, func(ctx context.Context, e *testEvent) error {
+		received, source = e, RemoteSource(ctx)
+		return nil
+	}))
+	require.NoError(t, NewConsumer(broker, billing, "billing").Start(context.Background(), "test.>"))
+
+	event := &testEvent{Name: "ada"}
+	event.SetEventID("event-1")
+	require.NoError(t, education.DispatchSync(event))
+
+	require.NotNil(t, received)
+	assert.Equal(t, "ada", received.Name)
+	assert.Equal(t, "event-1", received.EventID())
+	assert.Equal(t, "education", source)
+	// Billing's own forwarder saw the remote event but did not send it back.
+	require.Len(t, broker.published, 1)
+	assert.Equal(t, "test.happened", broker.published[0].Topic)
+	assert.Equal(t, "event-1", broker.published[0].Key)
+	assert.Equal(t, EnvelopeContentType, broker.published[0].Headers["content-type"])
+}
+
+func TestConsumer_SkipsOwnEvents(t *testing.T) {
+	broker := newLoopbackBroker()
+	d := NewDispatcher()
+	require.NoError(t, d.RegisterEvent(func() Event { return &testEvent{} }))
+	require.NoError(t, d.Handle(">", Publish(broker, "education")))
+
+	calls := 0
+	require.NoError(t, d.Handle("test.happened", func(ctx context.Context, e Event) error {
+		calls++
+		return nil
+	}))
+	require.NoError(t, NewConsumer(broker, d, "education").Start(context.Background(), ">"))
+
+	require.NoError(t, d.DispatchSync(&testEvent{}))
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestConsumer_ReportsHandlerErrorsAndDropsUnreadable(t *testing.T) {
+	broker := newLoopbackBroker()
+	d := NewDispatcher()
+	require.NoError(t, d.RegisterEvent(func() Event { return &testEvent{} }))
+	require.NoError(t, d.Handle("test.happened", func(ctx context.Context, e Event) error {
+		return errors.New("ledger unavailable")
+	}))
+
+	consumer := NewConsumer(broker, d, "billing")
+	logger := &recordingLogger{}
+	consumer.logger = logger
+	require.NoError(t, consumer.Start(context.Background(), ">"))
+
+	err := Publish(broker, "education")(context.Background(), &testEvent{})
+	assert.ErrorContains(t, err, "ledger unavailable")
+
+	assert.NoError(t, broker.Publish(context.Background(), Message{Topic: "test.happened", Body: []byte("not json")}))
+	assert.NoError(t, broker.Publish(context.Background(), Message{Topic: "billing.paid", Body: []byte(`{"specversion":"1.0","id":"1","source":"payments","type":"billing.paid","data":{}}`)}))
+	assert.Contains(t, logger.String(), "Dropping malformed message on test.happened")
+	assert.Contains(t, logger.String(), "Dropping event 1 from payments")
+}
@@ -1,74 +1,427 @@
 package shared_event
 
-import "sync"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
 
+// ErrUnknownEvent is returned when subscribing to a name or pattern that no
+// registered event type matches, which usually means a typo.
+var ErrUnknownEvent = errors.New("unknown event")
+
+// Handler is the original handler signature. It cannot report failures
+// other than by panicking; new code should use EventHandler.
 type Handler func(event interface{})
 
+// EventHandler is the handler contract. Returning an error or panicking
+// fails the delivery, which is then retried per the event's RetryPolicy. The
+// context is cancelled when the handler's timeout expires; a handler that
+// ignores it holds back its own retry until it returns.
+type EventHandler func(ctx context.Context, event Event) error
+
+// Adapt lets a Handler be used where an EventHandler is expected.
+func Adapt(handler Handler) EventHandler {
+	return func(ctx context.Context, event Event) error {
+		handler(event)
+		return nil
+	}
+}
+
+// RetryPolicy controls how often a failing handler is called for one event.
+// The delay starts at Backoff and doubles per attempt, up to MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// Metrics observes every handler call, including retries.
+type Metrics interface {
+	Dispatched(eventName string)
+	Failed(eventName string)
+	Observe(eventName string, latency time.Duration)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) Dispatched(string)             {}
+func (noopMetrics) Failed(string)                 {}
+func (noopMetrics) Observe(string, time.Duration) {}
+
+type DispatcherOption func(*Dispatcher)
+
+func WithLogger(logger Logger) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.logger = logger
+	}
+}
+
+func WithMetrics(metrics Metrics) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.metrics = metrics
+	}
+}
+
+// WithRetryPolicy sets the policy for one event name. Events without one use
+// the default policy, which makes a single attempt unless
+// WithDefaultRetryPolicy says otherwise.
+func WithRetryPolicy(eventName string, policy RetryPolicy) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.retries[eventName] = policy
+	}
+}
+
+func WithDefaultRetryPolicy(policy RetryPolicy) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.defaultRetry = policy
+	}
+}
+
+// WithHandlerTimeout bounds each handler call. Zero means no limit.
+func WithHandlerTimeout(timeout time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.timeout = timeout
+	}
+}
+
+type HandleOption func(*subscription)
+
+// Timeout overrides the dispatcher's handler timeout for one handler.
+func Timeout(timeout time.Duration) HandleOption {
+	return func(s *subscription) {
+		s.timeout = timeout
+	}
+}
+
+type subscription struct {
+	pattern string
+	handler EventHandler
+	timeout time.Duration
+}
+
 type Dispatcher struct {
-	handlers map[string][]Handler
-	mu       sync.RWMutex
+	handlers []subscription
+	// types lets events read back from storage be rebuilt as the concrete
+	// values their handlers expect.
+	types        map[string]func() Event
+	retries      map[string]RetryPolicy
+	defaultRetry RetryPolicy
+	timeout      time.Duration
+	logger       Logger
+	metrics      Metrics
+	sleep        func(time.Duration)
+	mu           sync.RWMutex
 }
 
-func NewDispatcher() *Dispatcher {
-	return &Dispatcher{handlers: make(map[string][]Handler)}
+func NewDispatcher(opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		types:   make(map[string]func() Event),
+		retries: make(map[string]RetryPolicy),
+		logger:  log.Default(),
+		metrics: noopMetrics{},
+		sleep:   time.Sleep,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
+// Register subscribes a Handler through Adapt. Like http.ServeMux it panics
+// on names that cannot match any event, since that is a programming error
+// found at startup; use Handle or Subscribe to get the error instead.
 func (d *Dispatcher) Register(eventName string, handler Handler) {
+	if err := d.Handle(eventName, Adapt(handler)); err != nil {
+		panic(err)
+	}
+}
+
+// Subscribe registers a handler for the event type T, taking the name from
+// T itself and registering T for decoding.
+//
+//	shared_event.Subscribe(d, func(ctx context.Context, e *user_event.UserCreatedEvent) error { ... })
+func Subscribe[T Event](d *Dispatcher, handler func(ctx context.Context, event T) error, opts ...HandleOption) error {
+	factory, err := factoryOf[T]()
+	if err != nil {
+		return err
+	}
+	if err := d.RegisterEvent(factory); err != nil {
+		return err
+	}
+
+	return d.Handle(factory().EventName(), func(ctx context.Context, event Event) error {
+		typed, ok := event.(T)
+		if !ok {
+			return fmt.Errorf("event %s is %T, not %T", event.EventName(), event, typed)
+		}
+		return handler(ctx, typed)
+	}, opts...)
+}
+
+// Handle subscribes to an event name or a topic pattern. Patterns match
+// dot-separated segments: * stands for exactly one segment and a trailing >
+// for one or more, so "user.*" matches "user.created" and "auth.>" matches
+// every auth event. Exact names and patterns alike must match at least one
+// registered event type.
+func (d *Dispatcher) Handle(pattern string, handler EventHandler, opts ...HandleOption) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
-	d.handlers[eventName] = append(d.handlers[eventName], handler)
+
+	if !d.known(pattern) {
+		return fmt.Errorf("%w: %q", ErrUnknownEvent, pattern)
+	}
+
+	s := subscription{pattern: pattern, handler: handler, timeout: d.timeout}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	d.handlers = append(d.handlers, s)
+	return nil
 }
 
-func (d *Dispatcher) Dispatch(event interface{}) {
-	if event == nil {
-		return
+func (d *Dispatcher) known(pattern string) bool {
+	for name := range d.types {
+		if MatchTopic(pattern, name) {
+			return true
+		}
 	}
+	return false
+}
 
+// RegisterEvent records an event type so it can be subscribed to and decoded,
+// keyed by the name the factory's value reports. Registering the same type
+// again is a no-op; a different type under the same name is an error.
+func (d *Dispatcher) RegisterEvent(factory func() Event) error {
+	event := factory()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if existing, ok := d.types[event.EventName()]; ok {
+		if reflect.TypeOf(existing()) != reflect.TypeOf(event) {
+			return fmt.Errorf("event %s is already registered as %T", event.EventName(), existing())
+		}
+		return nil
+	}
+	d.types[event.EventName()] = factory
+	return nil
+}
+
+// RegisterEvents registers a module's event catalog, stopping at the first
+// conflict.
+func (d *Dispatcher) RegisterEvents(factories ...func() Event) error {
+	for _, factory := range factories {
+		if err := d.RegisterEvent(factory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// factoryOf builds a factory for T, which must be a concrete type such as a
+// pointer to the event struct.
+func factoryOf[T Event]() (func() Event, error) {
+	var zero T
+	t := reflect.TypeOf(zero)
+	if t == nil {
+		return nil, errors.New("cannot subscribe to an interface type; use a concrete event type")
+	}
+	if t.Kind() != reflect.Pointer {
+		return func() Event { var e T; return e }, nil
+	}
+	return func() Event { return reflect.New(t.Elem()).Interface().(Event) }, nil
+}
+
+// Decode rebuilds an event from its JSON payload.
+func (d *Dispatcher) Decode(eventName string, payload []byte) (Event, error) {
 	d.mu.RLock()
-	defer d.mu.RUnlock()
+	factory, ok := d.types[eventName]
+	d.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownEvent, eventName)
+	}
 
+	event := factory()
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, fmt.Errorf("failed to decode event %q: %w", eventName, err)
+	}
+	return event, nil
+}
+
+// Dispatch runs each handler in its own goroutine. Failures are retried and
+// logged but not reported to the caller.
+func (d *Dispatcher) Dispatch(event interface{}) {
 	ev, ok := event.(Event)
-	if !ok {
+	if !ok || ev == nil {
 		return
 	}
 
-	handlers, ok := d.handlers[ev.EventName()]
-	if !ok || len(handlers) == 0 {
-		return
+	for _, s := range d.subscriptions(ev.EventName()) {
+		go func(s subscription) {
+			_ = d.run(context.Background(), s, ev)
+		}(s)
 	}
+}
 
-	for _, h := range handlers {
-		go func(handler Handler, e interface{}) {
-			defer func() { recover() }()
-			handler(e)
-		}(h, event)
+// DispatchSync runs the handlers one after another and returns the errors of
+// those that still failed after their retries.
+func (d *Dispatcher) DispatchSync(event interface{}) error {
+	ev, ok := event.(Event)
+	if !ok || ev == nil {
+		return nil
 	}
+	return d.Deliver(ev)
+}
 
+// Deliver is DispatchSync for callers that already hold an Event.
+func (d *Dispatcher) Deliver(event Event) error {
+	return d.DeliverContext(context.Background(), event)
 }
 
-func (d *Dispatcher) DispatchSync(event interface{}) {
-	if event == nil {
-		return
+// DeliverContext is Deliver with a parent for the handlers' contexts.
+func (d *Dispatcher) DeliverContext(ctx context.Context, event Event) error {
+	var errs []error
+	for _, s := range d.subscriptions(event.EventName()) {
+		if err := d.run(ctx, s, event); err != nil {
+			errs = append(errs, err)
+		}
 	}
+	return errors.Join(errs...)
+}
 
+func (d *Dispatcher) subscriptions(eventName string) []subscription {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
-	ev, ok := event.(Event)
-	if !ok {
-		return
+	var matched []subscription
+	for _, s := range d.handlers {
+		if MatchTopic(s.pattern, eventName) {
+			matched = append(matched, s)
+		}
 	}
+	return matched
+}
 
-	handlers, ok := d.handlers[ev.EventName()]
-	if !ok || len(handlers) == 0 {
-		return
+// MatchTopic reports whether an event name matches a pattern in the syntax
+// Handle accepts, which is also the NATS subject syntax.
+func MatchTopic(pattern, name string) bool {
+	patternSegments := strings.Split(pattern, ".")
+	nameSegments := strings.Split(name, ".")
+
+	for i, segment := range patternSegments {
+		if segment == ">" && i == len(patternSegments)-1 {
+			return len(nameSegments) > i
+		}
+		if i >= len(nameSegments) {
+			return false
+		}
+		if segment != "*" && segment != nameSegments[i] {
+			return false
+		}
 	}
+	return len(patternSegments) == len(nameSegments)
+}
 
-	for _, h := range handlers {
-		func(handler Handler, e interface{}) {
-			defer func() { recover() }()
-			handler(e)
-		}(h, event)
+func (d *Dispatcher) retryPolicy(eventName string) RetryPolicy {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if policy, ok := d.retries[eventName]; ok {
+		return policy
 	}
+	return d.defaultRetry
+}
+
+func (d *Dispatcher) run(ctx context.Context, s subscription, event Event) error {
+	name := event.EventName()
+	policy := d.retryPolicy(name)
+	attempts := policy.attempts()
+
+	var err error
+	var abandoned <-chan error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			if abandoned != nil {
+				// The call that timed out is still running; starting another
+				// would let two copies of the handler act on the event at once.
+				<-abandoned
+			}
+			d.sleep(backoff(policy.Backoff, policy.MaxBackoff, attempt-1))
+		}
+
+		start := time.Now()
+		abandoned, err = d.invoke(ctx, s, event)
+		d.metrics.Dispatched(name)
+		d.metrics.Observe(name, time.Since(start))
+		if err == nil {
+			return nil
+		}
 
+		d.metrics.Failed(name)
+		d.logger.Printf("Handler for %s failed (attempt %d/%d): %v", name, attempt, attempts, err)
+	}
+	return err
+}
+
+// invoke stops waiting for the handler when its timeout expires, but cannot
+// stop the handler itself. It then returns a channel that receives once the
+// abandoned call finally returns, so a retry can wait for it.
+func (d *Dispatcher) invoke(ctx context.Context, s subscription, event Event) (<-chan error, error) {
+	if s.timeout <= 0 {
+		return nil, d.call(ctx, s.handler, event)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- d.call(ctx, s.handler, event) }()
+
+	select {
+	case err := <-done:
+		return nil, err
+	case <-ctx.Done():
+		return done, fmt.Errorf("handler for %s: %w", event.EventName(), ctx.Err())
+	}
+}
+
+func (d *Dispatcher) call(ctx context.Context, handler EventHandler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.logger.Printf("Handler for %s panicked: %v\n%s", event.EventName(), r, debug.Stack())
+			err = fmt.Errorf("handler for %s panicked: %v", event.EventName(), r)
+		}
+	}()
+	return handler(ctx, event)
+}
+
+// backoff doubles base for each attempt after the first, up to max when max
+// is set.
+func backoff(base, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if max > 0 && delay >= max {
+			return max
+		}
+	}
+	return delay
 }
@@ -0,0 +1,298 @@
+package shared_event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "\n")
+}
+
+func newTestDispatcher(opts ...DispatcherOption) (*Dispatcher, *recordingLogger, *MetricsRecorder, *[]time.Duration) {
+	logger := &recordingLogger{}
+	metrics := NewMetricsRecorder()
+	d := NewDispatcher(append([]DispatcherOption{WithLogger(logger), WithMetrics(metrics)}, opts...)...)
+
+	var sleeps []time.Duration
+	d.sleep = func(delay time.Duration) { sleeps = append(sleeps, delay) }
+	_ = d.RegisterEvent(func() Event { return &testEvent{} })
+	return d, logger, metrics, &sleeps
+}
+
+func TestDispatcher_DeliverReportsHandlerErrors(t *testing.T) {
+	d, _, metrics, _ := newTestDispatcher()
+	d.Handle("test.happened", func(ctx context.Context, e Event) error { return errors.New("smtp down") })
+	d.Handle("test.happened", func(ctx context.Context, e Event) error { return nil })
+
+	err := d.Deliver(&testEvent{})
+
+	assert.EqualError(t, err, "smtp down")
+	stats := metrics.Snapshot()["test.happened"]
+	assert.Equal(t, int64(2), stats.Dispatched)
+	assert.Equal(t, int64(1), stats.Failed)
+}
+
+func TestDispatcher_RetriesPerEventPolicy(t *testing.T) {
+	d, logger, metrics, sleeps := newTestDispatcher(
+		WithRetryPolicy("test.happened", RetryPolicy{MaxAttempts: 3, Backoff: time.Second, MaxBackoff: time.Minute}),
+	)
+	calls := 0
+	d.Handle("test.happened", func(ctx context.Context, e Event) error {
+		calls++
+		if calls < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	})
+
+	err := d.DispatchSync(&testEvent{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *sleeps)
+	assert.Equal(t, int64(2), metrics.Snapshot()["test.happened"].Failed)
+	assert.Contains(t, logger.String(), "attempt 1/3")
+}
+
+func TestDispatcher_DefaultPolicyMakesOneAttempt(t *testing.T) {
+	d, _, _, sleeps := newTestDispatcher()
+	calls := 0
+	d.Handle("test.happened", func(ctx context.Context, e Event) error {
+		calls++
+		return errors.New("permanent")
+	})
+
+	assert.Error(t, d.DispatchSync(&testEvent{}))
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, *sleeps)
+}
+
+func TestDispatcher_CapturesPanicsWithStack(t *testing.T) {
+	d, logger, _, _ := newTestDispatcher()
+	d.Register("test.happened", func(e interface{}) { panic("boom") })
+
+	err := d.DispatchSync(&testEvent{})
+
+	assert.EqualError(t, err, "handler for test.happened panicked: boom")
+	assert.Contains(t, logger.String(), "Handler for test.happened panicked: boom")
+	assert.Contains(t, logger.String(), "goroutine")
+}
+
+func TestDispatcher_HandlerTimeout(t *testing.T) {
+	d, _, _, _ := newTestDispatcher(WithHandlerTimeout(time.Hour))
+	d.Handle("test.happened", func(ctx context.Context, e Event) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, Timeout(10*time.Millisecond))
+
+	err := d.DispatchSync(&testEvent{})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDispatcher_RetryWaitsForTimedOutHandler(t *testing.T) {
+	d, _, _, _ := newTestDispatcher(
+		WithRetryPolicy("test.happened", RetryPolicy{MaxAttempts: 2}),
+		WithHandlerTimeout(10*time.Millisecond),
+	)
+	release := make(chan struct{})
+	var running, overlapped, calls int32
+	var mu sync.Mutex
+	d.Handle("test.happened", func(ctx context.Context, e Event) error {
+		mu.Lock()
+		calls++
+		running++
+		if running > 1 {
+			overlapped++
+		}
+		first := calls == 1
+		mu.Unlock()
+		defer func() { mu.Lock(); running--; mu.Unlock() }()
+
+		if first {
+			<-release
+			return errors.New("too late")
+		}
+		return nil
+	})
+
+	delivered := make(chan error, 1)
+	go func() { delivered <- d.DispatchSync(&testEvent{}) }()
+
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, int32(1), calls, "the retry must not start while the first call runs")
+	mu.Unlock()
+
+	close(release)
+	assert.NoError(t, <-delivered)
+	assert.Equal(t, int32(2), calls)
+	assert.Zero(t, overlapped)
+}
+
+func TestDispatcher_RegisterAdaptsLegacyHandlers(t *testing.T) {
+	d, _, _, _ := newTestDispatcher()
+	received := make(chan interface{}, 1)
+	d.Register("test.happened", func(e interface{}) { received <- e })
+
+	event := &testEvent{Name: "hello"}
+	d.Dispatch(event)
+
+	select {
+	case e := <-received:
+		assert.Same(t, event, e)
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+}
+
+func TestDispatcher_IgnoresNonEvents(t *testing.T) {
+	d, _, metrics, _ := newTestDispatcher()
+	d.Register("test.happened", func(e interface{}) { t.Fatal("unexpected call") })
+
+	d.Dispatch("not an event")
+	require.NoError(t, d.DispatchSync(nil))
+	assert.Empty(t, metrics.Snapshot())
+}
+
+type otherEvent struct {
+	At time.Time
+}
+
+func (e *otherEvent) EventName() string     { return "test.other" }
+func (e *otherEvent) OccurredOn() time.Time { return e.At }
+
+type impostorEvent struct{}
+
+func (e *impostorEvent) EventName() string     { return "test.happened" }
+func (e *impostorEvent) OccurredOn() time.Time { return time.Time{} }
+
+func TestSubscribe_DerivesNameAndType(t *testing.T) {
+	d := NewDispatcher()
+	var received *otherEvent
+
+	err := Subscribe(d, func(ctx context.Context, e *otherEvent) error {
+		received = e
+		return nil
+	})
+	require.NoError(t, err)
+
+	event := &otherEvent{At: time.Now()}
+	require.NoError(t, d.DispatchSync(event))
+	assert.Same(t, event, received)
+
+	decoded, err := d.Decode("test.other", []byte(`{}`))
+	require.NoError(t, err)
+	assert.IsType(t, &otherEvent{}, decoded)
+}
+
+func TestSubscribe_RejectsConflictingTypes(t *testing.T) {
+	d := NewDispatcher()
+	require.NoError(t, Subscribe(d, func(ctx context.Context, e *testEvent) error { return nil }))
+
+	err := Subscribe(d, func(ctx context.Context, e *impostorEvent) error { return nil })
+
+	assert.EqualError(t, err, "event test.happened is already registered as *shared_event.testEvent")
+}
+
+func TestSubscribe_RejectsInterfaceTypes(t *testing.T) {
+	err := Subscribe(NewDispatcher(), func(ctx context.Context, e Event) error { return nil })
+
+	assert.Error(t, err)
+}
+
+func TestDispatcher_RegisterEvents(t *testing.T) {
+	d := NewDispatcher()
+
+	require.NoError(t, d.RegisterEvents(
+		func() Event { return &testEvent{} },
+		func() Event { return &otherEvent{} },
+	))
+	require.NoError(t, d.Handle("test.other", func(ctx context.Context, e Event) error { return nil }))
+
+	err := d.RegisterEvents(func() Event { return &impostorEvent{} })
+	assert.EqualError(t, err, "event test.happened is already registered as *shared_event.testEvent")
+}
+
+func TestHandle_RejectsUnknownNames(t *testing.T) {
+	d, _, _, _ := newTestDispatcher()
+
+	err := d.Handle("test.hapened", func(ctx context.Context, e Event) error { return nil })
+	assert.ErrorIs(t, err, ErrUnknownEvent)
+
+	err = d.Handle("tset.*", func(ctx context.Context, e Event) error { return nil })
+	assert.ErrorIs(t, err, ErrUnknownEvent)
+
+	assert.Panics(t, func() { d.Register("test.hapened", func(e interface{}) {}) })
+}
+
+func TestHandle_TopicPatterns(t *testing.T) {
+	d, _, _, _ := newTestDispatcher()
+	require.NoError(t, d.RegisterEvent(func() Event { return &otherEvent{} }))
+
+	var wildcard, tail, exact []string
+	record := func(names *[]string) EventHandler {
+		return func(ctx context.Context, e Event) error {
+			*names = append(*names, e.EventName())
+			return nil
+		}
+	}
+	require.NoError(t, d.Handle("test.*", record(&wildcard)))
+	require.NoError(t, d.Handle(">", record(&tail)))
+	require.NoError(t, d.Handle("test.other", record(&exact)))
+
+	require.NoError(t, d.DispatchSync(&testEvent{}))
+	require.NoError(t, d.DispatchSync(&otherEvent{}))
+
+	assert.Equal(t, []string{"test.happened", "test.other"}, wildcard)
+	assert.Equal(t, []string{"test.happened", "test.other"}, tail)
+	assert.Equal(t, []string{"test.other"}, exact)
+}
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"user.created", "user.created", true},
+		{"user.created", "user.deleted", false},
+		{"user.*", "user.created", true},
+		{"user.*", "user.created.v2", false},
+		{"*.created", "role.created", true},
+		{"user.>", "user.created", true},
+		{"user.>", "user.created.v2", true},
+		{"user.>", "user", false},
+		{">", "user.created", true},
+		{"user.>.x", "user.created.x", false},
+		{"user", "user.created", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.match, MatchTopic(tt.pattern, tt.name))
+		})
+	}
+}
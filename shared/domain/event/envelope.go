@@ -0,0 +1,98 @@
+package shared_event
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// CloudEventsSpecVersion is the only CloudEvents version envelopes are
+	// written in and accepted from.
+	CloudEventsSpecVersion = "1.0"
+	// EnvelopeContentType marks a message body as a structured CloudEvent.
+	EnvelopeContentType = "application/cloudevents+json"
+)
+
+var (
+	ErrUnsupportedEnvelope = errors.New("unsupported envelope")
+	// ErrUnsupportedVersion is returned for payloads newer than the local
+	// event type, which may carry fields this build would silently drop.
+	ErrUnsupportedVersion = errors.New("unsupported event version")
+)
+
+// Versioned events report the version of their payload. Bump it when a field
+// is renamed or changes meaning; events that do not implement it are
+// version 1.
+type Versioned interface {
+	EventVersion() int
+}
+
+func versionOf(event Event) int {
+	if v, ok := event.(Versioned); ok {
+		return v.EventVersion()
+	}
+	return 1
+}
+
+// Envelope is an event in the CloudEvents 1.0 structured JSON format, with
+// the payload version in the dataversion extension attribute.
+type Envelope struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	DataVersion     int             `json:"dataversion"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// NewEnvelope wraps an event raised by source. Events that embed Identity
+// keep their id, so a redelivered event can still be recognised remotely.
+func NewEnvelope(source string, event Event) (*Envelope, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event %q: %w", event.EventName(), err)
+	}
+
+	id := EventID(event)
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	return &Envelope{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              id,
+		Source:          source,
+		Type:            event.EventName(),
+		Time:            event.OccurredOn(),
+		DataContentType: "application/json",
+		DataVersion:     versionOf(event),
+		Data:            data,
+	}, nil
+}
+
+// Open rebuilds the event in an envelope as its registered type.
+func (d *Dispatcher) Open(envelope *Envelope) (Event, error) {
+	if envelope.SpecVersion != CloudEventsSpecVersion {
+		return nil, fmt.Errorf("%w: specversion %q", ErrUnsupportedEnvelope, envelope.SpecVersion)
+	}
+
+	event, err := d.Decode(envelope.Type, envelope.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	if version := versionOf(event); envelope.DataVersion > version {
+		return nil, fmt.Errorf("%w: %s version %d, expected at most %d", ErrUnsupportedVersion, envelope.Type, envelope.DataVersion, version)
+	}
+
+	if e, ok := event.(identifiable); ok {
+		e.SetEventID(envelope.ID)
+	}
+	return event, nil
+}
@@ -23,3 +23,31 @@ func (a *AggregateRoot) PullDomainEvents() []Event {
 	a.domainEvents = []Event{}
 	return events
 }
+
+// Identity gives an event a stable id. Events delivered through the outbox
+// embed it; the id is assigned once when the event is stored, so every
+// redelivery carries the same one and handlers can skip repeats.
+type Identity struct {
+	ID string `json:"id"`
+}
+
+func (i *Identity) EventID() string {
+	return i.ID
+}
+
+func (i *Identity) SetEventID(id string) {
+	i.ID = id
+}
+
+type identifiable interface {
+	EventID() string
+	SetEventID(id string)
+}
+
+// EventID returns the id of events that embed Identity, or "" for others.
+func EventID(event interface{}) string {
+	if e, ok := event.(identifiable); ok {
+		return e.EventID()
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package shared_event
+
+import (
+	"sync"
+	"time"
+)
+
+type EventStats struct {
+	Dispatched   int64
+	Failed       int64
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// MetricsRecorder is an in-memory Metrics keyed by event name.
+type MetricsRecorder struct {
+	mu    sync.Mutex
+	stats map[string]*EventStats
+}
+
+var _ Metrics = &MetricsRecorder{}
+
+func NewMetricsRecorder() *MetricsRecorder {
+	return &MetricsRecorder{stats: make(map[string]*EventStats)}
+}
+
+func (m *MetricsRecorder) Dispatched(eventName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.get(eventName).Dispatched++
+}
+
+func (m *MetricsRecorder) Failed(eventName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.get(eventName).Failed++
+}
+
+func (m *MetricsRecorder) Observe(eventName string, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := m.get(eventName)
+	stats.TotalLatency += latency
+	if latency > stats.MaxLatency {
+		stats.MaxLatency = latency
+	}
+}
+
+// Snapshot returns a copy of the stats collected so far.
+func (m *MetricsRecorder) Snapshot() map[string]EventStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]EventStats, len(m.stats))
+	for name, stats := range m.stats {
+		snapshot[name] = *stats
+	}
+	return snapshot
+}
+
+func (m *MetricsRecorder) get(eventName string) *EventStats {
+	stats, ok := m.stats[eventName]
+	if !ok {
+		stats = &EventStats{}
+		m.stats[eventName] = stats
+	}
+	return stats
+}
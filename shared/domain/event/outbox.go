@@ -0,0 +1,102 @@
+package shared_event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxPublished OutboxStatus = "published"
+	// OutboxDead marks events that failed MaxAttempts times. They stay in the
+	// table for inspection and are never retried automatically.
+	OutboxDead OutboxStatus = "dead"
+)
+
+// OutboxMessage is an event stored for delivery after the transaction that
+// raised it commits.
+type OutboxMessage struct {
+	ID            string
+	EventName     string
+	Payload       []byte
+	Status        OutboxStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	OccurredOn    time.Time
+	CreatedAt     time.Time
+	PublishedAt   *time.Time
+}
+
+// NewOutboxMessage serializes the event, first giving it an id when it
+// embeds Identity and has none yet.
+func NewOutboxMessage(event Event, now time.Time) (*OutboxMessage, error) {
+	id := EventID(event)
+	if id == "" {
+		id = uuid.New().String()
+		if e, ok := event.(identifiable); ok {
+			e.SetEventID(id)
+		}
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event %q: %w", event.EventName(), err)
+	}
+
+	return &OutboxMessage{
+		ID:            id,
+		EventName:     event.EventName(),
+		Payload:       payload,
+		Status:        OutboxPending,
+		NextAttemptAt: now,
+		OccurredOn:    event.OccurredOn(),
+		CreatedAt:     now,
+	}, nil
+}
+
+type OutboxStore interface {
+	// FetchDue returns pending messages whose next attempt is not after now,
+	// oldest first, and claims them so concurrent relays each get different
+	// messages until they are marked.
+	FetchDue(now time.Time, limit int) ([]*OutboxMessage, error)
+	MarkPublished(id string, at time.Time) error
+	MarkFailed(id string, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkDead(id string, attempts int, lastError string) error
+}
+
+// ProcessedStore remembers which events a consumer has already handled.
+type ProcessedStore interface {
+	// MarkProcessed records eventID for consumer and runs handle, unless the
+	// pair is already recorded, and reports whether it did. The record only
+	// sticks when handle succeeds, and deliveries racing on the same pair run
+	// handle at most once between them.
+	MarkProcessed(consumer, eventID string, at time.Time, handle func() error) (bool, error)
+}
+
+// Deduplicate makes a handler run at most once per event id for the named
+// consumer. Events without an id, such as those dispatched in-process, always
+// run. A handler that fails leaves the id unrecorded so redelivery retries
+// it.
+func Deduplicate(store ProcessedStore, consumer string) HandleOption {
+	return func(s *subscription) {
+		handler := s.handler
+		s.handler = func(ctx context.Context, event Event) error {
+			id := EventID(event)
+			if id == "" {
+				return handler(ctx, event)
+			}
+
+			_, err := store.MarkProcessed(consumer, id, time.Now(), func() error {
+				return handler(ctx, event)
+			})
+			return err
+		}
+	}
+}
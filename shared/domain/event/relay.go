@@ -0,0 +1,92 @@
+package shared_event
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type RelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// MaxAttempts is how many failed deliveries move a message to the dead
+	// letter state.
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Relay moves events from the outbox to the dispatcher's handlers. A message
+// is only marked published once every handler ran without panicking, so
+// delivery is at least once: after a failure or a crash all handlers see the
+// event again, and should deduplicate by EventID (see Deduplicate).
+type Relay struct {
+	store      OutboxStore
+	dispatcher *Dispatcher
+	config     RelayConfig
+	now        func() time.Time
+}
+
+func NewRelay(store OutboxStore, dispatcher *Dispatcher, config RelayConfig) *Relay {
+	return &Relay{store: store, dispatcher: dispatcher, config: config, now: time.Now}
+}
+
+// Run relays due messages every PollInterval until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayOnce(); err != nil {
+			log.Printf("Failed to relay outbox: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce delivers one batch of due messages and returns how many were
+// published.
+func (r *Relay) RelayOnce() (int, error) {
+	messages, err := r.store.FetchDue(r.now(), r.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, m := range messages {
+		ok, err := r.relay(m)
+		if err != nil {
+			return published, err
+		}
+		if ok {
+			published++
+		}
+	}
+	return published, nil
+}
+
+func (r *Relay) relay(m *OutboxMessage) (bool, error) {
+	event, err := r.dispatcher.Decode(m.EventName, m.Payload)
+	if err == nil {
+		if e, ok := event.(identifiable); ok {
+			e.SetEventID(m.ID)
+		}
+		err = r.dispatcher.Deliver(event)
+	}
+
+	if err == nil {
+		return true, r.store.MarkPublished(m.ID, r.now())
+	}
+
+	attempts := m.Attempts + 1
+	if attempts >= r.config.MaxAttempts {
+		log.Printf("Outbox message %s (%s) moved to dead letter after %d attempts: %v", m.ID, m.EventName, attempts, err)
+		return false, r.store.MarkDead(m.ID, attempts, err.Error())
+	}
+	return false, r.store.MarkFailed(m.ID, attempts, r.now().Add(backoff(r.config.BaseBackoff, r.config.MaxBackoff, attempts)), err.Error())
+}
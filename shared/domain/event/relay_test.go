@@ -0,0 +1,197 @@
+package shared_event
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	Identity
+	Name string
+	At   time.Time
+}
+
+func (e *testEvent) EventName() string     { return "test.happened" }
+func (e *testEvent) OccurredOn() time.Time { return e.At }
+
+type fakeOutboxStore struct {
+	messages  map[string]*OutboxMessage
+	published []string
+}
+
+func newFakeOutboxStore(messages ...*OutboxMessage) *fakeOutboxStore {
+	store := &fakeOutboxStore{messages: map[string]*OutboxMessage{}}
+	for _, m := range messages {
+		store.messages[m.ID] = m
+	}
+	return store
+}
+
+func (s *fakeOutboxStore) FetchDue(now time.Time, limit int) ([]*OutboxMessage, error) {
+	var due []*OutboxMessage
+	for _, m := range s.messages {
+		if m.Status == OutboxPending && !m.NextAttemptAt.After(now) {
+			due = append(due, m)
+		}
+	}
+	return due, nil
+}
+
+func (s *fakeOutboxStore) MarkPublished(id string, at time.Time) error {
+	s.messages[id].Status = OutboxPublished
+	s.messages[id].PublishedAt = &at
+	s.published = append(s.published, id)
+	return nil
+}
+
+func (s *fakeOutboxStore) MarkFailed(id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	s.messages[id].Attempts = attempts
+	s.messages[id].NextAttemptAt = nextAttemptAt
+	s.messages[id].LastError = lastError
+	return nil
+}
+
+func (s *fakeOutboxStore) MarkDead(id string, attempts int, lastError string) error {
+	s.messages[id].Status = OutboxDead
+	s.messages[id].Attempts = attempts
+	s.messages[id].LastError = lastError
+	return nil
+}
+
+type fakeProcessedStore struct {
+	seen map[string]bool
+}
+
+func (s *fakeProcessedStore) MarkProcessed(consumer, eventID string, at time.Time, handle func() error) (bool, error) {
+	key := consumer + "/" + eventID
+	if s.seen[key] {
+		return false, nil
+	}
+	if err := handle(); err != nil {
+		return false, err
+	}
+	s.seen[key] = true
+	return true, nil
+}
+
+var relayNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestRelay(t *testing.T, handler Handler) (*Relay, *fakeOutboxStore, *OutboxMessage) {
+	message, err := NewOutboxMessage(&testEvent{Name: "hello", At: relayNow}, relayNow)
+	require.NoError(t, err)
+
+	dispatcher := NewDispatcher()
+	require.NoError(t, dispatcher.RegisterEvent(func() Event { return &testEvent{} }))
+	dispatcher.Register("test.happened", handler)
+
+	store := newFakeOutboxStore(message)
+	relay := NewRelay(store, dispatcher, RelayConfig{BatchSize: 10, MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: 3 * time.Second})
+	relay.now = func() time.Time { return relayNow }
+	return relay, store, message
+}
+
+func TestNewOutboxMessage_AssignsEventID(t *testing.T) {
+	event := &testEvent{Name: "hello", At: relayNow}
+
+	message, err := NewOutboxMessage(event, relayNow)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, message.ID)
+	assert.Equal(t, message.ID, event.EventID())
+	assert.Equal(t, "test.happened", message.EventName)
+	assert.Equal(t, OutboxPending, message.Status)
+	assert.JSONEq(t, `{"id":"`+message.ID+`","Name":"hello","At":"2024-01-01T12:00:00Z"}`, string(message.Payload))
+}
+
+func TestRelay_DeliversDecodedEvent(t *testing.T) {
+	var received *testEvent
+	relay, store, message := newTestRelay(t, func(e interface{}) { received = e.(*testEvent) })
+
+	published, err := relay.RelayOnce()
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []string{message.ID}, store.published)
+	require.NotNil(t, received)
+	assert.Equal(t, "hello", received.Name)
+	assert.Equal(t, message.ID, received.EventID())
+}
+
+func TestRelay_RetriesWithBackoffThenDeadLetters(t *testing.T) {
+	relay, store, message := newTestRelay(t, func(e interface{}) { panic(errors.New("smtp down")) })
+
+	_, err := relay.RelayOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 1, message.Attempts)
+	assert.Equal(t, relayNow.Add(time.Second), message.NextAttemptAt)
+	assert.Contains(t, message.LastError, "smtp down")
+
+	retryAt := message.NextAttemptAt
+	relay.now = func() time.Time { return retryAt }
+	_, err = relay.RelayOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 2, message.Attempts)
+	assert.Equal(t, retryAt.Add(2*time.Second), message.NextAttemptAt)
+
+	retryAt = message.NextAttemptAt
+	_, err = relay.RelayOnce()
+	require.NoError(t, err)
+	assert.Equal(t, OutboxDead, message.Status)
+	assert.Equal(t, 3, message.Attempts)
+	assert.Empty(t, store.published)
+}
+
+func TestRelay_UnknownEventIsRetried(t *testing.T) {
+	relay, _, message := newTestRelay(t, func(e interface{}) {})
+	message.EventName = "test.unknown"
+
+	_, err := relay.RelayOnce()
+
+	require.NoError(t, err)
+	assert.Equal(t, OutboxPending, message.Status)
+	assert.Contains(t, message.LastError, `unknown event "test.unknown"`)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, backoff(time.Second, 5*time.Second, 1))
+	assert.Equal(t, 2*time.Second, backoff(time.Second, 5*time.Second, 2))
+	assert.Equal(t, 4*time.Second, backoff(time.Second, 5*time.Second, 3))
+	assert.Equal(t, 5*time.Second, backoff(time.Second, 5*time.Second, 4))
+	assert.Equal(t, 8*time.Second, backoff(time.Second, 0, 4))
+}
+
+func TestDeduplicate_SkipsRepeatedEventIDs(t *testing.T) {
+	d := NewDispatcher()
+	require.NoError(t, d.RegisterEvent(func() Event { return &testEvent{} }))
+	calls := 0
+	require.NoError(t, d.Handle("test.happened", func(ctx context.Context, e Event) error {
+		calls++
+		return nil
+	}, Deduplicate(&fakeProcessedStore{seen: map[string]bool{}}, "consumer")))
+
+	event := &testEvent{Identity: Identity{ID: "event-1"}}
+	require.NoError(t, d.Deliver(event))
+	require.NoError(t, d.Deliver(event))
+	require.NoError(t, d.Deliver(&testEvent{Identity: Identity{ID: "event-2"}}))
+	require.NoError(t, d.Deliver(&testEvent{}))
+	require.NoError(t, d.Deliver(&testEvent{}))
+
+	assert.Equal(t, 4, calls)
+}
+
+func TestDeduplicate_DoesNotMarkFailedHandlers(t *testing.T) {
+	store := &fakeProcessedStore{seen: map[string]bool{}}
+	d := NewDispatcher()
+	require.NoError(t, d.RegisterEvent(func() Event { return &testEvent{} }))
+	require.NoError(t, d.Handle("test.happened", func(ctx context.Context, e Event) error {
+		return errors.New("boom")
+	}, Deduplicate(store, "consumer")))
+
+	assert.Error(t, d.Deliver(&testEvent{Identity: Identity{ID: "event-1"}}))
+	assert.False(t, store.seen["consumer/event-1"])
+}
@@ -0,0 +1,235 @@
+package broker
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type KafkaOption func(*KafkaBroker)
+
+// WithTLS secures the connections to the brokers.
+func WithTLS(config *tls.Config) KafkaOption {
+	return func(b *KafkaBroker) {
+		b.clientOpts = append(b.clientOpts, kgo.DialTLSConfig(config))
+	}
+}
+
+// WithClientID names the client in the brokers' logs and quotas.
+func WithClientID(id string) KafkaOption {
+	return func(b *KafkaBroker) {
+		b.clientOpts = append(b.clientOpts, kgo.ClientID(id))
+	}
+}
+
+// WithRetryBackoff sets how long a consumer waits before handling a record
+// again after its handler failed.
+func WithRetryBackoff(backoff time.Duration) KafkaOption {
+	return func(b *KafkaBroker) {
+		b.retryBackoff = backoff
+	}
+}
+
+// KafkaBroker publishes to and consumes from Kafka. Publishing waits until
+// every in-sync replica has the record. Each Subscribe joins the broker's
+// consumer group with a client of its own; instances in one group share the
+// partitions. Offsets are committed once the handler succeeds, and a failing
+// handler rewinds its partition so the record is handled again: delivery is
+// at least once, in order per partition.
+type KafkaBroker struct {
+	seeds        []string
+	group        string
+	clientOpts   []kgo.Opt
+	retryBackoff time.Duration
+	producer     *kgo.Client
+
+	mu        sync.Mutex
+	consumers []*kgo.Client
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+var _ shared_event.Broker = &KafkaBroker{}
+
+func NewKafkaBroker(seeds []string, group string, opts ...KafkaOption) (*KafkaBroker, error) {
+	b := &KafkaBroker{
+		seeds:        seeds,
+		group:        group,
+		retryBackoff: time.Second,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	producer, err := kgo.NewClient(b.options()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+	b.producer = producer
+	return b, nil
+}
+
+func (b *KafkaBroker) Publish(ctx context.Context, message shared_event.Message) error {
+	record := &kgo.Record{Topic: message.Topic, Value: message.Body}
+	if message.Key != "" {
+		record.Key = []byte(message.Key)
+	}
+	for name, value := range message.Headers {
+		record.Headers = append(record.Headers, kgo.RecordHeader{Key: name, Value: []byte(value)})
+	}
+
+	if err := b.producer.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", message.Topic, err)
+	}
+	return nil
+}
+
+func (b *KafkaBroker) Subscribe(ctx context.Context, pattern string, handler func(ctx context.Context, message shared_event.Message) error) error {
+	client, err := kgo.NewClient(b.options(
+		kgo.ConsumerGroup(b.group),
+		kgo.ConsumeRegex(),
+		kgo.ConsumeTopics(topicPattern(pattern)),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		kgo.DisableAutoCommit(),
+		kgo.BlockRebalanceOnPoll(),
+	)...)
+	if err != nil {
+		return fmt.Errorf("failed to create Kafka consumer: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		client.Close()
+		return ErrClosed
+	}
+	b.consumers = append(b.consumers, client)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.consume(ctx, client, handler)
+	}()
+	return nil
+}
+
+// Close stops every consumer, leaving its group, and the producer.
+func (b *KafkaBroker) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	consumers := b.consumers
+	b.consumers = nil
+	b.mu.Unlock()
+
+	for _, client := range consumers {
+		client.Close()
+	}
+	b.wg.Wait()
+	b.producer.Close()
+	return nil
+}
+
+func (b *KafkaBroker) options(opts ...kgo.Opt) []kgo.Opt {
+	options := []kgo.Opt{kgo.SeedBrokers(b.seeds...)}
+	options = append(options, b.clientOpts...)
+	return append(options, opts...)
+}
+
+// consume polls until ctx is cancelled or the client is closed. Rebalances
+// wait until a batch is handled and committed, so no partition is committed
+// or rewound after it moved to another instance.
+func (b *KafkaBroker) consume(ctx context.Context, client *kgo.Client, handler func(ctx context.Context, message shared_event.Message) error) {
+	defer client.Close()
+
+	for {
+		fetches := client.PollFetches(ctx)
+		if fetches.IsClientClosed() || ctx.Err() != nil {
+			return
+		}
+		fetches.EachError(func(topic string, partition int32, err error) {
+			log.Printf("Failed to fetch %s[%d] from Kafka: %v", topic, partition, err)
+		})
+
+		var handled []*kgo.Record
+		rewind := make(map[string]map[int32]kgo.EpochOffset)
+		fetches.EachPartition(func(p kgo.FetchTopicPartition) {
+			done, failed, err := process(ctx, p.Records, handler)
+			handled = append(handled, done...)
+			if failed != nil {
+				log.Printf("Subscriber failed on %s[%d]@%d: %v", failed.Topic, failed.Partition, failed.Offset, err)
+				if rewind[failed.Topic] == nil {
+					rewind[failed.Topic] = make(map[int32]kgo.EpochOffset)
+				}
+				rewind[failed.Topic][failed.Partition] = kgo.EpochOffset{Epoch: failed.LeaderEpoch, Offset: failed.Offset}
+			}
+		})
+
+		if len(rewind) > 0 {
+			client.SetOffsets(rewind)
+		}
+		if len(handled) > 0 {
+			if err := client.CommitRecords(ctx, handled...); err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("Failed to commit Kafka offsets: %v", err)
+			}
+		}
+		client.AllowRebalance()
+
+		if len(rewind) > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(b.retryBackoff):
+			}
+		}
+	}
+}
+
+// process hands a partition's records to the handler in order. It returns
+// those that succeeded, and the first one that failed along with its error;
+// the records after it are left for the next fetch.
+func process(ctx context.Context, records []*kgo.Record, handler func(ctx context.Context, message shared_event.Message) error) ([]*kgo.Record, *kgo.Record, error) {
+	for i, record := range records {
+		message := shared_event.Message{Topic: record.Topic, Key: string(record.Key), Body: record.Value}
+		for _, header := range record.Headers {
+			if message.Headers == nil {
+				message.Headers = make(map[string]string)
+			}
+			message.Headers[header.Key] = string(header.Value)
+		}
+
+		if err := handler(ctx, message); err != nil {
+			return records[:i], record, err
+		}
+	}
+	return records, nil, nil
+}
+
+// topicPattern turns a MatchTopic pattern into the regular expression the
+// client matches whole topic names against.
+func topicPattern(pattern string) string {
+	segments := strings.Split(pattern, ".")
+	for i, segment := range segments {
+		switch {
+		case segment == "*":
+			segments[i] = `[^.]+`
+		case segment == ">" && i == len(segments)-1:
+			segments[i] = `.+`
+		default:
+			segments[i] = regexp.QuoteMeta(segment)
+		}
+	}
+	return "^" + strings.Join(segments, `\.`) + "$"
+}
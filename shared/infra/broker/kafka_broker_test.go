@@ -0,0 +1,128 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+func runKafkaCluster(t *testing.T) []string {
+	t.Helper()
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, "user.created", "user.deleted", "role.created"))
+	require.NoError(t, err)
+	t.Cleanup(cluster.Close)
+	return cluster.ListenAddrs()
+}
+
+func newTestKafkaBroker(t *testing.T, seeds []string, opts ...KafkaOption) *KafkaBroker {
+	t.Helper()
+	b, err := NewKafkaBroker(seeds, "system-education", opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func TestKafkaBroker_PublishAndSubscribe(t *testing.T) {
+	b := newTestKafkaBroker(t, runKafkaCluster(t))
+	ctx := context.Background()
+
+	require.NoError(t, b.Publish(ctx, shared_event.Message{Topic: "role.created", Key: "evt-0", Body: []byte(`{}`)}))
+	require.NoError(t, b.Publish(ctx, shared_event.Message{
+		Topic:   "user.created",
+		Key:     "evt-1",
+		Headers: map[string]string{"content-type": shared_event.EnvelopeContentType},
+		Body:    []byte(`{"id":"evt-1"}`),
+	}))
+
+	messages := make(chan shared_event.Message, 4)
+	require.NoError(t, b.Subscribe(ctx, "user.*", func(_ context.Context, message shared_event.Message) error {
+		messages <- message
+		return nil
+	}))
+
+	message := receive(t, messages)
+	assert.Equal(t, "user.created", message.Topic)
+	assert.Equal(t, "evt-1", message.Key)
+	assert.Equal(t, map[string]string{"content-type": shared_event.EnvelopeContentType}, message.Headers)
+	assert.Equal(t, `{"id":"evt-1"}`, string(message.Body))
+}
+
+func TestKafkaBroker_RewindsFailedRecords(t *testing.T) {
+	seeds := runKafkaCluster(t)
+	b := newTestKafkaBroker(t, seeds, WithRetryBackoff(10*time.Millisecond))
+	ctx := context.Background()
+
+	for _, key := range []string{"evt-1", "evt-2"} {
+		require.NoError(t, b.Publish(ctx, shared_event.Message{Topic: "user.created", Key: key, Body: []byte(`{}`)}))
+	}
+
+	attempts := make(chan shared_event.Message, 8)
+	var failed atomic.Bool
+	require.NoError(t, b.Subscribe(ctx, "user.>", func(_ context.Context, message shared_event.Message) error {
+		attempts <- message
+		if message.Key == "evt-2" && failed.CompareAndSwap(false, true) {
+			return errors.New("handler failed")
+		}
+		return nil
+	}))
+
+	keys := []string{receive(t, attempts).Key, receive(t, attempts).Key, receive(t, attempts).Key}
+	assert.Equal(t, []string{"evt-1", "evt-2", "evt-2"}, keys)
+
+	// Both records end up committed, so the group does not see them again.
+	admin, err := kgo.NewClient(kgo.SeedBrokers(seeds...))
+	require.NoError(t, err)
+	defer admin.Close()
+	assert.Eventually(t, func() bool {
+		offsets, err := kadm.NewClient(admin).FetchOffsets(ctx, "system-education")
+		if err != nil {
+			return false
+		}
+		committed, ok := offsets.Lookup("user.created", 0)
+		return ok && committed.At == 2
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestKafkaBroker_SubscribeAfterClose(t *testing.T) {
+	b := newTestKafkaBroker(t, runKafkaCluster(t))
+	require.NoError(t, b.Close())
+
+	err := b.Subscribe(context.Background(), ">", func(context.Context, shared_event.Message) error { return nil })
+
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestTopicPattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{"user.created", []string{"user.created"}, []string{"user.createdX", "userXcreated", "v1.user.created"}},
+		{"user.*", []string{"user.created", "user.deleted"}, []string{"user", "user.created.v2", "auth.login_failed"}},
+		{"user.>", []string{"user.created", "user.created.v2"}, []string{"user", "auth.login_failed"}},
+		{">", []string{"user.created", "auth"}, nil},
+	}
+
+	for _, c := range cases {
+		re := regexp.MustCompile(topicPattern(c.pattern))
+		for _, topic := range c.matches {
+			assert.True(t, re.MatchString(topic), "%s should match %s", c.pattern, topic)
+			assert.True(t, shared_event.MatchTopic(c.pattern, topic), "MatchTopic disagrees on %s %s", c.pattern, topic)
+		}
+		for _, topic := range c.misses {
+			assert.False(t, re.MatchString(topic), "%s should not match %s", c.pattern, topic)
+			assert.False(t, shared_event.MatchTopic(c.pattern, topic), "MatchTopic disagrees on %s %s", c.pattern, topic)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+var ErrClosed = errors.New("broker is closed")
+
+type memorySubscription struct {
+	pattern string
+	handler func(ctx context.Context, message shared_event.Message) error
+}
+
+// MemoryBroker is a broker that never leaves the process, for local
+// development and tests. Publish delivers to every matching subscriber before
+// it returns; like a real broker it does not report their errors to the
+// publisher, it logs them.
+type MemoryBroker struct {
+	subscriptions map[*memorySubscription]struct{}
+	closed        bool
+	mu            sync.RWMutex
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{subscriptions: make(map[*memorySubscription]struct{})}
+}
+
+var _ shared_event.Broker = &MemoryBroker{}
+
+func (b *MemoryBroker) Publish(ctx context.Context, message shared_event.Message) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrClosed
+	}
+	var matched []*memorySubscription
+	for s := range b.subscriptions {
+		if shared_event.MatchTopic(s.pattern, message.Topic) {
+			matched = append(matched, s)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, s := range matched {
+		if err := s.handler(ctx, message); err != nil {
+			log.Printf("Subscriber to %s failed on %s: %v", s.pattern, message.Topic, err)
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, pattern string, handler func(ctx context.Context, message shared_event.Message) error) error {
+	s := &memorySubscription{pattern: pattern, handler: handler}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	b.subscriptions[s] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscriptions, s)
+		b.mu.Unlock()
+	}()
+	return nil
+}
+
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.subscriptions = make(map[*memorySubscription]struct{})
+	return nil
+}
@@ -0,0 +1,61 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+func TestMemoryBroker_DeliversToMatchingSubscribers(t *testing.T) {
+	b := NewMemoryBroker()
+
+	var users, all []string
+	require.NoError(t, b.Subscribe(context.Background(), "user.*", func(ctx context.Context, m shared_event.Message) error {
+		users = append(users, m.Topic)
+		return nil
+	}))
+	require.NoError(t, b.Subscribe(context.Background(), ">", func(ctx context.Context, m shared_event.Message) error {
+		all = append(all, m.Topic)
+		return errors.New("not reported to the publisher")
+	}))
+
+	require.NoError(t, b.Publish(context.Background(), shared_event.Message{Topic: "user.created"}))
+	require.NoError(t, b.Publish(context.Background(), shared_event.Message{Topic: "auth.login_failed"}))
+
+	assert.Equal(t, []string{"user.created"}, users)
+	assert.Equal(t, []string{"user.created", "auth.login_failed"}, all)
+}
+
+func TestMemoryBroker_StopsWhenContextEnds(t *testing.T) {
+	b := NewMemoryBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	require.NoError(t, b.Subscribe(ctx, ">", func(ctx context.Context, m shared_event.Message) error {
+		calls++
+		return nil
+	}))
+	cancel()
+
+	require.Eventually(t, func() bool {
+		b.mu.RLock()
+		defer b.mu.RUnlock()
+		return len(b.subscriptions) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, b.Publish(context.Background(), shared_event.Message{Topic: "user.created"}))
+	assert.Zero(t, calls)
+}
+
+func TestMemoryBroker_Close(t *testing.T) {
+	b := NewMemoryBroker()
+	require.NoError(t, b.Close())
+
+	assert.ErrorIs(t, b.Publish(context.Background(), shared_event.Message{Topic: "user.created"}), ErrClosed)
+	assert.ErrorIs(t, b.Subscribe(context.Background(), ">", nil), ErrClosed)
+}
@@ -0,0 +1,273 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+const natsRequestTimeout = 10 * time.Second
+
+type natsOptions struct {
+	name        string
+	queue       string
+	stream      string
+	prefix      string
+	redelivery  time.Duration
+	connectOpts []nats.Option
+}
+
+type NATSOption func(*natsOptions)
+
+func newNATSOptions(opts []NATSOption) natsOptions {
+	options := natsOptions{stream: "EVENTS", prefix: "events", redelivery: time.Second}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func WithClientName(name string) NATSOption {
+	return func(o *natsOptions) {
+		o.name = name
+	}
+}
+
+// WithQueueGroup makes instances subscribed under the same group share a
+// durable consumer, so each message is handled by one of them and the
+// position survives restarts. Without it every Subscribe gets an ephemeral
+// consumer that only sees messages published while it runs.
+func WithQueueGroup(queue string) NATSOption {
+	return func(o *natsOptions) {
+		o.queue = queue
+	}
+}
+
+// WithStream sets the JetStream stream the events are kept in and the
+// subject prefix it captures: a topic is published as "<prefix>.<topic>".
+func WithStream(name, prefix string) NATSOption {
+	return func(o *natsOptions) {
+		o.stream = name
+		o.prefix = prefix
+	}
+}
+
+// WithRedeliveryDelay sets how long the server waits before delivering a
+// message again after its handler failed.
+func WithRedeliveryDelay(delay time.Duration) NATSOption {
+	return func(o *natsOptions) {
+		o.redelivery = delay
+	}
+}
+
+// WithConnectOptions passes options such as credentials or a custom TLS
+// configuration to the NATS client.
+func WithConnectOptions(opts ...nats.Option) NATSOption {
+	return func(o *natsOptions) {
+		o.connectOpts = append(o.connectOpts, opts...)
+	}
+}
+
+// NATSBroker keeps events in a JetStream stream. Publishing waits for the
+// server to store the message and uses the key as its message ID, so the
+// retries of the outbox are deduplicated. Handlers acknowledge what they
+// processed, and a failing handler has its message redelivered: delivery is
+// at least once. The client reconnects on its own; Publish fails while it is
+// disconnected, and the outbox retries.
+type NATSBroker struct {
+	conn    *nats.Conn
+	js      jetstream.JetStream
+	options natsOptions
+
+	mu        sync.Mutex
+	consumers map[jetstream.ConsumeContext]struct{}
+	closed    bool
+}
+
+var _ shared_event.Broker = &NATSBroker{}
+
+// DialNATS connects to a comma separated list of server URLs and creates or
+// updates the stream. A tls:// URL secures the connection.
+func DialNATS(url string, opts ...NATSOption) (*NATSBroker, error) {
+	options := newNATSOptions(opts)
+
+	connectOpts := append([]nats.Option{
+		nats.Name(options.name),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Printf("NATS connection lost: %v", err)
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			log.Printf("NATS reconnected to %s", conn.ConnectedUrlRedacted())
+		}),
+	}, options.connectOpts...)
+
+	conn, err := nats.Connect(url, connectOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	b, err := NewNATSBroker(conn, opts...)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// NewNATSBroker uses an open connection, which Close closes.
+func NewNATSBroker(conn *nats.Conn, opts ...NATSOption) (*NATSBroker, error) {
+	options := newNATSOptions(opts)
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JetStream: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), natsRequestTimeout)
+	defer cancel()
+	if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     options.stream,
+		Subjects: []string{options.prefix + ".>"},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create stream %s: %w", options.stream, err)
+	}
+
+	return &NATSBroker{
+		conn:      conn,
+		js:        js,
+		options:   options,
+		consumers: make(map[jetstream.ConsumeContext]struct{}),
+	}, nil
+}
+
+func (b *NATSBroker) Publish(ctx context.Context, message shared_event.Message) error {
+	if message.Topic == "" || strings.ContainsAny(message.Topic, " \t\r\n*>") {
+		return fmt.Errorf("invalid NATS subject %q", message.Topic)
+	}
+
+	msg := nats.NewMsg(b.subject(message.Topic))
+	msg.Data = message.Body
+	for name, value := range message.Headers {
+		msg.Header.Set(name, value)
+	}
+
+	var opts []jetstream.PublishOpt
+	if message.Key != "" {
+		opts = append(opts, jetstream.WithMsgID(message.Key))
+	}
+	if _, err := b.js.PublishMsg(ctx, msg, opts...); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", message.Topic, err)
+	}
+	return nil
+}
+
+func (b *NATSBroker) Subscribe(ctx context.Context, pattern string, handler func(ctx context.Context, message shared_event.Message) error) error {
+	config := jetstream.ConsumerConfig{
+		FilterSubject: b.subject(pattern),
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		DeliverPolicy: jetstream.DeliverNewPolicy,
+	}
+	if b.options.queue != "" {
+		config.Durable = durableName(b.options.queue, pattern)
+	}
+
+	consumer, err := b.js.CreateOrUpdateConsumer(ctx, b.options.stream, config)
+	if err != nil {
+		return fmt.Errorf("failed to create consumer for %s: %w", pattern, err)
+	}
+
+	consumeCtx, err := consumer.Consume(func(msg jetstream.Msg) {
+		message := b.message(msg)
+		if err := handler(ctx, message); err != nil {
+			log.Printf("Subscriber to %s failed on %s: %v", pattern, message.Topic, err)
+			if err := msg.NakWithDelay(b.options.redelivery); err != nil {
+				log.Printf("Failed to reject NATS message: %v", err)
+			}
+			return
+		}
+		if err := msg.Ack(); err != nil {
+			log.Printf("Failed to acknowledge NATS message: %v", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to consume %s: %w", pattern, err)
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		consumeCtx.Stop()
+		return ErrClosed
+	}
+	b.consumers[consumeCtx] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			b.mu.Lock()
+			delete(b.consumers, consumeCtx)
+			b.mu.Unlock()
+			consumeCtx.Stop()
+		case <-consumeCtx.Closed():
+		}
+	}()
+	return nil
+}
+
+// Close stops the consumers and closes the connection.
+func (b *NATSBroker) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	consumers := b.consumers
+	b.consumers = nil
+	b.mu.Unlock()
+
+	for consumeCtx := range consumers {
+		consumeCtx.Stop()
+	}
+	b.conn.Close()
+	return nil
+}
+
+func (b *NATSBroker) subject(topic string) string {
+	return b.options.prefix + "." + topic
+}
+
+func (b *NATSBroker) message(msg jetstream.Msg) shared_event.Message {
+	message := shared_event.Message{
+		Topic: strings.TrimPrefix(msg.Subject(), b.options.prefix+"."),
+		Body:  msg.Data(),
+	}
+	for name, values := range msg.Headers() {
+		if name == jetstream.MsgIDHeader {
+			message.Key = values[0]
+			continue
+		}
+		if message.Headers == nil {
+			message.Headers = make(map[string]string)
+		}
+		message.Headers[name] = values[0]
+	}
+	return message
+}
+
+// durableName names the consumer a queue group shares for a pattern. NATS
+// does not allow '.', '*' or '>' in consumer names.
+func durableName(queue, pattern string) string {
+	return queue + "_" + strings.NewReplacer(".", "_", "*", "any", ">", "all").Replace(pattern)
+}
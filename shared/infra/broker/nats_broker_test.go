@@ -0,0 +1,165 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+func runNATSServer(t *testing.T) string {
+	t.Helper()
+	s, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	require.NoError(t, err)
+
+	go s.Start()
+	t.Cleanup(s.Shutdown)
+	require.True(t, s.ReadyForConnections(5*time.Second), "NATS server did not start")
+	return s.ClientURL()
+}
+
+func dialTestNATS(t *testing.T, url string, opts ...NATSOption) *NATSBroker {
+	t.Helper()
+	b, err := DialNATS(url, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+func receive(t *testing.T, messages <-chan shared_event.Message) shared_event.Message {
+	t.Helper()
+	select {
+	case message := <-messages:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a message")
+		return shared_event.Message{}
+	}
+}
+
+func TestNATSBroker_PublishAndSubscribe(t *testing.T) {
+	b := dialTestNATS(t, runNATSServer(t))
+	ctx := context.Background()
+
+	messages := make(chan shared_event.Message, 4)
+	require.NoError(t, b.Subscribe(ctx, "user.*", func(_ context.Context, message shared_event.Message) error {
+		messages <- message
+		return nil
+	}))
+
+	require.NoError(t, b.Publish(ctx, shared_event.Message{Topic: "role.created", Key: "evt-0", Body: []byte(`{}`)}))
+	require.NoError(t, b.Publish(ctx, shared_event.Message{
+		Topic:   "user.created",
+		Key:     "evt-1",
+		Headers: map[string]string{"content-type": shared_event.EnvelopeContentType},
+		Body:    []byte(`{"id":"evt-1"}`),
+	}))
+
+	message := receive(t, messages)
+	assert.Equal(t, "user.created", message.Topic)
+	assert.Equal(t, "evt-1", message.Key)
+	assert.Equal(t, map[string]string{"content-type": shared_event.EnvelopeContentType}, message.Headers)
+	assert.Equal(t, `{"id":"evt-1"}`, string(message.Body))
+}
+
+func TestNATSBroker_PublishDeduplicatesByKey(t *testing.T) {
+	b := dialTestNATS(t, runNATSServer(t))
+	ctx := context.Background()
+
+	messages := make(chan shared_event.Message, 4)
+	require.NoError(t, b.Subscribe(ctx, ">", func(_ context.Context, message shared_event.Message) error {
+		messages <- message
+		return nil
+	}))
+
+	for _, body := range []string{"first", "retry"} {
+		require.NoError(t, b.Publish(ctx, shared_event.Message{Topic: "user.created", Key: "evt-1", Body: []byte(body)}))
+	}
+	require.NoError(t, b.Publish(ctx, shared_event.Message{Topic: "user.created", Key: "evt-2", Body: []byte("second")}))
+
+	assert.Equal(t, "first", string(receive(t, messages).Body))
+	assert.Equal(t, "second", string(receive(t, messages).Body))
+}
+
+func TestNATSBroker_RedeliversFailedMessages(t *testing.T) {
+	b := dialTestNATS(t, runNATSServer(t), WithRedeliveryDelay(10*time.Millisecond))
+	ctx := context.Background()
+
+	attempts := make(chan shared_event.Message, 4)
+	var failed atomic.Bool
+	require.NoError(t, b.Subscribe(ctx, "user.>", func(_ context.Context, message shared_event.Message) error {
+		attempts <- message
+		if failed.CompareAndSwap(false, true) {
+			return errors.New("handler failed")
+		}
+		return nil
+	}))
+
+	require.NoError(t, b.Publish(ctx, shared_event.Message{Topic: "user.created", Key: "evt-1", Body: []byte(`{}`)}))
+
+	assert.Equal(t, "evt-1", receive(t, attempts).Key)
+	assert.Equal(t, "evt-1", receive(t, attempts).Key)
+}
+
+func TestNATSBroker_QueueGroupSharesMessages(t *testing.T) {
+	url := runNATSServer(t)
+	first := dialTestNATS(t, url, WithQueueGroup("workers"))
+	second := dialTestNATS(t, url, WithQueueGroup("workers"))
+	ctx := context.Background()
+
+	messages := make(chan shared_event.Message, 8)
+	handler := func(_ context.Context, message shared_event.Message) error {
+		messages <- message
+		return nil
+	}
+	require.NoError(t, first.Subscribe(ctx, "user.*", handler))
+	require.NoError(t, second.Subscribe(ctx, "user.*", handler))
+
+	for _, key := range []string{"evt-1", "evt-2", "evt-3"} {
+		require.NoError(t, first.Publish(ctx, shared_event.Message{Topic: "user.created", Key: key, Body: []byte(`{}`)}))
+	}
+
+	keys := []string{receive(t, messages).Key, receive(t, messages).Key, receive(t, messages).Key}
+	assert.ElementsMatch(t, []string{"evt-1", "evt-2", "evt-3"}, keys)
+	select {
+	case message := <-messages:
+		t.Fatalf("%s was delivered twice", message.Key)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestNATSBroker_PublishRejectsWildcards(t *testing.T) {
+	b := dialTestNATS(t, runNATSServer(t))
+
+	err := b.Publish(context.Background(), shared_event.Message{Topic: "user.*", Body: []byte(`{}`)})
+
+	assert.Error(t, err)
+}
+
+func TestNATSBroker_SubscribeAfterClose(t *testing.T) {
+	b := dialTestNATS(t, runNATSServer(t))
+	require.NoError(t, b.Close())
+
+	err := b.Subscribe(context.Background(), ">", func(context.Context, shared_event.Message) error { return nil })
+
+	assert.Error(t, err)
+}
+
+func TestDialNATS_FailsWithoutServer(t *testing.T) {
+	_, err := DialNATS("nats://127.0.0.1:1")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+// EventMetricsHandler exposes a MetricsRecorder's snapshot in the Prometheus
+// text format, one series per event name.
+type EventMetricsHandler struct {
+	recorder *shared_event.MetricsRecorder
+}
+
+var _ http.Handler = &EventMetricsHandler{}
+
+func NewEventMetricsHandler(recorder *shared_event.MetricsRecorder) *EventMetricsHandler {
+	return &EventMetricsHandler{recorder: recorder}
+}
+
+func (h *EventMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	snapshot := h.recorder.Snapshot()
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out strings.Builder
+	series := []struct {
+		name, kind, help string
+		value            func(shared_event.EventStats) string
+	}{
+		{"event_handler_calls_total", "counter", "Handler calls per event, retries included.",
+			func(s shared_event.EventStats) string { return fmt.Sprint(s.Dispatched) }},
+		{"event_handler_failures_total", "counter", "Handler calls that returned an error, panicked or timed out.",
+			func(s shared_event.EventStats) string { return fmt.Sprint(s.Failed) }},
+		{"event_handler_latency_seconds_total", "counter", "Time spent in handler calls.",
+			func(s shared_event.EventStats) string { return fmt.Sprint(s.TotalLatency.Seconds()) }},
+		{"event_handler_latency_seconds_max", "gauge", "Slowest handler call since startup.",
+			func(s shared_event.EventStats) string { return fmt.Sprint(s.MaxLatency.Seconds()) }},
+	}
+	for _, metric := range series {
+		fmt.Fprintf(&out, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for _, name := range names {
+			fmt.Fprintf(&out, "%s{event=%q} %s\n", metric.name, name, metric.value(snapshot[name]))
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(out.String()))
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+func TestEventMetricsHandler(t *testing.T) {
+	recorder := shared_event.NewMetricsRecorder()
+	recorder.Dispatched("user.created")
+	recorder.Dispatched("user.created")
+	recorder.Failed("user.created")
+	recorder.Observe("user.created", 250*time.Millisecond)
+	recorder.Observe("user.created", 500*time.Millisecond)
+	recorder.Dispatched("auth.login_succeeded")
+
+	rec := httptest.NewRecorder()
+	NewEventMetricsHandler(recorder).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	body := rec.Body.String()
+	assert.Contains(t, body, "# TYPE event_handler_calls_total counter\n"+
+		"event_handler_calls_total{event=\"auth.login_succeeded\"} 1\n"+
+		"event_handler_calls_total{event=\"user.created\"} 2\n")
+	assert.Contains(t, body, "event_handler_failures_total{event=\"user.created\"} 1\n")
+	assert.Contains(t, body, "event_handler_latency_seconds_total{event=\"user.created\"} 0.75\n")
+	assert.Contains(t, body, "event_handler_latency_seconds_max{event=\"user.created\"} 0.5\n")
+}
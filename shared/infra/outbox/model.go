@@ -0,0 +1,74 @@
+package outbox
+
+import (
+	"time"
+
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+)
+
+type OutboxMessage struct {
+	ID            string `gorm:"primaryKey;type:uuid"`
+	EventName     string
+	Payload       string `gorm:"type:jsonb"`
+	Status        string `gorm:"index"`
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	OccurredOn    time.Time
+	CreatedAt     time.Time
+	PublishedAt   *time.Time
+	// LockedBy and LockedUntil record which FetchDue call holds the message
+	// and until when.
+	LockedBy    string
+	LockedUntil *time.Time
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
+
+type ProcessedEvent struct {
+	Consumer    string `gorm:"primaryKey"`
+	EventID     string `gorm:"primaryKey;type:uuid"`
+	ProcessedAt time.Time
+}
+
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
+
+func FromMessage(m *shared_event.OutboxMessage) *OutboxMessage {
+	if m == nil {
+		return nil
+	}
+	return &OutboxMessage{
+		ID:            m.ID,
+		EventName:     m.EventName,
+		Payload:       string(m.Payload),
+		Status:        string(m.Status),
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     m.LastError,
+		OccurredOn:    m.OccurredOn,
+		CreatedAt:     m.CreatedAt,
+		PublishedAt:   m.PublishedAt,
+	}
+}
+
+func ToMessage(m *OutboxMessage) *shared_event.OutboxMessage {
+	if m == nil {
+		return nil
+	}
+	return &shared_event.OutboxMessage{
+		ID:            m.ID,
+		EventName:     m.EventName,
+		Payload:       []byte(m.Payload),
+		Status:        shared_event.OutboxStatus(m.Status),
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     m.LastError,
+		OccurredOn:    m.OccurredOn,
+		CreatedAt:     m.CreatedAt,
+		PublishedAt:   m.PublishedAt,
+	}
+}
@@ -0,0 +1,158 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultLease is how long a relay owns the messages it fetched before
+// another relay may take them over.
+const DefaultLease = 5 * time.Minute
+
+// Append stores events with tx, so they are committed or rolled back together
+// with the aggregate that raised them.
+func Append(tx *gorm.DB, events []shared_event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	models := make([]*OutboxMessage, 0, len(events))
+	for _, e := range events {
+		message, err := shared_event.NewOutboxMessage(e, now)
+		if err != nil {
+			return err
+		}
+		models = append(models, FromMessage(message))
+	}
+	return tx.Create(&models).Error
+}
+
+// OutboxGormStore lets several relays share one outbox table. FetchDue
+// leases the messages it returns to the caller, and a message is only
+// offered again once it is marked or its lease runs out, for instance
+// because the relay holding it crashed.
+type OutboxGormStore struct {
+	DB    *gorm.DB
+	lease time.Duration
+}
+
+type OutboxOption func(*OutboxGormStore)
+
+// WithLease sets how long fetched messages stay claimed. It should comfortably
+// exceed the time a relay needs to deliver one batch.
+func WithLease(lease time.Duration) OutboxOption {
+	return func(s *OutboxGormStore) {
+		if lease > 0 {
+			s.lease = lease
+		}
+	}
+}
+
+func NewOutboxGormStore(db *gorm.DB, opts ...OutboxOption) *OutboxGormStore {
+	store := &OutboxGormStore{DB: db, lease: DefaultLease}
+	for _, opt := range opts {
+		opt(store)
+	}
+	return store
+}
+
+var _ shared_event.OutboxStore = &OutboxGormStore{}
+
+// FetchDue claims the due messages by stamping them with a fresh claim id.
+// The stamp only applies to rows whose lease is free at that moment, so when
+// two relays pick the same candidates the one that commits second updates
+// nothing and gets nothing back.
+func (s *OutboxGormStore) FetchDue(now time.Time, limit int) ([]*shared_event.OutboxMessage, error) {
+	claim := uuid.New().String()
+	var models []OutboxMessage
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		if err := tx.Model(&OutboxMessage{}).
+			Where("status = ? AND next_attempt_at <= ?", shared_event.OutboxPending, now).
+			Where("(locked_until IS NULL OR locked_until <= ?)", now).
+			Order("created_at").
+			Limit(limit).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&OutboxMessage{}).
+			Where("id IN ? AND status = ?", ids, shared_event.OutboxPending).
+			Where("(locked_until IS NULL OR locked_until <= ?)", now).
+			Updates(map[string]interface{}{"locked_by": claim, "locked_until": now.Add(s.lease)}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("locked_by = ?", claim).Order("created_at").Find(&models).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*shared_event.OutboxMessage, 0, len(models))
+	for i := range models {
+		messages = append(messages, ToMessage(&models[i]))
+	}
+	return messages, nil
+}
+
+func (s *OutboxGormStore) MarkPublished(id string, at time.Time) error {
+	return s.DB.Model(&OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"status": shared_event.OutboxPublished, "published_at": at, "locked_by": "", "locked_until": nil}).Error
+}
+
+func (s *OutboxGormStore) MarkFailed(id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return s.DB.Model(&OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"attempts": attempts, "next_attempt_at": nextAttemptAt, "last_error": lastError, "locked_by": "", "locked_until": nil}).Error
+}
+
+func (s *OutboxGormStore) MarkDead(id string, attempts int, lastError string) error {
+	return s.DB.Model(&OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"status": shared_event.OutboxDead, "attempts": attempts, "last_error": lastError, "locked_by": "", "locked_until": nil}).Error
+}
+
+type ProcessedGormStore struct {
+	DB *gorm.DB
+}
+
+func NewProcessedGormStore(db *gorm.DB) *ProcessedGormStore {
+	return &ProcessedGormStore{DB: db}
+}
+
+var _ shared_event.ProcessedStore = &ProcessedGormStore{}
+
+// MarkProcessed inserts the record and runs handle in one transaction. While
+// it is open a second delivery of the same event blocks on the primary key,
+// and then finds the record if handle succeeded or inserts it itself if the
+// first attempt rolled back.
+func (s *ProcessedGormStore) MarkProcessed(consumer, eventID string, at time.Time, handle func() error) (bool, error) {
+	recorded := false
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&ProcessedEvent{Consumer: consumer, EventID: eventID, ProcessedAt: at})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		recorded = true
+		return handle()
+	})
+	if err != nil {
+		return false, err
+	}
+	return recorded, nil
+}
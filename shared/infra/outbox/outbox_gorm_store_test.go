@@ -0,0 +1,167 @@
+package outbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	shared_event "github.com/williamkoller/system-education/shared/domain/event"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type storedEvent struct {
+	shared_event.Identity
+	At time.Time
+}
+
+func (e *storedEvent) EventName() string     { return "test.stored" }
+func (e *storedEvent) OccurredOn() time.Time { return e.At }
+
+type OutboxGormStoreSuite struct {
+	suite.Suite
+	db        *gorm.DB
+	store     *OutboxGormStore
+	processed *ProcessedGormStore
+}
+
+func (s *OutboxGormStoreSuite) SetupTest() {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	s.Require().NoError(err)
+	s.Require().NoError(db.AutoMigrate(&OutboxMessage{}, &ProcessedEvent{}))
+
+	s.db = db
+	s.store = NewOutboxGormStore(db)
+	s.processed = NewProcessedGormStore(db)
+}
+
+func (s *OutboxGormStoreSuite) appendEvent() *storedEvent {
+	event := &storedEvent{At: time.Now()}
+	s.Require().NoError(Append(s.db, []shared_event.Event{event}))
+	return event
+}
+
+func (s *OutboxGormStoreSuite) TestAppendAndFetchDue() {
+	event := s.appendEvent()
+	s.NotEmpty(event.EventID())
+
+	due, err := s.store.FetchDue(time.Now().Add(time.Second), 10)
+	s.NoError(err)
+	s.Require().Len(due, 1)
+	s.Equal(event.EventID(), due[0].ID)
+	s.Equal("test.stored", due[0].EventName)
+	s.Equal(shared_event.OutboxPending, due[0].Status)
+	s.Contains(string(due[0].Payload), event.EventID())
+}
+
+func (s *OutboxGormStoreSuite) TestAppendNothing() {
+	s.NoError(Append(s.db, nil))
+
+	var count int64
+	s.db.Model(&OutboxMessage{}).Count(&count)
+	s.Zero(count)
+}
+
+func (s *OutboxGormStoreSuite) TestMarkFailedDelaysMessage() {
+	event := s.appendEvent()
+	retryAt := time.Now().Add(time.Minute)
+
+	s.NoError(s.store.MarkFailed(event.EventID(), 1, retryAt, "boom"))
+
+	due, err := s.store.FetchDue(time.Now().Add(time.Second), 10)
+	s.NoError(err)
+	s.Empty(due)
+
+	due, err = s.store.FetchDue(retryAt, 10)
+	s.NoError(err)
+	s.Require().Len(due, 1)
+	s.Equal(1, due[0].Attempts)
+	s.Equal("boom", due[0].LastError)
+}
+
+func (s *OutboxGormStoreSuite) TestPublishedAndDeadAreNotFetched() {
+	published := s.appendEvent()
+	dead := s.appendEvent()
+
+	s.NoError(s.store.MarkPublished(published.EventID(), time.Now()))
+	s.NoError(s.store.MarkDead(dead.EventID(), 10, "gave up"))
+
+	due, err := s.store.FetchDue(time.Now().Add(time.Second), 10)
+	s.NoError(err)
+	s.Empty(due)
+
+	var model OutboxMessage
+	s.Require().NoError(s.db.First(&model, "id = ?", dead.EventID()).Error)
+	s.Equal(string(shared_event.OutboxDead), model.Status)
+	s.Equal(10, model.Attempts)
+}
+
+func (s *OutboxGormStoreSuite) TestFetchDueClaimsMessages() {
+	event := s.appendEvent()
+	now := time.Now().Add(time.Second)
+
+	due, err := s.store.FetchDue(now, 10)
+	s.NoError(err)
+	s.Require().Len(due, 1)
+
+	due, err = s.store.FetchDue(now, 10)
+	s.NoError(err)
+	s.Empty(due, "a claimed message must not be handed to a second relay")
+
+	due, err = s.store.FetchDue(now.Add(DefaultLease), 10)
+	s.NoError(err)
+	s.Require().Len(due, 1, "an expired claim is taken over")
+	s.Equal(event.EventID(), due[0].ID)
+}
+
+func (s *OutboxGormStoreSuite) TestMarkFailedReleasesClaim() {
+	event := s.appendEvent()
+	store := NewOutboxGormStore(s.db, WithLease(time.Hour))
+	now := time.Now().Add(time.Second)
+
+	due, err := store.FetchDue(now, 10)
+	s.NoError(err)
+	s.Require().Len(due, 1)
+
+	s.NoError(store.MarkFailed(event.EventID(), 1, now, "boom"))
+
+	due, err = store.FetchDue(now, 10)
+	s.NoError(err)
+	s.Len(due, 1)
+}
+
+func (s *OutboxGormStoreSuite) TestProcessedEvents() {
+	const id = "9b2f0c1e-0000-4000-8000-000000000001"
+	calls := 0
+	handle := func() error { calls++; return nil }
+
+	recorded, err := s.processed.MarkProcessed("welcome_email", id, time.Now(), handle)
+	s.NoError(err)
+	s.True(recorded)
+
+	recorded, err = s.processed.MarkProcessed("welcome_email", id, time.Now(), handle)
+	s.NoError(err)
+	s.False(recorded)
+
+	recorded, err = s.processed.MarkProcessed("email_verification", id, time.Now(), handle)
+	s.NoError(err)
+	s.True(recorded)
+	s.Equal(2, calls)
+}
+
+func (s *OutboxGormStoreSuite) TestProcessedEventsRollBackFailedHandlers() {
+	const id = "9b2f0c1e-0000-4000-8000-000000000002"
+
+	recorded, err := s.processed.MarkProcessed("welcome_email", id, time.Now(), func() error { return errors.New("boom") })
+	s.EqualError(err, "boom")
+	s.False(recorded)
+
+	recorded, err = s.processed.MarkProcessed("welcome_email", id, time.Now(), func() error { return nil })
+	s.NoError(err)
+	s.True(recorded)
+}
+
+func TestOutboxGormStoreSuite(t *testing.T) {
+	suite.Run(t, new(OutboxGormStoreSuite))
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// NewEngine returns the Gin engine the API is served from. Only requests
+// arriving from trustedProxies may name the client address through
+// X-Forwarded-For or X-Real-IP; with none, every request is attributed to
+// its TCP peer, so a client cannot choose the address it is throttled and
+// recorded under.
+func NewEngine(trustedProxies []string) (*gin.Engine, error) {
+	g := gin.Default()
+	if err := g.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	g.Use(gin.Recovery())
+	g.Use(GlobalErrorHandler())
+	return g, nil
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"log"
 	"net/http"
 
@@ -10,6 +11,13 @@ import (
 type ErrorResponse struct {
 	HTTPCode int    `json:"code"`
 	Error    string `json:"error,omitempty"`
+	Details  any    `json:"details,omitempty"`
+}
+
+// detailedError is implemented by errors that carry structured data for the
+// client, such as per-field validation failures.
+type detailedError interface {
+	Details() any
 }
 
 func GlobalErrorHandler() gin.HandlerFunc {
@@ -39,10 +47,15 @@ func GlobalErrorHandler() gin.HandlerFunc {
 				statusCode, lastErr.Error(), c.Writer.Written())
 
 			if !c.Writer.Written() {
-				c.JSON(statusCode, ErrorResponse{
+				response := ErrorResponse{
 					HTTPCode: statusCode,
 					Error:    lastErr.Error(),
-				})
+				}
+				var detailed detailedError
+				if errors.As(lastErr.Err, &detailed) {
+					response.Details = detailed.Details()
+				}
+				c.JSON(statusCode, response)
 			}
 			c.Abort()
 		}